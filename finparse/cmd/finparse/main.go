@@ -0,0 +1,1243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse"
+	"github.com/rumor-ml/commons.systems/finparse/internal/dedup"
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+	"github.com/rumor-ml/commons.systems/finparse/internal/output"
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/registry"
+	"github.com/rumor-ml/commons.systems/finparse/internal/scanner"
+	"github.com/rumor-ml/commons.systems/finparse/internal/transform"
+	"github.com/rumor-ml/commons.systems/finparse/internal/ui"
+	"github.com/rumor-ml/commons.systems/finparse/internal/validate"
+)
+
+const (
+	version = "0.1.0"
+)
+
+// Exit codes, so CI can tell failure kinds apart (listed in -help)
+const (
+	exitSuccess    = 0
+	exitError      = 1 // Any other failure, including invalid flags
+	exitValidation = 2 // Budget validation failed (or -strict-period found statements without a period)
+	exitNoFiles    = 3 // No statement files found, or none left after -institution/-format
+	exitCoverage   = 4 // Rule coverage below -fail-under
+)
+
+var (
+	errValidation = finparse.ErrValidation
+	errNoFiles    = finparse.ErrNoFiles
+	errCoverage   = errors.New("rule coverage below threshold")
+)
+
+// exitCode maps an error returned by run to the process exit code
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitSuccess
+	case errors.Is(err, errValidation):
+		return exitValidation
+	case errors.Is(err, errNoFiles):
+		return exitNoFiles
+	case errors.Is(err, errCoverage):
+		return exitCoverage
+	default:
+		return exitError
+	}
+}
+
+var (
+	// Global flags
+	versionFlag = flag.Bool("version", false, "Show version")
+
+	// Core CLI flags
+	inputDir    = flag.String("input", "", "Input directory containing statements, a single statement file, or - for stdin (required)")
+	stdinFormat = flag.String("stdin-format", "", "Format of the statement read with -input -: a parser name or format (ofx, csv)")
+	dryRun      = flag.Bool("dry-run", false, "Show what would be parsed without writing")
+	verbose     = flag.Bool("verbose", false, "Show detailed parsing logs")
+	logFormat   = flag.String("log-format", ui.FormatText, "Progress and diagnostics format: text or json (one JSON object per event on stderr)")
+
+	// Output and merge flags (Phase 4)
+	outputFile = flag.String("output", "", "Output JSON file (default: stdout)")
+	mergeMode  = flag.Bool("merge", false, "Merge with existing output file")
+	stream     = flag.Bool("stream", false, "Write JSON Lines output incrementally while parsing instead of building the whole budget in memory")
+	chunkSize  = flag.Int("chunk-size", 1000, "Transactions buffered before each flush in -stream mode")
+	normalize  = flag.Bool("normalize-output", false, "Write canonical JSON (sorted keys and entities, plain decimal numbers, UTC timestamps) so identical inputs give byte-identical files")
+	anonymize  = flag.Bool("anonymize", false, "Replace account numbers, IDs, descriptions and source files with hashed tokens for sharing sample output (lossy: the salt is random per run and discarded)")
+
+	// Phase 5 flags (deduplication and rules)
+	stateFile         = flag.String("state", "", "Deduplication state file")
+	checkpointEvery   = flag.Int("checkpoint-every", 0, "Save deduplication state every N files during parsing (0 = only at end; requires -state)")
+	rulesFile         = flag.String("rules", "", "Category rules file, or comma-separated files and directories of *.yaml files to merge")
+	formatFilter      = flag.String("format", finparse.FormatAll, "Only parse these formats, comma-separated: ofx, csv or all")
+	institutionFilter = flag.String("institution", "", "Only parse files from this institution (case-insensitive, aliases allowed; default: all). With a single -input file or stdin, names the statement's institution")
+
+	// Input guards
+	failOnUnknownInstitution = flag.Bool("fail-on-unknown-institution", false, "Abort if any file's institution cannot be determined from its path")
+	strictPeriod             = flag.Bool("strict-period", false, "Fail if any statement has no period (default: warn, since continuity and overlap validation are degraded)")
+
+	// Analysis flags
+	detectRecurring = flag.Bool("detect-recurring", false, "Detect likely recurring charges and add a recurring section to the output")
+	monthlySummary  = flag.Bool("monthly-summary", false, "Add a summary section with each category's net total per month (negative = spending, positive = income; transfers excluded)")
+
+	// Diagnostic flags
+	dedupReportCollisions = flag.Bool("dedup-report-collisions", false, "Report fingerprints shared by distinct transactions (potential dedup collisions)")
+	unmatchedReport       = flag.String("unmatched-report", "", "Write every transaction no category rule matched to this JSON file")
+	dumpAST               = flag.String("dump-ast", "", "Parse a single statement file and print the raw parser output as JSON (skips transform, dedup and validation)")
+	fileReport            = flag.String("transactions-per-file-report", "", "Write each input file's transaction counts (parsed, new, duplicate) and period to this file (.csv for CSV, otherwise JSON)")
+	coverageByInstitution = flag.Bool("coverage-by-institution", false, "Print rule-match coverage for each institution, worst first")
+	failUnder             = flag.Float64("fail-under", 0, "Fail with exit code 4 if rule coverage is below this percentage (0 = only warn below 80%)")
+	coverageDropWarn      = flag.Float64("coverage-drop-warn", 5, "Warn if rule coverage dropped by more than this many percentage points since the last run saved in -state")
+	statsJSON             = flag.String("stats-json", "", "Write the end-of-run summary counters (totals, duplicates, rule coverage, validation) to this JSON file after a successful run")
+	traceSource           = flag.Bool("trace-source", false, "Record each transaction's source file (and CSV line) under \"source\" in the output")
+)
+
+func main() {
+	// Custom usage message
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `finparse - Financial statement parser for budget prototype
+
+Usage:
+  finparse [flags]
+
+Flags:
+`)
+		flag.PrintDefaults()
+		fmt.Fprint(os.Stderr, `
+Examples:
+  # Parse all statements to stdout
+  finparse -input ~/statements
+
+  # Parse to file with state tracking
+  finparse -input ~/statements -output budget.json -state state.json
+
+  # Dry run with verbose output
+  finparse -input ~/statements -dry-run -verbose
+
+  # Try rules against one statement, from a file or stdin
+  finparse -input ~/statements/amex/2011/statement.qfx -dry-run
+  cat statement.csv | finparse -input - -stdin-format csv -rules rules.yaml
+
+  # Show what the parser extracted from one file
+  finparse -dump-ast ~/statements/amex/2011/statement.qfx
+
+  # Fail CI when fewer than 90% of transactions match a category rule
+  finparse -input ~/statements -output budget.json -fail-under 90
+
+Exit codes:
+  0  Success
+  1  Error (invalid flags, unreadable files, parse failures, ...)
+  2  Validation failed (including -strict-period)
+  3  No statement files found, or none matched -institution/-format
+  4  Rule coverage below -fail-under
+
+`)
+	}
+
+	// Parse errors exit with exitError rather than the flag package's 2, which is
+	// the validation failure code
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(exitSuccess)
+		}
+		os.Exit(exitError)
+	}
+
+	// Handle version flag
+	if *versionFlag {
+		fmt.Printf("finparse version %s\n", version)
+		os.Exit(exitSuccess)
+	}
+
+	// Debug mode: dump one file's raw parser output, bypassing the pipeline
+	if *dumpAST != "" {
+		if err := dumpRawStatement(*dumpAST, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		os.Exit(exitSuccess)
+	}
+
+	// Validate required flags
+	if *inputDir == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input flag is required\n\n")
+		flag.Usage()
+		os.Exit(exitError)
+	}
+
+	// Run parser
+	if err := run(); err != nil {
+		// In JSON mode run has already emitted the error as a run_failed event
+		if *logFormat != ui.FormatJSON {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(exitCode(err))
+	}
+}
+
+func run() (err error) {
+	// TODO(#1350): Add context cancellation support for graceful Ctrl+C handling.
+	// Currently uses Background() which ignores cancellation signals. Should use
+	// signal.NotifyContext to detect Ctrl+C. Graceful shutdown options:
+	//   1. Stop accepting new files, wait for current parser to complete (if parser supports context)
+	//   2. Save state with already-processed transactions before exiting
+	// Note: State is saved after all parsing completes but before output writing, and also
+	// every N files when -checkpoint-every is set. Graceful shutdown could reuse those checkpoints.
+	ctx := context.Background()
+
+	logger, err := ui.NewLogger(*logFormat, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("invalid -log-format: %w", err)
+	}
+	if *logFormat == ui.FormatJSON && *verbose {
+		return fmt.Errorf("-verbose cannot be combined with -log-format json (JSON events already include per-file detail)")
+	}
+	defer ui.SetLogger(ui.SetLogger(logger))
+
+	// Free-form diagnostics go to logOut; in JSON mode they are discarded so stderr
+	// carries only JSON events (structured equivalents are emitted via ui.Event)
+	var logOut io.Writer = os.Stderr
+	if ui.IsJSON() {
+		logOut = io.Discard
+		defer func() {
+			if err != nil {
+				ui.Event("run_failed", map[string]any{"error": err.Error(), "exit_code": exitCode(err)})
+			}
+		}()
+	}
+
+	if *stream {
+		if *chunkSize <= 0 {
+			return fmt.Errorf("-chunk-size must be > 0, got %d", *chunkSize)
+		}
+		if *mergeMode {
+			return fmt.Errorf("-stream cannot be combined with -merge (merging needs the whole existing budget in memory)")
+		}
+		if *detectRecurring {
+			return fmt.Errorf("-stream cannot be combined with -detect-recurring (detection needs every transaction in memory)")
+		}
+		if *monthlySummary {
+			return fmt.Errorf("-stream cannot be combined with -monthly-summary (totals need every transaction in memory)")
+		}
+		if *normalize {
+			return fmt.Errorf("-stream cannot be combined with -normalize-output (sorting needs every transaction in memory)")
+		}
+		if *anonymize {
+			return fmt.Errorf("-stream cannot be combined with -anonymize (anonymization rewrites the finished budget)")
+		}
+	}
+	if *anonymize && *mergeMode {
+		return fmt.Errorf("-anonymize cannot be combined with -merge (the existing file's tokens come from another run's salt)")
+	}
+
+	if *failUnder < 0 || *failUnder > 100 {
+		return fmt.Errorf("-fail-under must be between 0 and 100, got %g", *failUnder)
+	}
+	if *coverageDropWarn < 0 || *coverageDropWarn > 100 {
+		return fmt.Errorf("-coverage-drop-warn must be between 0 and 100, got %g", *coverageDropWarn)
+	}
+
+	if *checkpointEvery < 0 {
+		return fmt.Errorf("-checkpoint-every must be >= 0, got %d", *checkpointEvery)
+	}
+	if *checkpointEvery > 0 && *stateFile == "" {
+		return fmt.Errorf("-checkpoint-every requires -state (there is no state to checkpoint)")
+	}
+
+	// Scan for files
+	if !*verbose {
+		ui.Header("Parsing Financial Statements")
+		ui.Step(1, 4, "Scanning directory")
+	} else {
+		fmt.Fprintf(logOut, "Scanning directory: %s\n", *inputDir)
+	}
+
+	// Scanning, rules and the -institution/-format filters run in finparse.Prepare;
+	// the dry run stops before anything is parsed or state is touched
+	pipeline, err := finparse.Prepare(finparse.Options{
+		InputDir:                 *inputDir,
+		StdinFormat:              *stdinFormat,
+		StateFile:                *stateFile,
+		RulesFile:                *rulesFile,
+		Institution:              *institutionFilter,
+		Formats:                  *formatFilter,
+		FailOnUnknownInstitution: *failOnUnknownInstitution,
+		StrictPeriod:             *strictPeriod,
+		CheckpointEvery:          *checkpointEvery,
+		TraceSource:              *traceSource,
+	})
+	if err != nil {
+		return err
+	}
+	reg, engine, files := pipeline.Registry, pipeline.Engine, pipeline.Files
+
+	if *verbose {
+		fmt.Fprintf(logOut, "Registered parsers: %v\n", reg.ListParsers())
+		fmt.Fprintf(logOut, "Found %d statement files\n", len(pipeline.Scanned))
+		for _, f := range pipeline.Scanned {
+			fmt.Fprintf(logOut, "  - %s (institution: %s, account: %s)\n",
+				f.Path, f.Metadata.Institution(), f.Metadata.AccountNumber())
+		}
+	} else {
+		ui.Success(fmt.Sprintf("Found %d statement files", len(pipeline.Scanned)))
+	}
+	for _, f := range pipeline.Scanned {
+		ui.Event("file_scanned", map[string]any{
+			"path":        f.Path,
+			"institution": f.Metadata.Institution(),
+			"account":     f.Metadata.AccountNumber(),
+		})
+	}
+
+	if !*verbose {
+		ui.Step(2, 4, "Loading category rules")
+	} else {
+		if *rulesFile != "" {
+			fmt.Fprintf(logOut, "Loaded %d custom rules from %s\n", len(engine.GetRules()), *rulesFile)
+		} else {
+			fmt.Fprintf(logOut, "Loaded %d embedded rules\n", len(engine.GetRules()))
+		}
+		if len(engine.InstitutionAliases()) > 0 {
+			fmt.Fprintf(logOut, "Loaded %d institution aliases\n", len(engine.InstitutionAliases()))
+		}
+	}
+
+	reportFilters(pipeline)
+
+	// Dry run mode: report parser selection, don't parse
+	if *dryRun {
+		if err := writeDryRunReport(os.Stdout, pipeline.FindParser, files); err != nil {
+			return err
+		}
+		fmt.Printf("Dry run complete. Would process %d files.\n", len(files))
+		return nil
+	}
+
+	// Return error if no files found (non-dry-run) - prevents silent failures in scripts/CI
+	if len(files) == 0 {
+		return fmt.Errorf("%w found in %s\n\nPlease check:\n  - Directory path is correct\n  - Files have supported extensions (.qfx, .ofx, .csv) or OFX/CSV content\n  - You have read permissions on the directory and files\n\nRun with -verbose to see file discovery details", errNoFiles, *inputDir)
+	}
+
+	// Phase 5: Load dedup state if provided
+	if !*verbose && *stateFile != "" {
+		ui.Step(3, 4, "Loading deduplication state")
+	}
+	var state *dedup.State
+	if *stateFile != "" {
+		loadedState, existed, err := finparse.LoadState(*stateFile)
+		if err != nil {
+			return err
+		}
+		state = loadedState
+
+		if !existed {
+			if *verbose {
+				fmt.Fprintf(logOut, "State file not found, creating new state\n")
+			}
+		} else {
+			if state.TotalFingerprints() == 0 {
+				// Truly new state file with no history - OK for first run
+				fmt.Fprintf(logOut, "Creating new state file (first run) - all transactions will be processed as new\n")
+			}
+
+			if *verbose {
+				fmt.Fprintf(logOut, "Loaded state with %d fingerprints\n",
+					state.TotalFingerprints())
+				if !state.Metadata.LastUpdated.IsZero() {
+					fmt.Fprintf(logOut, "  Last updated: %s\n",
+						state.Metadata.LastUpdated.Format(time.RFC3339))
+				}
+			}
+		}
+	}
+
+	// Show deduplication status when enabled (regardless of verbose flag)
+	if state != nil && *stateFile != "" {
+		fmt.Fprintf(logOut, "Deduplication enabled with state file: %s (%d existing fingerprints)\n",
+			*stateFile, state.TotalFingerprints())
+	}
+
+	// Show summary of scan results with per-institution breakdown, using canonical
+	// institution names so aliases are counted together.
+	fmt.Printf("Scan complete: found %d statement files", len(files))
+	// Build institution breakdown for summary (shows file count per institution)
+	institutions := make(map[string]int)
+	for _, f := range files {
+		inst := f.Metadata.Institution()
+		if inst == "" {
+			inst = finparse.UnknownInstitution
+		} else {
+			inst = engine.CanonicalInstitution(inst)
+		}
+		institutions[inst]++
+	}
+	fmt.Printf(" across %d institutions\n", len(institutions))
+	for inst, count := range institutions {
+		fmt.Printf("  - %s: %d files\n", inst, count)
+	}
+
+	// Phase 4: Transform and output
+	budget := domain.NewBudget()
+
+	// Totals come from the pipeline's Stats; these collect the per-statement details
+	// the reports below need
+	unmatchedExamplesMap := make(map[string]bool) // Track unique unmatched descriptions
+	duplicateExamplesMap := make(map[string]bool) // Track unique duplicate examples
+	var overlapExamples []string                  // Merged overlapping statements, in file order
+	var unmatchedEntries []unmatchedReportEntry
+	var statementStats []*transform.TransformStats // Per-statement stats for -coverage-by-institution
+	var fileReportEntries []fileReportEntry
+
+	var collisionTracker *dedup.CollisionTracker
+	if *dedupReportCollisions {
+		collisionTracker = dedup.NewCollisionTracker()
+	}
+
+	// Streaming mode: transactions are flushed to the output in chunks and validated
+	// as they go; only the validator's lightweight index stays in memory
+	var (
+		streamOut        *output.StreamFile
+		streamValidator  *validate.StreamValidator
+		streamedTxnCount int
+	)
+	if *stream {
+		streamOut, err = output.CreateStreamFile(*outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to open streaming output: %w", err)
+		}
+		// Discard partial output unless the run completes (Abort is a no-op after Commit)
+		defer streamOut.Abort()
+		streamValidator = validate.NewStreamValidator()
+	}
+	flushChunk := func() error {
+		txns := budget.DrainTransactions()
+		if len(txns) == 0 {
+			return nil
+		}
+		streamValidator.AddTransactions(txns)
+		if err := streamOut.WriteTransactions(txns); err != nil {
+			return fmt.Errorf("failed to stream transactions: %w", err)
+		}
+		streamedTxnCount += len(txns)
+		ui.Event("chunk_flushed", map[string]any{"transactions": len(txns), "total": streamedTxnCount})
+		return nil
+	}
+
+	if *verbose {
+		fmt.Fprintln(logOut, "\nParsing and transforming statements...")
+	} else {
+		ui.Step(4, 4, "Parsing and transforming statements")
+	}
+
+	pipeline.Progress = func(fp finparse.FileProgress) error {
+		if *verbose {
+			fmt.Fprintf(logOut, "  Parsed %s with %s parser\n", fp.Path, fp.Parser)
+		} else {
+			// Show simple progress indicator for non-verbose mode
+			percentage := float64(fp.Index) / float64(fp.Total) * 100
+			fmt.Fprintf(logOut, "\r  Progress: %d/%d files (%.0f%%)...", fp.Index, fp.Total, percentage)
+		}
+
+		ui.Event("parser_selected", map[string]any{"path": fp.Path, "parser": fp.Parser})
+
+		if fp.CloseErr != nil {
+			fmt.Fprintf(logOut, "\nWARNING: Failed to close %s: %v\n", fp.Path, fp.CloseErr)
+		}
+
+		for _, stmt := range fp.Statements {
+			if stmt.Stats == nil {
+				continue // No period under -strict-period; the run fails below
+			}
+			stats := stmt.Stats
+
+			// Tracked from the raw statement so transactions skipped by dedup are still observed
+			if collisionTracker != nil {
+				if err := transform.TrackFingerprintCollisions(stmt.Raw, collisionTracker); err != nil {
+					return fmt.Errorf("collision tracking failed for %s: %w", fp.Path, err)
+				}
+			}
+
+			ui.Event("statement_transformed", map[string]any{
+				"path":               fp.Path,
+				"transactions":       len(stmt.Raw.Transactions),
+				"duplicates_skipped": stats.DuplicatesSkipped,
+				"rules_matched":      stats.RulesMatched,
+				"rules_unmatched":    stats.RulesUnmatched,
+				"transactions_split": stats.TransactionsSplit,
+			})
+
+			if *coverageByInstitution {
+				statementStats = append(statementStats, stats)
+			}
+			if *fileReport != "" {
+				fileReportEntries = append(fileReportEntries, fileReportEntry{
+					File:         fp.Path,
+					Parser:       fp.Parser,
+					Transactions: len(stmt.Raw.Transactions),
+					New:          len(stmt.Raw.Transactions) - stats.DuplicatesSkipped,
+					Duplicates:   stats.DuplicatesSkipped,
+					PeriodStart:  stmt.Raw.Period.Start().Format("2006-01-02"),
+					PeriodEnd:    stmt.Raw.Period.End().Format("2006-01-02"),
+				})
+			}
+			for _, desc := range stats.UnmatchedExamples() {
+				unmatchedExamplesMap[desc] = true
+			}
+			if *unmatchedReport != "" {
+				for _, u := range stats.Unmatched() {
+					unmatchedEntries = append(unmatchedEntries, unmatchedReportEntry{UnmatchedTransaction: u, SourceFile: fp.Path})
+				}
+			}
+			for _, example := range stats.DuplicateExamples() {
+				duplicateExamplesMap[example] = true
+			}
+			overlapExamples = append(overlapExamples, stats.OverlapExamples()...)
+		}
+
+		if streamOut != nil && budget.TransactionCount() >= *chunkSize {
+			if err := flushChunk(); err != nil {
+				return err
+			}
+		}
+
+		if fp.Checkpointed {
+			if *verbose {
+				fmt.Fprintf(logOut, "  Checkpointed state with %d fingerprints after %d/%d files\n",
+					state.TotalFingerprints(), fp.Index, fp.Total)
+			}
+			ui.Event("state_checkpoint", map[string]any{"files_done": fp.Index, "fingerprints": state.TotalFingerprints()})
+		}
+		return nil
+	}
+
+	runStats, err := pipeline.Run(ctx, budget, state)
+	if err != nil {
+		return err
+	}
+
+	if streamOut != nil {
+		if err := flushChunk(); err != nil {
+			return err
+		}
+	}
+
+	// Clear progress indicator in non-verbose mode
+	if !*verbose && len(files) > 0 {
+		fmt.Fprintf(logOut, "\r  Progress: %d/%d files (100%%) - Complete!\n", len(files), len(files))
+	}
+
+	if err := checkStatementPeriods(runStats.MissingPeriod, *strictPeriod); err != nil {
+		return err
+	}
+
+	// Check for close failures and provide detailed diagnostics
+	if closeErrors := runStats.CloseErrors; len(closeErrors) > 0 {
+		fmt.Fprintf(logOut, "\nERROR: %d file(s) failed to close properly\n", len(closeErrors))
+		// Show first 3 examples
+		for i, detail := range closeErrors {
+			if i >= 3 {
+				fmt.Fprintf(logOut, "    ... and %d more\n", len(closeErrors)-3)
+				break
+			}
+			fmt.Fprintf(logOut, "    - %s\n", detail)
+		}
+
+		// Return error if ANY file failed to close - conservative approach to detect filesystem issues early
+		return fmt.Errorf("%d file(s) failed to close - check filesystem health", len(closeErrors))
+	}
+
+	if *verbose {
+		institutions := budget.GetInstitutions()
+		accounts := budget.GetAccounts()
+		statements := budget.GetStatements()
+
+		fmt.Fprintf(logOut, "\nTransformation complete:\n")
+		fmt.Fprintf(logOut, "  Institutions: %d\n", len(institutions))
+		fmt.Fprintf(logOut, "  Accounts: %d\n", len(accounts))
+		fmt.Fprintf(logOut, "  Statements: %d\n", len(statements))
+		fmt.Fprintf(logOut, "  Transactions: %d\n", budget.TransactionCount()+streamedTxnCount)
+		if runStats.TransactionsSplit > 0 {
+			fmt.Fprintf(logOut, "  Split by rules: %d\n", runStats.TransactionsSplit)
+		}
+
+	}
+
+	ui.Event("transform_complete", map[string]any{
+		"institutions":       len(budget.GetInstitutions()),
+		"accounts":           len(budget.GetAccounts()),
+		"statements":         len(budget.GetStatements()),
+		"transactions":       budget.TransactionCount() + streamedTxnCount,
+		"duplicates_skipped": runStats.DuplicatesSkipped,
+		"statements_merged":  runStats.DuplicateStatementsMerged,
+		"transactions_split": runStats.TransactionsSplit,
+	})
+
+	// Filled in as the summary below is reported; written to -stats-json once the run succeeds
+	summary := runSummary{
+		Files:                        runStats.Files,
+		Institutions:                 len(budget.GetInstitutions()),
+		Accounts:                     len(budget.GetAccounts()),
+		Statements:                   len(budget.GetStatements()),
+		Transactions:                 budget.TransactionCount() + streamedTxnCount,
+		TransactionsSplit:            runStats.TransactionsSplit,
+		DuplicatesSkipped:            runStats.DuplicatesSkipped,
+		DuplicateInstitutionsSkipped: runStats.DuplicateInstitutionsSkipped,
+		DuplicateAccountsSkipped:     runStats.DuplicateAccountsSkipped,
+		DuplicateStatementsMerged:    runStats.DuplicateStatementsMerged,
+		RulesMatched:                 runStats.RulesMatched,
+		RulesUnmatched:               runStats.RulesUnmatched,
+	}
+
+	// Show deduplication statistics (always, not just verbose)
+	if state != nil && runStats.DuplicatesSkipped > 0 {
+		fmt.Fprintf(logOut, "\nDeduplication:\n")
+		fmt.Fprintf(logOut, "  Skipped %d duplicate transactions\n", runStats.DuplicatesSkipped)
+	}
+
+	// Example duplicates only in verbose mode
+	if *verbose && state != nil && len(duplicateExamplesMap) > 0 {
+		fmt.Fprintf(logOut, "  Example duplicates:\n")
+		count := 0
+		for desc := range duplicateExamplesMap {
+			if count >= 5 {
+				break
+			}
+			fmt.Fprintf(logOut, "    - %s\n", desc)
+			count++
+		}
+
+		// Show duplicate institution/account statistics
+		if runStats.DuplicateInstitutionsSkipped > 0 {
+			fmt.Fprintf(logOut, "  Skipped %d duplicate institution(s)\n", runStats.DuplicateInstitutionsSkipped)
+		}
+		if runStats.DuplicateAccountsSkipped > 0 {
+			fmt.Fprintf(logOut, "  Skipped %d duplicate account(s)\n", runStats.DuplicateAccountsSkipped)
+		}
+
+	}
+
+	// Overlapping statements are merged with or without a state file
+	if runStats.DuplicateStatementsMerged > 0 {
+		fmt.Fprintf(logOut, "\nOverlapping statements:\n")
+		fmt.Fprintf(logOut, "  Merged %d statement(s) into an overlapping statement for the same account\n",
+			runStats.DuplicateStatementsMerged)
+		if *verbose {
+			fmt.Fprintf(logOut, "  Example overlaps:\n")
+			for i, example := range overlapExamples {
+				if i >= 5 {
+					break
+				}
+				fmt.Fprintf(logOut, "    - %s\n", example)
+			}
+		}
+	}
+
+	if collisionTracker != nil {
+		reportCollisions(logOut, collisionTracker)
+	}
+
+	// Recurring detection runs after all statements so cadence spans statement boundaries
+	if *detectRecurring {
+		recurring, err := transform.DetectRecurring(budget)
+		if err != nil {
+			return fmt.Errorf("recurring detection failed: %w", err)
+		}
+		ui.Event("recurring_detected", map[string]any{"count": len(recurring)})
+		recurringCount := len(recurring)
+		summary.RecurringDetected = &recurringCount
+		if *verbose {
+			fmt.Fprintf(logOut, "\nRecurring charges detected: %d\n", len(recurring))
+			for _, r := range recurring {
+				fmt.Fprintf(logOut, "  - %s (%.2f, %s, %d occurrences)\n", r.Merchant, r.Amount, r.Cadence, len(r.Dates))
+			}
+		} else {
+			fmt.Fprintf(logOut, "\n")
+			ui.Info(fmt.Sprintf("Detected %d recurring charges", len(recurring)))
+		}
+	}
+
+	// Summarized after all statements so each month includes every account's transactions
+	if *monthlySummary {
+		if err := budget.SummarizeByMonth(); err != nil {
+			return fmt.Errorf("monthly summary failed: %w", err)
+		}
+		months := len(budget.GetSummary().ByMonth)
+		ui.Event("monthly_summary", map[string]any{"months": months})
+		if *verbose {
+			fmt.Fprintf(logOut, "\nMonthly summary: %d months\n", months)
+		} else {
+			fmt.Fprintf(logOut, "\n")
+			ui.Info(fmt.Sprintf("Summarized category totals for %d months", months))
+		}
+	}
+
+	// Show rule matching statistics (always, not just verbose)
+	var coverageErr error
+	if engine != nil {
+		if coverage, ok := runStats.Coverage(); ok {
+			totalProcessed := runStats.RulesMatched + runStats.RulesUnmatched
+			summary.CoveragePercent = &coverage
+			ui.Event("rule_coverage", map[string]any{
+				"matched":          runStats.RulesMatched,
+				"unmatched":        runStats.RulesUnmatched,
+				"coverage_percent": coverage,
+			})
+			if *verbose {
+				fmt.Fprintf(logOut, "\nRule matching statistics:\n")
+				fmt.Fprintf(logOut, "  Matched: %d (%.1f%%)\n", runStats.RulesMatched, coverage)
+				fmt.Fprintf(logOut, "  Unmatched: %d\n", runStats.RulesUnmatched)
+			} else {
+				fmt.Fprintf(logOut, "\n")
+				ui.Info(fmt.Sprintf("Rule coverage: %.1f%% (%d/%d matched)", coverage, runStats.RulesMatched, totalProcessed))
+			}
+
+			// Warn if coverage is low
+			if coverage < 80.0 {
+				if *verbose {
+					fmt.Fprintf(logOut, "  WARNING: Rule coverage is %.1f%% (below 80%% target)\n", coverage)
+					fmt.Fprintf(logOut, "           %d transactions categorized as 'other' need rules\n", runStats.RulesUnmatched)
+				} else {
+					ui.Warning(fmt.Sprintf("Rule coverage %.1f%% below 80%% target (%d unmatched)", coverage, runStats.RulesUnmatched))
+				}
+				if !*verbose {
+					ui.Info("Run with -verbose to see example unmatched transactions")
+				}
+			}
+
+			// Compared with the last run saved in the state file, so new merchants without
+			// rules show up even while coverage is above the 80% target. The first run has
+			// no baseline.
+			if state != nil {
+				if previous, ok := state.Metadata.RecordCoverage(coverage); ok && previous-coverage > *coverageDropWarn {
+					ui.Event("coverage_regressed", map[string]any{
+						"previous_percent": previous,
+						"coverage_percent": coverage,
+					})
+					if *verbose {
+						fmt.Fprintf(logOut, "  WARNING: Rule coverage dropped from %.1f%% to %.1f%% since the last run\n", previous, coverage)
+					} else {
+						ui.Warning(fmt.Sprintf("Rule coverage dropped from %.1f%% to %.1f%% since the last run (new merchants may need rules)", previous, coverage))
+					}
+				}
+			}
+
+			// Reported after validation, whose failure takes precedence
+			if *failUnder > 0 && coverage < *failUnder {
+				coverageErr = fmt.Errorf("%w: %.1f%% is below -fail-under %g%% (%d unmatched)",
+					errCoverage, coverage, *failUnder, runStats.RulesUnmatched)
+			}
+		}
+
+		if *coverageByInstitution {
+			printCoverageByInstitution(logOut, transform.CoverageByInstitution(statementStats))
+		}
+	}
+
+	// Show example unmatched transactions only in verbose mode
+	if *verbose && len(unmatchedExamplesMap) > 0 {
+		fmt.Fprintf(logOut, "  Example unmatched transactions:\n")
+		count := 0
+		for desc := range unmatchedExamplesMap {
+			if count >= 5 {
+				break
+			}
+			fmt.Fprintf(logOut, "    - %s\n", desc)
+			count++
+		}
+	}
+
+	// Written before validation so the report is available to fix rules even when
+	// validation fails
+	if *unmatchedReport != "" {
+		if err := writeUnmatchedReport(*unmatchedReport, unmatchedEntries); err != nil {
+			return err
+		}
+		ui.Event("unmatched_report_written", map[string]any{"path": *unmatchedReport, "count": len(unmatchedEntries)})
+		if *verbose {
+			fmt.Fprintf(logOut, "  Wrote %d unmatched transactions to %s\n", len(unmatchedEntries), *unmatchedReport)
+		} else {
+			ui.Info(fmt.Sprintf("Wrote %d unmatched transactions to %s", len(unmatchedEntries), *unmatchedReport))
+		}
+	}
+
+	if *fileReport != "" {
+		if err := writeFileReport(*fileReport, fileReportEntries); err != nil {
+			return err
+		}
+		ui.Event("file_report_written", map[string]any{"path": *fileReport, "files": len(fileReportEntries)})
+		if *verbose {
+			fmt.Fprintf(logOut, "  Wrote per-file transaction counts for %d files to %s\n", len(fileReportEntries), *fileReport)
+		} else {
+			ui.Info(fmt.Sprintf("Wrote per-file transaction counts for %d files to %s", len(fileReportEntries), *fileReport))
+		}
+	}
+
+	// Phase 6: Validate budget before saving
+	if !*verbose {
+		fmt.Fprintf(logOut, "\n")
+		ui.Info("Validating budget...")
+	} else {
+		fmt.Fprintf(logOut, "\nValidating budget...\n")
+	}
+
+	var validationResult *validate.ValidationResult
+	if streamValidator != nil {
+		validationResult = streamValidator.Finish(budget)
+	} else {
+		validationResult = validate.ValidateBudget(budget)
+	}
+	logValidationResult(validationResult)
+	summary.ValidationErrors = len(validationResult.Errors)
+	summary.ValidationWarnings = len(validationResult.Warnings)
+	if len(validationResult.Errors) > 0 {
+		if *verbose {
+			fmt.Fprintf(logOut, "\nValidation failed with %d errors:\n", len(validationResult.Errors))
+			for _, e := range validationResult.Errors {
+				fmt.Fprintf(logOut, "  - %s %s [%s]: %s%s\n", e.Entity, e.ID, e.Field, e.Message, sourceSuffix(e.Source))
+			}
+		} else {
+			ui.Error(fmt.Sprintf("Validation failed with %d errors", len(validationResult.Errors)))
+			ui.Info("Showing first 5 errors (run with -verbose to see all):")
+			// Show first 5 errors
+			for i, e := range validationResult.Errors {
+				if i >= 5 {
+					ui.Error(fmt.Sprintf("... and %d more errors", len(validationResult.Errors)-5))
+					break
+				}
+				ui.Error(fmt.Sprintf("%s %s [%s]: %s%s", e.Entity, e.ID, e.Field, e.Message, sourceSuffix(e.Source)))
+			}
+			ui.Info("To fix: Review the errors above and check your statement files")
+		}
+		return fmt.Errorf("%w with %d errors", errValidation, len(validationResult.Errors))
+	}
+
+	if len(validationResult.Warnings) > 0 {
+		if *verbose {
+			fmt.Fprintf(logOut, "Validation warnings (%d):\n", len(validationResult.Warnings))
+			for _, w := range validationResult.Warnings {
+				fmt.Fprintf(logOut, "  - %s %s [%s]: %s\n", w.Entity, w.ID, w.Field, w.Message)
+			}
+		} else {
+			ui.Warning(fmt.Sprintf("Validation produced %d warnings", len(validationResult.Warnings)))
+		}
+	} else {
+		if !*verbose {
+			ui.Success("Validation passed")
+		} else {
+			fmt.Fprintf(logOut, "Validation passed\n")
+		}
+	}
+
+	// -fail-under fails like validation: before state or output are written
+	if coverageErr != nil {
+		return coverageErr
+	}
+
+	// CRITICAL ORDERING: Save state before writing output to prevent reprocessing on retry.
+	// This ordering provides retry safety:
+	//   - If state saves but output fails: retry output without re-parsing
+	//   - If state save fails: abort before output to maintain consistency
+	//   - Never write output with unsaved state (would lose deduplication on retry)
+	if state != nil && *stateFile != "" {
+		if err := dedup.SaveState(state, *stateFile); err != nil {
+			// State save failed - explain impact and provide recovery guidance
+			fmt.Fprintf(logOut, "\nERROR: Failed to save deduplication state: %v\n", err)
+			fmt.Fprintf(logOut, "\nThis means:\n")
+			fmt.Fprintf(logOut, "  - All parsing work for this run will be lost\n")
+			fmt.Fprintf(logOut, "  - Transactions will be reprocessed as NEW on next run\n")
+			fmt.Fprintf(logOut, "  - Output file will NOT be written to prevent inconsistency\n")
+
+			// Provide actionable recovery steps for common error types (permission, disk space)
+			if strings.Contains(err.Error(), "permission denied") {
+				stateDir := filepath.Dir(*stateFile)
+				fmt.Fprintf(logOut, "\nPermission denied - check directory permissions:\n")
+				fmt.Fprintf(logOut, "  ls -la %q\n", stateDir)
+			} else if strings.Contains(err.Error(), "no space left") {
+				fmt.Fprintf(logOut, "\nDisk full - check available space:\n")
+				fmt.Fprintf(logOut, "  df -h\n")
+			}
+
+			return fmt.Errorf("failed to save state file before writing output: %w", err)
+		} else if *verbose {
+			fmt.Fprintf(logOut, "Saved state with %d fingerprints to %s\n",
+				state.TotalFingerprints(), *stateFile)
+		}
+		ui.Event("state_saved", map[string]any{"path": *stateFile, "fingerprints": state.TotalFingerprints()})
+	}
+
+	opts := output.WriteOptions{
+		MergeMode: *mergeMode,
+		FilePath:  *outputFile,
+		Normalize: *normalize,
+		Anonymize: *anonymize,
+	}
+
+	if streamOut != nil {
+		if err := streamOut.WriteBudget(budget); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		if err := streamOut.Commit(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	} else if err := output.WriteBudgetToFile(budget, opts); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	ui.Event("output_written", map[string]any{"path": *outputFile, "merge": *mergeMode})
+
+	if *outputFile != "" {
+		if *verbose {
+			fmt.Printf("\nOutput written to %s\n", *outputFile)
+		} else {
+			fmt.Fprintf(logOut, "\n")
+			ui.Success(fmt.Sprintf("Output written to %s", *outputFile))
+		}
+	}
+
+	if *statsJSON != "" {
+		if err := writeStatsJSON(*statsJSON, summary); err != nil {
+			return err
+		}
+		ui.Event("stats_written", map[string]any{"path": *statsJSON})
+		if *verbose {
+			fmt.Fprintf(logOut, "Wrote run statistics to %s\n", *statsJSON)
+		}
+	}
+
+	return nil
+}
+
+// runSummary is the -stats-json file: the counters reported at the end of a run.
+// Transactions counts the output's transactions, after deduplication and split rules.
+type runSummary struct {
+	Files                        int `json:"files"`
+	Institutions                 int `json:"institutions"`
+	Accounts                     int `json:"accounts"`
+	Statements                   int `json:"statements"`
+	Transactions                 int `json:"transactions"`
+	TransactionsSplit            int `json:"transactionsSplit"`
+	DuplicatesSkipped            int `json:"duplicatesSkipped"`
+	DuplicateInstitutionsSkipped int `json:"duplicateInstitutionsSkipped"`
+	DuplicateAccountsSkipped     int `json:"duplicateAccountsSkipped"`
+	DuplicateStatementsMerged    int `json:"duplicateStatementsMerged"`
+	RulesMatched                 int `json:"rulesMatched"`
+	RulesUnmatched               int `json:"rulesUnmatched"`
+	// CoveragePercent is null without -rules or when no transactions were categorized
+	CoveragePercent *float64 `json:"coveragePercent"`
+	// RecurringDetected is only present with -detect-recurring
+	RecurringDetected  *int `json:"recurringDetected,omitempty"`
+	ValidationErrors   int  `json:"validationErrors"`
+	ValidationWarnings int  `json:"validationWarnings"`
+}
+
+// writeStatsJSON writes the -stats-json file.
+func writeStatsJSON(path string, summary runSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run statistics: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run statistics %s: %w", path, err)
+	}
+	return nil
+}
+
+// dumpRawStatement parses a single statement file and writes the RawStatement the
+// parser produced as indented JSON, before transform, dedup or rules touch it.
+// Files holding several accounts are written as one JSON document per account.
+func dumpRawStatement(path string, w io.Writer) error {
+	reg, err := registry.New()
+	if err != nil {
+		return fmt.Errorf("failed to create parser registry: %w", err)
+	}
+	p, err := reg.FindParser(path)
+	if err != nil {
+		return fmt.Errorf("failed to find parser for %s: %w", path, err)
+	}
+	if p == nil {
+		return fmt.Errorf("no parser found for %s", path)
+	}
+
+	meta, err := parser.NewMetadata(path, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create metadata for %s: %w", path, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	rawStmts, err := parser.ParseStatements(context.Background(), p, f, meta)
+	if err != nil {
+		return fmt.Errorf("parse failed for %s with %s parser: %w", path, p.Name(), err)
+	}
+
+	// Combined multi-account files produce one JSON document per account
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	for _, rawStmt := range rawStmts {
+		if rawStmt == nil {
+			return fmt.Errorf("parser %s violated interface contract: returned nil statement without error for %s (parser bug)", p.Name(), path)
+		}
+		if err := encoder.Encode(rawStmt); err != nil {
+			return fmt.Errorf("failed to encode raw statement: %w", err)
+		}
+	}
+	return nil
+}
+
+// unmatchedReportEntry is one transaction in the -unmatched-report file.
+type unmatchedReportEntry struct {
+	transform.UnmatchedTransaction
+	SourceFile string `json:"sourceFile"`
+}
+
+// printCoverageByInstitution prints one line per institution, worst coverage first, and
+// emits an institution_coverage event for each.
+func printCoverageByInstitution(w io.Writer, coverage []transform.InstitutionCoverage) {
+	fmt.Fprintf(w, "\nRule coverage by institution:\n")
+	if len(coverage) == 0 {
+		fmt.Fprintf(w, "  (no categorized transactions)\n")
+		return
+	}
+	for _, c := range coverage {
+		ui.Event("institution_coverage", map[string]any{
+			"institution_id":   c.InstitutionID,
+			"institution_name": c.InstitutionName,
+			"matched":          c.Matched,
+			"unmatched":        c.Unmatched,
+			"coverage_percent": c.Percent(),
+		})
+		fmt.Fprintf(w, "  %-24s %5.1f%% (%d/%d matched)\n", c.InstitutionName, c.Percent(), c.Matched, c.Total())
+	}
+}
+
+// writeUnmatchedReport writes every unmatched transaction to path as indented JSON.
+// Unlike the capped examples shown with -verbose, the report is complete, so it can
+// be used to write rules for the whole backlog at once.
+func writeUnmatchedReport(path string, entries []unmatchedReportEntry) error {
+	if entries == nil {
+		entries = []unmatchedReportEntry{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Count        int                    `json:"count"`
+		Transactions []unmatchedReportEntry `json:"transactions"`
+	}{len(entries), entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode unmatched report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write unmatched report %s: %w", path, err)
+	}
+	return nil
+}
+
+// fileReportEntry is one input file in the -transactions-per-file-report file.
+// New and Duplicates count the file's own transactions before any split rules.
+type fileReportEntry struct {
+	File         string `json:"file"`
+	Parser       string `json:"parser"`
+	Transactions int    `json:"transactions"`
+	New          int    `json:"new"`
+	Duplicates   int    `json:"duplicates"`
+	PeriodStart  string `json:"periodStart"`
+	PeriodEnd    string `json:"periodEnd"`
+}
+
+// checkStatementPeriods reports statements parsed without a period: a warning per file,
+// or with strict set, an error listing every file.
+func checkStatementPeriods(files []string, strict bool) error {
+	if len(files) == 0 {
+		return nil
+	}
+	for _, path := range files {
+		ui.Event("missing_period", map[string]any{"path": path, "strict": strict})
+	}
+	if strict {
+		return fmt.Errorf("%w: strict period: %d statement(s) have no period:\n  - %s",
+			errValidation, len(files), strings.Join(files, "\n  - "))
+	}
+	for _, path := range files {
+		ui.Warning(fmt.Sprintf("Statement has no period (continuity and overlap checks are unreliable for it): %s", path))
+	}
+	return nil
+}
+
+// writeFileReport writes per-file transaction counts to path, as CSV when path ends in
+// .csv and as indented JSON otherwise. Files are listed in processing order.
+func writeFileReport(path string, entries []fileReportEntry) error {
+	if entries == nil {
+		entries = []fileReportEntry{}
+	}
+
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"file", "parser", "transactions", "new", "duplicates", "period_start", "period_end"})
+		for _, e := range entries {
+			w.Write([]string{e.File, e.Parser, strconv.Itoa(e.Transactions), strconv.Itoa(e.New),
+				strconv.Itoa(e.Duplicates), e.PeriodStart, e.PeriodEnd})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to encode file report: %w", err)
+		}
+	} else {
+		data, err := json.MarshalIndent(struct {
+			Count int               `json:"count"`
+			Files []fileReportEntry `json:"files"`
+		}{len(entries), entries}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode file report: %w", err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file report %s: %w", path, err)
+	}
+	return nil
+}
+
+// reportFilters reports how many scanned files -institution and -format skipped
+func reportFilters(p *finparse.Pipeline) {
+	scanned := len(p.Scanned)
+	if *institutionFilter != "" && scanned > 0 {
+		ui.Event("institution_filter", map[string]any{"institution": *institutionFilter, "matched": scanned - p.InstitutionSkipped, "skipped": p.InstitutionSkipped})
+		if p.InstitutionSkipped > 0 {
+			ui.Info(fmt.Sprintf("Institution filter %q: skipped %d of %d files", *institutionFilter, p.InstitutionSkipped, scanned))
+		}
+	}
+
+	// The format filter runs on the files the institution filter kept
+	remaining := scanned - p.InstitutionSkipped
+	if *formatFilter != finparse.FormatAll && remaining > 0 {
+		ui.Event("format_filter", map[string]any{"format": *formatFilter, "matched": len(p.Files), "skipped": p.FormatSkipped})
+		if p.FormatSkipped > 0 {
+			ui.Info(fmt.Sprintf("Format filter %q: skipped %d of %d files", *formatFilter, p.FormatSkipped, remaining))
+		}
+	}
+}
+
+// noParserLabel marks files in the dry-run report that no registered parser accepts.
+const noParserLabel = "NO PARSER"
+
+// writeDryRunReport prints a path -> parser table for files, using find, the same
+// detection as a real run. It only reads file headers: nothing is parsed and no state
+// is touched. Returns an error if any file lacks a parser.
+func writeDryRunReport(w io.Writer, find func(path string) (parser.Parser, error), files []scanner.ScanResult) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	width := len("PATH")
+	for _, f := range files {
+		width = max(width, len(f.Path))
+	}
+
+	var missing []string
+	fmt.Fprintf(w, "%-*s  %s\n", width, "PATH", "PARSER")
+	for _, f := range files {
+		name := noParserLabel
+		p, err := find(f.Path)
+		if err == nil && p != nil {
+			name = p.Name()
+		} else {
+			missing = append(missing, f.Path)
+		}
+		fields := map[string]any{"path": f.Path, "parser": name}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		ui.Event("dry_run_file", fields)
+		fmt.Fprintf(w, "%-*s  %s\n", width, f.Path, name)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("dry run: no parser for %d of %d file(s):\n  - %s\n\nCheck the file extension (.qfx, .ofx, .csv) and that the content matches a supported format",
+			len(missing), len(files), strings.Join(missing, "\n  - "))
+	}
+	return nil
+}
+
+// logValidationResult emits one structured event per validation issue plus a summary,
+// so CI can assert on validation outcomes without parsing the human output.
+func logValidationResult(result *validate.ValidationResult) {
+	for _, e := range result.Errors {
+		logValidationIssue("error", e.Entity, e.ID, e.Field, e.Message, e.Source)
+	}
+	for _, w := range result.Warnings {
+		logValidationIssue("warning", w.Entity, w.ID, w.Field, w.Message, "")
+	}
+	ui.Event("validation_complete", map[string]any{
+		"errors":   len(result.Errors),
+		"warnings": len(result.Warnings),
+		"passed":   len(result.Errors) == 0,
+	})
+}
+
+func logValidationIssue(severity, entity, id, field, message, source string) {
+	fields := map[string]any{
+		"severity": severity,
+		"entity":   entity,
+		"id":       id,
+		"field":    field,
+		"message":  message,
+	}
+	if source != "" {
+		fields["source"] = source
+	}
+	ui.Event("validation_issue", fields)
+}
+
+// sourceSuffix formats a validation error's source for the human output, or returns ""
+// when sources aren't traced
+func sourceSuffix(source string) string {
+	if source == "" {
+		return ""
+	}
+	return " (at " + source + ")"
+}
+
+// reportCollisions prints fingerprints shared by distinct transactions.
+// Each collision means dedup would drop all but the first of those transactions.
+func reportCollisions(w io.Writer, tracker *dedup.CollisionTracker) {
+	collisions := tracker.Collisions()
+	ui.Event("collision_report", map[string]any{
+		"fingerprints_checked": tracker.TotalFingerprints(),
+		"collisions":           len(collisions),
+	})
+	fmt.Fprintf(w, "\nFingerprint collision report:\n")
+	fmt.Fprintf(w, "  Fingerprints checked: %d\n", tracker.TotalFingerprints())
+	if len(collisions) == 0 {
+		fmt.Fprintf(w, "  No collisions detected\n")
+		return
+	}
+
+	fmt.Fprintf(w, "  WARNING: %d potential collision(s) - dedup would treat these distinct transactions as one:\n", len(collisions))
+	for _, c := range collisions {
+		fmt.Fprintf(w, "  - %s (%d distinct transactions)\n", c.Fingerprint, len(c.Examples))
+		for _, example := range c.Examples {
+			fmt.Fprintf(w, "      %s\n", example)
+		}
+	}
+}
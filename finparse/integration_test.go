@@ -25,7 +25,7 @@ func TestIntegration_DryRun(t *testing.T) {
 
 	// Create test OFX file
 	testFile := filepath.Join(periodDir, "statement.ofx")
-	if err := os.WriteFile(testFile, []byte("test ofx content"), 0644); err != nil {
+	if err := os.WriteFile(testFile, detectableStatement("ofx"), 0644); err != nil {
 		t.Fatal(err)
 	}
 
@@ -120,7 +120,7 @@ func TestIntegration_MultipleFiles(t *testing.T) {
 		}
 
 		testFile := filepath.Join(acctDir, "statement."+inst.format)
-		if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		if err := os.WriteFile(testFile, detectableStatement(inst.format), 0644); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -177,7 +177,7 @@ func TestIntegration_RegistryIntegration(t *testing.T) {
 	}
 
 	testFile := filepath.Join(acctDir, "statement.ofx")
-	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+	if err := os.WriteFile(testFile, detectableStatement("ofx"), 0644); err != nil {
 		t.Fatal(err)
 	}
 
@@ -254,7 +254,7 @@ func TestIntegration_FileCountDisplay(t *testing.T) {
 				}
 
 				testFile := filepath.Join(acctDir, "statement.ofx")
-				if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+				if err := os.WriteFile(testFile, detectableStatement("ofx"), 0644); err != nil {
 					t.Fatal(err)
 				}
 			}
@@ -348,3 +348,13 @@ func getFinparseRoot(t *testing.T) string {
 		dir = parent
 	}
 }
+
+// detectableStatement returns the smallest content the parser registry recognizes for a
+// statement with the given extension (no leading dot). Dry-run only detects parsers, so
+// the content does not need to parse.
+func detectableStatement(ext string) []byte {
+	if ext == "csv" {
+		return []byte("5000000000000000,2025/10/01,2025/10/31,100.00,90.00\n")
+	}
+	return []byte("OFXHEADER:100\n")
+}
@@ -0,0 +1,97 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// CollisionTracker records, per fingerprint, the distinct transaction detail-hashes
+// observed for it. A fingerprint mapping to more than one detail-hash indicates a
+// potential collision: two genuinely different transactions that deduplication
+// would treat as one, silently dropping the second.
+//
+// The tracker is a diagnostic aid for validating a fingerprint strategy and does not
+// affect deduplication decisions.
+type CollisionTracker struct {
+	// fingerprint -> detail hash -> example description of the transaction
+	observed map[string]map[string]string
+}
+
+// Collision describes a fingerprint shared by more than one distinct transaction.
+type Collision struct {
+	Fingerprint string
+	// Examples holds one human-readable description per distinct detail-hash,
+	// sorted for stable output.
+	Examples []string
+}
+
+// NewCollisionTracker creates an empty collision tracker.
+func NewCollisionTracker() *CollisionTracker {
+	return &CollisionTracker{
+		observed: make(map[string]map[string]string),
+	}
+}
+
+// GenerateDetailHash creates a SHA256 hash of every identifying detail of a transaction.
+// Unlike GenerateFingerprint, the description is not normalized and the account and
+// parser-assigned transaction ID are included, so re-observing the same transaction
+// (e.g. from overlapping statements) yields the same hash while distinct transactions
+// that happen to share a fingerprint do not.
+// Format: SHA256("{accountID}|{transactionID}|{date}|{amount}|{description}")
+func GenerateDetailHash(accountID, transactionID, date string, amount float64, description string) string {
+	input := fmt.Sprintf("%s|%s|%s|%.2f|%s", accountID, transactionID, date, amount, description)
+	hash := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(hash[:])
+}
+
+// Observe records that a transaction with the given detail-hash produced fingerprint.
+// The example is kept for reporting (first example wins per detail-hash).
+func (t *CollisionTracker) Observe(fingerprint, detailHash, example string) error {
+	if fingerprint == "" {
+		return fmt.Errorf("fingerprint cannot be empty")
+	}
+	if detailHash == "" {
+		return fmt.Errorf("detail hash cannot be empty")
+	}
+
+	details, exists := t.observed[fingerprint]
+	if !exists {
+		details = make(map[string]string)
+		t.observed[fingerprint] = details
+	}
+	if _, seen := details[detailHash]; !seen {
+		details[detailHash] = example
+	}
+	return nil
+}
+
+// Collisions returns every fingerprint observed with more than one distinct
+// detail-hash, sorted by fingerprint.
+func (t *CollisionTracker) Collisions() []Collision {
+	var collisions []Collision
+	for fingerprint, details := range t.observed {
+		if len(details) < 2 {
+			continue
+		}
+		examples := make([]string, 0, len(details))
+		for _, example := range details {
+			examples = append(examples, example)
+		}
+		sort.Strings(examples)
+		collisions = append(collisions, Collision{
+			Fingerprint: fingerprint,
+			Examples:    examples,
+		})
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Fingerprint < collisions[j].Fingerprint
+	})
+	return collisions
+}
+
+// TotalFingerprints returns the number of distinct fingerprints observed.
+func (t *CollisionTracker) TotalFingerprints() int {
+	return len(t.observed)
+}
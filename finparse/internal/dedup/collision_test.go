@@ -0,0 +1,80 @@
+package dedup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollisionTracker_ReportsDistinctTransactionsSharingFingerprint(t *testing.T) {
+	tracker := NewCollisionTracker()
+
+	// Same date, amount and normalized description, but different transactions
+	fingerprint := GenerateFingerprint("2025-01-15", -5.00, "Coffee Shop")
+	if fingerprint != GenerateFingerprint("2025-01-15", -5.00, "COFFEE SHOP ") {
+		t.Fatal("expected crafted transactions to share a fingerprint")
+	}
+
+	first := GenerateDetailHash("Bank/1234", "TXN001", "2025-01-15", -5.00, "Coffee Shop")
+	second := GenerateDetailHash("Bank/1234", "TXN002", "2025-01-15", -5.00, "COFFEE SHOP ")
+	if first == second {
+		t.Fatal("expected distinct transactions to have distinct detail hashes")
+	}
+
+	if err := tracker.Observe(fingerprint, first, "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tracker.Observe(fingerprint, second, "second"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	collisions := tracker.Collisions()
+	if len(collisions) != 1 {
+		t.Fatalf("expected 1 collision, got %d", len(collisions))
+	}
+	if collisions[0].Fingerprint != fingerprint {
+		t.Errorf("expected fingerprint %q, got %q", fingerprint, collisions[0].Fingerprint)
+	}
+	if strings.Join(collisions[0].Examples, ",") != "first,second" {
+		t.Errorf("expected examples [first second], got %v", collisions[0].Examples)
+	}
+}
+
+func TestCollisionTracker_RepeatedTransactionIsNotCollision(t *testing.T) {
+	tracker := NewCollisionTracker()
+
+	// The same transaction seen in two overlapping statements
+	fingerprint := GenerateFingerprint("2025-01-15", -50.00, "Whole Foods")
+	detail := GenerateDetailHash("Bank/1234", "TXN001", "2025-01-15", -50.00, "Whole Foods")
+	for i := 0; i < 2; i++ {
+		if err := tracker.Observe(fingerprint, detail, "whole foods"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	other := GenerateFingerprint("2025-01-16", -50.00, "Whole Foods")
+	otherDetail := GenerateDetailHash("Bank/1234", "TXN002", "2025-01-16", -50.00, "Whole Foods")
+	if err := tracker.Observe(other, otherDetail, "next day"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if collisions := tracker.Collisions(); len(collisions) != 0 {
+		t.Errorf("expected no collisions, got %v", collisions)
+	}
+	if tracker.TotalFingerprints() != 2 {
+		t.Errorf("expected 2 fingerprints, got %d", tracker.TotalFingerprints())
+	}
+}
+
+func TestCollisionTracker_ObserveRejectsEmptyInput(t *testing.T) {
+	tracker := NewCollisionTracker()
+
+	if err := tracker.Observe("", "detail", "example"); err == nil {
+		t.Error("expected error for empty fingerprint")
+	}
+	if err := tracker.Observe("fingerprint", "", "example"); err == nil {
+		t.Error("expected error for empty detail hash")
+	}
+	if tracker.TotalFingerprints() != 0 {
+		t.Errorf("expected no fingerprints recorded, got %d", tracker.TotalFingerprints())
+	}
+}
@@ -176,6 +176,20 @@ func (s *State) UnmarshalJSON(data []byte) error {
 // TODO(#1429): StateMetadata underutilized - could track more useful state information
 type StateMetadata struct {
 	LastUpdated time.Time `json:"lastUpdated"`
+
+	// LastCoverage is the rule coverage percentage of the last saved run, or nil if no
+	// run has recorded one yet (e.g. no transactions were categorized)
+	LastCoverage *float64 `json:"lastCoverage,omitempty"`
+}
+
+// RecordCoverage stores coverage as the latest run's rule coverage and returns the
+// previous run's, with false when there was none
+func (m *StateMetadata) RecordCoverage(coverage float64) (previous float64, ok bool) {
+	if m.LastCoverage != nil {
+		previous, ok = *m.LastCoverage, true
+	}
+	m.LastCoverage = &coverage
+	return previous, ok
 }
 
 const (
@@ -255,7 +269,7 @@ func SaveState(state *State, filePath string) error {
 
 	// Atomic write pattern: write to temp file, then rename
 	tempFile := filePath + ".tmp"
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+	if err := writeFileSynced(tempFile, data); err != nil {
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
 
@@ -280,6 +294,24 @@ func SaveState(state *State, filePath string) error {
 	return nil
 }
 
+// writeFileSynced writes data and fsyncs before returning, so the subsequent rename
+// never exposes a partially written file after a crash or power loss.
+func writeFileSynced(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // IsDuplicate checks if a fingerprint exists in the state.
 func (s *State) IsDuplicate(fingerprint string) bool {
 	_, exists := s.fingerprints[fingerprint]
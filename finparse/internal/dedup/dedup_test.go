@@ -1989,3 +1989,29 @@ func TestGenerateFingerprint_SameFingerprintDifferentFITID(t *testing.T) {
 	t.Log("")
 	t.Log("Impact: Minimal - deduplication works correctly. FITID mismatch is cosmetic.")
 }
+
+func TestStateMetadata_RecordCoverage(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "state.json")
+
+	state := NewState()
+	if _, ok := state.Metadata.RecordCoverage(92.5); ok {
+		t.Error("RecordCoverage() on a new state reported a previous coverage")
+	}
+	state.RecordTransaction("abc123", "txn-001", time.Now())
+	if err := SaveState(state, stateFile); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	loaded, err := LoadState(stateFile)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	previous, ok := loaded.Metadata.RecordCoverage(80)
+	if !ok || previous != 92.5 {
+		t.Errorf("RecordCoverage() = %v, %v, want 92.5, true", previous, ok)
+	}
+	if loaded.Metadata.LastCoverage == nil || *loaded.Metadata.LastCoverage != 80 {
+		t.Errorf("LastCoverage = %v, want 80", loaded.Metadata.LastCoverage)
+	}
+}
@@ -22,9 +22,12 @@
 package domain
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"time"
 )
 
@@ -93,9 +96,33 @@ type Transaction struct {
 	transfer            bool     `json:"transfer"`
 	redemptionRate      float64  `json:"redemptionRate"`
 	LinkedTransactionID *string  `json:"linkedTransactionId,omitempty"`
+	SplitOf             *SplitOf `json:"splitOf,omitempty"` // Set on entries expanded from a split rule
+	Source              *Source  `json:"source,omitempty"`  // Set when tracing sources (finparse -trace-source)
 	statementIDs        []string
 }
 
+// SplitOf records the original transaction a split entry was expanded from.
+// Split entries of one parent sum exactly to ParentAmount.
+type SplitOf struct {
+	ParentID     string  `json:"parentId"`
+	ParentAmount float64 `json:"parentAmount"`
+}
+
+// Source records where a transaction was parsed from, for tracing odd values back to
+// the statement file.
+type Source struct {
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"` // 1-based row start; omitted for formats without rows (OFX)
+}
+
+// String formats the source as file:line, or just the file when there is no line.
+func (s Source) String() string {
+	if s.Line > 0 {
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	}
+	return s.File
+}
+
 // Statement matches TypeScript Statement interface.
 // After construction, Statement should be treated as immutable.
 // Modifying StartDate or EndDate fields directly may violate invariants.
@@ -107,6 +134,14 @@ type Statement struct {
 	StartDate      string `json:"startDate"` // YYYY-MM-DD (immutable)
 	EndDate        string `json:"endDate"`   // YYYY-MM-DD (immutable)
 	transactionIDs []string
+	balance        *StatementBalance // Not serialized; only available for freshly parsed statements
+}
+
+// StatementBalance carries what is needed to reconcile a statement against the
+// institution-reported balance.
+type StatementBalance struct {
+	Ledger      float64 // Closing balance reported by the institution
+	ParsedTotal float64 // Sum of every transaction parsed from the statement, including dedup-skipped ones
 }
 
 // Account matches TypeScript Account interface
@@ -123,6 +158,33 @@ type Institution struct {
 	Name string `json:"name"`
 }
 
+// RecurringCadence is the estimated interval between occurrences of a recurring charge
+type RecurringCadence string
+
+const (
+	RecurringCadenceWeekly   RecurringCadence = "weekly"
+	RecurringCadenceBiweekly RecurringCadence = "biweekly"
+	RecurringCadenceMonthly  RecurringCadence = "monthly"
+)
+
+// RecurringCharge is a likely subscription or recurring payment detected across statements.
+// It is derived from transactions for review only and never affects them.
+type RecurringCharge struct {
+	Merchant string           `json:"merchant"`
+	Amount   float64          `json:"amount"`
+	Cadence  RecurringCadence `json:"cadence"`
+	Dates    []string         `json:"dates"` // Occurrence dates, YYYY-MM-DD ascending
+}
+
+// Summary holds totals computed from the budget's categorized transactions.
+// Like recurring charges it is derived for review only and never affects them.
+type Summary struct {
+	// ByMonth maps YYYY-MM to each category's net total that month. Totals follow the
+	// transaction sign convention: negative is net spending, positive is net income.
+	// Transfers are excluded, and months or categories without transactions are absent.
+	ByMonth map[string]map[Category]float64 `json:"byMonth"`
+}
+
 // Budget is the root output structure (full JSON file)
 // TODO(#1439): Add atomic multi-entity operations like AddAccountWithStatements to prevent partial failures
 type Budget struct {
@@ -130,6 +192,8 @@ type Budget struct {
 	accounts     []Account
 	statements   []Statement
 	transactions []Transaction
+	recurring    []RecurringCharge // Only populated by recurring detection
+	summary      *Summary          // Only populated by SummarizeByMonth
 }
 
 // NewBudget creates an empty budget with initialized slices
@@ -231,6 +295,17 @@ func (b *Budget) AddTransaction(txn Transaction) error {
 	return nil
 }
 
+// HasTransaction reports whether a transaction with id is in the budget. Transactions
+// already drained by DrainTransactions are not.
+func (b *Budget) HasTransaction(id string) bool {
+	for _, existing := range b.transactions {
+		if existing.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // GetInstitutions returns a defensive copy of the institutions slice
 func (b *Budget) GetInstitutions() []Institution {
 	return append([]Institution(nil), b.institutions...)
@@ -251,28 +326,117 @@ func (b *Budget) GetTransactions() []Transaction {
 	return append([]Transaction(nil), b.transactions...)
 }
 
-// MarshalJSON implements custom JSON marshaling for Budget
+// TransactionCount returns the number of transactions held in memory
+func (b *Budget) TransactionCount() int {
+	return len(b.transactions)
+}
+
+// DrainTransactions removes and returns all transactions held in memory.
+// Used by streaming output, which writes transactions in chunks instead of
+// accumulating them. Duplicate-ID checks in AddTransaction only cover
+// transactions added since the last drain.
+func (b *Budget) DrainTransactions() []Transaction {
+	drained := b.transactions
+	b.transactions = []Transaction{}
+	return drained
+}
+
+// SetRecurring replaces the detected recurring charges
+func (b *Budget) SetRecurring(recurring []RecurringCharge) {
+	b.recurring = append([]RecurringCharge(nil), recurring...)
+}
+
+// GetRecurring returns a defensive copy of the detected recurring charges
+func (b *Budget) GetRecurring() []RecurringCharge {
+	return append([]RecurringCharge(nil), b.recurring...)
+}
+
+// SummarizeByMonth computes the budget's summary from the transactions it holds and
+// stores it, replacing any earlier one. Amounts are summed in whole cents so totals
+// don't drift with the number of transactions.
+func (b *Budget) SummarizeByMonth() error {
+	cents := make(map[string]map[Category]int64)
+	for _, txn := range b.transactions {
+		// Money moving between the user's own accounts is neither spending nor income
+		if txn.transfer {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", txn.Date)
+		if err != nil {
+			return fmt.Errorf("transaction %s has invalid date %q: %w", txn.ID, txn.Date, err)
+		}
+		month := date.Format("2006-01")
+		if cents[month] == nil {
+			cents[month] = make(map[Category]int64)
+		}
+		cents[month][txn.Category] += int64(math.Round(txn.Amount * 100))
+	}
+
+	byMonth := make(map[string]map[Category]float64, len(cents))
+	for month, categories := range cents {
+		byMonth[month] = make(map[Category]float64, len(categories))
+		for category, total := range categories {
+			byMonth[month][category] = float64(total) / 100
+		}
+	}
+	b.summary = &Summary{ByMonth: byMonth}
+	return nil
+}
+
+// GetSummary returns the budget's summary, or nil if none was computed
+func (b *Budget) GetSummary() *Summary {
+	return b.summary
+}
+
+// Sort orders the budget's entities independently of the order they were added in, so
+// serializing the same data always gives the same output: institutions by name,
+// accounts by institution and name, statements by account and start date, and
+// transactions by date, amount and description. IDs break any remaining ties.
+func (b *Budget) Sort() {
+	slices.SortStableFunc(b.institutions, func(x, y Institution) int {
+		return cmp.Or(cmp.Compare(x.Name, y.Name), cmp.Compare(x.ID, y.ID))
+	})
+	slices.SortStableFunc(b.accounts, func(x, y Account) int {
+		return cmp.Or(cmp.Compare(x.InstitutionID, y.InstitutionID), cmp.Compare(x.Name, y.Name), cmp.Compare(x.ID, y.ID))
+	})
+	slices.SortStableFunc(b.statements, func(x, y Statement) int {
+		return cmp.Or(cmp.Compare(x.AccountID, y.AccountID), cmp.Compare(x.StartDate, y.StartDate), cmp.Compare(x.ID, y.ID))
+	})
+	slices.SortStableFunc(b.transactions, func(x, y Transaction) int {
+		return cmp.Or(cmp.Compare(x.Date, y.Date), cmp.Compare(x.Amount, y.Amount),
+			cmp.Compare(x.Description, y.Description), cmp.Compare(x.ID, y.ID))
+	})
+}
+
+// MarshalJSON implements custom JSON marshaling for Budget.
+// The recurring and summary sections are omitted unless they were computed.
 func (b *Budget) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		Institutions []Institution `json:"institutions"`
-		Accounts     []Account     `json:"accounts"`
-		Statements   []Statement   `json:"statements"`
-		Transactions []Transaction `json:"transactions"`
+		Institutions []Institution     `json:"institutions"`
+		Accounts     []Account         `json:"accounts"`
+		Statements   []Statement       `json:"statements"`
+		Transactions []Transaction     `json:"transactions"`
+		Recurring    []RecurringCharge `json:"recurring,omitempty"`
+		Summary      *Summary          `json:"summary,omitempty"`
 	}{
 		Institutions: append([]Institution(nil), b.institutions...),
 		Accounts:     append([]Account(nil), b.accounts...),
 		Statements:   append([]Statement(nil), b.statements...),
 		Transactions: append([]Transaction(nil), b.transactions...),
+		Recurring:    append([]RecurringCharge(nil), b.recurring...),
+		Summary:      b.summary,
 	})
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Budget
 func (b *Budget) UnmarshalJSON(data []byte) error {
 	aux := &struct {
-		Institutions []Institution `json:"institutions"`
-		Accounts     []Account     `json:"accounts"`
-		Statements   []Statement   `json:"statements"`
-		Transactions []Transaction `json:"transactions"`
+		Institutions []Institution     `json:"institutions"`
+		Accounts     []Account         `json:"accounts"`
+		Statements   []Statement       `json:"statements"`
+		Transactions []Transaction     `json:"transactions"`
+		Recurring    []RecurringCharge `json:"recurring"`
+		Summary      *Summary          `json:"summary"`
 	}{}
 	if err := json.Unmarshal(data, aux); err != nil {
 		return err
@@ -311,6 +475,8 @@ func (b *Budget) UnmarshalJSON(data []byte) error {
 	b.accounts = aux.Accounts
 	b.statements = aux.Statements
 	b.transactions = aux.Transactions
+	b.recurring = aux.Recurring
+	b.summary = aux.Summary
 	return nil
 }
 
@@ -441,6 +607,8 @@ func (t *Transaction) MarshalJSON() ([]byte, error) {
 		Transfer            bool     `json:"transfer"`
 		RedemptionRate      float64  `json:"redemptionRate"`
 		LinkedTransactionID *string  `json:"linkedTransactionId,omitempty"`
+		SplitOf             *SplitOf `json:"splitOf,omitempty"`
+		Source              *Source  `json:"source,omitempty"`
 		StatementIDs        []string `json:"statementIds"`
 	}{
 		ID:                  t.ID,
@@ -453,6 +621,8 @@ func (t *Transaction) MarshalJSON() ([]byte, error) {
 		Transfer:            t.transfer,
 		RedemptionRate:      t.redemptionRate,
 		LinkedTransactionID: t.LinkedTransactionID,
+		SplitOf:             t.SplitOf,
+		Source:              t.Source,
 		StatementIDs:        statementIDsCopy,
 	})
 }
@@ -471,6 +641,8 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 		Transfer            bool     `json:"transfer"`
 		RedemptionRate      float64  `json:"redemptionRate"`
 		LinkedTransactionID *string  `json:"linkedTransactionId,omitempty"`
+		SplitOf             *SplitOf `json:"splitOf,omitempty"`
+		Source              *Source  `json:"source,omitempty"`
 		StatementIDs        []string `json:"statementIds"`
 	}{}
 
@@ -487,6 +659,8 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 	t.vacation = aux.Vacation
 	t.transfer = aux.Transfer
 	t.LinkedTransactionID = aux.LinkedTransactionID
+	t.SplitOf = aux.SplitOf
+	t.Source = aux.Source
 	t.statementIDs = aux.StatementIDs
 
 	// Validate redemption rate bounds
@@ -576,6 +750,19 @@ func (s *Statement) AddTransactionID(id string) error {
 	return nil
 }
 
+// SetBalance records the reported closing balance and parsed transaction total
+func (s *Statement) SetBalance(balance StatementBalance) {
+	s.balance = &balance
+}
+
+// Balance returns the statement balance and whether one was recorded
+func (s *Statement) Balance() (StatementBalance, bool) {
+	if s.balance == nil {
+		return StatementBalance{}, false
+	}
+	return *s.balance, true
+}
+
 // GetTransactionIDs returns a defensive copy of the transaction IDs slice
 func (s *Statement) GetTransactionIDs() []string {
 	if s.transactionIDs == nil {
@@ -3,6 +3,8 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -828,3 +830,118 @@ func TestStatement_Validate(t *testing.T) {
 		}
 	})
 }
+
+func TestBudget_SummarizeByMonth(t *testing.T) {
+	budget := NewBudget()
+	add := func(id, date string, amount float64, category Category, transfer bool) {
+		t.Helper()
+		txn, err := NewTransaction(id, date, "merchant", amount, category)
+		if err != nil {
+			t.Fatalf("NewTransaction failed: %v", err)
+		}
+		if err := txn.SetTransfer(transfer); err != nil {
+			t.Fatalf("SetTransfer failed: %v", err)
+		}
+		if err := budget.AddTransaction(*txn); err != nil {
+			t.Fatalf("AddTransaction failed: %v", err)
+		}
+	}
+	add("t1", "2024-01-03", -0.1, CategoryGroceries, false)
+	add("t2", "2024-01-17", -0.2, CategoryGroceries, false)
+	add("t3", "2024-01-31", 3200, CategoryIncome, false)
+	add("t4", "2024-01-20", -500, CategoryOther, true) // Transfer: excluded
+	add("t5", "2024-03-02", -40, CategoryShopping, false)
+	add("t6", "2024-03-09", 15.5, CategoryShopping, false) // Partial refund
+	add("t7", "2024-04-11", 25, CategoryDining, false)     // Refund only: net income
+
+	if budget.GetSummary() != nil {
+		t.Fatal("Expected no summary before SummarizeByMonth")
+	}
+	if err := budget.SummarizeByMonth(); err != nil {
+		t.Fatalf("SummarizeByMonth failed: %v", err)
+	}
+
+	want := map[string]map[Category]float64{
+		"2024-01": {CategoryGroceries: -0.3, CategoryIncome: 3200},
+		"2024-03": {CategoryShopping: -24.5},
+		"2024-04": {CategoryDining: 25},
+	}
+	if got := budget.GetSummary().ByMonth; !reflect.DeepEqual(got, want) {
+		t.Errorf("ByMonth = %v, want %v", got, want)
+	}
+
+	t.Run("invalid date", func(t *testing.T) {
+		bad := NewBudget()
+		if err := bad.AddTransaction(Transaction{ID: "bad", Date: "01/02/2024", Category: CategoryOther}); err != nil {
+			t.Fatalf("AddTransaction failed: %v", err)
+		}
+		if err := bad.SummarizeByMonth(); err == nil || !strings.Contains(err.Error(), "bad") {
+			t.Errorf("Expected invalid date error naming the transaction, got %v", err)
+		}
+	})
+
+	t.Run("round trips through JSON", func(t *testing.T) {
+		data, err := json.Marshal(budget)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var decoded Budget
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if decoded.GetSummary() == nil || !reflect.DeepEqual(decoded.GetSummary().ByMonth, want) {
+			t.Errorf("Summary after round trip = %+v, want %v", decoded.GetSummary(), want)
+		}
+
+		data, err = json.Marshal(NewBudget())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if strings.Contains(string(data), "summary") {
+			t.Errorf("Expected no summary section when not computed, got %s", data)
+		}
+	})
+}
+
+func TestBudget_RecurringJSON(t *testing.T) {
+	t.Run("omitted when not detected", func(t *testing.T) {
+		data, err := json.Marshal(NewBudget())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, exists := fields["recurring"]; exists {
+			t.Errorf("Expected no recurring section, got %s", fields["recurring"])
+		}
+	})
+
+	t.Run("round trips when detected", func(t *testing.T) {
+		budget := NewBudget()
+		budget.SetRecurring([]RecurringCharge{{
+			Merchant: "Streaming Service",
+			Amount:   -15.99,
+			Cadence:  RecurringCadenceMonthly,
+			Dates:    []string{"2024-01-05", "2024-02-05", "2024-03-05"},
+		}})
+
+		data, err := json.Marshal(budget)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var decoded Budget
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		recurring := decoded.GetRecurring()
+		if len(recurring) != 1 {
+			t.Fatalf("Expected 1 recurring charge, got %d", len(recurring))
+		}
+		if recurring[0].Cadence != RecurringCadenceMonthly || len(recurring[0].Dates) != 3 {
+			t.Errorf("Unexpected recurring charge after round trip: %+v", recurring[0])
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+// anonymizeSaltSize is the length of the random per-run salt, in bytes
+const anonymizeSaltSize = 32
+
+// newAnonymizeSalt returns a random salt for one anonymized write. It is never
+// written anywhere, so tokens can't be reversed or matched across runs.
+func newAnonymizeSalt() ([]byte, error) {
+	salt := make([]byte, anonymizeSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate anonymization salt: %w", err)
+	}
+	return salt, nil
+}
+
+// anonymizer replaces identifying values with salted hash tokens. The same value of
+// the same kind always gets the same token, so references between entities and
+// repeated merchants still line up in the output.
+type anonymizer struct {
+	salt []byte
+}
+
+// token returns the stable token for value, e.g. "acc-3f9a0c12d4e7"
+func (a anonymizer) token(prefix, value string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(prefix))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return prefix + "-" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// anonymizeBudget returns a copy of budget with account, statement and transaction IDs,
+// account names, descriptions, recurring merchants and source files replaced by tokens.
+// Institutions, amounts, dates, categories, flags and the summary are kept, as is the
+// structure: every reference between entities points at the same token as its target.
+func anonymizeBudget(budget *domain.Budget, salt []byte) (*domain.Budget, error) {
+	if budget == nil {
+		return nil, fmt.Errorf("budget cannot be nil")
+	}
+	a := anonymizer{salt: salt}
+
+	raw, err := json.Marshal(budget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode budget as JSON: %w", err)
+	}
+	// Decode generically so every field is carried over; UseNumber keeps amounts exact
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode budget for anonymization: %w", err)
+	}
+
+	for _, acc := range objects(doc["accounts"]) {
+		replaceString(acc, "id", func(s string) string { return a.token("acc", s) })
+		// Names are derived from the account number ("Account 2011")
+		replaceString(acc, "name", func(s string) string { return a.token("Account", s) })
+	}
+	for _, stmt := range objects(doc["statements"]) {
+		replaceString(stmt, "id", func(s string) string { return a.token("stmt", s) })
+		replaceString(stmt, "accountId", func(s string) string { return a.token("acc", s) })
+		replaceStrings(stmt, "transactionIds", func(s string) string { return a.token("txn", s) })
+	}
+	for _, txn := range objects(doc["transactions"]) {
+		replaceString(txn, "id", func(s string) string { return a.token("txn", s) })
+		replaceString(txn, "description", func(s string) string { return a.token("desc", s) })
+		replaceString(txn, "linkedTransactionId", func(s string) string { return a.token("txn", s) })
+		if splitOf, ok := txn["splitOf"].(map[string]any); ok {
+			replaceString(splitOf, "parentId", func(s string) string { return a.token("txn", s) })
+		}
+		if source, ok := txn["source"].(map[string]any); ok {
+			// Paths often name the account; keep the extension to show the format
+			replaceString(source, "file", func(s string) string { return a.token("file", s) + filepath.Ext(s) })
+		}
+		replaceStrings(txn, "statementIds", func(s string) string { return a.token("stmt", s) })
+	}
+	for _, charge := range objects(doc["recurring"]) {
+		// Recurring merchants are normalized descriptions
+		replaceString(charge, "merchant", func(s string) string { return a.token("desc", s) })
+	}
+
+	anonymized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode anonymized budget: %w", err)
+	}
+	var result domain.Budget
+	if err := json.Unmarshal(anonymized, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode anonymized budget: %w", err)
+	}
+	return &result, nil
+}
+
+// objects returns the objects in a decoded JSON array (nil for anything else)
+func objects(v any) []map[string]any {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	result := make([]map[string]any, 0, len(arr))
+	for _, item := range arr {
+		if obj, ok := item.(map[string]any); ok {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// replaceString rewrites obj[key] with replace if it is a string
+func replaceString(obj map[string]any, key string, replace func(string) string) {
+	if s, ok := obj[key].(string); ok {
+		obj[key] = replace(s)
+	}
+}
+
+// replaceStrings rewrites each string in the array obj[key] with replace
+func replaceStrings(obj map[string]any, key string, replace func(string) string) {
+	arr, ok := obj[key].([]any)
+	if !ok {
+		return
+	}
+	for i, item := range arr {
+		if s, ok := item.(string); ok {
+			arr[i] = replace(s)
+		}
+	}
+}
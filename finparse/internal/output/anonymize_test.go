@@ -0,0 +1,165 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+// buildSensitiveBudget returns a budget with an account number in its account and
+// statement IDs, a repeated merchant, a split and a traced source file.
+func buildSensitiveBudget(t *testing.T) *domain.Budget {
+	t.Helper()
+	budget := domain.NewBudget()
+	inst, err := domain.NewInstitution("american-express", "American Express")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err := domain.NewAccount("acc-amex-2011", inst.ID, "Account 2011", domain.AccountTypeCredit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := domain.NewStatement("stmt-2024-01-acc-amex-2011", acc.ID, "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := budget.AddInstitution(*inst); err != nil {
+		t.Fatal(err)
+	}
+	if err := budget.AddAccount(*acc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		id, date, desc string
+		amount         float64
+	}{
+		{"FIT001", "2024-01-05", "JOE'S COFFEE 2011", -4.25},
+		{"FIT002", "2024-01-12", "JOE'S COFFEE 2011", -5.75},
+		{"FIT003-split-1", "2024-01-20", "ACME RENT", -700},
+	} {
+		txn, err := domain.NewTransaction(tc.id, tc.date, tc.desc, tc.amount, domain.CategoryDining)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.AddStatementID(stmt.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.AddTransactionID(tc.id); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(tc.id, "-split-1") {
+			txn.SplitOf = &domain.SplitOf{ParentID: "FIT003", ParentAmount: -1400}
+		}
+		txn.Source = &domain.Source{File: "/home/me/statements/amex/2011/jan.qfx"}
+		if err := budget.AddTransaction(*txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := budget.AddStatement(*stmt); err != nil {
+		t.Fatal(err)
+	}
+	return budget
+}
+
+func TestAnonymizeBudget(t *testing.T) {
+	budget := buildSensitiveBudget(t)
+	salt := []byte("test salt")
+
+	got, err := anonymizeBudget(budget, salt)
+	if err != nil {
+		t.Fatalf("anonymizeBudget failed: %v", err)
+	}
+	a := anonymizer{salt: salt}
+
+	// Structure and non-identifying values are kept
+	if len(got.GetInstitutions()) != 1 || got.GetInstitutions()[0].Name != "American Express" {
+		t.Errorf("Institutions = %+v, want American Express kept", got.GetInstitutions())
+	}
+	accounts := got.GetAccounts()
+	if len(accounts) != 1 || accounts[0].ID != a.token("acc", "acc-amex-2011") || accounts[0].Type != domain.AccountTypeCredit {
+		t.Errorf("Accounts = %+v, want one tokenized credit account", accounts)
+	}
+	statements := got.GetStatements()
+	if len(statements) != 1 || statements[0].AccountID != accounts[0].ID || statements[0].StartDate != "2024-01-01" {
+		t.Errorf("Statements = %+v, want one statement referencing the tokenized account", statements)
+	}
+
+	txns := got.GetTransactions()
+	if len(txns) != 3 {
+		t.Fatalf("Got %d transactions, want 3", len(txns))
+	}
+	for i, want := range budget.GetTransactions() {
+		txn := txns[i]
+		if txn.Amount != want.Amount || txn.Date != want.Date || txn.Category != want.Category {
+			t.Errorf("Transaction %d = %+v, want amount, date and category of %+v", i, txn, want)
+		}
+		if ids := txn.GetStatementIDs(); len(ids) != 1 || ids[0] != statements[0].ID {
+			t.Errorf("Transaction %d statementIds = %v, want [%s]", i, ids, statements[0].ID)
+		}
+		if ids := statements[0].GetTransactionIDs(); len(ids) != 3 || ids[i] != txn.ID {
+			t.Errorf("Statement transactionIds = %v, want %s at %d", ids, txn.ID, i)
+		}
+		if txn.Source == nil || !strings.HasSuffix(txn.Source.File, ".qfx") || strings.Contains(txn.Source.File, "2011") {
+			t.Errorf("Transaction %d source = %+v, want a tokenized .qfx file", i, txn.Source)
+		}
+	}
+	// The same merchant keeps the same token
+	if txns[0].Description != txns[1].Description || txns[0].Description == txns[2].Description {
+		t.Errorf("Descriptions = %q, %q, %q; want the repeated merchant to share a token",
+			txns[0].Description, txns[1].Description, txns[2].Description)
+	}
+	if txns[2].SplitOf == nil || txns[2].SplitOf.ParentID != a.token("txn", "FIT003") || txns[2].SplitOf.ParentAmount != -1400 {
+		t.Errorf("SplitOf = %+v, want the tokenized parent", txns[2].SplitOf)
+	}
+
+	// Nothing identifying survives anywhere in the output
+	var out strings.Builder
+	if err := WriteBudget(got, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"2011", "COFFEE", "ACME", "FIT00", "/home/me"} {
+		if strings.Contains(out.String(), secret) {
+			t.Errorf("Anonymized output contains %q:\n%s", secret, out.String())
+		}
+	}
+
+	// The original budget is untouched
+	if budget.GetAccounts()[0].ID != "acc-amex-2011" || budget.GetTransactions()[0].Description != "JOE'S COFFEE 2011" {
+		t.Error("anonymizeBudget modified its input")
+	}
+
+	// Another salt gives other tokens
+	other, err := anonymizeBudget(budget, []byte("other salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.GetAccounts()[0].ID == accounts[0].ID {
+		t.Error("Different salts should give different tokens")
+	}
+}
+
+func TestWriteBudgetToFile_Anonymize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+	if err := WriteBudgetToFile(buildSensitiveBudget(t), WriteOptions{FilePath: path, Anonymize: true}); err != nil {
+		t.Fatalf("WriteBudgetToFile failed: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if strings.Contains(string(got), "acc-amex-2011") || strings.Contains(string(got), "COFFEE") {
+		t.Errorf("Anonymized file leaks identifying values:\n%s", got)
+	}
+	if !strings.Contains(string(got), `"amount": -4.25`) {
+		t.Errorf("Anonymized file should keep amounts:\n%s", got)
+	}
+
+	err = WriteBudgetToFile(buildSensitiveBudget(t), WriteOptions{FilePath: path, Anonymize: true, MergeMode: true})
+	if err == nil || !strings.Contains(err.Error(), "anonymized output cannot be merged") {
+		t.Errorf("Merging anonymized output: err = %v, want rejection", err)
+	}
+}
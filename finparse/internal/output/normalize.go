@@ -0,0 +1,165 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+// WriteNormalizedBudget serializes Budget in canonical form, so identical budgets
+// produce byte-identical files regardless of the order entities were added in:
+//   - object keys sorted, 2-space indentation and a single trailing newline
+//   - institutions, accounts and statements sorted by ID, transactions by date then ID,
+//     recurring charges by merchant, cadence and amount, and each transaction's
+//     statementIds sorted
+//   - numbers in plain decimal notation with the shortest exact digits (no exponents, no -0)
+//   - RFC 3339 timestamps converted to UTC (YYYY-MM-DD dates are left as-is)
+func WriteNormalizedBudget(budget *domain.Budget, w io.Writer) error {
+	if budget == nil {
+		return fmt.Errorf("budget cannot be nil")
+	}
+
+	raw, err := json.Marshal(budget)
+	if err != nil {
+		return fmt.Errorf("failed to encode budget as JSON: %w", err)
+	}
+
+	// Decode generically; maps re-encode with sorted keys
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode budget for normalization: %w", err)
+	}
+
+	sortObjects(doc["institutions"], "id")
+	sortObjects(doc["accounts"], "id")
+	sortObjects(doc["statements"], "id")
+	sortObjects(doc["transactions"], "date", "id")
+	sortObjects(doc["recurring"], "merchant", "cadence", "amount")
+	if txns, ok := doc["transactions"].([]any); ok {
+		for _, txn := range txns {
+			if obj, ok := txn.(map[string]any); ok {
+				sortStrings(obj["statementIds"])
+			}
+		}
+	}
+
+	normalized, err := normalizeValue(doc)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(normalized); err != nil {
+		return fmt.Errorf("failed to encode normalized budget as JSON: %w", err)
+	}
+	return nil
+}
+
+// sortObjects stably sorts a JSON array of objects by the given keys. Non-arrays are
+// left untouched.
+func sortObjects(v any, keys ...string) {
+	items, ok := v.([]any)
+	if !ok {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, _ := items[i].(map[string]any)
+		b, _ := items[j].(map[string]any)
+		for _, key := range keys {
+			if c := compareScalars(a[key], b[key]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareScalars orders two decoded JSON scalars: numbers numerically, everything
+// else by its text.
+func compareScalars(a, b any) int {
+	na, aIsNum := a.(json.Number)
+	nb, bIsNum := b.(json.Number)
+	if aIsNum && bIsNum {
+		fa, errA := na.Float64()
+		fb, errB := nb.Float64()
+		if errA == nil && errB == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// sortStrings sorts a JSON array of strings in place. Non-arrays are left untouched.
+func sortStrings(v any) {
+	items, ok := v.([]any)
+	if !ok {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, _ := items[i].(string)
+		b, _ := items[j].(string)
+		return a < b
+	})
+}
+
+// normalizeValue rewrites numbers and timestamps in a decoded JSON value to canonical form.
+func normalizeValue(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			normalized, err := normalizeValue(child)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = normalized
+		}
+		return v, nil
+	case []any:
+		for i, child := range v {
+			normalized, err := normalizeValue(child)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+		return v, nil
+	case json.Number:
+		return normalizeNumber(v)
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return ts.UTC().Format(time.RFC3339Nano), nil
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// normalizeNumber formats n in plain decimal notation with the fewest digits that
+// round-trip, so 1e2, 100.0 and 100 all become 100.
+func normalizeNumber(n json.Number) (json.Number, error) {
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid number %q in budget: %w", n, err)
+	}
+	if f == 0 {
+		return "0", nil // Also folds -0
+	}
+	return json.Number(strconv.FormatFloat(f, 'f', -1, 64)), nil
+}
@@ -0,0 +1,166 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+// buildBudget adds the same two institutions, accounts, statements and transactions,
+// in forward or reverse order, as if files had been processed in a different order.
+func buildBudget(t *testing.T, reverse bool) *domain.Budget {
+	t.Helper()
+
+	type entry struct {
+		inst, acc, stmt, txn, date string
+		amount                     float64
+	}
+	entries := []entry{
+		{"bank-a", "acc-a", "stmt-a", "txn-a", "2024-02-01", -12.5},
+		{"bank-b", "acc-b", "stmt-b", "txn-b", "2024-01-15", 1e2},
+	}
+	if reverse {
+		entries[0], entries[1] = entries[1], entries[0]
+	}
+
+	budget := domain.NewBudget()
+	for _, e := range entries {
+		inst, err := domain.NewInstitution(e.inst, strings.ToUpper(e.inst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		acc, err := domain.NewAccount(e.acc, e.inst, "Checking", domain.AccountTypeChecking)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt, err := domain.NewStatement(e.stmt, e.acc, "2024-01-01", "2024-02-29")
+		if err != nil {
+			t.Fatal(err)
+		}
+		txn, err := domain.NewTransaction(e.txn, e.date, "Purchase", e.amount, domain.CategoryOther)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Both statements cover every transaction, attached in processing order
+		stmtIDs := []string{"stmt-a", "stmt-b"}
+		if reverse {
+			stmtIDs[0], stmtIDs[1] = stmtIDs[1], stmtIDs[0]
+		}
+		for _, id := range stmtIDs {
+			if err := txn.AddStatementID(id); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := budget.AddInstitution(*inst); err != nil {
+			t.Fatal(err)
+		}
+		if err := budget.AddAccount(*acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := budget.AddStatement(*stmt); err != nil {
+			t.Fatal(err)
+		}
+		if err := budget.AddTransaction(*txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return budget
+}
+
+func TestWriteNormalizedBudget_IndependentOfProcessingOrder(t *testing.T) {
+	var forward, reverse bytes.Buffer
+	if err := WriteNormalizedBudget(buildBudget(t, false), &forward); err != nil {
+		t.Fatalf("WriteNormalizedBudget failed: %v", err)
+	}
+	if err := WriteNormalizedBudget(buildBudget(t, true), &reverse); err != nil {
+		t.Fatalf("WriteNormalizedBudget failed: %v", err)
+	}
+
+	if !bytes.Equal(forward.Bytes(), reverse.Bytes()) {
+		t.Fatalf("Normalized output differs by processing order:\n%s\nvs\n%s", forward.String(), reverse.String())
+	}
+
+	// The plain writer keeps insertion order, so the inputs really were ordered differently
+	var plainForward, plainReverse bytes.Buffer
+	if err := WriteBudget(buildBudget(t, false), &plainForward); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBudget(buildBudget(t, true), &plainReverse); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(plainForward.Bytes(), plainReverse.Bytes()) {
+		t.Fatal("Test budgets serialize identically without normalization; the test proves nothing")
+	}
+
+	out := forward.String()
+	if !strings.HasSuffix(out, "}\n") || strings.HasSuffix(out, "\n\n") {
+		t.Errorf("Output should end with exactly one newline, got %q", out[len(out)-5:])
+	}
+	// Transactions by date: txn-b (2024-01-15) before txn-a (2024-02-01)
+	if strings.Index(out, `"txn-b"`) > strings.Index(out, `"txn-a"`) {
+		t.Errorf("Transactions not sorted by date:\n%s", out)
+	}
+	if !strings.Contains(out, `"amount": 100,`) {
+		t.Errorf("Expected 1e2 formatted as 100:\n%s", out)
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"sorted keys", `{"b":1,"a":{"d":2,"c":3}}`, `{"a":{"c":3,"d":2},"b":1}`},
+		{"exponent", `[1e2, 1.5E-7, 2.50]`, `[100,0.00000015,2.5]`},
+		{"negative zero", `[-0, -0.0]`, `[0,0]`},
+		{"timestamp to UTC", `["2024-03-01T09:30:00-05:00"]`, `["2024-03-01T14:30:00Z"]`},
+		{"plain date untouched", `["2024-03-01"]`, `["2024-03-01"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tt.input))
+			decoder.UseNumber()
+			var v any
+			if err := decoder.Decode(&v); err != nil {
+				t.Fatal(err)
+			}
+			normalized, err := normalizeValue(v)
+			if err != nil {
+				t.Fatalf("normalizeValue failed: %v", err)
+			}
+			got, err := json.Marshal(normalized)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("normalizeValue(%s) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteBudgetToFile_Normalize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+	if err := WriteBudgetToFile(buildBudget(t, true), WriteOptions{FilePath: path, Normalize: true}); err != nil {
+		t.Fatalf("WriteBudgetToFile failed: %v", err)
+	}
+
+	var want bytes.Buffer
+	if err := WriteNormalizedBudget(buildBudget(t, false), &want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("File output differs from WriteNormalizedBudget:\n%s\nvs\n%s", got, want.String())
+	}
+}
@@ -0,0 +1,242 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+// Record types in streamed (JSON Lines) output
+const (
+	RecordInstitution = "institution"
+	RecordAccount     = "account"
+	RecordStatement   = "statement"
+	RecordTransaction = "transaction"
+	RecordRecurring   = "recurring"
+)
+
+// StreamRecord is one line of streamed output: a typed wrapper around a single entity.
+type StreamRecord struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// StreamWriter writes a budget as JSON Lines, one entity per line, so transactions
+// can be flushed in chunks as they are produced instead of accumulated in memory.
+//
+// Transactions are written first, as they arrive; institutions, accounts and
+// statements are written by WriteBudget once parsing completes. ReadStream
+// reassembles the budget regardless of record order.
+type StreamWriter struct {
+	w *bufio.Writer
+}
+
+// NewStreamWriter creates a StreamWriter on w.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{w: bufio.NewWriter(w)}
+}
+
+func (s *StreamWriter) writeRecord(recordType string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", recordType, err)
+	}
+	line, err := json.Marshal(StreamRecord{Type: recordType, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", recordType, err)
+	}
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s record: %w", recordType, err)
+	}
+	return nil
+}
+
+// WriteTransactions writes a chunk of transactions and flushes them to the
+// underlying writer, so the chunk can be released by the caller.
+func (s *StreamWriter) WriteTransactions(txns []domain.Transaction) error {
+	for i := range txns {
+		if err := s.writeRecord(RecordTransaction, &txns[i]); err != nil {
+			return fmt.Errorf("transaction %s: %w", txns[i].ID, err)
+		}
+	}
+	return s.Flush()
+}
+
+// WriteBudget writes the rest of the budget: institutions, accounts, statements,
+// recurring charges and any transactions not yet written.
+func (s *StreamWriter) WriteBudget(budget *domain.Budget) error {
+	if budget == nil {
+		return fmt.Errorf("budget cannot be nil")
+	}
+	for _, inst := range budget.GetInstitutions() {
+		if err := s.writeRecord(RecordInstitution, inst); err != nil {
+			return err
+		}
+	}
+	for _, acc := range budget.GetAccounts() {
+		if err := s.writeRecord(RecordAccount, acc); err != nil {
+			return err
+		}
+	}
+	for _, stmt := range budget.GetStatements() {
+		if err := s.writeRecord(RecordStatement, &stmt); err != nil {
+			return err
+		}
+	}
+	for _, r := range budget.GetRecurring() {
+		if err := s.writeRecord(RecordRecurring, r); err != nil {
+			return err
+		}
+	}
+	return s.WriteTransactions(budget.GetTransactions())
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (s *StreamWriter) Flush() error {
+	if err := s.w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush stream: %w", err)
+	}
+	return nil
+}
+
+// StreamFile is a StreamWriter whose output replaces a file atomically on Commit.
+// Records are written to a temp file while parsing, so the destination is never
+// left half-written. With an empty path, records go straight to stdout.
+type StreamFile struct {
+	*StreamWriter
+	file    *os.File // nil for stdout
+	path    string
+	tmpPath string
+}
+
+// CreateStreamFile opens streamed output for path (empty = stdout).
+func CreateStreamFile(path string) (*StreamFile, error) {
+	if path == "" {
+		return &StreamFile{StreamWriter: NewStreamWriter(os.Stdout)}, nil
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file %s: %w", tmpPath, err)
+	}
+	return &StreamFile{StreamWriter: NewStreamWriter(f), file: f, path: path, tmpPath: tmpPath}, nil
+}
+
+// Commit flushes remaining records and moves the temp file into place.
+func (s *StreamFile) Commit() error {
+	if err := s.Flush(); err != nil {
+		s.Abort()
+		return err
+	}
+	if s.file == nil {
+		return nil
+	}
+
+	if err := s.file.Close(); err != nil {
+		s.file = nil
+		os.Remove(s.tmpPath)
+		return fmt.Errorf("failed to close temp file before rename: %w", err)
+	}
+	s.file = nil
+
+	if err := os.Rename(s.tmpPath, s.path); err != nil {
+		os.Remove(s.tmpPath)
+		return fmt.Errorf("failed to rename temp file to %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Abort discards the temp file. Records already written to stdout cannot be
+// retracted. Safe to call after Commit (no-op).
+func (s *StreamFile) Abort() {
+	if s.file == nil {
+		return
+	}
+	s.file.Close()
+	s.file = nil
+	if err := os.Remove(s.tmpPath); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to clean up temp file %s: %v\n", s.tmpPath, err)
+	}
+}
+
+// ReadStream reassembles a budget from streamed output.
+func ReadStream(r io.Reader) (*domain.Budget, error) {
+	var (
+		institutions []domain.Institution
+		accounts     []domain.Account
+		statements   []domain.Statement
+		transactions []domain.Transaction
+		recurring    []domain.RecurringCharge
+	)
+
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var record StreamRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+
+		var err error
+		switch record.Type {
+		case RecordInstitution:
+			var inst domain.Institution
+			err = json.Unmarshal(record.Data, &inst)
+			institutions = append(institutions, inst)
+		case RecordAccount:
+			var acc domain.Account
+			err = json.Unmarshal(record.Data, &acc)
+			accounts = append(accounts, acc)
+		case RecordStatement:
+			var stmt domain.Statement
+			err = json.Unmarshal(record.Data, &stmt)
+			statements = append(statements, stmt)
+		case RecordTransaction:
+			var txn domain.Transaction
+			err = json.Unmarshal(record.Data, &txn)
+			transactions = append(transactions, txn)
+		case RecordRecurring:
+			var rc domain.RecurringCharge
+			err = json.Unmarshal(record.Data, &rc)
+			recurring = append(recurring, rc)
+		default:
+			err = fmt.Errorf("unknown record type %q", record.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+	}
+
+	// Entities reference earlier sections, so add them in dependency order
+	budget := domain.NewBudget()
+	for _, inst := range institutions {
+		if err := budget.AddInstitution(inst); err != nil {
+			return nil, fmt.Errorf("failed to add institution %s: %w", inst.ID, err)
+		}
+	}
+	for _, acc := range accounts {
+		if err := budget.AddAccount(acc); err != nil {
+			return nil, fmt.Errorf("failed to add account %s: %w", acc.ID, err)
+		}
+	}
+	for _, stmt := range statements {
+		if err := budget.AddStatement(stmt); err != nil {
+			return nil, fmt.Errorf("failed to add statement %s: %w", stmt.ID, err)
+		}
+	}
+	for _, txn := range transactions {
+		if err := budget.AddTransaction(txn); err != nil {
+			return nil, fmt.Errorf("failed to add transaction %s: %w", txn.ID, err)
+		}
+	}
+	if len(recurring) > 0 {
+		budget.SetRecurring(recurring)
+	}
+	return budget, nil
+}
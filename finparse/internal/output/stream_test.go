@@ -0,0 +1,139 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+func newStreamTestBudget(t *testing.T, txnCount int) *domain.Budget {
+	t.Helper()
+	budget := domain.NewBudget()
+	if err := budget.AddInstitution(domain.Institution{ID: "bank", Name: "Bank"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := budget.AddAccount(domain.Account{ID: "acc", InstitutionID: "bank", Name: "Card", Type: domain.AccountTypeCredit}); err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := domain.NewStatement("stmt", "acc", "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < txnCount; i++ {
+		txn, err := domain.NewTransaction(strings.Repeat("t", i+1), "2024-01-15", "Purchase", -float64(i+1), domain.CategoryOther)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.AddStatementID("stmt"); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.AddTransactionID(txn.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := budget.AddTransaction(*txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := budget.AddStatement(*stmt); err != nil {
+		t.Fatal(err)
+	}
+	return budget
+}
+
+func TestStreamWriter_RoundTrip(t *testing.T) {
+	budget := newStreamTestBudget(t, 5)
+	want, err := json.Marshal(budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stream the first transactions as a chunk, leave the rest for WriteBudget
+	var buf bytes.Buffer
+	sw := NewStreamWriter(&buf)
+	all := budget.DrainTransactions()
+	if err := sw.WriteTransactions(all[:3]); err != nil {
+		t.Fatalf("WriteTransactions failed: %v", err)
+	}
+	for _, txn := range all[3:] {
+		if err := budget.AddTransaction(txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sw.WriteBudget(budget); err != nil {
+		t.Fatalf("WriteBudget failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 8 {
+		t.Errorf("Expected 8 records (1 institution, 1 account, 1 statement, 5 transactions), got %d", len(lines))
+	}
+
+	read, err := ReadStream(&buf)
+	if err != nil {
+		t.Fatalf("ReadStream failed: %v", err)
+	}
+	got, err := json.Marshal(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Round trip mismatch:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestReadStream_UnknownRecordType(t *testing.T) {
+	_, err := ReadStream(strings.NewReader(`{"type":"bogus","data":{}}` + "\n"))
+	if err == nil || !strings.Contains(err.Error(), `unknown record type "bogus"`) {
+		t.Errorf("Expected unknown record type error, got: %v", err)
+	}
+}
+
+func TestStreamFile_CommitAndAbort(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("commit replaces destination", func(t *testing.T) {
+		path := filepath.Join(dir, "out.jsonl")
+		sf, err := CreateStreamFile(path)
+		if err != nil {
+			t.Fatalf("CreateStreamFile failed: %v", err)
+		}
+		if err := sf.WriteBudget(newStreamTestBudget(t, 2)); err != nil {
+			t.Fatalf("WriteBudget failed: %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Destination should not exist before Commit, stat err: %v", err)
+		}
+		if err := sf.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		sf.Abort() // no-op after commit
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected destination after Commit: %v", err)
+		}
+		if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+			t.Errorf("Expected no temp file after Commit, stat err: %v", err)
+		}
+	})
+
+	t.Run("abort leaves no output", func(t *testing.T) {
+		path := filepath.Join(dir, "aborted.jsonl")
+		sf, err := CreateStreamFile(path)
+		if err != nil {
+			t.Fatalf("CreateStreamFile failed: %v", err)
+		}
+		if err := sf.WriteTransactions(newStreamTestBudget(t, 2).GetTransactions()); err != nil {
+			t.Fatalf("WriteTransactions failed: %v", err)
+		}
+		sf.Abort()
+		for _, p := range []string{path, path + ".tmp"} {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be absent after Abort, stat err: %v", p, err)
+			}
+		}
+	})
+}
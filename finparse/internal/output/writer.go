@@ -15,6 +15,8 @@ import (
 type WriteOptions struct {
 	MergeMode bool   // If true, load existing file and merge
 	FilePath  string // Output path (empty = stdout)
+	Normalize bool   // If true, write the canonical form (see WriteNormalizedBudget)
+	Anonymize bool   // If true, replace identifying values with salted tokens (see anonymizeBudget)
 }
 
 // Validate checks that WriteOptions are valid
@@ -22,6 +24,10 @@ func (o WriteOptions) Validate() error {
 	if o.MergeMode && o.FilePath == "" {
 		return fmt.Errorf("merge mode requires a file path (cannot merge with stdout)")
 	}
+	if o.MergeMode && o.Anonymize {
+		// The existing file's tokens were made with another run's salt and won't match
+		return fmt.Errorf("anonymized output cannot be merged")
+	}
 	return nil
 }
 
@@ -41,7 +47,10 @@ func WriteBudget(budget *domain.Budget, w io.Writer) error {
 	return nil
 }
 
-// WriteBudgetToFile writes Budget to file or stdout based on options
+// WriteBudgetToFile writes Budget to file or stdout based on options. The budget (or,
+// in merge mode, the merged one) is sorted first (see domain.Budget.Sort), so the same
+// data always produces the same file and output diffs only show real changes. With
+// opts.Anonymize, an anonymized copy is written instead and budget is left unchanged.
 func WriteBudgetToFile(budget *domain.Budget, opts WriteOptions) (err error) {
 	if budget == nil {
 		return fmt.Errorf("budget cannot be nil")
@@ -71,12 +80,31 @@ func WriteBudgetToFile(budget *domain.Budget, opts WriteOptions) (err error) {
 		}
 	}
 
+	// Anonymization is lossy: tokens come from a random salt that is discarded after the
+	// write, so the same value maps to the same token within this file only
+	if opts.Anonymize {
+		salt, err := newAnonymizeSalt()
+		if err != nil {
+			return err
+		}
+		if budget, err = anonymizeBudget(budget, salt); err != nil {
+			return fmt.Errorf("failed to anonymize budget: %w", err)
+		}
+	}
+
+	budget.Sort()
+
+	encode := WriteBudget
+	if opts.Normalize {
+		encode = WriteNormalizedBudget
+	}
+
 	// Write to stdout if no file path specified
 	if opts.FilePath == "" {
 		// For stdout, validate by encoding to buffer first to catch errors
 		// before writing any partial output
 		var buf bytes.Buffer
-		if err := WriteBudget(budget, &buf); err != nil {
+		if err := encode(budget, &buf); err != nil {
 			return fmt.Errorf("failed to encode budget (no output written): %w", err)
 		}
 		// Only write to stdout after successful encoding
@@ -98,7 +126,7 @@ func WriteBudgetToFile(budget *domain.Budget, opts WriteOptions) (err error) {
 	}
 
 	// Write budget to temp file
-	writeErr := WriteBudget(budget, f)
+	writeErr := encode(budget, f)
 	closeErr := f.Close()
 
 	// Handle write error first
@@ -208,5 +236,17 @@ func mergeBudgets(target, source *domain.Budget) error {
 		}
 	}
 
+	// Recurring charges are derived data: a fresh detection supersedes the existing one
+	if recurring := source.GetRecurring(); len(recurring) > 0 {
+		target.SetRecurring(recurring)
+	}
+
+	// The summary covers the whole merged file, not just this run's transactions
+	if source.GetSummary() != nil {
+		if err := target.SummarizeByMonth(); err != nil {
+			return fmt.Errorf("failed to summarize merged budget: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -114,6 +114,77 @@ func TestWriteBudgetToFile_FreshMode(t *testing.T) {
 	}
 }
 
+// TestWriteBudgetToFile_SortsEntities writes the same data added in two different
+// orders, which must produce byte-identical files in sorted order
+func TestWriteBudgetToFile_SortsEntities(t *testing.T) {
+	tmpDir := t.TempDir()
+	build := func(reverse bool) *domain.Budget {
+		t.Helper()
+		budget := domain.NewBudget()
+		insts := []string{"bank-b:Bank B", "bank-a:Bank A"}
+		txns := []struct{ id, date, desc string }{
+			{"t1", "2025-10-02", "Coffee"},
+			{"t2", "2025-10-01", "Rent"},
+			{"t3", "2025-10-01", "Groceries"},
+		}
+		if reverse {
+			insts[0], insts[1] = insts[1], insts[0]
+			txns[0], txns[2] = txns[2], txns[0]
+		}
+		for _, entry := range insts {
+			id, name, _ := strings.Cut(entry, ":")
+			inst, err := domain.NewInstitution(id, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := budget.AddInstitution(*inst); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, tc := range txns {
+			txn, err := domain.NewTransaction(tc.id, tc.date, tc.desc, -10, domain.CategoryOther)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := budget.AddTransaction(*txn); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return budget
+	}
+
+	var outputs [2][]byte
+	for i := range outputs {
+		path := filepath.Join(tmpDir, "budget.json")
+		if err := WriteBudgetToFile(build(i == 1), WriteOptions{FilePath: path}); err != nil {
+			t.Fatalf("WriteBudgetToFile failed: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[i] = data
+	}
+	if !bytes.Equal(outputs[0], outputs[1]) {
+		t.Errorf("Same budget in a different order wrote different output:\n%s\n---\n%s", outputs[0], outputs[1])
+	}
+
+	written, err := LoadBudget(filepath.Join(tmpDir, "budget.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, inst := range written.GetInstitutions() {
+		ids = append(ids, inst.ID)
+	}
+	for _, txn := range written.GetTransactions() {
+		ids = append(ids, txn.ID)
+	}
+	if got, want := strings.Join(ids, ","), "bank-a,bank-b,t3,t2,t1"; got != want {
+		t.Errorf("Written order = %s, want %s", got, want)
+	}
+}
+
 func TestWriteBudgetToFile_MergeMode(t *testing.T) {
 	// Create temporary directory
 	tmpDir, err := os.MkdirTemp("", "finparse-test-*")
@@ -355,6 +426,70 @@ func TestMergeBudgets(t *testing.T) {
 	}
 }
 
+func TestMergeBudgets_RecurringReplacedBySource(t *testing.T) {
+	target := domain.NewBudget()
+	target.SetRecurring([]domain.RecurringCharge{{Merchant: "Old Gym", Amount: -30, Cadence: domain.RecurringCadenceMonthly}})
+
+	// Source without detection keeps the existing section
+	if err := mergeBudgets(target, domain.NewBudget()); err != nil {
+		t.Fatalf("mergeBudgets failed: %v", err)
+	}
+	if recurring := target.GetRecurring(); len(recurring) != 1 || recurring[0].Merchant != "Old Gym" {
+		t.Errorf("expected existing recurring section to be kept, got %+v", recurring)
+	}
+
+	// Source with detection supersedes it
+	source := domain.NewBudget()
+	source.SetRecurring([]domain.RecurringCharge{{Merchant: "New Gym", Amount: -35, Cadence: domain.RecurringCadenceMonthly}})
+	if err := mergeBudgets(target, source); err != nil {
+		t.Fatalf("mergeBudgets failed: %v", err)
+	}
+	if recurring := target.GetRecurring(); len(recurring) != 1 || recurring[0].Merchant != "New Gym" {
+		t.Errorf("expected recurring section from source, got %+v", recurring)
+	}
+}
+
+func TestMergeBudgets_SummaryRecomputed(t *testing.T) {
+	addTxn := func(b *domain.Budget, id, date string, amount float64) {
+		t.Helper()
+		txn, err := domain.NewTransaction(id, date, "Grocer", amount, domain.CategoryGroceries)
+		if err != nil {
+			t.Fatalf("NewTransaction failed: %v", err)
+		}
+		if err := b.AddTransaction(*txn); err != nil {
+			t.Fatalf("AddTransaction failed: %v", err)
+		}
+	}
+
+	target := domain.NewBudget()
+	addTxn(target, "txn-1", "2024-01-05", -20)
+
+	// Source without a summary doesn't add one
+	if err := mergeBudgets(target, domain.NewBudget()); err != nil {
+		t.Fatalf("mergeBudgets failed: %v", err)
+	}
+	if target.GetSummary() != nil {
+		t.Errorf("expected no summary, got %+v", target.GetSummary())
+	}
+
+	// Source with a summary: totals include the existing file's transactions
+	source := domain.NewBudget()
+	addTxn(source, "txn-2", "2024-01-19", -30)
+	if err := source.SummarizeByMonth(); err != nil {
+		t.Fatalf("SummarizeByMonth failed: %v", err)
+	}
+	if err := mergeBudgets(target, source); err != nil {
+		t.Fatalf("mergeBudgets failed: %v", err)
+	}
+	summary := target.GetSummary()
+	if summary == nil {
+		t.Fatal("expected merged budget to have a summary")
+	}
+	if got := summary.ByMonth["2024-01"][domain.CategoryGroceries]; got != -50 {
+		t.Errorf("merged groceries total = %v, want -50", got)
+	}
+}
+
 func TestMergeBudgets_DuplicateStatement(t *testing.T) {
 	// Create target budget with a statement
 	target := domain.NewBudget()
@@ -613,6 +748,16 @@ func TestWriteOptions_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "merge mode requires a file path",
 		},
+		{
+			name: "invalid: anonymized merge",
+			opts: WriteOptions{
+				MergeMode: true,
+				FilePath:  "/path/to/file.json",
+				Anonymize: true,
+			},
+			wantErr: true,
+			errMsg:  "anonymized output cannot be merged",
+		},
 	}
 
 	for _, tt := range tests {
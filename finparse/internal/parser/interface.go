@@ -21,6 +21,40 @@ type Parser interface {
 	Parse(ctx context.Context, r io.Reader, meta *Metadata) (*RawStatement, error)
 }
 
+// MultiStatementParser is implemented by parsers whose files can hold several accounts
+// (e.g. a combined CSV export with an account column).
+type MultiStatementParser interface {
+	Parser
+
+	// ParseAll extracts one statement per account in the file, in the order the
+	// accounts first appear
+	ParseAll(ctx context.Context, r io.Reader, meta *Metadata) ([]*RawStatement, error)
+}
+
+// ContentSniffer is implemented by parsers that can recognize their format from the
+// file's content alone. FindParser falls back to Sniff for files no parser accepts
+// by extension, such as an OFX download saved as "download" or "statement.txt".
+type ContentSniffer interface {
+	Parser
+
+	// Sniff reports whether header, the first bytes of a file, is in this parser's
+	// format. It must be stricter than CanParse, since nothing else vouches for the file.
+	Sniff(header []byte) bool
+}
+
+// ParseStatements parses r with p, returning one statement per account. Parsers that
+// implement MultiStatementParser may return several; others return exactly one.
+func ParseStatements(ctx context.Context, p Parser, r io.Reader, meta *Metadata) ([]*RawStatement, error) {
+	if mp, ok := p.(MultiStatementParser); ok {
+		return mp.ParseAll(ctx, r, meta)
+	}
+	stmt, err := p.Parse(ctx, r, meta)
+	if err != nil {
+		return nil, err
+	}
+	return []*RawStatement{stmt}, nil
+}
+
 // TODO(Phase 2): Consider making Transactions slice immutable with controlled access methods
 // if normalization layer needs to prevent external modification of parser results.
 // Current design exposes mutable []RawTransaction which allows external modification.
@@ -32,9 +66,15 @@ type Parser interface {
 // RawStatement represents parsed data before normalization
 // TODO(#1307): Consider hiding Transactions behind accessor methods to prevent external mutation
 type RawStatement struct {
-	Account      RawAccount
-	Period       Period
-	Transactions []RawTransaction
+	Account      RawAccount       `json:"account"`
+	Period       Period           `json:"period"`
+	Transactions []RawTransaction `json:"transactions"`
+	// LedgerBalance is the closing balance reported by the institution (OFX <LEDGERBAL>).
+	// Nil when the source format does not carry one.
+	LedgerBalance *float64 `json:"ledgerBalance,omitempty"`
+	// SourceFile is the path the statement was parsed from. When set, TransformStatement
+	// records it (with each transaction's line) on the domain transactions.
+	SourceFile string `json:"sourceFile,omitempty"`
 }
 
 // RawAccount represents account information from the file
@@ -137,6 +177,7 @@ type RawTransaction struct {
 	amount      float64 // Positive=income, Negative=expense
 	txnType     string  // "DEBIT", "CREDIT", etc.
 	memo        string  // Additional context
+	line        int     // 1-based line the row starts on; 0 for formats without rows (OFX)
 }
 
 // ID returns the transaction ID
@@ -160,6 +201,10 @@ func (r *RawTransaction) Type() string { return r.txnType }
 // Memo returns the transaction memo
 func (r *RawTransaction) Memo() string { return r.memo }
 
+// Line returns the 1-based line the transaction's row starts on in the source file,
+// or 0 if the parser doesn't track lines
+func (r *RawTransaction) Line() int { return r.line }
+
 // SetType sets the optional transaction type.
 // Example values from OFX/QFX: "DEBIT", "CREDIT", "ATM", "CHECK", "TRANSFER", "FEE", "POS", "PAYMENT".
 // CSV formats may use different values depending on the institution.
@@ -177,6 +222,11 @@ func (r *RawTransaction) SetMemo(memo string) {
 	r.memo = memo
 }
 
+// SetLine sets the line the transaction's row starts on in the source file
+func (r *RawTransaction) SetLine(line int) {
+	r.line = line
+}
+
 // NewRawTransaction creates a validated raw transaction
 func NewRawTransaction(id string, date, postedDate time.Time, description string, amount float64) (*RawTransaction, error) {
 	if id == "" {
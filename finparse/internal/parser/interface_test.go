@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -471,3 +472,36 @@ func TestRawTransaction_SetTypeAndMemo(t *testing.T) {
 		t.Errorf("Expected Memo 'Monthly subscription' after SetMemo, got: %s", txn.Memo())
 	}
 }
+
+func TestRawStatement_MarshalJSON(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+	account, err := NewRawAccount("AMEX", "American Express", "2011", "credit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	period, err := NewPeriod(start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := NewRawTransaction("TXN001", start, time.Time{}, "Coffee", -4.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn.SetType("DEBIT")
+	balance := -4.5
+
+	stmt := &RawStatement{Account: *account, Period: *period, Transactions: []RawTransaction{*txn}, LedgerBalance: &balance}
+	data, err := json.Marshal(stmt)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	want := `{"account":{"institutionId":"AMEX","institutionName":"American Express","accountId":"2011","accountType":"credit"},` +
+		`"period":{"start":"2025-01-01T00:00:00Z","end":"2025-01-31T23:59:59Z"},` +
+		`"transactions":[{"id":"TXN001","date":"2025-01-01T00:00:00Z","postedDate":"2025-01-01T00:00:00Z","description":"Coffee","amount":-4.5,"type":"DEBIT"}],` +
+		`"ledgerBalance":-4.5}`
+	if string(data) != want {
+		t.Errorf("Unexpected JSON:\n got: %s\nwant: %s", data, want)
+	}
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSON encoding of raw parser output, used to dump exactly what a parser
+// extracted (finparse -dump-ast). Times keep full precision (RFC 3339) rather
+// than the YYYY-MM-DD dates of the normalized domain types, so timezone and
+// time-of-day issues in a parser stay visible.
+
+// MarshalJSON implements json.Marshaler for RawAccount
+func (r RawAccount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		InstitutionID   string `json:"institutionId"`
+		InstitutionName string `json:"institutionName"`
+		AccountID       string `json:"accountId"`
+		AccountType     string `json:"accountType"`
+	}{r.institutionID, r.institutionName, r.accountID, r.accountType})
+}
+
+// MarshalJSON implements json.Marshaler for Period
+func (p Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}{p.start.Format(time.RFC3339), p.end.Format(time.RFC3339)})
+}
+
+// MarshalJSON implements json.Marshaler for RawTransaction
+func (r RawTransaction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID          string  `json:"id"`
+		Date        string  `json:"date"`
+		PostedDate  string  `json:"postedDate"`
+		Description string  `json:"description"`
+		Amount      float64 `json:"amount"`
+		Type        string  `json:"type,omitempty"`
+		Memo        string  `json:"memo,omitempty"`
+		Line        int     `json:"line,omitempty"`
+	}{r.id, r.date.Format(time.RFC3339), r.postedDate.Format(time.RFC3339), r.description, r.amount, r.txnType, r.memo, r.line})
+}
@@ -2,6 +2,8 @@
 package csv
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/csv"
 	"fmt"
@@ -52,10 +54,32 @@ func (p *Parser) CanParse(path string, header []byte) bool {
 	if ext != ".csv" {
 		return false
 	}
+	return isSummaryLine(header)
+}
+
+// Sniff checks whether header opens with a PNC summary line regardless of the file's
+// extension
+func (p *Parser) Sniff(header []byte) bool {
+	return isSummaryLine(header)
+}
 
-	// Parse header to validate PNC CSV format
-	// Expected: 5 fields with YYYY/MM/DD dates in fields 1 and 2
-	r := csv.NewReader(strings.NewReader(string(header)))
+// utf8BOM is the byte order mark some banks write at the start of CSV exports
+var utf8BOM = []byte("\ufeff")
+
+// skipBOM returns a reader over r without a leading UTF-8 byte order mark. Left in
+// place, the BOM would become part of the first field of the summary line.
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	return br
+}
+
+// isSummaryLine reports whether the first record of header is a PNC summary line.
+// Expected: 5 fields with YYYY/MM/DD dates in fields 1 and 2
+func isSummaryLine(header []byte) bool {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(header, utf8BOM)))
 	r.LazyQuotes = true
 	r.TrimLeadingSpace = true
 	r.FieldsPerRecord = -1
@@ -82,8 +106,28 @@ func (p *Parser) CanParse(path string, header []byte) bool {
 	return true
 }
 
-// Parse extracts raw data from PNC CSV file
+// accountColumn is the index of the optional account-number column that follows the
+// six transaction fields in combined exports holding several accounts in one file.
+// Rows without it belong to the account on the summary line.
+const accountColumn = 6
+
+// Parse extracts raw data from a single-account PNC CSV file. Combined files holding
+// several accounts are rejected; use ParseAll for those.
 func (p *Parser) Parse(ctx context.Context, r io.Reader, meta *parser.Metadata) (*parser.RawStatement, error) {
+	statements, err := p.ParseAll(ctx, r, meta)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) != 1 {
+		return nil, fmt.Errorf("CSV file holds %d accounts%s; parse it with ParseAll", len(statements), getFileInfo(meta))
+	}
+	return statements[0], nil
+}
+
+// ParseAll extracts one statement per account from a PNC CSV file. Transaction rows
+// are partitioned by the optional account column (see accountColumn); every
+// statement shares the summary line's period.
+func (p *Parser) ParseAll(ctx context.Context, r io.Reader, meta *parser.Metadata) ([]*parser.RawStatement, error) {
 	// Check if context was cancelled before parsing
 	select {
 	case <-ctx.Done():
@@ -91,15 +135,26 @@ func (p *Parser) Parse(ctx context.Context, r io.Reader, meta *parser.Metadata)
 	default:
 	}
 
-	csvReader := csv.NewReader(r)
+	csvReader := csv.NewReader(skipBOM(r))
 	csvReader.LazyQuotes = true
 	csvReader.TrimLeadingSpace = true
 	csvReader.FieldsPerRecord = -1
 
-	// Read all records
-	records, err := csvReader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV content%s: %w", getFileInfo(meta), err)
+	// Read all records, remembering the line each starts on. Quoted fields may span
+	// several lines, so record indexes don't map to line numbers.
+	var records [][]string
+	var lines []int
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV content%s: %w", getFileInfo(meta), err)
+		}
+		line, _ := csvReader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
 	}
 
 	if len(records) < 1 {
@@ -112,17 +167,36 @@ func (p *Parser) Parse(ctx context.Context, r io.Reader, meta *parser.Metadata)
 		return nil, fmt.Errorf("failed to parse summary line%s: %w", getFileInfo(meta), err)
 	}
 
-	// Parse transactions (remaining rows)
-	transactions, err := p.parseTransactions(records[1:], meta)
+	// Parse transactions (remaining rows), grouped by account
+	accountIDs, byAccount, err := p.parseTransactions(records[1:], lines[1:], account.AccountID(), meta)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse transactions%s: %w", getFileInfo(meta), err)
 	}
 
-	return &parser.RawStatement{
-		Account:      *account,
-		Period:       *period,
-		Transactions: transactions,
-	}, nil
+	// A file without transactions still yields the summary line's (empty) statement
+	if len(accountIDs) == 0 {
+		accountIDs = []string{account.AccountID()}
+	}
+
+	statements := make([]*parser.RawStatement, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		stmtAccount := account
+		if accountID != account.AccountID() {
+			if stmtAccount, err = p.newAccount(accountID, meta); err != nil {
+				return nil, fmt.Errorf("failed to create account %q%s: %w", accountID, getFileInfo(meta), err)
+			}
+		}
+		transactions := byAccount[accountID]
+		if transactions == nil {
+			transactions = []parser.RawTransaction{}
+		}
+		statements = append(statements, &parser.RawStatement{
+			Account:      *stmtAccount,
+			Period:       *period,
+			Transactions: transactions,
+		})
+	}
+	return statements, nil
 }
 
 // parseSummaryLine parses the first row containing account and period information
@@ -151,14 +225,9 @@ func (p *Parser) parseSummaryLine(record []string, meta *parser.Metadata) (*pars
 	}
 
 	// Create account
-	account, err := parser.NewRawAccount("PNC", "", accountID, "checking")
+	account, err := p.newAccount(accountID, meta)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create raw account: %w", err)
-	}
-
-	// Set institution name from metadata if available
-	if meta != nil && meta.Institution() != "" {
-		account.SetInstitutionName(meta.Institution())
+		return nil, nil, err
 	}
 
 	// Create period
@@ -170,9 +239,27 @@ func (p *Parser) parseSummaryLine(record []string, meta *parser.Metadata) (*pars
 	return account, period, nil
 }
 
-// parseTransactions converts CSV transaction rows to RawTransactions
-func (p *Parser) parseTransactions(records [][]string, meta *parser.Metadata) ([]parser.RawTransaction, error) {
-	transactions := make([]parser.RawTransaction, 0, len(records))
+// newAccount creates a PNC checking account, named from metadata if available
+func (p *Parser) newAccount(accountID string, meta *parser.Metadata) (*parser.RawAccount, error) {
+	account, err := parser.NewRawAccount("PNC", "", accountID, "checking")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raw account: %w", err)
+	}
+
+	// Set institution name from metadata if available
+	if meta != nil && meta.Institution() != "" {
+		account.SetInstitutionName(meta.Institution())
+	}
+	return account, nil
+}
+
+// parseTransactions converts CSV transaction rows to RawTransactions grouped by
+// account. Rows without an account column belong to defaultAccountID. lines holds the
+// line each record starts on, for error messages. Returns the account IDs in order of
+// first appearance.
+func (p *Parser) parseTransactions(records [][]string, lines []int, defaultAccountID string, meta *parser.Metadata) ([]string, map[string][]parser.RawTransaction, error) {
+	var accountIDs []string
+	byAccount := make(map[string][]parser.RawTransaction)
 
 	for i, record := range records {
 		// Skip empty rows
@@ -180,17 +267,30 @@ func (p *Parser) parseTransactions(records [][]string, meta *parser.Metadata) ([
 			continue
 		}
 
+		accountID := defaultAccountID
+		if len(record) == accountColumn+1 {
+			accountID = strings.TrimSpace(record[accountColumn])
+			if accountID == "" {
+				return nil, nil, fmt.Errorf("failed to parse transaction at row %d: account number cannot be empty", lines[i])
+			}
+			record = record[:accountColumn]
+		}
+
 		rawTxn, err := p.parseTransactionRow(record, meta)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse transaction at row %d: %w", i+2, err)
+			return nil, nil, fmt.Errorf("failed to parse transaction at row %d: %w", lines[i], err)
+		}
+		rawTxn.SetLine(lines[i])
+		if _, seen := byAccount[accountID]; !seen {
+			accountIDs = append(accountIDs, accountID)
 		}
-		transactions = append(transactions, *rawTxn)
+		byAccount[accountID] = append(byAccount[accountID], *rawTxn)
 	}
 
-	return transactions, nil
+	return accountIDs, byAccount, nil
 }
 
-// parseTransactionRow parses a single transaction row
+// parseTransactionRow parses a single transaction row (without the account column)
 // Format: Date, Amount, Description, Memo, Reference, Type
 func (p *Parser) parseTransactionRow(record []string, meta *parser.Metadata) (*parser.RawTransaction, error) {
 	if len(record) != 6 {
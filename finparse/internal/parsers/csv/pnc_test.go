@@ -1,7 +1,10 @@
 package csv
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -172,6 +175,28 @@ func TestCanParse_InvalidFormat(t *testing.T) {
 	}
 }
 
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"PNC summary line", "12345,2024/01/01,2024/01/31,1000.00,2000.00\n2024/01/05,10.00,Coffee,,REF1,DEBIT", true},
+		{"Generic CSV header", "Date,Description,Amount\n2024-01-01,Test,100.00", false},
+		{"OFX", "OFXHEADER:100\n", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			if got := p.Sniff([]byte(tt.header)); got != tt.expected {
+				t.Errorf("Sniff() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParse_SyntheticStatement(t *testing.T) {
 	// Create synthetic PNC CSV content
 	// Format: AccountNumber, StartDate, EndDate, BeginningBalance, EndingBalance
@@ -526,3 +551,160 @@ func TestParse_WithMetadata(t *testing.T) {
 		t.Errorf("InstitutionName = %q, want %q", stmt.Account.InstitutionName(), "PNC Bank")
 	}
 }
+
+func TestParseAll_MultiAccountFixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "multi-account.csv"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	p := NewParser()
+	meta, err := parser.NewMetadata("/test/pnc/multi-account.csv", time.Now())
+	if err != nil {
+		t.Fatalf("failed to create metadata: %v", err)
+	}
+	meta.SetInstitution("PNC Bank")
+
+	stmts, err := p.ParseAll(context.Background(), f, meta)
+	if err != nil {
+		t.Fatalf("ParseAll() error = %v", err)
+	}
+
+	want := []struct {
+		accountID    string
+		transactions int
+	}{
+		{"1111111111", 3},
+		{"2222222222", 2},
+		{"3333333333", 1},
+	}
+	if len(stmts) != len(want) {
+		t.Fatalf("got %d statements, want %d", len(stmts), len(want))
+	}
+	expectedStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, w := range want {
+		stmt := stmts[i]
+		if stmt.Account.AccountID() != w.accountID {
+			t.Errorf("statements[%d].AccountID = %q, want %q", i, stmt.Account.AccountID(), w.accountID)
+		}
+		if len(stmt.Transactions) != w.transactions {
+			t.Errorf("account %s: got %d transactions, want %d", w.accountID, len(stmt.Transactions), w.transactions)
+		}
+		if stmt.Account.InstitutionName() != "PNC Bank" {
+			t.Errorf("account %s: InstitutionName = %q, want %q", w.accountID, stmt.Account.InstitutionName(), "PNC Bank")
+		}
+		if !stmt.Period.Start().Equal(expectedStart) {
+			t.Errorf("account %s: Period.Start = %v, want %v", w.accountID, stmt.Period.Start(), expectedStart)
+		}
+	}
+
+	// The transfer appears once on each side with opposite signs
+	if got := stmts[0].Transactions[2].Amount(); got != -300.00 {
+		t.Errorf("checking transfer amount = %v, want -300.00", got)
+	}
+	if got := stmts[1].Transactions[0].Amount(); got != 300.00 {
+		t.Errorf("savings transfer amount = %v, want 300.00", got)
+	}
+}
+
+func TestParse_RejectsMultiAccountFile(t *testing.T) {
+	csvContent := `1111111111,2024/01/01,2024/01/31,0.00,0.00
+2024/01/03,12.00,Bakery,Bread,REF101,DEBIT,1111111111
+2024/01/05,300.00,Transfer,From checking,REF201,CREDIT,2222222222`
+
+	_, err := NewParser().Parse(context.Background(), strings.NewReader(csvContent), nil)
+	if err == nil || !strings.Contains(err.Error(), "holds 2 accounts") {
+		t.Errorf("Parse() error = %v, want multi-account error", err)
+	}
+}
+
+func TestParseAll_EmptyAccountColumn(t *testing.T) {
+	csvContent := `1111111111,2024/01/01,2024/01/31,0.00,0.00
+2024/01/03,12.00,Bakery,Bread,REF101,DEBIT, `
+
+	_, err := NewParser().ParseAll(context.Background(), strings.NewReader(csvContent), nil)
+	if err == nil || !strings.Contains(err.Error(), "account number cannot be empty") {
+		t.Errorf("ParseAll() error = %v, want empty account error", err)
+	}
+}
+
+func TestParse_BOMFixture(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "bom.csv"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("\ufeff")) {
+		t.Fatal("fixture should start with a UTF-8 BOM")
+	}
+
+	p := NewParser()
+	if !p.CanParse("bom.csv", data) {
+		t.Error("CanParse() = false for a summary line behind a BOM")
+	}
+	if !p.Sniff(data) {
+		t.Error("Sniff() = false for a summary line behind a BOM")
+	}
+
+	stmt, err := p.Parse(context.Background(), bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if stmt.Account.AccountID() != "4444444444" {
+		t.Errorf("AccountID = %q, want %q", stmt.Account.AccountID(), "4444444444")
+	}
+	if len(stmt.Transactions) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(stmt.Transactions))
+	}
+	if got := stmt.Transactions[0].Amount(); got != -20.00 {
+		t.Errorf("Amount = %v, want -20.00", got)
+	}
+}
+
+func TestParse_MultilineFieldsFixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "multiline-memo.csv"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	stmt, err := NewParser().Parse(context.Background(), f, nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(stmt.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(stmt.Transactions))
+	}
+
+	txn := stmt.Transactions[0]
+	if want := "ACH DEPOSIT\nEMPLOYER INC PAYROLL"; txn.Description() != want {
+		t.Errorf("Description = %q, want %q", txn.Description(), want)
+	}
+	if want := "Pay period 02/15-02/29\nDirect deposit"; txn.Memo() != want {
+		t.Errorf("Memo = %q, want %q", txn.Memo(), want)
+	}
+	if txn.Amount() != 1000.00 {
+		t.Errorf("Amount = %v, want 1000.00", txn.Amount())
+	}
+	if got := stmt.Transactions[1].Description(); got != "Cafe" {
+		t.Errorf("Transactions[1].Description = %q, want %q", got, "Cafe")
+	}
+
+	// Lines are where each row starts, counting the continuation lines before it
+	for i, want := range []int{2, 5} {
+		if got := stmt.Transactions[i].Line(); got != want {
+			t.Errorf("Transactions[%d].Line = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestParse_ErrorLineAfterMultilineField(t *testing.T) {
+	csvContent := "5555555555,2024/03/01,2024/03/31,0.00,0.00\n" +
+		"2024/03/04,10.00,\"Two\nlines\",,REF1,DEBIT\n" +
+		"2024/03/05,abc,Broken,,REF2,DEBIT\n"
+
+	_, err := NewParser().Parse(context.Background(), strings.NewReader(csvContent), nil)
+	if err == nil || !strings.Contains(err.Error(), "row 4") {
+		t.Errorf("Parse() error = %v, want error at row 4", err)
+	}
+}
@@ -52,16 +52,31 @@ func (p *Parser) CanParse(path string, header []byte) bool {
 		return false
 	}
 
-	// Check header for OFX markers
-	headerStr := string(header)
-	headerUpper := strings.ToUpper(headerStr)
+	return hasOFXMarker(strings.ToUpper(string(header)))
+}
 
-	// Look for OFX header markers (both v1 SGML and v2 XML formats)
-	hasOFXMarker := strings.Contains(headerUpper, "OFXHEADER") ||
+// Sniff checks whether header is OFX regardless of the file's extension. The file
+// must open with an OFX v1 header, an XML declaration followed by an OFX v2
+// processing instruction, or the <OFX> element itself.
+func (p *Parser) Sniff(header []byte) bool {
+	headerUpper := strings.ToUpper(string(header))
+	start := strings.TrimLeft(strings.TrimPrefix(headerUpper, "\uFEFF"), " \t\r\n")
+	switch {
+	case strings.HasPrefix(start, "OFXHEADER"), strings.HasPrefix(start, "<OFX>"):
+		return true
+	case strings.HasPrefix(start, "<?XML"):
+		return strings.Contains(start, "<?OFX")
+	default:
+		return false
+	}
+}
+
+// hasOFXMarker reports whether the upper-cased header contains an OFX header marker
+// (both v1 SGML and v2 XML formats)
+func hasOFXMarker(headerUpper string) bool {
+	return strings.Contains(headerUpper, "OFXHEADER") ||
 		strings.Contains(headerUpper, "<?OFX") ||
 		strings.Contains(headerUpper, "<OFX>")
-
-	return hasOFXMarker
 }
 
 // Parse extracts raw data from OFX/QFX file
@@ -162,9 +177,10 @@ func (p *Parser) parseCreditCard(resp *ofxgo.Response, meta *parser.Metadata) (*
 	}
 
 	return &parser.RawStatement{
-		Account:      *account,
-		Period:       *period,
-		Transactions: transactions,
+		Account:       *account,
+		Period:        *period,
+		Transactions:  transactions,
+		LedgerBalance: extractLedgerBalance(ccStmt.BalAmt, ccStmt.DtAsOf, accountID),
 	}, nil
 }
 
@@ -227,9 +243,10 @@ func (p *Parser) parseBank(resp *ofxgo.Response, meta *parser.Metadata) (*parser
 	}
 
 	return &parser.RawStatement{
-		Account:      *account,
-		Period:       *period,
-		Transactions: transactions,
+		Account:       *account,
+		Period:        *period,
+		Transactions:  transactions,
+		LedgerBalance: extractLedgerBalance(bankStmt.BalAmt, bankStmt.DtAsOf, accountID),
 	}, nil
 }
 
@@ -400,6 +417,19 @@ func extractInstitutionID(resp *ofxgo.Response) (string, error) {
 	return institutionID, nil
 }
 
+// extractLedgerBalance returns the <LEDGERBAL> closing balance, or nil when the
+// statement omits it. ofxgo leaves DTASOF zero when the aggregate is absent.
+func extractLedgerBalance(balAmt ofxgo.Amount, dtAsOf ofxgo.Date, accountID string) *float64 {
+	if dtAsOf.IsZero() {
+		return nil
+	}
+	balance, exact := balAmt.Float64()
+	if !exact {
+		fmt.Fprintf(os.Stderr, "Warning: Precision loss in ledger balance for account %s: %v (cannot be exactly represented as float64)\n", accountID, balAmt)
+	}
+	return &balance
+}
+
 // setInstitutionNameFromMeta sets institution name from metadata if available
 func setInstitutionNameFromMeta(account *parser.RawAccount, meta *parser.Metadata) {
 	if meta != nil && meta.Institution() != "" {
@@ -147,6 +147,32 @@ func TestCanParse_NonOFXFile(t *testing.T) {
 	}
 }
 
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"OFX v1 header", "OFXHEADER:100\nDATA:OFXSGML\n", true},
+		{"OFX v1 header after BOM and blank lines", "\uFEFF\r\n\r\nOFXHEADER:100\n", true},
+		{"OFX v2 XML", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<?OFX OFXHEADER=\"200\" VERSION=\"220\"?>\n<OFX>", true},
+		{"Bare OFX element", "<OFX>\n<SIGNONMSGSRSV1>", true},
+		{"XML that is not OFX", "<?xml version=\"1.0\"?>\n<rss version=\"2.0\">", false},
+		{"Text mentioning OFXHEADER", "Notes: the OFXHEADER line comes first\n", false},
+		{"CSV", "Date,Description,Amount\n", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			if got := p.Sniff([]byte(tt.header)); got != tt.expected {
+				t.Errorf("Sniff() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParse_SyntheticBankStatement(t *testing.T) {
 	// Create synthetic OFX content for CI
 	// TODO(#1303): Consider extracting large OFX strings to testdata files or helper functions
@@ -275,6 +301,14 @@ NEWFILEUID:NONE
 	if txn2.Amount() != 1000.00 {
 		t.Errorf("Transaction[1].Amount = %v, want 1000.00", txn2.Amount())
 	}
+
+	// Verify ledger balance
+	if stmt.LedgerBalance == nil {
+		t.Fatal("LedgerBalance = nil, want 2000.00")
+	}
+	if *stmt.LedgerBalance != 2000.00 {
+		t.Errorf("LedgerBalance = %v, want 2000.00", *stmt.LedgerBalance)
+	}
 }
 
 func TestParse_SyntheticCreditCard(t *testing.T) {
@@ -362,6 +396,14 @@ NEWFILEUID:NONE
 	if stmt.Transactions[0].Description() != "Amazon Purchase" {
 		t.Errorf("Transaction description = %q, want %q", stmt.Transactions[0].Description(), "Amazon Purchase")
 	}
+
+	// Verify ledger balance keeps the OFX sign (negative = amount owed)
+	if stmt.LedgerBalance == nil {
+		t.Fatal("LedgerBalance = nil, want -500.00")
+	}
+	if *stmt.LedgerBalance != -500.00 {
+		t.Errorf("LedgerBalance = %v, want -500.00", *stmt.LedgerBalance)
+	}
 }
 
 func TestParse_SyntheticInvestment(t *testing.T) {
@@ -0,0 +1,217 @@
+package registry_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/registry"
+	"github.com/rumor-ml/commons.systems/finparse/internal/scanner"
+	"github.com/rumor-ml/commons.systems/finparse/internal/transform"
+)
+
+// fooParser parses a minimal "date|description|amount" line format, standing in
+// for an out-of-tree parser registered as a plugin.
+type fooParser struct{}
+
+func (p *fooParser) Name() string { return "foo" }
+
+func (p *fooParser) CanParse(path string, header []byte) bool { return false }
+
+func (p *fooParser) Parse(ctx context.Context, r io.Reader, meta *parser.Metadata) (*parser.RawStatement, error) {
+	account, err := parser.NewRawAccount("FOO", meta.Institution(), meta.AccountNumber(), "checking")
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []parser.RawTransaction
+	var first, last time.Time
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, got %d", line, len(fields))
+		}
+		date, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		amount, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		txn, err := parser.NewRawTransaction(fmt.Sprintf("FOO-%d", line), date, date, fields[1], amount)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, *txn)
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	period, err := parser.NewPeriod(first, last.Add(24*time.Hour-time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return &parser.RawStatement{Account: *account, Period: *period, Transactions: transactions}, nil
+}
+
+func registerFooPlugin(t *testing.T) {
+	t.Helper()
+	registry.Register("foo", func(path string) bool {
+		return strings.EqualFold(filepath.Ext(path), ".foo")
+	}, func() parser.Parser {
+		return &fooParser{}
+	})
+	t.Cleanup(func() { registry.Unregister("foo") })
+}
+
+// TestPlugin_EndToEnd verifies a registered plugin handles a .foo file from scan to budget.
+func TestPlugin_EndToEnd(t *testing.T) {
+	registerFooPlugin(t)
+
+	root := t.TempDir()
+	acctDir := filepath.Join(root, "payroll_co", "5555")
+	if err := os.MkdirAll(acctDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "2025-01-15|Payroll Deposit|2500.00\n2025-01-31|Payroll Deposit|2500.00\n"
+	if err := os.WriteFile(filepath.Join(acctDir, "export.foo"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := scanner.New(root).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected scanner to find 1 plugin file, got %d", len(files))
+	}
+
+	reg, err := registry.New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	p, err := reg.FindParser(files[0].Path)
+	if err != nil {
+		t.Fatalf("FindParser() error: %v", err)
+	}
+	if p.Name() != "foo" {
+		t.Fatalf("Expected foo parser, got %q", p.Name())
+	}
+
+	f, err := os.Open(files[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	raw, err := p.Parse(context.Background(), f, files[0].Metadata)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	budget := domain.NewBudget()
+	if _, err := transform.TransformStatement(raw, budget, nil, nil); err != nil {
+		t.Fatalf("TransformStatement() error: %v", err)
+	}
+	if got := len(budget.GetTransactions()); got != 2 {
+		t.Errorf("Expected 2 transactions, got %d", got)
+	}
+	if accounts := budget.GetAccounts(); len(accounts) != 1 || accounts[0].InstitutionID != "payroll-co" {
+		t.Errorf("Expected one payroll-co account, got %+v", accounts)
+	}
+}
+
+// TestPlugin_TakesPrecedenceOverBuiltins verifies plugin matchers are consulted before built-in parsers.
+func TestPlugin_TakesPrecedenceOverBuiltins(t *testing.T) {
+	registry.Register("foo-csv", func(path string) bool {
+		return strings.HasSuffix(path, ".payroll.csv")
+	}, func() parser.Parser {
+		return &namedFooParser{name: "foo-csv"}
+	})
+	t.Cleanup(func() { registry.Unregister("foo-csv") })
+
+	path := filepath.Join(t.TempDir(), "jan.payroll.csv")
+	if err := os.WriteFile(path, []byte("Account,Date\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := registry.New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	p, err := reg.FindParser(path)
+	if err != nil {
+		t.Fatalf("FindParser() error: %v", err)
+	}
+	if p.Name() != "foo-csv" {
+		t.Errorf("Expected plugin parser, got %q", p.Name())
+	}
+}
+
+// TestRegister_Panics verifies invalid registrations panic like database/sql.Register.
+func TestRegister_Panics(t *testing.T) {
+	registerFooPlugin(t)
+
+	matcher := func(string) bool { return false }
+	factory := func() parser.Parser { return &fooParser{} }
+
+	tests := []struct {
+		name     string
+		register func()
+	}{
+		{"empty name", func() { registry.Register("", matcher, factory) }},
+		{"nil matcher", func() { registry.Register("bar", nil, factory) }},
+		{"nil factory", func() { registry.Register("bar", matcher, nil) }},
+		{"duplicate name", func() { registry.Register("foo", matcher, factory) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected Register to panic")
+				}
+			}()
+			tt.register()
+		})
+	}
+}
+
+// TestNew_PluginNameConflict verifies a plugin cannot shadow a built-in parser name.
+func TestNew_PluginNameConflict(t *testing.T) {
+	registry.Register("ofx-shadow", func(string) bool { return false }, func() parser.Parser {
+		return &namedFooParser{name: "ofx"}
+	})
+	t.Cleanup(func() { registry.Unregister("ofx-shadow") })
+
+	_, err := registry.New()
+	if err == nil {
+		t.Fatal("Expected error for plugin parser named like a built-in")
+	}
+	if !strings.Contains(err.Error(), `plugin "ofx-shadow"`) {
+		t.Errorf("Expected error to name the plugin, got: %v", err)
+	}
+}
+
+type namedFooParser struct {
+	fooParser
+	name string
+}
+
+func (p *namedFooParser) Name() string { return p.name }
@@ -15,16 +15,85 @@ import (
 // Registry holds all registered parsers with thread-safe access for concurrent file parsing.
 // TODO(#1322): Add concurrent tests to verify thread safety of FindParser calls
 type Registry struct {
-	mu      sync.RWMutex
-	parsers []parser.Parser
+	mu       sync.RWMutex
+	parsers  []parser.Parser
+	matchers map[string]func(path string) bool // Plugin parser name -> path matcher
 }
 
-// New creates a registry with built-in parsers and optional custom parsers.
-// Returns an error including: (1) list of successfully registered parser names, (2) which parser
+// plugin is a parser registered through Register, instantiated by each New call.
+type plugin struct {
+	name    string
+	matcher func(path string) bool
+	factory func() parser.Parser
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   []plugin
+)
+
+// Register makes a plugin parser available to every registry subsequently created by New.
+// FindParser selects the plugin for any path the matcher accepts, ahead of built-in parsers,
+// and the scanner picks up files it accepts regardless of extension.
+//
+// Register is intended to be called from an init function. Like database/sql.Register,
+// it panics if name is empty, matcher or factory is nil, or name is already registered.
+func Register(name string, matcher func(path string) bool, factory func() parser.Parser) {
+	if name == "" {
+		panic("registry: Register called with empty plugin name")
+	}
+	if matcher == nil {
+		panic(fmt.Sprintf("registry: Register called with nil matcher for plugin %q", name))
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("registry: Register called with nil factory for plugin %q", name))
+	}
+
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	for _, existing := range plugins {
+		if existing.name == name {
+			panic(fmt.Sprintf("registry: Register called twice for plugin %q", name))
+		}
+	}
+	plugins = append(plugins, plugin{name: name, matcher: matcher, factory: factory})
+}
+
+// Unregister removes a plugin added by Register. Registries already created are unaffected.
+// Returns false if no plugin with that name is registered.
+func Unregister(name string) bool {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	for i, existing := range plugins {
+		if existing.name == name {
+			plugins = append(plugins[:i], plugins[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesPlugin reports whether any registered plugin accepts path.
+func MatchesPlugin(path string) bool {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	for _, p := range plugins {
+		if p.matcher(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// New creates a registry with built-in parsers, plugins added via Register, and optional
+// custom parsers. Returns an error including: (1) list of successfully registered parser names, (2) which parser
 // failed (for custom parsers: "parser X of Y"), and (3) the underlying error. Registration can fail
 // due to duplicate names or nil parsers.
 func New(customParsers ...parser.Parser) (*Registry, error) {
-	r := &Registry{parsers: []parser.Parser{}}
+	r := &Registry{
+		parsers:  []parser.Parser{},
+		matchers: make(map[string]func(path string) bool),
+	}
 
 	// getRegisteredNames returns comma-separated parser names, or "none" if empty.
 	// Using "none" instead of empty string makes error messages clearer when no parsers
@@ -53,6 +122,23 @@ func New(customParsers ...parser.Parser) (*Registry, error) {
 		return nil, fmt.Errorf("failed to register csv-pnc parser - Successfully registered: %s: %w", getRegisteredNames(), err)
 	}
 
+	// Register plugins
+	pluginsMu.RLock()
+	registered := append([]plugin(nil), plugins...)
+	pluginsMu.RUnlock()
+	for _, pl := range registered {
+		p := pl.factory()
+		if p == nil {
+			return nil, fmt.Errorf("failed to register plugin %q - Successfully registered: %s: factory returned nil parser",
+				pl.name, getRegisteredNames())
+		}
+		if err := r.register(p); err != nil {
+			return nil, fmt.Errorf("failed to register plugin %q - Successfully registered: %s: %w",
+				pl.name, getRegisteredNames(), err)
+		}
+		r.matchers[p.Name()] = pl.matcher
+	}
+
 	// Register custom parsers
 	for i, p := range customParsers {
 		if err := r.register(p); err != nil {
@@ -99,7 +185,8 @@ func (r *Registry) register(p parser.Parser) error {
 // larger headers should document this constraint.
 //
 // Each parser's CanParse method receives the header and must validate it contains
-// sufficient data for reliable format detection.
+// sufficient data for reliable format detection. When none accepts the file, parsers
+// implementing parser.ContentSniffer are asked to recognize it from the header alone.
 // TODO(#1320): Simplify comment to avoid duplicating implementation details from code
 func (r *Registry) FindParser(path string) (parser.Parser, error) {
 	// Read file header for format detection
@@ -111,13 +198,30 @@ func (r *Registry) FindParser(path string) (parser.Parser, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Try each parser's CanParse method
+	// Plugins are consulted first so they can claim files a built-in parser would also accept
+	for _, p := range r.parsers {
+		if matcher, isPlugin := r.matchers[p.Name()]; isPlugin && matcher(path) {
+			return p, nil
+		}
+	}
+
+	// Try each remaining parser's CanParse method
 	for _, p := range r.parsers {
+		if _, isPlugin := r.matchers[p.Name()]; isPlugin {
+			continue
+		}
 		if p.CanParse(path, header) {
 			return p, nil
 		}
 	}
 
+	// No parser accepts the file by extension, so fall back to content sniffing
+	for _, p := range r.parsers {
+		if s, ok := p.(parser.ContentSniffer); ok && s.Sniff(header) {
+			return p, nil
+		}
+	}
+
 	return nil, fmt.Errorf("no parser found for file: %s", path)
 }
 
@@ -159,3 +263,17 @@ func (r *Registry) ListParsers() []string {
 	}
 	return names
 }
+
+// Parser returns the registered parser with the given name, for input whose format is
+// known up front (e.g. a statement on stdin) rather than detected by FindParser.
+func (r *Registry) Parser(name string) (parser.Parser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.parsers {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no parser named %q", name)
+}
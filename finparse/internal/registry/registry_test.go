@@ -343,6 +343,41 @@ func TestRegistry_FindParser(t *testing.T) {
 	}
 }
 
+func TestRegistry_FindParser_ContentSniffing(t *testing.T) {
+	ofxContent := "OFXHEADER:100\nDATA:OFXSGML\n<OFX><SIGNONMSGSRSV1></SIGNONMSGSRSV1></OFX>"
+	csvContent := "12345,2024/01/01,2024/01/31,1000.00,2000.00\n"
+	tests := []struct {
+		name         string
+		content      string
+		ext          string
+		expectParser string
+	}{
+		{"OFX without extension", ofxContent, "", "ofx"},
+		{"OFX saved as .txt", ofxContent, ".txt", "ofx"},
+		{"PNC CSV without extension", csvContent, "", "csv-pnc"},
+		{"Unrecognized content", "Some unknown format", "", ""},
+	}
+
+	reg := MustNew()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := reg.FindParser(createTempFileWithExt(t, tt.content, tt.ext))
+			if tt.expectParser == "" {
+				if err == nil {
+					t.Fatalf("Expected no parser, got %s", p.Name())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if p.Name() != tt.expectParser {
+				t.Errorf("Expected parser %q, got %q", tt.expectParser, p.Name())
+			}
+		})
+	}
+}
+
 func TestRegistry_FindParser_FileErrors(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -4,7 +4,9 @@ package rules
 import (
 	_ "embed"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -78,7 +80,7 @@ func (f *Flags) UnmarshalYAML(unmarshal func(interface{}) error) error {
 // Rule represents a single categorization rule.
 //
 // Rules should be created via:
-//   - YAML loading: NewEngine, LoadEmbedded, LoadFromFile
+//   - YAML loading: NewEngine, LoadEmbedded, LoadFromFile, LoadFromFiles
 //   - Programmatic construction: NewRule constructor
 //
 // Both methods provide comprehensive validation of all invariants:
@@ -101,6 +103,60 @@ type Rule struct {
 	Category       string    `yaml:"category"`
 	Flags          Flags     `yaml:"flags"`
 	RedemptionRate float64   `yaml:"redemption_rate"`
+	// Splits optionally divide a matched transaction across categories.
+	// Category remains the fallback when a transaction cannot be split.
+	Splits []Split `yaml:"splits,omitempty"`
+}
+
+// Split is one share of a split rule: either a percentage or a fixed amount.
+//
+// Fixed amounts are taken first (as magnitudes, in the sign of the transaction);
+// percentage splits divide the remainder and must total 100. A split rule needs
+// at least two splits and at least one percentage split, so the parts always sum
+// exactly to the original amount.
+type Split struct {
+	Category string  `yaml:"category"`
+	Percent  float64 `yaml:"percent,omitempty"`
+	Amount   float64 `yaml:"amount,omitempty"`
+}
+
+// splitPercentTolerance absorbs float noise when summing YAML percentages.
+const splitPercentTolerance = 1e-9
+
+// ValidateSplits checks split invariants. An empty slice is valid (no splitting).
+func ValidateSplits(splits []Split) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	if len(splits) < 2 {
+		return fmt.Errorf("splits need at least 2 entries, got %d", len(splits))
+	}
+
+	var percentTotal float64
+	percentSplits := 0
+	for i, split := range splits {
+		if !domain.ValidateCategory(domain.Category(split.Category)) {
+			return fmt.Errorf("split %d: invalid category %q", i, split.Category)
+		}
+		if split.Percent < 0 || split.Amount < 0 {
+			return fmt.Errorf("split %d: percent and amount must not be negative", i)
+		}
+		if (split.Percent > 0) == (split.Amount > 0) {
+			return fmt.Errorf("split %d: exactly one of percent or amount must be set", i)
+		}
+		if split.Percent > 0 {
+			percentSplits++
+			percentTotal += split.Percent
+		}
+	}
+
+	if percentSplits == 0 {
+		return fmt.Errorf("splits need at least one percent split to absorb the remainder")
+	}
+	if math.Abs(percentTotal-100) > splitPercentTolerance {
+		return fmt.Errorf("split percentages must total 100, got %g", percentTotal)
+	}
+	return nil
 }
 
 // NewRule creates a validated rule. Checks invariants:
@@ -175,7 +231,10 @@ func (r *Rule) Validate() error {
 		r.Flags,
 		r.RedemptionRate,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return ValidateSplits(r.Splits)
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler to enforce validation during YAML loading.
@@ -196,6 +255,10 @@ func (r *Rule) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if err != nil {
 		return fmt.Errorf("invalid rule %q: %w", raw.Name, err)
 	}
+	if err := ValidateSplits(raw.Splits); err != nil {
+		return fmt.Errorf("invalid rule %q: %w", raw.Name, err)
+	}
+	validated.Splits = raw.Splits
 
 	*r = *validated
 	return nil
@@ -205,11 +268,15 @@ func (r *Rule) UnmarshalYAML(unmarshal func(interface{}) error) error {
 // RuleSet represents the top-level YAML structure
 type RuleSet struct {
 	Rules []Rule `yaml:"rules"`
+	// InstitutionAliases maps a canonical institution name to the variants
+	// statements use for it (e.g. "Chase": ["CHASE", "JPMORGAN CHASE"]).
+	InstitutionAliases map[string][]string `yaml:"institution_aliases"`
 }
 
 // Engine performs rule matching on transaction descriptions
 type Engine struct {
-	rules []Rule // Sorted by priority (highest first)
+	rules   []Rule            // Sorted by priority (highest first)
+	aliases map[string]string // Normalized institution name variant -> canonical name
 }
 
 // MatchResult contains the result of applying a rule
@@ -219,7 +286,8 @@ type MatchResult struct {
 	Vacation       bool
 	Transfer       bool
 	RedemptionRate float64
-	RuleName       string // For debugging
+	RuleName       string  // For debugging
+	Splits         []Split // Non-empty when the matched rule splits transactions
 }
 
 // NewMatchResult creates a validated match result.
@@ -249,6 +317,15 @@ func NewMatchResult(category domain.Category, redeemable, vacation, transfer boo
 
 // NewEngine creates a rules engine from YAML data
 func NewEngine(rulesData []byte) (*Engine, error) {
+	ruleSet, err := parseRuleSet(rulesData)
+	if err != nil {
+		return nil, err
+	}
+	return newEngine(ruleSet.Rules, ruleSet.InstitutionAliases)
+}
+
+// parseRuleSet parses YAML rules and validates each one, keeping file order
+func parseRuleSet(rulesData []byte) (*RuleSet, error) {
 	var ruleSet RuleSet
 	if err := yaml.Unmarshal(rulesData, &ruleSet); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML rules (check syntax, indentation, and field names): %w", err)
@@ -270,20 +347,97 @@ func NewEngine(rulesData []byte) (*Engine, error) {
 		if err != nil {
 			return nil, fmt.Errorf("rule %d (%s): %w", i, rule.Name, err)
 		}
+		if err := ValidateSplits(rule.Splits); err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i, rule.Name, err)
+		}
+		validatedRule.Splits = rule.Splits
 		validatedRules[i] = *validatedRule
 	}
+	ruleSet.Rules = validatedRules
+	return &ruleSet, nil
+}
 
+// newEngine builds an engine from validated rules in file order
+func newEngine(rules []Rule, institutionAliases map[string][]string) (*Engine, error) {
 	// Sort rules by priority (highest first). SliceStable preserves YAML file order
 	// for equal-priority rules, ensuring deterministic first-match-wins behavior.
-	sort.SliceStable(validatedRules, func(i, j int) bool {
-		return validatedRules[i].Priority > validatedRules[j].Priority
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
 	})
 
+	aliases, err := buildInstitutionAliases(institutionAliases)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Engine{
-		rules: validatedRules,
+		rules:   rules,
+		aliases: aliases,
 	}, nil
 }
 
+// buildInstitutionAliases indexes alias variants by normalized (lowercased, trimmed)
+// name. Each canonical name is also indexed, so differently-cased spellings of it
+// resolve to the configured form. A variant claimed by two canonical names is an error.
+func buildInstitutionAliases(canonicalToVariants map[string][]string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	add := func(variant, canonical string) error {
+		key := normalizeInstitution(variant)
+		if key == "" {
+			return fmt.Errorf("institution alias for %q cannot be empty", canonical)
+		}
+		if existing, ok := aliases[key]; ok && existing != canonical {
+			return fmt.Errorf("institution alias %q maps to both %q and %q", variant, existing, canonical)
+		}
+		aliases[key] = canonical
+		return nil
+	}
+
+	// Sorted so a conflicting alias is reported deterministically
+	canonicals := make([]string, 0, len(canonicalToVariants))
+	for canonical := range canonicalToVariants {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+
+	for _, canonical := range canonicals {
+		if strings.TrimSpace(canonical) != canonical || canonical == "" {
+			return nil, fmt.Errorf("canonical institution name %q must be non-empty without surrounding whitespace", canonical)
+		}
+		if err := add(canonical, canonical); err != nil {
+			return nil, err
+		}
+		for _, variant := range canonicalToVariants[canonical] {
+			if err := add(variant, canonical); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return aliases, nil
+}
+
+func normalizeInstitution(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// CanonicalInstitution returns the canonical name for an institution, matching
+// aliases case-insensitively. Names with no alias are returned unchanged.
+func (e *Engine) CanonicalInstitution(name string) string {
+	if canonical, ok := e.aliases[normalizeInstitution(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+// InstitutionAliases returns a copy of the alias index (normalized variant -> canonical name).
+func (e *Engine) InstitutionAliases() map[string]string {
+	result := make(map[string]string, len(e.aliases))
+	for k, v := range e.aliases {
+		result[k] = v
+	}
+	return result
+}
+
 // LoadEmbedded loads the embedded rules.yaml file
 func LoadEmbedded() (*Engine, error) {
 	engine, err := NewEngine(embeddedRules)
@@ -306,6 +460,95 @@ func LoadFromFile(path string) (*Engine, error) {
 	return engine, nil
 }
 
+// LoadFromFiles loads and merges rules from several paths. A directory contributes
+// its *.yaml and *.yml files in name order. A single file loads exactly as with
+// LoadFromFile.
+//
+// Rules are prioritized across the merged set; equal priorities keep the order of
+// paths, then file order. Institution aliases are combined. A rule name used in two
+// files is an error naming both, since MatchResult.RuleName must say which rule matched.
+func LoadFromFiles(paths []string) (*Engine, error) {
+	files, err := expandRulePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 1 {
+		return LoadFromFile(files[0])
+	}
+
+	var merged []Rule
+	aliases := make(map[string][]string)
+	ruleFiles := make(map[string]string) // Rule name -> file that defined it
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules file: %w", err)
+		}
+		ruleSet, err := parseRuleSet(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rules from %q: %w", path, err)
+		}
+
+		// Duplicates within one file are allowed, as with LoadFromFile
+		fileRules := make(map[string]bool)
+		for _, rule := range ruleSet.Rules {
+			if rule.Name == "" || fileRules[rule.Name] {
+				continue
+			}
+			if other, ok := ruleFiles[rule.Name]; ok {
+				return nil, fmt.Errorf("duplicate rule %q in %q and %q", rule.Name, other, path)
+			}
+			fileRules[rule.Name] = true
+			ruleFiles[rule.Name] = path
+		}
+		merged = append(merged, ruleSet.Rules...)
+
+		for canonical, variants := range ruleSet.InstitutionAliases {
+			aliases[canonical] = append(aliases[canonical], variants...)
+		}
+	}
+
+	engine, err := newEngine(merged, aliases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge rules from %s: %w", strings.Join(files, ", "), err)
+	}
+	return engine, nil
+}
+
+// expandRulePaths replaces directories in paths with the YAML files they contain
+func expandRulePaths(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		// Unreadable paths are reported when read, as with LoadFromFile
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules directory: %w", err)
+		}
+		found := 0
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+			found++
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("rules directory %q has no .yaml or .yml files", path)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no rules files given")
+	}
+	return files, nil
+}
+
 // Match applies rules to a transaction description and returns the first match.
 // Rules are evaluated in priority order (highest first). Rules with equal priority
 // are evaluated in their original YAML file order (stable sort in NewEngine preserves
@@ -345,6 +588,9 @@ func (e *Engine) Match(description string) (*MatchResult, bool, error) {
 					rule.Name, rule.Category, rule.Flags.Redeemable, rule.Flags.Vacation,
 					rule.Flags.Transfer, rule.RedemptionRate, err)
 			}
+			if len(rule.Splits) > 0 {
+				result.Splits = append([]Split(nil), rule.Splits...)
+			}
 			return result, true, nil
 		}
 	}
@@ -354,13 +600,17 @@ func (e *Engine) Match(description string) (*MatchResult, bool, error) {
 
 // GetRules returns a copy of the rules for inspection/debugging.
 //
-// Returns a new slice containing copies of each Rule struct. Rule fields are
-// either value types or immutable (strings), so modifying returned rules will
-// not affect the engine's internal state.
+// Returns a new slice containing copies of each Rule struct, with Splits deep
+// copied, so modifying returned rules will not affect the engine's internal state.
 // Rules are returned in priority order (highest first). For equal priorities,
 // rules appear in YAML file order (stable sort).
 func (e *Engine) GetRules() []Rule {
 	result := make([]Rule, len(e.rules))
 	copy(result, e.rules)
+	for i := range result {
+		if result[i].Splits != nil {
+			result[i].Splits = append([]Split(nil), result[i].Splits...)
+		}
+	}
 	return result
 }
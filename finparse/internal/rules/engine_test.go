@@ -751,6 +751,134 @@ func TestLoadFromFile_NotExists(t *testing.T) {
 	}
 }
 
+func TestLoadFromFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeRules := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	groceries := writeRules("groceries.yaml", `
+rules:
+  - name: "Market"
+    pattern: "MARKET"
+    match_type: "contains"
+    priority: 50
+    category: "groceries"
+institution_aliases:
+  Chase:
+    - CHASE
+`)
+	dining := writeRules("dining.yaml", `
+rules:
+  - name: "Farmers Market Cafe"
+    pattern: "FARMERS MARKET CAFE"
+    match_type: "contains"
+    priority: 80
+    category: "dining"
+institution_aliases:
+  Chase:
+    - JPMORGAN CHASE
+`)
+
+	engine, err := LoadFromFiles([]string{groceries, dining})
+	require.NoError(t, err)
+
+	// Priority spans files: the later file's higher-priority rule wins
+	result, matched, err := engine.Match("FARMERS MARKET CAFE")
+	require.NoError(t, err)
+	require.True(t, matched)
+	assert.Equal(t, domain.CategoryDining, result.Category)
+
+	result, matched, err = engine.Match("CORNER MARKET")
+	require.NoError(t, err)
+	require.True(t, matched)
+	assert.Equal(t, domain.CategoryGroceries, result.Category)
+
+	// Aliases for the same institution are combined
+	assert.Equal(t, "Chase", engine.CanonicalInstitution("CHASE"))
+	assert.Equal(t, "Chase", engine.CanonicalInstitution("JPMorgan Chase"))
+
+	t.Run("directory", func(t *testing.T) {
+		writeRules("dir/a.yaml", `
+rules:
+  - name: "A"
+    pattern: "ALPHA"
+    match_type: "contains"
+    priority: 10
+    category: "shopping"
+`)
+		writeRules("dir/b.yml", `
+rules:
+  - name: "B"
+    pattern: "ALPHA BETA"
+    match_type: "contains"
+    priority: 10
+    category: "groceries"
+`)
+		writeRules("dir/notes.txt", "not rules")
+
+		engine, err := LoadFromFiles([]string{filepath.Join(tmpDir, "dir")})
+		require.NoError(t, err)
+		names := []string{}
+		for _, rule := range engine.GetRules() {
+			names = append(names, rule.Name)
+		}
+		// Equal priorities keep file name order
+		assert.Equal(t, []string{"A", "B"}, names)
+	})
+
+	t.Run("duplicate rule across files", func(t *testing.T) {
+		dup := writeRules("dup.yaml", `
+rules:
+  - name: "Market"
+    pattern: "SUPERMARKET"
+    match_type: "contains"
+    priority: 60
+    category: "groceries"
+`)
+		_, err := LoadFromFiles([]string{groceries, dup})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `duplicate rule "Market"`)
+		assert.Contains(t, err.Error(), groceries)
+		assert.Contains(t, err.Error(), dup)
+	})
+
+	t.Run("conflicting aliases across files", func(t *testing.T) {
+		amex := writeRules("amex.yaml", `
+rules: []
+institution_aliases:
+  Amex:
+    - CHASE
+`)
+		_, err := LoadFromFiles([]string{groceries, amex})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maps to both")
+	})
+
+	t.Run("single file matches LoadFromFile", func(t *testing.T) {
+		single, err := LoadFromFiles([]string{groceries})
+		require.NoError(t, err)
+		want, err := LoadFromFile(groceries)
+		require.NoError(t, err)
+		assert.Equal(t, want.GetRules(), single.GetRules())
+
+		_, err = LoadFromFiles([]string{"/nonexistent/rules.yaml"})
+		_, wantErr := LoadFromFile("/nonexistent/rules.yaml")
+		assert.Equal(t, wantErr.Error(), err.Error())
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		empty := filepath.Join(tmpDir, "empty")
+		require.NoError(t, os.Mkdir(empty, 0755))
+		_, err := LoadFromFiles([]string{empty})
+		assert.ErrorContains(t, err, "no .yaml or .yml files")
+	})
+}
+
 func TestGetRules(t *testing.T) {
 	rulesYAML := `
 rules:
@@ -1760,15 +1888,26 @@ rules:
 	})
 }
 
-func TestRuleIsValueType(t *testing.T) {
-	// This test ensures Rule contains only value types (no pointers/slices).
-	// If Rule gains pointer/slice fields, this will fail to compile,
-	// alerting developers that GetRules needs deep copying.
-	var r1, r2 Rule
-	_ = r1 == r2
+func TestGetRules_DeepCopiesSplits(t *testing.T) {
+	// Rule.Splits is the only reference-typed field; GetRules must deep copy it so
+	// callers cannot modify the engine's rules.
+	engine, err := NewEngine([]byte(`
+rules:
+  - name: "Costco"
+    pattern: "COSTCO"
+    match_type: "contains"
+    priority: 400
+    category: "groceries"
+    splits:
+      - {category: "groceries", percent: 70}
+      - {category: "shopping", percent: 30}
+`))
+	require.NoError(t, err)
 
-	// Document why this matters
-	t.Log("Rule is a pure value type - GetRules shallow copy is safe")
+	rules := engine.GetRules()
+	rules[0].Splits[0].Category = "other"
+
+	assert.Equal(t, "groceries", engine.GetRules()[0].Splits[0].Category)
 }
 
 func TestNewEngine_TransferRedeemableConflict(t *testing.T) {
@@ -2462,3 +2601,126 @@ func TestNewMatchResult(t *testing.T) {
 		})
 	}
 }
+
+func TestEngine_CanonicalInstitution(t *testing.T) {
+	engine, err := NewEngine([]byte(`
+rules: []
+institution_aliases:
+  Chase:
+    - CHASE
+    - Chase Bank
+    - "  JPMORGAN CHASE "
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"CHASE", "Chase"},
+		{"chase bank", "Chase"},
+		{"JPMorgan Chase", "Chase"},
+		{"chase", "Chase"},
+		{"Chase", "Chase"},
+		{"American Express", "American Express"}, // unknown passes through unchanged
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, engine.CanonicalInstitution(tt.name), "CanonicalInstitution(%q)", tt.name)
+	}
+	// "CHASE" and the canonical "Chase" share one normalized entry
+	assert.Len(t, engine.InstitutionAliases(), 3)
+}
+
+func TestNewEngine_InvalidInstitutionAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "alias claimed by two institutions",
+			yaml:    "institution_aliases:\n  Chase: [JPM]\n  JPMorgan: [jpm]\n",
+			wantErr: `institution alias "jpm" maps to both "Chase" and "JPMorgan"`,
+		},
+		{
+			name:    "empty alias",
+			yaml:    "institution_aliases:\n  Chase: [\"  \"]\n",
+			wantErr: `institution alias for "Chase" cannot be empty`,
+		},
+		{
+			name:    "canonical name with whitespace",
+			yaml:    "institution_aliases:\n  \" Chase\": [CHASE]\n",
+			wantErr: "must be non-empty without surrounding whitespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEngine([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateSplits(t *testing.T) {
+	tests := []struct {
+		name    string
+		splits  []Split
+		wantErr string
+	}{
+		{name: "no splits", splits: nil},
+		{name: "percentages", splits: []Split{{Category: "groceries", Percent: 70}, {Category: "shopping", Percent: 30}}},
+		{name: "fixed plus remainder", splits: []Split{{Category: "shopping", Amount: 25}, {Category: "groceries", Percent: 100}}},
+		{name: "single split", splits: []Split{{Category: "groceries", Percent: 100}}, wantErr: "at least 2 entries"},
+		{name: "percentages under 100", splits: []Split{{Category: "groceries", Percent: 60}, {Category: "shopping", Percent: 30}}, wantErr: "must total 100"},
+		{name: "only fixed amounts", splits: []Split{{Category: "groceries", Amount: 10}, {Category: "shopping", Amount: 5}}, wantErr: "at least one percent split"},
+		{name: "both percent and amount", splits: []Split{{Category: "groceries", Percent: 50, Amount: 5}, {Category: "shopping", Percent: 50}}, wantErr: "exactly one of percent or amount"},
+		{name: "neither percent nor amount", splits: []Split{{Category: "groceries"}, {Category: "shopping", Percent: 100}}, wantErr: "exactly one of percent or amount"},
+		{name: "negative amount", splits: []Split{{Category: "groceries", Amount: -5}, {Category: "shopping", Percent: 100}}, wantErr: "must not be negative"},
+		{name: "invalid category", splits: []Split{{Category: "bogus", Percent: 50}, {Category: "shopping", Percent: 50}}, wantErr: `invalid category "bogus"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSplits(tt.splits)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMatch_ReturnsSplits(t *testing.T) {
+	engine, err := NewEngine([]byte(`
+rules:
+  - name: "Costco"
+    pattern: "COSTCO"
+    match_type: "contains"
+    priority: 400
+    category: "groceries"
+    splits:
+      - {category: "groceries", percent: 70}
+      - {category: "shopping", percent: 30}
+`))
+	require.NoError(t, err)
+
+	result, matched, err := engine.Match("COSTCO WHSE #123")
+	require.NoError(t, err)
+	require.True(t, matched)
+	assert.Equal(t, []Split{{Category: "groceries", Percent: 70}, {Category: "shopping", Percent: 30}}, result.Splits)
+
+	_, err = NewEngine([]byte(`
+rules:
+  - name: "Bad split"
+    pattern: "X"
+    match_type: "contains"
+    priority: 1
+    category: "other"
+    splits:
+      - {category: "groceries", percent: 50}
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 2 entries")
+}
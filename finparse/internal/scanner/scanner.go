@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/registry"
 )
 
 // TODO(#1287): Consider removing vague comment about future unit tests
@@ -15,6 +16,8 @@ import (
 // Scanner walks directory tree and finds statement files
 type Scanner struct {
 	rootDir string
+	sniff   func(path string) bool
+	single  bool // Set by Scan when rootDir is a file
 }
 
 // New creates a new scanner for the given root directory
@@ -22,6 +25,17 @@ func New(rootDir string) *Scanner {
 	return &Scanner{rootDir: rootDir}
 }
 
+// NewWithSniffer creates a scanner that also considers files without an extension or
+// with an ambiguous one (.txt, .dat, .xml), keeping those sniff accepts. sniff is
+// typically backed by registry.FindParser, so files are picked up by their content.
+func NewWithSniffer(rootDir string, sniff func(path string) bool) *Scanner {
+	return &Scanner{rootDir: rootDir, sniff: sniff}
+}
+
+// sniffExtensions are extensions that say nothing about a file's format, so files
+// with them are statement candidates for the sniffer
+var sniffExtensions = map[string]bool{"": true, ".txt": true, ".dat": true, ".xml": true}
+
 // ScanResult represents a found file with metadata
 type ScanResult struct {
 	Path     string
@@ -54,7 +68,10 @@ func NewScanResult(path string, metadata *parser.Metadata) (ScanResult, error) {
 	return result, nil
 }
 
-// Scan walks the directory tree and finds all statement files
+// Scan walks the directory tree and finds all statement files. When the root is a
+// single file, that file is the only result, whatever its extension: it was named
+// explicitly, so a file no parser accepts is reported when it is parsed rather than
+// silently skipped.
 func (s *Scanner) Scan() ([]ScanResult, error) {
 	var results []ScanResult
 	fileCount := 0
@@ -65,6 +82,12 @@ func (s *Scanner) Scan() ([]ScanResult, error) {
 		return nil, fmt.Errorf("failed to expand path: %w", err)
 	}
 
+	// A missing root is left to Walk, which reports it like any other access error
+	if info, err := os.Stat(rootDir); err == nil && !info.IsDir() {
+		s.single = true
+		return s.scanFile(rootDir)
+	}
+
 	// Walk directory tree
 	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -76,8 +99,9 @@ func (s *Scanner) Scan() ([]ScanResult, error) {
 			return nil
 		}
 
-		// Only process files with known extensions
-		if !s.isStatementFile(path) {
+		// Only process files with known extensions, or candidates whose content a
+		// parser recognizes
+		if !s.isStatementFile(path) && !s.isSniffedStatement(path) {
 			return nil
 		}
 
@@ -106,10 +130,44 @@ func (s *Scanner) Scan() ([]ScanResult, error) {
 	return results, nil
 }
 
-// isStatementFile checks if file is a known statement format
+// SingleFile reports whether the last Scan found a single file rather than a directory
+func (s *Scanner) SingleFile() bool {
+	return s.single
+}
+
+// scanFile returns the result for a root that is a single file. There is no
+// directory structure to read, so its institution and account are unknown.
+func (s *Scanner) scanFile(path string) ([]ScanResult, error) {
+	metadata, err := parser.NewMetadata(path, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata for %s: %w", path, err)
+	}
+	result, err := NewScanResult(path, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scan result for %s: %w", path, err)
+	}
+	return []ScanResult{result}, nil
+}
+
+// isStatementFile checks if file is a known statement format or claimed by a plugin parser
 func (s *Scanner) isStatementFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
-	return ext == ".qfx" || ext == ".ofx" || ext == ".csv"
+	if ext == ".qfx" || ext == ".ofx" || ext == ".csv" {
+		return true
+	}
+	return registry.MatchesPlugin(path)
+}
+
+// isSniffedStatement checks if a file with an unknown or ambiguous extension is
+// accepted by the sniffer. Hidden files (e.g. .DS_Store) are never candidates.
+func (s *Scanner) isSniffedStatement(path string) bool {
+	if s.sniff == nil || strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+	if !sniffExtensions[strings.ToLower(filepath.Ext(path))] {
+		return false
+	}
+	return s.sniff(path)
 }
 
 // extractMetadata parses directory structure to extract institution/account info
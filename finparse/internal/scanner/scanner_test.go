@@ -95,6 +95,51 @@ func TestScanner_Scan(t *testing.T) {
 	assert.True(t, foundChase, "should find Chase statement")
 }
 
+func TestScanner_ScanWithSniffer(t *testing.T) {
+	tmpDir := t.TempDir()
+	acctDir := filepath.Join(tmpDir, "chase", "checking")
+	require.NoError(t, os.MkdirAll(acctDir, 0755))
+	for _, name := range []string{"statement.ofx", "download", "export.txt", "notes.txt", ".hidden", "image.pdf"} {
+		require.NoError(t, os.WriteFile(filepath.Join(acctDir, name), []byte("test"), 0644))
+	}
+
+	var sniffed []string
+	sniff := func(path string) bool {
+		sniffed = append(sniffed, filepath.Base(path))
+		return filepath.Base(path) != "notes.txt"
+	}
+	results, err := NewWithSniffer(tmpDir, sniff).Scan()
+	require.NoError(t, err)
+
+	var found []string
+	for _, r := range results {
+		found = append(found, filepath.Base(r.Path))
+		assert.Equal(t, "Chase", r.Metadata.Institution())
+	}
+	assert.ElementsMatch(t, []string{"statement.ofx", "download", "export.txt"}, found)
+	assert.ElementsMatch(t, []string{"download", "export.txt", "notes.txt"}, sniffed,
+		"only files with unknown or ambiguous extensions should be sniffed")
+}
+
+func TestScanner_Scan_SingleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"statement.qfx", "download"} {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte("test"), 0644))
+
+		sniff := func(string) bool {
+			t.Errorf("sniffer called for explicitly named file %s", name)
+			return false
+		}
+		results, err := NewWithSniffer(path, sniff).Scan()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, path, results[0].Path)
+		assert.Empty(t, results[0].Metadata.Institution())
+		assert.NotEmpty(t, results[0].Warnings, "a single file has no institution directory")
+	}
+}
+
 func TestScanner_Scan_NonExistentDirectory(t *testing.T) {
 	scanner := New("/nonexistent/directory/path")
 	results, err := scanner.Scan()
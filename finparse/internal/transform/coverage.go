@@ -0,0 +1,57 @@
+package transform
+
+import "sort"
+
+// InstitutionCoverage is the rule-match coverage of one institution's transactions.
+type InstitutionCoverage struct {
+	InstitutionID   string `json:"institutionId"`
+	InstitutionName string `json:"institutionName"`
+	Matched         int    `json:"matched"`
+	Unmatched       int    `json:"unmatched"`
+}
+
+// Total returns the number of categorized transactions (matched + unmatched).
+func (c InstitutionCoverage) Total() int {
+	return c.Matched + c.Unmatched
+}
+
+// Percent returns the matched share of Total as a percentage (0 when Total is 0).
+func (c InstitutionCoverage) Percent() float64 {
+	if c.Total() == 0 {
+		return 0
+	}
+	return float64(c.Matched) / float64(c.Total()) * 100
+}
+
+// CoverageByInstitution aggregates per-statement stats by institution ID. Results are
+// ordered worst coverage first (ties by institution ID) so the institutions most in
+// need of rules lead the list. Institutions whose transactions were all skipped as
+// duplicates are omitted.
+func CoverageByInstitution(stats []*TransformStats) []InstitutionCoverage {
+	byID := make(map[string]*InstitutionCoverage)
+	for _, s := range stats {
+		if s == nil || s.RulesMatched+s.RulesUnmatched == 0 {
+			continue
+		}
+		c, ok := byID[s.InstitutionID]
+		if !ok {
+			c = &InstitutionCoverage{InstitutionID: s.InstitutionID, InstitutionName: s.InstitutionName}
+			byID[s.InstitutionID] = c
+		}
+		c.Matched += s.RulesMatched
+		c.Unmatched += s.RulesUnmatched
+	}
+
+	result := make([]InstitutionCoverage, 0, len(byID))
+	for _, c := range byID {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		pi, pj := result[i].Percent(), result[j].Percent()
+		if pi != pj {
+			return pi < pj
+		}
+		return result[i].InstitutionID < result[j].InstitutionID
+	})
+	return result
+}
@@ -0,0 +1,80 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/rules"
+)
+
+func TestCoverageByInstitution(t *testing.T) {
+	engine, err := rules.NewEngine([]byte(`
+rules:
+  - name: "Coffee"
+    pattern: "COFFEE"
+    match_type: "contains"
+    priority: 400
+    category: "dining"
+`))
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	date := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	period := mustNewPeriod(t, date.AddDate(0, 0, -4), date.AddDate(0, 0, 20))
+
+	// Chase is well covered across two statements (4/5 matched); PNC is poorly covered (1/4).
+	statements := []struct {
+		institution  string
+		accountID    string
+		descriptions []string
+	}{
+		{"Chase", "1001", []string{"COFFEE SHOP", "COFFEE BAR", "MYSTERY"}},
+		{"Chase", "1002", []string{"COFFEE CART", "COFFEE HOUSE"}},
+		{"PNC", "2001", []string{"COFFEE SHOP", "ATM", "CHECK 104", "WIRE"}},
+	}
+
+	budget := domain.NewBudget()
+	var allStats []*TransformStats
+	for _, st := range statements {
+		raw := &parser.RawStatement{
+			Account: *mustNewRawAccount(t, "INST", st.institution, st.accountID, "checking"),
+			Period:  *period,
+		}
+		for i, desc := range st.descriptions {
+			raw.Transactions = append(raw.Transactions,
+				*mustNewRawTransaction(t, fmt.Sprintf("%s-%d", st.accountID, i), date, date, desc, -5))
+		}
+		stats, err := TransformStatement(raw, budget, nil, engine)
+		if err != nil {
+			t.Fatalf("TransformStatement(%s) failed: %v", st.accountID, err)
+		}
+		allStats = append(allStats, stats)
+	}
+
+	got := CoverageByInstitution(allStats)
+	want := []InstitutionCoverage{
+		{InstitutionID: "pnc", InstitutionName: "PNC", Matched: 1, Unmatched: 3},
+		{InstitutionID: "chase", InstitutionName: "Chase", Matched: 4, Unmatched: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CoverageByInstitution() = %+v, want %+v", got, want)
+	}
+	if got[0].Percent() != 25 || got[1].Percent() != 80 {
+		t.Errorf("Expected 25%% and 80%% coverage, got %.1f%% and %.1f%%", got[0].Percent(), got[1].Percent())
+	}
+}
+
+func TestCoverageByInstitution_SkipsEmptyStats(t *testing.T) {
+	stats := []*TransformStats{nil, {InstitutionID: "chase"}}
+	if got := CoverageByInstitution(stats); len(got) != 0 {
+		t.Errorf("Expected no coverage entries, got %+v", got)
+	}
+	if (InstitutionCoverage{}).Percent() != 0 {
+		t.Error("Expected 0% coverage for an empty institution")
+	}
+}
@@ -96,3 +96,11 @@ func abbreviateSlug(slug string) string {
 func GenerateStatementID(periodStart time.Time, accountID string) string {
 	return fmt.Sprintf("stmt-%04d-%02d-%s", periodStart.Year(), periodStart.Month(), accountID)
 }
+
+// GenerateDatedStatementID creates a statement ID that includes the start day, for a
+// statement starting in the same month as another statement kept for the account.
+// Format: "stmt-YYYY-MM-DD-{accountID}"
+// Example: GenerateDatedStatementID(time.Date(2025, 10, 15, ...), "acc-amex-2011") → "stmt-2025-10-15-acc-amex-2011"
+func GenerateDatedStatementID(periodStart time.Time, accountID string) string {
+	return fmt.Sprintf("stmt-%04d-%02d-%02d-%s", periodStart.Year(), periodStart.Month(), periodStart.Day(), accountID)
+}
@@ -323,6 +323,13 @@ func TestGenerateStatementID(t *testing.T) {
 	}
 }
 
+func TestGenerateDatedStatementID(t *testing.T) {
+	got := GenerateDatedStatementID(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), "acc-amex-2011")
+	if want := "stmt-2025-01-05-acc-amex-2011"; got != want {
+		t.Errorf("GenerateDatedStatementID() = %q, expected %q", got, want)
+	}
+}
+
 func TestAbbreviateSlug(t *testing.T) {
 	tests := []struct {
 		name     string
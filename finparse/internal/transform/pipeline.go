@@ -24,9 +24,15 @@ type TransformStats struct {
 	RulesMatched                 int
 	RulesUnmatched               int
 	unmatchedExamples            []string // unexported, capped at 5 items
+	unmatched                    []UnmatchedTransaction
 	DuplicateInstitutionsSkipped int
 	DuplicateAccountsSkipped     int
 	duplicateExamples            []string // unexported, capped at 5 items
+	DuplicateStatementsMerged    int      // Statements merged into an overlapping statement for the same account
+	overlapExamples              []string // unexported, capped at 5 items
+	TransactionsSplit            int      // Transactions expanded into split entries
+	InstitutionID                string   // Slug of the statement's (canonicalized) institution
+	InstitutionName              string
 }
 
 // UnmatchedExamples returns a defensive copy of unmatched transaction examples (max 5 items).
@@ -36,6 +42,13 @@ func (s *TransformStats) UnmatchedExamples() []string {
 	return result
 }
 
+// Unmatched returns a defensive copy of every transaction no rule matched (uncapped).
+func (s *TransformStats) Unmatched() []UnmatchedTransaction {
+	result := make([]UnmatchedTransaction, len(s.unmatched))
+	copy(result, s.unmatched)
+	return result
+}
+
 // DuplicateExamples returns a defensive copy of duplicate transaction examples (max 5 items).
 func (s *TransformStats) DuplicateExamples() []string {
 	result := make([]string, len(s.duplicateExamples))
@@ -43,6 +56,13 @@ func (s *TransformStats) DuplicateExamples() []string {
 	return result
 }
 
+// OverlapExamples returns a defensive copy of merged overlapping statement examples (max 5 items).
+func (s *TransformStats) OverlapExamples() []string {
+	result := make([]string, len(s.overlapExamples))
+	copy(result, s.overlapExamples)
+	return result
+}
+
 // addUnmatchedExample adds an example if under the 5-item cap.
 func (s *TransformStats) addUnmatchedExample(example string) {
 	if len(s.unmatchedExamples) < 5 {
@@ -57,18 +77,41 @@ func (s *TransformStats) addDuplicateExample(example string) {
 	}
 }
 
+// addOverlapExample adds an example if under the 5-item cap.
+func (s *TransformStats) addOverlapExample(example string) {
+	if len(s.overlapExamples) < 5 {
+		s.overlapExamples = append(s.overlapExamples, example)
+	}
+}
+
+// UnmatchedTransaction describes a transaction that no categorization rule matched.
+// Unlike UnmatchedExamples, every unmatched transaction is recorded, for writing
+// full coverage reports.
+type UnmatchedTransaction struct {
+	Date        string  `json:"date"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
 // TransformStatement converts RawStatement to domain types and adds to Budget.
 //
 // Idempotent entities (expected when processing multiple statements from same source):
 //   - Institutions: duplicates silently skipped, tracked in stats
 //   - Accounts: duplicates silently skipped, tracked in stats
 //
+// Statements whose period overlaps one already in the budget for the same account
+// (e.g. the same month downloaded twice under different filenames) are merged: no
+// second statement is added, the transactions are linked to the existing statement,
+// and transactions already in the budget by ID are skipped as duplicates.
+//
 // Non-idempotent entities (duplicates indicate data quality issues):
-//   - Statements: duplicate causes error
-//   - Transactions: duplicate causes error (unless filtered by dedup.State)
+//   - Transactions: duplicate causes error (unless filtered by dedup.State, or
+//     already present in a merged statement)
 //
 // Optional state parameter enables transaction deduplication (nil to disable).
-// Optional engine parameter enables rule-based categorization (nil to disable).
+// Optional engine parameter enables rule-based categorization and institution alias
+// canonicalization (nil to disable).
+// When raw.SourceFile is set, each transaction records it and its line as Source.
 // Returns statistics about the transformation process.
 func TransformStatement(raw *parser.RawStatement, budget *domain.Budget, state *dedup.State, engine *rules.Engine) (*TransformStats, error) {
 	if raw == nil {
@@ -83,11 +126,14 @@ func TransformStatement(raw *parser.RawStatement, budget *domain.Budget, state *
 		duplicateExamples: make([]string, 0, 5),
 	}
 
-	institution, err := transformInstitution(&raw.Account)
+	institution, err := transformInstitution(&raw.Account, engine)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transform institution: %w", err)
 	}
 
+	stats.InstitutionID = institution.ID
+	stats.InstitutionName = institution.Name
+
 	// Add institution (idempotent - silently skip if already exists)
 	if err := budget.AddInstitution(*institution); err != nil {
 		if !errors.Is(err, domain.ErrAlreadyExists) {
@@ -129,26 +175,50 @@ func TransformStatement(raw *parser.RawStatement, budget *domain.Budget, state *
 		return nil, fmt.Errorf("failed to transform statement: %w", err)
 	}
 
-	if err := budget.AddStatement(*statement); err != nil {
-		return nil, fmt.Errorf("failed to add statement: %w", err)
+	statementID := statement.ID
+	existing, merging := findOverlappingStatement(budget, statement)
+	if merging {
+		// Link this statement's transactions to the one already imported rather
+		// than recording a second statement for the same period
+		statementID = existing.ID
+		stats.DuplicateStatementsMerged++
+	} else {
+		if hasStatement(budget, statement.ID) {
+			// Another statement kept for the account starts the same month (one that
+			// partly overlaps this one, or ends when it starts)
+			statement.ID = GenerateDatedStatementID(raw.Period.Start(), account.ID)
+			statementID = statement.ID
+		}
+		if err := budget.AddStatement(*statement); err != nil {
+			return nil, fmt.Errorf("failed to add statement: %w", err)
+		}
 	}
+	mergedDuplicates := 0
 
 	// TODO(#1347): Consider adding benchmark tests for large transaction volumes
 	for i, rawTxn := range raw.Transactions {
 		// Transform basic transaction
-		txn, txnMatched, err := transformTransaction(&rawTxn, statement.ID, engine)
+		txn, match, err := transformTransaction(&rawTxn, statementID, engine)
 		if err != nil {
 			return nil, fmt.Errorf("failed to transform transaction %d/%d (ID: %q, date: %s): %w",
 				i+1, len(raw.Transactions), rawTxn.ID(), rawTxn.Date().Format("2006-01-02"), err)
 		}
+		if raw.SourceFile != "" {
+			txn.Source = &domain.Source{File: raw.SourceFile, Line: rawTxn.Line()}
+		}
 
 		// Track rule matching statistics
 		if engine != nil {
-			if txnMatched {
+			if match != nil {
 				stats.RulesMatched++
 			} else {
 				stats.RulesUnmatched++
 				stats.addUnmatchedExample(txn.Description)
+				stats.unmatched = append(stats.unmatched, UnmatchedTransaction{
+					Date:        txn.Date,
+					Description: txn.Description,
+					Amount:      txn.Amount,
+				})
 			}
 		}
 
@@ -179,14 +249,41 @@ func TransformStatement(raw *parser.RawStatement, budget *domain.Budget, state *
 			}
 		}
 
+		// Without a state file, a merged statement's repeats are caught by their
+		// stable parser ID instead
+		if merging && budget.HasTransaction(txn.ID) {
+			stats.DuplicatesSkipped++
+			mergedDuplicates++
+			stats.addDuplicateExample(
+				fmt.Sprintf("%s: %s (%.2f)", txn.Date, txn.Description, txn.Amount))
+			continue
+		}
+
 		// Add transaction to budget FIRST, before recording in state.
 		// This ordering chooses duplicates over loss: if budget.AddTransaction fails, the
 		// state is unchanged and the transaction can be retried. If we recorded in state first,
 		// a subsequent budget failure would mark the transaction as "seen" even though it wasn't
 		// added, causing permanent loss on retry.
-		if err := budget.AddTransaction(*txn); err != nil {
-			return nil, fmt.Errorf("failed to add transaction %d/%d (ID: %q): %w",
-				i+1, len(raw.Transactions), txn.ID, err)
+		//
+		// A split rule expands the transaction into entries that sum to its amount. The
+		// fingerprint and state record stay with the original, so dedup sees one transaction.
+		entries := []domain.Transaction{*txn}
+		if match != nil && len(match.Splits) > 0 {
+			children, err := expandSplits(txn, match.Splits)
+			if err != nil {
+				return nil, fmt.Errorf("failed to split transaction %d/%d (ID: %q) by rule %q: %w",
+					i+1, len(raw.Transactions), txn.ID, match.RuleName, err)
+			}
+			if children != nil {
+				entries = children
+				stats.TransactionsSplit++
+			}
+		}
+		for _, entry := range entries {
+			if err := budget.AddTransaction(entry); err != nil {
+				return nil, fmt.Errorf("failed to add transaction %d/%d (ID: %q): %w",
+					i+1, len(raw.Transactions), entry.ID, err)
+			}
 		}
 
 		// Record in state AFTER successful budget add (if state provided).
@@ -206,16 +303,88 @@ func TransformStatement(raw *parser.RawStatement, budget *domain.Budget, state *
 		}
 	}
 
+	if merging {
+		source := statement.ID
+		if raw.SourceFile != "" {
+			source = raw.SourceFile
+		}
+		stats.addOverlapExample(fmt.Sprintf("%s (%s..%s) merged into %s (%s..%s): %d of %d transactions already present",
+			source, statement.StartDate, statement.EndDate,
+			existing.ID, existing.StartDate, existing.EndDate,
+			mergedDuplicates, len(raw.Transactions)))
+	}
+
 	return stats, nil
 }
 
+// findOverlappingStatement returns the budget's first statement for stmt's account
+// whose period contains stmt's (including the same period). Any other overlap (e.g.
+// Jan 1-31 and Jan 15-Feb 15, or a new statement wider than the existing one) is kept
+// as a separate statement, like consecutive ones: merging would link transactions to
+// a statement whose period doesn't cover them.
+func findOverlappingStatement(budget *domain.Budget, stmt *domain.Statement) (domain.Statement, bool) {
+	for _, existing := range budget.GetStatements() {
+		if existing.AccountID != stmt.AccountID {
+			continue
+		}
+		// YYYY-MM-DD compares chronologically as a string
+		if existing.StartDate <= stmt.StartDate && stmt.EndDate <= existing.EndDate {
+			return existing, true
+		}
+	}
+	return domain.Statement{}, false
+}
+
+// hasStatement reports whether the budget already has a statement with this ID
+func hasStatement(budget *domain.Budget, id string) bool {
+	for _, existing := range budget.GetStatements() {
+		if existing.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TrackFingerprintCollisions records the fingerprint and detail-hash of every
+// transaction in raw so that fingerprint collisions can be reported.
+// Fingerprints are computed from the same inputs TransformStatement uses, and every
+// transaction is observed regardless of whether dedup would skip it.
+func TrackFingerprintCollisions(raw *parser.RawStatement, tracker *dedup.CollisionTracker) error {
+	if raw == nil {
+		return fmt.Errorf("raw statement cannot be nil")
+	}
+	if tracker == nil {
+		return fmt.Errorf("collision tracker cannot be nil")
+	}
+
+	accountKey := raw.Account.InstitutionName() + "/" + raw.Account.AccountID()
+	for i, rawTxn := range raw.Transactions {
+		date := formatDate(rawTxn.Date())
+		fingerprint := dedup.GenerateFingerprint(date, rawTxn.Amount(), rawTxn.Description())
+		detailHash := dedup.GenerateDetailHash(accountKey, rawTxn.ID(), date, rawTxn.Amount(), rawTxn.Description())
+		example := fmt.Sprintf("%s %s: %s (%.2f) [account %s]",
+			date, rawTxn.ID(), rawTxn.Description(), rawTxn.Amount(), accountKey)
+		if err := tracker.Observe(fingerprint, detailHash, example); err != nil {
+			return fmt.Errorf("failed to track transaction %d/%d (ID: %q): %w",
+				i+1, len(raw.Transactions), rawTxn.ID(), err)
+		}
+	}
+	return nil
+}
+
 // transformInstitution creates a domain Institution from RawAccount
-func transformInstitution(raw *parser.RawAccount) (*domain.Institution, error) {
+func transformInstitution(raw *parser.RawAccount, engine *rules.Engine) (*domain.Institution, error) {
 	name := raw.InstitutionName()
 	if name == "" {
 		return nil, fmt.Errorf("institution name cannot be empty")
 	}
 
+	// Canonicalize aliases ("CHASE", "Chase Bank" -> "Chase") before slugifying so
+	// variants share one institution ID and are deduplicated as the same institution
+	if engine != nil {
+		name = engine.CanonicalInstitution(name)
+	}
+
 	slug, err := SlugifyInstitution(name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to slugify institution name: %w", err)
@@ -275,17 +444,29 @@ func transformStatement(raw *parser.RawStatement, accountID string) (*domain.Sta
 	if err != nil {
 		return nil, fmt.Errorf("failed to create statement: %w", err)
 	}
+
+	// Total every parsed transaction, before dedup, so reconciliation reflects the source file
+	if raw.LedgerBalance != nil {
+		var total float64
+		for _, rawTxn := range raw.Transactions {
+			total += rawTxn.Amount()
+		}
+		statement.SetBalance(domain.StatementBalance{
+			Ledger:      *raw.LedgerBalance,
+			ParsedTotal: total,
+		})
+	}
 	return statement, nil
 }
 
 // transformTransaction creates a domain Transaction from RawTransaction.
 // Optional engine parameter enables rule-based categorization (nil to disable).
-// Returns the transaction, whether a rule matched, and any error.
-func transformTransaction(raw *parser.RawTransaction, statementID string, engine *rules.Engine) (*domain.Transaction, bool, error) {
+// Returns the transaction, the matched rule result (nil if no rule matched), and any error.
+func transformTransaction(raw *parser.RawTransaction, statementID string, engine *rules.Engine) (*domain.Transaction, *rules.MatchResult, error) {
 	// Use existing ID from RawTransaction (stable from parser)
 	txnID := raw.ID()
 	if txnID == "" {
-		return nil, false, fmt.Errorf("transaction ID cannot be empty")
+		return nil, nil, fmt.Errorf("transaction ID cannot be empty")
 	}
 
 	// Format date as YYYY-MM-DD
@@ -293,7 +474,7 @@ func transformTransaction(raw *parser.RawTransaction, statementID string, engine
 
 	description := raw.Description()
 	if description == "" {
-		return nil, false, fmt.Errorf("transaction description cannot be empty (date: %s, amount: %.2f, ID: %s)",
+		return nil, nil, fmt.Errorf("transaction description cannot be empty (date: %s, amount: %.2f, ID: %s)",
 			raw.Date().Format("2006-01-02"), raw.Amount(), raw.ID())
 	}
 
@@ -302,7 +483,7 @@ func transformTransaction(raw *parser.RawTransaction, statementID string, engine
 	// Create transaction with default category
 	txn, err := domain.NewTransaction(txnID, date, description, amount, domain.CategoryOther)
 	if err != nil {
-		return nil, false, err
+		return nil, nil, err
 	}
 
 	// Apply rules if engine provided. Match() performs defensive re-validation when
@@ -315,7 +496,7 @@ func transformTransaction(raw *parser.RawTransaction, statementID string, engine
 		var err error
 		result, matched, err = engine.Match(description)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to apply categorization rules to transaction %q: %w", description, err)
+			return nil, nil, fmt.Errorf("failed to apply categorization rules to transaction %q: %w", description, err)
 		}
 	}
 
@@ -324,19 +505,19 @@ func transformTransaction(raw *parser.RawTransaction, statementID string, engine
 		txn.Category = result.Category
 		txn.SetVacation(result.Vacation)
 		if err := txn.SetTransfer(result.Transfer); err != nil {
-			return nil, false, fmt.Errorf("failed to set transfer flag: %w", err)
+			return nil, nil, fmt.Errorf("failed to set transfer flag: %w", err)
 		}
 		if err := txn.SetRedeemable(result.Redeemable, result.RedemptionRate); err != nil {
-			return nil, false, fmt.Errorf("failed to set redeemable from rule: %w", err)
+			return nil, nil, fmt.Errorf("failed to set redeemable from rule: %w", err)
 		}
 	} else {
 		// No match or no engine: use defaults
 		txn.SetVacation(false)
 		if err := txn.SetTransfer(false); err != nil {
-			return nil, false, err
+			return nil, nil, err
 		}
 		if err := txn.SetRedeemable(false, 0.0); err != nil {
-			return nil, false, err
+			return nil, nil, err
 		}
 	}
 
@@ -344,10 +525,10 @@ func transformTransaction(raw *parser.RawTransaction, statementID string, engine
 
 	// Link transaction to statement
 	if err := txn.AddStatementID(statementID); err != nil {
-		return nil, false, fmt.Errorf("failed to link transaction to statement: %w", err)
+		return nil, nil, fmt.Errorf("failed to link transaction to statement: %w", err)
 	}
 
-	return txn, matched, nil
+	return txn, result, nil
 }
 
 // mapAccountType converts raw account type string to domain AccountType enum
@@ -42,7 +42,7 @@ func TestTransformInstitution(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			inst, err := transformInstitution(tt.rawAccount)
+			inst, err := transformInstitution(tt.rawAccount, nil)
 
 			if tt.expectError {
 				if err == nil {
@@ -173,13 +173,13 @@ func TestTransformTransaction(t *testing.T) {
 
 	statementID := "stmt-2025-10-acc-amex-2011"
 
-	txn, matched, err := transformTransaction(rawTxn, statementID, nil)
+	txn, match, err := transformTransaction(rawTxn, statementID, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	// Verify no rule matched (engine is nil)
-	if matched {
+	if match != nil {
 		t.Errorf("expected no match with nil engine, but matched = true")
 	}
 
@@ -448,11 +448,13 @@ func TestTransformStatementDuplicateHandling(t *testing.T) {
 	period := mustNewPeriod(t, startDate, endDate)
 
 	rawAccount := mustNewRawAccount(t, "PNC", "PNC Bank", "1234", "checking")
+	txnDate := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	rawTxn := mustNewRawTransaction(t, "TXN001", txnDate, txnDate, "Purchase", -25.00)
 
 	raw := &parser.RawStatement{
 		Account:      *rawAccount,
 		Period:       *period,
-		Transactions: []parser.RawTransaction{},
+		Transactions: []parser.RawTransaction{*rawTxn},
 	}
 
 	// Create budget and transform twice
@@ -464,12 +466,16 @@ func TestTransformStatementDuplicateHandling(t *testing.T) {
 		t.Fatalf("first transform failed: %v", err)
 	}
 
-	// Second transform should fail (duplicate statement)
-	_, err = TransformStatement(raw, budget, nil, nil)
-	if err == nil {
-		t.Errorf("expected error for duplicate statement")
-	} else if !strings.Contains(err.Error(), "already exists") {
-		t.Errorf("expected 'already exists' error, got: %v", err)
+	// Second transform merges into the first statement (same period downloaded twice)
+	stats, err := TransformStatement(raw, budget, nil, nil)
+	if err != nil {
+		t.Fatalf("second transform failed: %v", err)
+	}
+	if stats.DuplicateStatementsMerged != 1 {
+		t.Errorf("expected 1 merged statement, got %d", stats.DuplicateStatementsMerged)
+	}
+	if stats.DuplicatesSkipped != 1 {
+		t.Errorf("expected 1 duplicate transaction skipped, got %d", stats.DuplicatesSkipped)
 	}
 
 	// Verify only one set of entities was created
@@ -479,6 +485,192 @@ func TestTransformStatementDuplicateHandling(t *testing.T) {
 	if len(budget.GetAccounts()) != 1 {
 		t.Errorf("expected 1 account, got %d", len(budget.GetAccounts()))
 	}
+	if len(budget.GetStatements()) != 1 {
+		t.Errorf("expected 1 statement, got %d", len(budget.GetStatements()))
+	}
+	if len(budget.GetTransactions()) != 1 {
+		t.Errorf("expected 1 transaction, got %d", len(budget.GetTransactions()))
+	}
+}
+
+func TestTransformStatement_OverlappingPeriods(t *testing.T) {
+	rawAccount := mustNewRawAccount(t, "AMEX", "American Express", "2011", "credit")
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	statement := func(start, end time.Time, file string, txns ...parser.RawTransaction) *parser.RawStatement {
+		return &parser.RawStatement{
+			Account:      *rawAccount,
+			Period:       *mustNewPeriod(t, start, end),
+			Transactions: txns,
+			SourceFile:   file,
+		}
+	}
+	shared := *mustNewRawTransaction(t, "TXN002", date(10, 20), date(10, 20), "Coffee", -4.50)
+
+	budget := domain.NewBudget()
+	first := statement(date(10, 1), date(10, 31), "october.qfx",
+		*mustNewRawTransaction(t, "TXN001", date(10, 5), date(10, 5), "Groceries", -80.00), shared)
+	if _, err := TransformStatement(first, budget, nil, nil); err != nil {
+		t.Fatalf("first transform failed: %v", err)
+	}
+
+	// Part of the same month downloaded again under another name
+	second := statement(date(10, 3), date(10, 31), "october (1).qfx",
+		shared, *mustNewRawTransaction(t, "TXN003", date(10, 30), date(10, 30), "Gas", -40.00))
+	stats, err := TransformStatement(second, budget, nil, nil)
+	if err != nil {
+		t.Fatalf("overlapping transform failed: %v", err)
+	}
+	if stats.DuplicateStatementsMerged != 1 {
+		t.Errorf("DuplicateStatementsMerged = %d, want 1", stats.DuplicateStatementsMerged)
+	}
+	if stats.DuplicatesSkipped != 1 {
+		t.Errorf("DuplicatesSkipped = %d, want 1", stats.DuplicatesSkipped)
+	}
+	wantExample := "october (1).qfx (2025-10-03..2025-10-31) merged into stmt-2025-10-acc-amex-2011 (2025-10-01..2025-10-31): 1 of 2 transactions already present"
+	if examples := stats.OverlapExamples(); len(examples) != 1 || examples[0] != wantExample {
+		t.Errorf("OverlapExamples() = %q, want [%q]", examples, wantExample)
+	}
+
+	statements := budget.GetStatements()
+	if len(statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(statements))
+	}
+	txns := budget.GetTransactions()
+	if len(txns) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(txns))
+	}
+	if got := txns[2].GetStatementIDs(); len(got) != 1 || got[0] != statements[0].ID {
+		t.Errorf("merged transaction statementIds = %v, want [%s]", got, statements[0].ID)
+	}
+
+	// A statement starting the day the last one ended is the next statement, not a duplicate
+	cycleAccount := *mustNewRawAccount(t, "AMEX", "American Express", "5555", "credit")
+	for _, period := range [][2]time.Time{{date(9, 15), date(10, 15)}, {date(10, 15), date(11, 15)}} {
+		consecutive := &parser.RawStatement{Account: cycleAccount, Period: *mustNewPeriod(t, period[0], period[1])}
+		stats, err = TransformStatement(consecutive, budget, nil, nil)
+		if err != nil {
+			t.Fatalf("consecutive transform failed: %v", err)
+		}
+		if stats.DuplicateStatementsMerged != 0 {
+			t.Errorf("consecutive statement DuplicateStatementsMerged = %d, want 0", stats.DuplicateStatementsMerged)
+		}
+	}
+
+	// Overlap is per account
+	other := &parser.RawStatement{
+		Account: *mustNewRawAccount(t, "AMEX", "American Express", "9999", "credit"),
+		Period:  *mustNewPeriod(t, date(10, 1), date(10, 31)),
+	}
+	stats, err = TransformStatement(other, budget, nil, nil)
+	if err != nil {
+		t.Fatalf("other account transform failed: %v", err)
+	}
+	if stats.DuplicateStatementsMerged != 0 {
+		t.Errorf("other account DuplicateStatementsMerged = %d, want 0", stats.DuplicateStatementsMerged)
+	}
+	if len(budget.GetStatements()) != 4 {
+		t.Errorf("expected 4 statements, got %d", len(budget.GetStatements()))
+	}
+}
+
+// TestTransformStatement_PartialOverlap checks that a statement only partly
+// overlapping an earlier one keeps its own record, so the transactions outside the
+// earlier period are linked to a statement that covers them.
+func TestTransformStatement_PartialOverlap(t *testing.T) {
+	rawAccount := mustNewRawAccount(t, "AMEX", "American Express", "2011", "credit")
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	budget := domain.NewBudget()
+	january := &parser.RawStatement{
+		Account: *rawAccount,
+		Period:  *mustNewPeriod(t, date(1, 1), date(1, 31)),
+		Transactions: []parser.RawTransaction{
+			*mustNewRawTransaction(t, "TXN001", date(1, 20), date(1, 20), "Coffee", -4.50),
+		},
+	}
+	if _, err := TransformStatement(january, budget, nil, nil); err != nil {
+		t.Fatalf("first transform failed: %v", err)
+	}
+
+	midMonth := &parser.RawStatement{
+		Account: *rawAccount,
+		Period:  *mustNewPeriod(t, date(1, 15), date(2, 15)),
+		Transactions: []parser.RawTransaction{
+			*mustNewRawTransaction(t, "TXN002", date(2, 10), date(2, 10), "Gas", -40.00),
+		},
+	}
+	stats, err := TransformStatement(midMonth, budget, nil, nil)
+	if err != nil {
+		t.Fatalf("partially overlapping transform failed: %v", err)
+	}
+	if stats.DuplicateStatementsMerged != 0 {
+		t.Errorf("DuplicateStatementsMerged = %d, want 0", stats.DuplicateStatementsMerged)
+	}
+
+	statements := budget.GetStatements()
+	if len(statements) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(statements))
+	}
+	txns := budget.GetTransactions()
+	if len(txns) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txns))
+	}
+	if statements[1].ID != "stmt-2025-01-15-acc-amex-2011" {
+		t.Errorf("second statement ID = %q, want the start day in it", statements[1].ID)
+	}
+	if got := txns[1].GetStatementIDs(); len(got) != 1 || got[0] != statements[1].ID {
+		t.Errorf("February transaction statementIds = %v, want [%s]", got, statements[1].ID)
+	}
+}
+
+// TestTransformStatement_WiderStatement checks that a statement whose period contains
+// an earlier, narrower one is kept rather than merged into it, since the narrower
+// period doesn't cover its transactions.
+func TestTransformStatement_WiderStatement(t *testing.T) {
+	rawAccount := mustNewRawAccount(t, "AMEX", "American Express", "2011", "credit")
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	budget := domain.NewBudget()
+	narrow := &parser.RawStatement{
+		Account: *rawAccount,
+		Period:  *mustNewPeriod(t, date(1, 10), date(1, 20)),
+		Transactions: []parser.RawTransaction{
+			*mustNewRawTransaction(t, "TXN001", date(1, 15), date(1, 15), "Coffee", -4.50),
+		},
+	}
+	if _, err := TransformStatement(narrow, budget, nil, nil); err != nil {
+		t.Fatalf("first transform failed: %v", err)
+	}
+
+	wide := &parser.RawStatement{
+		Account: *rawAccount,
+		Period:  *mustNewPeriod(t, date(1, 1), date(2, 28)),
+		Transactions: []parser.RawTransaction{
+			*mustNewRawTransaction(t, "TXN002", date(2, 10), date(2, 10), "Gas", -40.00),
+		},
+	}
+	stats, err := TransformStatement(wide, budget, nil, nil)
+	if err != nil {
+		t.Fatalf("wider transform failed: %v", err)
+	}
+	if stats.DuplicateStatementsMerged != 0 {
+		t.Errorf("DuplicateStatementsMerged = %d, want 0", stats.DuplicateStatementsMerged)
+	}
+
+	statements := budget.GetStatements()
+	if len(statements) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(statements))
+	}
+	txns := budget.GetTransactions()
+	if got := txns[1].GetStatementIDs(); len(got) != 1 || got[0] != statements[1].ID {
+		t.Errorf("February transaction statementIds = %v, want [%s]", got, statements[1].ID)
+	}
 }
 
 func TestTransformStatement_NilBudget(t *testing.T) {
@@ -626,13 +818,13 @@ func TestTransformTransaction_UnmatchedDefaultsToOther(t *testing.T) {
 
 	statementID := "stmt-2025-10-acc-amex-2011"
 
-	txn, matched, err := transformTransaction(rawTxn, statementID, engine)
+	txn, match, err := transformTransaction(rawTxn, statementID, engine)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	// Verify no rule matched
-	if matched {
+	if match != nil {
 		t.Errorf("expected no rule match, but matched = true")
 	}
 
@@ -800,6 +992,48 @@ func TestTransformStatement_StatsWithNilEngine(t *testing.T) {
 	}
 }
 
+func TestTransformStatement_UnmatchedUncapped(t *testing.T) {
+	startDate := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	period := mustNewPeriod(t, startDate, startDate.AddDate(0, 1, 0))
+	rawAccount := mustNewRawAccount(t, "AMEX", "American Express", "2011", "credit")
+
+	var txns []parser.RawTransaction
+	for i := 0; i < 8; i++ {
+		txn := mustNewRawTransaction(t, fmt.Sprintf("TXN%03d", i), startDate.AddDate(0, 0, i), startDate,
+			fmt.Sprintf("UNKNOWN_XYZ_NOMATCH %d", i), -float64(i+1))
+		txns = append(txns, *txn)
+	}
+	raw := &parser.RawStatement{Account: *rawAccount, Period: *period, Transactions: txns}
+
+	engine, err := rules.LoadEmbedded()
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+
+	stats, err := TransformStatement(raw, domain.NewBudget(), nil, engine)
+	if err != nil {
+		t.Fatalf("TransformStatement failed: %v", err)
+	}
+
+	if len(stats.UnmatchedExamples()) != 5 {
+		t.Errorf("Expected examples capped at 5, got %d", len(stats.UnmatchedExamples()))
+	}
+	unmatched := stats.Unmatched()
+	if len(unmatched) != 8 {
+		t.Fatalf("Expected all 8 unmatched transactions, got %d", len(unmatched))
+	}
+	want := UnmatchedTransaction{Date: "2025-10-08", Description: "UNKNOWN_XYZ_NOMATCH 7", Amount: -8}
+	if unmatched[7] != want {
+		t.Errorf("Expected last unmatched %+v, got %+v", want, unmatched[7])
+	}
+
+	// Returned slice is a copy
+	unmatched[0].Description = "modified"
+	if stats.Unmatched()[0].Description == "modified" {
+		t.Error("Unmatched should return a defensive copy")
+	}
+}
+
 func TestTransformStatement_DedupWithoutRules(t *testing.T) {
 	startDate := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
 	period := mustNewPeriod(t, startDate, startDate.AddDate(0, 1, 0))
@@ -967,3 +1201,183 @@ func mustNewRawTransaction(t *testing.T, id string, date, postedDate time.Time,
 	}
 	return txn
 }
+
+func TestTrackFingerprintCollisions(t *testing.T) {
+	period := mustNewPeriod(t,
+		time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 31, 23, 59, 59, 0, time.UTC))
+	rawAccount := mustNewRawAccount(t, "AMEX", "American Express", "2011", "credit")
+
+	txnDate := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	// Two distinct coffees on the same day share a fingerprint
+	rawTxn1 := mustNewRawTransaction(t, "TXN001", txnDate, txnDate, "Coffee Shop", -5.00)
+	rawTxn2 := mustNewRawTransaction(t, "TXN002", txnDate, txnDate, "COFFEE SHOP", -5.00)
+	rawTxn3 := mustNewRawTransaction(t, "TXN003", txnDate, txnDate, "Groceries", -42.00)
+
+	raw := &parser.RawStatement{
+		Account:      *rawAccount,
+		Period:       *period,
+		Transactions: []parser.RawTransaction{*rawTxn1, *rawTxn2, *rawTxn3},
+	}
+
+	tracker := dedup.NewCollisionTracker()
+	if err := TrackFingerprintCollisions(raw, tracker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Re-tracking the same statement must not create new collisions
+	if err := TrackFingerprintCollisions(raw, tracker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	collisions := tracker.Collisions()
+	if len(collisions) != 1 {
+		t.Fatalf("expected 1 collision, got %d", len(collisions))
+	}
+	if len(collisions[0].Examples) != 2 {
+		t.Errorf("expected 2 examples, got %v", collisions[0].Examples)
+	}
+	if tracker.TotalFingerprints() != 2 {
+		t.Errorf("expected 2 fingerprints, got %d", tracker.TotalFingerprints())
+	}
+
+	if err := TrackFingerprintCollisions(nil, tracker); err == nil {
+		t.Error("expected error for nil statement")
+	}
+	if err := TrackFingerprintCollisions(raw, nil); err == nil {
+		t.Error("expected error for nil tracker")
+	}
+}
+
+func TestTransformStatement_LedgerBalance(t *testing.T) {
+	period := mustNewPeriod(t,
+		time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 31, 23, 59, 59, 0, time.UTC))
+	rawAccount := mustNewRawAccount(t, "BANK", "Test Bank", "1234", "checking")
+
+	txnDate := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	rawTxn1 := mustNewRawTransaction(t, "TXN001", txnDate, txnDate, "Deposit", 100.00)
+	rawTxn2 := mustNewRawTransaction(t, "TXN002", txnDate, txnDate, "Purchase", -30.25)
+
+	ledger := 1069.75
+	raw := &parser.RawStatement{
+		Account:       *rawAccount,
+		Period:        *period,
+		Transactions:  []parser.RawTransaction{*rawTxn1, *rawTxn2},
+		LedgerBalance: &ledger,
+	}
+
+	// Pre-seed state so one transaction is skipped; the parsed total must still include it
+	state := dedup.NewState()
+	fp := dedup.GenerateFingerprint(formatDate(txnDate), -30.25, "Purchase")
+	if err := state.RecordTransaction(fp, "TXN002", time.Now()); err != nil {
+		t.Fatalf("failed to seed state: %v", err)
+	}
+
+	budget := domain.NewBudget()
+	if _, err := TransformStatement(raw, budget, state, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statements := budget.GetStatements()
+	if len(statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(statements))
+	}
+	balance, ok := statements[0].Balance()
+	if !ok {
+		t.Fatal("expected statement balance to be recorded")
+	}
+	if balance.Ledger != 1069.75 {
+		t.Errorf("expected ledger %f, got %f", 1069.75, balance.Ledger)
+	}
+	if balance.ParsedTotal != 69.75 {
+		t.Errorf("expected parsed total %f, got %f", 69.75, balance.ParsedTotal)
+	}
+}
+
+func TestTransformStatement_InstitutionAliases(t *testing.T) {
+	engine, err := rules.NewEngine([]byte(`
+rules: []
+institution_aliases:
+  Chase:
+    - CHASE
+    - Chase Bank
+    - JPMORGAN CHASE
+`))
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	startDate := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	period := mustNewPeriod(t, startDate, startDate.AddDate(0, 1, 0))
+	budget := domain.NewBudget()
+
+	var skipped int
+	for i, name := range []string{"CHASE", "Chase Bank", "JPMORGAN CHASE", "PNC"} {
+		rawAccount := mustNewRawAccount(t, "INST", name, fmt.Sprintf("100%d", i), "checking")
+		txn := mustNewRawTransaction(t, fmt.Sprintf("TXN%d", i), startDate, startDate, "Purchase", -1)
+		raw := &parser.RawStatement{Account: *rawAccount, Period: *period, Transactions: []parser.RawTransaction{*txn}}
+
+		stats, err := TransformStatement(raw, budget, nil, engine)
+		if err != nil {
+			t.Fatalf("TransformStatement(%s) failed: %v", name, err)
+		}
+		skipped += stats.DuplicateInstitutionsSkipped
+	}
+
+	institutions := budget.GetInstitutions()
+	if len(institutions) != 2 {
+		t.Fatalf("Expected aliases to collapse into 2 institutions, got %d: %+v", len(institutions), institutions)
+	}
+	names := map[string]bool{}
+	for _, inst := range institutions {
+		names[inst.Name] = true
+	}
+	if !names["Chase"] || !names["PNC"] {
+		t.Errorf("Expected canonical Chase and unchanged PNC, got %v", names)
+	}
+	if skipped != 2 {
+		t.Errorf("Expected 2 alias institutions deduplicated, got %d", skipped)
+	}
+	for _, acc := range budget.GetAccounts() {
+		if acc.InstitutionID != "chase" && acc.InstitutionID != "pnc" {
+			t.Errorf("Account %s references non-canonical institution %q", acc.ID, acc.InstitutionID)
+		}
+	}
+}
+
+func TestTransformStatement_Source(t *testing.T) {
+	period := mustNewPeriod(t,
+		time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 31, 23, 59, 59, 0, time.UTC))
+	rawAccount := mustNewRawAccount(t, "BANK", "Test Bank", "1234", "checking")
+	txnDate := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	rawTxn := mustNewRawTransaction(t, "TXN001", txnDate, txnDate, "Deposit", 100.00)
+	rawTxn.SetLine(7)
+
+	tests := []struct {
+		name       string
+		sourceFile string
+		want       *domain.Source
+	}{
+		{"untraced", "", nil},
+		{"traced", "/statements/bank/1234/oct.csv", &domain.Source{File: "/statements/bank/1234/oct.csv", Line: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := &parser.RawStatement{
+				Account:      *rawAccount,
+				Period:       *period,
+				Transactions: []parser.RawTransaction{*rawTxn},
+				SourceFile:   tt.sourceFile,
+			}
+			budget := domain.NewBudget()
+			if _, err := TransformStatement(raw, budget, nil, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := budget.GetTransactions()[0].Source
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Source = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
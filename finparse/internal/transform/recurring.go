@@ -0,0 +1,138 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+// minRecurringOccurrences is the fewest occurrences needed before a charge is
+// considered recurring. Two occurrences establish an interval but not a pattern.
+const minRecurringOccurrences = 3
+
+// cadenceWindow is the inclusive range of days between occurrences accepted for a cadence.
+// Monthly allows 27-34 days to absorb month-length variation and weekend/holiday
+// posting shifts (e.g. Jan 31 -> Feb 28 -> Apr 1).
+type cadenceWindow struct {
+	cadence domain.RecurringCadence
+	minDays int
+	maxDays int
+}
+
+var cadenceWindows = []cadenceWindow{
+	{cadence: domain.RecurringCadenceWeekly, minDays: 6, maxDays: 8},
+	{cadence: domain.RecurringCadenceBiweekly, minDays: 13, maxDays: 15},
+	{cadence: domain.RecurringCadenceMonthly, minDays: 27, maxDays: 34},
+}
+
+// DetectRecurring finds likely subscriptions and other recurring charges in budget
+// and stores them as the budget's recurring section.
+//
+// Transactions are grouped by normalized description (case and whitespace
+// insensitive) and exact amount. A group is recurring when it has at least
+// minRecurringOccurrences distinct dates and every gap between consecutive dates
+// falls within the same cadence window. Transactions themselves are never modified,
+// so dedup and categorization are unaffected.
+//
+// Returns the detected charges sorted by merchant, then amount.
+func DetectRecurring(budget *domain.Budget) ([]domain.RecurringCharge, error) {
+	if budget == nil {
+		return nil, fmt.Errorf("budget cannot be nil")
+	}
+
+	type group struct {
+		merchant string // Description of the first occurrence, as it appeared
+		amount   float64
+		dates    map[string]time.Time
+	}
+	groups := make(map[string]*group)
+	var keys []string
+
+	for _, txn := range budget.GetTransactions() {
+		date, err := time.Parse("2006-01-02", txn.Date)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s has invalid date %q: %w", txn.ID, txn.Date, err)
+		}
+
+		key := fmt.Sprintf("%s|%.2f", normalizeMerchant(txn.Description), txn.Amount)
+		g, exists := groups[key]
+		if !exists {
+			g = &group{
+				merchant: strings.TrimSpace(txn.Description),
+				amount:   txn.Amount,
+				dates:    make(map[string]time.Time),
+			}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.dates[txn.Date] = date
+	}
+
+	var recurring []domain.RecurringCharge
+	for _, key := range keys {
+		g := groups[key]
+		if len(g.dates) < minRecurringOccurrences {
+			continue
+		}
+
+		dates := make([]time.Time, 0, len(g.dates))
+		for _, d := range g.dates {
+			dates = append(dates, d)
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+		cadence, ok := estimateCadence(dates)
+		if !ok {
+			continue
+		}
+
+		formatted := make([]string, len(dates))
+		for i, d := range dates {
+			formatted[i] = formatDate(d)
+		}
+		recurring = append(recurring, domain.RecurringCharge{
+			Merchant: g.merchant,
+			Amount:   g.amount,
+			Cadence:  cadence,
+			Dates:    formatted,
+		})
+	}
+
+	sort.Slice(recurring, func(i, j int) bool {
+		if recurring[i].Merchant != recurring[j].Merchant {
+			return recurring[i].Merchant < recurring[j].Merchant
+		}
+		return recurring[i].Amount < recurring[j].Amount
+	})
+
+	budget.SetRecurring(recurring)
+	return recurring, nil
+}
+
+// estimateCadence returns the cadence whose window contains every gap between
+// consecutive sorted dates, or false if the gaps are irregular.
+func estimateCadence(dates []time.Time) (domain.RecurringCadence, bool) {
+	for _, window := range cadenceWindows {
+		matches := true
+		for i := 1; i < len(dates); i++ {
+			days := int(dates[i].Sub(dates[i-1]).Hours() / 24)
+			if days < window.minDays || days > window.maxDays {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return window.cadence, true
+		}
+	}
+	return "", false
+}
+
+// normalizeMerchant lowercases a description and collapses internal whitespace
+// so formatting differences between statements do not split a group.
+func normalizeMerchant(description string) string {
+	return strings.Join(strings.Fields(strings.ToLower(description)), " ")
+}
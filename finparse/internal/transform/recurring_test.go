@@ -0,0 +1,137 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+)
+
+func mustAddTransaction(t *testing.T, budget *domain.Budget, id, date, description string, amount float64) {
+	t.Helper()
+	txn, err := domain.NewTransaction(id, date, description, amount, domain.CategoryOther)
+	if err != nil {
+		t.Fatalf("failed to create transaction: %v", err)
+	}
+	if err := budget.AddTransaction(*txn); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+}
+
+func TestDetectRecurring(t *testing.T) {
+	tests := []struct {
+		name        string
+		dates       []string
+		wantCadence domain.RecurringCadence
+		wantFound   bool
+	}{
+		{
+			name:        "monthly across month lengths",
+			dates:       []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"},
+			wantCadence: domain.RecurringCadenceMonthly,
+			wantFound:   true,
+		},
+		{
+			name:        "weekly",
+			dates:       []string{"2025-01-06", "2025-01-13", "2025-01-21"},
+			wantCadence: domain.RecurringCadenceWeekly,
+			wantFound:   true,
+		},
+		{
+			name:        "biweekly",
+			dates:       []string{"2025-01-03", "2025-01-17", "2025-01-31"},
+			wantCadence: domain.RecurringCadenceBiweekly,
+			wantFound:   true,
+		},
+		{
+			name:      "too few occurrences",
+			dates:     []string{"2025-01-15", "2025-02-15"},
+			wantFound: false,
+		},
+		{
+			name:      "irregular gaps",
+			dates:     []string{"2025-01-15", "2025-02-15", "2025-02-20"},
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := domain.NewBudget()
+			for i, date := range tt.dates {
+				mustAddTransaction(t, budget, fmt.Sprintf("txn-%d", i), date, "Streaming Service", -15.99)
+			}
+
+			recurring, err := DetectRecurring(budget)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantFound {
+				if len(recurring) != 0 {
+					t.Errorf("expected no recurring charges, got %+v", recurring)
+				}
+				return
+			}
+			if len(recurring) != 1 {
+				t.Fatalf("expected 1 recurring charge, got %d", len(recurring))
+			}
+			if recurring[0].Cadence != tt.wantCadence {
+				t.Errorf("expected cadence %q, got %q", tt.wantCadence, recurring[0].Cadence)
+			}
+			if !reflect.DeepEqual(recurring[0].Dates, tt.dates) {
+				t.Errorf("expected dates %v, got %v", tt.dates, recurring[0].Dates)
+			}
+		})
+	}
+}
+
+func TestDetectRecurring_GroupsByNormalizedDescriptionAndAmount(t *testing.T) {
+	budget := domain.NewBudget()
+	// Formatting differences between statements share a group
+	mustAddTransaction(t, budget, "txn-1", "2025-03-01", "NETFLIX.COM", -15.49)
+	mustAddTransaction(t, budget, "txn-2", "2025-04-01", "Netflix.com ", -15.49)
+	mustAddTransaction(t, budget, "txn-3", "2025-05-01", "netflix.com", -15.49)
+	// Same merchant at a different amount is a separate group
+	mustAddTransaction(t, budget, "txn-4", "2025-06-01", "NETFLIX.COM", -17.99)
+
+	recurring, err := DetectRecurring(budget)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recurring) != 1 {
+		t.Fatalf("expected 1 recurring charge, got %d", len(recurring))
+	}
+	if recurring[0].Merchant != "NETFLIX.COM" {
+		t.Errorf("expected merchant %q, got %q", "NETFLIX.COM", recurring[0].Merchant)
+	}
+	if recurring[0].Amount != -15.49 {
+		t.Errorf("expected amount %f, got %f", -15.49, recurring[0].Amount)
+	}
+	if !reflect.DeepEqual(budget.GetRecurring(), recurring) {
+		t.Errorf("expected budget recurring section to match result")
+	}
+}
+
+func TestDetectRecurring_DoesNotModifyTransactions(t *testing.T) {
+	budget := domain.NewBudget()
+	for i, date := range []string{"2025-01-10", "2025-02-10", "2025-03-10"} {
+		mustAddTransaction(t, budget, fmt.Sprintf("txn-%d", i), date, "Gym Membership", -40.00)
+	}
+	before := budget.GetTransactions()
+
+	if _, err := DetectRecurring(budget); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(before, budget.GetTransactions()) {
+		t.Error("expected transactions to be unchanged by recurring detection")
+	}
+}
+
+func TestDetectRecurring_NilBudget(t *testing.T) {
+	if _, err := DetectRecurring(nil); err == nil {
+		t.Error("expected error for nil budget")
+	}
+}
@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+	"github.com/rumor-ml/commons.systems/finparse/internal/rules"
+)
+
+// expandSplits divides txn into one entry per split rule share. Entries keep the
+// parent's date, description, flags and statements, take the split's category,
+// and reference the parent via SplitOf.
+//
+// Amounts are allocated in whole cents so the entries sum exactly to the parent:
+// fixed amounts are taken first, percentage shares are rounded down, and the last
+// percentage share absorbs the rounding remainder. Zero-cent shares are omitted.
+//
+// Returns nil (no split, the transaction keeps the rule's category) when the
+// fixed amounts exceed the transaction amount.
+func expandSplits(txn *domain.Transaction, splits []rules.Split) ([]domain.Transaction, error) {
+	if err := rules.ValidateSplits(splits); err != nil {
+		return nil, err
+	}
+
+	parentCents := toCents(txn.Amount)
+	sign := int64(1)
+	if parentCents < 0 {
+		sign = -1
+	}
+	total := parentCents * sign
+
+	shares := make([]int64, len(splits))
+	remainder := total
+	lastPercent := -1
+	for i, split := range splits {
+		if split.Amount > 0 {
+			shares[i] = toCents(split.Amount)
+			remainder -= shares[i]
+		} else {
+			lastPercent = i
+		}
+	}
+	if remainder < 0 {
+		return nil, nil
+	}
+
+	allocated := int64(0)
+	for i, split := range splits {
+		if split.Percent == 0 || i == lastPercent {
+			continue
+		}
+		shares[i] = int64(math.Floor(float64(remainder) * split.Percent / 100))
+		allocated += shares[i]
+	}
+	shares[lastPercent] = remainder - allocated
+
+	children := make([]domain.Transaction, 0, len(splits))
+	for i, split := range splits {
+		if shares[i] == 0 {
+			continue
+		}
+		child, err := newSplitChild(txn, fmt.Sprintf("%s-split-%d", txn.ID, i+1),
+			domain.Category(split.Category), float64(shares[i]*sign)/100)
+		if err != nil {
+			return nil, fmt.Errorf("split %d: %w", i+1, err)
+		}
+		children = append(children, *child)
+	}
+	return children, nil
+}
+
+// newSplitChild creates one split entry of parent with its own statement ID slice.
+func newSplitChild(parent *domain.Transaction, id string, category domain.Category, amount float64) (*domain.Transaction, error) {
+	child, err := domain.NewTransaction(id, parent.Date, parent.Description, amount, category)
+	if err != nil {
+		return nil, err
+	}
+	child.SetVacation(parent.Vacation())
+	if err := child.SetTransfer(parent.Transfer()); err != nil {
+		return nil, err
+	}
+	if err := child.SetRedeemable(parent.Redeemable(), parent.RedemptionRate()); err != nil {
+		return nil, err
+	}
+	for _, stmtID := range parent.GetStatementIDs() {
+		if err := child.AddStatementID(stmtID); err != nil {
+			return nil, err
+		}
+	}
+	child.SplitOf = &domain.SplitOf{ParentID: parent.ID, ParentAmount: parent.Amount}
+	child.Source = parent.Source
+	return child, nil
+}
+
+// toCents converts a dollar amount to whole cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,161 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/dedup"
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/rules"
+)
+
+func TestExpandSplits(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		splits []rules.Split
+		want   []float64 // nil = not split
+	}{
+		{
+			name:   "percentages",
+			amount: -100,
+			splits: []rules.Split{{Category: "groceries", Percent: 70}, {Category: "shopping", Percent: 30}},
+			want:   []float64{-70, -30},
+		},
+		{
+			name:   "last percentage absorbs rounding",
+			amount: -10.01,
+			splits: []rules.Split{{Category: "groceries", Percent: 33.3}, {Category: "shopping", Percent: 33.3}, {Category: "other", Percent: 33.4}},
+			want:   []float64{-3.33, -3.33, -3.35},
+		},
+		{
+			name:   "fixed amount first, remainder by percent",
+			amount: -120.5,
+			splits: []rules.Split{{Category: "shopping", Amount: 20}, {Category: "groceries", Percent: 100}},
+			want:   []float64{-20, -100.5},
+		},
+		{
+			name:   "income keeps its sign",
+			amount: 50,
+			splits: []rules.Split{{Category: "income", Percent: 50}, {Category: "other", Percent: 50}},
+			want:   []float64{25, 25},
+		},
+		{
+			name:   "fixed amount exceeds transaction",
+			amount: -10,
+			splits: []rules.Split{{Category: "shopping", Amount: 20}, {Category: "groceries", Percent: 100}},
+			want:   nil,
+		},
+		{
+			name:   "zero share omitted",
+			amount: -20,
+			splits: []rules.Split{{Category: "shopping", Amount: 20}, {Category: "groceries", Percent: 100}},
+			want:   []float64{-20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, err := domain.NewTransaction("TXN1", "2025-10-01", "COSTCO WHSE", tt.amount, domain.CategoryGroceries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := parent.AddStatementID("stmt1"); err != nil {
+				t.Fatal(err)
+			}
+
+			children, err := expandSplits(parent, tt.splits)
+			if err != nil {
+				t.Fatalf("expandSplits failed: %v", err)
+			}
+			if tt.want == nil {
+				if children != nil {
+					t.Errorf("Expected no split, got %d entries", len(children))
+				}
+				return
+			}
+			if len(children) != len(tt.want) {
+				t.Fatalf("Expected %d entries, got %d", len(tt.want), len(children))
+			}
+
+			var sumCents int64
+			for i, child := range children {
+				if toCents(child.Amount) != toCents(tt.want[i]) {
+					t.Errorf("entry %d: expected amount %.2f, got %.2f", i, tt.want[i], child.Amount)
+				}
+				if child.SplitOf == nil || child.SplitOf.ParentID != "TXN1" || child.SplitOf.ParentAmount != tt.amount {
+					t.Errorf("entry %d: expected SplitOf TXN1/%.2f, got %+v", i, tt.amount, child.SplitOf)
+				}
+				if ids := child.GetStatementIDs(); len(ids) != 1 || ids[0] != "stmt1" {
+					t.Errorf("entry %d: expected statement stmt1, got %v", i, ids)
+				}
+				sumCents += toCents(child.Amount)
+			}
+			if sumCents != toCents(tt.amount) {
+				t.Errorf("Entries sum to %d cents, expected %d", sumCents, toCents(tt.amount))
+			}
+		})
+	}
+}
+
+func TestTransformStatement_SplitRule(t *testing.T) {
+	engine, err := rules.NewEngine([]byte(`
+rules:
+  - name: "Costco"
+    pattern: "COSTCO"
+    match_type: "contains"
+    priority: 400
+    category: "groceries"
+    splits:
+      - {category: "groceries", percent: 70}
+      - {category: "shopping", percent: 30}
+`))
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	date := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	raw := &parser.RawStatement{
+		Account: *mustNewRawAccount(t, "AMEX", "American Express", "2011", "credit"),
+		Period:  *mustNewPeriod(t, date.AddDate(0, 0, -4), date.AddDate(0, 0, 20)),
+		Transactions: []parser.RawTransaction{
+			*mustNewRawTransaction(t, "TXN001", date, date, "COSTCO WHSE #123", -200),
+		},
+	}
+
+	state := dedup.NewState()
+	budget := domain.NewBudget()
+	stats, err := TransformStatement(raw, budget, state, engine)
+	if err != nil {
+		t.Fatalf("TransformStatement failed: %v", err)
+	}
+	if stats.TransactionsSplit != 1 {
+		t.Errorf("Expected 1 transaction split, got %d", stats.TransactionsSplit)
+	}
+
+	txns := budget.GetTransactions()
+	if len(txns) != 2 {
+		t.Fatalf("Expected 2 split entries, got %d", len(txns))
+	}
+	if txns[0].Category != domain.CategoryGroceries || txns[0].Amount != -140 ||
+		txns[1].Category != domain.CategoryShopping || txns[1].Amount != -60 {
+		t.Errorf("Unexpected split entries: %+v / %+v", txns[0], txns[1])
+	}
+
+	// Dedup sees the original as a single fingerprint, recorded under the parent ID
+	if state.TotalFingerprints() != 1 {
+		t.Errorf("Expected 1 fingerprint for the split transaction, got %d", state.TotalFingerprints())
+	}
+
+	// A second run skips the original once, adding none of its split entries
+	rerun := domain.NewBudget()
+	stats, err = TransformStatement(raw, rerun, state, engine)
+	if err != nil {
+		t.Fatalf("Second TransformStatement failed: %v", err)
+	}
+	if stats.DuplicatesSkipped != 1 || rerun.TransactionCount() != 0 {
+		t.Errorf("Expected split transaction skipped as 1 duplicate, got skipped=%d added=%d",
+			stats.DuplicatesSkipped, rerun.TransactionCount())
+	}
+}
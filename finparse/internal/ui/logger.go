@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Output formats accepted by NewLogger.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Level identifies the kind of UI event.
+type Level string
+
+const (
+	LevelHeader  Level = "header"
+	LevelStep    Level = "step"
+	LevelSuccess Level = "success"
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelEvent   Level = "event"
+)
+
+// Entry is a single UI event.
+type Entry struct {
+	Level   Level
+	Message string
+
+	// Step and Total are set for LevelStep.
+	Step  int
+	Total int
+
+	// Name and Fields are set for LevelEvent (machine-readable data such as file
+	// counts and coverage percentages).
+	Name   string
+	Fields map[string]any
+}
+
+// Logger receives every UI event. Header, Step, Success, Info, Warning, Error and
+// Event all route through the active logger, so the human and JSON formats are
+// driven by the same call sites and cannot drift apart.
+type Logger interface {
+	Log(e Entry)
+}
+
+var (
+	activeMu sync.RWMutex
+	active   Logger = TextLogger{}
+)
+
+// SetLogger installs l as the active logger and returns the previous one.
+func SetLogger(l Logger) Logger {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	prev := active
+	active = l
+	return prev
+}
+
+// IsJSON reports whether the active logger emits JSON. Callers use it to suppress
+// free-form output (progress bars, verbose dumps) that would corrupt the stream.
+func IsJSON() bool {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	_, ok := active.(*JSONLogger)
+	return ok
+}
+
+func emit(e Entry) {
+	activeMu.RLock()
+	l := active
+	activeMu.RUnlock()
+	l.Log(e)
+}
+
+// NewLogger returns the logger for format. JSON output is written to w; text
+// output keeps the terminal defaults of the package-level print functions.
+func NewLogger(format string, w io.Writer) (Logger, error) {
+	switch format {
+	case FormatText:
+		return TextLogger{}, nil
+	case FormatJSON:
+		return NewJSONLogger(w), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected %s or %s)", format, FormatText, FormatJSON)
+	}
+}
+
+// TextLogger renders events for interactive terminals. Structured events are
+// dropped: the human output already describes them in prose.
+type TextLogger struct{}
+
+// Log prints e in the colored terminal style.
+func (TextLogger) Log(e Entry) {
+	switch e.Level {
+	case LevelHeader:
+		line := strings.Repeat("=", 60)
+		green.Printf("\n%s\n", line)
+		green.Printf("%-60s\n", center(e.Message, 60))
+		green.Printf("%s\n\n", line)
+	case LevelStep:
+		yellow.Printf("[%d/%d] %s\n", e.Step, e.Total, e.Message)
+	case LevelSuccess:
+		green.Printf("  → %s\n", e.Message)
+	case LevelInfo:
+		fmt.Printf("  → %s\n", e.Message)
+	case LevelWarning:
+		yellow.Printf("  ⚠ %s\n", e.Message)
+	case LevelError:
+		red.Printf("Error: %s\n", e.Message)
+	}
+}
+
+// JSONLogger writes one JSON object per event, one event per line.
+type JSONLogger struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewJSONLogger creates a JSONLogger writing to w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w, now: time.Now}
+}
+
+type jsonEntry struct {
+	Time    string         `json:"time"`
+	Level   Level          `json:"level"`
+	Message string         `json:"msg,omitempty"`
+	Step    int            `json:"step,omitempty"`
+	Total   int            `json:"total,omitempty"`
+	Event   string         `json:"event,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Log writes e as a single line of JSON. Fields that cannot be encoded are
+// reported as an error event instead of being silently dropped.
+func (l *JSONLogger) Log(e Entry) {
+	entry := jsonEntry{
+		Time:    l.now().UTC().Format(time.RFC3339Nano),
+		Level:   e.Level,
+		Message: e.Message,
+		Step:    e.Step,
+		Total:   e.Total,
+		Event:   e.Name,
+		Fields:  e.Fields,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(jsonEntry{
+			Time:    entry.Time,
+			Level:   LevelError,
+			Message: fmt.Sprintf("failed to encode %s %q: %v", e.Level, e.Name, err),
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(append(data, '\n'))
+}
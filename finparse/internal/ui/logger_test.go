@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLogger_OneObjectPerEvent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf)
+	logger.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	prev := SetLogger(logger)
+	defer SetLogger(prev)
+
+	Step(2, 4, "Loading deduplication state")
+	Warning("Rule coverage low")
+	Event("rule_coverage", map[string]any{"matched": 8, "coverage_percent": 80.0})
+
+	want := []string{
+		`{"time":"2024-01-02T03:04:05Z","level":"step","msg":"Loading deduplication state","step":2,"total":4}`,
+		`{"time":"2024-01-02T03:04:05Z","level":"warning","msg":"Rule coverage low"}`,
+		`{"time":"2024-01-02T03:04:05Z","level":"event","event":"rule_coverage","fields":{"coverage_percent":80,"matched":8}}`,
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %q", len(want), len(got), buf.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d:\n got: %s\nwant: %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestJSONLogger_UnencodableFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf)
+	logger.Log(Entry{Level: LevelEvent, Name: "bad", Fields: map[string]any{"ch": make(chan int)}})
+
+	if !strings.Contains(buf.String(), `"level":"error"`) || !strings.Contains(buf.String(), `failed to encode event \"bad\"`) {
+		t.Errorf("Expected encode failure to be reported as error event, got: %s", buf.String())
+	}
+}
+
+func TestTextLogger_DropsEvents(t *testing.T) {
+	output := captureOutput(func() {
+		Event("file_scanned", map[string]any{"path": "a.qfx"})
+		Success("done")
+	})
+
+	if strings.Contains(output, "file_scanned") || strings.Contains(output, "a.qfx") {
+		t.Errorf("Text logger should not print structured events, got: %q", output)
+	}
+	if !strings.Contains(output, "done") {
+		t.Errorf("Expected success message, got: %q", output)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	if _, err := NewLogger(FormatText, nil); err != nil {
+		t.Errorf("text: unexpected error %v", err)
+	}
+	if l, err := NewLogger(FormatJSON, &bytes.Buffer{}); err != nil {
+		t.Errorf("json: unexpected error %v", err)
+	} else if _, ok := l.(*JSONLogger); !ok {
+		t.Errorf("json: expected *JSONLogger, got %T", l)
+	}
+	if _, err := NewLogger("xml", nil); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/fatih/color"
@@ -16,35 +15,38 @@ var (
 
 // Header prints a formatted header
 func Header(text string) {
-	line := strings.Repeat("=", 60)
-	green.Printf("\n%s\n", line)
-	green.Printf("%-60s\n", center(text, 60))
-	green.Printf("%s\n\n", line)
+	emit(Entry{Level: LevelHeader, Message: text})
 }
 
 // Step prints a step indicator
 func Step(stepNum, totalSteps int, text string) {
-	yellow.Printf("[%d/%d] %s\n", stepNum, totalSteps, text)
+	emit(Entry{Level: LevelStep, Message: text, Step: stepNum, Total: totalSteps})
 }
 
 // Success prints a success message
 func Success(text string) {
-	green.Printf("  → %s\n", text)
+	emit(Entry{Level: LevelSuccess, Message: text})
 }
 
 // Info prints an info message
 func Info(text string) {
-	fmt.Printf("  → %s\n", text)
+	emit(Entry{Level: LevelInfo, Message: text})
 }
 
 // Warning prints a warning message
 func Warning(text string) {
-	yellow.Printf("  ⚠ %s\n", text)
+	emit(Entry{Level: LevelWarning, Message: text})
 }
 
 // Error prints an error message
 func Error(text string) {
-	red.Printf("Error: %s\n", text)
+	emit(Entry{Level: LevelError, Message: text})
+}
+
+// Event records a structured event (e.g. "file_scanned") for machine consumers.
+// The text logger ignores events; the JSON logger emits them with their fields.
+func Event(name string, fields map[string]any) {
+	emit(Entry{Level: LevelEvent, Name: name, Fields: fields})
 }
 
 // TODO(#1434): Consider removing unused BlueText and YellowText functions
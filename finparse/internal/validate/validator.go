@@ -2,6 +2,8 @@ package validate
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
@@ -43,6 +45,7 @@ type ValidationError struct {
 	Field   string
 	Value   string
 	Message string
+	Source  string // file:line of the transaction at fault, when sources are traced
 }
 
 // ValidationWarning represents a non-critical validation issue
@@ -58,6 +61,131 @@ type ValidationWarning struct {
 // checking both individual entity constraints and referential integrity.
 // Returns ValidationResult with all errors and warnings found.
 func ValidateBudget(b *domain.Budget) *ValidationResult {
+	v := NewStreamValidator()
+	v.AddTransactions(b.GetTransactions())
+	return v.Finish(b)
+}
+
+// StreamValidator validates a budget whose transactions are not all in memory at once,
+// as in streaming output where transactions are flushed in chunks.
+//
+// Each chunk is checked as it is added, leaving behind only a lightweight index
+// (transaction IDs and their statement references). Finish then validates the
+// remaining entities and cross-references against that index. The result is
+// identical to ValidateBudget on the fully assembled budget.
+type StreamValidator struct {
+	txnResult      ValidationResult // Per-transaction errors, reported after statement errors
+	transactionIDs map[string]bool
+	statementRefs  []statementRef
+	splitGroups    map[string]*splitGroup // Parent transaction ID -> its split entries
+	splitOrder     []string               // Parent IDs in first-seen order, for stable errors
+}
+
+// splitGroup accumulates the split entries of one parent transaction, in cents so
+// reconciliation is exact.
+type splitGroup struct {
+	parentCents int64
+	sumCents    int64
+}
+
+// statementRef is a transaction's reference to a statement, checked once all
+// statements are known.
+type statementRef struct {
+	transactionID string
+	statementID   string
+}
+
+// NewStreamValidator creates a validator with an empty index.
+func NewStreamValidator() *StreamValidator {
+	return &StreamValidator{
+		txnResult:      ValidationResult{Errors: []ValidationError{}, Warnings: []ValidationWarning{}},
+		transactionIDs: make(map[string]bool),
+		splitGroups:    make(map[string]*splitGroup),
+	}
+}
+
+// AddTransactions validates a chunk of transactions and records them in the index.
+// Chunks must be added in output order so duplicate IDs are reported consistently.
+func (v *StreamValidator) AddTransactions(txns []domain.Transaction) {
+	result := &v.txnResult
+	for _, txn := range txns {
+		firstErr := len(result.Errors)
+		if txn.ID == "" {
+			result.addError("transaction", txn.ID, "ID", "", "transaction ID cannot be empty")
+		}
+
+		// Validate date format
+		if txn.Date != "" {
+			if _, err := time.Parse("2006-01-02", txn.Date); err != nil {
+				result.addError("transaction", txn.ID, "Date", txn.Date,
+					fmt.Sprintf("invalid date format (expected YYYY-MM-DD): %v", err))
+			}
+		}
+
+		// Validate category enum
+		if !domain.ValidateCategory(txn.Category) {
+			result.addError("transaction", txn.ID, "Category", string(txn.Category),
+				fmt.Sprintf("invalid category: %s", txn.Category))
+		}
+
+		// Validate redemption rate
+		if txn.RedemptionRate() < 0 || txn.RedemptionRate() > 1 {
+			result.addError("transaction", txn.ID, "RedemptionRate", fmt.Sprintf("%f", txn.RedemptionRate()),
+				fmt.Sprintf("redemption rate must be in [0,1], got %f", txn.RedemptionRate()))
+		}
+
+		// Validate redeemable consistency
+		if txn.Redeemable() && txn.RedemptionRate() == 0 {
+			result.addError("transaction", txn.ID, "RedemptionRate", "0",
+				"redeemable transaction must have non-zero redemption rate")
+		}
+		if !txn.Redeemable() && txn.RedemptionRate() != 0 {
+			result.addError("transaction", txn.ID, "RedemptionRate", fmt.Sprintf("%f", txn.RedemptionRate()),
+				"non-redeemable transaction must have zero redemption rate")
+		}
+
+		// Validate transfer and redeemable flags
+		if txn.Transfer() && txn.Redeemable() {
+			result.addError("transaction", txn.ID, "Transfer", "true",
+				"transaction cannot be both transfer and redeemable (transfers should not earn cashback)")
+		}
+
+		// Check for duplicate IDs
+		if txn.ID != "" {
+			if v.transactionIDs[txn.ID] {
+				result.addError("transaction", txn.ID, "ID", txn.ID, "duplicate transaction ID")
+			}
+			v.transactionIDs[txn.ID] = true
+		}
+
+		// Split entries are reconciled against their parent amount in Finish
+		if txn.SplitOf != nil {
+			v.addSplitEntry(result, txn)
+		}
+
+		// Validate statement references (bidirectional check)
+		for _, stmtID := range txn.GetStatementIDs() {
+			if stmtID == "" {
+				result.addError("transaction", txn.ID, "StatementIDs", "", "transaction contains empty statement ID")
+				continue
+			}
+			// Existence is checked in Finish because bidirectional transaction↔statement
+			// references may appear in any order.
+			v.statementRefs = append(v.statementRefs, statementRef{transactionID: txn.ID, statementID: stmtID})
+		}
+
+		if txn.Source != nil {
+			for i := firstErr; i < len(result.Errors); i++ {
+				result.Errors[i].Source = txn.Source.String()
+			}
+		}
+	}
+}
+
+// Finish validates b's institutions, accounts and statements, then checks
+// cross-references against the transactions passed to AddTransactions.
+// Transactions still held in b are ignored; add them first.
+func (v *StreamValidator) Finish(b *domain.Budget) *ValidationResult {
 	// Note: Empty budgets are valid during initialization (domain.NewBudget() creates empty budget).
 	// After parsing statements, an empty budget would indicate no files were processed.
 	// Entities are added incrementally during the parsing/transformation pipeline.
@@ -74,7 +202,6 @@ func ValidateBudget(b *domain.Budget) *ValidationResult {
 	institutionIDs := make(map[string]bool)
 	accountIDs := make(map[string]bool)
 	statementIDs := make(map[string]bool)
-	transactionIDs := make(map[string]bool)
 
 	// Validate institutions
 	for _, inst := range b.GetInstitutions() {
@@ -183,86 +310,120 @@ func ValidateBudget(b *domain.Budget) *ValidationResult {
 		}
 	}
 
-	// Validate transactions
-	for _, txn := range b.GetTransactions() {
-		if txn.ID == "" {
-			result.addError("transaction", txn.ID, "ID", "", "transaction ID cannot be empty")
+	// Transaction errors were collected as chunks were added
+	result.Errors = append(result.Errors, v.txnResult.Errors...)
+	result.Warnings = append(result.Warnings, v.txnResult.Warnings...)
+
+	// Second pass: validate bidirectional references
+	// Check that transaction.statementIds reference existing statements
+	for _, ref := range v.statementRefs {
+		if !statementIDs[ref.statementID] {
+			result.addError("transaction", ref.transactionID, "StatementIDs", ref.statementID,
+				fmt.Sprintf("references non-existent statement: %s", ref.statementID))
 		}
+	}
 
-		// Validate date format
-		if txn.Date != "" {
-			if _, err := time.Parse("2006-01-02", txn.Date); err != nil {
-				result.addError("transaction", txn.ID, "Date", txn.Date,
-					fmt.Sprintf("invalid date format (expected YYYY-MM-DD): %v", err))
+	// Check that statement.transactionIds reference existing transactions
+	for _, stmt := range b.GetStatements() {
+		for _, txnID := range stmt.GetTransactionIDs() {
+			if txnID != "" && !v.transactionIDs[txnID] {
+				result.addError("statement", stmt.ID, "TransactionIDs", txnID,
+					fmt.Sprintf("references non-existent transaction: %s", txnID))
 			}
 		}
+	}
 
-		// Validate category enum
-		if !domain.ValidateCategory(txn.Category) {
-			result.addError("transaction", txn.ID, "Category", string(txn.Category),
-				fmt.Sprintf("invalid category: %s", txn.Category))
+	// Split entries must sum exactly to the transaction they were split from
+	for _, parentID := range v.splitOrder {
+		group := v.splitGroups[parentID]
+		if group.sumCents != group.parentCents {
+			result.addError("transaction", parentID, "SplitOf", fmt.Sprintf("%.2f", float64(group.sumCents)/100),
+				fmt.Sprintf("split entries sum to %.2f, expected parent amount %.2f",
+					float64(group.sumCents)/100, float64(group.parentCents)/100))
 		}
+	}
 
-		// Validate redemption rate
-		if txn.RedemptionRate() < 0 || txn.RedemptionRate() > 1 {
-			result.addError("transaction", txn.ID, "RedemptionRate", fmt.Sprintf("%f", txn.RedemptionRate()),
-				fmt.Sprintf("redemption rate must be in [0,1], got %f", txn.RedemptionRate()))
-		}
+	reconcileBalances(b, result)
 
-		// Validate redeemable consistency
-		if txn.Redeemable() && txn.RedemptionRate() == 0 {
-			result.addError("transaction", txn.ID, "RedemptionRate", "0",
-				"redeemable transaction must have non-zero redemption rate")
-		}
-		if !txn.Redeemable() && txn.RedemptionRate() != 0 {
-			result.addError("transaction", txn.ID, "RedemptionRate", fmt.Sprintf("%f", txn.RedemptionRate()),
-				"non-redeemable transaction must have zero redemption rate")
-		}
+	return result
+}
 
-		// Validate transfer and redeemable flags
-		if txn.Transfer() && txn.Redeemable() {
-			result.addError("transaction", txn.ID, "Transfer", "true",
-				"transaction cannot be both transfer and redeemable (transfers should not earn cashback)")
-		}
+// addSplitEntry records a split entry against its parent.
+func (v *StreamValidator) addSplitEntry(result *ValidationResult, txn domain.Transaction) {
+	parentID := txn.SplitOf.ParentID
+	if parentID == "" {
+		result.addError("transaction", txn.ID, "SplitOf", "", "split entry has empty parent ID")
+		return
+	}
 
-		// Check for duplicate IDs
-		if txn.ID != "" {
-			if transactionIDs[txn.ID] {
-				result.addError("transaction", txn.ID, "ID", txn.ID, "duplicate transaction ID")
-			}
-			transactionIDs[txn.ID] = true
-		}
+	parentCents := toCents(txn.SplitOf.ParentAmount)
+	group, ok := v.splitGroups[parentID]
+	if !ok {
+		group = &splitGroup{parentCents: parentCents}
+		v.splitGroups[parentID] = group
+		v.splitOrder = append(v.splitOrder, parentID)
+	} else if group.parentCents != parentCents {
+		result.addError("transaction", txn.ID, "SplitOf", fmt.Sprintf("%.2f", txn.SplitOf.ParentAmount),
+			fmt.Sprintf("split entry parent amount %.2f disagrees with sibling entries (%.2f)",
+				txn.SplitOf.ParentAmount, float64(group.parentCents)/100))
+	}
+	group.sumCents += toCents(txn.Amount)
+}
 
-		// Validate statement references (bidirectional check)
-		for _, stmtID := range txn.GetStatementIDs() {
-			if stmtID == "" {
-				result.addError("transaction", txn.ID, "StatementIDs", "", "transaction contains empty statement ID")
-			}
-			// Note: Statement ID validation deferred to second pass (starting line 246) because
-			// bidirectional transaction↔statement references may appear in any order.
+// toCents converts a dollar amount to whole cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// balanceTolerance is the largest divergence between reported and computed balances
+// treated as rounding noise.
+const balanceTolerance = 0.01
+
+// reconcileBalances warns when an account's statements do not reconcile: the previous
+// statement's closing balance plus the current statement's transactions should equal
+// the current closing balance. A mismatch usually means a missing or misparsed transaction.
+//
+// Only statements carrying a reported balance participate. The first such statement per
+// account has no opening balance and is not checked. Overlapping statement periods are
+// skipped because their shared transactions would be counted twice.
+func reconcileBalances(b *domain.Budget, result *ValidationResult) {
+	byAccount := make(map[string][]domain.Statement)
+	var accountIDs []string
+	for _, stmt := range b.GetStatements() {
+		if _, ok := stmt.Balance(); !ok {
+			continue
+		}
+		if _, exists := byAccount[stmt.AccountID]; !exists {
+			accountIDs = append(accountIDs, stmt.AccountID)
 		}
+		byAccount[stmt.AccountID] = append(byAccount[stmt.AccountID], stmt)
 	}
 
-	// Second pass: validate bidirectional references
-	// Check that transaction.statementIds reference existing statements
-	for _, txn := range b.GetTransactions() {
-		for _, stmtID := range txn.GetStatementIDs() {
-			if stmtID != "" && !statementIDs[stmtID] {
-				result.addError("transaction", txn.ID, "StatementIDs", stmtID,
-					fmt.Sprintf("references non-existent statement: %s", stmtID))
+	for _, accountID := range accountIDs {
+		stmts := byAccount[accountID]
+		// YYYY-MM-DD sorts chronologically as a string
+		sort.SliceStable(stmts, func(i, j int) bool {
+			return stmts[i].EndDate < stmts[j].EndDate
+		})
+
+		for i := 1; i < len(stmts); i++ {
+			prev, cur := stmts[i-1], stmts[i]
+			if cur.StartDate < prev.EndDate {
+				continue
 			}
-		}
-	}
 
-	// Check that statement.transactionIds reference existing transactions
-	for _, stmt := range b.GetStatements() {
-		for _, txnID := range stmt.GetTransactionIDs() {
-			if txnID != "" && !transactionIDs[txnID] {
-				result.addError("statement", stmt.ID, "TransactionIDs", txnID,
-					fmt.Sprintf("references non-existent transaction: %s", txnID))
+			prevBalance, _ := prev.Balance()
+			curBalance, _ := cur.Balance()
+			computed := prevBalance.Ledger + curBalance.ParsedTotal
+			diff := curBalance.Ledger - computed
+			// Small epsilon absorbs float64 summation error at exactly one cent
+			if math.Abs(diff) <= balanceTolerance+1e-9 {
+				continue
 			}
+
+			result.addWarning("account", accountID, "LedgerBalance", fmt.Sprintf("%.2f", computed),
+				fmt.Sprintf("statement %s does not reconcile: expected closing balance %.2f, computed %.2f (opening %.2f from %s + transactions %.2f), off by %.2f",
+					cur.ID, curBalance.Ledger, computed, prevBalance.Ledger, prev.ID, curBalance.ParsedTotal, diff))
 		}
 	}
-
-	return result
 }
@@ -2,6 +2,7 @@ package validate
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -950,3 +951,257 @@ func TestValidateBudget_RedemptionRateBoundaries(t *testing.T) {
 		})
 	}
 }
+
+// newReconcileBudget creates a budget with one account and a statement per balance.
+// Each entry is {startDate, endDate, ledger, parsedTotal}.
+func newReconcileBudget(t *testing.T, statements []struct {
+	start, end          string
+	ledger, parsedTotal float64
+}) *domain.Budget {
+	t.Helper()
+	budget := domain.NewBudget()
+	if err := budget.AddInstitution(domain.Institution{ID: "inst1", Name: "Test Bank"}); err != nil {
+		t.Fatalf("failed to add institution: %v", err)
+	}
+	acc, err := domain.NewAccount("acc1", "inst1", "Checking", domain.AccountTypeChecking)
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if err := budget.AddAccount(*acc); err != nil {
+		t.Fatalf("failed to add account: %v", err)
+	}
+	for i, s := range statements {
+		stmt, err := domain.NewStatement(fmt.Sprintf("stmt%d", i+1), "acc1", s.start, s.end)
+		if err != nil {
+			t.Fatalf("failed to create statement: %v", err)
+		}
+		stmt.SetBalance(domain.StatementBalance{Ledger: s.ledger, ParsedTotal: s.parsedTotal})
+		if err := budget.AddStatement(*stmt); err != nil {
+			t.Fatalf("failed to add statement: %v", err)
+		}
+	}
+	return budget
+}
+
+func TestValidateBudget_BalanceReconciliation(t *testing.T) {
+	type stmt = struct {
+		start, end          string
+		ledger, parsedTotal float64
+	}
+
+	tests := []struct {
+		name         string
+		statements   []stmt
+		wantWarnings int
+	}{
+		{
+			name: "reconciles",
+			statements: []stmt{
+				{"2024-01-01", "2024-01-31", 1000.00, 0},
+				{"2024-02-01", "2024-02-29", 950.10, -49.90},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name: "within one cent",
+			statements: []stmt{
+				{"2024-01-01", "2024-01-31", 1000.00, 0},
+				{"2024-02-01", "2024-02-29", 950.11, -49.90},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name: "missing transaction",
+			statements: []stmt{
+				{"2024-01-01", "2024-01-31", 1000.00, 0},
+				{"2024-02-01", "2024-02-29", 925.10, -49.90},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "statements out of order",
+			statements: []stmt{
+				{"2024-02-01", "2024-02-29", 925.10, -49.90},
+				{"2024-01-01", "2024-01-31", 1000.00, 0},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "overlapping periods skipped",
+			statements: []stmt{
+				{"2024-01-01", "2024-01-31", 1000.00, 0},
+				{"2024-01-15", "2024-02-15", 500.00, -10.00},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name: "single statement has no opening balance",
+			statements: []stmt{
+				{"2024-01-01", "2024-01-31", 1000.00, -25.00},
+			},
+			wantWarnings: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateBudget(newReconcileBudget(t, tt.statements))
+
+			if len(result.Errors) != 0 {
+				t.Errorf("reconciliation must not produce errors, got %v", result.Errors)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Fatalf("expected %d warnings, got %d: %v", tt.wantWarnings, len(result.Warnings), result.Warnings)
+			}
+			if tt.wantWarnings == 0 {
+				return
+			}
+
+			w := result.Warnings[0]
+			if w.Entity != "account" || w.ID != "acc1" {
+				t.Errorf("expected warning for account acc1, got %s %s", w.Entity, w.ID)
+			}
+			if !strings.Contains(w.Message, "expected closing balance 925.10, computed 950.10") {
+				t.Errorf("expected warning to show expected vs computed balance, got %q", w.Message)
+			}
+		})
+	}
+}
+
+func TestValidateBudget_BalanceReconciliationIgnoresStatementsWithoutBalance(t *testing.T) {
+	budget := newReconcileBudget(t, nil)
+	for i, dates := range [][2]string{{"2024-01-01", "2024-01-31"}, {"2024-02-01", "2024-02-29"}} {
+		stmt, err := domain.NewStatement(fmt.Sprintf("stmt%d", i+1), "acc1", dates[0], dates[1])
+		if err != nil {
+			t.Fatalf("failed to create statement: %v", err)
+		}
+		if err := budget.AddStatement(*stmt); err != nil {
+			t.Fatalf("failed to add statement: %v", err)
+		}
+	}
+
+	result := ValidateBudget(budget)
+	if len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings for statements without balances, got %v", result.Warnings)
+	}
+}
+
+func TestStreamValidator_ChunkedMatchesValidateBudget(t *testing.T) {
+	jsonData := []byte(`{
+		"institutions": [{"id": "inst1", "name": "Bank"}],
+		"accounts": [{"id": "acc1", "institutionId": "inst1", "name": "Card", "type": "credit"}],
+		"statements": [{"id": "stmt1", "accountId": "acc1", "startDate": "2024-01-01", "endDate": "2024-01-31",
+			"transactionIds": ["txn1", "txn2", "missing-txn"]}],
+		"transactions": [
+			{"id": "txn1", "date": "2024-01-15", "description": "A", "amount": -5, "category": "groceries", "statementIds": ["stmt1"]},
+			{"id": "txn2", "date": "2024-13-01", "description": "B", "amount": -6, "category": "groceries", "statementIds": ["stmt1", "missing-stmt"]},
+			{"id": "txn1", "date": "2024-01-16", "description": "C", "amount": -7, "category": "groceries", "statementIds": ["stmt1"]}
+		]
+	}`)
+
+	budget := domain.NewBudget()
+	if err := budget.UnmarshalJSON(jsonData); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+	want := ValidateBudget(budget)
+	if len(want.Errors) == 0 {
+		t.Fatal("fixture should produce validation errors")
+	}
+
+	// Duplicate txn1 lands in a different chunk than the original
+	txns := budget.DrainTransactions()
+	v := NewStreamValidator()
+	v.AddTransactions(txns[:2])
+	v.AddTransactions(txns[2:])
+	got := v.Finish(budget)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunked result differs from ValidateBudget:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestValidateBudget_SplitReconciliation(t *testing.T) {
+	newSplit := func(t *testing.T, id string, amount, parentAmount float64) domain.Transaction {
+		t.Helper()
+		txn, err := domain.NewTransaction(id, "2024-01-15", "COSTCO WHSE", amount, domain.CategoryGroceries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txn.SplitOf = &domain.SplitOf{ParentID: "txn1", ParentAmount: parentAmount}
+		return *txn
+	}
+	splitErrors := func(result *ValidationResult) []ValidationError {
+		var errs []ValidationError
+		for _, e := range result.Errors {
+			if e.Field == "SplitOf" {
+				errs = append(errs, e)
+			}
+		}
+		return errs
+	}
+
+	t.Run("entries sum to parent", func(t *testing.T) {
+		budget := domain.NewBudget()
+		// 0.1 + 0.2 style float noise must not matter: amounts are compared in cents
+		for _, txn := range []domain.Transaction{newSplit(t, "txn1-split-1", -70.07, -100.1), newSplit(t, "txn1-split-2", -30.03, -100.1)} {
+			if err := budget.AddTransaction(txn); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if errs := splitErrors(ValidateBudget(budget)); len(errs) != 0 {
+			t.Errorf("Expected no split errors, got %+v", errs)
+		}
+	})
+
+	t.Run("entries do not sum to parent", func(t *testing.T) {
+		budget := domain.NewBudget()
+		for _, txn := range []domain.Transaction{newSplit(t, "txn1-split-1", -70, -100), newSplit(t, "txn1-split-2", -29.99, -100)} {
+			if err := budget.AddTransaction(txn); err != nil {
+				t.Fatal(err)
+			}
+		}
+		errs := splitErrors(ValidateBudget(budget))
+		if len(errs) != 1 || errs[0].ID != "txn1" || !strings.Contains(errs[0].Message, "sum to -99.99, expected parent amount -100.00") {
+			t.Errorf("Expected one reconciliation error for txn1, got %+v", errs)
+		}
+	})
+
+	t.Run("siblings disagree on parent amount", func(t *testing.T) {
+		budget := domain.NewBudget()
+		for _, txn := range []domain.Transaction{newSplit(t, "txn1-split-1", -70, -100), newSplit(t, "txn1-split-2", -30, -90)} {
+			if err := budget.AddTransaction(txn); err != nil {
+				t.Fatal(err)
+			}
+		}
+		errs := splitErrors(ValidateBudget(budget))
+		if len(errs) == 0 || !strings.Contains(errs[0].Message, "disagrees with sibling entries") {
+			t.Errorf("Expected sibling disagreement error, got %+v", errs)
+		}
+	})
+}
+
+func TestValidateBudget_ErrorSource(t *testing.T) {
+	budget := domain.NewBudget()
+	for _, txn := range []domain.Transaction{
+		{ID: "traced", Date: "2024-13-01", Category: domain.CategoryGroceries,
+			Source: &domain.Source{File: "stmt.csv", Line: 4}},
+		{ID: "untraced", Date: "2024-13-02", Category: domain.CategoryGroceries},
+	} {
+		if err := budget.AddTransaction(txn); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+
+	sources := make(map[string]string)
+	for _, e := range ValidateBudget(budget).Errors {
+		if e.Field == "Date" {
+			sources[e.ID] = e.Source
+		}
+	}
+	if sources["traced"] != "stmt.csv:4" {
+		t.Errorf("traced error Source = %q, want %q", sources["traced"], "stmt.csv:4")
+	}
+	if src, ok := sources["untraced"]; !ok || src != "" {
+		t.Errorf("untraced error Source = %q (found %v), want empty", src, ok)
+	}
+}
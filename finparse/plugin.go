@@ -0,0 +1,59 @@
+package finparse
+
+import (
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/registry"
+)
+
+// The parser types are re-exported so plugins can be written outside this module.
+type (
+	// Parser parses one statement file. See RegisterParser.
+	Parser = parser.Parser
+	// MultiStatementParser is implemented by parsers whose files hold several statements.
+	MultiStatementParser = parser.MultiStatementParser
+	// ContentSniffer is implemented by parsers that recognize files by their contents.
+	ContentSniffer = parser.ContentSniffer
+	// Metadata describes a statement file, as derived from its path.
+	Metadata = parser.Metadata
+	// RawStatement is a parser's output before transformation into the budget.
+	RawStatement = parser.RawStatement
+	// RawAccount identifies the account a RawStatement belongs to.
+	RawAccount = parser.RawAccount
+	// Period is the date range a RawStatement covers.
+	Period = parser.Period
+	// RawTransaction is a single transaction within a RawStatement.
+	RawTransaction = parser.RawTransaction
+)
+
+// NewRawAccount validates and returns a RawAccount.
+func NewRawAccount(institutionID, institutionName, accountID, accountType string) (*RawAccount, error) {
+	return parser.NewRawAccount(institutionID, institutionName, accountID, accountType)
+}
+
+// NewPeriod validates and returns a Period.
+func NewPeriod(start, end time.Time) (*Period, error) {
+	return parser.NewPeriod(start, end)
+}
+
+// NewRawTransaction validates and returns a RawTransaction.
+func NewRawTransaction(id string, date, postedDate time.Time, description string, amount float64) (*RawTransaction, error) {
+	return parser.NewRawTransaction(id, date, postedDate, description, amount)
+}
+
+// RegisterParser makes a plugin parser available to every subsequent Process or Prepare
+// call. The parser is used for any path matcher accepts, ahead of the built-in parsers,
+// and such files are scanned regardless of extension.
+//
+// RegisterParser is intended to be called from an init function. It panics if name is
+// empty, matcher or factory is nil, or name is already registered.
+func RegisterParser(name string, matcher func(path string) bool, factory func() Parser) {
+	registry.Register(name, matcher, factory)
+}
+
+// UnregisterParser removes a parser added by RegisterParser. Returns false if no parser
+// with that name is registered.
+func UnregisterParser(name string) bool {
+	return registry.Unregister(name)
+}
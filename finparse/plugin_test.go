@@ -0,0 +1,103 @@
+package finparse_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse"
+)
+
+// payrollParser parses "date|description|amount" lines using only the exported
+// finparse API, as a plugin built outside this module would.
+type payrollParser struct{}
+
+func (p *payrollParser) Name() string { return "payroll" }
+
+func (p *payrollParser) CanParse(path string, header []byte) bool { return false }
+
+func (p *payrollParser) Parse(ctx context.Context, r io.Reader, meta *finparse.Metadata) (*finparse.RawStatement, error) {
+	account, err := finparse.NewRawAccount("PAYROLL", meta.Institution(), meta.AccountNumber(), "checking")
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []finparse.RawTransaction
+	var first, last time.Time
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, got %d", line, len(fields))
+		}
+		date, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		amount, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		txn, err := finparse.NewRawTransaction(fmt.Sprintf("PAY-%d", line), date, date, fields[1], amount)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, *txn)
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	period, err := finparse.NewPeriod(first, last.Add(24*time.Hour-time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return &finparse.RawStatement{Account: *account, Period: *period, Transactions: transactions}, nil
+}
+
+// TestProcess_RegisteredParser verifies Process hands files to a parser added with RegisterParser.
+func TestProcess_RegisteredParser(t *testing.T) {
+	finparse.RegisterParser("payroll", func(path string) bool {
+		return strings.EqualFold(filepath.Ext(path), ".pay")
+	}, func() finparse.Parser {
+		return &payrollParser{}
+	})
+	t.Cleanup(func() { finparse.UnregisterParser("payroll") })
+
+	root := t.TempDir()
+	acctDir := filepath.Join(root, "payroll_co", "5555")
+	if err := os.MkdirAll(acctDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "2025-01-15|Payroll Deposit|2500.00\n2025-01-31|Payroll Deposit|2500.00\n"
+	if err := os.WriteFile(filepath.Join(acctDir, "export.pay"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	budget, stats, err := finparse.Process(context.Background(), finparse.Options{InputDir: root})
+	if err != nil {
+		t.Fatalf("Process() error: %v", err)
+	}
+	if stats.Files != 1 {
+		t.Errorf("Expected 1 file processed, got %d", stats.Files)
+	}
+	if got := len(budget.GetTransactions()); got != 2 {
+		t.Errorf("Expected 2 transactions, got %d", got)
+	}
+	if accounts := budget.GetAccounts(); len(accounts) != 1 || accounts[0].InstitutionID != "payroll-co" {
+		t.Errorf("Expected one payroll-co account, got %+v", accounts)
+	}
+}
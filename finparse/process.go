@@ -0,0 +1,710 @@
+// Package finparse parses a directory of financial statements into a budget. It is
+// the pipeline behind the finparse command (scan, parse, transform, validate), for
+// programs that embed it instead of running the binary.
+//
+// Process runs the whole pipeline. Callers that need to act between the steps, as the
+// command does for its dry run and streaming output, use Prepare, LoadState and
+// Pipeline.Run instead.
+//
+// Apart from parsers added with RegisterParser, the package keeps no global state:
+// separate runs may execute concurrently, as long as they don't share a state file.
+package finparse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/dedup"
+	"github.com/rumor-ml/commons.systems/finparse/internal/domain"
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/registry"
+	"github.com/rumor-ml/commons.systems/finparse/internal/rules"
+	"github.com/rumor-ml/commons.systems/finparse/internal/scanner"
+	"github.com/rumor-ml/commons.systems/finparse/internal/transform"
+	"github.com/rumor-ml/commons.systems/finparse/internal/validate"
+)
+
+var (
+	// ErrValidation is wrapped by errors for budgets that fail validation, and for
+	// statements without a period when StrictPeriod is set
+	ErrValidation = errors.New("validation failed")
+
+	// ErrNoFiles is wrapped by errors for runs with no statement files, including
+	// filters that match none of the scanned files
+	ErrNoFiles = errors.New("no statement files")
+)
+
+// FormatAll selects every parser format in Options.Formats
+const FormatAll = "all"
+
+// StdinInput as Options.InputDir reads a single statement from Options.Stdin
+const StdinInput = "-"
+
+// UnknownInstitution stands for files whose institution could not be inferred from
+// their path
+const UnknownInstitution = "<unknown>"
+
+// Options configures a run
+type Options struct {
+	// InputDir is the directory scanned for statements, a single statement file, or
+	// StdinInput (required)
+	InputDir string
+
+	// Stdin is read when InputDir is StdinInput. Nil reads os.Stdin.
+	Stdin io.Reader
+
+	// StdinFormat names the parser for a statement read from Stdin, by parser name
+	// (e.g. "csv-pnc") or format (e.g. "ofx"). Required with StdinInput, since there
+	// is no file name or header to detect the format from.
+	StdinFormat string
+
+	// StateFile is the deduplication state file. Empty disables deduplication.
+	StateFile string
+
+	// RulesFile is the category rules file, or several comma-separated files or
+	// directories of *.yaml files to merge (see rules.LoadFromFiles). Empty uses the
+	// embedded rules.
+	RulesFile string
+
+	// Institution keeps only files from this institution, compared case-insensitively
+	// on canonical names so aliases work. Empty keeps every institution. When InputDir
+	// is a single file or StdinInput, it sets the statement's institution instead.
+	Institution string
+
+	// Formats keeps only files of these formats, comma-separated (e.g. "ofx,csv").
+	// Empty or FormatAll keeps every format.
+	Formats string
+
+	// FailOnUnknownInstitution fails before parsing if any file's institution cannot
+	// be determined from its path
+	FailOnUnknownInstitution bool
+
+	// StrictPeriod skips statements without a period and, in Process, fails the run
+	// listing them. Otherwise they are transformed and only listed in Stats.
+	StrictPeriod bool
+
+	// CheckpointEvery saves the state file every N files during parsing (0 = only at
+	// the end). Requires StateFile.
+	CheckpointEvery int
+
+	// TraceSource records each transaction's file path and, for CSV, row line as its
+	// Source, so odd values can be traced back to the statement
+	TraceSource bool
+
+	// Progress, if set, is called once per file after it is parsed and transformed.
+	// Calls are made in file order from the goroutine running the pipeline, never
+	// concurrently, so the callback needs no locking unless it shares data with other
+	// goroutines. Returning an error stops the run with that error.
+	Progress func(FileProgress) error
+}
+
+// FileProgress describes one processed file
+type FileProgress struct {
+	// Index is the 1-based position of the file among Total files
+	Index int
+	Total int
+
+	Path   string
+	Parser string
+
+	// Statements holds one entry per account the file contained
+	Statements []StatementResult
+
+	// Checkpointed is set when the state file was saved after this file
+	Checkpointed bool
+
+	// CloseErr is a non-critical error closing the file. The file was parsed, but
+	// Process fails once every file is done (see Stats.CloseErrors).
+	CloseErr error
+}
+
+// StatementResult is one parsed statement and what transforming it did
+type StatementResult struct {
+	Raw *parser.RawStatement
+
+	// Stats is nil when the statement was skipped for having no period under
+	// StrictPeriod
+	Stats *transform.TransformStats
+}
+
+// Stats summarizes a run
+type Stats struct {
+	FilesScanned int // Statement files found before filtering
+	Files        int // Files parsed
+	Statements   int // Statements transformed
+
+	// Transactions parsed from the statements, before deduplication and split rules
+	Transactions int
+
+	DuplicatesSkipped            int
+	DuplicateInstitutionsSkipped int
+	DuplicateAccountsSkipped     int
+	DuplicateStatementsMerged    int
+	RulesMatched                 int
+	RulesUnmatched               int
+	TransactionsSplit            int
+
+	// MissingPeriod lists files with a statement that has no period
+	MissingPeriod []string
+
+	// CloseErrors lists files that failed to close, as "path: error"
+	CloseErrors []string
+
+	// Validation is the budget validation result (set by Process only)
+	Validation *validate.ValidationResult
+}
+
+// Coverage returns the percentage of categorized transactions a rule matched, and
+// false when no transactions were categorized
+func (s Stats) Coverage() (float64, bool) {
+	total := s.RulesMatched + s.RulesUnmatched
+	if total == 0 {
+		return 0, false
+	}
+	return float64(s.RulesMatched) / float64(total) * 100, true
+}
+
+func (s *Stats) add(raw *parser.RawStatement, stats *transform.TransformStats) {
+	s.Statements++
+	s.Transactions += len(raw.Transactions)
+	s.DuplicatesSkipped += stats.DuplicatesSkipped
+	s.DuplicateInstitutionsSkipped += stats.DuplicateInstitutionsSkipped
+	s.DuplicateAccountsSkipped += stats.DuplicateAccountsSkipped
+	s.DuplicateStatementsMerged += stats.DuplicateStatementsMerged
+	s.RulesMatched += stats.RulesMatched
+	s.RulesUnmatched += stats.RulesUnmatched
+	s.TransactionsSplit += stats.TransactionsSplit
+}
+
+// Process scans opts.InputDir and parses, transforms and validates every selected
+// statement into a new budget. With a state file, transactions seen in earlier runs
+// are skipped and the state is saved once the budget validates, so a caller writing
+// the budget out afterwards never writes output the state doesn't cover. The saved
+// state also records the run's rule coverage as its LastCoverage.
+//
+// Stats are returned even when the run fails part way.
+func Process(ctx context.Context, opts Options) (*domain.Budget, Stats, error) {
+	p, err := Prepare(opts)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	if len(p.Files) == 0 {
+		return nil, Stats{FilesScanned: len(p.Scanned)}, fmt.Errorf("%w found in %s", ErrNoFiles, opts.InputDir)
+	}
+
+	var state *dedup.State
+	if opts.StateFile != "" {
+		if state, _, err = LoadState(opts.StateFile); err != nil {
+			return nil, Stats{FilesScanned: len(p.Scanned)}, err
+		}
+	}
+
+	budget := domain.NewBudget()
+	stats, err := p.Run(ctx, budget, state)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	if opts.StrictPeriod && len(stats.MissingPeriod) > 0 {
+		return nil, stats, fmt.Errorf("%w: strict period: %d statement(s) have no period:\n  - %s",
+			ErrValidation, len(stats.MissingPeriod), strings.Join(stats.MissingPeriod, "\n  - "))
+	}
+	if len(stats.CloseErrors) > 0 {
+		return nil, stats, fmt.Errorf("%d file(s) failed to close - check filesystem health:\n  - %s",
+			len(stats.CloseErrors), strings.Join(stats.CloseErrors, "\n  - "))
+	}
+
+	stats.Validation = validate.ValidateBudget(budget)
+	if len(stats.Validation.Errors) > 0 {
+		return nil, stats, fmt.Errorf("%w with %d errors", ErrValidation, len(stats.Validation.Errors))
+	}
+
+	if state != nil {
+		if coverage, ok := stats.Coverage(); ok {
+			state.Metadata.RecordCoverage(coverage)
+		}
+		if err := dedup.SaveState(state, opts.StateFile); err != nil {
+			return nil, stats, fmt.Errorf("failed to save state file: %w", err)
+		}
+	}
+	return budget, stats, nil
+}
+
+// Pipeline is a run whose files have been scanned and filtered but not yet parsed
+type Pipeline struct {
+	opts Options
+
+	// Scanned holds every statement file found, and Files the ones left after the
+	// institution and format filters, in processing order
+	Scanned []scanner.ScanResult
+	Files   []scanner.ScanResult
+
+	// InstitutionSkipped and FormatSkipped count the files each filter removed
+	InstitutionSkipped int
+	FormatSkipped      int
+
+	Registry *registry.Registry
+	Engine   *rules.Engine
+
+	// stdin and stdinParser are set when the input is StdinInput
+	stdin       io.Reader
+	stdinParser parser.Parser
+
+	// Progress is called by Run for each file, as described for Options.Progress.
+	// Prepare sets it from the options; callers may replace it before Run.
+	Progress func(FileProgress) error
+}
+
+// Prepare validates opts, scans the input (directory, file or stdin), loads the
+// category rules and applies the filters. Nothing is parsed and the state file is not touched, so
+// Prepare also serves dry runs. A filter that matches none of a non-empty scan fails
+// with ErrNoFiles; an empty scan does not fail here.
+func Prepare(opts Options) (*Pipeline, error) {
+	if opts.InputDir == "" {
+		return nil, fmt.Errorf("input directory is required")
+	}
+	if opts.StdinFormat != "" && opts.InputDir != StdinInput {
+		return nil, fmt.Errorf("stdin format %q is only used when reading from stdin (input %q)", opts.StdinFormat, StdinInput)
+	}
+	if opts.CheckpointEvery < 0 {
+		return nil, fmt.Errorf("checkpoint interval must be >= 0, got %d", opts.CheckpointEvery)
+	}
+	if opts.CheckpointEvery > 0 && opts.StateFile == "" {
+		return nil, fmt.Errorf("checkpoints require a state file (there is no state to checkpoint)")
+	}
+
+	// The format filter is validated against the registry's parsers before scanning
+	reg, err := registry.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parser registry: %w", err)
+	}
+	var formats map[string]bool
+	if opts.Formats != "" {
+		if formats, err = parseFormats(opts.Formats, reg.ListParsers()); err != nil {
+			return nil, err
+		}
+	}
+
+	var scanned []scanner.ScanResult
+	var stdinParser parser.Parser
+	single := opts.InputDir == StdinInput
+	if single {
+		if stdinParser, err = parserForFormat(reg, opts.StdinFormat); err != nil {
+			return nil, err
+		}
+		meta, err := parser.NewMetadata(StdinInput, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdin metadata: %w", err)
+		}
+		result, err := scanner.NewScanResult(StdinInput, meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdin scan result: %w", err)
+		}
+		scanned = []scanner.ScanResult{result}
+	} else {
+		// Files without a telling extension are kept if the registry recognizes their content
+		sniff := func(path string) bool {
+			p, err := reg.FindParser(path)
+			return err == nil && p != nil
+		}
+		s := scanner.NewWithSniffer(opts.InputDir, sniff)
+		if scanned, err = s.Scan(); err != nil {
+			return nil, fmt.Errorf("failed to scan directory %s: %w", opts.InputDir, err)
+		}
+		single = s.SingleFile()
+	}
+	// A single file or stdin has no institution directory, so the institution filter
+	// names it instead
+	if single && opts.Institution != "" {
+		for _, f := range scanned {
+			f.Metadata.SetInstitution(opts.Institution)
+		}
+	}
+
+	// Rules load before the institution filter, which compares canonical names
+	var engine *rules.Engine
+	if opts.RulesFile != "" {
+		if engine, err = rules.LoadFromFiles(splitRulesFiles(opts.RulesFile)); err != nil {
+			return nil, fmt.Errorf("failed to load rules file: %w", err)
+		}
+	} else if engine, err = rules.LoadEmbedded(); err != nil {
+		return nil, fmt.Errorf("failed to load embedded rules: %w", err)
+	}
+
+	p := &Pipeline{opts: opts, Scanned: scanned, Registry: reg, Engine: engine, Progress: opts.Progress, stdinParser: stdinParser}
+	if stdinParser != nil {
+		p.stdin = opts.Stdin
+		if p.stdin == nil {
+			p.stdin = os.Stdin
+		}
+	}
+
+	files, available := filterByInstitution(scanned, opts.Institution, engine)
+	if opts.Institution != "" && len(scanned) > 0 && len(files) == 0 {
+		return nil, fmt.Errorf("%w: institution %q matched none of the %d scanned file(s)\n\nAvailable institutions:\n  - %s",
+			ErrNoFiles, opts.Institution, len(scanned), strings.Join(available, "\n  - "))
+	}
+	p.InstitutionSkipped = len(scanned) - len(files)
+
+	// Abort before parsing anything so unknown-institution data never reaches the output
+	if opts.FailOnUnknownInstitution {
+		if err := checkUnknownInstitutions(files); err != nil {
+			return nil, err
+		}
+	}
+
+	if formats != nil && len(files) > 0 {
+		var kept []scanner.ScanResult
+		for _, f := range files {
+			if formats[fileFormat(p.FindParser, f.Path)] {
+				kept = append(kept, f)
+			}
+		}
+		if len(kept) == 0 {
+			return nil, fmt.Errorf("%w: format %q matched none of the %d scanned file(s)", ErrNoFiles, opts.Formats, len(files))
+		}
+		p.FormatSkipped = len(files) - len(kept)
+		files = kept
+	}
+	p.Files = files
+
+	return p, nil
+}
+
+// FindParser returns the parser for one of the pipeline's files: the -stdin-format
+// parser for StdinInput, otherwise the registry's pick for the file.
+func (p *Pipeline) FindParser(path string) (parser.Parser, error) {
+	if path == StdinInput && p.stdinParser != nil {
+		return p.stdinParser, nil
+	}
+	return p.Registry.FindParser(path)
+}
+
+// Run parses and transforms every file into budget, deduplicating against state when
+// it is non-nil, and checkpoints state every Options.CheckpointEvery files. The context
+// is checked before each file. Run doesn't validate the budget or save state at the
+// end; Stats lists statements without a period and files that failed to close for the
+// caller to act on.
+func (p *Pipeline) Run(ctx context.Context, budget *domain.Budget, state *dedup.State) (Stats, error) {
+	stats := Stats{FilesScanned: len(p.Scanned)}
+	total := len(p.Files)
+
+	for i, file := range p.Files {
+		if err := ctx.Err(); err != nil {
+			return stats, fmt.Errorf("stopped after %d of %d files: %w", i, total, err)
+		}
+
+		fileParser, err := p.FindParser(file.Path)
+		if err != nil {
+			return stats, fmt.Errorf("failed to find parser for %s: %w", file.Path, err)
+		}
+		if fileParser == nil {
+			return stats, fmt.Errorf("INTERNAL ERROR: registry.FindParser returned nil without error for %s (scanner detected this as parseable)\n\nThis indicates a bug in the parser registry.\nPlease report this issue with:\n  - File extension: %s\n  - File path: %s",
+				file.Path, filepath.Ext(file.Path), file.Path)
+		}
+
+		rawStmts, closeErr, err := p.parseFile(ctx, fileParser, file)
+		if closeErr != nil {
+			errStr := closeErr.Error()
+
+			// Fail immediately on critical errors that indicate serious issues
+			if strings.Contains(errStr, "permission") || strings.Contains(errStr, "denied") {
+				return stats, fmt.Errorf("failed to close file %s (critical permission error, stopping): %w", file.Path, closeErr)
+			} else if strings.Contains(errStr, "no space") || strings.Contains(errStr, "disk full") {
+				return stats, fmt.Errorf("failed to close file %s (disk full, stopping): %w", file.Path, closeErr)
+			} else if strings.Contains(errStr, "bad file") || strings.Contains(errStr, "stale") || strings.Contains(errStr, "filesystem") {
+				return stats, fmt.Errorf("failed to close file %s (filesystem corruption, stopping): %w", file.Path, closeErr)
+			}
+
+			// Non-critical errors are collected so every file is reported at once
+			stats.CloseErrors = append(stats.CloseErrors, fmt.Sprintf("%s: %v", file.Path, closeErr))
+		}
+
+		if err != nil {
+			return stats, fmt.Errorf("parse failed for file %d of %d (%s): %w",
+				i+1, total, file.Path, err)
+		}
+
+		progress := FileProgress{Index: i + 1, Total: total, Path: file.Path, Parser: fileParser.Name(), CloseErr: closeErr}
+
+		// Combined exports (e.g. a CSV with an account column) yield one statement per account
+		for _, rawStmt := range rawStmts {
+			// Verify parser contract (see internal/parser/parser.go): Parse() must return non-nil statement when error is nil.
+			// This defensive check catches parser implementation bugs that could cause nil pointer panics downstream.
+			// If triggered, this indicates a bug in the parser implementation that needs fixing.
+			if rawStmt == nil {
+				return stats, fmt.Errorf("parser %s violated interface contract: returned nil statement without error for %s (parser bug)",
+					fileParser.Name(), file.Path)
+			}
+
+			if !hasStatementPeriod(rawStmt) {
+				stats.MissingPeriod = append(stats.MissingPeriod, file.Path)
+				if p.opts.StrictPeriod {
+					// The run fails afterwards; keep going only to list every offending file
+					progress.Statements = append(progress.Statements, StatementResult{Raw: rawStmt})
+					continue
+				}
+			}
+
+			if p.opts.TraceSource {
+				rawStmt.SourceFile = file.Path
+			}
+			stmtStats, err := transform.TransformStatement(rawStmt, budget, state, p.Engine)
+			if err != nil {
+				return stats, fmt.Errorf("transform failed for file %d of %d (%s) with %d transactions from %s to %s: %w",
+					i+1, total, file.Path,
+					len(rawStmt.Transactions),
+					rawStmt.Period.Start().Format("2006-01-02"),
+					rawStmt.Period.End().Format("2006-01-02"),
+					err)
+			}
+			stats.add(rawStmt, stmtStats)
+			progress.Statements = append(progress.Statements, StatementResult{Raw: rawStmt, Stats: stmtStats})
+		}
+		stats.Files++
+
+		// Checkpoint state so a crash mid-run keeps dedup progress. Checkpoints run
+		// synchronously inside the loop, so they always complete before the caller's
+		// final save; the last file is left to that save.
+		if state != nil && shouldCheckpoint(i+1, total, p.opts.CheckpointEvery) {
+			if err := dedup.SaveState(state, p.opts.StateFile); err != nil {
+				return stats, fmt.Errorf("failed to checkpoint state after file %d of %d: %w", i+1, total, err)
+			}
+			progress.Checkpointed = true
+		}
+
+		if p.Progress != nil {
+			if err := p.Progress(progress); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// parseFile parses one file, or the statement on stdin for StdinInput. closeErr is the
+// error closing the file, which stdin never is.
+func (p *Pipeline) parseFile(ctx context.Context, fileParser parser.Parser, file scanner.ScanResult) (raws []*parser.RawStatement, closeErr, err error) {
+	if file.Path == StdinInput && p.stdin != nil {
+		raws, err = parser.ParseStatements(ctx, fileParser, p.stdin, file.Metadata)
+		return raws, nil, err
+	}
+
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", file.Path, err)
+	}
+	raws, err = parser.ParseStatements(ctx, fileParser, f, file.Metadata)
+
+	// Close file immediately after parsing instead of deferring to avoid file descriptor accumulation in loop
+	return raws, f.Close(), err
+}
+
+// LoadState loads the deduplication state file at path, or returns a new state when
+// it doesn't exist (existed is false). A state file that exists but can't be read, is
+// invalid, or has metadata but no fingerprints is an error rather than a fresh state,
+// since starting over would reprocess every transaction as new.
+func LoadState(path string) (state *dedup.State, existed bool, err error) {
+	state, err = dedup.LoadState(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dedup.NewState(), false, nil
+		}
+
+		// CRITICAL: State file exists but cannot be loaded. Return error to prevent:
+		// 1. Overwriting the corrupt state file with empty state
+		// 2. Reprocessing all transactions as new (creating duplicates in output)
+		// Check if this is a permission error to provide specific guidance
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) && errors.Is(pathErr.Err, os.ErrPermission) {
+			return nil, true, fmt.Errorf("failed to load state file %q: permission denied: %w\n\nCRITICAL: The state file exists but cannot be read.\nDeleting it will cause all transactions to be reprocessed as NEW (losing deduplication history).\n\nOptions:\n  1. Check file permissions: ls -la %q\n  2. Check ownership: stat %q\n  3. Backup and reset (will reprocess ALL transactions): cp %q %q.backup && rm %q",
+				path, err, path, path, path, path, path)
+		}
+
+		// Generic load failure (corruption, format error, etc)
+		return nil, true, fmt.Errorf("failed to load existing state file %q: %w\n\nCRITICAL: The state file exists but cannot be loaded.\nDeleting it will cause all transactions to be reprocessed as NEW (losing deduplication history).\n\nOptions:\n  1. Check file integrity: file %q\n  2. Backup the file: cp %q %q.backup\n  3. Try to recover: inspect JSON structure in %q\n  4. Reset (will reprocess ALL transactions): rm %q after backing up",
+			path, err, path, path, path, path, path)
+	}
+
+	// Validate loaded state integrity
+	if err := state.Validate(); err != nil {
+		return nil, true, fmt.Errorf("state file %q failed validation: %w\n\nCRITICAL: Cannot proceed with parsing.\nParsing with invalid state would allow duplicate transactions and risk further corruption.\n\nThe state file exists but contains invalid data.\nDeleting it will cause all transactions to be reprocessed as NEW (losing deduplication history).\n\nRecovery options:\n  1. Restore from backup if available\n  2. Inspect state file: cat %q\n  3. Reset (will reprocess ALL transactions): rm %q after backing up",
+			path, err, path, path)
+	}
+
+	if state.Version != dedup.CurrentVersion {
+		return nil, true, fmt.Errorf("state file version mismatch: got %d, expected %d",
+			state.Version, dedup.CurrentVersion)
+	}
+
+	if state.TotalFingerprints() == 0 && !state.Metadata.LastUpdated.IsZero() {
+		// State file has metadata but no fingerprints - likely corruption
+		return nil, true, fmt.Errorf("state file %q exists but is empty (has metadata, 0 fingerprints)\n\nCRITICAL: Parsing aborted due to suspicious empty state.\nContinuing would process all transactions without deduplication history.\n\nRecovery options:\n  1. Restore state from backup if available\n  2. Check filesystem integrity: fsck or disk utility\n  3. Delete state file to start fresh: rm %q\n\nCannot proceed until state is fixed or removed.",
+			path, path)
+	}
+
+	return state, true, nil
+}
+
+// shouldCheckpoint reports whether state should be saved after filesDone files.
+// The final file is skipped because the post-validation save always follows it.
+func shouldCheckpoint(filesDone, totalFiles, every int) bool {
+	return every > 0 && filesDone < totalFiles && filesDone%every == 0
+}
+
+// hasStatementPeriod reports whether raw declares both ends of its statement period.
+// Parsers that cannot find one leave the zero Period, which continuity and overlap
+// validation cannot use.
+func hasStatementPeriod(raw *parser.RawStatement) bool {
+	return !raw.Period.Start().IsZero() && !raw.Period.End().IsZero()
+}
+
+// checkUnknownInstitutions returns an error listing every file whose institution
+// could not be inferred from its path (reported as <unknown> in the scan summary).
+func checkUnknownInstitutions(files []scanner.ScanResult) error {
+	var unknown []string
+	for _, f := range files {
+		if f.Metadata.Institution() == "" {
+			unknown = append(unknown, f.Path)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("institution could not be determined for %d file(s):\n  - %s\n\nFiles must be organized as {input}/{institution}/{account}/statement.ext.\nMove these files into an institution directory, or run without -fail-on-unknown-institution to import them as <unknown>",
+		len(unknown), strings.Join(unknown, "\n  - "))
+}
+
+// filterByInstitution returns the files matching filter (all files when it is empty)
+// and the sorted canonical institutions found across every file. Names are compared
+// canonically and case-insensitively, so any alias selects the same files.
+func filterByInstitution(files []scanner.ScanResult, filter string, engine *rules.Engine) ([]scanner.ScanResult, []string) {
+	want := strings.TrimSpace(engine.CanonicalInstitution(filter))
+	seen := make(map[string]bool)
+	var kept []scanner.ScanResult
+	for _, f := range files {
+		inst := f.Metadata.Institution()
+		if inst == "" {
+			seen[UnknownInstitution] = true
+		} else {
+			inst = engine.CanonicalInstitution(inst)
+			seen[inst] = true
+		}
+		if filter == "" || (inst != "" && strings.EqualFold(inst, want)) {
+			kept = append(kept, f)
+		}
+	}
+
+	available := make([]string, 0, len(seen))
+	for inst := range seen {
+		available = append(available, inst)
+	}
+	sort.Strings(available)
+	return kept, available
+}
+
+// parserFormat returns the format a parser handles, the part of its name before any
+// "-" (e.g. "csv" for the csv-pnc parser)
+func parserFormat(name string) string {
+	format, _, _ := strings.Cut(name, "-")
+	return format
+}
+
+// splitRulesFiles splits a comma-separated RulesFile into paths, dropping empty entries
+func splitRulesFiles(value string) []string {
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// parseFormats parses a comma-separated format filter into the set of formats to
+// parse, or nil for all of them. Every token must be FormatAll or the format of one
+// of the registered parsers.
+func parseFormats(value string, parserNames []string) (map[string]bool, error) {
+	allowed := map[string]bool{}
+	for _, name := range parserNames {
+		allowed[parserFormat(name)] = true
+	}
+
+	formats := map[string]bool{}
+	all := false
+	for _, token := range strings.Split(value, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		switch {
+		case token == FormatAll:
+			all = true
+		case allowed[token]:
+			formats[token] = true
+		default:
+			names := make([]string, 0, len(allowed)+1)
+			for format := range allowed {
+				names = append(names, format)
+			}
+			sort.Strings(names)
+			names = append(names, FormatAll)
+			return nil, fmt.Errorf("invalid format filter %q: unknown format %q (allowed: %s, comma-separated)",
+				value, token, strings.Join(names, ", "))
+		}
+	}
+	if all {
+		return nil, nil
+	}
+	return formats, nil
+}
+
+// fileFormat returns the format of the parser find picks for path. Files no parser
+// accepts fall back to their extension, so dry runs and the parse loop still report
+// them when their format is selected.
+func fileFormat(find func(path string) (parser.Parser, error), path string) string {
+	if p, err := find(path); err == nil && p != nil {
+		return parserFormat(p.Name())
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".qfx", ".ofx":
+		return "ofx"
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
+
+// parserForFormat returns the registered parser named format, or the only one handling
+// that format (e.g. "csv" for csv-pnc).
+func parserForFormat(reg *registry.Registry, format string) (parser.Parser, error) {
+	names := reg.ListParsers()
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return nil, fmt.Errorf("reading from stdin requires a format (one of: %s)", strings.Join(names, ", "))
+	}
+
+	var matches []string
+	for _, name := range names {
+		if name == format {
+			matches = []string{name}
+			break
+		}
+		if parserFormat(name) == format {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown stdin format %q (one of: %s)", format, strings.Join(names, ", "))
+	case 1:
+		return reg.Parser(matches[0])
+	default:
+		return nil, fmt.Errorf("stdin format %q matches several parsers (%s); name one", format, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,430 @@
+package finparse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rumor-ml/commons.systems/finparse/internal/dedup"
+	"github.com/rumor-ml/commons.systems/finparse/internal/parser"
+	"github.com/rumor-ml/commons.systems/finparse/internal/rules"
+	"github.com/rumor-ml/commons.systems/finparse/internal/scanner"
+)
+
+// ofxStatement returns a minimal credit card OFX statement with one transaction
+func ofxStatement(month int, fitID string, amount string) string {
+	return fmt.Sprintf(`OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<SIGNONMSGSRSV1>
+<SONRS>
+<STATUS>
+<CODE>0
+<SEVERITY>INFO
+</STATUS>
+<DTSERVER>20250101120000
+<LANGUAGE>ENG
+<FI>
+<ORG>AMEX
+<FID>1000
+</FI>
+</SONRS>
+</SIGNONMSGSRSV1>
+<CREDITCARDMSGSRSV1>
+<CCSTMTTRNRS>
+<TRNUID>1
+<STATUS>
+<CODE>0
+<SEVERITY>INFO
+</STATUS>
+<CCSTMTRS>
+<CURDEF>USD
+<CCACCTFROM>
+<ACCTID>2011
+</CCACCTFROM>
+<BANKTRANLIST>
+<DTSTART>2025%02d01000000
+<DTEND>2025%02d28235959
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>2025%02d05120000
+<TRNAMT>%s
+<FITID>%s
+<NAME>Purchase %s
+</STMTTRN>
+</BANKTRANLIST>
+</CCSTMTRS>
+</CCSTMTTRNRS>
+</CREDITCARDMSGSRSV1>
+</OFX>`, month, month, month, amount, fitID, fitID)
+}
+
+// writeStatements creates two one-transaction statements under
+// {dir}/american_express/2011 and returns dir
+func writeStatements(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	acctDir := filepath.Join(dir, "american_express", "2011")
+	if err := os.MkdirAll(acctDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for month, fitID := range map[int]string{1: "TXN001", 2: "TXN002"} {
+		name := filepath.Join(acctDir, fmt.Sprintf("stmt%d.qfx", month))
+		if err := os.WriteFile(name, []byte(ofxStatement(month, fitID, "-10.00")), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestProcess(t *testing.T) {
+	dir := writeStatements(t)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	var calls []FileProgress
+	opts := Options{
+		InputDir:  dir,
+		StateFile: statePath,
+		Progress: func(fp FileProgress) error {
+			calls = append(calls, fp)
+			return nil
+		},
+	}
+
+	budget, stats, err := Process(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if got := budget.TransactionCount(); got != 2 {
+		t.Errorf("Expected 2 transactions in the budget, got %d", got)
+	}
+	if stats.FilesScanned != 2 || stats.Files != 2 || stats.Statements != 2 || stats.Transactions != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.Validation == nil || len(stats.Validation.Errors) != 0 {
+		t.Errorf("Expected a passing validation result, got %+v", stats.Validation)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 progress calls, got %d", len(calls))
+	}
+	for i, fp := range calls {
+		if fp.Index != i+1 || fp.Total != 2 || fp.Parser == "" || len(fp.Statements) != 1 || fp.Statements[0].Stats == nil {
+			t.Errorf("Unexpected progress call %d: %+v", i, fp)
+		}
+	}
+
+	state, err := dedup.LoadState(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load saved state: %v", err)
+	}
+	if coverage, _ := stats.Coverage(); state.Metadata.LastCoverage == nil || *state.Metadata.LastCoverage != coverage {
+		t.Errorf("Expected the state to record coverage %.1f, got %v", coverage, state.Metadata.LastCoverage)
+	}
+
+	// The saved state makes a second run skip every transaction
+	calls = nil
+	budget, stats, err = Process(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Second Process failed: %v", err)
+	}
+	if budget.TransactionCount() != 0 || stats.DuplicatesSkipped != 2 {
+		t.Errorf("Expected 2 duplicates and an empty budget, got %d skipped and %d transactions",
+			stats.DuplicatesSkipped, budget.TransactionCount())
+	}
+}
+
+func TestProcess_SniffsFilesWithoutExtension(t *testing.T) {
+	dir := writeStatements(t)
+	acctDir := filepath.Join(dir, "american_express", "2011")
+	if err := os.WriteFile(filepath.Join(acctDir, "download"), []byte(ofxStatement(3, "TXN003", "-10.00")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(acctDir, "notes.txt"), []byte("not a statement"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	budget, stats, err := Process(context.Background(), Options{InputDir: dir})
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.FilesScanned != 3 || budget.TransactionCount() != 3 {
+		t.Errorf("Expected the extensionless OFX file to be parsed and notes.txt skipped, got %d files and %d transactions",
+			stats.FilesScanned, budget.TransactionCount())
+	}
+}
+
+func TestProcess_SingleFile(t *testing.T) {
+	dir := writeStatements(t)
+
+	budget, stats, err := Process(context.Background(), Options{
+		InputDir:    filepath.Join(dir, "american_express", "2011", "stmt1.qfx"),
+		Institution: "American Express",
+	})
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.FilesScanned != 1 || budget.TransactionCount() != 1 {
+		t.Errorf("Expected only the named file to be parsed, got %d files and %d transactions",
+			stats.FilesScanned, budget.TransactionCount())
+	}
+}
+
+func TestProcess_Stdin(t *testing.T) {
+	budget, stats, err := Process(context.Background(), Options{
+		InputDir:    StdinInput,
+		Stdin:       strings.NewReader(ofxStatement(1, "TXN001", "-10.00")),
+		StdinFormat: "OFX",
+		Institution: "American Express",
+	})
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.Files != 1 || budget.TransactionCount() != 1 {
+		t.Errorf("Expected one statement from stdin, got %d files and %d transactions", stats.Files, budget.TransactionCount())
+	}
+
+	for _, tc := range []struct {
+		opts    Options
+		wantErr string
+	}{
+		{Options{InputDir: StdinInput}, "requires a format"},
+		{Options{InputDir: StdinInput, StdinFormat: "pdf"}, `unknown stdin format "pdf"`},
+		{Options{InputDir: t.TempDir(), StdinFormat: "ofx"}, "only used when reading from stdin"},
+	} {
+		if _, err := Prepare(tc.opts); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("Prepare(%+v) = %v, want error containing %q", tc.opts, err, tc.wantErr)
+		}
+	}
+}
+
+func TestProcess_TraceSource(t *testing.T) {
+	dir := writeStatements(t)
+	csvDir := filepath.Join(dir, "pnc", "5555")
+	if err := os.MkdirAll(csvDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	csvPath := filepath.Join(csvDir, "march.csv")
+	csvContent := "5555,2025/03/01,2025/03/31,0.00,0.00\n2025/03/04,12.50,Cafe,Lunch,REF1,DEBIT\n"
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	budget, _, err := Process(context.Background(), Options{InputDir: dir, TraceSource: true})
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	sources := make(map[string]string)
+	for _, txn := range budget.GetTransactions() {
+		if txn.Source == nil {
+			t.Fatalf("Transaction %s has no source", txn.ID)
+		}
+		sources[txn.Description] = txn.Source.String()
+	}
+	if got := sources["Cafe"]; got != csvPath+":2" {
+		t.Errorf("CSV transaction source = %q, want %q", got, csvPath+":2")
+	}
+	if want := filepath.Join(dir, "american_express", "2011", "stmt1.qfx"); sources["Purchase TXN001"] != want {
+		t.Errorf("OFX transaction source = %q, want %q", sources["Purchase TXN001"], want)
+	}
+
+	// Untraced runs leave sources out
+	budget, _, err = Process(context.Background(), Options{InputDir: dir})
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	for _, txn := range budget.GetTransactions() {
+		if txn.Source != nil {
+			t.Errorf("Transaction %s has source %v without TraceSource", txn.ID, txn.Source)
+		}
+	}
+}
+
+func TestProcess_ProgressErrorStops(t *testing.T) {
+	dir := writeStatements(t)
+	errStop := errors.New("stop")
+
+	_, stats, err := Process(context.Background(), Options{
+		InputDir: dir,
+		Progress: func(FileProgress) error { return errStop },
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("Expected the callback's error, got: %v", err)
+	}
+	if stats.Files != 1 {
+		t.Errorf("Expected processing to stop after 1 file, got %d", stats.Files)
+	}
+}
+
+func TestProcess_Canceled(t *testing.T) {
+	dir := writeStatements(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := Process(ctx, Options{InputDir: dir}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestProcess_NoFiles(t *testing.T) {
+	if _, _, err := Process(context.Background(), Options{InputDir: t.TempDir()}); !errors.Is(err, ErrNoFiles) {
+		t.Errorf("Expected ErrNoFiles for an empty directory, got: %v", err)
+	}
+
+	dir := writeStatements(t)
+	_, _, err := Process(context.Background(), Options{InputDir: dir, Institution: "chase"})
+	if !errors.Is(err, ErrNoFiles) || !strings.Contains(err.Error(), "- American Express") {
+		t.Errorf("Expected ErrNoFiles listing the available institutions, got: %v", err)
+	}
+}
+
+func TestHasStatementPeriod(t *testing.T) {
+	// A parser that found no period leaves the zero Period
+	if hasStatementPeriod(&parser.RawStatement{}) {
+		t.Fatal("Expected zero period to be reported as missing")
+	}
+}
+
+// TestShouldCheckpoint tests checkpoint scheduling
+func TestShouldCheckpoint(t *testing.T) {
+	tests := []struct {
+		filesDone, totalFiles, every int
+		want                         bool
+	}{
+		{1, 10, 0, false}, // Disabled
+		{1, 10, 1, true},
+		{2, 10, 3, false},
+		{3, 10, 3, true},
+		{6, 10, 3, true},
+		{10, 10, 5, false}, // Last file is covered by the final save
+		{10, 10, 1, false},
+	}
+	for _, tt := range tests {
+		got := shouldCheckpoint(tt.filesDone, tt.totalFiles, tt.every)
+		if got != tt.want {
+			t.Errorf("shouldCheckpoint(%d, %d, %d) = %v, want %v",
+				tt.filesDone, tt.totalFiles, tt.every, got, tt.want)
+		}
+	}
+}
+
+func TestFilterByInstitution(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"chase/1234", "chase_bank/5678", "american_express/9012"} {
+		acctDir := filepath.Join(tmpDir, filepath.FromSlash(dir))
+		if err := os.MkdirAll(acctDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(acctDir, "statement.ofx"), []byte("OFXHEADER:100\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "loose.ofx"), []byte("OFXHEADER:100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := scanner.New(tmpDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	engine, err := rules.NewEngine([]byte("rules: []\ninstitution_aliases:\n  Chase:\n    - Chase Bank\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filter string
+		want   int
+	}{
+		{"", 4},
+		{"chase", 2},      // Case-insensitive, includes the Chase Bank alias
+		{"CHASE BANK", 2}, // An alias selects the canonical institution
+		{"american express", 1},
+		{"wells fargo", 0},
+	}
+	for _, tt := range tests {
+		kept, available := filterByInstitution(files, tt.filter, engine)
+		if len(kept) != tt.want {
+			t.Errorf("filter %q kept %d files, want %d", tt.filter, len(kept), tt.want)
+		}
+		want := []string{"<unknown>", "American Express", "Chase"}
+		if strings.Join(available, ",") != strings.Join(want, ",") {
+			t.Errorf("available = %v, want %v", available, want)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	parsers := []string{"ofx", "csv-pnc"}
+	tests := []struct {
+		value   string
+		want    []string // nil means all formats
+		wantErr bool
+	}{
+		{"all", nil, false},
+		{"ofx", []string{"ofx"}, false},
+		{"ofx,csv", []string{"csv", "ofx"}, false},
+		{" OFX , csv ", []string{"csv", "ofx"}, false},
+		{"ofx,all", nil, false},
+		{"ofx,cvs", nil, true},
+		{"pdf", nil, true},
+		{"", nil, true},
+		{"ofx,", nil, true},
+	}
+	for _, tt := range tests {
+		formats, err := parseFormats(tt.value, parsers)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFormats(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			if !strings.Contains(err.Error(), "allowed: csv, ofx, all") {
+				t.Errorf("parseFormats(%q) error should list the allowed formats, got: %v", tt.value, err)
+			}
+			continue
+		}
+		if tt.want == nil {
+			if formats != nil {
+				t.Errorf("parseFormats(%q) = %v, want all formats", tt.value, formats)
+			}
+			continue
+		}
+		var got []string
+		for format := range formats {
+			got = append(got, format)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("parseFormats(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSplitRulesFiles(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"rules.yaml", []string{"rules.yaml"}},
+		{"groceries.yaml,dining.yaml", []string{"groceries.yaml", "dining.yaml"}},
+		{" rules/ , extra.yaml ,", []string{"rules/", "extra.yaml"}},
+		{",", nil},
+	}
+	for _, tt := range tests {
+		if got := splitRulesFiles(tt.value); strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("splitRulesFiles(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
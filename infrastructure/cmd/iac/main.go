@@ -21,6 +21,10 @@ func main() {
 		autoApprove   = flag.Bool("auto-approve", false, "Auto-approve Terraform changes")
 		ci            = flag.Bool("ci", false, "CI mode: implies --skip-gcp-setup --auto-approve")
 		verbose       = flag.Bool("verbose", false, "Show detailed output")
+		planOnly      = flag.Bool("plan-only", false, "Only run terraform plan and print the diff; never apply")
+		planOut       = flag.String("plan-out", "", "With --plan-only, also write the plan diff to this file")
+		destroy       = flag.Bool("destroy", false, "Destroy Terraform-managed resources (requires --project-id; irreversible)")
+		forceRefresh  = flag.Bool("force-refresh", false, "Re-check GCP APIs even if they were confirmed enabled in the last 24h")
 	)
 
 	flag.Usage = func() {
@@ -36,10 +40,13 @@ func main() {
 
 	flag.Parse()
 
-	// CI mode implies skip-gcp-setup and auto-approve
+	// CI mode implies skip-gcp-setup and auto-approve (but never auto-approve for a
+	// plan-only run, and destroy needs an explicit --auto-approve)
 	if *ci {
 		*skipGCPSetup = true
-		*autoApprove = true
+		if !*planOnly && !*destroy {
+			*autoApprove = true
+		}
 	}
 
 	// Load configuration
@@ -52,16 +59,23 @@ func main() {
 		AutoApprove:   *autoApprove,
 		CI:            *ci,
 		Verbose:       *verbose,
+		PlanOnly:      *planOnly,
+		PlanOut:       *planOut,
+		Destroy:       *destroy,
+		ForceRefresh:  *forceRefresh,
 	}
 
-	// If project ID not provided via flag, check environment
-	if cfg.ProjectID == "" {
-		cfg.ProjectID = os.Getenv("GCP_PROJECT_ID")
+	// If project ID not provided via flag, check environment (never for destroy)
+	if cfg.ProjectID != "" {
+		cfg.ProjectIDSource = config.ProjectIDFromFlag
+	} else if envProjectID := os.Getenv("GCP_PROJECT_ID"); envProjectID != "" && !cfg.Destroy {
+		cfg.ProjectID = envProjectID
+		cfg.ProjectIDSource = config.ProjectIDFromEnv
 	}
 
-	// Validate configuration
+	// Validate configuration before doing any work
 	if err := cfg.Validate(); err != nil {
-		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		output.Error(fmt.Sprintf("Configuration error: %v", err))
 		os.Exit(1)
 	}
 
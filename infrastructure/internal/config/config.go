@@ -7,12 +7,24 @@ import (
 
 var repoNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
 
+// projectIDPattern follows GCP's rules: 6-30 lowercase letters, digits or hyphens,
+// starting with a letter and not ending with a hyphen
+var projectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+
+// Project ID sources, named in validation errors
+const (
+	ProjectIDFromFlag   = "--project-id flag"
+	ProjectIDFromEnv    = "GCP_PROJECT_ID environment variable"
+	ProjectIDFromGCloud = "gcloud config"
+)
+
 // Config holds the configuration for the infrastructure setup
 type Config struct {
 	// Required configuration
-	ProjectID string
-	RepoOwner string
-	RepoName  string
+	ProjectID       string
+	ProjectIDSource string // Where ProjectID came from (ProjectIDFrom*)
+	RepoOwner       string
+	RepoName        string
 
 	// Optional configuration
 	Region string
@@ -23,6 +35,10 @@ type Config struct {
 	AutoApprove   bool
 	CI            bool
 	Verbose       bool
+	PlanOnly      bool   // Run terraform plan and never apply
+	PlanOut       string // File to write the plan-only diff to
+	Destroy       bool   // Run terraform destroy instead of setup
+	ForceRefresh  bool   // Re-check GCP APIs even if recently confirmed enabled
 
 	// Populated during runtime
 	WorkloadIdentityProvider string
@@ -45,5 +61,47 @@ func (c *Config) Validate() error {
 	if !repoNamePattern.MatchString(c.RepoName) {
 		return fmt.Errorf("invalid repo-name: must contain only alphanumeric, dots, hyphens, underscores")
 	}
+	if c.ProjectID != "" || c.ProjectIDSource != "" {
+		if err := ValidateProjectID(c.ProjectID, c.ProjectIDSource); err != nil {
+			return err
+		}
+	} else if c.CI {
+		return fmt.Errorf("GCP project ID is required in CI mode: set --project-id or GCP_PROJECT_ID")
+	}
+	if c.PlanOnly && c.AutoApprove {
+		return fmt.Errorf("--plan-only and --auto-approve are mutually exclusive: a plan-only run never applies")
+	}
+	if c.PlanOnly && c.SkipTerraform {
+		return fmt.Errorf("--plan-only and --skip-terraform are mutually exclusive")
+	}
+	if c.Destroy {
+		if c.ProjectIDSource != ProjectIDFromFlag {
+			return fmt.Errorf("--destroy requires an explicit --project-id (GCP_PROJECT_ID and gcloud config are not used for destroy)")
+		}
+		if c.PlanOnly || c.SkipTerraform {
+			return fmt.Errorf("--destroy cannot be combined with --plan-only or --skip-terraform")
+		}
+		if c.CI && !c.AutoApprove {
+			return fmt.Errorf("--destroy in CI mode requires --auto-approve")
+		}
+	}
+	if c.PlanOut != "" && !c.PlanOnly {
+		return fmt.Errorf("--plan-out requires --plan-only")
+	}
+	return nil
+}
+
+// ValidateProjectID checks a GCP project ID against GCP's naming rules. source names
+// where the ID came from for the error message.
+func ValidateProjectID(projectID, source string) error {
+	if source == "" {
+		source = "configuration"
+	}
+	if projectID == "" {
+		return fmt.Errorf("GCP project ID from %s is empty", source)
+	}
+	if !projectIDPattern.MatchString(projectID) {
+		return fmt.Errorf("invalid GCP project ID %q from %s: must be 6-30 characters of lowercase letters, digits and hyphens, start with a letter and not end with a hyphen", projectID, source)
+	}
 	return nil
 }
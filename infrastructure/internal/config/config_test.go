@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestValidate_ValidInputs(t *testing.T) {
 	valid := []struct {
@@ -67,3 +70,100 @@ func TestValidate_EmptyStrings(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate_PlanOnlyFlags(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"plan only", Config{PlanOnly: true}, false},
+		{"plan only with output file", Config{PlanOnly: true, PlanOut: "plan.txt"}, false},
+		{"plan only with auto-approve", Config{PlanOnly: true, AutoApprove: true}, true},
+		{"plan only with skip-terraform", Config{PlanOnly: true, SkipTerraform: true}, true},
+		{"plan-out without plan only", Config{PlanOut: "plan.txt"}, true},
+	}
+	for _, tc := range testCases {
+		tc.cfg.RepoOwner, tc.cfg.RepoName = "rumor-ml", "commons.systems"
+		if err := tc.cfg.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateProjectID(t *testing.T) {
+	testCases := []struct {
+		id      string
+		wantErr bool
+	}{
+		{"chalanding", false},
+		{"my-project-123", false},
+		{"abcdef", false},
+		{"a23456789012345678901234567890", false},
+		{"", true},
+		{"abcde", true},                           // Too short
+		{"a234567890123456789012345678901", true}, // Too long
+		{"1project", true},
+		{"My-Project", true},
+		{"my_project", true},
+		{"my-project-", true},
+		{"my project", true},
+	}
+	for _, tc := range testCases {
+		err := ValidateProjectID(tc.id, ProjectIDFromEnv)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateProjectID(%q) error = %v, wantErr %v", tc.id, err, tc.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), ProjectIDFromEnv) {
+			t.Errorf("ValidateProjectID(%q) error %q does not name the source", tc.id, err)
+		}
+	}
+}
+
+func TestValidate_ProjectID(t *testing.T) {
+	cfg := Config{RepoOwner: "rumor-ml", RepoName: "commons.systems", ProjectID: "Bad_ID", ProjectIDSource: ProjectIDFromFlag}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "--project-id flag") {
+		t.Errorf("Expected an error naming the flag, got %v", err)
+	}
+
+	cfg = Config{RepoOwner: "rumor-ml", RepoName: "commons.systems", CI: true}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an error for CI mode without a project ID")
+	}
+
+	cfg = Config{RepoOwner: "rumor-ml", RepoName: "commons.systems"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Interactive mode may resolve the project ID later, got %v", err)
+	}
+}
+
+func TestValidate_Destroy(t *testing.T) {
+	base := func() Config {
+		return Config{RepoOwner: "rumor-ml", RepoName: "commons.systems", Destroy: true}
+	}
+	testCases := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr bool
+	}{
+		{"explicit project ID", func(c *Config) { c.ProjectID, c.ProjectIDSource = "my-project", ProjectIDFromFlag }, false},
+		{"project ID from env", func(c *Config) { c.ProjectID, c.ProjectIDSource = "my-project", ProjectIDFromEnv }, true},
+		{"no project ID", func(c *Config) {}, true},
+		{"CI with auto-approve", func(c *Config) {
+			c.ProjectID, c.ProjectIDSource, c.CI, c.AutoApprove = "my-project", ProjectIDFromFlag, true, true
+		}, false},
+		{"CI without auto-approve", func(c *Config) {
+			c.ProjectID, c.ProjectIDSource, c.CI = "my-project", ProjectIDFromFlag, true
+		}, true},
+		{"with plan-only", func(c *Config) {
+			c.ProjectID, c.ProjectIDSource, c.PlanOnly = "my-project", ProjectIDFromFlag, true
+		}, true},
+	}
+	for _, tc := range testCases {
+		cfg := base()
+		tc.mutate(&cfg)
+		if err := cfg.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package gcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// APICacheTTL is how long APIs confirmed enabled for a project are trusted before
+// EnableAPIs checks them again
+const APICacheTTL = 24 * time.Hour
+
+// apiCacheEntry records when a project's APIs were last confirmed enabled
+type apiCacheEntry struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	APIs      []string  `json:"apis"`
+}
+
+// apiCache maps project IDs to their last confirmed API enablement
+type apiCache map[string]apiCacheEntry
+
+// apiCachePath returns where the API enablement cache is stored, e.g.
+// ~/.config/commons.systems/iac/api-cache.json
+func apiCachePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "commons.systems", "iac", "api-cache.json"), nil
+}
+
+// loadAPICache reads the cache at path. A missing file gives an empty cache.
+func loadAPICache(path string) (apiCache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return apiCache{}, nil
+	}
+	if err != nil {
+		return apiCache{}, fmt.Errorf("failed to read API cache: %w", err)
+	}
+
+	cache := apiCache{}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return apiCache{}, fmt.Errorf("failed to parse API cache %s: %w", path, err)
+	}
+	return cache, nil
+}
+
+// saveAPICache writes the cache to path, creating its directory if needed
+func saveAPICache(path string, cache apiCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create API cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API cache: %w", err)
+	}
+
+	// Write to a temp file and rename so a failed write can't leave a truncated cache
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write API cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write API cache: %w", err)
+	}
+	return nil
+}
+
+// confirmed returns when apis were last confirmed enabled for projectID, and whether
+// that is recent enough (within APICacheTTL of now) to skip checking them again. An
+// entry that doesn't cover every API in apis never counts.
+func (c apiCache) confirmed(projectID string, apis []string, now time.Time) (time.Time, bool) {
+	entry, ok := c[projectID]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	enabled := make(map[string]bool, len(entry.APIs))
+	for _, api := range entry.APIs {
+		enabled[api] = true
+	}
+	for _, api := range apis {
+		if !enabled[api] {
+			return entry.CheckedAt, false
+		}
+	}
+
+	age := now.Sub(entry.CheckedAt)
+	return entry.CheckedAt, age >= 0 && age < APICacheTTL
+}
+
+// record notes that apis were confirmed enabled for projectID at now
+func (c apiCache) record(projectID string, apis []string, now time.Time) {
+	c[projectID] = apiCacheEntry{
+		CheckedAt: now,
+		APIs:      append([]string(nil), apis...),
+	}
+}
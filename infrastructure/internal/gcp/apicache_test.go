@@ -0,0 +1,73 @@
+package gcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAPICache_Confirmed(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	apis := []string{"run.googleapis.com", "iam.googleapis.com"}
+
+	cache := apiCache{}
+	if _, ok := cache.confirmed("my-project", apis, now); ok {
+		t.Error("Empty cache should not confirm anything")
+	}
+
+	cache.record("my-project", apis, now.Add(-time.Hour))
+	testCases := []struct {
+		name      string
+		projectID string
+		apis      []string
+		now       time.Time
+		want      bool
+	}{
+		{"fresh", "my-project", apis, now, true},
+		{"other project", "other-project", apis, now, false},
+		{"expired", "my-project", apis, now.Add(APICacheTTL), false},
+		{"new required API", "my-project", append(apis, "sts.googleapis.com"), now, false},
+		{"subset of cached APIs", "my-project", apis[:1], now, true},
+		{"clock moved backwards", "my-project", apis, now.Add(-2 * time.Hour), false},
+	}
+	for _, tc := range testCases {
+		if _, got := cache.confirmed(tc.projectID, tc.apis, tc.now); got != tc.want {
+			t.Errorf("%s: confirmed() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAPICache_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iac", "api-cache.json")
+
+	cache, err := loadAPICache(path)
+	if err != nil || len(cache) != 0 {
+		t.Fatalf("loadAPICache() of missing file = %v, %v; want empty cache", cache, err)
+	}
+
+	checkedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache.record("project-a", []string{"run.googleapis.com"}, checkedAt)
+	cache.record("project-b", []string{"iam.googleapis.com"}, checkedAt)
+	if err := saveAPICache(path, cache); err != nil {
+		t.Fatalf("saveAPICache() error = %v", err)
+	}
+
+	loaded, err := loadAPICache(path)
+	if err != nil {
+		t.Fatalf("loadAPICache() error = %v", err)
+	}
+	if got, ok := loaded.confirmed("project-a", []string{"run.googleapis.com"}, checkedAt); !ok || !got.Equal(checkedAt) {
+		t.Errorf("Loaded cache confirmed(project-a) = %v, %v; want %v, true", got, ok, checkedAt)
+	}
+	if _, ok := loaded.confirmed("project-b", []string{"iam.googleapis.com"}, checkedAt); !ok {
+		t.Error("Saving one project should keep the others")
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadAPICache(path); err == nil {
+		t.Error("loadAPICache() of a corrupt file should fail")
+	}
+}
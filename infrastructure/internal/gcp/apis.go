@@ -3,6 +3,7 @@ package gcp
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rumor-ml/commons.systems/infrastructure/internal/exec"
 	"github.com/rumor-ml/commons.systems/infrastructure/internal/output"
@@ -25,8 +26,9 @@ var requiredAPIs = []string{
 	"identitytoolkit.googleapis.com",
 }
 
-// EnableAPIs enables all required GCP APIs
-func EnableAPIs(projectID string) error {
+// EnableAPIs enables all required GCP APIs. If they were confirmed enabled for projectID
+// within APICacheTTL, the check is skipped unless forceRefresh is set.
+func EnableAPIs(projectID string, forceRefresh bool) error {
 	output.Step(1, 5, "Enabling required GCP APIs...")
 
 	// Set project
@@ -34,6 +36,24 @@ func EnableAPIs(projectID string) error {
 		return fmt.Errorf("failed to set project: %w", err)
 	}
 
+	// The cache only saves time, so any problem with it just means checking the APIs
+	cachePath, err := apiCachePath()
+	if err != nil {
+		output.Warning(fmt.Sprintf("API cache disabled: %v", err))
+	}
+	cache := apiCache{}
+	if cachePath != "" {
+		if cache, err = loadAPICache(cachePath); err != nil {
+			output.Warning(fmt.Sprintf("Ignoring API cache: %v", err))
+		}
+	}
+	if !forceRefresh {
+		if checkedAt, ok := cache.confirmed(projectID, requiredAPIs, time.Now()); ok {
+			output.Success(fmt.Sprintf("APIs confirmed enabled %s ago (cached; use --force-refresh to re-check)", time.Since(checkedAt).Round(time.Minute)))
+			return nil
+		}
+	}
+
 	// First, try to enable the Service Usage API
 	output.Info("Checking Service Usage API...")
 	result, err := exec.Run(fmt.Sprintf("gcloud services enable serviceusage.googleapis.com --project=%s 2>&1", projectID), true)
@@ -61,5 +81,12 @@ Steps:
 	}
 
 	output.Success("APIs enabled (already-enabled APIs skipped)")
+
+	if cachePath != "" {
+		cache.record(projectID, requiredAPIs, time.Now())
+		if err := saveAPICache(cachePath, cache); err != nil {
+			output.Warning(fmt.Sprintf("Failed to save API cache: %v", err))
+		}
+	}
 	return nil
 }
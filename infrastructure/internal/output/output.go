@@ -47,6 +47,22 @@ func Error(text string) {
 	red.Printf("Error: %s\n", text)
 }
 
+// Diff prints a Terraform plan, coloring added, removed and changed lines
+func Diff(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		switch trimmed := strings.TrimLeft(line, " "); {
+		case strings.HasPrefix(trimmed, "-/+"), strings.HasPrefix(trimmed, "~"):
+			yellow.Println(line)
+		case strings.HasPrefix(trimmed, "+"):
+			green.Println(line)
+		case strings.HasPrefix(trimmed, "-"):
+			red.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
 // BlueText prints blue text
 func BlueText(text string) {
 	blue.Println(text)
@@ -1,7 +1,11 @@
 package runner
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/rumor-ml/commons.systems/infrastructure/internal/config"
 	"github.com/rumor-ml/commons.systems/infrastructure/internal/firebase"
@@ -48,6 +52,16 @@ func (r *Runner) Run() error {
 		return err
 	}
 
+	// A plan-only run changes nothing: skip setup steps and only plan
+	if r.config.PlanOnly {
+		return r.planTerraform()
+	}
+
+	// Destroy only touches Terraform-managed resources; GCP setup is never run
+	if r.config.Destroy {
+		return r.destroyTerraform()
+	}
+
 	// Step 4: GCP Setup (unless skipped)
 	if !r.config.SkipGCPSetup {
 		if err := r.setupGCP(); err != nil {
@@ -107,7 +121,11 @@ func (r *Runner) resolveProjectID() error {
 	// Get from gcloud config
 	projectID, err := gcp.GetDefaultProject()
 	if err == nil && projectID != "" {
+		if err := config.ValidateProjectID(projectID, config.ProjectIDFromGCloud); err != nil {
+			return err
+		}
 		r.config.ProjectID = projectID
+		r.config.ProjectIDSource = config.ProjectIDFromGCloud
 		output.Info(fmt.Sprintf("Using project from gcloud config: %s", projectID))
 		return nil
 	}
@@ -120,7 +138,7 @@ func (r *Runner) setupGCP() error {
 	output.Header("GCP Setup")
 
 	// Enable APIs
-	if err := gcp.EnableAPIs(r.config.ProjectID); err != nil {
+	if err := gcp.EnableAPIs(r.config.ProjectID, r.config.ForceRefresh); err != nil {
 		return fmt.Errorf("failed to enable APIs: %w", err)
 	}
 
@@ -176,6 +194,63 @@ func (r *Runner) setupFirebase() error {
 	return nil
 }
 
+// planTerraform runs terraform plan without creating or applying anything. The
+// state bucket must already exist.
+func (r *Runner) planTerraform() error {
+	output.Header("Terraform Plan")
+
+	// Generate terraform.tfvars (a local file)
+	if err := terraform.GenerateVars(r.config.ProjectID); err != nil {
+		return fmt.Errorf("failed to generate terraform.tfvars: %w", err)
+	}
+
+	if err := terraform.Plan(r.config.PlanOut); err != nil {
+		return fmt.Errorf("failed to plan terraform: %w", err)
+	}
+
+	output.Success("\nPlan-only run complete; nothing was applied ✓")
+	return nil
+}
+
+// destroyTerraform tears down Terraform-managed resources after the user types the
+// project ID to confirm (or with --auto-approve)
+func (r *Runner) destroyTerraform() error {
+	output.Header("Terraform Destroy")
+	output.Error(fmt.Sprintf("This DESTROYS every Terraform-managed resource in project %s.", r.config.ProjectID))
+	output.Error("This cannot be undone. Data in destroyed resources is lost.")
+
+	if r.config.AutoApprove {
+		output.Warning("--auto-approve set: destroying without confirmation")
+	} else if err := confirmDestroy(os.Stdin, r.config.ProjectID); err != nil {
+		return err
+	}
+
+	// Generate terraform.tfvars so destroy targets the confirmed project
+	if err := terraform.GenerateVars(r.config.ProjectID); err != nil {
+		return fmt.Errorf("failed to generate terraform.tfvars: %w", err)
+	}
+
+	if err := terraform.Destroy(); err != nil {
+		return fmt.Errorf("failed to destroy terraform resources: %w", err)
+	}
+
+	output.Success("\nTerraform-managed resources destroyed")
+	return nil
+}
+
+// confirmDestroy asks the user to type the project ID and fails on any mismatch
+func confirmDestroy(in io.Reader, projectID string) error {
+	output.YellowText(fmt.Sprintf("Type the project ID (%s) to confirm:", projectID))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return fmt.Errorf("destroy not confirmed: %w", err)
+	}
+	if strings.TrimSpace(answer) != projectID {
+		return fmt.Errorf("destroy not confirmed: typed %q, expected %q", strings.TrimSpace(answer), projectID)
+	}
+	return nil
+}
+
 // setupTerraform runs Terraform setup steps
 func (r *Runner) setupTerraform() error {
 	output.Header("Terraform Setup")
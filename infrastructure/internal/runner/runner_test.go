@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfirmDestroy(t *testing.T) {
+	testCases := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"my-project\n", false},
+		{"  my-project  \n", false},
+		{"my-project", false}, // EOF without newline
+		{"yes\n", true},
+		{"my-project-2\n", true},
+		{"\n", true},
+		{"", true},
+	}
+	for _, tc := range testCases {
+		err := confirmDestroy(strings.NewReader(tc.input), "my-project")
+		if (err != nil) != tc.wantErr {
+			t.Errorf("confirmDestroy(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+		}
+	}
+}
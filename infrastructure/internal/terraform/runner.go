@@ -18,10 +18,131 @@ func IsTerraformInstalled() bool {
 func Run(autoApprove bool) error {
 	output.Info("Running Terraform...")
 
+	restore, err := enterTerraformDir()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if err := initAndValidate(); err != nil {
+		return err
+	}
+
+	// Terraform plan
+	output.Info("Running terraform plan...")
+	if _, err := exec.Run("terraform plan -no-color -out=tfplan", false); err != nil {
+		return fmt.Errorf("terraform plan failed: %w", err)
+	}
+	output.Success("Terraform plan created")
+
+	// Terraform apply
+	output.Info("Running terraform apply...")
+	applyCmd := "terraform apply tfplan"
+	if !autoApprove {
+		applyCmd = "terraform apply -no-color"
+	}
+
+	if _, err := exec.Run(applyCmd, false); err != nil {
+		return fmt.Errorf("terraform apply failed: %w", err)
+	}
+	output.Success("Terraform applied successfully")
+
+	return nil
+}
+
+// Plan runs terraform plan and prints the diff without applying it. When planOut is
+// set, the diff is also written there (relative to the current directory) so CI can
+// post it.
+func Plan(planOut string) error {
+	output.Info("Running Terraform (plan only)...")
+
+	if planOut != "" {
+		abs, err := filepath.Abs(planOut)
+		if err != nil {
+			return fmt.Errorf("invalid --plan-out path: %w", err)
+		}
+		planOut = abs
+	}
+
+	restore, err := enterTerraformDir()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if err := initAndValidate(); err != nil {
+		return err
+	}
+
+	// -detailed-exitcode: 0 = no changes, 1 = error, 2 = changes present
+	output.Info("Running terraform plan...")
+	result, err := exec.Run("terraform plan -no-color -input=false -detailed-exitcode -out=tfplan", true)
+	if err != nil {
+		return fmt.Errorf("terraform plan failed: %w", err)
+	}
+	if result.ExitCode != 0 && result.ExitCode != 2 {
+		return fmt.Errorf("terraform plan failed: %s", result.Stderr)
+	}
+
+	shown, err := exec.Run("terraform show -no-color tfplan", true)
+	if err != nil {
+		return fmt.Errorf("terraform show failed: %w", err)
+	}
+	if shown.ExitCode != 0 {
+		return fmt.Errorf("terraform show failed: %s", shown.Stderr)
+	}
+
+	output.Diff(shown.Stdout)
+	if result.ExitCode == 0 {
+		output.Success("No changes: infrastructure matches the configuration")
+	} else {
+		output.Success("Plan complete (not applied)")
+	}
+
+	if planOut != "" {
+		if err := os.WriteFile(planOut, []byte(shown.Stdout+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write plan to %s: %w", planOut, err)
+		}
+		output.Info(fmt.Sprintf("Plan written to %s", planOut))
+	}
+
+	return nil
+}
+
+// Destroy runs terraform destroy non-interactively; callers confirm first
+func Destroy() error {
+	output.Info("Running Terraform destroy...")
+
+	restore, err := enterTerraformDir()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if err := initAndValidate(); err != nil {
+		return err
+	}
+
+	output.Info("Running terraform destroy...")
+	result, err := exec.Run("terraform destroy -no-color -input=false -auto-approve", false)
+	if err != nil {
+		return fmt.Errorf("terraform destroy failed: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("terraform destroy failed with exit code %d", result.ExitCode)
+	}
+	output.Success("Terraform destroy complete")
+
+	return nil
+}
+
+// enterTerraformDir changes to the terraform directory and returns a function that
+// changes back
+func enterTerraformDir() (func(), error) {
 	// Get the repository root
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Terraform directory is infrastructure/terraform
@@ -32,12 +153,14 @@ func Run(autoApprove bool) error {
 	}
 
 	// Change to terraform directory
-	originalDir, _ := os.Getwd()
 	if err := os.Chdir(terraformDir); err != nil {
-		return fmt.Errorf("failed to change to terraform directory: %w", err)
+		return nil, fmt.Errorf("failed to change to terraform directory: %w", err)
 	}
-	defer os.Chdir(originalDir)
+	return func() { os.Chdir(currentDir) }, nil
+}
 
+// initAndValidate runs terraform init and validate
+func initAndValidate() error {
 	// Terraform init
 	output.Info("Running terraform init...")
 	if _, err := exec.Run("terraform init -reconfigure", false); err != nil {
@@ -53,24 +176,5 @@ func Run(autoApprove bool) error {
 	}
 	output.Success("Terraform configuration valid")
 
-	// Terraform plan
-	output.Info("Running terraform plan...")
-	if _, err := exec.Run("terraform plan -no-color -out=tfplan", false); err != nil {
-		return fmt.Errorf("terraform plan failed: %w", err)
-	}
-	output.Success("Terraform plan created")
-
-	// Terraform apply
-	output.Info("Running terraform apply...")
-	applyCmd := "terraform apply tfplan"
-	if !autoApprove {
-		applyCmd = "terraform apply -no-color"
-	}
-
-	if _, err := exec.Run(applyCmd, false); err != nil {
-		return fmt.Errorf("terraform apply failed: %w", err)
-	}
-	output.Success("Terraform applied successfully")
-
 	return nil
 }
@@ -0,0 +1,356 @@
+package filesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// uploadPartsPrefix holds the chunks of uploads in progress, one object per chunk
+	uploadPartsPrefix = "uploads/.parts/"
+
+	// maxComposeSources is the GCS limit on source objects per compose request
+	maxComposeSources = 32
+
+	// maxUploadFileNameLength bounds the file name used in the final object path
+	maxUploadFileNameLength = 200
+)
+
+// ChunkedUploader stores uploads that arrive in chunks, so large files don't have to
+// fit in one request. Each chunk is written with the GCS resumable writer to its own
+// part object; when the last byte arrives the parts are composed into the final
+// object and the file is recorded in the FileStore. Upload state lives in an
+// UploadStore, so any server instance can accept the next chunk.
+//
+// Clients don't get a GCS resumable session URI to upload to directly: storage.Writer
+// keeps its session URI internal, and a writer can't be resumed by another request
+// or instance. Chunks go through the server instead, and CleanupStale removes the
+// part objects of abandoned uploads.
+type ChunkedUploader struct {
+	gcsClient *storage.Client
+	bucket    string
+	uploads   UploadStore
+	files     FileStore
+}
+
+// NewChunkedUploader creates a new chunked uploader
+func NewChunkedUploader(gcsClient *storage.Client, bucket string, uploads UploadStore, files FileStore) *ChunkedUploader {
+	return &ChunkedUploader{
+		gcsClient: gcsClient,
+		bucket:    bucket,
+		uploads:   uploads,
+		files:     files,
+	}
+}
+
+// Initiate starts a chunked upload of totalSize bytes for the user
+func (u *ChunkedUploader) Initiate(ctx context.Context, userID, sessionID, fileName, contentType string, totalSize int64) (*UploadSession, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	if totalSize <= 0 {
+		return nil, fmt.Errorf("size must be positive, got %d", totalSize)
+	}
+	name := SanitizeFilename(path.Base(strings.ReplaceAll(fileName, "\\", "/")), maxUploadFileNameLength)
+	if name == "" || name == "." || name == "/" {
+		return nil, fmt.Errorf("file name is required")
+	}
+
+	id := uuid.New().String()
+	gcsPath := path.Join("uploads", SanitizePath(userID), id, name)
+	if err := ValidateGCSPath(gcsPath); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	upload := &UploadSession{
+		ID:          id,
+		UserID:      userID,
+		SessionID:   sessionID,
+		FileName:    fileName,
+		ContentType: contentType,
+		GCSPath:     gcsPath,
+		TotalSize:   totalSize,
+		Status:      UploadStatusOpen,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := u.uploads.Create(ctx, upload); err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return upload, nil
+}
+
+// Get retrieves an upload session, checking that it belongs to the user
+func (u *ChunkedUploader) Get(ctx context.Context, userID, uploadID string) (*UploadSession, error) {
+	upload, err := u.uploads.Get(ctx, uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: upload %s: %v", ErrNotFound, uploadID, err)
+	}
+	if upload.UserID != userID {
+		return nil, fmt.Errorf("%w: upload %s belongs to another user", ErrPermissionDenied, uploadID)
+	}
+	return upload, nil
+}
+
+// WriteChunk stores length bytes from r at offset. The offset must equal the bytes
+// received so far (ErrOffsetMismatch otherwise, and the client should resume from
+// upload.Received). Resending the last accepted chunk is harmless: part objects are
+// named by offset. The chunk that completes the upload finalizes it; the returned
+// session then has Status UploadStatusFinalized and FileID set.
+func (u *ChunkedUploader) WriteChunk(ctx context.Context, upload *UploadSession, offset, length int64, r io.Reader) (*UploadSession, error) {
+	if upload.Status == UploadStatusFinalized {
+		return upload, ErrUploadFinalized
+	}
+	if offset != upload.Received {
+		return upload, fmt.Errorf("%w: got offset %d, expected %d", ErrOffsetMismatch, offset, upload.Received)
+	}
+	if length <= 0 || offset+length > upload.TotalSize {
+		return upload, fmt.Errorf("chunk of %d bytes at offset %d exceeds upload size %d", length, offset, upload.TotalSize)
+	}
+
+	if err := u.writePart(ctx, upload, offset, length, r); err != nil {
+		return upload, err
+	}
+
+	upload.Received = offset + length
+	upload.UpdatedAt = time.Now()
+	if upload.Received < upload.TotalSize {
+		if err := u.uploads.Update(ctx, upload); err != nil {
+			return upload, fmt.Errorf("failed to record chunk: %w", err)
+		}
+		return upload, nil
+	}
+
+	if err := u.finalize(ctx, upload); err != nil {
+		return upload, err
+	}
+	return upload, nil
+}
+
+// writePart writes one chunk to its part object. A short or failed read aborts the
+// write, so no partial part is left behind.
+func (u *ChunkedUploader) writePart(ctx context.Context, upload *UploadSession, offset, length int64, r io.Reader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := u.gcsClient.Bucket(u.bucket).Object(partName(upload.ID, offset)).NewWriter(ctx)
+	if _, err := io.CopyN(writer, r, length); err != nil {
+		cancel() // Cancelling before Close discards the object
+		writer.Close()
+		return fmt.Errorf("failed to write chunk at offset %d: %w", offset, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize chunk at offset %d: %w", offset, err)
+	}
+	return nil
+}
+
+// finalize composes the parts into the final object, records the file and removes
+// the parts
+func (u *ChunkedUploader) finalize(ctx context.Context, upload *UploadSession) error {
+	parts, err := u.listParts(ctx, upload.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkContiguous(parts, upload.TotalSize); err != nil {
+		return err
+	}
+
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		names[i] = p.name
+	}
+	if err := u.compose(ctx, upload, names); err != nil {
+		return err
+	}
+
+	// Hash is left empty: composite objects have no MD5, and hashing would mean
+	// reading the whole object back
+	file := &SyncFile{
+		ID:        upload.ID,
+		UserID:    upload.UserID,
+		SessionID: upload.SessionID,
+		LocalPath: upload.FileName,
+		GCSPath:   upload.GCSPath,
+		Size:      upload.TotalSize,
+		Status:    FileStatusUploaded,
+		UpdatedAt: time.Now(),
+	}
+	if err := u.files.Create(ctx, file); err != nil {
+		// An unrecorded object counts toward nobody's usage (e.g. one rejected by the
+		// quota), so it mustn't stay; the parts remain for a retry or stale cleanup
+		if delErr := u.gcsClient.Bucket(u.bucket).Object(upload.GCSPath).Delete(ctx); delErr != nil && !errors.Is(delErr, storage.ErrObjectNotExist) {
+			log.Printf("WARN: Failed to delete unrecorded object of upload %s: %v", upload.ID, delErr)
+		}
+		return fmt.Errorf("failed to record uploaded file: %w", err)
+	}
+
+	upload.Status = UploadStatusFinalized
+	upload.FileID = file.ID
+	upload.UpdatedAt = time.Now()
+	if err := u.uploads.Update(ctx, upload); err != nil {
+		return fmt.Errorf("failed to mark upload finalized: %w", err)
+	}
+
+	if err := u.deleteParts(ctx, upload.ID); err != nil {
+		// The upload succeeded; stale cleanup retries the deletion later
+		log.Printf("WARN: Failed to delete parts of upload %s: %v", upload.ID, err)
+	}
+	return nil
+}
+
+// compose concatenates the named objects into the upload's final object, composing
+// in rounds when there are more than maxComposeSources parts
+func (u *ChunkedUploader) compose(ctx context.Context, upload *UploadSession, names []string) error {
+	bucket := u.gcsClient.Bucket(u.bucket)
+	for round := 0; len(names) > maxComposeSources; round++ {
+		var next []string
+		for i := 0; i < len(names); i += maxComposeSources {
+			batch := names[i:min(i+maxComposeSources, len(names))]
+			dst := fmt.Sprintf("%s%s/compose-%d-%06d", uploadPartsPrefix, upload.ID, round, i/maxComposeSources)
+			if err := composeObjects(ctx, bucket, batch, dst, ""); err != nil {
+				return err
+			}
+			next = append(next, dst)
+		}
+		names = next
+	}
+	return composeObjects(ctx, bucket, names, upload.GCSPath, upload.ContentType)
+}
+
+func composeObjects(ctx context.Context, bucket *storage.BucketHandle, names []string, dst, contentType string) error {
+	srcs := make([]*storage.ObjectHandle, len(names))
+	for i, name := range names {
+		srcs[i] = bucket.Object(name)
+	}
+	composer := bucket.Object(dst).ComposerFrom(srcs...)
+	composer.ContentType = contentType
+	if _, err := composer.Run(ctx); err != nil {
+		return fmt.Errorf("failed to compose %s: %w", dst, err)
+	}
+	return nil
+}
+
+// uploadPart is a stored chunk
+type uploadPart struct {
+	name   string
+	offset int64
+	size   int64
+}
+
+// listParts returns the chunks of an upload ordered by offset
+func (u *ChunkedUploader) listParts(ctx context.Context, uploadID string) ([]uploadPart, error) {
+	prefix := uploadPartsPrefix + uploadID + "/part-"
+	iter := u.gcsClient.Bucket(u.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+
+	var parts []uploadPart
+	for {
+		attrs, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list upload parts: %w", err)
+		}
+		offset, err := strconv.ParseInt(strings.TrimPrefix(attrs.Name, prefix), 10, 64)
+		if err != nil {
+			continue // Not a part this uploader wrote
+		}
+		parts = append(parts, uploadPart{name: attrs.Name, offset: offset, size: attrs.Size})
+	}
+	// Listing is lexicographic and part names are zero-padded, so parts are in offset order
+	return parts, nil
+}
+
+// checkContiguous verifies the parts cover [0, totalSize) without gaps or overlaps
+func checkContiguous(parts []uploadPart, totalSize int64) error {
+	var next int64
+	for _, p := range parts {
+		if p.offset != next {
+			return fmt.Errorf("upload parts are not contiguous: expected offset %d, found %d", next, p.offset)
+		}
+		next += p.size
+	}
+	if next != totalSize {
+		return fmt.Errorf("upload parts cover %d of %d bytes", next, totalSize)
+	}
+	return nil
+}
+
+// deleteParts removes every temporary object of an upload
+func (u *ChunkedUploader) deleteParts(ctx context.Context, uploadID string) error {
+	bucket := u.gcsClient.Bucket(u.bucket)
+	iter := bucket.Objects(ctx, &storage.Query{Prefix: uploadPartsPrefix + uploadID + "/"})
+	for {
+		attrs, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := bucket.Object(attrs.Name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return err
+		}
+	}
+}
+
+// CleanupStale deletes upload sessions not updated within maxAge, along with the
+// parts of abandoned ones. It returns the number of sessions removed.
+func (u *ChunkedUploader) CleanupStale(ctx context.Context, maxAge time.Duration) (int, error) {
+	stale, err := u.uploads.ListStale(ctx, time.Now().Add(-maxAge))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list stale uploads: %w", err)
+	}
+
+	removed := 0
+	for _, upload := range stale {
+		if err := u.deleteParts(ctx, upload.ID); err != nil {
+			return removed, fmt.Errorf("failed to delete parts of upload %s: %w", upload.ID, err)
+		}
+		if err := u.uploads.Delete(ctx, upload.ID); err != nil {
+			return removed, fmt.Errorf("failed to delete upload %s: %w", upload.ID, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RunCleanup calls CleanupStale every interval until ctx is cancelled
+func (u *ChunkedUploader) RunCleanup(ctx context.Context, interval, maxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := u.CleanupStale(ctx, maxAge)
+			if err != nil {
+				log.Printf("ERROR: Upload cleanup failed: %v", err)
+			}
+			if removed > 0 {
+				log.Printf("INFO: Removed %d stale upload sessions", removed)
+			}
+		}
+	}
+}
+
+// partName is the object holding the chunk that starts at offset. Zero-padding
+// keeps lexicographic listing in offset order.
+func partName(uploadID string, offset int64) string {
+	return fmt.Sprintf("%s%s/part-%020d", uploadPartsPrefix, uploadID, offset)
+}
@@ -0,0 +1,255 @@
+package filesync
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+type mockUploadStore struct {
+	uploads map[string]*UploadSession
+	mu      sync.Mutex
+}
+
+func newMockUploadStore() *mockUploadStore {
+	return &mockUploadStore{uploads: make(map[string]*UploadSession)}
+}
+
+func (m *mockUploadStore) Create(ctx context.Context, upload *UploadSession) error {
+	return m.Update(ctx, upload)
+}
+
+func (m *mockUploadStore) Update(ctx context.Context, upload *UploadSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *upload
+	m.uploads[upload.ID] = &copied
+	return nil
+}
+
+func (m *mockUploadStore) Get(ctx context.Context, uploadID string) (*UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, ok := m.uploads[uploadID]
+	if !ok {
+		return nil, errors.New("upload not found")
+	}
+	copied := *upload
+	return &copied, nil
+}
+
+func (m *mockUploadStore) ListStale(ctx context.Context, before time.Time) ([]*UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var stale []*UploadSession
+	for _, upload := range m.uploads {
+		if upload.UpdatedAt.Before(before) {
+			copied := *upload
+			stale = append(stale, &copied)
+		}
+	}
+	return stale, nil
+}
+
+func (m *mockUploadStore) Delete(ctx context.Context, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.uploads, uploadID)
+	return nil
+}
+
+func TestCheckContiguous(t *testing.T) {
+	tests := []struct {
+		name    string
+		parts   []uploadPart
+		total   int64
+		wantErr bool
+	}{
+		{"complete", []uploadPart{{offset: 0, size: 5}, {offset: 5, size: 3}}, 8, false},
+		{"gap", []uploadPart{{offset: 0, size: 5}, {offset: 6, size: 2}}, 8, true},
+		{"overlap", []uploadPart{{offset: 0, size: 5}, {offset: 4, size: 4}}, 8, true},
+		{"short", []uploadPart{{offset: 0, size: 5}}, 8, true},
+		{"no parts", nil, 8, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkContiguous(tt.parts, tt.total)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkContiguous() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPartName_SortsByOffset(t *testing.T) {
+	offsets := []int64{10 << 30, 0, 9, 8 << 20, 100}
+	names := make([]string, len(offsets))
+	for i, offset := range offsets {
+		names[i] = partName("abc", offset)
+	}
+	sort.Strings(names)
+
+	want := []string{partName("abc", 0), partName("abc", 9), partName("abc", 100), partName("abc", 8<<20), partName("abc", 10<<30)}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("lexicographic order %v, want offset order %v", names, want)
+		}
+	}
+}
+
+func TestChunkedUploader_Upload(t *testing.T) {
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	bucketName := "test-bucket-chunked"
+	createTestBucket(t, gcsClient, bucketName)
+
+	uploads := newMockUploadStore()
+	files := newMockFileStore()
+	uploader := NewChunkedUploader(gcsClient, bucketName, uploads, files)
+	ctx := context.Background()
+
+	content := strings.Repeat("0123456789", 5)
+	upload, err := uploader.Initiate(ctx, "user-123", "session-1", "dir/My Book?.pdf", "application/pdf", int64(len(content)))
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	if !strings.HasSuffix(upload.GCSPath, "/My Book.pdf") {
+		t.Errorf("expected sanitized base name in %q", upload.GCSPath)
+	}
+
+	if _, err := uploader.Get(ctx, "someone-else", upload.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("expected ErrPermissionDenied for another user, got %v", err)
+	}
+
+	// First chunk, then a chunk at the wrong offset
+	upload, err = uploader.WriteChunk(ctx, upload, 0, 20, strings.NewReader(content[:20]))
+	if err != nil {
+		t.Fatalf("WriteChunk failed: %v", err)
+	}
+	if _, err := uploader.WriteChunk(ctx, upload, 30, 20, strings.NewReader(content[30:])); !errors.Is(err, ErrOffsetMismatch) {
+		t.Fatalf("expected ErrOffsetMismatch, got %v", err)
+	}
+
+	// Resume from the stored state, as a different server instance would
+	upload, err = uploader.Get(ctx, "user-123", upload.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if upload.Received != 20 {
+		t.Fatalf("expected 20 bytes received, got %d", upload.Received)
+	}
+	upload, err = uploader.WriteChunk(ctx, upload, 20, 30, strings.NewReader(content[20:]))
+	if err != nil {
+		t.Fatalf("final WriteChunk failed: %v", err)
+	}
+	if upload.Status != UploadStatusFinalized || upload.FileID == "" {
+		t.Fatalf("expected finalized upload with a file ID, got %+v", upload)
+	}
+
+	verifyGCSObject(t, gcsClient, bucketName, upload.GCSPath, content)
+	file, err := files.Get(ctx, upload.FileID)
+	if err != nil {
+		t.Fatalf("uploaded file not recorded: %v", err)
+	}
+	if file.UserID != "user-123" || file.SessionID != "session-1" || file.Status != FileStatusUploaded {
+		t.Errorf("unexpected file record: %+v", file)
+	}
+	parts, err := uploader.listParts(ctx, upload.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 0 {
+		t.Errorf("expected parts to be deleted, found %d", len(parts))
+	}
+
+	if _, err := uploader.WriteChunk(ctx, upload, 50, 1, strings.NewReader("x")); !errors.Is(err, ErrUploadFinalized) {
+		t.Errorf("expected ErrUploadFinalized, got %v", err)
+	}
+}
+
+// TestChunkedUploader_QuotaExceeded checks that an upload the file store rejects for
+// the quota leaves no final object behind
+func TestChunkedUploader_QuotaExceeded(t *testing.T) {
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	bucketName := "test-bucket-chunked-quota"
+	createTestBucket(t, gcsClient, bucketName)
+
+	files := newMockFileStore()
+	files.quota = 10
+	uploader := NewChunkedUploader(gcsClient, bucketName, newMockUploadStore(), files)
+	ctx := context.Background()
+
+	content := strings.Repeat("x", 20)
+	upload, err := uploader.Initiate(ctx, "user-123", "", "big.pdf", "application/pdf", int64(len(content)))
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	if _, err := uploader.WriteChunk(ctx, upload, 0, int64(len(content)), strings.NewReader(content)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := gcsClient.Bucket(bucketName).Object(upload.GCSPath).Attrs(ctx); !errors.Is(err, storage.ErrObjectNotExist) {
+		t.Errorf("expected the rejected upload's object to be deleted, got %v", err)
+	}
+	if used, _ := files.Usage(ctx, "user-123"); used != 0 {
+		t.Errorf("expected no usage recorded, got %d", used)
+	}
+}
+
+func TestChunkedUploader_CleanupStale(t *testing.T) {
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	bucketName := "test-bucket-chunked"
+	createTestBucket(t, gcsClient, bucketName)
+
+	uploads := newMockUploadStore()
+	uploader := NewChunkedUploader(gcsClient, bucketName, uploads, newMockFileStore())
+	ctx := context.Background()
+
+	abandoned, err := uploader.Initiate(ctx, "user-123", "", "big.pdf", "", 100)
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	if _, err := uploader.WriteChunk(ctx, abandoned, 0, 10, strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("WriteChunk failed: %v", err)
+	}
+	active, err := uploader.Initiate(ctx, "user-123", "", "new.pdf", "", 100)
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+
+	// Age the abandoned upload
+	stored, _ := uploads.Get(ctx, abandoned.ID)
+	stored.UpdatedAt = time.Now().Add(-48 * time.Hour)
+	uploads.Update(ctx, stored)
+
+	removed, err := uploader.CleanupStale(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupStale failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 stale upload removed, got %d", removed)
+	}
+	if _, err := uploads.Get(ctx, abandoned.ID); err == nil {
+		t.Error("abandoned upload session still exists")
+	}
+	if _, err := uploads.Get(ctx, active.ID); err != nil {
+		t.Errorf("active upload session was removed: %v", err)
+	}
+	parts, err := uploader.listParts(ctx, abandoned.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 0 {
+		t.Errorf("expected abandoned parts to be deleted, found %d", len(parts))
+	}
+}
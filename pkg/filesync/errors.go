@@ -24,8 +24,45 @@ var (
 
 	// ErrConflict is returned when a path conflict occurs (different hash at target path)
 	ErrConflict = errors.New("path conflict: different hash at target path")
+
+	// ErrInvalidCursor is returned when a pagination cursor is unknown or belongs to another user
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrOffsetMismatch is returned when an upload chunk doesn't start where the previous one ended
+	ErrOffsetMismatch = errors.New("chunk offset does not match bytes received")
+
+	// ErrUploadFinalized is returned when a chunk is sent for an upload that is already complete
+	ErrUploadFinalized = errors.New("upload already finalized")
+
+	// ErrFileBusy is returned when a file can't be deleted because the pipeline is still working on it
+	ErrFileBusy = errors.New("file is being processed")
+
+	// ErrNotInTrash is returned when restoring a file that isn't in the trash
+	ErrNotInTrash = errors.New("file is not in the trash")
+
+	// ErrRetentionExpired is returned when restoring a file that has been in the trash too long
+	ErrRetentionExpired = errors.New("trash retention window has passed")
+
+	// ErrQuotaExceeded is returned when storing a file would take its user over their storage quota
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
 )
 
+// QuotaExceededError describes a write rejected by the storage quota
+type QuotaExceededError struct {
+	UserID     string
+	UsedBytes  int64 // Bytes stored before the write
+	QuotaBytes int64
+	Size       int64 // Bytes the write would have added
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%v: user %s has %d of %d bytes stored, %d more requested", ErrQuotaExceeded, e.UserID, e.UsedBytes, e.QuotaBytes, e.Size)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
 // DiscoveryError represents an error during file discovery
 type DiscoveryError struct {
 	Path string
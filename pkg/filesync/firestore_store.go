@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
@@ -12,6 +13,8 @@ import (
 const (
 	sessionsCollection = "printsync-sessions"
 	filesCollection    = "printsync-files"
+	uploadsCollection  = "printsync-uploads"
+	usageCollection    = "printsync-usage"
 )
 
 // FirestoreSessionStore implements SessionStore using Firestore
@@ -142,11 +145,33 @@ func (s *FirestoreSessionStore) Delete(ctx context.Context, sessionID string) er
 // FirestoreFileStore implements FileStore using Firestore
 type FirestoreFileStore struct {
 	client *firestore.Client
+	quota  int64 // Bytes each user's stored files may take up (0 = no quota)
+}
+
+// FirestoreFileStoreOption configures a FirestoreFileStore
+type FirestoreFileStoreOption func(*FirestoreFileStore)
+
+// WithStorageQuota caps the bytes each user's stored files may take up (0 = no quota).
+// Writes that would take a user over it fail with a *QuotaExceededError.
+func WithStorageQuota(quota int64) FirestoreFileStoreOption {
+	return func(f *FirestoreFileStore) {
+		f.quota = quota
+	}
 }
 
 // NewFirestoreFileStore creates a new Firestore-backed file store
-func NewFirestoreFileStore(client *firestore.Client) *FirestoreFileStore {
-	return &FirestoreFileStore{client: client}
+func NewFirestoreFileStore(client *firestore.Client, opts ...FirestoreFileStoreOption) *FirestoreFileStore {
+	f := &FirestoreFileStore{client: client}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// userUsage is the running total of a user's stored bytes, kept in usageCollection
+// under the user ID so uploads don't have to sum every file
+type userUsage struct {
+	Bytes int64 `firestore:"bytes"`
 }
 
 // Create creates a new sync file
@@ -155,8 +180,7 @@ func (f *FirestoreFileStore) Create(ctx context.Context, file *SyncFile) error {
 		return fmt.Errorf("file ID is required")
 	}
 
-	_, err := f.client.Collection(filesCollection).Doc(file.ID).Set(ctx, file)
-	return err
+	return f.write(ctx, file)
 }
 
 // Update updates an existing sync file
@@ -165,8 +189,175 @@ func (f *FirestoreFileStore) Update(ctx context.Context, file *SyncFile) error {
 		return fmt.Errorf("file ID is required")
 	}
 
-	_, err := f.client.Collection(filesCollection).Doc(file.ID).Set(ctx, file)
-	return err
+	return f.write(ctx, file)
+}
+
+// write stores file and moves its user's usage total by the change in stored bytes,
+// in one transaction
+func (f *FirestoreFileStore) write(ctx context.Context, file *SyncFile) error {
+	ref := f.client.Collection(filesCollection).Doc(file.ID)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		previous, err := getFile(tx, ref)
+		if err != nil {
+			return err
+		}
+		adjust, err := f.usageAdjuster(tx, previous, file)
+		if err != nil {
+			return err
+		}
+		if err := tx.Set(ref, file); err != nil {
+			return err
+		}
+		return adjust()
+	})
+}
+
+// Delete deletes a sync file and takes it off its user's usage total
+func (f *FirestoreFileStore) Delete(ctx context.Context, fileID string) error {
+	ref := f.client.Collection(filesCollection).Doc(fileID)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		previous, err := getFile(tx, ref)
+		if err != nil {
+			return err
+		}
+		adjust, err := f.usageAdjuster(tx, previous, nil)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(ref); err != nil {
+			return err
+		}
+		return adjust()
+	})
+}
+
+// getFile reads a file in tx, returning nil if it doesn't exist
+func getFile(tx *firestore.Transaction, ref *firestore.DocumentRef) (*SyncFile, error) {
+	snap, err := tx.Get(ref)
+	if snap != nil && !snap.Exists() {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file SyncFile
+	if err := snap.DataTo(&file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// usageAdjuster reads what a change from previous to next (either may be nil) needs
+// from the usage totals and returns the writes that apply it. Transactions must read
+// before they write, so the caller runs the returned func after its own writes.
+// Users without a total yet are left alone (Usage computes theirs from the files),
+// unless the store has a quota: then the total is computed here to check the change
+// against it, and a change that would exceed it fails with a *QuotaExceededError.
+func (f *FirestoreFileStore) usageAdjuster(tx *firestore.Transaction, previous, next *SyncFile) (func() error, error) {
+	deltas := make(map[string]int64)
+	if previous != nil && previous.UserID != "" {
+		deltas[previous.UserID] -= storedBytes(previous)
+	}
+	if next != nil && next.UserID != "" {
+		deltas[next.UserID] += storedBytes(next)
+	}
+
+	var writes []func() error
+	for userID, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+		ref := f.client.Collection(usageCollection).Doc(userID)
+		snap, err := tx.Get(ref)
+		if snap == nil && err != nil {
+			return nil, err
+		}
+		exists := snap.Exists()
+		if !exists && (f.quota <= 0 || delta < 0) {
+			continue
+		}
+
+		var usage userUsage
+		if exists {
+			if err := snap.DataTo(&usage); err != nil {
+				return nil, err
+			}
+		} else if usage.Bytes, err = sumStoredBytes(tx, f.client, userID); err != nil {
+			return nil, err
+		}
+		if f.quota > 0 && delta > 0 && usage.Bytes+delta > f.quota {
+			return nil, &QuotaExceededError{UserID: userID, UsedBytes: usage.Bytes, QuotaBytes: f.quota, Size: delta}
+		}
+
+		if exists {
+			writes = append(writes, func() error {
+				return tx.Update(ref, []firestore.Update{{Path: "bytes", Value: firestore.Increment(delta)}})
+			})
+		} else {
+			// The sum read the stored previous version, so the total starts from it
+			total := userUsage{Bytes: usage.Bytes + delta}
+			writes = append(writes, func() error { return tx.Set(ref, total) })
+		}
+	}
+
+	return func() error {
+		for _, write := range writes {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// sumStoredBytes adds up the stored bytes of the user's files in tx
+func sumStoredBytes(tx *firestore.Transaction, client *firestore.Client, userID string) (int64, error) {
+	docs, err := tx.Documents(client.Collection(filesCollection).
+		Where("userId", "==", userID).
+		Where("status", "in", []string{string(FileStatusUploading), string(FileStatusUploaded)})).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, doc := range docs {
+		var file SyncFile
+		if err := doc.DataTo(&file); err != nil {
+			return 0, err
+		}
+		total += storedBytes(&file)
+	}
+	return total, nil
+}
+
+// Usage returns the bytes the user's stored files take up, from the user's running
+// total. The first call for a user sums their files to start the total.
+func (f *FirestoreFileStore) Usage(ctx context.Context, userID string) (int64, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("user ID is required")
+	}
+
+	ref := f.client.Collection(usageCollection).Doc(userID)
+	var usage userUsage
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if snap != nil && snap.Exists() {
+			return snap.DataTo(&usage)
+		}
+		if snap == nil && err != nil {
+			return err
+		}
+
+		total, err := sumStoredBytes(tx, f.client, userID)
+		if err != nil {
+			return err
+		}
+		usage = userUsage{Bytes: total}
+		return tx.Set(ref, usage)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storage usage for user %s: %w", userID, err)
+	}
+	return usage.Bytes, nil
 }
 
 // Get retrieves a sync file by ID
@@ -214,6 +405,136 @@ func (f *FirestoreFileStore) ListBySession(ctx context.Context, sessionID string
 	return files, nil
 }
 
+// ListByUser retrieves one page of a user's files, most recently updated first.
+// The cursor is the ID of the last file of the previous page (empty for the first
+// page); paging resumes after that file's document snapshot, so concurrent inserts
+// don't shift or repeat results. Files in the trash are skipped unless includeDeleted
+// is set; they're filtered here rather than in the query, because files written
+// before the trash existed have no deletedAt field for the query to match. Documents
+// are read in bounded batches, so a page never reads all of the user's files.
+func (f *FirestoreFileStore) ListByUser(ctx context.Context, userID, cursor string, limit int, includeDeleted bool) (*FilePage, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+
+	query := f.client.Collection(filesCollection).
+		Where("userId", "==", userID).
+		OrderBy("updatedAt", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc)
+	var after *firestore.DocumentSnapshot
+	if cursor != "" {
+		snap, err := f.client.Collection(filesCollection).Doc(cursor).Get(ctx)
+		if snap != nil && !snap.Exists() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if owner, _ := snap.DataAt("userId"); owner != userID {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+		}
+		after = snap
+	}
+
+	// Read in batches of limit+1: one extra file tells whether another page exists, and
+	// further batches are read only when trashed files were skipped
+	batchSize := limit + 1
+	page := &FilePage{}
+	for {
+		batch := query
+		if after != nil {
+			batch = batch.StartAfter(after)
+		}
+		docs, err := batch.Limit(batchSize).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range docs {
+			var file SyncFile
+			if err := doc.DataTo(&file); err != nil {
+				return nil, err
+			}
+			file.ID = doc.Ref.ID
+			if file.DeletedAt != nil && !includeDeleted {
+				continue
+			}
+			if len(page.Files) == limit {
+				page.NextCursor = page.Files[limit-1].ID
+				return page, nil
+			}
+
+			page.Files = append(page.Files, &file)
+		}
+
+		if len(docs) < batchSize {
+			return page, nil
+		}
+		after = docs[len(docs)-1]
+	}
+}
+
+// ListDeleted returns files moved to the trash before the given time
+func (f *FirestoreFileStore) ListDeleted(ctx context.Context, before time.Time) ([]*SyncFile, error) {
+	iter := f.client.Collection(filesCollection).
+		Where("deletedAt", "<", before).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var files []*SyncFile
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var file SyncFile
+		if err := doc.DataTo(&file); err != nil {
+			return nil, err
+		}
+		file.ID = doc.Ref.ID
+
+		files = append(files, &file)
+	}
+
+	return files, nil
+}
+
+// ListByGCSPath returns every file whose record points at the object, trashed or not
+func (f *FirestoreFileStore) ListByGCSPath(ctx context.Context, gcsPath string) ([]*SyncFile, error) {
+	iter := f.client.Collection(filesCollection).
+		Where("gcsPath", "==", gcsPath).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var files []*SyncFile
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var file SyncFile
+		if err := doc.DataTo(&file); err != nil {
+			return nil, err
+		}
+		file.ID = doc.Ref.ID
+
+		files = append(files, &file)
+	}
+
+	return files, nil
+}
+
 // SubscribeBySession subscribes to real-time updates for all files in a session
 func (f *FirestoreFileStore) SubscribeBySession(ctx context.Context, sessionID string, callback func(*SyncFile)) error {
 	go func() {
@@ -261,8 +582,126 @@ func (f *FirestoreFileStore) SubscribeBySession(ctx context.Context, sessionID s
 	return nil
 }
 
-// Delete deletes a sync file
-func (f *FirestoreFileStore) Delete(ctx context.Context, fileID string) error {
-	_, err := f.client.Collection(filesCollection).Doc(fileID).Delete(ctx)
+// WatchByUser listens for changes to all files owned by a user
+func (f *FirestoreFileStore) WatchByUser(ctx context.Context, userID string, callback func(FileChange)) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+
+	iter := f.client.Collection(filesCollection).
+		Where("userId", "==", userID).
+		Snapshots(ctx)
+	defer iter.Stop()
+
+	for {
+		snap, err := iter.Next()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("file listener for user %s failed: %w", userID, err)
+		}
+
+		for _, change := range snap.Changes {
+			var file SyncFile
+			if err := change.Doc.DataTo(&file); err != nil {
+				log.Printf("ERROR: Failed to parse file data for user %s: %v", userID, err)
+				continue
+			}
+			file.ID = change.Doc.Ref.ID
+
+			kind := FileChangeModified
+			switch change.Kind {
+			case firestore.DocumentAdded:
+				kind = FileChangeAdded
+			case firestore.DocumentRemoved:
+				kind = FileChangeRemoved
+			}
+			callback(FileChange{Kind: kind, File: &file})
+		}
+	}
+}
+
+// FirestoreUploadStore implements UploadStore using Firestore
+type FirestoreUploadStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreUploadStore creates a new Firestore-backed upload store
+func NewFirestoreUploadStore(client *firestore.Client) *FirestoreUploadStore {
+	return &FirestoreUploadStore{client: client}
+}
+
+// Create creates a new upload session
+func (u *FirestoreUploadStore) Create(ctx context.Context, upload *UploadSession) error {
+	if upload.ID == "" {
+		return fmt.Errorf("upload ID is required")
+	}
+
+	_, err := u.client.Collection(uploadsCollection).Doc(upload.ID).Set(ctx, upload)
+	return err
+}
+
+// Update updates an existing upload session
+func (u *FirestoreUploadStore) Update(ctx context.Context, upload *UploadSession) error {
+	if upload.ID == "" {
+		return fmt.Errorf("upload ID is required")
+	}
+
+	_, err := u.client.Collection(uploadsCollection).Doc(upload.ID).Set(ctx, upload)
+	return err
+}
+
+// Get retrieves an upload session by ID
+func (u *FirestoreUploadStore) Get(ctx context.Context, uploadID string) (*UploadSession, error) {
+	doc, err := u.client.Collection(uploadsCollection).Doc(uploadID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var upload UploadSession
+	if err := doc.DataTo(&upload); err != nil {
+		return nil, err
+	}
+	upload.ID = doc.Ref.ID
+
+	return &upload, nil
+}
+
+// ListStale retrieves upload sessions last updated before the given time
+func (u *FirestoreUploadStore) ListStale(ctx context.Context, before time.Time) ([]*UploadSession, error) {
+	iter := u.client.Collection(uploadsCollection).
+		Where("updatedAt", "<", before).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var uploads []*UploadSession
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var upload UploadSession
+		if err := doc.DataTo(&upload); err != nil {
+			return nil, err
+		}
+		upload.ID = doc.Ref.ID
+
+		uploads = append(uploads, &upload)
+	}
+
+	return uploads, nil
+}
+
+// Delete deletes an upload session
+func (u *FirestoreUploadStore) Delete(ctx context.Context, uploadID string) error {
+	_, err := u.client.Collection(uploadsCollection).Doc(uploadID).Delete(ctx)
 	return err
 }
@@ -2,6 +2,7 @@ package filesync
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -368,6 +369,99 @@ func TestFirestoreFileStore_ListBySession(t *testing.T) {
 	}
 }
 
+func TestFirestoreFileStore_ListByUser(t *testing.T) {
+	client := getTestClient(t)
+	defer client.Close()
+
+	store := NewFirestoreFileStore(client)
+	ctx := context.Background()
+
+	userID := "user-list-by-user"
+	base := time.Now()
+	for i, id := range []string{"test-file-page-1", "test-file-page-2", "test-file-page-3"} {
+		file := &SyncFile{
+			ID:        id,
+			UserID:    userID,
+			SessionID: "session-page-test",
+			Status:    FileStatusUploaded,
+			UpdatedAt: base.Add(time.Duration(i) * time.Second),
+		}
+		defer cleanupFile(t, client, file.ID)
+		if err := store.Create(ctx, file); err != nil {
+			t.Fatalf("failed to create %s: %v", id, err)
+		}
+	}
+	other := &SyncFile{ID: "test-file-page-other", UserID: "someone-else", UpdatedAt: base}
+	defer cleanupFile(t, client, other.ID)
+	if err := store.Create(ctx, other); err != nil {
+		t.Fatalf("failed to create other user's file: %v", err)
+	}
+
+	// Most recently updated first, two per page
+	page, err := store.ListByUser(ctx, userID, "", 2, false)
+	if err != nil {
+		t.Fatalf("failed to list first page: %v", err)
+	}
+	if len(page.Files) != 2 || page.Files[0].ID != "test-file-page-3" || page.Files[1].ID != "test-file-page-2" {
+		t.Fatalf("unexpected first page: %+v", page.Files)
+	}
+	if page.NextCursor != "test-file-page-2" {
+		t.Errorf("expected next cursor test-file-page-2, got %q", page.NextCursor)
+	}
+
+	page, err = store.ListByUser(ctx, userID, page.NextCursor, 2, false)
+	if err != nil {
+		t.Fatalf("failed to list second page: %v", err)
+	}
+	if len(page.Files) != 1 || page.Files[0].ID != "test-file-page-1" {
+		t.Fatalf("unexpected second page: %+v", page.Files)
+	}
+	if page.NextCursor != "" {
+		t.Errorf("expected no next cursor on the last page, got %q", page.NextCursor)
+	}
+
+	// Cursors can't be used to page through another user's files
+	if _, err := store.ListByUser(ctx, userID, other.ID, 2, false); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor for another user's cursor, got %v", err)
+	}
+	if _, err := store.ListByUser(ctx, userID, "missing-file", 2, false); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor for an unknown cursor, got %v", err)
+	}
+
+	// Files in the trash are skipped unless asked for
+	trashed, err := store.Get(ctx, "test-file-page-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deletedAt := time.Now()
+	trashed.DeletedAt = &deletedAt
+	if err := store.Update(ctx, trashed); err != nil {
+		t.Fatalf("failed to trash file: %v", err)
+	}
+	page, err = store.ListByUser(ctx, userID, "", 2, false)
+	if err != nil {
+		t.Fatalf("failed to list without trash: %v", err)
+	}
+	if len(page.Files) != 2 || page.Files[0].ID != "test-file-page-3" || page.Files[1].ID != "test-file-page-1" || page.NextCursor != "" {
+		t.Errorf("expected the trashed file to be skipped, got %+v (next %q)", page.Files, page.NextCursor)
+	}
+	// A batch that is all trash is skipped and the next one read
+	page, err = store.ListByUser(ctx, userID, "test-file-page-3", 1, false)
+	if err != nil {
+		t.Fatalf("failed to list past the trashed file: %v", err)
+	}
+	if len(page.Files) != 1 || page.Files[0].ID != "test-file-page-1" || page.NextCursor != "" {
+		t.Errorf("expected test-file-page-1 after skipping the trash, got %+v (next %q)", page.Files, page.NextCursor)
+	}
+	page, err = store.ListByUser(ctx, userID, "", 3, true)
+	if err != nil {
+		t.Fatalf("failed to list with trash: %v", err)
+	}
+	if len(page.Files) != 3 {
+		t.Errorf("expected the trashed file to be included, got %+v", page.Files)
+	}
+}
+
 func TestFirestoreFileStore_Delete(t *testing.T) {
 	client := getTestClient(t)
 	defer client.Close()
@@ -402,3 +496,46 @@ func TestFirestoreFileStore_Delete(t *testing.T) {
 		t.Error("expected error when getting deleted file, got nil")
 	}
 }
+
+func TestFirestoreFileStore_Usage(t *testing.T) {
+	client := getTestClient(t)
+	defer client.Close()
+
+	store := NewFirestoreFileStore(client)
+	ctx := context.Background()
+	userID := "user-usage-" + time.Now().Format("150405.000000")
+	defer client.Collection(usageCollection).Doc(userID).Delete(ctx)
+
+	// A file uploaded before the user has a running total is counted by the first Usage
+	existing := &SyncFile{ID: userID + "-existing", UserID: userID, Size: 100, Status: FileStatusUploaded, UpdatedAt: time.Now()}
+	pending := &SyncFile{ID: userID + "-pending", UserID: userID, Size: 1000, Status: FileStatusPending, UpdatedAt: time.Now()}
+	for _, f := range []*SyncFile{existing, pending} {
+		if err := store.Create(ctx, f); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		defer cleanupFile(t, client, f.ID)
+	}
+	wantUsage := func(want int64) {
+		t.Helper()
+		got, err := store.Usage(ctx, userID)
+		if err != nil {
+			t.Fatalf("Usage failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Usage = %d, want %d", got, want)
+		}
+	}
+	wantUsage(100)
+
+	// Once the total exists, creates, updates and deletes keep it current
+	pending.Status = FileStatusUploaded
+	if err := store.Update(ctx, pending); err != nil {
+		t.Fatalf("failed to update file: %v", err)
+	}
+	wantUsage(1100)
+
+	if err := store.Delete(ctx, existing.ID); err != nil {
+		t.Fatalf("failed to delete file: %v", err)
+	}
+	wantUsage(1000)
+}
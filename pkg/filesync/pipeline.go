@@ -9,6 +9,7 @@ package filesync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -411,6 +412,7 @@ func (p *Pipeline) processFile(
 		SessionID: session.ID,
 		LocalPath: file.Path,
 		Hash:      file.Hash,
+		Size:      file.Size,
 		Status:    FileStatusPending,
 		UpdatedAt: time.Now(),
 	}
@@ -519,6 +521,16 @@ func (p *Pipeline) approveAndUploadFile(ctx context.Context, syncFile *SyncFile,
 	syncFile.GCSPath = normalizedPath.GCSPath
 	syncFile.UpdatedAt = time.Now()
 	if err := p.fileStore.Update(ctx, syncFile); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			// Nothing was sent; the file stays listed with the reason
+			syncFile.Status = FileStatusError
+			syncFile.Error = err.Error()
+			syncFile.UpdatedAt = time.Now()
+			if updateErr := p.fileStore.Update(ctx, syncFile); updateErr != nil {
+				return fmt.Errorf("upload rejected: %w (additionally, failed to update file status: %v)", err, updateErr)
+			}
+			return fmt.Errorf("upload rejected: %w", err)
+		}
 		return fmt.Errorf("failed to update file status to uploading: %w", err)
 	}
 
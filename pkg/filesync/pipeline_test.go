@@ -3,6 +3,8 @@ package filesync
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -202,9 +204,27 @@ func (m *mockSessionStore) Delete(ctx context.Context, sessionID string) error {
 
 type mockFileStore struct {
 	files map[string]*SyncFile
+	quota int64 // Enforced like FirestoreFileStore's WithStorageQuota (0 = none)
 	mu    sync.Mutex
 }
 
+// checkQuota rejects storing file if it would take its user over the quota
+func (m *mockFileStore) checkQuota(file *SyncFile) error {
+	if m.quota <= 0 || storedBytes(file) == 0 {
+		return nil
+	}
+	var used int64
+	for id, f := range m.files {
+		if id != file.ID && f.UserID == file.UserID {
+			used += storedBytes(f)
+		}
+	}
+	if used+storedBytes(file) > m.quota {
+		return &QuotaExceededError{UserID: file.UserID, UsedBytes: used, QuotaBytes: m.quota, Size: storedBytes(file)}
+	}
+	return nil
+}
+
 func newMockFileStore() *mockFileStore {
 	return &mockFileStore{
 		files: make(map[string]*SyncFile),
@@ -214,6 +234,9 @@ func newMockFileStore() *mockFileStore {
 func (m *mockFileStore) Create(ctx context.Context, file *SyncFile) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if err := m.checkQuota(file); err != nil {
+		return err
+	}
 	m.files[file.ID] = file
 	return nil
 }
@@ -224,6 +247,9 @@ func (m *mockFileStore) Update(ctx context.Context, file *SyncFile) error {
 	if _, exists := m.files[file.ID]; !exists {
 		return errors.New("file not found")
 	}
+	if err := m.checkQuota(file); err != nil {
+		return err
+	}
 	m.files[file.ID] = file
 	return nil
 }
@@ -250,10 +276,86 @@ func (m *mockFileStore) ListBySession(ctx context.Context, sessionID string) ([]
 	return files, nil
 }
 
+func (m *mockFileStore) ListDeleted(ctx context.Context, before time.Time) ([]*SyncFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var files []*SyncFile
+	for _, f := range m.files {
+		if f.DeletedAt != nil && f.DeletedAt.Before(before) {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (m *mockFileStore) ListByGCSPath(ctx context.Context, gcsPath string) ([]*SyncFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var files []*SyncFile
+	for _, f := range m.files {
+		if f.GCSPath == gcsPath {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (m *mockFileStore) Usage(ctx context.Context, userID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total int64
+	for _, f := range m.files {
+		if f.UserID == userID {
+			total += storedBytes(f)
+		}
+	}
+	return total, nil
+}
+
+func (m *mockFileStore) ListByUser(ctx context.Context, userID, cursor string, limit int, includeDeleted bool) (*FilePage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var files []*SyncFile
+	for _, f := range m.files {
+		if f.UserID == userID && (includeDeleted || f.DeletedAt == nil) {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].UpdatedAt.Equal(files[j].UpdatedAt) {
+			return files[i].UpdatedAt.After(files[j].UpdatedAt)
+		}
+		return files[i].ID > files[j].ID
+	})
+	if cursor != "" {
+		start := -1
+		for i, f := range files {
+			if f.ID == cursor {
+				start = i + 1
+			}
+		}
+		if start < 0 {
+			return nil, ErrInvalidCursor
+		}
+		files = files[start:]
+	}
+	page := &FilePage{Files: files}
+	if len(files) > limit {
+		page.Files = files[:limit]
+		page.NextCursor = files[limit-1].ID
+	}
+	return page, nil
+}
+
 func (m *mockFileStore) SubscribeBySession(ctx context.Context, sessionID string, callback func(*SyncFile)) error {
 	return nil
 }
 
+func (m *mockFileStore) WatchByUser(ctx context.Context, userID string, callback func(FileChange)) error {
+	<-ctx.Done()
+	return nil
+}
+
 func (m *mockFileStore) Delete(ctx context.Context, fileID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -1021,6 +1123,57 @@ func TestPipeline_RejectFiles(t *testing.T) {
 	}
 }
 
+// TestPipeline_ApproveAndUpload_QuotaExceeded checks that a file that would take its
+// user over the storage quota is marked with the error and never sent to the uploader.
+func TestPipeline_ApproveAndUpload_QuotaExceeded(t *testing.T) {
+	ctx := context.Background()
+
+	files := []FileInfo{
+		{Path: "/test/file1.pdf", RelativePath: "file1.pdf", Size: 100, Hash: "hash1"},
+		{Path: "/test/file2.pdf", RelativePath: "file2.pdf", Size: 200, Hash: "hash2"},
+	}
+	uploader := &mockUploader{}
+	fileStore := newMockFileStore()
+	fileStore.quota = 250
+
+	pipeline, err := NewPipeline(&mockDiscoverer{files: files}, &mockExtractor{canExtract: true}, &mockNormalizer{}, uploader, newMockSessionStore(), fileStore)
+	if err != nil {
+		t.Fatalf("NewPipeline() failed: %v", err)
+	}
+	result, err := pipeline.RunExtraction(ctx, "/test", "user123")
+	if err != nil {
+		t.Fatalf("pipeline.RunExtraction() failed: %v", err)
+	}
+
+	approvalResult, err := pipeline.ApproveAllAndUpload(ctx, result.SessionID)
+	if err != nil {
+		t.Fatalf("pipeline.ApproveAllAndUpload() failed: %v", err)
+	}
+	if approvalResult.Uploaded != 1 || approvalResult.Failed != 1 {
+		t.Fatalf("expected 1 uploaded and 1 failed file, got %+v", approvalResult)
+	}
+	if !errors.Is(approvalResult.Errors[0].Err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", approvalResult.Errors[0].Err)
+	}
+	if got := len(uploader.getUploadedFiles()); got != 1 {
+		t.Errorf("expected only the file within quota to be uploaded, got %d uploads", got)
+	}
+
+	stored, _ := fileStore.ListBySession(ctx, result.SessionID)
+	var rejected *SyncFile
+	for _, f := range stored {
+		if f.Status == FileStatusError {
+			rejected = f
+		}
+	}
+	if rejected == nil || !strings.Contains(rejected.Error, "storage quota exceeded") {
+		t.Errorf("expected the rejected file to be marked with the quota error, got %+v", stored)
+	}
+	if used, _ := fileStore.Usage(ctx, "user123"); used > fileStore.quota {
+		t.Errorf("usage %d exceeds quota %d", used, fileStore.quota)
+	}
+}
+
 func TestPipeline_ApproveAndUpload_WrongStatus(t *testing.T) {
 	ctx := context.Background()
 
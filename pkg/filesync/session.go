@@ -69,10 +69,69 @@ type SyncFile struct {
 	LocalPath string       `firestore:"localPath"`
 	GCSPath   string       `firestore:"gcsPath"`
 	Hash      string       `firestore:"hash"`
+	Size      int64        `firestore:"size"` // Bytes; counts toward the user's storage usage once uploaded
 	Status    FileStatus   `firestore:"status"`
 	Metadata  FileMetadata `firestore:"metadata"`
 	Error     string       `firestore:"error"`
 	UpdatedAt time.Time    `firestore:"updatedAt"`
+	DeletedAt *time.Time   `firestore:"deletedAt"` // Set while the file is in the trash (see FileTrash)
+}
+
+// UploadStatus represents the state of a chunked upload
+type UploadStatus string
+
+const (
+	UploadStatusOpen      UploadStatus = "open"
+	UploadStatusFinalized UploadStatus = "finalized"
+)
+
+// UploadSession tracks a chunked upload. Received is the number of bytes stored so
+// far, which is the offset the next chunk must start at.
+type UploadSession struct {
+	ID          string       `firestore:"-"`
+	UserID      string       `firestore:"userId"`
+	SessionID   string       `firestore:"sessionId"`
+	FileName    string       `firestore:"fileName"`
+	ContentType string       `firestore:"contentType"`
+	GCSPath     string       `firestore:"gcsPath"`
+	TotalSize   int64        `firestore:"totalSize"`
+	Received    int64        `firestore:"received"`
+	Status      UploadStatus `firestore:"status"`
+	FileID      string       `firestore:"fileId"`
+	CreatedAt   time.Time    `firestore:"createdAt"`
+	UpdatedAt   time.Time    `firestore:"updatedAt"`
+}
+
+// storedBytes is how much a file adds to its user's storage usage: its size once it
+// is in the bucket, and while it uploads, so the quota is checked before the bytes
+// are sent. Nothing before that, or once an upload fails or is deduplicated.
+func storedBytes(file *SyncFile) int64 {
+	if file == nil || (file.Status != FileStatusUploading && file.Status != FileStatusUploaded) {
+		return 0
+	}
+	return file.Size
+}
+
+// FilePage is one page of a user's files, most recently updated first.
+// NextCursor is empty on the last page.
+type FilePage struct {
+	Files      []*SyncFile
+	NextCursor string
+}
+
+// FileChangeKind says how a file changed in a FileStore.WatchByUser listener
+type FileChangeKind string
+
+const (
+	FileChangeAdded    FileChangeKind = "added"
+	FileChangeModified FileChangeKind = "modified"
+	FileChangeRemoved  FileChangeKind = "removed"
+)
+
+// FileChange is one file change seen by a FileStore.WatchByUser listener
+type FileChange struct {
+	Kind FileChangeKind
+	File *SyncFile
 }
 
 // SessionStore defines operations for managing sync sessions
@@ -91,6 +150,27 @@ type FileStore interface {
 	Update(ctx context.Context, file *SyncFile) error
 	Get(ctx context.Context, fileID string) (*SyncFile, error)
 	ListBySession(ctx context.Context, sessionID string) ([]*SyncFile, error)
+	// ListByUser skips files in the trash unless includeDeleted is set
+	ListByUser(ctx context.Context, userID, cursor string, limit int, includeDeleted bool) (*FilePage, error)
+	// ListDeleted returns files moved to the trash before the given time
+	ListDeleted(ctx context.Context, before time.Time) ([]*SyncFile, error)
+	// ListByGCSPath returns every file whose record points at the object, trashed or not
+	ListByGCSPath(ctx context.Context, gcsPath string) ([]*SyncFile, error)
+	// Usage returns the bytes the user's uploaded files take up
+	Usage(ctx context.Context, userID string) (int64, error)
 	SubscribeBySession(ctx context.Context, sessionID string, callback func(*SyncFile)) error
+	// WatchByUser calls callback for every change to the user's files, starting with
+	// one FileChangeAdded per existing file. It blocks until ctx is done (returning nil)
+	// or the listener fails.
+	WatchByUser(ctx context.Context, userID string, callback func(FileChange)) error
 	Delete(ctx context.Context, fileID string) error
 }
+
+// UploadStore defines operations for managing chunked upload sessions
+type UploadStore interface {
+	Create(ctx context.Context, upload *UploadSession) error
+	Update(ctx context.Context, upload *UploadSession) error
+	Get(ctx context.Context, uploadID string) (*UploadSession, error)
+	ListStale(ctx context.Context, before time.Time) ([]*UploadSession, error)
+	Delete(ctx context.Context, uploadID string) error
+}
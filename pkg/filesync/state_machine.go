@@ -48,6 +48,16 @@ func CanTrash(status FileStatus) bool {
 	return status == FileStatusUploaded || status == FileStatusSkipped
 }
 
+// CanDelete checks if a file can be moved to the trash from its current state; files
+// the pipeline is still working on can't
+func CanDelete(status FileStatus) bool {
+	switch status {
+	case FileStatusPending, FileStatusExtracting, FileStatusUploading:
+		return false
+	}
+	return true
+}
+
 // CanReject checks if a file can be rejected from its current state
 func CanReject(status FileStatus) bool {
 	return status == FileStatusExtracted
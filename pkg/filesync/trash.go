@@ -0,0 +1,267 @@
+package filesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// trashPrefix holds the objects of files in the trash, under their original path
+const trashPrefix = "trash/"
+
+// FileTrash soft-deletes files: Delete moves a file's object under trash/ and marks
+// its record with DeletedAt, Restore undoes that within the retention window, and
+// Purge deletes files whose window has passed for good. Files in the trash still
+// count toward their user's storage usage until they are purged.
+type FileTrash struct {
+	gcsClient *storage.Client
+	bucket    string
+	files     FileStore
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewFileTrash creates a trash that keeps deleted files restorable for retention
+func NewFileTrash(gcsClient *storage.Client, bucket string, files FileStore, retention time.Duration) *FileTrash {
+	return &FileTrash{
+		gcsClient: gcsClient,
+		bucket:    bucket,
+		files:     files,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Retention returns how long deleted files stay restorable
+func (t *FileTrash) Retention() time.Duration {
+	return t.retention
+}
+
+// get retrieves a file, checking that it belongs to the user
+func (t *FileTrash) get(ctx context.Context, userID, fileID string) (*SyncFile, error) {
+	file, err := t.files.Get(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: file %s: %v", ErrNotFound, fileID, err)
+	}
+	if file.UserID != userID {
+		return nil, fmt.Errorf("%w: file %s belongs to another user", ErrPermissionDenied, fileID)
+	}
+	return file, nil
+}
+
+// Delete moves the user's file to the trash. Deleting a file that is already in the
+// trash returns it unchanged. An object another file outside the trash points at (a
+// deduplicated upload of the same content) stays where it is; only the record is
+// marked deleted.
+func (t *FileTrash) Delete(ctx context.Context, userID, fileID string) (*SyncFile, error) {
+	file, err := t.get(ctx, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.DeletedAt != nil {
+		return file, nil
+	}
+	if !CanDelete(file.Status) {
+		return nil, fmt.Errorf("%w: file %s is %s", ErrFileBusy, fileID, file.Status)
+	}
+
+	// Copy first and delete the original only once the record says the file is in
+	// the trash, so a failure part way never loses the object
+	moved := file.Status == FileStatusUploaded && file.GCSPath != ""
+	if moved {
+		shared, err := t.referencedElsewhere(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		moved = !shared
+	}
+	if moved {
+		if err := t.copyObject(ctx, file.GCSPath, trashPrefix+file.GCSPath); err != nil {
+			return nil, err
+		}
+	}
+
+	now := t.now()
+	file.DeletedAt = &now
+	file.UpdatedAt = now
+	if err := t.files.Update(ctx, file); err != nil {
+		if moved {
+			if delErr := t.deleteObject(ctx, trashPrefix+file.GCSPath); delErr != nil {
+				log.Printf("WARN: Failed to remove trash copy of file %s: %v", fileID, delErr)
+			}
+		}
+		return nil, fmt.Errorf("failed to mark file %s deleted: %w", fileID, err)
+	}
+
+	if moved {
+		if err := t.deleteObject(ctx, file.GCSPath); err != nil {
+			// The file is in the trash either way; Restore overwrites the original and
+			// Purge deletes it
+			log.Printf("WARN: Failed to delete original object of trashed file %s: %v", fileID, err)
+		}
+	}
+	return file, nil
+}
+
+// Restore takes the user's file out of the trash. A file whose retention window has
+// passed is purged instead and ErrRetentionExpired returned.
+func (t *FileTrash) Restore(ctx context.Context, userID, fileID string) (*SyncFile, error) {
+	file, err := t.get(ctx, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: file %s", ErrNotInTrash, fileID)
+	}
+	if t.expired(file) {
+		if err := t.purge(ctx, file); err != nil {
+			log.Printf("WARN: Failed to purge expired file %s: %v", fileID, err)
+		}
+		return nil, fmt.Errorf("%w: file %s was deleted at %s", ErrRetentionExpired, fileID, file.DeletedAt.Format(time.RFC3339))
+	}
+
+	// Without a trash copy, Delete left the shared original in place
+	moved := file.Status == FileStatusUploaded && file.GCSPath != ""
+	if moved {
+		if moved, err = t.objectExists(ctx, trashPrefix+file.GCSPath); err != nil {
+			return nil, err
+		}
+	}
+	if moved {
+		if err := t.copyObject(ctx, trashPrefix+file.GCSPath, file.GCSPath); err != nil {
+			return nil, err
+		}
+	}
+
+	file.DeletedAt = nil
+	file.UpdatedAt = t.now()
+	if err := t.files.Update(ctx, file); err != nil {
+		// The trash copy is still there, so the file stays restorable
+		return nil, fmt.Errorf("failed to mark file %s restored: %w", fileID, err)
+	}
+
+	if moved {
+		if err := t.deleteObject(ctx, trashPrefix+file.GCSPath); err != nil {
+			log.Printf("WARN: Failed to delete trash copy of restored file %s: %v", fileID, err)
+		}
+	}
+	return file, nil
+}
+
+// expired reports whether a trashed file's retention window has passed
+func (t *FileTrash) expired(file *SyncFile) bool {
+	return file.DeletedAt != nil && !t.now().Before(file.DeletedAt.Add(t.retention))
+}
+
+// Purge permanently deletes files whose retention window has passed, returning how
+// many were removed
+func (t *FileTrash) Purge(ctx context.Context) (int, error) {
+	expired, err := t.files.ListDeleted(ctx, t.now().Add(-t.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deleted files: %w", err)
+	}
+
+	purged := 0
+	for _, file := range expired {
+		if err := t.purge(ctx, file); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purge deletes a trashed file's objects and record. Only an uploaded file can have a
+// trash copy (none if Delete kept a shared original); its original object is deleted
+// too, in case Delete couldn't remove it, unless another file outside the trash points
+// at the same path (a later upload to it, or a deduplicated file).
+func (t *FileTrash) purge(ctx context.Context, file *SyncFile) error {
+	if file.Status == FileStatusUploaded && file.GCSPath != "" {
+		if err := t.deleteObject(ctx, trashPrefix+file.GCSPath); err != nil {
+			return fmt.Errorf("failed to delete trash object of file %s: %w", file.ID, err)
+		}
+
+		shared, err := t.referencedElsewhere(ctx, file)
+		if err != nil {
+			return err
+		}
+		if !shared {
+			if err := t.deleteObject(ctx, file.GCSPath); err != nil {
+				return fmt.Errorf("failed to delete object of file %s: %w", file.ID, err)
+			}
+		}
+	}
+	if err := t.files.Delete(ctx, file.ID); err != nil {
+		return fmt.Errorf("failed to delete file %s: %w", file.ID, err)
+	}
+	return nil
+}
+
+// referencedElsewhere reports whether a file outside the trash, other than file,
+// points at file's object
+func (t *FileTrash) referencedElsewhere(ctx context.Context, file *SyncFile) (bool, error) {
+	others, err := t.files.ListByGCSPath(ctx, file.GCSPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to list files sharing the object of file %s: %w", file.ID, err)
+	}
+	for _, other := range others {
+		if other.ID != file.ID && other.DeletedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RunPurge calls Purge every interval until ctx is cancelled
+func (t *FileTrash) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := t.Purge(ctx)
+			if err != nil {
+				log.Printf("ERROR: Trash purge failed: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("INFO: Purged %d files from the trash", purged)
+			}
+		}
+	}
+}
+
+func (t *FileTrash) copyObject(ctx context.Context, src, dst string) error {
+	bucket := t.gcsClient.Bucket(t.bucket)
+	if _, err := bucket.Object(dst).CopierFrom(bucket.Object(src)).Run(ctx); err != nil {
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+	return nil
+}
+
+// objectExists reports whether the named object is in the bucket
+func (t *FileTrash) objectExists(ctx context.Context, name string) (bool, error) {
+	_, err := t.gcsClient.Bucket(t.bucket).Object(name).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// deleteObject deletes an object; one that doesn't exist is not an error
+func (t *FileTrash) deleteObject(ctx context.Context, name string) error {
+	err := t.gcsClient.Bucket(t.bucket).Object(name).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return err
+	}
+	return nil
+}
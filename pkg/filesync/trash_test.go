@@ -0,0 +1,196 @@
+package filesync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileTrash_DeleteRestorePurge(t *testing.T) {
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	bucketName := "test-bucket-trash"
+	createTestBucket(t, gcsClient, bucketName)
+
+	files := newMockFileStore()
+	uploader := NewChunkedUploader(gcsClient, bucketName, newMockUploadStore(), files)
+	trash := NewFileTrash(gcsClient, bucketName, files, 24*time.Hour)
+	ctx := context.Background()
+
+	content := "trash me"
+	upload, err := uploader.Initiate(ctx, "user-123", "", "book.pdf", "application/pdf", int64(len(content)))
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	upload, err = uploader.WriteChunk(ctx, upload, 0, int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("WriteChunk failed: %v", err)
+	}
+
+	if _, err := trash.Delete(ctx, "someone-else", upload.FileID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("expected ErrPermissionDenied for another user, got %v", err)
+	}
+	if _, err := trash.Restore(ctx, "user-123", upload.FileID); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("expected ErrNotInTrash before deleting, got %v", err)
+	}
+
+	file, err := trash.Delete(ctx, "user-123", upload.FileID)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if file.DeletedAt == nil {
+		t.Fatal("expected DeletedAt to be set")
+	}
+	verifyGCSObject(t, gcsClient, bucketName, trashPrefix+upload.GCSPath, content)
+	if page, _ := files.ListByUser(ctx, "user-123", "", 10, false); len(page.Files) != 0 {
+		t.Errorf("expected the trashed file to be hidden from listings, got %d files", len(page.Files))
+	}
+
+	file, err = trash.Restore(ctx, "user-123", upload.FileID)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if file.DeletedAt != nil {
+		t.Error("expected DeletedAt to be cleared")
+	}
+	verifyGCSObject(t, gcsClient, bucketName, upload.GCSPath, content)
+
+	// Past the retention window the file can't be restored and is purged
+	if _, err := trash.Delete(ctx, "user-123", upload.FileID); err != nil {
+		t.Fatalf("second Delete failed: %v", err)
+	}
+	trash.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	purged, err := trash.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1 file purged, got %d", purged)
+	}
+	if _, err := files.Get(ctx, upload.FileID); err == nil {
+		t.Error("purged file record still exists")
+	}
+	if _, err := gcsClient.Bucket(bucketName).Object(trashPrefix + upload.GCSPath).Attrs(ctx); err == nil {
+		t.Error("purged trash object still exists")
+	}
+}
+
+// TestFileTrash_PurgeKeepsSharedObject checks that purging a deduplicated file leaves
+// alone the object it points at, which belongs to the file it was deduplicated onto
+func TestFileTrash_PurgeKeepsSharedObject(t *testing.T) {
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	bucketName := "test-bucket-trash-shared"
+	createTestBucket(t, gcsClient, bucketName)
+
+	files := newMockFileStore()
+	uploader := NewChunkedUploader(gcsClient, bucketName, newMockUploadStore(), files)
+	trash := NewFileTrash(gcsClient, bucketName, files, 24*time.Hour)
+	ctx := context.Background()
+
+	content := "keep me"
+	upload, err := uploader.Initiate(ctx, "user-123", "", "book.pdf", "application/pdf", int64(len(content)))
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	upload, err = uploader.WriteChunk(ctx, upload, 0, int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("WriteChunk failed: %v", err)
+	}
+
+	duplicate := &SyncFile{
+		ID:        "duplicate",
+		UserID:    "user-123",
+		GCSPath:   upload.GCSPath,
+		Status:    FileStatusSkipped,
+		UpdatedAt: time.Now(),
+	}
+	if err := files.Create(ctx, duplicate); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := trash.Delete(ctx, "user-123", duplicate.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	trash.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	purged, err := trash.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1 file purged, got %d", purged)
+	}
+	verifyGCSObject(t, gcsClient, bucketName, upload.GCSPath, content)
+	if _, err := files.Get(ctx, upload.FileID); err != nil {
+		t.Errorf("live file record was removed: %v", err)
+	}
+}
+
+// TestFileTrash_DeleteKeepsSharedObject checks that trashing an upload another user's
+// deduplicated file points at leaves the object in place, and that the trashed file
+// can still be restored and purged without a trash copy
+func TestFileTrash_DeleteKeepsSharedObject(t *testing.T) {
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	bucketName := "test-bucket-trash-delete-shared"
+	createTestBucket(t, gcsClient, bucketName)
+
+	files := newMockFileStore()
+	uploader := NewChunkedUploader(gcsClient, bucketName, newMockUploadStore(), files)
+	trash := NewFileTrash(gcsClient, bucketName, files, 24*time.Hour)
+	ctx := context.Background()
+
+	content := "shared"
+	upload, err := uploader.Initiate(ctx, "user-123", "", "book.pdf", "application/pdf", int64(len(content)))
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	upload, err = uploader.WriteChunk(ctx, upload, 0, int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("WriteChunk failed: %v", err)
+	}
+	duplicate := &SyncFile{
+		ID:        "duplicate",
+		UserID:    "user-456",
+		GCSPath:   upload.GCSPath,
+		Status:    FileStatusSkipped,
+		UpdatedAt: time.Now(),
+	}
+	if err := files.Create(ctx, duplicate); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := trash.Delete(ctx, "user-123", upload.FileID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	verifyGCSObject(t, gcsClient, bucketName, upload.GCSPath, content)
+	if _, err := gcsClient.Bucket(bucketName).Object(trashPrefix + upload.GCSPath).Attrs(ctx); err == nil {
+		t.Error("expected no trash copy of a shared object")
+	}
+
+	file, err := trash.Restore(ctx, "user-123", upload.FileID)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if file.DeletedAt != nil {
+		t.Error("expected DeletedAt to be cleared")
+	}
+	verifyGCSObject(t, gcsClient, bucketName, upload.GCSPath, content)
+
+	if _, err := trash.Delete(ctx, "user-123", upload.FileID); err != nil {
+		t.Fatalf("second Delete failed: %v", err)
+	}
+	trash.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	if _, err := trash.Purge(ctx); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if _, err := files.Get(ctx, upload.FileID); err == nil {
+		t.Error("purged file record still exists")
+	}
+	verifyGCSObject(t, gcsClient, bucketName, upload.GCSPath, content)
+}
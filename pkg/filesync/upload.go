@@ -52,31 +52,37 @@ func NewGCSUploader(
 }
 
 // CheckExists checks if a file with the given hash already exists in GCS
-// Returns true and the GCS path if found, false otherwise
+// Returns true and the GCS path if found, false otherwise. Files in the trash don't
+// count: their object has moved under trash/ and is purged when retention ends.
 func (u *GCSUploader) CheckExists(ctx context.Context, hash string) (exists bool, gcsPath string, err error) {
-	// Query Firestore for files with matching hash and uploaded status
+	// Query Firestore for files with matching hash and uploaded status. Trashed files
+	// keep that status, so they're skipped below.
 	iter := u.firestoreClient.Collection(u.collection).
 		Where("hash", "==", hash).
 		Where("status", "==", string(FileStatusUploaded)).
-		Limit(1).
 		Documents(ctx)
 	defer iter.Stop()
 
-	doc, err := iter.Next()
-	if err == iterator.Done {
-		// No matching file found
-		return false, "", nil
-	}
-	if err != nil {
-		return false, "", fmt.Errorf("failed to query for existing file: %w", err)
-	}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			// No matching file found
+			return false, "", nil
+		}
+		if err != nil {
+			return false, "", fmt.Errorf("failed to query for existing file: %w", err)
+		}
 
-	var file SyncFile
-	if err := doc.DataTo(&file); err != nil {
-		return false, "", fmt.Errorf("failed to unmarshal file data: %w", err)
-	}
+		var file SyncFile
+		if err := doc.DataTo(&file); err != nil {
+			return false, "", fmt.Errorf("failed to unmarshal file data: %w", err)
+		}
+		if file.DeletedAt != nil {
+			continue
+		}
 
-	return true, file.GCSPath, nil
+		return true, file.GCSPath, nil
+	}
 }
 
 // Upload uploads a file to GCS, sending progress updates
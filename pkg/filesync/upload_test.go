@@ -149,6 +149,42 @@ func TestGCSUploader_CheckExists_Found(t *testing.T) {
 	}
 }
 
+func TestGCSUploader_CheckExists_SkipsTrash(t *testing.T) {
+	firestoreClient := getTestClient(t)
+	defer firestoreClient.Close()
+
+	gcsClient := getTestGCSClient(t)
+	defer gcsClient.Close()
+
+	uploader := NewGCSUploader(gcsClient, firestoreClient, "test-bucket")
+	ctx := context.Background()
+
+	// A trashed file keeps its uploaded status, but its object is under trash/
+	testHash := "test-hash-trashed"
+	deletedAt := time.Now()
+	syncFile := &SyncFile{
+		ID:        testHash,
+		Hash:      testHash,
+		GCSPath:   "test/path/trashed.txt",
+		Status:    FileStatusUploaded,
+		UpdatedAt: deletedAt,
+		DeletedAt: &deletedAt,
+	}
+	defer cleanupTestFile(t, firestoreClient, uploader.collection, testHash)
+
+	if _, err := firestoreClient.Collection(uploader.collection).Doc(testHash).Set(ctx, syncFile); err != nil {
+		t.Fatalf("failed to create test file record: %v", err)
+	}
+
+	exists, gcsPath, err := uploader.CheckExists(ctx, testHash)
+	if err != nil {
+		t.Fatalf("CheckExists failed: %v", err)
+	}
+	if exists || gcsPath != "" {
+		t.Errorf("expected a trashed file not to be reused, got exists=%v path=%q", exists, gcsPath)
+	}
+}
+
 func TestGCSUploader_CheckExists_NotFound(t *testing.T) {
 	firestoreClient := getTestClient(t)
 	defer firestoreClient.Close()
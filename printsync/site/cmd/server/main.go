@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -20,6 +21,9 @@ import (
 func main() {
 	cfg := config.Load()
 
+	// Structured logging; the stdlib log package writes through it too
+	slog.SetDefault(cfg.Logger())
+
 	ctx := context.Background()
 
 	// Initialize Firestore client
@@ -54,8 +58,19 @@ func main() {
 	sessionStore := filesync.NewFirestoreSessionStore(fsClient.Client)
 	fileStore := filesync.NewFirestoreFileStore(fsClient.Client)
 
+	// Chunked uploads, with abandoned sessions cleaned up in the background
+	uploadStore := filesync.NewFirestoreUploadStore(fsClient.Client)
+	uploader := filesync.NewChunkedUploader(gcsClient, cfg.GCSBucketName, uploadStore, fileStore)
+	cleanupCtx, stopCleanup := context.WithCancel(ctx)
+	defer stopCleanup()
+	go uploader.RunCleanup(cleanupCtx, time.Hour, 24*time.Hour)
+
+	// Deleted files stay in the trash for the retention window, then are purged
+	trash := filesync.NewFileTrash(gcsClient, cfg.GCSBucketName, fileStore, cfg.TrashRetention)
+	go trash.RunPurge(cleanupCtx, time.Hour)
+
 	// Create router with all dependencies
-	router := server.NewRouter(fsClient, gcsClient, cfg.GCSBucketName, firebaseApp, sessionStore, fileStore)
+	router := server.NewRouter(fsClient, gcsClient, cfg.GCSBucketName, firebaseApp, sessionStore, fileStore, uploader, cfg.SignedURLExpiry, cfg.StorageQuotaBytes, trash)
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,16 +16,32 @@ type Config struct {
 	SyncRootDir    string
 	GCSBucketName  string
 	ConcurrentJobs int
+	// SignedURLExpiry is how long download URLs from GET /api/files/{id}/download-url stay valid
+	SignedURLExpiry time.Duration
+	// StorageQuotaBytes caps the bytes each user's uploaded files may take up; uploads
+	// that would exceed it are rejected with 413 (0 = no quota)
+	StorageQuotaBytes int64
+	// TrashRetention is how long deleted files stay restorable before they are purged
+	TrashRetention time.Duration
+	// Logging: LogLevel is debug, info, warn or error; LogFormat is json or text
+	LogLevel  string
+	LogFormat string
 }
 
 func Load() Config {
 	return Config{
-		Port:           getEnv("PORT", "8080"),
-		GCPProjectID:   getEnv("GCP_PROJECT_ID", "chalanding"),
-		Environment:    getEnv("GO_ENV", "production"),
-		SyncRootDir:    getEnv("SYNC_ROOT_DIR", "~/Downloads"),
-		GCSBucketName:  getEnv("GCS_BUCKET_NAME", "rml-media"),
-		ConcurrentJobs: getEnvInt("CONCURRENT_JOBS", 8),
+		Port:            getEnv("PORT", "8080"),
+		GCPProjectID:    getEnv("GCP_PROJECT_ID", "chalanding"),
+		Environment:     getEnv("GO_ENV", "production"),
+		SyncRootDir:     getEnv("SYNC_ROOT_DIR", "~/Downloads"),
+		GCSBucketName:   getEnv("GCS_BUCKET_NAME", "rml-media"),
+		ConcurrentJobs:  getEnvInt("CONCURRENT_JOBS", 8),
+		SignedURLExpiry: getEnvDuration("SIGNED_URL_EXPIRY", 15*time.Minute),
+		// 50 GiB per user
+		StorageQuotaBytes: getEnvInt64("USER_STORAGE_QUOTA_BYTES", 50<<30),
+		TrashRetention:    getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		LogFormat:         getEnv("LOG_FORMAT", "json"),
 	}
 }
 
@@ -41,3 +60,36 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil && intValue >= 0 {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// Logger builds the structured logger described by LogLevel and LogFormat. Unknown
+// values fall back to info and JSON.
+func (c Config) Logger() *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	if strings.EqualFold(c.LogFormat, "text") {
+		return slog.New(slog.NewTextHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/commons-systems/filesync"
+	"printsync/internal/middleware"
+)
+
+// maxSignedURLExpiry is the longest validity GCS allows for V4 signed URLs
+const maxSignedURLExpiry = 7 * 24 * time.Hour
+
+// DownloadHandlers hands out signed URLs so clients download files straight from GCS
+type DownloadHandlers struct {
+	gcsClient *storage.Client
+	bucket    string
+	fileStore filesync.FileStore
+	expiry    time.Duration
+}
+
+// NewDownloadHandlers creates a new download handlers instance
+func NewDownloadHandlers(gcsClient *storage.Client, bucket string, fileStore filesync.FileStore, expiry time.Duration) (*DownloadHandlers, error) {
+	if gcsClient == nil {
+		return nil, fmt.Errorf("gcsClient is required")
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if fileStore == nil {
+		return nil, fmt.Errorf("fileStore is required")
+	}
+	if expiry <= 0 || expiry > maxSignedURLExpiry {
+		return nil, fmt.Errorf("signed URL expiry must be between 0 and %v, got %v", maxSignedURLExpiry, expiry)
+	}
+
+	return &DownloadHandlers{
+		gcsClient: gcsClient,
+		bucket:    bucket,
+		fileStore: fileStore,
+		expiry:    expiry,
+	}, nil
+}
+
+// DownloadURLResponse is a time-limited URL for downloading a file from GCS
+type DownloadURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// DownloadURL handles GET /api/files/{id}/download-url
+func (h *DownloadHandlers) DownloadURL(w http.ResponseWriter, r *http.Request) {
+	fileID := r.PathValue("id")
+
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: DownloadURL for file %s - unauthorized access attempt", fileID)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	file, err := h.fileStore.Get(r.Context(), fileID)
+	if err != nil {
+		log.Printf("ERROR: DownloadURL for user %s, file %s - file not found: %v", authInfo.UserID, fileID, err)
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	// Verify ownership before signing anything
+	if file.UserID != authInfo.UserID {
+		log.Printf("ERROR: DownloadURL - user %s attempted to download file %s owned by %s", authInfo.UserID, fileID, file.UserID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if file.DeletedAt != nil {
+		log.Printf("ERROR: DownloadURL for user %s, file %s - file is in the trash", authInfo.UserID, fileID)
+		http.Error(w, "File is in the trash", http.StatusGone)
+		return
+	}
+
+	if file.Status != filesync.FileStatusUploaded || file.GCSPath == "" {
+		log.Printf("ERROR: DownloadURL for user %s, file %s - file is %s, not uploaded", authInfo.UserID, fileID, file.Status)
+		http.Error(w, "File not uploaded", http.StatusConflict)
+		return
+	}
+
+	expiresAt := time.Now().Add(h.expiry)
+	url, err := h.gcsClient.Bucket(h.bucket).SignedURL(file.GCSPath, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: expiresAt,
+		QueryParameters: map[string][]string{
+			"response-content-disposition": {fmt.Sprintf("attachment; filename=%q", path.Base(file.GCSPath))},
+		},
+	})
+	if err != nil {
+		log.Printf("ERROR: DownloadURL for user %s, file %s - failed to sign URL: %v", authInfo.UserID, fileID, err)
+		http.Error(w, "Failed to create download URL", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(DownloadURLResponse{
+		URL:       url,
+		ExpiresAt: expiresAt.UTC(),
+	})
+}
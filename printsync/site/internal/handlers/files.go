@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/commons-systems/filesync"
+	"printsync/internal/middleware"
+)
+
+// FileHandlers moves files to the trash and restores them
+type FileHandlers struct {
+	trash *filesync.FileTrash
+}
+
+// NewFileHandlers creates a new file handlers instance
+func NewFileHandlers(trash *filesync.FileTrash) (*FileHandlers, error) {
+	if trash == nil {
+		return nil, fmt.Errorf("trash is required")
+	}
+	return &FileHandlers{trash: trash}, nil
+}
+
+// TrashedFileResponse describes a file after it was deleted or restored. PurgeAt is
+// when a file in the trash is permanently deleted.
+type TrashedFileResponse struct {
+	File    *filesync.SyncFile `json:"file"`
+	PurgeAt *time.Time         `json:"purgeAt,omitempty"`
+}
+
+// DeleteFile handles DELETE /api/files/{id}
+func (h *FileHandlers) DeleteFile(w http.ResponseWriter, r *http.Request) {
+	fileID := r.PathValue("id")
+
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: DeleteFile for file %s - unauthorized access attempt", fileID)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	file, err := h.trash.Delete(r.Context(), authInfo.UserID, fileID)
+	if err != nil {
+		log.Printf("ERROR: DeleteFile for user %s, file %s - %v", authInfo.UserID, fileID, err)
+		writeTrashError(w, err, "Failed to delete file")
+		return
+	}
+
+	purgeAt := file.DeletedAt.Add(h.trash.Retention())
+	writeTrashedFile(w, TrashedFileResponse{File: file, PurgeAt: &purgeAt})
+}
+
+// RestoreFile handles POST /api/files/{id}/restore
+func (h *FileHandlers) RestoreFile(w http.ResponseWriter, r *http.Request) {
+	fileID := r.PathValue("id")
+
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: RestoreFile for file %s - unauthorized access attempt", fileID)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	file, err := h.trash.Restore(r.Context(), authInfo.UserID, fileID)
+	if err != nil {
+		log.Printf("ERROR: RestoreFile for user %s, file %s - %v", authInfo.UserID, fileID, err)
+		writeTrashError(w, err, "Failed to restore file")
+		return
+	}
+
+	writeTrashedFile(w, TrashedFileResponse{File: file})
+}
+
+// writeTrashError maps FileTrash errors to status codes
+func writeTrashError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, filesync.ErrNotFound):
+		http.Error(w, "File not found", http.StatusNotFound)
+	case errors.Is(err, filesync.ErrPermissionDenied):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, filesync.ErrFileBusy):
+		http.Error(w, "File is still being processed", http.StatusConflict)
+	case errors.Is(err, filesync.ErrNotInTrash):
+		http.Error(w, "File is not in the trash", http.StatusConflict)
+	case errors.Is(err, filesync.ErrRetentionExpired):
+		http.Error(w, "File was permanently deleted", http.StatusGone)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+func writeTrashedFile(w http.ResponseWriter, resp TrashedFileResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/commons-systems/filesync"
+	"printsync/internal/middleware"
+	"printsync/internal/streaming"
+)
+
+// fileEventsBuffer is how many file changes may queue while the client is being written to
+const fileEventsBuffer = 64
+
+// StreamFileEvents handles GET /api/files/events (SSE endpoint). It streams a file_change
+// event for every change to the authenticated user's files, starting with one "added"
+// event per existing file, until the client disconnects.
+func (h *SyncHandlers) StreamFileEvents(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: StreamFileEvents - unauthorized access attempt")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("ERROR: StreamFileEvents for user %s - streaming not supported", authInfo.UserID)
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Set SSE headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// The listener stops when the client disconnects or the stream ends
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	changes := make(chan filesync.FileChange, fileEventsBuffer)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- h.fileStore.WatchByUser(ctx, authInfo.UserID, func(change filesync.FileChange) {
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	// Heartbeat ticker
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Client disconnected
+			return
+
+		case err := <-watchErr:
+			// The browser's EventSource reconnects, which starts a new listener
+			if err != nil {
+				log.Printf("ERROR: StreamFileEvents for user %s - %v", authInfo.UserID, err)
+			}
+			return
+
+		case change := <-changes:
+			event := streaming.SSEEvent{
+				Type:      streaming.EventTypeFileChange,
+				Timestamp: time.Now(),
+				Data: streaming.FileChangeEvent{
+					Change: string(change.Kind),
+					File:   change.File,
+				},
+			}
+			if err := writeSSEEventJSON(w, event); err != nil {
+				log.Printf("ERROR: StreamFileEvents for user %s - failed to write event for file %s: %v", authInfo.UserID, change.File.ID, err)
+				return
+			}
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			// Send heartbeat (keep as simple text)
+			if _, err := fmt.Fprintf(w, "event: %s\n", streaming.EventTypeHeartbeat); err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "data: {\"status\":\"alive\"}\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEventJSON writes an SSE event with the JSON-encoded event as its data
+func writeSSEEventJSON(w http.ResponseWriter, event streaming.SSEEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\n", event.Type); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	return nil
+}
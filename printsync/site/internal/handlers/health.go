@@ -1,22 +1,102 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"printsync/internal/firestore"
 )
 
+// readinessTimeout bounds each dependency check so a hung backend fails the probe
+// instead of stalling it
+const readinessTimeout = 3 * time.Second
+
+// readinessCollection is read by the Firestore check (the filesync session collection)
+const readinessCollection = "printsync-sessions"
+
 type HealthResponse struct {
 	Status    string    `json:"status"`
 	Service   string    `json:"service"`
 	Timestamp time.Time `json:"timestamp"`
+	Reason    string    `json:"reason,omitempty"`
 }
 
 func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, "healthy", "")
+}
+
+// HealthHandlers serves the liveness and readiness probes
+type HealthHandlers struct {
+	fsClient  *firestore.Client
+	gcsClient *storage.Client
+	bucket    string
+}
+
+// NewHealthHandlers creates health handlers that check the given dependencies
+func NewHealthHandlers(fsClient *firestore.Client, gcsClient *storage.Client, bucket string) *HealthHandlers {
+	return &HealthHandlers{fsClient: fsClient, gcsClient: gcsClient, bucket: bucket}
+}
+
+// Liveness reports 200 whenever the server is serving requests
+func (h *HealthHandlers) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, "alive", "")
+}
+
+// Readiness reports 200 when Firestore and GCS are reachable, or 503 with the
+// failing dependency as the reason
+func (h *HealthHandlers) Readiness(w http.ResponseWriter, r *http.Request) {
+	if err := h.checkFirestore(r.Context()); err != nil {
+		writeHealth(w, http.StatusServiceUnavailable, "unavailable", fmt.Sprintf("firestore: %v", err))
+		return
+	}
+	if err := h.checkStorage(r.Context()); err != nil {
+		writeHealth(w, http.StatusServiceUnavailable, "unavailable", fmt.Sprintf("storage: %v", err))
+		return
+	}
+	writeHealth(w, http.StatusOK, "ready", "")
+}
+
+// checkFirestore reads at most one document
+func (h *HealthHandlers) checkFirestore(ctx context.Context) error {
+	if h.fsClient == nil {
+		return fmt.Errorf("client not configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	_, err := h.fsClient.Collection(readinessCollection).Limit(1).Documents(ctx).GetAll()
+	return err
+}
+
+// checkStorage lists at most one object in the bucket
+func (h *HealthHandlers) checkStorage(ctx context.Context) error {
+	if h.gcsClient == nil || h.bucket == "" {
+		return fmt.Errorf("client not configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	it := h.gcsClient.Bucket(h.bucket).Objects(ctx, nil)
+	it.PageInfo().MaxSize = 1
+	if _, err := it.Next(); err != nil && err != iterator.Done {
+		return err
+	}
+	return nil
+}
+
+func writeHealth(w http.ResponseWriter, status int, state, reason string) {
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(HealthResponse{
-		Status:    "healthy",
+		Status:    state,
 		Service:   "printsync",
 		Timestamp: time.Now().UTC(),
+		Reason:    reason,
 	})
 }
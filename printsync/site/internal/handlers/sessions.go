@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/commons-systems/filesync"
+	"printsync/internal/middleware"
+)
+
+// SessionSummary describes one of the user's sync sessions. LastActivityAt is when the
+// session finished, or when it started while it is still running.
+type SessionSummary struct {
+	ID             string                 `json:"id"`
+	Status         filesync.SessionStatus `json:"status"`
+	RootDir        string                 `json:"rootDir"`
+	StartedAt      time.Time              `json:"startedAt"`
+	LastActivityAt time.Time              `json:"lastActivityAt"`
+	Stats          filesync.SessionStats  `json:"stats"`
+}
+
+// ListSessionsResponse is the authenticated user's sync sessions, most recent first
+type ListSessionsResponse struct {
+	Sessions []SessionSummary `json:"sessions"`
+}
+
+// ListSessions handles GET /api/sessions
+func (h *SyncHandlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: ListSessions - unauthorized access attempt")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.sessionStore.List(r.Context(), authInfo.UserID)
+	if err != nil {
+		log.Printf("ERROR: ListSessions for user %s - failed to list sessions: %v", authInfo.UserID, err)
+		http.Error(w, "Failed to list sessions", http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]SessionSummary, 0, len(sessions))
+	for _, session := range sessions {
+		lastActivity := session.StartedAt
+		if session.CompletedAt != nil {
+			lastActivity = *session.CompletedAt
+		}
+		summaries = append(summaries, SessionSummary{
+			ID:             session.ID,
+			Status:         session.Status,
+			RootDir:        session.RootDir,
+			StartedAt:      session.StartedAt,
+			LastActivityAt: lastActivity,
+			Stats:          session.Stats,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListSessionsResponse{Sessions: summaries})
+}
+
+// DeleteSession handles DELETE /api/sessions/{id}. It removes the session record only;
+// the session's files stay in the user's library. Sessions owned by someone else are
+// reported as not found, so their IDs can't be probed. Running sessions must be
+// cancelled first, since the pipeline would recreate the record on its next update.
+func (h *SyncHandlers) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: DeleteSession for session %s - unauthorized access attempt", sessionID)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.sessionStore.Get(r.Context(), sessionID)
+	if err != nil {
+		log.Printf("ERROR: DeleteSession for user %s, session %s - session not found: %v", authInfo.UserID, sessionID, err)
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if session.UserID != authInfo.UserID {
+		log.Printf("ERROR: DeleteSession - user %s attempted to delete session %s owned by %s", authInfo.UserID, sessionID, session.UserID)
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if _, running := h.registry.Get(sessionID); running {
+		log.Printf("ERROR: DeleteSession for user %s, session %s - session still running", authInfo.UserID, sessionID)
+		http.Error(w, "Session is still running; cancel it first", http.StatusConflict)
+		return
+	}
+
+	if err := h.sessionStore.Delete(r.Context(), sessionID); err != nil {
+		log.Printf("ERROR: DeleteSession for user %s, session %s - failed to delete session: %v", authInfo.UserID, sessionID, err)
+		http.Error(w, "Failed to delete session", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: User %s deleted session %s", authInfo.UserID, sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,9 +3,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 
 	"cloud.google.com/go/storage"
 	"github.com/commons-systems/filesync"
@@ -231,6 +233,75 @@ func (h *SyncHandlers) CancelSync(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"status":"cancelled"}`))
 }
 
+const (
+	// defaultFilesLimit is the page size when ?limit= is omitted
+	defaultFilesLimit = 50
+	// maxFilesLimit caps ?limit= for GET /api/files
+	maxFilesLimit = 200
+)
+
+// ListFilesResponse is one page of the authenticated user's files
+type ListFilesResponse struct {
+	Files      []*filesync.SyncFile `json:"files"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+// ListFiles handles GET /api/files?cursor=&limit=&trashed=. Files in the trash are
+// only included with trashed=true.
+func (h *SyncHandlers) ListFiles(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: ListFiles - unauthorized access attempt")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := defaultFilesLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxFilesLimit {
+			log.Printf("ERROR: ListFiles for user %s - invalid limit %q", authInfo.UserID, raw)
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxFilesLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	cursor := r.URL.Query().Get("cursor")
+	includeTrashed := false
+	if raw := r.URL.Query().Get("trashed"); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Printf("ERROR: ListFiles for user %s - invalid trashed %q", authInfo.UserID, raw)
+			http.Error(w, "trashed must be true or false", http.StatusBadRequest)
+			return
+		}
+		includeTrashed = b
+	}
+
+	page, err := h.fileStore.ListByUser(r.Context(), authInfo.UserID, cursor, limit, includeTrashed)
+	if errors.Is(err, filesync.ErrInvalidCursor) {
+		log.Printf("ERROR: ListFiles for user %s - invalid cursor %q", authInfo.UserID, cursor)
+		http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: ListFiles for user %s - failed to list files: %v", authInfo.UserID, err)
+		http.Error(w, "Failed to list files", http.StatusInternalServerError)
+		return
+	}
+
+	files := page.Files
+	if files == nil {
+		files = []*filesync.SyncFile{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListFilesResponse{
+		Files:      files,
+		NextCursor: page.NextCursor,
+	})
+}
+
 // ApproveFile handles POST /api/files/{id}/approve
 func (h *SyncHandlers) ApproveFile(w http.ResponseWriter, r *http.Request) {
 	fileID := r.PathValue("id")
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/commons-systems/filesync"
+	"printsync/internal/middleware"
+)
+
+// maxChunkSize caps the body of PUT /api/uploads/{id}
+const maxChunkSize = 32 << 20
+
+// UploadHandlers handles chunked upload requests
+type UploadHandlers struct {
+	uploader  *filesync.ChunkedUploader
+	fileStore filesync.FileStore
+	quota     int64
+}
+
+// NewUploadHandlers creates a new upload handlers instance. quota caps each user's
+// stored bytes (0 = no quota).
+func NewUploadHandlers(uploader *filesync.ChunkedUploader, fileStore filesync.FileStore, quota int64) (*UploadHandlers, error) {
+	if uploader == nil {
+		return nil, fmt.Errorf("uploader is required")
+	}
+	if fileStore == nil {
+		return nil, fmt.Errorf("fileStore is required")
+	}
+	if quota < 0 {
+		return nil, fmt.Errorf("quota must not be negative, got %d", quota)
+	}
+	return &UploadHandlers{uploader: uploader, fileStore: fileStore, quota: quota}, nil
+}
+
+// QuotaExceededResponse is the 413 body for uploads that would exceed the user's quota
+type QuotaExceededResponse struct {
+	Error      string `json:"error"`
+	UsedBytes  int64  `json:"usedBytes"`
+	QuotaBytes int64  `json:"quotaBytes"`
+	Size       int64  `json:"size"`
+}
+
+// InitiateUploadRequest represents the request to start a chunked upload
+type InitiateUploadRequest struct {
+	FileName    string `json:"fileName"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType"`
+	SessionID   string `json:"sessionId"`
+}
+
+// UploadStatusResponse reports how much of an upload the server has
+type UploadStatusResponse struct {
+	UploadID string `json:"uploadId"`
+	Offset   int64  `json:"offset"`
+	Size     int64  `json:"size"`
+	Status   string `json:"status"`
+	FileID   string `json:"fileId,omitempty"`
+	GCSPath  string `json:"gcsPath,omitempty"`
+}
+
+// InitiateUpload handles POST /api/uploads. It returns an upload ID, not a GCS
+// resumable session URI: the Go storage client doesn't expose one, so chunks are sent
+// to PutChunk and stored by the server (see filesync.ChunkedUploader).
+func (h *UploadHandlers) InitiateUpload(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: InitiateUpload - unauthorized access attempt")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req InitiateUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: InitiateUpload for user %s - invalid request body: %v", authInfo.UserID, err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.FileName == "" || req.Size <= 0 {
+		log.Printf("ERROR: InitiateUpload for user %s - fileName and a positive size are required", authInfo.UserID)
+		http.Error(w, "fileName and a positive size are required", http.StatusBadRequest)
+		return
+	}
+
+	if h.quota > 0 {
+		used, err := h.fileStore.Usage(r.Context(), authInfo.UserID)
+		if err != nil {
+			log.Printf("ERROR: InitiateUpload for user %s - failed to check storage usage: %v", authInfo.UserID, err)
+			http.Error(w, "Failed to check storage usage", http.StatusInternalServerError)
+			return
+		}
+		if used+req.Size > h.quota {
+			log.Printf("ERROR: InitiateUpload for user %s - %d byte upload exceeds quota (%d of %d bytes used)", authInfo.UserID, req.Size, used, h.quota)
+			writeQuotaExceeded(w, used, h.quota, req.Size)
+			return
+		}
+	}
+
+	upload, err := h.uploader.Initiate(r.Context(), authInfo.UserID, req.SessionID, req.FileName, req.ContentType, req.Size)
+	if err != nil {
+		log.Printf("ERROR: InitiateUpload for user %s - failed to initiate upload: %v", authInfo.UserID, err)
+		http.Error(w, fmt.Sprintf("Failed to initiate upload: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeUploadStatus(w, http.StatusCreated, upload)
+}
+
+// PutChunk handles PUT /api/uploads/{id}. The body is one chunk described by
+// "Content-Range: bytes start-end/total". An empty body with "bytes */total" asks for
+// the upload's status. Incomplete uploads answer 308 with "Range: bytes=0-N" (as GCS
+// resumable uploads do), the completing chunk answers 200, and a chunk at the wrong
+// offset answers 409 with the offset to resume from. Each chunk is stored as a part
+// object under uploads/.parts/{id}/ and the completing chunk composes the parts into
+// the final object. Uploads untouched for a day are removed with their parts.
+func (h *UploadHandlers) PutChunk(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("id")
+
+	// Get authenticated user
+	authInfo, ok := middleware.GetAuth(r)
+	if !ok {
+		log.Printf("ERROR: PutChunk for upload %s - unauthorized access attempt", uploadID)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	upload, err := h.uploader.Get(r.Context(), authInfo.UserID, uploadID)
+	if errors.Is(err, filesync.ErrPermissionDenied) {
+		log.Printf("ERROR: PutChunk - user %s attempted to write upload %s", authInfo.UserID, uploadID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: PutChunk for user %s, upload %s - upload not found: %v", authInfo.UserID, uploadID, err)
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return
+	}
+
+	start, end, total, err := parseContentRange(r.Header.Get("Content-Range"))
+	if err != nil || total != upload.TotalSize {
+		log.Printf("ERROR: PutChunk for user %s, upload %s - invalid Content-Range %q", authInfo.UserID, uploadID, r.Header.Get("Content-Range"))
+		http.Error(w, "Invalid Content-Range", http.StatusBadRequest)
+		return
+	}
+
+	// Status query
+	if start < 0 {
+		writeUploadProgress(w, upload)
+		return
+	}
+
+	length := end - start + 1
+	if length > maxChunkSize {
+		http.Error(w, fmt.Sprintf("Chunks are limited to %d bytes", maxChunkSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if r.ContentLength >= 0 && r.ContentLength != length {
+		log.Printf("ERROR: PutChunk for user %s, upload %s - body is %d bytes, Content-Range says %d", authInfo.UserID, uploadID, r.ContentLength, length)
+		http.Error(w, "Body length does not match Content-Range", http.StatusBadRequest)
+		return
+	}
+	body := http.MaxBytesReader(w, r.Body, length)
+
+	upload, err = h.uploader.WriteChunk(r.Context(), upload, start, length, body)
+	var quotaErr *filesync.QuotaExceededError
+	switch {
+	case errors.Is(err, filesync.ErrOffsetMismatch):
+		writeUploadStatus(w, http.StatusConflict, upload)
+		return
+	case errors.Is(err, filesync.ErrUploadFinalized):
+		writeUploadStatus(w, http.StatusOK, upload)
+		return
+	case errors.As(err, &quotaErr):
+		// A store with a quota checks it again when the last chunk records the file
+		log.Printf("ERROR: PutChunk for user %s, upload %s - %v", authInfo.UserID, uploadID, err)
+		writeQuotaExceeded(w, quotaErr.UsedBytes, quotaErr.QuotaBytes, quotaErr.Size)
+		return
+	case err != nil:
+		log.Printf("ERROR: PutChunk for user %s, upload %s - failed to write chunk: %v", authInfo.UserID, uploadID, err)
+		http.Error(w, fmt.Sprintf("Failed to write chunk: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeUploadProgress(w, upload)
+}
+
+// writeQuotaExceeded writes the 413 response for an upload that would exceed the
+// user's quota
+func writeQuotaExceeded(w http.ResponseWriter, used, quota, size int64) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.NewEncoder(w).Encode(QuotaExceededResponse{
+		Error:      "Storage quota exceeded",
+		UsedBytes:  used,
+		QuotaBytes: quota,
+		Size:       size,
+	})
+}
+
+// parseContentRange parses "bytes start-end/total", or "bytes */total" which returns
+// start -1
+func parseContentRange(header string) (start, end, total int64, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("missing bytes unit")
+	}
+	rangePart, totalPart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("missing total")
+	}
+	if total, err = strconv.ParseInt(totalPart, 10, 64); err != nil || total <= 0 {
+		return 0, 0, 0, fmt.Errorf("invalid total %q", totalPart)
+	}
+	if rangePart == "*" {
+		return -1, -1, total, nil
+	}
+
+	startPart, endPart, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid range %q", rangePart)
+	}
+	start, errStart := strconv.ParseInt(startPart, 10, 64)
+	end, errEnd := strconv.ParseInt(endPart, 10, 64)
+	if errStart != nil || errEnd != nil || start < 0 || end < start || end >= total {
+		return 0, 0, 0, fmt.Errorf("invalid range %q", rangePart)
+	}
+	return start, end, total, nil
+}
+
+// writeUploadProgress answers 200 for finalized uploads and 308 otherwise
+func writeUploadProgress(w http.ResponseWriter, upload *filesync.UploadSession) {
+	if upload.Status == filesync.UploadStatusFinalized {
+		writeUploadStatus(w, http.StatusOK, upload)
+		return
+	}
+	if upload.Received > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", upload.Received-1))
+	}
+	writeUploadStatus(w, http.StatusPermanentRedirect, upload)
+}
+
+func writeUploadStatus(w http.ResponseWriter, status int, upload *filesync.UploadSession) {
+	resp := UploadStatusResponse{
+		UploadID: upload.ID,
+		Offset:   upload.Received,
+		Size:     upload.TotalSize,
+		Status:   string(upload.Status),
+	}
+	if upload.Status == filesync.UploadStatusFinalized {
+		resp.FileID = upload.FileID
+		resp.GCSPath = upload.GCSPath
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
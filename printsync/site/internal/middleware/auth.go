@@ -68,6 +68,8 @@ func FirebaseAuth(firebaseApp *firebase.App) func(http.Handler) http.Handler {
 				authInfo.Email = claims
 			}
 
+			setLoggedUser(r.Context(), authInfo.UserID)
+
 			// Store auth info in request context
 			ctx := context.WithValue(r.Context(), AuthKey, authInfo)
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+const (
+	RequestIDKey  contextKey = "requestID"
+	requestLogKey contextKey = "requestLog"
+
+	// RequestIDHeader carries the request ID on requests and responses
+	RequestIDHeader = "X-Request-ID"
+)
+
+// validRequestID limits which incoming request IDs are trusted and echoed back
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// requestLog collects fields set further down the chain, such as the
+// authenticated user, for the request's log line
+type requestLog struct {
+	userID string
+}
+
+// RequestLogger logs one structured line per request through slog.Default(): method,
+// path, status, duration, request ID and the authenticated UID when there is one.
+// Server errors log at error level and client errors at warn. The request ID is taken
+// from a valid incoming X-Request-ID header or generated, stored in the context (see
+// GetRequestID) and returned in the X-Request-ID response header.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		entry := &requestLog{}
+		ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+		ctx = context.WithValue(ctx, requestLogKey, entry)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("requestId", requestID),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int64("bytes", rec.bytes),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if entry.userID != "" {
+			attrs = append(attrs, slog.String("uid", entry.userID))
+		}
+		slog.LogAttrs(r.Context(), level, "request", attrs...)
+	})
+}
+
+// GetRequestID retrieves the request ID set by RequestLogger
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDKey).(string)
+	return id
+}
+
+// setLoggedUser records the authenticated user for the request's log line
+func setLoggedUser(ctx context.Context, userID string) {
+	if entry, ok := ctx.Value(requestLogKey).(*requestLog); ok {
+		entry.userID = userID
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder captures the status code and body size written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps SSE streaming working through the recorder
+func (s *statusRecorder) Flush() {
+	if s.status == 0 {
+		s.status = http.StatusOK // Flushing commits the headers
+	}
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
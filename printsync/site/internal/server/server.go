@@ -3,6 +3,7 @@ package server
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"cloud.google.com/go/storage"
 	firebase "firebase.google.com/go/v4"
@@ -20,6 +21,10 @@ func NewRouter(
 	firebaseApp *firebase.App,
 	sessionStore filesync.SessionStore,
 	fileStore filesync.FileStore,
+	uploader *filesync.ChunkedUploader,
+	signedURLExpiry time.Duration,
+	storageQuota int64,
+	trash *filesync.FileTrash,
 ) http.Handler {
 	mux := http.NewServeMux()
 
@@ -29,6 +34,11 @@ func NewRouter(
 	// Health check for Cloud Run
 	mux.HandleFunc("GET /health", handlers.HealthHandler)
 
+	// Liveness and readiness probes (unauthenticated)
+	healthH := handlers.NewHealthHandlers(fs, gcsClient, bucket)
+	mux.HandleFunc("GET /healthz", healthH.Liveness)
+	mux.HandleFunc("GET /readyz", healthH.Readiness)
+
 	// Pages (support HTMX partial + full page)
 	pageH := handlers.NewPageHandlers(fs)
 	mux.HandleFunc("GET /", pageH.Home)
@@ -57,6 +67,24 @@ func NewRouter(
 		log.Fatalf("Failed to create sync handlers: %v", err)
 	}
 
+	// Chunked upload handlers
+	uploadH, err := handlers.NewUploadHandlers(uploader, fileStore, storageQuota)
+	if err != nil {
+		log.Fatalf("Failed to create upload handlers: %v", err)
+	}
+
+	// Signed download URL handlers
+	downloadH, err := handlers.NewDownloadHandlers(gcsClient, bucket, fileStore, signedURLExpiry)
+	if err != nil {
+		log.Fatalf("Failed to create download handlers: %v", err)
+	}
+
+	// Soft-delete handlers
+	fileH, err := handlers.NewFileHandlers(trash)
+	if err != nil {
+		log.Fatalf("Failed to create file handlers: %v", err)
+	}
+
 	// Protected sync API routes (require Firebase Auth)
 	authMiddleware := middleware.FirebaseAuth(firebaseApp)
 
@@ -68,19 +96,32 @@ func NewRouter(
 	mux.Handle("POST /api/sync/{id}/approve-all", authMiddleware(http.HandlerFunc(syncH.ApproveAll)))
 	mux.Handle("POST /api/sync/{id}/trash-all", authMiddleware(http.HandlerFunc(syncH.TrashAll)))
 
+	// Session API
+	mux.Handle("GET /api/sessions", authMiddleware(http.HandlerFunc(syncH.ListSessions)))
+	mux.Handle("DELETE /api/sessions/{id}", authMiddleware(http.HandlerFunc(syncH.DeleteSession)))
+
 	// File API
+	mux.Handle("GET /api/files", authMiddleware(http.HandlerFunc(syncH.ListFiles)))
+	mux.Handle("GET /api/files/events", authMiddleware(http.HandlerFunc(syncH.StreamFileEvents)))
+	mux.Handle("GET /api/files/{id}/download-url", authMiddleware(http.HandlerFunc(downloadH.DownloadURL)))
+	mux.Handle("DELETE /api/files/{id}", authMiddleware(http.HandlerFunc(fileH.DeleteFile)))
+	mux.Handle("POST /api/files/{id}/restore", authMiddleware(http.HandlerFunc(fileH.RestoreFile)))
 	mux.Handle("POST /api/files/{id}/approve", authMiddleware(http.HandlerFunc(syncH.ApproveFile)))
 	mux.Handle("POST /api/files/{id}/reject", authMiddleware(http.HandlerFunc(syncH.RejectFile)))
 	mux.Handle("POST /api/files/{id}/retry", authMiddleware(http.HandlerFunc(syncH.RetryFile)))
 	mux.Handle("POST /api/files/{id}/trash", authMiddleware(http.HandlerFunc(syncH.TrashFile)))
 
+	// Chunked upload API
+	mux.Handle("POST /api/uploads", authMiddleware(http.HandlerFunc(uploadH.InitiateUpload)))
+	mux.Handle("PUT /api/uploads/{id}", authMiddleware(http.HandlerFunc(uploadH.PutChunk)))
+
 	// Protected partials
 	mux.Handle("GET /partials/sync/history", authMiddleware(http.HandlerFunc(syncH.HistoryPartial)))
 	mux.Handle("GET /partials/trash-modal", authMiddleware(http.HandlerFunc(syncH.RenderTrashModal)))
 
 	// Apply middleware
 	return middleware.Chain(mux,
-		middleware.Logger,
+		middleware.RequestLogger,
 		middleware.HTMX,
 	)
 }
@@ -13,6 +13,8 @@ const (
 	EventTypeFile      = "file"
 	EventTypeComplete  = "complete"
 	EventTypeHeartbeat = "heartbeat"
+	// EventTypeFileChange is sent by GET /api/files/events for each change to the user's files
+	EventTypeFileChange = "file_change"
 )
 
 // SSEEvent represents a server-sent event
@@ -52,3 +54,10 @@ type CompleteEvent struct {
 	SessionID string                 `json:"sessionId"`
 	Status    filesync.SessionStatus `json:"status"`
 }
+
+// FileChangeEvent represents a change to one of the user's files. Change is "added",
+// "modified" or "removed" (see filesync.FileChangeKind).
+type FileChangeEvent struct {
+	Change string             `json:"change"`
+	File   *filesync.SyncFile `json:"file"`
+}
@@ -33,13 +33,14 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+		// Terminals can report zero while resizing; never store a negative size
+		m.width = max(msg.Width, 0)
+		m.height = max(msg.Height, 0)
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
 			return m, tea.Quit
 		}
 	}
@@ -47,7 +48,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// View renders the model
+// View renders the model at the last reported terminal size
 func (m Model) View() string {
-	return ui.Render()
+	return ui.Render(m.width, m.height)
 }
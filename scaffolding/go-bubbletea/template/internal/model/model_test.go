@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestNew(t *testing.T) {
@@ -49,24 +50,51 @@ func TestUpdateWindowSize(t *testing.T) {
 func TestUpdateQuitKeys(t *testing.T) {
 	tests := []struct {
 		name string
-		key  tea.KeyType
+		msg  tea.KeyMsg
 	}{
-		{"CtrlC", tea.KeyCtrlC},
-		{"Escape", tea.KeyEsc},
+		{"CtrlC", tea.KeyMsg{Type: tea.KeyCtrlC}},
+		{"Escape", tea.KeyMsg{Type: tea.KeyEsc}},
+		{"q", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := New()
-			msg := tea.KeyMsg{Type: tt.key}
-			_, cmd := m.Update(msg)
+			_, cmd := m.Update(tt.msg)
 			if cmd == nil {
-				t.Error("expected quit command")
+				t.Fatal("expected quit command")
+			}
+			if _, ok := cmd().(tea.QuitMsg); !ok {
+				t.Error("expected the command to quit")
 			}
 		})
 	}
 }
 
+func TestUpdateOtherKeys(t *testing.T) {
+	m := New()
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd != nil {
+		t.Error("expected no command for unhandled keys")
+	}
+}
+
+func TestView_RespectsSize(t *testing.T) {
+	m := New()
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 30, Height: 6})
+	view := newModel.(Model).View()
+
+	lines := strings.Split(view, "\n")
+	if len(lines) > 6 {
+		t.Errorf("expected at most 6 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 30 {
+			t.Errorf("line %q is %d cells wide, expected at most 30", line, w)
+		}
+	}
+}
+
 func TestView(t *testing.T) {
 	m := New()
 	view := m.View()
@@ -77,7 +105,8 @@ func TestView(t *testing.T) {
 	expectedTexts := []string{
 		"{{APP_NAME_TITLE}}",
 		"Welcome to your new Bubbletea TUI app!",
-		"Press Ctrl+C or Esc to quit",
+		"Press q, Ctrl+C or Esc to quit",
+		"Terminal size: 80x24",
 	}
 	for _, text := range expectedTexts {
 		if !containsText(view, text) {
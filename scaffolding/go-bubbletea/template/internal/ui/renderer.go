@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -26,8 +27,10 @@ var (
 			MarginTop(1)
 )
 
-// Render renders the UI
-func Render() string {
+// Render renders the UI to fill a width x height terminal. Content that doesn't fit
+// is cut off rather than wrapping past the bottom of the screen; zero dimensions
+// (before the first WindowSizeMsg) leave the output unconstrained.
+func Render(width, height int) string {
 	var b strings.Builder
 
 	title := titleStyle.Render("{{APP_NAME_TITLE}}")
@@ -38,9 +41,20 @@ func Render() string {
 	b.WriteString(content)
 	b.WriteString("\n")
 
-	help := helpStyle.Render("Press Ctrl+C or Esc to quit")
+	size := contentStyle.Render(fmt.Sprintf("Terminal size: %dx%d", width, height))
+	b.WriteString(size)
+	b.WriteString("\n")
+
+	help := helpStyle.Render("Press q, Ctrl+C or Esc to quit")
 	b.WriteString(help)
 	b.WriteString("\n")
 
-	return b.String()
+	container := lipgloss.NewStyle().Padding(1)
+	if width > 0 {
+		container = container.Width(width).MaxWidth(width)
+	}
+	if height > 0 {
+		container = container.Height(height).MaxHeight(height)
+	}
+	return container.Render(b.String())
 }
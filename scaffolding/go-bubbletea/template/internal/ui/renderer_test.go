@@ -3,10 +3,12 @@ package ui
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestRender(t *testing.T) {
-	output := Render()
+	output := Render(80, 24)
 
 	if output == "" {
 		t.Error("expected non-empty output")
@@ -23,7 +25,7 @@ func TestRender(t *testing.T) {
 }
 
 func TestRender_ContainsTitle(t *testing.T) {
-	output := Render()
+	output := Render(80, 24)
 
 	// The title contains the app name placeholder which will be replaced
 	// during scaffolding, so just check output is structured
@@ -33,9 +35,23 @@ func TestRender_ContainsTitle(t *testing.T) {
 }
 
 func TestRender_ContainsHelpText(t *testing.T) {
-	output := Render()
+	output := Render(80, 24)
 
 	if !strings.Contains(output, "Ctrl+C") && !strings.Contains(output, "Esc") {
 		t.Error("expected output to contain quit instructions with Ctrl+C or Esc")
 	}
 }
+
+func TestRender_FitsSize(t *testing.T) {
+	output := Render(20, 5)
+
+	lines := strings.Split(output, "\n")
+	if len(lines) > 5 {
+		t.Errorf("expected at most 5 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 20 {
+			t.Errorf("line %q is %d cells wide, expected at most 20", line, w)
+		}
+	}
+}
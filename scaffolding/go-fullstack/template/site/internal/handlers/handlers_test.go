@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -33,3 +34,17 @@ func TestPageHandlers_Dashboard_ReturnsOK(t *testing.T) {
 		t.Error("Expected a response code to be set")
 	}
 }
+
+func TestHealthHandler_ReturnsHealthy(t *testing.T) {
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rr := httptest.NewRecorder()
+
+	HealthHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"status":"healthy"`) {
+		t.Errorf("Expected healthy status, got %s", rr.Body.String())
+	}
+}
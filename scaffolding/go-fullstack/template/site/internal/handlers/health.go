@@ -5,6 +5,8 @@ import (
 	"net/http"
 )
 
+// HealthHandler reports that the server is up. It is served at /healthz (and /health)
+// for Cloud Run health checks, so it must not depend on Firestore.
 func HealthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -15,7 +15,8 @@ func NewRouter(fs *firestore.Client, distFS embed.FS) http.Handler {
 	// Static assets
 	mux.Handle("GET /static/", StaticHandler(distFS))
 
-	// Health check for Cloud Run
+	// Health checks for Cloud Run probes and uptime checks
+	mux.HandleFunc("GET /healthz", handlers.HealthHandler)
 	mux.HandleFunc("GET /health", handlers.HealthHandler)
 
 	// Pages (support HTMX partial + full page)
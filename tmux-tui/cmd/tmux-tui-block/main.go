@@ -14,19 +14,24 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/daemon"
 	"github.com/commons-systems/tmux-tui/internal/debug"
+	"github.com/commons-systems/tmux-tui/internal/jsonout"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
+	"github.com/commons-systems/tmux-tui/internal/version"
 )
 
 var (
 	ErrPanePathFailed  = errors.New("failed to get pane current path")
 	ErrGitBranchFailed = errors.New("failed to get current branch")
+	ErrNoTmuxPane      = errors.New("not running in a tmux pane (TMUX_PANE not set)")
 )
 
 // TODO(#328): Consider extracting error hint pattern into shared error handling package
@@ -41,9 +46,130 @@ func printErrorHint(err error) {
 		fmt.Fprintln(os.Stderr, "Hint: Daemon may be slow to respond. Check health with: tmux-tui-daemon health")
 	case errors.Is(err, daemon.ErrConnectionFailed):
 		fmt.Fprintln(os.Stderr, "Hint: Connection issue. Check if tmux-tui-daemon is running.")
+	case errors.Is(err, daemon.ErrNamespaceMismatch):
+		fmt.Fprintln(os.Stderr, "Hint: This pane belongs to a different tmux server than the daemon. Run tmux-tui-block from a pane of the daemon's server, or check $TMUX.")
+	case errors.Is(err, tmux.ErrTmuxNotFound):
+		fmt.Fprintf(os.Stderr, "Hint: tmux is not on PATH. Set %s to the tmux executable.\n", tmux.TmuxBinEnv)
 	}
 }
 
+// Actions reported in blockResult.Action
+const (
+	actionUnblocked       = "unblocked"        // The current branch was blocked and is now unblocked (toggle off)
+	actionPickerRequested = "picker_requested" // The TUI was asked to show the block picker (toggle on)
+	actionUnblockedAll    = "unblocked_all"    // --all cleared every block
+	actionRenamed         = "renamed"          // --rename moved a branch's blocks
+	actionError           = "error"            // The command failed; see blockResult.Code
+)
+
+// blockResult is the --json output: one object on stdout describing what happened.
+type blockResult struct {
+	Action string `json:"action"`
+	// Branch the action applied to: the current branch, or the new name for --rename
+	Branch string `json:"branch"`
+	// BlockedBy lists the branches that were blocking Branch before it was unblocked
+	BlockedBy []string `json:"blockedBy"`
+	// RenamedFrom is the old branch name for --rename
+	RenamedFrom string `json:"renamedFrom,omitempty"`
+	// Code classifies the failure for errors (see errorCode); Error is its message
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// errorCodes maps sentinel errors to the stable codes reported by --json, most specific first.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{daemon.ErrNamespaceMismatch, "namespace_mismatch"},
+	{daemon.ErrConnectionRejected, "connection_rejected"},
+	{daemon.ErrProtocolMismatch, "protocol_mismatch"},
+	{daemon.ErrSocketNotFound, "socket_not_found"},
+	{daemon.ErrPermissionDenied, "permission_denied"},
+	{daemon.ErrConnectionTimeout, "connection_timeout"},
+	{daemon.ErrQueryTimeout, "query_timeout"},
+	{daemon.ErrQueryChannelFull, "query_channel_full"},
+	{daemon.ErrQueryChannelClosed, "query_channel_closed"},
+	{daemon.ErrClientClosed, "client_closed"},
+	{daemon.ErrMessageTooLarge, "message_too_large"},
+	{daemon.ErrConnectionFailed, "connection_failed"},
+	{tmux.ErrTmuxNotFound, "tmux_not_found"},
+	{ErrNoTmuxPane, "no_tmux_pane"},
+}
+
+// errorCode returns the --json error code for err, or "error" if it matches no sentinel.
+func errorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return "error"
+}
+
+// output reports results and failures, as text with hints on stderr or, with --json,
+// as a blockResult on stdout with the hints left out.
+type output struct {
+	jsonOpts *jsonout.Options
+	stdout   io.Writer
+}
+
+// isJSON reports whether results are written as JSON.
+func (o *output) isJSON() bool {
+	return o != nil && o.jsonOpts != nil && o.jsonOpts.Enabled()
+}
+
+// warn prints a warning and the hint for err (which may be nil). JSON output drops warnings:
+// the command carries on and its result says what happened.
+func (o *output) warn(err error, format string, args ...any) {
+	if o.isJSON() {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+	if err != nil {
+		printErrorHint(err)
+	}
+}
+
+// result reports a successful action. text is printed for human-readable output; empty
+// text prints nothing.
+func (o *output) result(r blockResult, text string) {
+	if !o.isJSON() {
+		if text != "" {
+			fmt.Fprintln(o.stdout, text)
+		}
+		return
+	}
+	if r.BlockedBy == nil {
+		r.BlockedBy = []string{}
+	}
+	if err := o.jsonOpts.Write(o.stdout, r); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeError reports err, prefixed by context for human-readable output.
+func (o *output) writeError(branch string, err error, context string) {
+	if !o.isJSON() {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", context, err)
+		printErrorHint(err)
+		return
+	}
+	o.result(blockResult{
+		Action: actionError,
+		Branch: branch,
+		Code:   errorCode(err),
+		Error:  fmt.Sprintf("%s: %v", context, err),
+	}, "")
+}
+
+// fail reports err like writeError and exits with status 1.
+func (o *output) fail(branch string, err error, context string) {
+	o.writeError(branch, err, context)
+	os.Exit(1)
+}
+
 // isRetryableError determines if an error should trigger a retry
 func isRetryableError(err error) bool {
 	return errors.Is(err, daemon.ErrConnectionTimeout) ||
@@ -90,7 +216,7 @@ func queryBlockedStateWithRetry(client branchBlocker, branch string, maxRetries
 func getCurrentBranch(executor tmux.CommandExecutor, paneID string) (string, error) {
 	output, err := executor.ExecCommandOutput("tmux", "display-message", "-p", "-t", paneID, "#{pane_current_path}")
 	if err != nil {
-		return "", fmt.Errorf("%w: %v", ErrPanePathFailed, err)
+		return "", fmt.Errorf("%w: %w", ErrPanePathFailed, err)
 	}
 	path := strings.TrimSpace(string(output))
 
@@ -122,50 +248,121 @@ type branchBlocker interface {
 //   - Second press: Unblock (immediate)
 //   - Third press: Block again (via picker)
 //
-// Returns true if the branch was blocked and successfully unblocked (operation complete).
+// Returns true and the branches it was blocked by if the branch was blocked and
+// successfully unblocked (operation complete).
 // Returns false to show the branch picker in these cases:
 //   - Branch is empty (no current branch detected)
-//   - Query failed (falls back to picker for user selection)
+//   - Query failed (falls back to picker for user selection), except on a namespace
+//     mismatch, which exits
 //   - Branch is not blocked (show picker for blocking it)
-func toggleBlockedState(client branchBlocker, paneID, branch string) bool {
+func toggleBlockedState(client branchBlocker, out *output, paneID, branch string) ([]string, bool) {
 	if branch == "" {
-		return false
+		return nil, false
 	}
 
 	// Query with retry (max 3 attempts)
 	state, err := queryBlockedStateWithRetry(client, branch, 3)
+	if errors.Is(err, daemon.ErrNamespaceMismatch) {
+		// The picker would open on the wrong server's TUI - don't fall back to it
+		out.fail(branch, err, "Failed to query blocked state")
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Could not query blocked state for '%s': %v\n", branch, err)
-		printErrorHint(err)
-		fmt.Fprintln(os.Stderr, "Showing branch picker as fallback.")
+		out.warn(err, "Could not query blocked state for '%s': %v", branch, err)
+		if !out.isJSON() {
+			fmt.Fprintln(os.Stderr, "Showing branch picker as fallback.")
+		}
 		debug.Log("BLOCK_CLI_QUERY_ERROR paneID=%s branch=%s error=%v", paneID, branch, err)
-		return false
+		return nil, false
 	}
 
 	if !state.IsBlocked() {
-		return false // Not blocked, show picker
+		return nil, false // Not blocked, show picker
 	}
 
 	// Branch is blocked - unblock it
 	debug.Log("BLOCK_CLI_UNBLOCK paneID=%s branch=%s blockedBy=%s", paneID, branch, state.BlockedBy())
 	if err := client.UnblockBranch(branch); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to unblock branch: %v\n", err)
-		printErrorHint(err)
-		os.Exit(1)
+		out.fail(branch, err, "Failed to unblock branch")
 	}
 	debug.Log("BLOCK_CLI_UNBLOCK_SUCCESS paneID=%s branch=%s", paneID, branch)
-	return true
+	return state.Blockers(), true
+}
+
+// connectDaemon connects to the daemon, exiting with a hint on failure.
+func connectDaemon(out *output, branch string) *daemon.DaemonClient {
+	client := daemon.NewDaemonClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := client.ConnectWithRetry(ctx, 3); err != nil {
+		out.fail(branch, err, "Failed to connect to daemon")
+	}
+	return client
+}
+
+// unblockAll clears every blocked branch (--all). It doesn't need a tmux pane.
+func unblockAll(out *output) {
+	debug.Log("BLOCK_CLI_UNBLOCK_ALL")
+	client := connectDaemon(out, "")
+	defer client.Close()
+
+	if err := client.UnblockAll(); err != nil {
+		out.fail("", err, "Failed to unblock all branches")
+	}
+	out.result(blockResult{Action: actionUnblockedAll}, "")
+	debug.Log("BLOCK_CLI_UNBLOCK_ALL_SUCCESS")
+}
+
+// renameBranch moves a branch's blocks to its new name after a git branch rename
+// (--rename OLD NEW). Like --all, it doesn't need a tmux pane.
+func renameBranch(out *output, oldBranch, newBranch string) {
+	debug.Log("BLOCK_CLI_RENAME old=%s new=%s", oldBranch, newBranch)
+	client := connectDaemon(out, newBranch)
+	defer client.Close()
+
+	if err := client.RenameBranch(oldBranch, newBranch); err != nil {
+		out.fail(newBranch, err, fmt.Sprintf("Failed to rename '%s' to '%s'", oldBranch, newBranch))
+	}
+	out.result(blockResult{Action: actionRenamed, Branch: newBranch, RenamedFrom: oldBranch},
+		fmt.Sprintf("Moved blocks from '%s' to '%s'", oldBranch, newBranch))
+	debug.Log("BLOCK_CLI_RENAME_SUCCESS old=%s new=%s", oldBranch, newBranch)
 }
 
 func main() {
+	all := flag.Bool("all", false, "Unblock every blocked branch instead of toggling the current one")
+	ttl := flag.Duration("ttl", 0, "Unblock automatically after this long, e.g. 2h (default: never)")
+	rename := flag.Bool("rename", false, "Move blocks from branch OLD to NEW after a git branch rename (usage: --rename OLD NEW)")
+	showVersion := flag.Bool("version", false, "Print the version and exit")
+	out := &output{jsonOpts: jsonout.RegisterFlags(flag.CommandLine), stdout: os.Stdout}
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("tmux-tui-block %s\n", version.String())
+		return
+	}
+	if *ttl < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --ttl must not be negative, got %v\n", *ttl)
+		os.Exit(2)
+	}
+	if *rename {
+		if flag.NArg() != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --rename takes two branch names: --rename OLD NEW")
+			os.Exit(2)
+		}
+		renameBranch(out, flag.Arg(0), flag.Arg(1))
+		return
+	}
+	if *all {
+		unblockAll(out)
+		return
+	}
+
 	// Get current pane ID from environment
 	paneID := os.Getenv("TMUX_PANE")
 	if paneID == "" {
-		fmt.Fprintln(os.Stderr, "Error: Not running in a tmux pane (TMUX_PANE not set)")
-		os.Exit(1)
+		out.fail("", ErrNoTmuxPane, "Cannot toggle the current branch")
 	}
 
-	debug.Log("BLOCK_CLI_START paneID=%s", paneID)
+	debug.Log("BLOCK_CLI_START paneID=%s ttl=%v", paneID, *ttl)
 
 	// Get current branch
 	executor := &tmux.RealCommandExecutor{}
@@ -173,11 +370,11 @@ func main() {
 	if err != nil {
 		// Provide user feedback based on error type
 		if errors.Is(err, ErrPanePathFailed) {
-			fmt.Fprintln(os.Stderr, "Warning: Could not detect pane directory. Showing branch picker.")
+			out.warn(err, "Could not detect pane directory. Showing branch picker.")
 		} else if errors.Is(err, ErrGitBranchFailed) {
-			fmt.Fprintln(os.Stderr, "Warning: Not in a git repository or detached HEAD. Showing branch picker.")
+			out.warn(nil, "Not in a git repository or detached HEAD. Showing branch picker.")
 		} else {
-			fmt.Fprintln(os.Stderr, "Warning: Could not detect current branch. Showing branch picker.")
+			out.warn(nil, "Could not detect current branch. Showing branch picker.")
 		}
 		debug.Log("BLOCK_CLI_NO_BRANCH paneID=%s error=%v", paneID, err)
 		branch = ""
@@ -186,28 +383,21 @@ func main() {
 	}
 
 	// Connect to daemon
-	client := daemon.NewDaemonClient()
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	if err := client.ConnectWithRetry(ctx, 3); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to connect to daemon: %v\n", err)
-		printErrorHint(err)
-		os.Exit(1)
-	}
+	client := connectDaemon(out, branch)
 	defer client.Close()
 
 	// If we have a branch, try to toggle its blocked state
-	if toggleBlockedState(client, paneID, branch) {
+	if blockedBy, unblocked := toggleBlockedState(client, out, paneID, branch); unblocked {
+		out.result(blockResult{Action: actionUnblocked, Branch: branch, BlockedBy: blockedBy}, "")
 		return // Successfully unblocked, we're done
 	}
 
-	// Send request to show block picker (includes internal wait for daemon processing)
-	if err := client.RequestBlockPicker(paneID); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to request block picker: %v\n", err)
-		printErrorHint(err)
-		os.Exit(1)
+	// Send request to show block picker (includes internal wait for daemon processing);
+	// the block picked there expires after the TTL
+	if err := client.RequestBlockPickerWithTTL(paneID, *ttl); err != nil {
+		out.fail(branch, err, "Failed to request block picker")
 	}
+	out.result(blockResult{Action: actionPickerRequested, Branch: branch}, "")
 
 	debug.Log("BLOCK_CLI_SUCCESS paneID=%s", paneID)
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -10,6 +12,8 @@ import (
 	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/daemon"
+	"github.com/commons-systems/tmux-tui/internal/jsonout"
+	"github.com/commons-systems/tmux-tui/internal/tmux"
 	"github.com/commons-systems/tmux-tui/internal/tmux/testutil"
 )
 
@@ -397,6 +401,16 @@ func TestPrintErrorHint_SentinelErrors(t *testing.T) {
 			err:   fmt.Errorf("network error: %w", daemon.ErrConnectionFailed),
 			match: daemon.ErrConnectionFailed,
 		},
+		{
+			name:  "Wrapped sentinel error - ErrNamespaceMismatch",
+			err:   fmt.Errorf("%w: daemon serves /tmp/claude/a, but this shell's $TMUX expects /tmp/claude/b", daemon.ErrNamespaceMismatch),
+			match: daemon.ErrNamespaceMismatch,
+		},
+		{
+			name:  "Pane path failure keeps ErrTmuxNotFound",
+			err:   fmt.Errorf("%w: %w", ErrPanePathFailed, tmux.ErrTmuxNotFound),
+			match: tmux.ErrTmuxNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -408,6 +422,69 @@ func TestPrintErrorHint_SentinelErrors(t *testing.T) {
 	}
 }
 
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{daemon.ErrSocketNotFound, "socket_not_found"},
+		{fmt.Errorf("failed to connect: %w", daemon.ErrPermissionDenied), "permission_denied"},
+		{fmt.Errorf("query failed after 3 retries: %w", daemon.ErrQueryTimeout), "query_timeout"},
+		{fmt.Errorf("%w: daemon serves /tmp/claude/a", daemon.ErrNamespaceMismatch), "namespace_mismatch"},
+		{fmt.Errorf("%w: %w", ErrPanePathFailed, tmux.ErrTmuxNotFound), "tmux_not_found"},
+		{ErrNoTmuxPane, "no_tmux_pane"},
+		{errors.New("'feature' has no blocks to rename"), "error"},
+	}
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestOutput_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	out := &output{jsonOpts: &jsonout.Options{JSON: true}, stdout: &buf}
+
+	out.result(blockResult{Action: actionUnblocked, Branch: "feature", BlockedBy: []string{"main"}}, "ignored")
+	out.result(blockResult{Action: actionPickerRequested, Branch: "feature"}, "ignored")
+	out.writeError("feature", fmt.Errorf("query failed: %w", daemon.ErrQueryTimeout), "Failed to request block picker")
+
+	want := []blockResult{
+		{Action: actionUnblocked, Branch: "feature", BlockedBy: []string{"main"}},
+		{Action: actionPickerRequested, Branch: "feature", BlockedBy: []string{}},
+		{Action: actionError, Branch: "feature", BlockedBy: []string{}, Code: "query_timeout",
+			Error: "Failed to request block picker: query failed: " + daemon.ErrQueryTimeout.Error()},
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Got %d JSON lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, line := range lines {
+		var got blockResult
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("Line %d is not JSON: %v\n%s", i, err, line)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want[i]) {
+			t.Errorf("Line %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if !strings.Contains(lines[1], `"blockedBy":[]`) {
+		t.Errorf("blockedBy should always be an array, got %s", lines[1])
+	}
+}
+
+func TestOutput_Text(t *testing.T) {
+	var buf bytes.Buffer
+	out := &output{stdout: &buf}
+
+	out.result(blockResult{Action: actionRenamed, Branch: "new", RenamedFrom: "old"}, "Moved blocks from 'old' to 'new'")
+	out.result(blockResult{Action: actionPickerRequested}, "")
+	if got := buf.String(); got != "Moved blocks from 'old' to 'new'\n" {
+		t.Errorf("Text output = %q", got)
+	}
+}
+
 // mockDaemonClient implements the daemon client interface for testing
 type mockDaemonClient struct {
 	queryBlockedStateFunc func(branch string) (daemon.BlockedState, error)
@@ -432,7 +509,7 @@ func (m *mockDaemonClient) UnblockBranch(branch string) error {
 
 func TestToggleBlockedState_EmptyBranch(t *testing.T) {
 	// Empty branch should return false (show picker)
-	result := toggleBlockedState(nil, "%1", "")
+	_, result := toggleBlockedState(nil, nil, "%1", "")
 	if result != false {
 		t.Error("Expected toggleBlockedState to return false for empty branch")
 	}
@@ -458,7 +535,7 @@ func TestToggleBlockedState_BranchIsBlocked(t *testing.T) {
 		},
 	}
 
-	result := toggleBlockedState(mock, "%1", "feature-branch")
+	_, result := toggleBlockedState(mock, nil, "%1", "feature-branch")
 
 	if !result {
 		t.Error("Expected toggleBlockedState to return true when branch is blocked")
@@ -483,7 +560,7 @@ func TestToggleBlockedState_BranchNotBlocked(t *testing.T) {
 		},
 	}
 
-	result := toggleBlockedState(mock, "%1", "feature-branch")
+	_, result := toggleBlockedState(mock, nil, "%1", "feature-branch")
 
 	if result {
 		t.Error("Expected toggleBlockedState to return false when branch is not blocked")
@@ -503,7 +580,7 @@ func TestToggleBlockedState_QueryError(t *testing.T) {
 		},
 	}
 
-	result := toggleBlockedState(mock, "%1", "feature-branch")
+	_, result := toggleBlockedState(mock, nil, "%1", "feature-branch")
 
 	if result {
 		t.Error("Expected toggleBlockedState to return false on query error")
@@ -724,7 +801,7 @@ func TestRapidToggle_ConcurrentInvocations(t *testing.T) {
 			defer wg.Done()
 
 			t.Logf("Invocation %d: calling toggleBlockedState", invocationID)
-			result := toggleBlockedState(mock, "%1", "feature-branch")
+			_, result := toggleBlockedState(mock, nil, "%1", "feature-branch")
 
 			if result {
 				// Branch was blocked and got unblocked
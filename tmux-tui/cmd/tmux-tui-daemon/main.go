@@ -2,28 +2,99 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/daemon"
 	"github.com/commons-systems/tmux-tui/internal/debug"
+	"github.com/commons-systems/tmux-tui/internal/jsonout"
 	"github.com/commons-systems/tmux-tui/internal/namespace"
+	"github.com/commons-systems/tmux-tui/internal/version"
 )
 
 func main() {
 	// Check for health subcommand
 	if len(os.Args) > 1 && os.Args[1] == "health" {
-		if err := showHealth(); err != nil {
+		fs := flag.NewFlagSet("health", flag.ExitOnError)
+		jsonOpts := jsonout.RegisterFlags(fs)
+		fs.Parse(os.Args[2:])
+		if err := showHealth(jsonOpts); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to get health status: %v\n", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
 	}
 
+	// Current alerts and blocked branches as JSON, for scripting
+	if len(os.Args) > 1 && os.Args[1] == "list" {
+		fs := flag.NewFlagSet("list", flag.ExitOnError)
+		jsonOpts := jsonout.RegisterFlags(fs) // output is always JSON; --json-pretty indents it
+		fs.Parse(os.Args[2:])
+		if err := listState(os.Stdout, jsonOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list daemon state: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Connected clients, for debugging who is attached to the daemon
+	if len(os.Args) > 1 && os.Args[1] == "clients" {
+		fs := flag.NewFlagSet("clients", flag.ExitOnError)
+		jsonOpts := jsonout.RegisterFlags(fs)
+		fs.Parse(os.Args[2:])
+		if err := listClients(os.Stdout, jsonOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list clients: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Block configuration transfer between namespaces
+	if len(os.Args) > 1 && os.Args[1] == "export-blocks" {
+		if err := exportBlocks(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export blocks: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import-blocks" {
+		fs := flag.NewFlagSet("import-blocks", flag.ExitOnError)
+		replace := fs.Bool("replace", false, "Replace all existing blocks instead of merging")
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "Usage: tmux-tui-daemon import-blocks [--replace] [file]\n\nReads a blocked-branches JSON map (as written by export-blocks) from file or stdin.\n\n")
+			fs.PrintDefaults()
+		}
+		fs.Parse(os.Args[2:])
+		if err := importBlocks(fs.Arg(0), *replace); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to import blocks: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	selfTest := flag.String("self-test", selfTestOff,
+		"Check persistence, socket, watch directory and audio at startup: off, warn (report only) or strict (refuse to start on failures)")
+	showVersion := flag.Bool("version", false, "Print the version and exit")
+	metricsAddr := flag.String("metrics-addr", daemon.MetricsAddrFromEnv(),
+		"Serve Prometheus metrics at http://ADDR/metrics, e.g. 127.0.0.1:9469 (default $"+daemon.MetricsAddrEnv+"; empty disables)")
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("tmux-tui-daemon %s\n", version.String())
+		os.Exit(0)
+	}
+	if err := validateSelfTestMode(*selfTest); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+
 	// Auto-detect namespace from $TMUX environment variable
 	ns := namespace.GetSessionNamespace()
 	debug.Log("DAEMON_MAIN namespace=%s", ns)
@@ -55,23 +126,58 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *selfTest != selfTestOff {
+		report := d.SelfTest()
+		report.Write(os.Stdout)
+		if report.Failed() {
+			if *selfTest == selfTestStrict {
+				fmt.Fprintf(os.Stderr, "Self-test failed, refusing to start (use --self-test=warn to start anyway)\n")
+				d.Stop()
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "WARNING: Self-test failed, starting anyway\n")
+		}
+	}
+
 	if err := d.Start(); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to start daemon: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Handle signals for graceful shutdown
+	// Metrics are optional: a port clash (e.g. a second session's daemon on the same
+	// machine) shouldn't stop the daemon itself
+	var metricsServer *http.Server
+	if *metricsAddr != "" {
+		if metricsServer, err = startMetricsServer(*metricsAddr, d); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: Metrics disabled: %v\n", err)
+		}
+	}
+
+	// Handle signals for graceful shutdown; SIGHUP reloads the blocked-branches file
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	fmt.Printf("Daemon started (namespace: %s)\n", ns)
 	debug.Log("DAEMON_MAIN started namespace=%s", ns)
 
-	// Wait for signal
+	// Wait for a shutdown signal
 	sig := <-sigCh
+	for sig == syscall.SIGHUP {
+		debug.Log("DAEMON_MAIN signal=%v", sig)
+		if err := d.ReloadBlockedBranches(); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		} else {
+			fmt.Println("Reloaded blocked branches")
+		}
+		sig = <-sigCh
+	}
 	debug.Log("DAEMON_MAIN signal=%v", sig)
 	fmt.Printf("Received signal %v, shutting down...\n", sig)
 
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+
 	// Stop daemon
 	if err := d.Stop(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error stopping daemon: %v\n", err)
@@ -82,9 +188,164 @@ func main() {
 	fmt.Println("Daemon stopped")
 }
 
+// startMetricsServer serves d's metrics on addr until the returned server is closed
+func startMetricsServer(addr string, d *daemon.AlertDaemon) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{
+		Handler:           d.MetricsHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "ERROR: Metrics server stopped: %v\n", err)
+		}
+	}()
+	fmt.Printf("Serving metrics at http://%s/metrics\n", listener.Addr())
+	debug.Log("DAEMON_MAIN metrics addr=%s", listener.Addr())
+	return server, nil
+}
+
+// Startup self-test modes for --self-test
+const (
+	selfTestOff    = "off"
+	selfTestWarn   = "warn"
+	selfTestStrict = "strict"
+)
+
+func validateSelfTestMode(mode string) error {
+	switch mode {
+	case selfTestOff, selfTestWarn, selfTestStrict:
+		return nil
+	default:
+		return fmt.Errorf("invalid --self-test=%q (expected %s, %s or %s)", mode, selfTestOff, selfTestWarn, selfTestStrict)
+	}
+}
+
+// listState writes the running daemon's alerts and blocked branches to w as JSON
+func listState(w io.Writer, jsonOpts *jsonout.Options) error {
+	conn, err := net.Dial("unix", namespace.DaemonSocket())
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	state, err := daemon.QueryList(conn)
+	if err != nil {
+		return err
+	}
+	return jsonOpts.Write(w, state)
+}
+
+// listClients writes the running daemon's connected clients to w, as a table or as
+// JSON when requested by jsonOpts
+func listClients(w io.Writer, jsonOpts *jsonout.Options) error {
+	conn, err := net.Dial("unix", namespace.DaemonSocket())
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	clients, err := daemon.QueryClients(conn)
+	if err != nil {
+		return err
+	}
+	if jsonOpts.Enabled() {
+		return jsonOpts.Write(w, clients)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCONNECTED\tLAST SEEN\tLAST PING\tLAST SEQ")
+	for _, c := range clients {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", c.ID,
+			formatClientTime(c.ConnectedAt), formatClientTime(c.LastSeen), formatClientTime(c.LastPing), c.LastSeqSent)
+	}
+	return tw.Flush()
+}
+
+// formatClientTime formats a client timestamp for the clients table ("-" if unset)
+func formatClientTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("15:04:05")
+}
+
+// exportBlocks writes the running daemon's blocked-branches map to w as JSON
+func exportBlocks(w io.Writer) error {
+	conn, err := net.Dial("unix", namespace.DaemonSocket())
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	blocks, err := daemon.ExportBlocks(conn)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(blocks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal blocks: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// importBlocks reads a blocked-branches map from path (stdin if empty or "-") and
+// sends it to the running daemon, which validates, applies, persists and broadcasts it
+func importBlocks(path string, replace bool) error {
+	var data []byte
+	var err error
+	if path == "" || path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read blocks: %w", err)
+	}
+
+	var blocks daemon.BlockMap
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return fmt.Errorf("invalid blocks JSON (expected {\"branch\": [\"blockedByBranch\", ...]}): %w", err)
+	}
+	// Catch self-blocks and cycles in the file itself before contacting the daemon
+	if err := daemon.ValidateBlockGraph(blocks); err != nil {
+		return err
+	}
+
+	mode := daemon.ImportModeMerge
+	if replace {
+		mode = daemon.ImportModeReplace
+	}
+
+	conn, err := net.Dial("unix", namespace.DaemonSocket())
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	result, err := daemon.ImportBlocks(conn, blocks, mode)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d blocks (%s) - %d branches now blocked\n", len(blocks), mode, len(result))
+	return nil
+}
+
+// healthReport is the JSON form of the health command output
+type healthReport struct {
+	Health        daemon.HealthStatus `json:"health"`
+	Assessment    string              `json:"assessment"`
+	ClientVersion string              `json:"client_version"`
+}
+
 // TODO(#281): Add integration tests for health command CLI - see PR review for #273
-// showHealth connects to the daemon and displays health metrics
-func showHealth() error {
+// showHealth connects to the daemon and displays health metrics,
+// as text or as JSON when requested by jsonOpts
+func showHealth(jsonOpts *jsonout.Options) error {
 	socketPath := namespace.DaemonSocket()
 
 	// Connect to daemon socket
@@ -119,6 +380,14 @@ func showHealth() error {
 		return fmt.Errorf("health response missing status data")
 	}
 
+	if jsonOpts.Enabled() {
+		return jsonOpts.Write(os.Stdout, healthReport{
+			Health:        *msg.HealthStatus,
+			Assessment:    assessHealth(*msg.HealthStatus),
+			ClientVersion: version.String(),
+		})
+	}
+
 	// Display health status
 	displayHealthStatus(*msg.HealthStatus)
 	return nil
@@ -128,12 +397,24 @@ func showHealth() error {
 func displayHealthStatus(status daemon.HealthStatus) {
 	fmt.Printf("Daemon Health Status (as of %s)\n", status.GetTimestamp().Format("2006-01-02 15:04:05"))
 	fmt.Println("============================================================")
+	if started := status.GetStartTime(); !started.IsZero() {
+		fmt.Printf("Uptime: %v (started %s)\n", status.GetUptime().Round(time.Second), started.Format("2006-01-02 15:04:05"))
+	}
+	if v := status.GetVersion(); v != "" {
+		fmt.Printf("Version: %s\n", v)
+		if local := version.String(); v != local {
+			fmt.Printf("  This client is %s; restart the daemon to run the same build\n", local)
+		}
+	}
 	fmt.Println()
 
 	// Connections
 	fmt.Println("Connections:")
 	fmt.Printf("  Connected Clients: %d\n", status.GetConnectedClients())
 	fmt.Printf("  Broadcast Failures: %d\n", status.GetBroadcastFailures())
+	fmt.Printf("  Resync Failures: %d\n", status.GetResyncFailures())
+	fmt.Printf("  Broadcast Latency: last %v, avg %v\n",
+		status.GetLastBroadcastDuration().Round(time.Microsecond), status.GetAvgBroadcastDuration().Round(time.Microsecond))
 	if status.GetLastBroadcastError() != "" {
 		fmt.Printf("  Last Broadcast Error: %s\n", status.GetLastBroadcastError())
 	}
@@ -175,6 +456,9 @@ func assessHealth(status daemon.HealthStatus) string {
 	if status.GetConnectedClients() == 0 {
 		warnings = append(warnings, "No connected clients")
 	}
+	if v := status.GetVersion(); v != "" && v != version.String() {
+		warnings = append(warnings, "Daemon version differs from client")
+	}
 
 	if len(warnings) == 0 {
 		return "healthy"
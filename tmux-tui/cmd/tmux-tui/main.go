@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"sort"
@@ -16,6 +17,8 @@ import (
 	"github.com/commons-systems/tmux-tui/internal/namespace"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
 	"github.com/commons-systems/tmux-tui/internal/ui"
+	"github.com/commons-systems/tmux-tui/internal/version"
+	"github.com/commons-systems/tmux-tui/internal/watcher"
 )
 
 type timeTickMsg time.Time
@@ -24,19 +27,30 @@ type daemonEventMsg struct {
 	msg daemon.Message
 }
 
+// daemonReconnectedMsg reports the outcome of reconnectDaemonCmd
+type daemonReconnectedMsg struct {
+	err error // nil = reconnected; otherwise retries stopped for good
+}
+
 type model struct {
 	renderer     *ui.TreeRenderer
 	daemonClient *daemon.DaemonClient
 	tree         tmux.RepoTree
 
 	// Alert state with concurrency protection
-	alerts   map[string]string
-	alertsMu *sync.RWMutex
+	alerts     map[string]string
+	alertTimes map[string]time.Time // When each alert started, as reported by the daemon
+	alertsMu   *sync.RWMutex
 
 	// Blocked branch state with concurrency protection
-	blockedBranches map[string]string
+	blockedBranches daemon.BlockMap
+	blockExpiries   daemon.BlockExpiries // When expiring blocks end, as reported by the daemon
 	blockedMu       *sync.RWMutex
 
+	// Globally focused pane, seeded from full_state on connect and kept current by
+	// pane_focus. Reapplied to every tree_update so the highlight survives daemon restarts.
+	activePaneID string
+
 	// Error state with concurrency protection
 	// Six distinct error paths determine application behavior:
 	// 1. err != nil: Fatal error - displays message and exits immediately
@@ -45,40 +59,68 @@ type model struct {
 	// 4. persistenceError != "": Daemon persistence failure - displays warning banner
 	// 5. audioError != "": Audio playback failure - displays warning banner
 	// 6. treeRefreshError != nil: Tmux tree refresh failure - displays warning banner
+	// 7. treeWarning != "": Some panes couldn't be read - displays notice banner over the partial tree
 	err                   error
 	alertsDisabled        bool
 	alertError            string
 	persistenceError      string
 	audioError            string
 	treeRefreshError      error         // NEW: tree refresh failure tracking
+	treeWarning           string        // Why the last tree_update is missing panes ("" if complete)
 	consecutiveNilUpdates int           // Circuit breaker for malformed tree updates
 	errorMu               *sync.RWMutex // NEW: protects all error fields
 
 	// UI state
-	width  int
-	height int
+	keys         ui.KeyMap
+	theme        ui.Theme
+	width        int
+	height       int
+	reconnecting bool // Lost the daemon connection; reconnectDaemonCmd is retrying
 
 	// Branch picker state
-	pickingBranch    bool
-	pickingForBranch string
-	branchPicker     *ui.BranchPicker
+	pickingBranch     bool
+	pickingForBranch  string
+	pickingTTL        time.Duration // TTL requested with the picker (tmux-tui-block --ttl), 0 = never expires
+	branchPicker      *ui.BranchPicker
+	protectedBranches []string // Never offered for blocking; mirrors the daemon's protected set
+	blockRejection    string   // Last block_rejected reason, cleared by the next block_change
 }
 
 func initialModel() model {
 	// Initialize model with mutexes first to ensure safe concurrent access
 	m := model{
 		alerts:          make(map[string]string),
+		alertTimes:      make(map[string]time.Time),
 		alertsMu:        &sync.RWMutex{},
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(daemon.BlockMap),
+		blockExpiries:   make(daemon.BlockExpiries),
 		blockedMu:       &sync.RWMutex{},
 		errorMu:         &sync.RWMutex{},
 		width:           80,
 		height:          24,
 		pickingBranch:   false,
 		branchPicker:    ui.NewBranchPicker([]string{}, 80, 24),
+
+		protectedBranches: daemon.ProtectedBranchesFromEnv(),
+	}
+
+	keys, err := ui.LoadKeyMap(namespace.KeyBindingsFile())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: %v - using default key bindings\n", err)
+	}
+	m.keys = keys
+
+	theme, err := ui.LoadTheme(namespace.ThemeFile())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: %v - using default colors\n", err)
 	}
+	theme.NoColor = ui.NoColorFromEnv()
+	m.theme = theme
+	m.branchPicker.SetTheme(theme)
 
 	renderer := ui.NewTreeRenderer(80) // Default width
+	renderer.SetSeverities(watcher.SeveritiesFromEnv())
+	renderer.SetTheme(theme)
 	m.renderer = renderer
 
 	// Initialize empty tree - will be populated by daemon's tree_update broadcast
@@ -95,7 +137,7 @@ func initialModel() model {
 	// Use background context with a reasonable timeout for initialization
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 
-	err := daemonClient.ConnectWithRetry(ctx, 5)
+	err = daemonClient.ConnectWithRetry(ctx, 5)
 	cancel() // Cancel context immediately after connection attempt completes
 
 	if err != nil {
@@ -157,18 +199,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		// Handle picker navigation if active
 		if m.pickingBranch {
-			switch msg.String() {
-			case "up", "k":
+			// Typed characters filter the list, so navigation keys can't be printable
+			switch msg.Type {
+			case tea.KeyRunes:
+				m.branchPicker.Filter(m.branchPicker.Query() + string(msg.Runes))
+				return m, nil
+			case tea.KeyBackspace:
+				if query := []rune(m.branchPicker.Query()); len(query) > 0 {
+					m.branchPicker.Filter(string(query[:len(query)-1]))
+				}
+				return m, nil
+			}
+			action, _ := m.keys.PickerAction(msg.String())
+			switch action {
+			case ui.ActionPickerUp:
 				m.branchPicker.MoveUp()
 				return m, nil
-			case "down", "j":
+			case ui.ActionPickerDown:
 				m.branchPicker.MoveDown()
 				return m, nil
-			case "enter":
+			case ui.ActionPickerSelect:
 				// Confirm selection - send block request for branch
 				selectedBranch := m.branchPicker.Selected()
+				if selectedBranch == "" && m.branchPicker.Query() != "" {
+					// Nothing matches the filter - keep the picker open to edit it
+					return m, nil
+				}
 				if selectedBranch != "" && m.daemonClient != nil && m.pickingForBranch != "" {
-					if err := m.daemonClient.BlockBranch(m.pickingForBranch, selectedBranch); err != nil {
+					if err := m.daemonClient.BlockBranchWithTTL(m.pickingForBranch, selectedBranch, m.pickingTTL); err != nil {
 						errMsg := fmt.Sprintf("Failed to block branch '%s' with '%s': %v\nBlock was not applied.", m.pickingForBranch, selectedBranch, err)
 						fmt.Fprintf(os.Stderr, "ERROR: %s\n", errMsg)
 
@@ -185,21 +243,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.pickingBranch = false
 				m.pickingForBranch = ""
+				m.pickingTTL = 0
 				return m, nil
-			case "esc":
+			case ui.ActionPickerCancel:
 				// Cancel picker
 				m.pickingBranch = false
 				m.pickingForBranch = ""
+				m.pickingTTL = 0
 				return m, nil
 			}
 			return m, nil
 		}
 
 		// Normal key handling when picker is not active
-		switch msg.Type {
-		case tea.KeyCtrlC:
+		if action, _ := m.keys.TreeAction(msg.String()); action == ui.ActionQuit {
 			// Clean up daemon client on quit
-			closeDaemonClient(m.daemonClient, "Ctrl+C")
+			closeDaemonClient(m.daemonClient, "quit")
 			return m, tea.Quit
 		}
 
@@ -214,23 +273,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.alerts = make(map[string]string)
 			}
+			if msg.msg.AlertTimes != nil {
+				m.alertTimes = msg.msg.AlertTimes
+			} else {
+				m.alertTimes = make(map[string]time.Time)
+			}
 			m.alertsMu.Unlock()
 
 			m.blockedMu.Lock()
 			if msg.msg.BlockedBranches != nil {
 				m.blockedBranches = msg.msg.BlockedBranches
 			} else {
-				m.blockedBranches = make(map[string]string)
+				m.blockedBranches = make(daemon.BlockMap)
 			}
+			m.blockExpiries = msg.msg.BlockExpiries.Clone()
 			m.blockedMu.Unlock()
 
-			debug.Log("TUI_DAEMON_STATE alerts=%d blocked=%d", len(msg.msg.Alerts), len(msg.msg.BlockedBranches))
+			m.updateActivePane(msg.msg.ActivePaneID)
+
+			debug.Log("TUI_DAEMON_STATE alerts=%d blocked=%d active_pane=%s",
+				len(msg.msg.Alerts), len(msg.msg.BlockedBranches), msg.msg.ActivePaneID)
 			// Continue watching daemon (tree updates come via tree_update messages)
 			return m, m.continueWatchingDaemon()
 
 		case daemon.MsgTypePaneFocus:
-			// Pane focus changed - logged for debugging, tree state comes via tree_update
+			// Pane focus changed - the daemon follows up with a tree_update carrying the
+			// same highlight; recording it here keeps later tree_updates consistent
 			debug.Log("TUI_PANE_FOCUS paneID=%s", msg.msg.ActivePaneID)
+			m.updateActivePane(msg.msg.ActivePaneID)
 			return m, m.continueWatchingDaemon()
 
 		case daemon.MsgTypeAlertChange:
@@ -242,9 +312,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.msg.Created && msg.msg.EventType != "working" {
 				// Alert state (idle, stop, permission, elicitation) - store it
 				m.alerts[msg.msg.PaneID] = msg.msg.EventType
+				if !msg.msg.AlertedAt.IsZero() {
+					m.alertTimes[msg.msg.PaneID] = msg.msg.AlertedAt
+				}
 			} else {
 				// Either file deleted OR "working" state - remove alert
 				delete(m.alerts, msg.msg.PaneID)
+				delete(m.alertTimes, msg.msg.PaneID)
 			}
 			m.alertsMu.Unlock()
 
@@ -253,7 +327,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case daemon.MsgTypeShowBlockPicker:
 			// Show branch picker for specified pane (or toggle off if already blocked)
-			debug.Log("TUI_SHOW_PICKER paneID=%s", msg.msg.PaneID)
+			debug.Log("TUI_SHOW_PICKER paneID=%s ttl=%v", msg.msg.PaneID, msg.msg.TTL)
 
 			// TODO(#1195): Extract to helper method like FindBranchForPane(paneID string) (string, bool)
 			// Find which branch this pane is on
@@ -304,8 +378,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.continueWatchingDaemon()
 			}
 
+			// Protected branches are not blockable - don't offer the picker at all
+			if daemon.IsProtectedBranch(currentBranch, m.protectedBranches) {
+				debug.Log("TUI_PICKER_PROTECTED branch=%s", currentBranch)
+				m.errorMu.Lock()
+				m.blockRejection = fmt.Sprintf("protected branch: '%s' cannot be blocked", currentBranch)
+				m.errorMu.Unlock()
+				return m, m.continueWatchingDaemon()
+			}
+
 			// Branch is not blocked - show picker to block it
 			m.pickingForBranch = currentBranch
+			m.pickingTTL = msg.msg.TTL
 
 			// Extract all unique branches from tree (excluding current branch)
 			branchSet := make(map[string]bool)
@@ -324,6 +408,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Sort branches alphabetically for consistent display
 			sort.Strings(branches)
 
+			// Mark blocked branches and leave out those that would form a block cycle
+			m.blockedMu.RLock()
+			blocked := m.blockedBranches.Clone()
+			m.blockedMu.RUnlock()
+
+			m.branchPicker.Filter("")
+			m.branchPicker.SetBlockedBranches(currentBranch, blocked)
 			m.branchPicker.SetBranches(branches)
 			m.pickingBranch = true
 
@@ -332,26 +423,53 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case daemon.MsgTypeBlockChange:
 			// Block state changed for a branch
-			debug.Log("TUI_BLOCK_CHANGE branch=%s blockedBy=%s blocked=%v",
-				msg.msg.Branch, msg.msg.BlockedBranch, msg.msg.Blocked)
+			debug.Log("TUI_BLOCK_CHANGE branch=%s blockedBy=%v blocked=%v",
+				msg.msg.Branch, msg.msg.Blockers, msg.msg.Blocked)
+
+			blockers := msg.msg.Blockers
+			if len(blockers) == 0 && msg.msg.BlockedBranch != "" {
+				blockers = []string{msg.msg.BlockedBranch} // daemon predates Blockers
+			}
 
 			m.blockedMu.Lock()
 			if msg.msg.Blocked {
-				m.blockedBranches[msg.msg.Branch] = msg.msg.BlockedBranch
+				m.blockedBranches[msg.msg.Branch] = blockers
 			} else {
 				delete(m.blockedBranches, msg.msg.Branch)
 			}
+			if expiries := msg.msg.BlockExpiries[msg.msg.Branch]; len(expiries) > 0 {
+				m.blockExpiries[msg.msg.Branch] = expiries
+			} else {
+				delete(m.blockExpiries, msg.msg.Branch)
+			}
 			m.blockedMu.Unlock()
 
+			m.errorMu.Lock()
+			m.blockRejection = ""
+			m.errorMu.Unlock()
+
 			// Close picker in all TUI windows when a block is confirmed
 			if m.pickingBranch {
 				m.pickingBranch = false
 				m.pickingForBranch = ""
+				m.pickingTTL = 0
 			}
 
 			// Continue watching daemon
 			return m, m.continueWatchingDaemon()
 
+		case daemon.MsgTypeBlockRejected:
+			// Daemon refused our block request (e.g. protected branch, or a block cycle
+			// whose path the reason spells out) - state is unchanged
+			debug.Log("TUI_BLOCK_REJECTED branch=%s blockedBy=%s reason=%s cycle=%v",
+				msg.msg.Branch, msg.msg.BlockedBranch, msg.msg.Error, msg.msg.CyclePath)
+			m.errorMu.Lock()
+			m.blockRejection = msg.msg.Error
+			m.errorMu.Unlock()
+
+			// Continue watching daemon
+			return m, m.continueWatchingDaemon()
+
 		case daemon.MsgTypePersistenceError:
 			// Persistence error from daemon
 			debug.Log("TUI_PERSISTENCE_ERROR error=%s", msg.msg.Error)
@@ -429,6 +547,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.consecutiveNilUpdates = 0
 
 			m.tree = *msg.msg.Tree
+			m.applyActivePane()
 			// Reconcile alerts with lock held to prevent race with fast path
 			m.alertsMu.Lock()
 			alertsBefore := len(m.alerts)
@@ -440,6 +559,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			m.alerts = reconcileAlerts(m.tree, m.alerts)
+			// Keep start times only for surviving alerts so their ages carry on
+			for paneID := range m.alertTimes {
+				if _, ok := m.alerts[paneID]; !ok {
+					delete(m.alertTimes, paneID)
+				}
+			}
 			alertsAfter := len(m.alerts)
 			removed := alertsBefore - alertsAfter
 
@@ -459,10 +584,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.alertsMu.Unlock()
 
-			// Clear any previous tree refresh error
+			// Clear any previous tree refresh error; a partial tree's warning lasts until the next update
 			m.errorMu.Lock()
 			m.treeRefreshError = nil
+			m.treeWarning = msg.msg.Error
 			m.errorMu.Unlock()
+			if msg.msg.Error != "" {
+				debug.Log("TUI_TREE_UPDATE_PARTIAL warning=%q", msg.msg.Error)
+			}
 
 			return m, m.continueWatchingDaemon()
 
@@ -496,21 +625,71 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Continue watching daemon
 			return m, m.continueWatchingDaemon()
 
-		case "disconnect":
-			// Daemon disconnected
-			debug.Log("TUI_DAEMON_DISCONNECT")
-			fmt.Fprintf(os.Stderr, "Disconnected from daemon\n")
+		case daemon.MsgTypeRejected:
+			// Daemon refused the connection (e.g. client limit) and closed it. Reconnecting
+			// would just be refused again, so stop and show why.
+			debug.Log("TUI_DAEMON_REJECTED reason=%s", msg.msg.Error)
+			fmt.Fprintf(os.Stderr, "Daemon rejected connection: %s\n", msg.msg.Error)
+			m.errorMu.Lock()
+			m.alertsDisabled = true
+			m.alertError = "Daemon rejected connection: " + msg.msg.Error
+			m.errorMu.Unlock()
+			m.reconnecting = false
+			m.daemonClient = nil
+			return m, nil
+
+		case daemon.MsgTypeVersionMismatch:
+			// Daemon runs an incompatible protocol version; as with a rejection, retrying
+			// can't help until one side is updated
+			debug.Log("TUI_DAEMON_VERSION_MISMATCH daemon_protocol=%s", msg.msg.ProtocolVersion)
+			reason := fmt.Sprintf("Daemon speaks protocol %s, this TUI %s - update tmux-tui and restart the daemon",
+				msg.msg.ProtocolVersion, daemon.ProtocolVersion)
+			fmt.Fprintln(os.Stderr, reason)
 			m.errorMu.Lock()
 			m.alertsDisabled = true
-			m.alertError = "Disconnected from daemon"
+			m.alertError = reason
 			m.errorMu.Unlock()
+			m.reconnecting = false
 			m.daemonClient = nil
 			return m, nil
+
+		case "disconnect":
+			// Daemon disconnected (e.g. restarted) - reconnect in the background and keep
+			// watching, since events from the new connection arrive on the same channel
+			if m.daemonClient == nil {
+				return m, nil
+			}
+			if m.reconnecting {
+				// Receive and heartbeat can both report the same failure
+				return m, m.continueWatchingDaemon()
+			}
+			debug.Log("TUI_DAEMON_DISCONNECT error=%s", msg.msg.Error)
+			fmt.Fprintf(os.Stderr, "Disconnected from daemon, reconnecting...\n")
+			m.reconnecting = true
+			return m, tea.Batch(reconnectDaemonCmd(m.daemonClient), m.continueWatchingDaemon())
 		}
 
 		// Continue watching
 		return m, m.continueWatchingDaemon()
 
+	case daemonReconnectedMsg:
+		m.reconnecting = false
+		if msg.err != nil {
+			// Client closed or retries cancelled - give up on the daemon
+			debug.Log("TUI_DAEMON_RECONNECT_FAILED error=%v", msg.err)
+			fmt.Fprintf(os.Stderr, "Failed to reconnect to daemon: %v\n", msg.err)
+			m.errorMu.Lock()
+			m.alertsDisabled = true
+			m.alertError = fmt.Sprintf("Disconnected from daemon: %v", msg.err)
+			m.errorMu.Unlock()
+			m.daemonClient = nil
+			return m, nil
+		}
+		// The daemon sends full_state for the new connection, which resyncs alerts and blocks
+		debug.Log("TUI_DAEMON_RECONNECTED")
+		fmt.Fprintf(os.Stderr, "Reconnected to daemon\n")
+		return m, nil
+
 	case timeTickMsg:
 		// Time tick for header update (1s)
 		return m, timeTickCmd()
@@ -519,11 +698,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// warningStyle creates a lipgloss style for warning banners with the specified background color
-func warningStyle(bgColor string) lipgloss.Style {
+// warningStyle creates a lipgloss style for warning banners with the specified theme background color
+func warningStyle(theme ui.Theme, bgColor string) lipgloss.Style {
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("0")).
-		Background(lipgloss.Color(bgColor)).
+		Foreground(theme.Color("0")).
+		Background(theme.Color(bgColor)).
 		Bold(true).
 		Padding(0, 1)
 }
@@ -537,6 +716,8 @@ func (m model) View() string {
 	alertsDisabled := m.alertsDisabled
 	alertErr := m.alertError
 	treeRefreshErr := m.treeRefreshError
+	treeWarning := m.treeWarning
+	blockRejection := m.blockRejection
 	m.errorMu.RUnlock()
 
 	if criticalErr != nil {
@@ -547,17 +728,23 @@ func (m model) View() string {
 		return "Loading..."
 	}
 
-	// Build warning banners (priority: persistence > audio > tree refresh > alerts)
+	// Build warning banners (priority: persistence > audio > tree refresh > reconnecting > alerts > block rejection > partial tree)
 	var warningBanner string
 
 	if persistenceErr != "" {
-		warningBanner = warningStyle("1").Render("⚠ PERSISTENCE ERROR: "+persistenceErr+" (changes won't survive restart)") + "\n\n"
+		warningBanner = warningStyle(m.theme, m.theme.Error).Render("⚠ PERSISTENCE ERROR: "+persistenceErr+" (changes won't survive restart)") + "\n\n"
 	} else if audioErr != "" {
-		warningBanner = warningStyle("3").Render("⚠ AUDIO ERROR: "+audioErr+" (notifications may not work)") + "\n\n"
+		warningBanner = warningStyle(m.theme, m.theme.Warning).Render("⚠ AUDIO ERROR: "+audioErr+" (notifications may not work)") + "\n\n"
 	} else if treeRefreshErr != nil {
-		warningBanner = warningStyle("3").Render(fmt.Sprintf("⚠ TREE REFRESH FAILED: %v (showing stale data, will retry)", treeRefreshErr)) + "\n\n"
+		warningBanner = warningStyle(m.theme, m.theme.Warning).Render(fmt.Sprintf("⚠ TREE REFRESH FAILED: %v (showing stale data, will retry)", treeRefreshErr)) + "\n\n"
+	} else if m.reconnecting {
+		warningBanner = warningStyle(m.theme, m.theme.Warning).Render("⟳ DAEMON DISCONNECTED: reconnecting… (alerts paused)") + "\n\n"
 	} else if alertsDisabled {
-		warningBanner = warningStyle("3").Render("⚠ ALERT NOTIFICATIONS DISABLED: "+alertErr) + "\n\n"
+		warningBanner = warningStyle(m.theme, m.theme.Warning).Render("⚠ ALERT NOTIFICATIONS DISABLED: "+alertErr) + "\n\n"
+	} else if blockRejection != "" {
+		warningBanner = warningStyle(m.theme, m.theme.Warning).Render("⚠ BLOCK REJECTED: "+blockRejection) + "\n\n"
+	} else if treeWarning != "" {
+		warningBanner = warningStyle(m.theme, m.theme.Info).Render("ⓘ TREE INCOMPLETE: "+treeWarning) + "\n\n"
 	}
 
 	// Render header
@@ -570,19 +757,23 @@ func (m model) View() string {
 	for k, v := range m.alerts {
 		alertsCopy[k] = v
 	}
+	alertTimesCopy := make(map[string]time.Time, len(m.alertTimes))
+	for k, v := range m.alertTimes {
+		alertTimesCopy[k] = v
+	}
 	m.alertsMu.RUnlock()
 
 	m.blockedMu.RLock()
-	blockedCopy := make(map[string]string)
-	for k, v := range m.blockedBranches {
-		blockedCopy[k] = v
-	}
+	blockedCopy := m.blockedBranches.Clone()
+	expiriesCopy := m.blockExpiries.Clone()
 	m.blockedMu.RUnlock()
 
 	if len(blockedCopy) > 0 {
 		debug.Log("TUI_VIEW_RENDER blockedBranches=%v", blockedCopy)
 	}
 
+	m.renderer.SetAlertTimes(alertTimesCopy)
+	m.renderer.SetBlockExpiries(expiriesCopy)
 	output := m.renderer.Render(m.tree, alertsCopy, blockedCopy)
 
 	// If picker is active, overlay it centered on screen
@@ -616,6 +807,60 @@ func watchDaemonCmd(client *daemon.DaemonClient) tea.Cmd {
 	}
 }
 
+// reconnectDaemonCmd reconnects client after a disconnect, retrying until it succeeds
+// or the client is closed (quit), and reports the outcome as a daemonReconnectedMsg.
+func reconnectDaemonCmd(client *daemon.DaemonClient) tea.Cmd {
+	return func() tea.Msg {
+		return daemonReconnectedMsg{err: client.Reconnect(context.Background())}
+	}
+}
+
+// updateActivePane records the globally focused pane and highlights it in the tree.
+// Empty IDs are ignored so a full_state from a daemon that has not yet seen a focus
+// event does not clear the known focus.
+func (m *model) updateActivePane(paneID string) {
+	if paneID == "" {
+		return
+	}
+	m.activePaneID = paneID
+	m.applyActivePane()
+}
+
+// applyActivePane highlights activePaneID in the tree and deactivates every other pane,
+// mirroring the daemon's pane-focus handling (only one pane is highlighted globally).
+// Does nothing if the pane is not in the tree yet; it is applied on the next tree_update.
+func (m *model) applyActivePane() {
+	if m.activePaneID == "" {
+		return
+	}
+	target, _, _, found := m.tree.FindPaneByID(m.activePaneID)
+	if !found {
+		debug.Log("TUI_ACTIVE_PANE_NOTFOUND paneID=%s", m.activePaneID)
+		return
+	}
+
+	// Work on a clone - the tree may be shared with the daemon message
+	tree := m.tree.Clone()
+	for _, repo := range tree.Repos() {
+		for _, branch := range tree.Branches(repo) {
+			panes, ok := tree.GetPanes(repo, branch)
+			if !ok {
+				continue
+			}
+			updated := make([]tmux.Pane, len(panes))
+			for i, p := range panes {
+				updated[i] = p.WithWindowActive(p.ID() == m.activePaneID)
+			}
+			if err := tree.SetPanes(repo, branch, updated); err != nil {
+				debug.Log("TUI_ACTIVE_PANE_SETPANES_ERROR repo=%s branch=%s error=%v", repo, branch, err)
+				return
+			}
+		}
+	}
+	m.tree = tree
+	debug.Log("TUI_ACTIVE_PANE_APPLIED paneID=%s title=%s", target.ID(), target.Title())
+}
+
 // reconcileAlerts removes alerts for panes that no longer exist.
 // It modifies the alerts map in-place and returns the same map.
 func reconcileAlerts(tree tmux.RepoTree, alerts map[string]string) map[string]string {
@@ -665,7 +910,7 @@ func timeTickCmd() tea.Cmd {
 }
 
 // closeDaemonClient closes a daemon client connection with consistent error handling
-// context describes the operation that triggered the close (e.g., "Ctrl+C", "circuit breaker")
+// context describes the operation that triggered the close (e.g., "quit", "circuit breaker")
 func closeDaemonClient(client *daemon.DaemonClient, context string) {
 	if client == nil {
 		return
@@ -676,7 +921,7 @@ func closeDaemonClient(client *daemon.DaemonClient, context string) {
 			fmt.Fprintf(os.Stderr, "Note: Failed to cleanly close daemon connection: %v\n", err)
 			if context == "circuit breaker" {
 				fmt.Fprintf(os.Stderr, "      Circuit breaker activated. Daemon may have stale client state.\n")
-			} else if context == "Ctrl+C" {
+			} else if context == "quit" {
 				fmt.Fprintf(os.Stderr, "      Application will exit normally. If you see connection issues on restart, run: pkill tmux-tui-daemon\n")
 			}
 		}
@@ -723,6 +968,13 @@ func (m model) GetAlertsForTesting() map[string]string {
 }
 
 func main() {
+	showVersion := flag.Bool("version", false, "Print the version and exit")
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("tmux-tui %s\n", version.String())
+		return
+	}
+
 	p := tea.NewProgram(initialModel())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running tmux-tui: %v\n", err)
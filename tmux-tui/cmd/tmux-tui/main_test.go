@@ -5,9 +5,12 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/commons-systems/tmux-tui/internal/daemon"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
+	"github.com/commons-systems/tmux-tui/internal/ui"
 )
 
 // TODO(#1212): Extract testPane and testTree helpers to shared testing package
@@ -128,6 +131,67 @@ func TestTreeRefreshErrorClearing(t *testing.T) {
 	}
 }
 
+func TestTreeUpdate_PartialTreeWarning(t *testing.T) {
+	m := initialModel()
+	m.alertsDisabled = false // No daemon in tests; keep that banner out of the way
+
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {
+			"main": {
+				testPane("%1", "@1", 0, true),
+			},
+		},
+	})
+	partial := daemonEventMsg{msg: daemon.Message{
+		Type:  daemon.MsgTypeTreeUpdate,
+		Tree:  &tree,
+		Error: "1 of 2 panes could not be read",
+	}}
+
+	updatedModel, _ := m.Update(partial)
+	m = updatedModel.(model)
+	if got := m.treeWarning; got != "1 of 2 panes could not be read" {
+		t.Errorf("treeWarning = %q, want the daemon's warning", got)
+	}
+	if view := m.View(); !strings.Contains(view, "TREE INCOMPLETE") || !strings.Contains(view, "test-repo") {
+		t.Errorf("View should show the partial tree with a notice, got:\n%s", view)
+	}
+
+	// The next complete tree clears the notice
+	complete := daemonEventMsg{msg: daemon.Message{Type: daemon.MsgTypeTreeUpdate, Tree: &tree}}
+	updatedModel, _ = m.Update(complete)
+	m = updatedModel.(model)
+	if m.treeWarning != "" {
+		t.Errorf("treeWarning = %q after a complete tree, want empty", m.treeWarning)
+	}
+	if strings.Contains(m.View(), "TREE INCOMPLETE") {
+		t.Error("View should drop the notice after a complete tree")
+	}
+}
+
+func TestBlockRejected_ShowsCycle(t *testing.T) {
+	m := initialModel()
+	m.alertsDisabled = false // No daemon in tests; keep that banner out of the way
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {"api": {testPane("%1", "@1", 0, true)}},
+	})
+	updatedModel, _ := m.Update(daemonEventMsg{msg: daemon.Message{Type: daemon.MsgTypeTreeUpdate, Tree: &tree}})
+	m = updatedModel.(model)
+
+	rejected := daemonEventMsg{msg: daemon.Message{
+		Type:          daemon.MsgTypeBlockRejected,
+		Branch:        "api",
+		BlockedBranch: "ui",
+		CyclePath:     []string{"api", "ui", "api"},
+		Error:         "block cycle: 'api' cannot be blocked by 'ui' (api -> ui -> api)",
+	}}
+	updatedModel, _ = m.Update(rejected)
+	m = updatedModel.(model)
+	if view := m.View(); !strings.Contains(view, "BLOCK REJECTED") || !strings.Contains(view, "api -> ui -> api") {
+		t.Errorf("View should show the rejected cycle, got:\n%s", view)
+	}
+}
+
 func TestViewErrorStateSnapshot(t *testing.T) {
 	m := initialModel()
 	// Ensure tree is initialized
@@ -952,6 +1016,49 @@ func TestTreeUpdate_EmptyTreeValid(t *testing.T) {
 	}
 }
 
+// TestFullState_ActivePaneAppliedToTreeUpdate verifies the focused pane from full_state
+// is highlighted once the tree arrives, and every other pane is deactivated.
+func TestFullState_ActivePaneAppliedToTreeUpdate(t *testing.T) {
+	m := initialModel()
+
+	updatedModel, _ := m.Update(daemonEventMsg{
+		msg: daemon.Message{Type: daemon.MsgTypeFullState, ActivePaneID: "%2"},
+	})
+	m = updatedModel.(model)
+
+	if m.activePaneID != "%2" {
+		t.Fatalf("Expected activePaneID %%2 after full_state, got %q", m.activePaneID)
+	}
+
+	// Freshly collected tree marks each session's active pane, not the focused one
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"repo1": {"main": {testPane("%1", "@1", 0, true)}},
+		"repo2": {"feature": {testPane("%2", "@2", 0, false)}},
+	})
+	updatedModel, _ = m.Update(daemonEventMsg{
+		msg: daemon.Message{Type: daemon.MsgTypeTreeUpdate, Tree: &tree},
+	})
+	m = updatedModel.(model)
+
+	for _, tc := range []struct {
+		id         string
+		wantActive bool
+	}{{"%1", false}, {"%2", true}} {
+		pane, _, _, found := m.tree.FindPaneByID(tc.id)
+		if !found {
+			t.Fatalf("Pane %s missing from tree", tc.id)
+		}
+		if pane.WindowActive() != tc.wantActive {
+			t.Errorf("Pane %s WindowActive() = %v, want %v", tc.id, pane.WindowActive(), tc.wantActive)
+		}
+	}
+
+	// The message's tree must not be mutated
+	if pane, _, _, _ := tree.FindPaneByID("%1"); !pane.WindowActive() {
+		t.Error("applyActivePane mutated the tree from the daemon message")
+	}
+}
+
 // TestTreeUpdate_CircuitBreakerTriggersAtThreshold verifies that the circuit breaker
 // disconnects from daemon after exactly 3 consecutive nil tree updates.
 // This is a critical protection mechanism against runaway daemon bugs.
@@ -1243,6 +1350,51 @@ func TestTreeUpdate_AlertReconciliationLogic(t *testing.T) {
 	}
 }
 
+// TestTreeUpdate_ReconcilePreservesAlertTimes verifies alert start times from the daemon
+// survive tree reconciliation for remaining alerts and are dropped with removed ones.
+func TestTreeUpdate_ReconcilePreservesAlertTimes(t *testing.T) {
+	m := initialModel()
+	alertedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	fullState := daemonEventMsg{msg: daemon.Message{
+		Type:       daemon.MsgTypeFullState,
+		Alerts:     map[string]string{"%1": "stop"},
+		AlertTimes: map[string]time.Time{"%1": alertedAt},
+	}}
+	updatedModel, _ := m.Update(fullState)
+	m = updatedModel.(model)
+
+	alertChange := daemonEventMsg{msg: daemon.Message{
+		Type:      daemon.MsgTypeAlertChange,
+		PaneID:    "%2",
+		EventType: "idle",
+		Created:   true,
+		AlertedAt: alertedAt.Add(time.Minute),
+	}}
+	updatedModel, _ = m.Update(alertChange)
+	m = updatedModel.(model)
+
+	// Pane %2 disappears from the tree
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {
+			"main": {
+				testPane("%1", "@1", 0, true),
+			},
+		},
+	})
+	updatedModel, _ = m.Update(daemonEventMsg{msg: daemon.Message{Type: daemon.MsgTypeTreeUpdate, Tree: &tree}})
+	m = updatedModel.(model)
+
+	m.alertsMu.RLock()
+	defer m.alertsMu.RUnlock()
+	if got := m.alertTimes["%1"]; !got.Equal(alertedAt) {
+		t.Errorf("Alert time for surviving %%1 = %v, want %v", got, alertedAt)
+	}
+	if _, exists := m.alertTimes["%2"]; exists {
+		t.Error("Alert time for the removed pane should be dropped with its alert")
+	}
+}
+
 // TestReconcileAlerts_EmptyTree verifies that reconcileAlerts correctly handles
 // empty tree state (all panes removed). All alerts should be removed to prevent
 // memory leaks from orphaned alerts.
@@ -1895,3 +2047,103 @@ func TestTreeUpdate_CompleteFlowWithAlertReconciliation(t *testing.T) {
 		t.Errorf("Expected treeRefreshError to be cleared after successful tree_update, got: %v", err)
 	}
 }
+
+// TestDisconnect_ReconnectsInsteadOfDisabling verifies a daemon disconnect shows the
+// reconnecting banner and keeps the client, and that only a failed reconnect falls
+// back to the permanent alerts-disabled state.
+func TestDisconnect_ReconnectsInsteadOfDisabling(t *testing.T) {
+	m := initialModel()
+	m.tree = testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {"main": {testPane("%1", "@1", 0, true)}},
+	})
+	client := daemon.NewDaemonClient()
+	defer client.Close()
+	m.daemonClient = client
+	m.errorMu.Lock()
+	m.alertsDisabled = false
+	m.alertError = ""
+	m.errorMu.Unlock()
+
+	updated, cmd := m.Update(daemonEventMsg{msg: daemon.Message{Type: "disconnect"}})
+	m = updated.(model)
+	if cmd == nil || m.daemonClient != client || !m.reconnecting {
+		t.Fatalf("Disconnect should start reconnecting with the same client (cmd=%v, reconnecting=%v)", cmd != nil, m.reconnecting)
+	}
+	if view := m.View(); !strings.Contains(view, "reconnecting") || strings.Contains(view, "ALERT NOTIFICATIONS DISABLED") {
+		t.Errorf("Expected the reconnecting banner, got:\n%s", view)
+	}
+
+	// A second report of the same failure doesn't start another reconnect
+	updated, _ = m.Update(daemonEventMsg{msg: daemon.Message{Type: "disconnect"}})
+	m = updated.(model)
+	if !m.reconnecting {
+		t.Error("Duplicate disconnect cleared the reconnecting state")
+	}
+
+	updated, _ = m.Update(daemonReconnectedMsg{})
+	m = updated.(model)
+	if m.reconnecting || m.daemonClient != client || m.alertsDisabled {
+		t.Errorf("After reconnecting: reconnecting=%v alertsDisabled=%v client kept=%v", m.reconnecting, m.alertsDisabled, m.daemonClient == client)
+	}
+
+	// Reconnect that gives up (client closed) is the permanent-failure path
+	m.reconnecting = true
+	updated, _ = m.Update(daemonReconnectedMsg{err: daemon.ErrClientClosed})
+	m = updated.(model)
+	if m.daemonClient != nil || !m.alertsDisabled {
+		t.Errorf("Failed reconnect should disable alerts and drop the client")
+	}
+	if view := m.View(); !strings.Contains(view, "ALERT NOTIFICATIONS DISABLED") {
+		t.Errorf("Expected the alerts-disabled banner, got:\n%s", view)
+	}
+}
+
+// TestUpdate_CustomKeyBindings checks that the picker and quit go through the key map
+func TestUpdate_CustomKeyBindings(t *testing.T) {
+	m := initialModel()
+	keys, err := ui.ParseKeyMap([]byte(`{"picker_down": ["ctrl+j"], "picker_cancel": ["ctrl+g"], "quit": ["q"]}`))
+	if err != nil {
+		t.Fatalf("ParseKeyMap failed: %v", err)
+	}
+	m.keys = keys
+	m.pickingBranch = true
+	m.pickingForBranch = "feature"
+	m.branchPicker.SetBranches([]string{"api", "main"})
+
+	update := func(key tea.KeyMsg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(key)
+		m = updated.(model)
+		return cmd
+	}
+
+	// The replaced default no longer moves the selection
+	update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.branchPicker.Selected(); got != "api" {
+		t.Errorf("Selected after unbound down = %q, want api", got)
+	}
+	update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if got := m.branchPicker.Selected(); got != "main" {
+		t.Errorf("Selected after ctrl+j = %q, want main", got)
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.pickingBranch {
+		t.Fatal("Unbound esc should leave the picker open")
+	}
+	update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if m.pickingBranch {
+		t.Fatal("ctrl+g should cancel the picker")
+	}
+
+	if cmd := update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd != nil {
+		t.Error("ctrl+c should not quit once quit is rebound")
+	}
+	cmd := update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("q should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("q should return tea.Quit")
+	}
+}
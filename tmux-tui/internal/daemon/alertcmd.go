@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// AlertCommandEnv names an environment variable holding a shell command that plays the
+// alert sound instead of the terminal notification sequence. "{sound}" in the command
+// expands to the shell-quoted AlertSoundEnv file. The special value "auto" picks a
+// player for the platform (afplay on macOS, paplay or aplay on Linux) and its default
+// sound. Setting it to an empty string disables alert audio entirely.
+//
+// Unset by default, which keeps the terminal notification (OSC 777/9 + BEL).
+const AlertCommandEnv = "TMUX_TUI_ALERT_CMD"
+
+// AlertSoundEnv names the sound file substituted for "{sound}" in AlertCommandEnv.
+// With "auto", it overrides the player's default sound.
+const AlertSoundEnv = "TMUX_TUI_ALERT_SOUND"
+
+// alertCommandAuto selects a platform default player (see AlertCommandEnv).
+const alertCommandAuto = "auto"
+
+// alertSoundPlaceholder is replaced with the sound file in alert command templates.
+const alertSoundPlaceholder = "{sound}"
+
+// alertPlayer is a platform sound player used by "auto".
+type alertPlayer struct {
+	binary   string // Looked up on PATH; the first player found is used
+	template string
+	sound    string // Default sound file
+}
+
+// alertPlayers lists the "auto" players for each GOOS, in order of preference.
+var alertPlayers = map[string][]alertPlayer{
+	"darwin": {
+		{binary: "afplay", template: "afplay {sound}", sound: "/System/Library/Sounds/Glass.aiff"},
+	},
+	"linux": {
+		{binary: "paplay", template: "paplay {sound}", sound: "/usr/share/sounds/freedesktop/stereo/complete.oga"},
+		{binary: "aplay", template: "aplay -q {sound}", sound: "/usr/share/sounds/alsa/Front_Center.wav"},
+	},
+}
+
+// alertAudio is how the daemon plays alerts, resolved once at startup.
+type alertAudio struct {
+	command  string // Expanded shell command; empty = terminal notification sequence
+	disabled bool   // No audio at all (AlertCommandEnv set to empty, or no "auto" player)
+}
+
+// alertAudioFromEnv resolves AlertCommandEnv and AlertSoundEnv for this platform.
+// Problems are reported once here, so a missing player doesn't produce an error on
+// every alert.
+func alertAudioFromEnv() alertAudio {
+	template, ok := os.LookupEnv(AlertCommandEnv)
+	if !ok {
+		return alertAudio{}
+	}
+	audio, err := resolveAlertAudio(strings.TrimSpace(template), os.Getenv(AlertSoundEnv), runtime.GOOS, exec.LookPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: %v - alert audio disabled\n", err)
+	}
+	return audio
+}
+
+// resolveAlertAudio turns an AlertCommandEnv template into the command to run.
+// lookPath is exec.LookPath outside tests.
+func resolveAlertAudio(template, sound, goos string, lookPath func(string) (string, error)) (alertAudio, error) {
+	if template == "" {
+		return alertAudio{disabled: true}, nil
+	}
+	if template == alertCommandAuto {
+		player, ok := findAlertPlayer(goos, lookPath)
+		if !ok {
+			return alertAudio{disabled: true}, fmt.Errorf("%s=%s: no supported sound player found for %s", AlertCommandEnv, alertCommandAuto, goos)
+		}
+		template = player.template
+		if sound == "" {
+			sound = player.sound
+		}
+	}
+	return alertAudio{command: expandAlertCommand(template, sound)}, nil
+}
+
+// findAlertPlayer returns the first "auto" player for goos that is on PATH.
+func findAlertPlayer(goos string, lookPath func(string) (string, error)) (alertPlayer, bool) {
+	for _, player := range alertPlayers[goos] {
+		if _, err := lookPath(player.binary); err == nil {
+			return player, true
+		}
+	}
+	return alertPlayer{}, false
+}
+
+// expandAlertCommand replaces every "{sound}" in template with the shell-quoted sound.
+func expandAlertCommand(template, sound string) string {
+	return strings.ReplaceAll(template, alertSoundPlaceholder, shellQuote(sound))
+}
+
+// shellQuote quotes s as a single sh word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
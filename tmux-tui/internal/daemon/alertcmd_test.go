@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/commons-systems/tmux-tui/internal/watcher"
+)
+
+func TestExpandAlertCommand(t *testing.T) {
+	tests := []struct {
+		template string
+		sound    string
+		want     string
+	}{
+		{"afplay {sound}", "/System/Library/Sounds/Glass.aiff", "afplay '/System/Library/Sounds/Glass.aiff'"},
+		{"paplay --volume 30000 {sound}", "/home/me/My Sounds/ding.oga", "paplay --volume 30000 '/home/me/My Sounds/ding.oga'"},
+		{"play {sound} && play {sound}", "it's.wav", `play 'it'\''s.wav' && play 'it'\''s.wav'`},
+		{"say done", "/ignored.wav", "say done"},
+	}
+	for _, tt := range tests {
+		if got := expandAlertCommand(tt.template, tt.sound); got != tt.want {
+			t.Errorf("expandAlertCommand(%q, %q) = %q, want %q", tt.template, tt.sound, got, tt.want)
+		}
+	}
+}
+
+func TestResolveAlertAudio(t *testing.T) {
+	// onPath returns a lookPath that only finds the given binaries
+	onPath := func(binaries ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, b := range binaries {
+				if b == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		sound    string
+		goos     string
+		lookPath func(string) (string, error)
+		want     alertAudio
+		wantErr  bool
+	}{
+		{"empty disables audio", "", "", "linux", onPath(), alertAudio{disabled: true}, false},
+		{"auto on macOS", "auto", "", "darwin", onPath("afplay"),
+			alertAudio{command: "afplay '/System/Library/Sounds/Glass.aiff'"}, false},
+		{"auto on Linux prefers paplay", "auto", "", "linux", onPath("paplay", "aplay"),
+			alertAudio{command: "paplay '/usr/share/sounds/freedesktop/stereo/complete.oga'"}, false},
+		{"auto on Linux falls back to aplay", "auto", "/tmp/bell.wav", "linux", onPath("aplay"),
+			alertAudio{command: "aplay -q '/tmp/bell.wav'"}, false},
+		{"auto without a player", "auto", "", "linux", onPath(), alertAudio{disabled: true}, true},
+		{"auto on unsupported platform", "auto", "", "windows", onPath("afplay"), alertAudio{disabled: true}, true},
+		{"custom command", "mpv --really-quiet {sound}", "/tmp/a.mp3", "linux", onPath(),
+			alertAudio{command: "mpv --really-quiet '/tmp/a.mp3'"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAlertAudio(tt.template, tt.sound, tt.goos, tt.lookPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAlertAudio() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPlayAlertSound_RunsAlertCommand verifies a configured alert command replaces the
+// terminal notification and runs between the pre/post hooks.
+func TestPlayAlertSound_RunsAlertCommand(t *testing.T) {
+	t.Setenv("CLAUDE_E2E_TEST", "")
+	logPath := filepath.Join(t.TempDir(), "audio.log")
+	cleanup := setupPlayAlertSoundTest(func() (io.WriteCloser, error) {
+		return logWriter{path: logPath}, nil
+	})
+	defer cleanup()
+
+	daemon := &AlertDaemon{
+		clients:    make(map[string]*clientConnection),
+		alertHooks: map[string]alertHook{watcher.EventTypeStop: {pre: "echo pre >> " + logPath}},
+		alertAudio: alertAudio{command: `echo "play $TMUX_TUI_ALERT_EVENT" >> ` + logPath},
+	}
+	daemon.playAlertSound(watcher.EventTypeStop)
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read audio log: %v", err)
+	}
+	if got, want := string(data), "pre\nplay stop\n"; got != want {
+		t.Errorf("Audio log = %q, want %q (command instead of the terminal sequence)", got, want)
+	}
+}
+
+func TestPlayAlertSound_DisabledAudio(t *testing.T) {
+	t.Setenv("CLAUDE_E2E_TEST", "")
+	mock := &mockWriteCloser{}
+	cleanup := setupPlayAlertSoundTest(func() (io.WriteCloser, error) {
+		return mock, nil
+	})
+	defer cleanup()
+
+	daemon := &AlertDaemon{
+		clients:    make(map[string]*clientConnection),
+		alertAudio: alertAudio{disabled: true},
+	}
+	daemon.playAlertSound(watcher.EventTypeStop)
+
+	if len(mock.written) != 0 {
+		t.Errorf("Disabled audio wrote %q to the terminal", mock.written)
+	}
+}
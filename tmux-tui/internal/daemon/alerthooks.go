@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+	"github.com/commons-systems/tmux-tui/internal/watcher"
+)
+
+// AlertPreCommandEnv and AlertPostCommandEnv name environment variables holding shell
+// commands run immediately before and after the alert sound (e.g. to set the volume or
+// switch the output device). A per-event variant, suffixed with the upper-cased event
+// type (TMUX_TUI_ALERT_PRE_COMMAND_PERMISSION), overrides the default for that event;
+// setting it to an empty string disables the hook for that event only.
+//
+// Both are unset by default, which leaves alert sounds unchanged.
+const (
+	AlertPreCommandEnv  = "TMUX_TUI_ALERT_PRE_COMMAND"
+	AlertPostCommandEnv = "TMUX_TUI_ALERT_POST_COMMAND"
+)
+
+// alertEventEnv is set to the alert's event type in the environment of hook commands.
+const alertEventEnv = "TMUX_TUI_ALERT_EVENT"
+
+// alertHookTimeout bounds each hook command. Hooks run synchronously with the sound,
+// under the audio rate limit, so a hung command must not stall alert handling.
+const alertHookTimeout = 2 * time.Second
+
+// alertEventTypes are the event types that play a sound (everything except working).
+var alertEventTypes = []string{
+	watcher.EventTypeStop,
+	watcher.EventTypePermission,
+	watcher.EventTypeIdle,
+	watcher.EventTypeElicitation,
+}
+
+// alertHook holds the commands run around the alert sound for one event type.
+type alertHook struct {
+	pre  string
+	post string
+}
+
+// alertHooksFromEnv resolves the pre/post commands for every alert event type from
+// AlertPreCommandEnv and AlertPostCommandEnv. Event types without commands are omitted.
+func alertHooksFromEnv() map[string]alertHook {
+	hooks := make(map[string]alertHook)
+	for _, eventType := range alertEventTypes {
+		hook := alertHook{
+			pre:  alertCommandFromEnv(AlertPreCommandEnv, eventType),
+			post: alertCommandFromEnv(AlertPostCommandEnv, eventType),
+		}
+		if hook.pre != "" || hook.post != "" {
+			hooks[eventType] = hook
+		}
+	}
+	return hooks
+}
+
+// alertCommandFromEnv returns the per-event command if its variable is set (even to
+// an empty string), otherwise the default command from base.
+func alertCommandFromEnv(base, eventType string) string {
+	if cmd, ok := os.LookupEnv(base + "_" + strings.ToUpper(eventType)); ok {
+		return strings.TrimSpace(cmd)
+	}
+	return strings.TrimSpace(os.Getenv(base))
+}
+
+// runAlertHook runs one hook command through sh with the event type in its
+// environment. Failures are broadcast like other audio errors; the sound still plays.
+func (d *AlertDaemon) runAlertHook(stage, command, eventType string) {
+	if command == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertHookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), alertEventEnv+"="+eventType)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %v", alertHookTimeout)
+		}
+		debug.Log("AUDIO_HOOK_FAILED stage=%s event=%s error=%v output=%q", stage, eventType, err, output)
+		d.broadcastAudioError(fmt.Errorf("alert %s-command failed: %w (output: %s)",
+			stage, err, strings.TrimSpace(string(output))))
+		return
+	}
+	debug.Log("AUDIO_HOOK_COMPLETED stage=%s event=%s", stage, eventType)
+}
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/watcher"
+)
+
+// logWriter appends a marker line to a log file when the alert sequence is written,
+// so tests can check its order relative to hook commands writing to the same file.
+type logWriter struct{ path string }
+
+func (w logWriter) Write(p []byte) (int, error) {
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if _, err := f.WriteString("sound\n"); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w logWriter) Close() error { return nil }
+
+func TestPlayAlertSound_RunsPreAndPostCommands(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	cleanup := setupPlayAlertSoundTest(func() (io.WriteCloser, error) {
+		return logWriter{path: logPath}, nil
+	})
+	defer cleanup()
+
+	daemon := &AlertDaemon{
+		clients: make(map[string]*clientConnection),
+		alertHooks: map[string]alertHook{
+			watcher.EventTypePermission: {
+				pre:  `echo "pre $TMUX_TUI_ALERT_EVENT" >> ` + logPath,
+				post: `echo "post $TMUX_TUI_ALERT_EVENT" >> ` + logPath,
+			},
+		},
+	}
+	daemon.playAlertSound(watcher.EventTypePermission)
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read hook log: %v", err)
+	}
+	if got, want := string(data), "pre permission\nsound\npost permission\n"; got != want {
+		t.Errorf("Hook log = %q, want %q", got, want)
+	}
+}
+
+func TestPlayAlertSound_NoHooksForOtherEventTypes(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	cleanup := setupPlayAlertSoundTest(func() (io.WriteCloser, error) {
+		return logWriter{path: logPath}, nil
+	})
+	defer cleanup()
+
+	daemon := &AlertDaemon{
+		clients: make(map[string]*clientConnection),
+		alertHooks: map[string]alertHook{
+			watcher.EventTypePermission: {pre: "echo pre >> " + logPath},
+		},
+	}
+	daemon.playAlertSound(watcher.EventTypeStop)
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read hook log: %v", err)
+	}
+	if got := string(data); got != "sound\n" {
+		t.Errorf("Hook log = %q, want only the sound", got)
+	}
+}
+
+// TestPlayAlertSound_PreCommandFailureBroadcastsAndPlays verifies a failing hook is
+// reported as an audio error without suppressing the sound.
+func TestPlayAlertSound_PreCommandFailureBroadcastsAndPlays(t *testing.T) {
+	mock := &mockWriteCloser{}
+	cleanup := setupPlayAlertSoundTest(func() (io.WriteCloser, error) {
+		return mock, nil
+	})
+	defer cleanup()
+
+	daemon, clientConn, serverConn := createTestDaemon(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+	daemon.alertHooks = map[string]alertHook{
+		watcher.EventTypeStop: {pre: "echo no such device >&2; exit 3"},
+	}
+
+	var msg Message
+	done := make(chan error, 1)
+	go func() { done <- json.NewDecoder(clientConn).Decode(&msg) }()
+
+	daemon.playAlertSound(watcher.EventTypeStop)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Failed to receive error broadcast: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for error broadcast")
+	}
+	if msg.Type != MsgTypeAudioError || !strings.Contains(msg.Error, "alert pre-command failed") ||
+		!strings.Contains(msg.Error, "no such device") {
+		t.Errorf("Expected pre-command audio error with output, got %s: %q", msg.Type, msg.Error)
+	}
+	if len(mock.written) == 0 {
+		t.Error("Sound was not played after pre-command failure")
+	}
+}
+
+func TestAlertHooksFromEnv(t *testing.T) {
+	t.Setenv(AlertPreCommandEnv, "volume 30")
+	t.Setenv(AlertPostCommandEnv, "")
+	t.Setenv(AlertPreCommandEnv+"_PERMISSION", "volume 80")
+	t.Setenv(AlertPostCommandEnv+"_PERMISSION", "volume 30")
+	t.Setenv(AlertPreCommandEnv+"_IDLE", "") // disables the default for idle
+
+	hooks := alertHooksFromEnv()
+	if got := hooks[watcher.EventTypeStop]; got != (alertHook{pre: "volume 30"}) {
+		t.Errorf("stop hook = %+v, want default pre command", got)
+	}
+	if got := hooks[watcher.EventTypePermission]; got != (alertHook{pre: "volume 80", post: "volume 30"}) {
+		t.Errorf("permission hook = %+v, want per-event commands", got)
+	}
+	if _, ok := hooks[watcher.EventTypeIdle]; ok {
+		t.Errorf("idle hook = %+v, want none", hooks[watcher.EventTypeIdle])
+	}
+}
@@ -0,0 +1,685 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// BlockMap maps each blocked branch to the branches blocking it, in the order they
+// were added. A branch stays blocked until its last blocker is removed.
+//
+// It unmarshals from both the current array form ({"feature": ["main", "dev"]}) and
+// the original single-blocker form ({"feature": "main"}), so blocked-branches files
+// and exports written before multiple blockers were supported still load.
+type BlockMap map[string][]string
+
+// UnmarshalJSON accepts a string or an array of strings for each branch.
+func (m *BlockMap) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*m = nil
+		return nil
+	}
+
+	blocks := make(BlockMap, len(raw))
+	for branch, value := range raw {
+		var blocker string
+		if err := json.Unmarshal(value, &blocker); err == nil {
+			blocks[branch] = []string{blocker}
+			continue
+		}
+		var blockers []string
+		if err := json.Unmarshal(value, &blockers); err != nil {
+			return fmt.Errorf("blockers of %q must be a string or an array of strings: %w", branch, err)
+		}
+		blocks[branch] = blockers
+	}
+	*m = blocks
+	return nil
+}
+
+// Clone returns a deep copy, so callers can't mutate the original's blocker slices.
+// Returns an empty (non-nil) map if m is nil, like copyStringMap.
+func (m BlockMap) Clone() BlockMap {
+	result := make(BlockMap, len(m))
+	for branch, blockers := range m {
+		result[branch] = append([]string(nil), blockers...)
+	}
+	return result
+}
+
+// involves reports whether branch is blocked or blocks another branch.
+func (m BlockMap) involves(branch string) bool {
+	for blocked, blockers := range m {
+		if blocked == branch || slices.Contains(blockers, branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// cyclePath returns the block cycle that blocking branch on blocker would create, as
+// branch -> blocker -> ... -> branch, or nil if it would create none. Blockers are
+// searched breadth-first in the order they were added, so the shortest cycle is
+// reported. Blocking a branch on itself is the one-step cycle branch -> branch.
+func (m BlockMap) cyclePath(branch, blocker string) []string {
+	if blocker == branch {
+		return []string{branch, branch}
+	}
+	// via records how each branch was reached from blocker: blocked branch -> the
+	// branch it blocks on the way back
+	via := map[string]string{blocker: ""}
+	pending := []string{blocker}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, next := range m[current] {
+			if _, seen := via[next]; seen {
+				continue
+			}
+			via[next] = current
+			if next != branch {
+				pending = append(pending, next)
+				continue
+			}
+			// Walk back from branch to blocker, then put the new block in front
+			var chain []string
+			for step := branch; step != ""; step = via[step] {
+				chain = append(chain, step)
+			}
+			slices.Reverse(chain)
+			return append([]string{branch}, chain...)
+		}
+	}
+	return nil
+}
+
+// blockCycleReason explains a rejected block that would create cycle (see cyclePath).
+func blockCycleReason(branch, blocker string, cycle []string) string {
+	return fmt.Sprintf("block cycle: '%s' cannot be blocked by '%s' (%s)", branch, blocker, strings.Join(cycle, " -> "))
+}
+
+// BlockExpiries records when expiring blocks end: blocked branch -> blocker -> expiry.
+// Blocks without an entry never expire. An entry only applies while its block is in
+// the BlockMap; see prune.
+type BlockExpiries map[string]map[string]time.Time
+
+// Clone returns a deep copy. Returns an empty (non-nil) map if e is nil, like BlockMap.Clone.
+func (e BlockExpiries) Clone() BlockExpiries {
+	result := make(BlockExpiries, len(e))
+	for branch, blockers := range e {
+		if len(blockers) > 0 {
+			result[branch] = copyTimeMap(blockers)
+		}
+	}
+	return result
+}
+
+// set records that blocker's block on branch ends at expiresAt. A zero expiresAt makes
+// the block permanent.
+func (e BlockExpiries) set(branch, blocker string, expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		delete(e[branch], blocker)
+		if len(e[branch]) == 0 {
+			delete(e, branch)
+		}
+		return
+	}
+	if e[branch] == nil {
+		e[branch] = make(map[string]time.Time)
+	}
+	e[branch][blocker] = expiresAt
+}
+
+// forBranch returns a copy of branch's expiries, blocker -> expiry (nil if none).
+func (e BlockExpiries) forBranch(branch string) map[string]time.Time {
+	return copyTimeMap(e[branch])
+}
+
+// prune drops the entries of blocks that are no longer in blocks.
+func (e BlockExpiries) prune(blocks BlockMap) {
+	for branch, expiries := range e {
+		for blocker := range expiries {
+			if !slices.Contains(blocks[branch], blocker) {
+				e.set(branch, blocker, time.Time{})
+			}
+		}
+	}
+}
+
+// expired returns the blocks in blocks whose expiry is at or before now, as
+// branch -> expired blockers.
+func (e BlockExpiries) expired(blocks BlockMap, now time.Time) BlockMap {
+	result := make(BlockMap)
+	for branch, blockers := range blocks {
+		for _, blocker := range blockers {
+			if expiresAt, ok := e[branch][blocker]; ok && !expiresAt.After(now) {
+				result[branch] = append(result[branch], blocker)
+			}
+		}
+	}
+	return result
+}
+
+// blockExpiriesPath returns the file that persists BlockExpiries next to the
+// blocked-branches file. Keeping them apart leaves the blocked-branches file in the
+// format exports, imports and hand edits use.
+func blockExpiriesPath(blockedPath string) string {
+	return strings.TrimSuffix(blockedPath, ".json") + "-expiries.json"
+}
+
+// loadBlockExpiries loads BlockExpiries saved by saveBlockedBranches. A missing file
+// means no block expires.
+func loadBlockExpiries(path string) (BlockExpiries, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(BlockExpiries), nil
+		}
+		return nil, fmt.Errorf("failed to read block expiries file: %w", err)
+	}
+	var expiries BlockExpiries
+	if err := json.Unmarshal(data, &expiries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal block expiries: %w", err)
+	}
+	return expiries.Clone(), nil
+}
+
+// ValidateBlockGraph checks a complete blocked-branches map (branch -> blockers) for
+// empty names, self-blocks and cycles (a blocked by b, b blocked by a). All problems
+// are reported together, in sorted branch order.
+func ValidateBlockGraph(blocks BlockMap) error {
+	branches := make([]string, 0, len(blocks))
+	for branch := range blocks {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+
+	var problems []string
+	// edges holds only well-formed blocks, so malformed ones aren't also reported as cycles
+	edges := make(map[string][]string, len(blocks))
+	for _, branch := range branches {
+		blockers := blocks[branch]
+		if strings.TrimSpace(branch) == "" {
+			problems = append(problems, fmt.Sprintf("empty branch name (blocked by %q)", strings.Join(blockers, ", ")))
+			continue
+		}
+		if len(blockers) == 0 {
+			problems = append(problems, fmt.Sprintf("'%s' has an empty blocking branch", branch))
+			continue
+		}
+		for _, blocker := range blockers {
+			switch {
+			case strings.TrimSpace(blocker) == "":
+				problems = append(problems, fmt.Sprintf("'%s' has an empty blocking branch", branch))
+			case branch == blocker:
+				problems = append(problems, fmt.Sprintf("'%s' cannot block itself", branch))
+			default:
+				edges[branch] = append(edges[branch], blocker)
+			}
+		}
+	}
+
+	// Depth-first search over blocker edges; reaching a branch that is still on the
+	// current path means a cycle. Finished branches are never revisited, so each
+	// cycle is reported once.
+	const (
+		unvisited = iota
+		onPath
+		finished
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(branch string)
+	visit = func(branch string) {
+		state[branch] = onPath
+		path = append(path, branch)
+		for _, next := range edges[branch] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case onPath:
+				start := 0
+				for path[start] != next {
+					start++
+				}
+				cycle := append(append([]string(nil), path[start:]...), next)
+				problems = append(problems, "block cycle: "+strings.Join(cycle, " -> "))
+			}
+		}
+		path = path[:len(path)-1]
+		state[branch] = finished
+	}
+	for _, branch := range branches {
+		if state[branch] == unvisited {
+			visit(branch)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid block configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// checkProtectedBlocks returns an error naming the first blocked branch in blocks
+// (sorted) that this daemon protects, or nil if none is protected.
+func (d *AlertDaemon) checkProtectedBlocks(blocks BlockMap) error {
+	branches := make([]string, 0, len(blocks))
+	for branch := range blocks {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+	for _, branch := range branches {
+		if IsProtectedBranch(branch, d.protectedBranches) {
+			return fmt.Errorf("protected branch: '%s' cannot be blocked", branch)
+		}
+	}
+	return nil
+}
+
+// importBlocks merges or replaces the blocked branches with blocks. The resulting
+// state is validated (self-blocks, cycles, protected branches) before anything is
+// applied, persisted, and announced with one block_change broadcast per changed
+// branch. On persistence failure the previous state is restored.
+// Returns the blocked state after the import.
+func (d *AlertDaemon) importBlocks(blocks BlockMap, mode string) (BlockMap, error) {
+	d.blockedMu.Lock()
+	previous := d.blockedBranches.Clone()
+
+	next := make(BlockMap, len(previous)+len(blocks))
+	if mode == ImportModeMerge {
+		for k, v := range previous {
+			next[k] = v
+		}
+	}
+	for k, v := range blocks.Clone() {
+		next[k] = v
+	}
+
+	if err := ValidateBlockGraph(next); err != nil {
+		d.blockedMu.Unlock()
+		return nil, err
+	}
+	if err := d.checkProtectedBlocks(blocks); err != nil {
+		d.blockedMu.Unlock()
+		return nil, err
+	}
+
+	// Imported blocks are permanent; blocks the import keeps retain their expiry
+	previousExpiries := d.blockExpiries.Clone()
+	d.blockedBranches = next
+	d.blockExpiries.prune(next)
+	expiries := d.blockExpiries.Clone()
+	d.blockedMu.Unlock()
+
+	if err := d.saveBlockedBranches(); err != nil {
+		d.blockedMu.Lock()
+		d.blockedBranches = previous
+		d.blockExpiries = previousExpiries
+		d.blockedMu.Unlock()
+		d.handlePersistenceError(err)
+		return nil, fmt.Errorf("failed to persist imported blocks: %w", err)
+	}
+
+	// Announce every change so connected TUIs update without a resync
+	changed := make([]string, 0, len(next))
+	for branch, blockers := range next {
+		if old, ok := previous[branch]; !ok || !slices.Equal(old, blockers) {
+			changed = append(changed, branch)
+		}
+	}
+	for branch := range previous {
+		if _, ok := next[branch]; !ok {
+			changed = append(changed, branch)
+		}
+	}
+	sort.Strings(changed)
+	for _, branch := range changed {
+		msg, err := NewBlockChangeMessage(d.seqCounter.Add(1), branch, next[branch], expiries.forBranch(branch))
+		if err != nil {
+			debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_change error=%v", err)
+			continue
+		}
+		d.broadcast(msg.ToWireFormat())
+	}
+
+	debug.Log("DAEMON_IMPORT_BLOCKS mode=%s imported=%d changed=%d total=%d", mode, len(blocks), len(changed), len(next))
+	return d.copyBlockedBranches(), nil
+}
+
+// unblockAll clears every blocked branch (tmux-tui-block --all). The empty state is
+// persisted before anything is announced: if the save fails, the complete previous map
+// is restored and no block_change is sent. On success each previously blocked branch
+// is broadcast as unblocked so connected TUIs update without a resync.
+func (d *AlertDaemon) unblockAll() error {
+	d.blockedMu.Lock()
+	previous := d.blockedBranches
+	previousExpiries := d.blockExpiries
+	d.blockedBranches = make(BlockMap)
+	d.blockExpiries = make(BlockExpiries)
+	d.blockedMu.Unlock()
+
+	if err := d.saveBlockedBranches(); err != nil {
+		d.blockedMu.Lock()
+		d.blockedBranches = previous
+		d.blockExpiries = previousExpiries
+		d.blockedMu.Unlock()
+		d.handlePersistenceError(err)
+		return fmt.Errorf("failed to persist cleared blocks: %w", err)
+	}
+
+	cleared := make([]string, 0, len(previous))
+	for branch := range previous {
+		cleared = append(cleared, branch)
+	}
+	sort.Strings(cleared)
+	for _, branch := range cleared {
+		msg, err := NewBlockChangeMessage(d.seqCounter.Add(1), branch, nil, nil)
+		if err != nil {
+			debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_change error=%v", err)
+			continue
+		}
+		d.broadcast(msg.ToWireFormat())
+	}
+
+	debug.Log("DAEMON_UNBLOCK_ALL cleared=%d", len(cleared))
+	return nil
+}
+
+// renameBranch moves oldBranch's blocks to newBranch after a git branch rename: the
+// blocks on oldBranch and its place among other branches' blockers, with their expiries.
+// newBranch must not appear in the blocked state yet, so a rename never merges two
+// branches' blocks; on any error the state is left unchanged. Persists the result and
+// broadcasts a block_change per affected branch.
+func (d *AlertDaemon) renameBranch(oldBranch, newBranch string) error {
+	d.blockedMu.Lock()
+	previous := d.blockedBranches.Clone()
+	previousExpiries := d.blockExpiries.Clone()
+
+	if !previous.involves(oldBranch) {
+		d.blockedMu.Unlock()
+		return fmt.Errorf("'%s' has no blocks to rename", oldBranch)
+	}
+	if previous.involves(newBranch) {
+		d.blockedMu.Unlock()
+		return fmt.Errorf("'%s' already has blocks; unblock it before renaming '%s' to it", newBranch, oldBranch)
+	}
+	if _, ok := previous[oldBranch]; ok && IsProtectedBranch(newBranch, d.protectedBranches) {
+		d.blockedMu.Unlock()
+		return fmt.Errorf("protected branch: '%s' cannot be blocked", newBranch)
+	}
+
+	rename := func(branch string) string {
+		if branch == oldBranch {
+			return newBranch
+		}
+		return branch
+	}
+	next := make(BlockMap, len(previous))
+	expiries := make(BlockExpiries, len(previousExpiries))
+	var changed []string
+	for branch, blockers := range previous {
+		renamed := make([]string, len(blockers))
+		touched := branch == oldBranch
+		for i, blocker := range blockers {
+			renamed[i] = rename(blocker)
+			touched = touched || blocker == oldBranch
+		}
+		next[rename(branch)] = renamed
+		if touched && branch != oldBranch {
+			changed = append(changed, branch)
+		}
+		for blocker, expiresAt := range previousExpiries[branch] {
+			expiries.set(rename(branch), rename(blocker), expiresAt)
+		}
+	}
+	if _, ok := previous[oldBranch]; ok {
+		changed = append(changed, oldBranch, newBranch)
+	}
+	d.blockedBranches = next
+	d.blockExpiries = expiries
+	d.blockedMu.Unlock()
+
+	if err := d.saveBlockedBranches(); err != nil {
+		d.blockedMu.Lock()
+		d.blockedBranches = previous
+		d.blockExpiries = previousExpiries
+		d.blockedMu.Unlock()
+		d.handlePersistenceError(err)
+		return fmt.Errorf("failed to persist renamed blocks: %w", err)
+	}
+
+	sort.Strings(changed)
+	for _, branch := range changed {
+		msg, err := NewBlockChangeMessage(d.seqCounter.Add(1), branch, next[branch], expiries.forBranch(branch))
+		if err != nil {
+			debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_change error=%v", err)
+			continue
+		}
+		d.broadcast(msg.ToWireFormat())
+	}
+
+	debug.Log("DAEMON_RENAME_BRANCH old=%s new=%s changed=%d", oldBranch, newBranch, len(changed))
+	return nil
+}
+
+// blockExpiryInterval is how often watchBlockExpiry looks for expired blocks, so a
+// block outlives its TTL by at most this long
+const blockExpiryInterval = 10 * time.Second
+
+// watchBlockExpiry removes blocks whose TTL has passed until the daemon stops.
+func (d *AlertDaemon) watchBlockExpiry() {
+	ticker := time.NewTicker(blockExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+			d.expireBlocks()
+		}
+	}
+}
+
+// expireBlocks removes every block whose expiry is at or before d.now(), persists the
+// result and broadcasts a block_change per affected branch. Unlike a client's unblock,
+// a failed save is reported but not reverted: the expiries are still on disk, so the
+// blocks would expire again right after a restart anyway.
+func (d *AlertDaemon) expireBlocks() {
+	d.blockedMu.Lock()
+	expired := d.blockExpiries.expired(d.blockedBranches, d.now())
+	if len(expired) == 0 {
+		d.blockedMu.Unlock()
+		return
+	}
+	changed := make([]string, 0, len(expired))
+	for branch, blockers := range expired {
+		var remaining []string
+		for _, blocker := range d.blockedBranches[branch] {
+			if !slices.Contains(blockers, blocker) {
+				remaining = append(remaining, blocker)
+			}
+		}
+		if len(remaining) > 0 {
+			d.blockedBranches[branch] = remaining
+		} else {
+			delete(d.blockedBranches, branch)
+		}
+		for _, blocker := range blockers {
+			d.blockExpiries.set(branch, blocker, time.Time{})
+		}
+		changed = append(changed, branch)
+	}
+	sort.Strings(changed)
+	after := d.blockedBranches.Clone()
+	expiries := d.blockExpiries.Clone()
+	d.blockedMu.Unlock()
+
+	if err := d.saveBlockedBranches(); err != nil {
+		d.handlePersistenceError(err)
+	}
+
+	for _, branch := range changed {
+		debug.Log("DAEMON_BLOCK_EXPIRED branch=%s blockers=%v", branch, expired[branch])
+		msg, err := NewBlockChangeMessage(d.seqCounter.Add(1), branch, after[branch], expiries.forBranch(branch))
+		if err != nil {
+			debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_change error=%v", err)
+			continue
+		}
+		d.broadcast(msg.ToWireFormat())
+	}
+}
+
+// ReloadBlockedBranches re-reads the blocked-branches file (e.g. after a hand edit,
+// on SIGHUP) and replaces the in-memory state with it. A file that doesn't parse, fails
+// ValidateBlockGraph or blocks a protected branch is rejected, like a block_branch
+// request would be, and the current state is kept. On success a
+// full_state is broadcast so connected clients resync without reconnecting, unless
+// every client already has that state.
+func (d *AlertDaemon) ReloadBlockedBranches() error {
+	// Hold saveMu so a concurrent save can't be half-written while we read
+	d.saveMu.Lock()
+	blocks, err := loadBlockedBranches(d.blockedPath)
+	if err == nil {
+		err = ValidateBlockGraph(blocks)
+	}
+	if err == nil {
+		err = d.checkProtectedBlocks(blocks)
+	}
+	if err != nil {
+		d.saveMu.Unlock()
+		debug.Log("DAEMON_RELOAD_BLOCKED_ERROR path=%s error=%v", d.blockedPath, err)
+		return fmt.Errorf("blocked branches not reloaded from %s (keeping current state): %w", d.blockedPath, err)
+	}
+	// Expiries of blocks the edit removed no longer apply
+	d.blockedMu.Lock()
+	d.blockedBranches = blocks
+	d.blockExpiries.prune(blocks)
+	d.blockedMu.Unlock()
+	d.saveMu.Unlock()
+
+	alerts, alertTimes, blocked, expiries, activePaneID := d.copyAlerts(), d.copyAlertTimes(), d.copyBlockedBranches(), d.copyBlockExpiries(), d.getActivePaneID()
+	snapshot, err := NewFullStateMessage(0, alerts, alertTimes, blocked, expiries, activePaneID, d.alertDir)
+	if err != nil {
+		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=full_state error=%v", err)
+		return fmt.Errorf("blocked branches reloaded but clients not notified: %w", err)
+	}
+	// An unchanged file leaves clients with the state they have; skipping the broadcast
+	// also leaves the sequence number alone
+	if d.clientsHaveFullState(fullStateHash(snapshot.ToWireFormat())) {
+		debug.Log("DAEMON_RELOAD_BLOCKED_UNCHANGED path=%s count=%d", d.blockedPath, len(blocks))
+		return nil
+	}
+
+	fullStateMsg, err := NewFullStateMessage(d.seqCounter.Add(1), alerts, alertTimes, blocked, expiries, activePaneID, d.alertDir)
+	if err != nil {
+		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=full_state error=%v", err)
+		return fmt.Errorf("blocked branches reloaded but clients not notified: %w", err)
+	}
+	d.broadcast(fullStateMsg.ToWireFormat())
+
+	debug.Log("DAEMON_RELOAD_BLOCKED path=%s count=%d", d.blockedPath, len(blocks))
+	return nil
+}
+
+// oneShotReadTimeout bounds each read by short-lived CLI clients (ExportBlocks,
+// ImportBlocks, QueryList), matching the health command's response timeout.
+const oneShotReadTimeout = 2 * time.Second
+
+// ExportBlocks connects to the daemon over conn as a short-lived client and returns
+// its blocked-branches map (taken from the full_state sent on connect).
+func ExportBlocks(conn net.Conn) (BlockMap, error) {
+	decoder, err := helloOneShot(conn, "export-blocks")
+	if err != nil {
+		return nil, err
+	}
+	msg, err := readUntil(conn, decoder, MsgTypeFullState)
+	if err != nil {
+		return nil, err
+	}
+	return msg.BlockedBranches.Clone(), nil
+}
+
+// ImportBlocks connects to the daemon over conn, sends blocks with the given mode
+// (ImportModeMerge or ImportModeReplace) and waits for the result. Returns the daemon's
+// blocked state after the import, or the daemon's reason for rejecting it.
+func ImportBlocks(conn net.Conn, blocks BlockMap, mode string) (BlockMap, error) {
+	importMsg, err := NewImportBlocksMessage(0, blocks, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := helloOneShot(conn, "import-blocks")
+	if err != nil {
+		return nil, err
+	}
+	// Daemon sends full_state before reading further messages
+	if _, err := readUntil(conn, decoder, MsgTypeFullState); err != nil {
+		return nil, err
+	}
+	// Send while reading: the daemon may be writing broadcasts to us at the same time,
+	// and neither side would drain the connection if we wrote first
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- json.NewEncoder(conn).Encode(importMsg.ToWireFormat()) }()
+
+	result, err := readUntil(conn, decoder, MsgTypeImportBlocksResult)
+	if err != nil {
+		return nil, err
+	}
+	if err := <-sendErr; err != nil {
+		return nil, fmt.Errorf("failed to send import request: %w", err)
+	}
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+	return result.BlockedBranches.Clone(), nil
+}
+
+// helloOneShot registers a one-off client for a CLI subcommand.
+func helloOneShot(conn net.Conn, purpose string) (*json.Decoder, error) {
+	hello := Message{Type: MsgTypeHello, ClientID: fmt.Sprintf("%s-%d", purpose, os.Getpid()), ProtocolVersion: ProtocolVersion}
+	data, err := json.Marshal(hello)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hello: %w", err)
+	}
+	// No trailing newline: the daemon stops reading at the closing brace, and on an
+	// unbuffered conn a leftover byte would block this write while it sends full_state
+	if _, err := conn.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to send hello: %w", err)
+	}
+	return json.NewDecoder(conn), nil
+}
+
+// readUntil discards broadcasts (alerts, tree updates, ...) until a msgType message arrives.
+func readUntil(conn net.Conn, decoder *json.Decoder, msgType string) (Message, error) {
+	for {
+		conn.SetReadDeadline(time.Now().Add(oneShotReadTimeout))
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			return Message{}, fmt.Errorf("failed to receive %s: %w", msgType, err)
+		}
+		if msg.Type == msgType {
+			return msg, nil
+		}
+		if msg.Type == MsgTypeRejected {
+			return Message{}, fmt.Errorf("%w: %s", ErrConnectionRejected, msg.Error)
+		}
+		if msg.Type == MsgTypeVersionMismatch {
+			return Message{}, protocolMismatchError(msg)
+		}
+	}
+}
@@ -0,0 +1,660 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidateBlockGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocks    BlockMap
+		errSubstr string // empty = valid
+	}{
+		{"empty", BlockMap{}, ""},
+		{"chain", BlockMap{"a": {"b"}, "b": {"c"}, "d": {"c"}}, ""},
+		{"self block", BlockMap{"a": {"a"}}, "'a' cannot block itself"},
+		{"empty blocker", BlockMap{"a": {" "}}, "'a' has an empty blocking branch"},
+		{"two cycle", BlockMap{"a": {"b"}, "b": {"a"}}, "block cycle: a -> b -> a"},
+		{"three cycle", BlockMap{"a": {"b"}, "b": {"c"}, "c": {"a"}, "x": {"a"}}, "block cycle: a -> b -> c -> a"},
+		{"multiple blockers", BlockMap{"a": {"b", "c"}, "b": {"c"}}, ""},
+		{"cycle through second blocker", BlockMap{"a": {"b", "c"}, "c": {"a"}}, "block cycle: a -> c -> a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBlockGraph(tt.blocks)
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("Expected valid graph, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errSubstr, err)
+			}
+			if err != nil && strings.Count(err.Error(), "block cycle") > 1 {
+				t.Errorf("Expected each cycle reported once, got: %v", err)
+			}
+		})
+	}
+}
+
+// newBlocksTestDaemon returns a daemon with the given blocks persisted to a temp file.
+func newBlocksTestDaemon(t *testing.T, blocks BlockMap) *AlertDaemon {
+	t.Helper()
+	d := &AlertDaemon{
+		clients:         make(map[string]*clientConnection),
+		alerts:          make(map[string]string),
+		blockedBranches: blocks,
+		blockedPath:     filepath.Join(t.TempDir(), "blocked-branches.json"),
+	}
+	d.lastBroadcastError.Store("")
+	return d
+}
+
+// connectTestClient serves one client connection on d and returns the client end.
+func connectTestClient(t *testing.T, d *AlertDaemon) net.Conn {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	go d.handleClient(serverConn)
+	t.Cleanup(func() { clientConn.Close() })
+	return clientConn
+}
+
+// TestExportImportBlocks_RoundTrip exports blocks from one daemon and imports them into
+// a fresh daemon, which must end up with the same in-memory and persisted state.
+func TestExportImportBlocks_RoundTrip(t *testing.T) {
+	want := BlockMap{"feature-1": {"main"}, "feature-2": {"feature-1"}, "hotfix": {"release"}}
+	source := newBlocksTestDaemon(t, want)
+
+	exported, err := ExportBlocks(connectTestClient(t, source))
+	if err != nil {
+		t.Fatalf("ExportBlocks failed: %v", err)
+	}
+	if !reflect.DeepEqual(exported, want) {
+		t.Fatalf("Exported %v, want %v", exported, want)
+	}
+
+	target := newBlocksTestDaemon(t, BlockMap{"stale": {"other"}})
+	result, err := ImportBlocks(connectTestClient(t, target), exported, ImportModeReplace)
+	if err != nil {
+		t.Fatalf("ImportBlocks failed: %v", err)
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Import result %v, want %v", result, want)
+	}
+	if got := target.copyBlockedBranches(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Target daemon state %v, want %v", got, want)
+	}
+	persisted, err := loadBlockedBranches(target.blockedPath)
+	if err != nil {
+		t.Fatalf("Failed to load persisted blocks: %v", err)
+	}
+	if !reflect.DeepEqual(persisted, want) {
+		t.Errorf("Persisted state %v, want %v", persisted, want)
+	}
+}
+
+func TestImportBlocks_Merge(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{"feature-1": {"main"}})
+
+	result, err := ImportBlocks(connectTestClient(t, d), BlockMap{"feature-2": {"main"}}, ImportModeMerge)
+	if err != nil {
+		t.Fatalf("ImportBlocks failed: %v", err)
+	}
+	want := BlockMap{"feature-1": {"main"}, "feature-2": {"main"}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Merge result %v, want %v", result, want)
+	}
+}
+
+// TestImportBlocks_RejectsCycleWithExistingState verifies that a merge creating a cycle
+// with blocks already in the daemon is rejected and leaves state untouched.
+func TestImportBlocks_RejectsCycleWithExistingState(t *testing.T) {
+	initial := BlockMap{"feature-1": {"feature-2"}}
+	d := newBlocksTestDaemon(t, BlockMap{"feature-1": {"feature-2"}})
+
+	_, err := ImportBlocks(connectTestClient(t, d), BlockMap{"feature-2": {"feature-1"}}, ImportModeMerge)
+	if err == nil || !strings.Contains(err.Error(), "block cycle") {
+		t.Fatalf("Expected cycle rejection, got: %v", err)
+	}
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, initial) {
+		t.Errorf("State changed after rejected import: %v", got)
+	}
+}
+
+func TestImportBlocks_RejectsProtectedBranch(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{})
+	d.protectedBranches = []string{"main"}
+
+	_, err := ImportBlocks(connectTestClient(t, d), BlockMap{"main": {"feature"}}, ImportModeMerge)
+	if err == nil || !strings.Contains(err.Error(), "protected branch") {
+		t.Fatalf("Expected protected branch rejection, got: %v", err)
+	}
+	if got := d.copyBlockedBranches(); len(got) != 0 {
+		t.Errorf("State changed after rejected import: %v", got)
+	}
+}
+
+// TestBlockMap_UnmarshalJSON verifies both the array form and the single-blocker
+// string form written before multiple blockers were supported.
+func TestBlockMap_UnmarshalJSON(t *testing.T) {
+	var blocks BlockMap
+	if err := json.Unmarshal([]byte(`{"a": "main", "b": ["main", "develop"]}`), &blocks); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := BlockMap{"a": {"main"}, "b": {"main", "develop"}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("Unmarshal = %v, want %v", blocks, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a": 1}`), &blocks); err == nil {
+		t.Error("Expected error for a non-string blocker")
+	}
+}
+
+// connectBlockClient connects a registered client to d and reads past its full_state.
+func connectBlockClient(t *testing.T, d *AlertDaemon) (net.Conn, *json.Decoder) {
+	t.Helper()
+	conn := connectTestClient(t, d)
+	decoder, err := helloOneShot(conn, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readUntil(conn, decoder, MsgTypeFullState); err != nil {
+		t.Fatal(err)
+	}
+	return conn, decoder
+}
+
+// sendForBlockChange sends msg and returns the next block_change broadcast. It sends
+// while reading, as in ImportBlocks: the daemon may be writing to us concurrently.
+func sendForBlockChange(t *testing.T, conn net.Conn, decoder *json.Decoder, msg Message) Message {
+	t.Helper()
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- json.NewEncoder(conn).Encode(msg) }()
+	change, err := readUntil(conn, decoder, MsgTypeBlockChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-sendErr; err != nil {
+		t.Fatalf("Failed to send %s: %v", msg.Type, err)
+	}
+	return change
+}
+
+// TestBlockUnblock_MultipleBlockers blocks a branch on two branches, removes one
+// blocker, then removes the rest, checking broadcasts and persisted state.
+func TestBlockUnblock_MultipleBlockers(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{})
+	conn, decoder := connectBlockClient(t, d)
+	send := func(msg Message) Message {
+		t.Helper()
+		return sendForBlockChange(t, conn, decoder, msg)
+	}
+
+	send(Message{Type: MsgTypeBlockBranch, Branch: "feature", BlockedBranch: "main"})
+	change := send(Message{Type: MsgTypeBlockBranch, Branch: "feature", BlockedBranch: "develop"})
+	if !change.Blocked || !reflect.DeepEqual(change.Blockers, []string{"main", "develop"}) || change.BlockedBranch != "main" {
+		t.Errorf("After second block: %+v, want blocked by [main develop]", change)
+	}
+
+	change = send(Message{Type: MsgTypeUnblockBranch, Branch: "feature", BlockedBranch: "main"})
+	if !change.Blocked || !reflect.DeepEqual(change.Blockers, []string{"develop"}) {
+		t.Errorf("After removing main: %+v, want still blocked by [develop]", change)
+	}
+	persisted, err := loadBlockedBranches(d.blockedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (BlockMap{"feature": {"develop"}}); !reflect.DeepEqual(persisted, want) {
+		t.Errorf("Persisted %v, want %v", persisted, want)
+	}
+
+	send(Message{Type: MsgTypeBlockBranch, Branch: "feature", BlockedBranch: "main"})
+	change = send(Message{Type: MsgTypeUnblockBranch, Branch: "feature"})
+	if change.Blocked || len(change.Blockers) != 0 {
+		t.Errorf("After unblocking all: %+v, want unblocked", change)
+	}
+	if got := d.copyBlockedBranches(); len(got) != 0 {
+		t.Errorf("State after unblocking all: %v, want empty", got)
+	}
+}
+
+func TestBlockMap_CyclePath(t *testing.T) {
+	blocks := BlockMap{
+		"b":    {"a"},
+		"c":    {"main", "b"},
+		"d":    {"c"},
+		"side": {"main"},
+	}
+	tests := []struct {
+		branch, blocker string
+		want            []string
+	}{
+		{"a", "b", []string{"a", "b", "a"}},
+		{"a", "d", []string{"a", "d", "c", "b", "a"}},
+		{"a", "a", []string{"a", "a"}},
+		{"a", "side", nil},
+		{"d", "a", nil},                                   // Same direction as the existing chain
+		{"c", "b", nil},                                   // Already blocked: nothing new
+		{"main", "d", []string{"main", "d", "c", "main"}}, // Shortest path wins
+	}
+	for _, tt := range tests {
+		if got := blocks.cyclePath(tt.branch, tt.blocker); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cyclePath(%q, %q) = %v, want %v", tt.branch, tt.blocker, got, tt.want)
+		}
+	}
+}
+
+// TestBlockBranch_RejectsCycle checks that block requests closing a two-branch and a
+// longer cycle are answered with block_rejected carrying the cycle, leaving the state
+// and disk untouched.
+func TestBlockBranch_RejectsCycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocks    BlockMap
+		branch    string
+		blocker   string
+		wantCycle []string
+	}{
+		{"two branches", BlockMap{"b": {"a"}}, "a", "b", []string{"a", "b", "a"}},
+		{"longer cycle", BlockMap{"b": {"a"}, "c": {"b"}, "d": {"c"}}, "a", "d", []string{"a", "d", "c", "b", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newBlocksTestDaemon(t, tt.blocks.Clone())
+			conn, decoder := connectBlockClient(t, d)
+
+			sendErr := make(chan error, 1)
+			go func() {
+				sendErr <- json.NewEncoder(conn).Encode(Message{Type: MsgTypeBlockBranch, Branch: tt.branch, BlockedBranch: tt.blocker})
+			}()
+			rejected, err := readUntil(conn, decoder, MsgTypeBlockRejected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := <-sendErr; err != nil {
+				t.Fatalf("Failed to send block_branch: %v", err)
+			}
+
+			if !reflect.DeepEqual(rejected.CyclePath, tt.wantCycle) {
+				t.Errorf("CyclePath = %v, want %v", rejected.CyclePath, tt.wantCycle)
+			}
+			if want := blockCycleReason(tt.branch, tt.blocker, tt.wantCycle); rejected.Error != want {
+				t.Errorf("Error = %q, want %q", rejected.Error, want)
+			}
+			if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, tt.blocks) {
+				t.Errorf("Blocked state changed: got %v, want %v", got, tt.blocks)
+			}
+			if _, err := os.Stat(d.blockedPath); !os.IsNotExist(err) {
+				t.Errorf("Expected no persistence write for rejected block, stat err=%v", err)
+			}
+		})
+	}
+}
+
+// TestBlockExpiry blocks a branch with a TTL and another without, then lets the TTL
+// pass: only the expiring block is removed, persisted and broadcast.
+func TestBlockExpiry(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	d := newBlocksTestDaemon(t, BlockMap{})
+	d.clock = clock
+	conn, decoder := connectBlockClient(t, d)
+
+	change := sendForBlockChange(t, conn, decoder, Message{Type: MsgTypeBlockBranch, Branch: "feature", BlockedBranch: "main", TTL: 2 * time.Hour})
+	if got, want := change.BlockExpiries["feature"]["main"], start.Add(2*time.Hour); !got.Equal(want) {
+		t.Errorf("Broadcast expiry = %v, want %v", got, want)
+	}
+	sendForBlockChange(t, conn, decoder, Message{Type: MsgTypeBlockBranch, Branch: "feature", BlockedBranch: "develop"})
+	expiriesPath := blockExpiriesPath(d.blockedPath)
+	persisted, err := loadBlockExpiries(expiriesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := persisted["feature"]["main"]; !ok || len(persisted["feature"]) != 1 {
+		t.Errorf("Persisted expiries = %v, want only feature/main", persisted)
+	}
+
+	// Not yet due
+	clock.Advance(time.Hour)
+	d.expireBlocks()
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got["feature"], []string{"main", "develop"}) {
+		t.Fatalf("Blocks before expiry = %v, want feature blocked by [main develop]", got)
+	}
+
+	clock.Advance(time.Hour)
+	go d.expireBlocks()
+	change, err = readUntil(conn, decoder, MsgTypeBlockChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(change.Blockers, []string{"develop"}) || len(change.BlockExpiries) != 0 {
+		t.Errorf("Expiry broadcast = %+v, want blocked by [develop] with no expiries", change)
+	}
+	blocks, err := loadBlockedBranches(d.blockedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (BlockMap{"feature": {"develop"}}); !reflect.DeepEqual(blocks, want) {
+		t.Errorf("Persisted %v, want %v", blocks, want)
+	}
+	if _, err := os.Stat(expiriesPath); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed once nothing expires, got %v", expiriesPath, err)
+	}
+}
+
+// TestBlockBranch_PersistenceFailureRestoresBlockers verifies the revert path puts back
+// the complete previous blocker list, not just one blocker.
+func TestBlockBranch_PersistenceFailureRestoresBlockers(t *testing.T) {
+	previous := BlockMap{"feature": {"main", "develop"}}
+	d := newBlocksTestDaemon(t, previous.Clone())
+	d.blockedPath = filepath.Join(t.TempDir(), "missing-dir", "blocked-branches.json")
+	conn, decoder := connectBlockClient(t, d)
+
+	change := sendForBlockChange(t, conn, decoder, Message{Type: MsgTypeBlockBranch, Branch: "feature", BlockedBranch: "api"})
+	if !reflect.DeepEqual(change.Blockers, previous["feature"]) {
+		t.Errorf("Revert broadcast blockers = %v, want %v", change.Blockers, previous["feature"])
+	}
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, previous) {
+		t.Errorf("State after failed save = %v, want %v", got, previous)
+	}
+}
+
+// TestUnblockAll_PersistenceFailureRestoresMap verifies a failed save puts back every
+// previously blocked branch, not just one.
+func TestUnblockAll_PersistenceFailureRestoresMap(t *testing.T) {
+	previous := BlockMap{"feature-1": {"main"}, "feature-2": {"feature-1", "develop"}}
+	d := newBlocksTestDaemon(t, previous.Clone())
+	d.blockedPath = filepath.Join(t.TempDir(), "missing-dir", "blocked-branches.json")
+
+	if err := d.unblockAll(); err == nil {
+		t.Fatal("Expected unblockAll to fail when the save fails")
+	}
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, previous) {
+		t.Errorf("State after failed save = %v, want %v", got, previous)
+	}
+}
+
+// TestConcurrentBlockUnblockAll mirrors TestConcurrentBlockUnblock_SameBranch for the
+// all-clear case: one client keeps blocking branches while another keeps clearing
+// every block. In-memory and persisted state must agree afterwards and both clients
+// must see block_change broadcasts.
+func TestConcurrentBlockUnblockAll(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{"stale": {"main"}})
+
+	type testClient struct {
+		conn      net.Conn
+		changes   atomic.Int64
+		listReply chan struct{}
+	}
+	connect := func(clientID string) *testClient {
+		conn := connectTestClient(t, d)
+		decoder, err := helloOneShot(conn, clientID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Time{})
+		c := &testClient{conn: conn, listReply: make(chan struct{}, 1)}
+		// Keep reading: broadcasts to a client that isn't reading block the daemon
+		go func() {
+			for {
+				var msg Message
+				if err := decoder.Decode(&msg); err != nil {
+					return
+				}
+				switch msg.Type {
+				case MsgTypeBlockChange:
+					c.changes.Add(1)
+				case MsgTypeListResponse:
+					c.listReply <- struct{}{}
+				}
+			}
+		}()
+		return c
+	}
+	blocker, clearer := connect("blocker-client"), connect("unblocker-client")
+
+	// Each client ends with a list_query: its reply means every earlier message from
+	// that client has been processed
+	const numOperations = 20
+	var wg sync.WaitGroup
+	run := func(c *testClient, op func(i int) Message) {
+		defer wg.Done()
+		encoder := json.NewEncoder(c.conn)
+		for i := 0; i < numOperations; i++ {
+			if err := encoder.Encode(op(i)); err != nil {
+				t.Errorf("Failed to send operation %d: %v", i, err)
+				return
+			}
+		}
+		if err := encoder.Encode(Message{Type: MsgTypeListQuery}); err != nil {
+			t.Errorf("Failed to send list_query: %v", err)
+		}
+	}
+	wg.Add(2)
+	go run(blocker, func(i int) Message {
+		return Message{Type: MsgTypeBlockBranch, Branch: "test-branch", BlockedBranch: []string{"main", "develop"}[i%2]}
+	})
+	go run(clearer, func(int) Message { return Message{Type: MsgTypeUnblockAll} })
+	wg.Wait()
+
+	for _, c := range []*testClient{blocker, clearer} {
+		select {
+		case <-c.listReply:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the daemon to process all operations")
+		}
+	}
+
+	inMemory := d.copyBlockedBranches()
+	persisted, err := loadBlockedBranches(d.blockedPath)
+	if err != nil {
+		t.Fatalf("Failed to load persistence file: %v", err)
+	}
+	if !reflect.DeepEqual(inMemory, persisted) {
+		t.Errorf("State mismatch: in-memory %v, persisted %v", inMemory, persisted)
+	}
+	if _, ok := inMemory["stale"]; ok {
+		t.Error("Initial block should have been cleared")
+	}
+
+	if blocker.changes.Load() == 0 {
+		t.Error("Blocking client should have received block_change broadcasts")
+	}
+	if clearer.changes.Load() == 0 {
+		t.Error("Clearing client should have received block_change broadcasts")
+	}
+	t.Logf("Final state %v; broadcasts: blocker=%d clearer=%d", inMemory, blocker.changes.Load(), clearer.changes.Load())
+}
+
+// TestReloadBlockedBranches verifies a reload (SIGHUP) swaps in the file's state and
+// resyncs connected clients with a full_state, while a corrupt or cyclic file is
+// rejected and leaves the in-memory state alone.
+func TestReloadBlockedBranches(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{"feature": {"main"}})
+	d.protectedBranches = []string{"main", "release/*"}
+	conn, decoder, _ := connectNamedClient(t, d, "tui")
+
+	edited := BlockMap{"api": {"schema"}, "web": {"api", "design"}}
+	data, err := json.Marshal(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(d.blockedPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload while reading: the full_state broadcast blocks until the client reads it
+	reloadErr := make(chan error, 1)
+	go func() { reloadErr <- d.ReloadBlockedBranches() }()
+	fullState, err := readUntil(conn, decoder, MsgTypeFullState)
+	if err != nil {
+		t.Fatalf("No full_state after reload: %v", err)
+	}
+	if err := <-reloadErr; err != nil {
+		t.Fatalf("ReloadBlockedBranches failed: %v", err)
+	}
+	if !reflect.DeepEqual(fullState.BlockedBranches, edited) {
+		t.Errorf("full_state blocked branches = %v, want %v", fullState.BlockedBranches, edited)
+	}
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, edited) {
+		t.Errorf("State after reload = %v, want %v", got, edited)
+	}
+
+	for name, contents := range map[string]string{
+		"corrupt":   `{"api": [`,
+		"cycle":     `{"api": ["web"], "web": ["api"]}`,
+		"protected": `{"api": ["schema"], "release/1.0": ["api"]}`,
+	} {
+		if err := os.WriteFile(d.blockedPath, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.ReloadBlockedBranches(); err == nil {
+			t.Errorf("%s file: expected reload to fail", name)
+		}
+		if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, edited) {
+			t.Errorf("%s file: state after rejected reload = %v, want %v", name, got, edited)
+		}
+	}
+}
+
+// TestReloadBlockedBranches_AfterIncrementalChange verifies a reload that puts back the
+// state of the client's last full_state still resyncs it: a block_change since then
+// means the client holds a different state.
+func TestReloadBlockedBranches_AfterIncrementalChange(t *testing.T) {
+	initial := BlockMap{"feature": {"main"}}
+	d := newBlocksTestDaemon(t, initial.Clone())
+	conn, decoder := connectBlockClient(t, d)
+
+	sendForBlockChange(t, conn, decoder, Message{Type: MsgTypeBlockBranch, Branch: "web", BlockedBranch: "api"})
+
+	data, err := json.Marshal(initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(d.blockedPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloadErr := make(chan error, 1)
+	go func() { reloadErr <- d.ReloadBlockedBranches() }()
+	fullState, err := readUntil(conn, decoder, MsgTypeFullState)
+	if err != nil {
+		t.Fatalf("No full_state after reloading the original state: %v", err)
+	}
+	if err := <-reloadErr; err != nil {
+		t.Fatalf("ReloadBlockedBranches failed: %v", err)
+	}
+	if !reflect.DeepEqual(fullState.BlockedBranches, initial) {
+		t.Errorf("full_state blocked branches = %v, want %v", fullState.BlockedBranches, initial)
+	}
+}
+
+// TestRenameBranch moves a branch that is both blocked and blocking, checking the
+// result message, broadcasts, expiries and persisted state.
+func TestRenameBranch(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{"feature": {"main", "develop"}, "docs": {"feature"}, "hotfix": {"release"}})
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	d.blockExpiries = BlockExpiries{"feature": {"develop": expiresAt}, "docs": {"feature": expiresAt}}
+	conn, decoder := connectBlockClient(t, d)
+
+	rename, err := NewRenameBranchMessage(0, "feature", "feature-v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- json.NewEncoder(conn).Encode(rename.ToWireFormat()) }()
+	changes := make(map[string]Message)
+	var result Message
+	for result.Type == "" {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			t.Fatal(err)
+		}
+		switch msg.Type {
+		case MsgTypeBlockChange:
+			changes[msg.Branch] = msg
+		case MsgTypeRenameBranchResult:
+			result = msg
+		}
+	}
+	if err := <-sendErr; err != nil {
+		t.Fatal(err)
+	}
+	if result.Error != "" || result.Branch != "feature" || result.NewBranch != "feature-v2" {
+		t.Fatalf("Result = %+v, want success for feature -> feature-v2", result)
+	}
+
+	want := BlockMap{"feature-v2": {"main", "develop"}, "docs": {"feature-v2"}, "hotfix": {"release"}}
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, want) {
+		t.Errorf("State = %v, want %v", got, want)
+	}
+	persisted, err := loadBlockedBranches(d.blockedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(persisted, want) {
+		t.Errorf("Persisted state = %v, want %v", persisted, want)
+	}
+	wantExpiries := BlockExpiries{"feature-v2": {"develop": expiresAt}, "docs": {"feature-v2": expiresAt}}
+	if got := d.copyBlockExpiries(); !reflect.DeepEqual(got, wantExpiries) {
+		t.Errorf("Expiries = %v, want %v", got, wantExpiries)
+	}
+
+	if len(changes) != 3 || changes["feature"].Blocked || !changes["feature-v2"].Blocked ||
+		!reflect.DeepEqual(changes["docs"].Blockers, []string{"feature-v2"}) {
+		t.Errorf("Broadcasts = %+v, want feature unblocked, feature-v2 and docs updated", changes)
+	}
+}
+
+// TestRenameBranch_Rejected verifies refused renames leave the state untouched.
+func TestRenameBranch_Rejected(t *testing.T) {
+	initial := BlockMap{"feature": {"main"}, "docs": {"release"}}
+	tests := []struct {
+		name, old, new, wantErr string
+		protected               []string
+	}{
+		{name: "no blocks", old: "unknown", new: "renamed", wantErr: "no blocks"},
+		{name: "new name is blocked", old: "feature", new: "docs", wantErr: "already has blocks"},
+		{name: "new name is a blocker", old: "feature", new: "release", wantErr: "already has blocks"},
+		{name: "protected new name", old: "feature", new: "stable", protected: []string{"stable"}, wantErr: "protected branch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newBlocksTestDaemon(t, initial.Clone())
+			d.protectedBranches = tt.protected
+			err := d.renameBranch(tt.old, tt.new)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("renameBranch(%q, %q) = %v, want error containing %q", tt.old, tt.new, err, tt.wantErr)
+			}
+			if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, initial) {
+				t.Errorf("State changed after rejected rename: %v", got)
+			}
+		})
+	}
+}
+
+// TestRenameBranch_PersistenceFailureRestoresMap verifies a failed save puts back the
+// old names.
+func TestRenameBranch_PersistenceFailureRestoresMap(t *testing.T) {
+	previous := BlockMap{"feature": {"main"}, "docs": {"feature"}}
+	d := newBlocksTestDaemon(t, previous.Clone())
+	d.blockedPath = filepath.Join(t.TempDir(), "missing-dir", "blocked-branches.json")
+
+	if err := d.renameBranch("feature", "feature-v2"); err == nil {
+		t.Fatal("Expected renameBranch to fail when the save fails")
+	}
+	if got := d.copyBlockedBranches(); !reflect.DeepEqual(got, previous) {
+		t.Errorf("State after failed save = %v, want %v", got, previous)
+	}
+}
@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -16,11 +17,58 @@ import (
 )
 
 const (
-	heartbeatInterval       = 5 * time.Second        // How often to send pings
-	heartbeatTimeout        = 3 * time.Second        // How long to wait for pong response
+	// DefaultQueryTimeout is how long QueryBlockedState waits for the daemon's response
+	DefaultQueryTimeout = 2 * time.Second
+	// DefaultHeartbeatInterval is how often the client pings the daemon
+	DefaultHeartbeatInterval = 5 * time.Second
+	// DefaultHeartbeatTimeout is how long past an interval the client waits for a pong
+	DefaultHeartbeatTimeout = 3 * time.Second
+	// DefaultReorderWindow is how long the client holds messages that arrived past a
+	// sequence gap, waiting for the missing ones, before requesting a resync
+	DefaultReorderWindow = 20 * time.Millisecond
+
 	messagePropagationDelay = 100 * time.Millisecond // Best-effort delay after send (not an ack mechanism)
+
+	reconnectInitialBackoff = 100 * time.Millisecond // First Reconnect retry delay, doubled per attempt
+	reconnectMaxBackoff     = 5 * time.Second        // Cap on the Reconnect retry delay
 )
 
+// DaemonClientConfig holds the client's timing settings. Zero fields use the defaults,
+// so callers only set what they need (e.g. longer timeouts over a slow forwarded socket).
+type DaemonClientConfig struct {
+	QueryTimeout      time.Duration // Wait for query responses (DefaultQueryTimeout)
+	HeartbeatInterval time.Duration // Ping period (DefaultHeartbeatInterval)
+	HeartbeatTimeout  time.Duration // Extra wait for a pong before disconnecting (DefaultHeartbeatTimeout)
+	ReorderWindow     time.Duration // Wait for out-of-order messages to fill a gap (DefaultReorderWindow)
+}
+
+// DefaultDaemonClientConfig returns the timing settings used by NewDaemonClient.
+func DefaultDaemonClientConfig() DaemonClientConfig {
+	return DaemonClientConfig{
+		QueryTimeout:      DefaultQueryTimeout,
+		HeartbeatInterval: DefaultHeartbeatInterval,
+		HeartbeatTimeout:  DefaultHeartbeatTimeout,
+		ReorderWindow:     DefaultReorderWindow,
+	}
+}
+
+// withDefaults returns cfg with zero or negative fields replaced by the defaults.
+func (cfg DaemonClientConfig) withDefaults() DaemonClientConfig {
+	if cfg.QueryTimeout <= 0 {
+		cfg.QueryTimeout = DefaultQueryTimeout
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if cfg.HeartbeatTimeout <= 0 {
+		cfg.HeartbeatTimeout = DefaultHeartbeatTimeout
+	}
+	if cfg.ReorderWindow <= 0 {
+		cfg.ReorderWindow = DefaultReorderWindow
+	}
+	return cfg
+}
+
 // queryResponse holds both data and error channels for query responses
 type queryResponse struct {
 	dataCh chan Message // Receives successful responses
@@ -45,7 +93,7 @@ type DaemonClient struct {
 	conn       net.Conn
 	encoder    *json.Encoder
 	encoderMu  sync.Mutex // Protects encoder from concurrent writes
-	decoder    *json.Decoder
+	decoder    messageDecoder
 	eventCh    chan Message
 	done       chan struct{}
 	mu         sync.Mutex
@@ -54,6 +102,9 @@ type DaemonClient struct {
 	lastPongMu sync.RWMutex  // Protects lastPong
 	lastSeq    atomic.Uint64 // Last received sequence number for gap detection
 
+	maxMessageSize int64              // Per-message decode limit (0 = DefaultMaxMessageSize)
+	config         DaemonClientConfig // Timeouts; zero fields use the defaults
+
 	// Health metrics for diagnostics
 	syncWarnings            atomic.Uint64 // Sync warning count
 	resyncFailures          atomic.Uint64 // Failed resync request count
@@ -64,16 +115,32 @@ type DaemonClient struct {
 	// Query response routing (prevents event loss in QueryBlockedState)
 	queryResponses map[string]*queryResponse // Response channels keyed by branch
 	queryMu        sync.Mutex                // Protects queryResponses map
+
+	// Namespace check: the caller's session namespace, compared with the one the daemon
+	// reports in full_state (empty skips the check)
+	namespace    string
+	namespaceErr error // ErrNamespaceMismatch once full_state reports another namespace, under mu
+
+	rejectErr error // ErrConnectionRejected or ErrProtocolMismatch once the daemon refuses this connection, under mu
 }
 
-// NewDaemonClient creates a new daemon client.
+// NewDaemonClient creates a new daemon client with DefaultDaemonClientConfig.
 func NewDaemonClient() *DaemonClient {
+	return NewDaemonClientWithConfig(DefaultDaemonClientConfig())
+}
+
+// NewDaemonClientWithConfig creates a new daemon client with the given timeouts.
+// Zero fields in cfg use the defaults.
+func NewDaemonClientWithConfig(cfg DaemonClientConfig) *DaemonClient {
 	return &DaemonClient{
 		clientID:       uuid.New().String(),
 		socketPath:     namespace.DaemonSocket(),
 		eventCh:        make(chan Message, 100),
 		done:           make(chan struct{}),
 		queryResponses: make(map[string]*queryResponse),
+		maxMessageSize: maxMessageSizeFromEnv(),
+		config:         cfg.withDefaults(),
+		namespace:      namespace.GetSessionNamespace(),
 	}
 }
 
@@ -93,7 +160,39 @@ func (c *DaemonClient) sendAndWait(msg Message) error {
 		return err
 	}
 	time.Sleep(messagePropagationDelay)
-	return nil
+	return c.connectionError()
+}
+
+// checkNamespace records ErrNamespaceMismatch when the daemon's namespace (from
+// full_state) differs from the caller's. Either side being empty skips the check.
+func (c *DaemonClient) checkNamespace(daemonNamespace string) {
+	if c.namespace == "" || daemonNamespace == "" || daemonNamespace == c.namespace {
+		return
+	}
+	err := fmt.Errorf("%w: daemon serves %s, but this shell's $TMUX expects %s",
+		ErrNamespaceMismatch, daemonNamespace, c.namespace)
+	debug.Log("CLIENT_NAMESPACE_MISMATCH id=%s expected=%s actual=%s", c.clientID, c.namespace, daemonNamespace)
+	c.mu.Lock()
+	c.namespaceErr = err
+	c.mu.Unlock()
+}
+
+// protocolMismatchError converts a version_mismatch message into an ErrProtocolMismatch
+// error telling the user how to get the binaries back in step.
+func protocolMismatchError(msg Message) error {
+	return fmt.Errorf("%w: daemon speaks protocol %s, this client %s - update tmux-tui and restart the daemon so both run the same build",
+		ErrProtocolMismatch, msg.ProtocolVersion, ProtocolVersion)
+}
+
+// connectionError returns the error recorded by checkNamespace or a rejected
+// connection, if any.
+func (c *DaemonClient) connectionError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rejectErr != nil {
+		return c.rejectErr
+	}
+	return c.namespaceErr
 }
 
 // updateLastPong updates the timestamp of the last pong received
@@ -118,6 +217,11 @@ func (c *DaemonClient) Connect() error {
 	if c.connected {
 		return nil
 	}
+	select {
+	case <-c.done:
+		return ErrClientClosed
+	default:
+	}
 
 	// Connect to Unix socket
 	conn, err := net.Dial("unix", c.socketPath)
@@ -139,12 +243,15 @@ func (c *DaemonClient) Connect() error {
 
 	c.conn = conn
 	c.encoder = json.NewEncoder(conn)
-	c.decoder = json.NewDecoder(conn)
+	c.decoder = newLimitedDecoder(conn, c.maxMessageSize)
+	c.namespaceErr = nil // Rechecked against this daemon's full_state
+	c.rejectErr = nil
 
 	// Send hello message
 	helloMsg := Message{
-		Type:     MsgTypeHello,
-		ClientID: c.clientID,
+		Type:            MsgTypeHello,
+		ClientID:        c.clientID,
+		ProtocolVersion: ProtocolVersion,
 	}
 	if err := c.sendMessage(helloMsg); err != nil {
 		conn.Close()
@@ -167,29 +274,67 @@ func (c *DaemonClient) Connect() error {
 	return nil
 }
 
-// receive receives messages from the daemon.
+// receive receives messages from the daemon on the current connection. It stops
+// when that connection fails; after a Reconnect, a new receive serves the new one.
+// Sequenced messages are delivered in order: those arriving past a gap are held for
+// the reorder window, and a resync is requested only if the gap isn't filled by then.
 func (c *DaemonClient) receive() {
+	c.mu.Lock()
+	conn, decoder := c.conn, c.decoder
+	c.mu.Unlock()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	msgs, decodeErr := c.decodeMessages(decoder, stop)
+
+	// Messages past a sequence gap wait here briefly in case the missing ones were
+	// only reordered
+	reorder := newReorderBuffer(c.config.withDefaults().ReorderWindow)
+	defer reorder.stop()
+
 	for {
 		var msg Message
-		if err := c.decoder.Decode(&msg); err != nil {
+		select {
+		case err := <-decodeErr:
 			select {
 			case <-c.done:
 				// Client closed - expected
 				return
 			default:
 				debug.Log("CLIENT_RECEIVE_ERROR id=%s error=%v", c.clientID, err)
+				disconnectMsg := Message{Type: "disconnect"}
 				c.mu.Lock()
+				if c.conn != conn {
+					// Superseded by Reconnect, which closed this connection - not a new disconnect
+					c.mu.Unlock()
+					return
+				}
 				c.connected = false
+				if errors.Is(err, ErrMessageTooLarge) {
+					// Stream is mid-message and cannot be resynchronized - drop the connection
+					fmt.Fprintf(os.Stderr, "WARNING: Daemon sent oversized message: %v - disconnecting\n", err)
+					disconnectMsg.Error = err.Error()
+					if c.conn != nil {
+						c.conn.Close()
+					}
+				}
 				c.mu.Unlock()
 
 				// Send disconnect event (context-aware to prevent goroutine leak)
 				select {
-				case c.eventCh <- Message{Type: "disconnect"}:
+				case c.eventCh <- disconnectMsg:
 				case <-c.done:
 					return
 				}
 				return
 			}
+		case <-reorder.expired():
+			// The gap wasn't filled in time: deliver what arrived and resync
+			if !c.flushReorderBuffer(conn, reorder) {
+				return
+			}
+			continue
+		case msg = <-msgs:
 		}
 
 		// Handle pong messages to update heartbeat
@@ -199,183 +344,290 @@ func (c *DaemonClient) receive() {
 			continue
 		}
 
-		// Gap detection: Check sequence numbers for missed messages
+		// Sequencing: messages past a gap are held until the gap fills, the window
+		// expires or the buffer overflows
 		// TODO(#520): Add test for sequence number wraparound from MaxUint64 to 0
 		if msg.SeqNum > 0 {
-			lastSeq := c.lastSeq.Load()
-			if lastSeq > 0 && msg.SeqNum > lastSeq+1 {
-				// Gap detected - request resync from daemon
-				gap := msg.SeqNum - lastSeq - 1
-				debug.Log("CLIENT_GAP_DETECTED id=%s expected=%d got=%d gap=%d",
-					c.clientID, lastSeq+1, msg.SeqNum, gap)
-				// Request full state resync with retry logic
-				go func() {
-					const maxRetries = 3
-					backoff := 50 * time.Millisecond
-					savedGap := gap // Capture for logging
-
-					for attempt := 0; attempt < maxRetries; attempt++ {
-						if attempt > 0 {
-							time.Sleep(backoff)
-							backoff *= 2 // Exponential backoff: 50ms, 100ms, 200ms
-						}
+			ready, lastSeq, overflow := reorder.push(msg, c.lastSeq.Load())
+			c.lastSeq.Store(lastSeq)
+			if overflow && !c.flushReorderBuffer(conn, reorder) {
+				return
+			}
+			for _, m := range ready {
+				if !c.dispatch(conn, m) {
+					return
+				}
+			}
+			continue
+		}
 
-						resyncMsg := Message{Type: MsgTypeResyncRequest}
-						if err := c.sendMessage(resyncMsg); err == nil {
-							debug.Log("CLIENT_RESYNC_REQUESTED id=%s attempt=%d gap=%d", c.clientID, attempt+1, savedGap)
-							return // Success
-						}
+		if !c.dispatch(conn, msg) {
+			return
+		}
+	}
+}
 
-						if attempt == maxRetries-1 {
-							// All retries exhausted - must disconnect
-							c.resyncFailures.Add(1)
-							debug.Log("CLIENT_RESYNC_REQUEST_EXHAUSTED - disconnecting id=%s attempts=%d gap=%d count=%d",
-								c.clientID, maxRetries, savedGap, c.resyncFailures.Load())
-
-							// CRITICAL: Cannot continue with known gaps - disconnect to trigger full reconnect
-							c.mu.Lock()
-							c.connected = false
-							if c.conn != nil {
-								c.conn.Close()
-							}
-							c.mu.Unlock()
-
-							// TODO(#281): Fix channel blocking on disconnect notifications - see PR review for #273
-							// Send disconnect event with timeout to prevent goroutine leak
-							select {
-							case c.eventCh <- Message{Type: "disconnect", Error: fmt.Sprintf("Failed to request resync after %d attempts (gap=%d)", maxRetries, savedGap)}:
-							case <-time.After(100 * time.Millisecond):
-								// CRITICAL: Resync failed and disconnect notification blocked
-								fmt.Fprintf(os.Stderr, "CRITICAL: Resync disconnect blocked - eventCh congested (client=%s, gap=%d)\n",
-									c.clientID, savedGap)
-								debug.Log("CLIENT_RESYNC_DISCONNECT_EVENT_BLOCKED id=%s gap=%d", c.clientID, savedGap)
-							case <-c.done:
-							}
-						}
-					}
-				}()
+// decodeMessages decodes messages from decoder on a separate goroutine, so receive can
+// also wait on the reorder window. The goroutine exits on the first decode error,
+// which it sends on the error channel, or once stop or the client's done is closed.
+func (c *DaemonClient) decodeMessages(decoder messageDecoder, stop <-chan struct{}) (<-chan Message, <-chan error) {
+	msgs := make(chan Message)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			var msg Message
+			if err := decoder.Decode(&msg); err != nil {
+				errs <- err
+				return
 			}
-			c.lastSeq.Store(msg.SeqNum)
+			select {
+			case msgs <- msg:
+			case <-stop:
+				return
+			case <-c.done:
+				return
+			}
+		}
+	}()
+	return msgs, errs
+}
+
+// flushReorderBuffer gives up on the reorder buffer's gap: it delivers the held
+// messages in order and requests a resync for the ones that never arrived. Returns
+// false if receive should stop.
+func (c *DaemonClient) flushReorderBuffer(conn net.Conn, reorder *reorderBuffer) bool {
+	expected := c.lastSeq.Load() + 1
+	ready, lastSeq, missing := reorder.flush(c.lastSeq.Load())
+	c.lastSeq.Store(lastSeq)
+	if missing > 0 {
+		debug.Log("CLIENT_GAP_DETECTED id=%s expected=%d got=%d gap=%d",
+			c.clientID, expected, lastSeq, missing)
+		go c.requestResync(missing)
+	}
+	for _, m := range ready {
+		if !c.dispatch(conn, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestResync asks the daemon for a full_state after a sequence gap, retrying with
+// backoff, and disconnects if every attempt fails.
+func (c *DaemonClient) requestResync(gap uint64) {
+	const maxRetries = 3
+	backoff := 50 * time.Millisecond
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2 // Exponential backoff: 50ms, 100ms, 200ms
 		}
 
-		// Route query responses to dedicated channels to ensure QueryBlockedState() receives them.
-		// Without this routing, responses would be delivered to the general event channel,
-		// requiring the caller to poll Events() instead of receiving a direct return value.
-		// TODO(#281): Use narrow catch blocks for query channel errors
-		// Current: Broad catch block masks channel overflow vs deadlock
-		if msg.Type == MsgTypeBlockedStateResponse {
-			c.queryMu.Lock()
-			if resp, exists := c.queryResponses[msg.Branch]; exists {
+		resyncMsg := Message{Type: MsgTypeResyncRequest}
+		if err := c.sendMessage(resyncMsg); err == nil {
+			debug.Log("CLIENT_RESYNC_REQUESTED id=%s attempt=%d gap=%d", c.clientID, attempt+1, gap)
+			return // Success
+		}
+
+		if attempt == maxRetries-1 {
+			// All retries exhausted - must disconnect
+			c.resyncFailures.Add(1)
+			debug.Log("CLIENT_RESYNC_REQUEST_EXHAUSTED - disconnecting id=%s attempts=%d gap=%d count=%d",
+				c.clientID, maxRetries, gap, c.resyncFailures.Load())
+
+			// CRITICAL: Cannot continue with known gaps - disconnect to trigger full reconnect
+			c.mu.Lock()
+			c.connected = false
+			if c.conn != nil {
+				c.conn.Close()
+			}
+			c.mu.Unlock()
+
+			// TODO(#281): Fix channel blocking on disconnect notifications - see PR review for #273
+			// Send disconnect event with timeout to prevent goroutine leak
+			select {
+			case c.eventCh <- Message{Type: "disconnect", Error: fmt.Sprintf("Failed to request resync after %d attempts (gap=%d)", maxRetries, gap)}:
+			case <-time.After(100 * time.Millisecond):
+				// CRITICAL: Resync failed and disconnect notification blocked
+				fmt.Fprintf(os.Stderr, "CRITICAL: Resync disconnect blocked - eventCh congested (client=%s, gap=%d)\n",
+					c.clientID, gap)
+				debug.Log("CLIENT_RESYNC_DISCONNECT_EVENT_BLOCKED id=%s gap=%d", c.clientID, gap)
+			case <-c.done:
+			}
+		}
+	}
+}
+
+// dispatch handles a message in delivery order: query responses go to their waiting
+// callers and everything else to Events(). Returns false if receive should stop.
+func (c *DaemonClient) dispatch(conn net.Conn, msg Message) bool {
+	// Route query responses to dedicated channels to ensure QueryBlockedState() and
+	// QueryAllBlockedStates() receive them.
+	// Without this routing, responses would be delivered to the general event channel,
+	// requiring the caller to poll Events() instead of receiving a direct return value.
+	// TODO(#281): Use narrow catch blocks for query channel errors
+	// Current: Broad catch block masks channel overflow vs deadlock
+	if queryKey, ok := queryResponseKey(msg); ok {
+		c.queryMu.Lock()
+		if resp, exists := c.queryResponses[queryKey]; exists {
+			select {
+			case resp.dataCh <- msg:
+				// Response delivered successfully
+			case <-c.done:
+				c.queryMu.Unlock()
+				return false
+			default:
+				// Channel full - send error notification to caller
+				// This provides accurate error instead of misleading timeout
+				c.queryChannelFull.Add(1)
+				errMsg := ErrQueryChannelFull
 				select {
-				case resp.dataCh <- msg:
-					// Response delivered successfully
-				case <-c.done:
-					c.queryMu.Unlock()
-					return
+				case resp.errCh <- errMsg:
+					debug.Log("CLIENT_QUERY_CHANNEL_FULL id=%s branch=%s fallback_level=1 total_overflows=%d reason=dataCh_full",
+						c.clientID, queryKey, c.queryChannelFull.Load())
 				default:
-					// Channel full - send error notification to caller
-					// This provides accurate error instead of misleading timeout
-					c.queryChannelFull.Add(1)
-					errMsg := ErrQueryChannelFull
+					// Both channels full - retry once after brief delay before forcing disconnect
+					debug.Log("CLIENT_QUERY_CHANNELS_BOTH_FULL id=%s branch=%s fallback_level=2 total_overflows=%d reason=both_channels_full retrying_after=50ms",
+						c.clientID, queryKey, c.queryChannelFull.Load())
+					time.Sleep(50 * time.Millisecond)
+
 					select {
+					case resp.dataCh <- msg:
+						debug.Log("CLIENT_QUERY_RETRY_SUCCESS id=%s branch=%s fallback_level=2 total_overflows=%d retry_path=dataCh",
+							c.clientID, queryKey, c.queryChannelFull.Load())
 					case resp.errCh <- errMsg:
-						debug.Log("CLIENT_QUERY_CHANNEL_FULL id=%s branch=%s fallback_level=1 total_overflows=%d reason=dataCh_full",
-							c.clientID, msg.Branch, c.queryChannelFull.Load())
+						debug.Log("CLIENT_QUERY_RETRY_SUCCESS id=%s branch=%s fallback_level=2 total_overflows=%d retry_path=errCh",
+							c.clientID, queryKey, c.queryChannelFull.Load())
 					default:
-						// Both channels full - retry once after brief delay before forcing disconnect
-						debug.Log("CLIENT_QUERY_CHANNELS_BOTH_FULL id=%s branch=%s fallback_level=2 total_overflows=%d reason=both_channels_full retrying_after=50ms",
-							c.clientID, msg.Branch, c.queryChannelFull.Load())
-						time.Sleep(50 * time.Millisecond)
+						// CRITICAL: Still deadlocked after retry - force disconnect
+						c.queryDeadlockRecoveries.Add(1)
+						c.lastDeadlockBranch.Store(queryKey)
+
+						errMsg := fmt.Sprintf("Query channel deadlock for branch %s - forcing disconnect (total deadlocks: %d)",
+							queryKey, c.queryDeadlockRecoveries.Load())
+
+						// ERROR VISIBILITY: Make deadlock visible to users
+						fmt.Fprintf(os.Stderr, "ERROR: %s\n", errMsg)
+						fmt.Fprintf(os.Stderr, "  Client ID: %s\n", c.clientID)
+						fmt.Fprintf(os.Stderr, "  This indicates a severe congestion issue - daemon will reconnect and resync\n")
+
+						debug.Log("CLIENT_QUERY_DEADLOCK_CONFIRMED id=%s branch=%s fallback_level=3 total_overflows=%d total_deadlocks=%d reason=retry_failed_both_channels_still_full action=forcing_disconnect",
+							c.clientID, queryKey, c.queryChannelFull.Load(), c.queryDeadlockRecoveries.Load())
+
+						c.mu.Lock()
+						c.connected = false
+						if c.conn != nil {
+							c.conn.Close()
+						}
+						c.mu.Unlock()
 
+						// Send disconnect event with timeout to prevent goroutine leak
+						// TODO(#281): Make disconnect notification always visible
 						select {
-						case resp.dataCh <- msg:
-							debug.Log("CLIENT_QUERY_RETRY_SUCCESS id=%s branch=%s fallback_level=2 total_overflows=%d retry_path=dataCh",
-								c.clientID, msg.Branch, c.queryChannelFull.Load())
-						case resp.errCh <- errMsg:
-							debug.Log("CLIENT_QUERY_RETRY_SUCCESS id=%s branch=%s fallback_level=2 total_overflows=%d retry_path=errCh",
-								c.clientID, msg.Branch, c.queryChannelFull.Load())
-						default:
-							// CRITICAL: Still deadlocked after retry - force disconnect
-							c.queryDeadlockRecoveries.Add(1)
-							c.lastDeadlockBranch.Store(msg.Branch)
-
-							errMsg := fmt.Sprintf("Query channel deadlock for branch %s - forcing disconnect (total deadlocks: %d)",
-								msg.Branch, c.queryDeadlockRecoveries.Load())
-
-							// ERROR VISIBILITY: Make deadlock visible to users
-							fmt.Fprintf(os.Stderr, "ERROR: %s\n", errMsg)
-							fmt.Fprintf(os.Stderr, "  Client ID: %s\n", c.clientID)
-							fmt.Fprintf(os.Stderr, "  This indicates a severe congestion issue - daemon will reconnect and resync\n")
-
-							debug.Log("CLIENT_QUERY_DEADLOCK_CONFIRMED id=%s branch=%s fallback_level=3 total_overflows=%d total_deadlocks=%d reason=retry_failed_both_channels_still_full action=forcing_disconnect",
-								c.clientID, msg.Branch, c.queryChannelFull.Load(), c.queryDeadlockRecoveries.Load())
-
-							c.mu.Lock()
-							c.connected = false
-							if c.conn != nil {
-								c.conn.Close()
-							}
-							c.mu.Unlock()
-
-							// Send disconnect event with timeout to prevent goroutine leak
-							// TODO(#281): Make disconnect notification always visible
-							select {
-							case c.eventCh <- Message{
-								Type:  "disconnect",
-								Error: errMsg,
-							}:
-							case <-time.After(100 * time.Millisecond):
-								// CRITICAL: eventCh blocked - disconnect notification lost
-								fmt.Fprintf(os.Stderr, "CRITICAL: Client disconnect blocked - eventCh congested (client=%s, branch=%s, deadlocks=%d)\n",
-									c.clientID, msg.Branch, c.queryDeadlockRecoveries.Load())
-								debug.Log("CLIENT_DISCONNECT_EVENT_BLOCKED id=%s branch=%s - event channel full",
-									c.clientID, msg.Branch)
-							case <-c.done:
-								return
-							}
-							return // Exit receive() goroutine
+						case c.eventCh <- Message{
+							Type:  "disconnect",
+							Error: errMsg,
+						}:
+						case <-time.After(100 * time.Millisecond):
+							// CRITICAL: eventCh blocked - disconnect notification lost
+							fmt.Fprintf(os.Stderr, "CRITICAL: Client disconnect blocked - eventCh congested (client=%s, branch=%s, deadlocks=%d)\n",
+								c.clientID, queryKey, c.queryDeadlockRecoveries.Load())
+							debug.Log("CLIENT_DISCONNECT_EVENT_BLOCKED id=%s branch=%s - event channel full",
+								c.clientID, queryKey)
+						case <-c.done:
+							return false
 						}
+						return false // Exit receive() goroutine
 					}
 				}
-				delete(c.queryResponses, msg.Branch)
-				c.queryMu.Unlock()
-				continue // Don't forward to eventCh
 			}
+			delete(c.queryResponses, queryKey)
 			c.queryMu.Unlock()
-			debug.Log("CLIENT_QUERY_RESPONSE_UNREGISTERED id=%s branch=%s", c.clientID, msg.Branch)
+			return true // Don't forward to eventCh
 		}
+		c.queryMu.Unlock()
+		debug.Log("CLIENT_QUERY_RESPONSE_UNREGISTERED id=%s branch=%s", c.clientID, queryKey)
+	}
 
-		// Handle sync warnings - log but don't forward to avoid client disruption
-		if msg.Type == MsgTypeSyncWarning {
-			c.syncWarnings.Add(1)
-			debug.Log("CLIENT_SYNC_WARNING id=%s warning=%s count=%d",
-				c.clientID, msg.Error, c.syncWarnings.Load())
-			continue // Skip forwarding to eventCh
+	if msg.Type == MsgTypeFullState {
+		c.checkNamespace(msg.Namespace)
+	}
+
+	// Rejected instead of registered: the daemon closes the connection, so forward the
+	// reason in place of the generic disconnect that would follow
+	if msg.Type == MsgTypeRejected {
+		err := fmt.Errorf("%w: %s", ErrConnectionRejected, msg.Error)
+		debug.Log("CLIENT_REJECTED id=%s reason=%s", c.clientID, msg.Error)
+		c.mu.Lock()
+		if c.conn == conn {
+			c.connected = false
+			c.rejectErr = err
+			conn.Close()
+		}
+		c.mu.Unlock()
+		select {
+		case c.eventCh <- msg:
+		case <-c.done:
 		}
+		return false
+	}
 
-		// Forward message to event channel
+	// Same for a daemon that doesn't speak this client's protocol version
+	if msg.Type == MsgTypeVersionMismatch {
+		err := protocolMismatchError(msg)
+		debug.Log("CLIENT_VERSION_MISMATCH id=%s daemon_protocol=%s", c.clientID, msg.ProtocolVersion)
+		c.mu.Lock()
+		if c.conn == conn {
+			c.connected = false
+			c.rejectErr = err
+			conn.Close()
+		}
+		c.mu.Unlock()
 		select {
 		case c.eventCh <- msg:
 		case <-c.done:
-			return
 		}
+		return false
 	}
+
+	// Handle sync warnings - log but don't forward to avoid client disruption
+	if msg.Type == MsgTypeSyncWarning {
+		c.syncWarnings.Add(1)
+		debug.Log("CLIENT_SYNC_WARNING id=%s warning=%s count=%d",
+			c.clientID, msg.Error, c.syncWarnings.Load())
+		return true // Skip forwarding to eventCh
+	}
+
+	// Forward message to event channel
+	select {
+	case c.eventCh <- msg:
+	case <-c.done:
+		return false
+	}
+	return true
 }
 
 // heartbeat periodically sends ping messages and monitors for pong responses.
 // If no pong is received within the timeout period, it triggers a disconnect.
+// It stops with the connection it was started for.
 func (c *DaemonClient) heartbeat() {
-	ticker := time.NewTicker(heartbeatInterval)
+	cfg := c.config.withDefaults()
+	ticker := time.NewTicker(cfg.HeartbeatInterval)
 	defer ticker.Stop()
 
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
 	for {
 		select {
 		case <-c.done:
 			return
 		case <-ticker.C:
-			// Check if we're still connected
+			// Check if we're still connected, on the same connection
 			c.mu.Lock()
-			connected := c.connected
+			connected := c.connected && c.conn == conn
 			c.mu.Unlock()
 
 			if !connected {
@@ -385,7 +637,7 @@ func (c *DaemonClient) heartbeat() {
 			// Check if last pong is too old
 			lastPong := c.getLastPong()
 			timeSinceLastPong := time.Since(lastPong)
-			if timeSinceLastPong > heartbeatInterval+heartbeatTimeout {
+			if timeSinceLastPong > cfg.HeartbeatInterval+cfg.HeartbeatTimeout {
 				debug.Log("CLIENT_HEARTBEAT_TIMEOUT id=%s since_last_pong=%v", c.clientID, timeSinceLastPong)
 				c.mu.Lock()
 				c.connected = false
@@ -440,13 +692,12 @@ func (c *DaemonClient) Close() error {
 
 	c.mu.Lock()
 	wasConnected := c.connected
-	if wasConnected {
-		select {
-		case <-c.done:
-			// Already closed
-		default:
-			close(c.done)
-		}
+	// Close done even when disconnected, so a Reconnect in progress stops retrying
+	select {
+	case <-c.done:
+		// Already closed
+	default:
+		close(c.done)
 	}
 	c.connected = false
 
@@ -516,55 +767,222 @@ func (c *DaemonClient) ConnectWithRetry(ctx context.Context, maxRetries int) err
 	return fmt.Errorf("failed to connect after %d attempts", maxRetries)
 }
 
+// Reconnect re-establishes the connection after a "disconnect" event, retrying with
+// exponential backoff (100ms doubling to 5s) until it succeeds, ctx is cancelled or
+// Close is called (ErrClientClosed). The daemon answers the new hello with a
+// full_state on Events(), which resynchronizes the caller's state.
+func (c *DaemonClient) Reconnect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.connected {
+		c.mu.Unlock()
+		return nil
+	}
+	// Drop the failed connection; its receive goroutine exits without another disconnect
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.mu.Unlock()
+
+	// The daemon may have restarted with a fresh sequence counter
+	c.lastSeq.Store(0)
+
+	backoff := reconnectInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.Connect()
+		if err == nil {
+			debug.Log("CLIENT_RECONNECTED id=%s attempts=%d", c.clientID, attempt)
+			return nil
+		}
+		if errors.Is(err, ErrClientClosed) {
+			return err
+		}
+		debug.Log("CLIENT_RECONNECT_RETRY id=%s attempt=%d backoff=%v error=%v", c.clientID, attempt, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-c.done:
+			return ErrClientClosed
+		case <-ctx.Done():
+			return fmt.Errorf("reconnect cancelled after %d attempts: %w", attempt, ctx.Err())
+		}
+
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
 // RequestBlockPicker sends a request to show the block picker for a pane
 func (c *DaemonClient) RequestBlockPicker(paneID string) error {
+	return c.RequestBlockPickerWithTTL(paneID, 0)
+}
+
+// RequestBlockPickerWithTTL is RequestBlockPicker for a block that the daemon removes
+// after ttl (0 = never expires)
+func (c *DaemonClient) RequestBlockPickerWithTTL(paneID string, ttl time.Duration) error {
 	msg := Message{
 		Type:   MsgTypeShowBlockPicker,
 		PaneID: paneID,
+		TTL:    ttl,
 	}
 	if err := c.sendAndWait(msg); err != nil {
 		return fmt.Errorf("failed to send show block picker message: %w", err)
 	}
-	debug.Log("CLIENT_REQUEST_BLOCK_PICKER id=%s paneID=%s", c.clientID, paneID)
+	debug.Log("CLIENT_REQUEST_BLOCK_PICKER id=%s paneID=%s ttl=%v", c.clientID, paneID, ttl)
 	return nil
 }
 
 // BlockBranch sends a request to block a branch with another branch
 func (c *DaemonClient) BlockBranch(branch, blockedByBranch string) error {
+	return c.BlockBranchWithTTL(branch, blockedByBranch, 0)
+}
+
+// BlockBranchWithTTL sends a request to block a branch with another branch until ttl
+// has passed (0 = never expires). Blocking an already blocked pair replaces its expiry.
+func (c *DaemonClient) BlockBranchWithTTL(branch, blockedByBranch string, ttl time.Duration) error {
 	msg := Message{
 		Type:          MsgTypeBlockBranch,
 		Branch:        branch,
 		BlockedBranch: blockedByBranch,
+		TTL:           ttl,
 	}
 	if err := c.sendAndWait(msg); err != nil {
 		return fmt.Errorf("failed to send block branch message: %w", err)
 	}
-	debug.Log("CLIENT_BLOCK_BRANCH id=%s branch=%s blockedBy=%s", c.clientID, branch, blockedByBranch)
+	debug.Log("CLIENT_BLOCK_BRANCH id=%s branch=%s blockedBy=%s ttl=%v", c.clientID, branch, blockedByBranch, ttl)
 	return nil
 }
 
-// UnblockBranch sends a request to unblock a branch
+// UnblockBranch sends a request to unblock a branch, removing all of its blockers
 func (c *DaemonClient) UnblockBranch(branch string) error {
+	return c.UnblockBranchFrom(branch, "")
+}
+
+// UnblockBranchFrom sends a request to remove one blocker from a branch. The branch
+// stays blocked if other branches still block it. An empty blockedByBranch removes
+// all blockers, like UnblockBranch.
+func (c *DaemonClient) UnblockBranchFrom(branch, blockedByBranch string) error {
 	msg := Message{
-		Type:   MsgTypeUnblockBranch,
-		Branch: branch,
+		Type:          MsgTypeUnblockBranch,
+		Branch:        branch,
+		BlockedBranch: blockedByBranch,
 	}
 	if err := c.sendAndWait(msg); err != nil {
 		return fmt.Errorf("failed to send unblock branch message: %w", err)
 	}
-	debug.Log("CLIENT_UNBLOCK_BRANCH id=%s branch=%s", c.clientID, branch)
+	debug.Log("CLIENT_UNBLOCK_BRANCH id=%s branch=%s blockedBy=%s", c.clientID, branch, blockedByBranch)
 	return nil
 }
 
-// QueryBlockedState queries whether a branch is blocked and returns the blocking branch if so
+// UnblockAll sends a request to clear every blocked branch
+func (c *DaemonClient) UnblockAll() error {
+	if err := c.sendAndWait(Message{Type: MsgTypeUnblockAll}); err != nil {
+		return fmt.Errorf("failed to send unblock all message: %w", err)
+	}
+	debug.Log("CLIENT_UNBLOCK_ALL id=%s", c.clientID)
+	return nil
+}
+
+// RenameBranch asks the daemon to move oldBranch's blocks to newBranch after a git
+// branch rename and waits for the outcome. Returns the daemon's reason if it refused,
+// e.g. because oldBranch has no blocks or newBranch already has some.
+func (c *DaemonClient) RenameBranch(oldBranch, newBranch string) error {
+	renameMsg, err := NewRenameBranchMessage(0, oldBranch, newBranch)
+	if err != nil {
+		return err
+	}
+	debug.Log("CLIENT_RENAME_BRANCH id=%s old=%s new=%s", c.clientID, renameMsg.OldBranch(), renameMsg.NewBranch())
+	msg, err := c.awaitQuery(renameQueryKey(renameMsg.OldBranch()), renameMsg.ToWireFormat())
+	if err != nil {
+		return err
+	}
+	if msg.Error != "" {
+		return errors.New(msg.Error)
+	}
+	return nil
+}
+
+// RequestResync asks the daemon for a full_state even if the state hasn't changed
+// since the last one this client received. The resyncs sent automatically on sequence
+// gaps are skipped by the daemon in that case.
+func (c *DaemonClient) RequestResync() error {
+	if err := c.sendMessage(Message{Type: MsgTypeResyncRequest, Force: true}); err != nil {
+		return fmt.Errorf("failed to send resync request: %w", err)
+	}
+	debug.Log("CLIENT_RESYNC_REQUESTED id=%s force=true", c.clientID)
+	return nil
+}
+
+// QueryBlockedState queries whether a branch is blocked and returns the blocking branches if so
 func (c *DaemonClient) QueryBlockedState(branch string) (BlockedState, error) {
+	queryMsg := Message{
+		Type:   MsgTypeQueryBlockedState,
+		Branch: branch,
+	}
+	debug.Log("CLIENT_QUERY_BLOCKED_STATE id=%s branch=%s", c.clientID, branch)
+	msg, err := c.awaitQuery(branch, queryMsg)
+	if err != nil {
+		return BlockedState{}, err
+	}
+
+	debug.Log("CLIENT_BLOCKED_STATE_RESPONSE id=%s branch=%s isBlocked=%v blockedBy=%v",
+		c.clientID, branch, msg.IsBlocked, msg.Blockers)
+	blockers := msg.Blockers
+	if len(blockers) == 0 {
+		blockers = []string{msg.BlockedBranch} // daemon predates Blockers
+	}
+	return NewBlockedState(msg.IsBlocked, blockers...)
+}
+
+// QueryAllBlockedStates returns every blocked branch (branch -> blocking branches) in a
+// single round trip, with the same timeout and response routing as QueryBlockedState.
+func (c *DaemonClient) QueryAllBlockedStates() (BlockMap, error) {
+	debug.Log("CLIENT_QUERY_ALL_BLOCKED id=%s", c.clientID)
+	msg, err := c.awaitQuery(allBlockedQueryKey, Message{Type: MsgTypeQueryAllBlocked})
+	if err != nil {
+		return nil, err
+	}
+
+	debug.Log("CLIENT_ALL_BLOCKED_RESPONSE id=%s count=%d", c.clientID, len(msg.BlockedBranches))
+	if msg.BlockedBranches == nil {
+		return make(BlockMap), nil
+	}
+	return msg.BlockedBranches, nil
+}
+
+// allBlockedQueryKey registers query_all_blocked in queryResponses. Git forbids ':' in
+// branch names, so it can't collide with a QueryBlockedState registration.
+const allBlockedQueryKey = ":all"
+
+// renameQueryKey registers a rename_branch request in queryResponses; the ':' keeps it
+// apart from QueryBlockedState registrations like allBlockedQueryKey.
+func renameQueryKey(oldBranch string) string { return ":rename:" + oldBranch }
+
+// queryResponseKey returns the queryResponses key a query response is routed to.
+// ok is false for messages that aren't query responses.
+func queryResponseKey(msg Message) (key string, ok bool) {
+	switch msg.Type {
+	case MsgTypeBlockedStateResponse:
+		return msg.Branch, true
+	case MsgTypeAllBlockedResponse:
+		return allBlockedQueryKey, true
+	case MsgTypeRenameBranchResult:
+		return renameQueryKey(msg.Branch), true
+	}
+	return "", false
+}
+
+// awaitQuery sends query and waits for the response that receive() routes to key.
+func (c *DaemonClient) awaitQuery(key string, query Message) (Message, error) {
 	// Create response channels (buffered to prevent blocking)
 	resp := newQueryResponse()
 
 	// Register for response
 	c.queryMu.Lock()
 	// Map already initialized in constructor
-	c.queryResponses[branch] = resp
+	c.queryResponses[key] = resp
 	c.queryMu.Unlock()
 
 	// Cleanup registration
@@ -573,40 +991,39 @@ func (c *DaemonClient) QueryBlockedState(branch string) (BlockedState, error) {
 	// then we close it here, then receive() tries to send → panic on closed channel.
 	defer func() {
 		c.queryMu.Lock()
-		delete(c.queryResponses, branch)
+		delete(c.queryResponses, key)
 		close(resp.dataCh)
 		close(resp.errCh)
 		c.queryMu.Unlock()
 	}()
 
-	// Send query message
-	queryMsg := Message{
-		Type:   MsgTypeQueryBlockedState,
-		Branch: branch,
-	}
-	if err := c.sendMessage(queryMsg); err != nil {
-		return BlockedState{}, fmt.Errorf("failed to send query blocked state message: %w", err)
+	if err := c.sendMessage(query); err != nil {
+		return Message{}, fmt.Errorf("failed to send %s message: %w", query.Type, err)
 	}
-	debug.Log("CLIENT_QUERY_BLOCKED_STATE id=%s branch=%s", c.clientID, branch)
 
 	// Wait for response on dedicated channel to prevent race conditions.
 	// Without this dedicated channel, the response could be consumed by the
-	// general receive() loop before QueryBlockedState returns it to the caller.
+	// general receive() loop before the query returns it to the caller.
 	// Timeout prevents indefinite blocking if daemon is unresponsive.
-	timeout := time.After(2 * time.Second)
+	timeout := time.After(c.config.withDefaults().QueryTimeout)
 	select {
 	case msg := <-resp.dataCh:
-		debug.Log("CLIENT_BLOCKED_STATE_RESPONSE id=%s branch=%s isBlocked=%v blockedBy=%s",
-			c.clientID, branch, msg.IsBlocked, msg.BlockedBranch)
-		return NewBlockedState(msg.IsBlocked, msg.BlockedBranch)
+		// The daemon sends full_state before answering anything, so the check has run
+		if err := c.connectionError(); err != nil {
+			return Message{}, err
+		}
+		return msg, nil
 	case queryErr := <-resp.errCh:
 		// Receive() detected an issue (channel full/closed) and notified us
-		debug.Log("CLIENT_QUERY_ERROR id=%s branch=%s error=%v", c.clientID, branch, queryErr)
-		return BlockedState{}, fmt.Errorf("query failed: %w", queryErr)
+		debug.Log("CLIENT_QUERY_ERROR id=%s key=%s error=%v", c.clientID, key, queryErr)
+		return Message{}, fmt.Errorf("query failed: %w", queryErr)
 	case <-timeout:
-		return BlockedState{}, ErrQueryTimeout
+		if err := c.connectionError(); err != nil {
+			return Message{}, err // Rejected connections never answer
+		}
+		return Message{}, ErrQueryTimeout
 	case <-c.done:
-		return BlockedState{}, fmt.Errorf("client closed")
+		return Message{}, fmt.Errorf("client closed")
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
@@ -133,6 +134,113 @@ func TestQueryBlockedState_Success(t *testing.T) {
 	}
 }
 
+// TestQueryBlockedState_NamespaceMismatch tests that a daemon reporting another session
+// namespace in full_state fails queries with ErrNamespaceMismatch, naming both namespaces.
+func TestQueryBlockedState_NamespaceMismatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		daemonNamespace string
+		wantMismatch    bool
+	}{
+		{"same namespace", "/tmp/claude/default", false},
+		{"daemon predates the check", "", false},
+		{"different tmux server", "/tmp/claude/other", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientReader, serverWriter := io.Pipe()
+			serverReader, clientWriter := io.Pipe()
+			defer serverWriter.Close()
+
+			client := &DaemonClient{
+				clientID:       "test-client",
+				conn:           &mockConn{reader: clientReader, writer: clientWriter, localAddr: &mockAddr{"unix", "/tmp/test.sock"}, remoteAddr: &mockAddr{"unix", "/tmp/test.sock"}},
+				encoder:        json.NewEncoder(clientWriter),
+				decoder:        json.NewDecoder(clientReader),
+				eventCh:        make(chan Message, 100),
+				done:           make(chan struct{}),
+				lastPong:       time.Now(),
+				queryResponses: make(map[string]*queryResponse),
+				namespace:      "/tmp/claude/default",
+			}
+			go client.receive()
+			defer client.Close()
+
+			go func() {
+				decoder := json.NewDecoder(serverReader)
+				encoder := json.NewEncoder(serverWriter)
+				encoder.Encode(Message{Type: MsgTypeFullState, Namespace: tt.daemonNamespace})
+				var queryMsg Message
+				if err := decoder.Decode(&queryMsg); err != nil {
+					return
+				}
+				encoder.Encode(Message{Type: MsgTypeBlockedStateResponse, Branch: queryMsg.Branch})
+			}()
+
+			_, err := client.QueryBlockedState("feature-branch")
+			if got := errors.Is(err, ErrNamespaceMismatch); got != tt.wantMismatch {
+				t.Fatalf("QueryBlockedState() error = %v, want mismatch %v", err, tt.wantMismatch)
+			}
+			if !tt.wantMismatch {
+				if err != nil {
+					t.Fatalf("QueryBlockedState failed: %v", err)
+				}
+				return
+			}
+			for _, ns := range []string{"/tmp/claude/other", "/tmp/claude/default"} {
+				if !strings.Contains(err.Error(), ns) {
+					t.Errorf("Error %q should name namespace %s", err, ns)
+				}
+			}
+		})
+	}
+}
+
+// TestReceive_Rejected tests that a rejected connection is forwarded as the rejection
+// (not a generic disconnect) and fails later calls with ErrConnectionRejected.
+func TestReceive_Rejected(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	_, clientWriter := io.Pipe()
+
+	client := &DaemonClient{
+		clientID:       "test-client",
+		conn:           &mockConn{reader: clientReader, writer: clientWriter, localAddr: &mockAddr{"unix", "/tmp/test.sock"}, remoteAddr: &mockAddr{"unix", "/tmp/test.sock"}},
+		encoder:        json.NewEncoder(clientWriter),
+		decoder:        json.NewDecoder(clientReader),
+		eventCh:        make(chan Message, 100),
+		done:           make(chan struct{}),
+		lastPong:       time.Now(),
+		queryResponses: make(map[string]*queryResponse),
+		connected:      true,
+	}
+	go client.receive()
+	defer client.Close()
+
+	go json.NewEncoder(serverWriter).Encode(Message{Type: MsgTypeRejected, Error: "client limit reached"})
+
+	select {
+	case msg := <-client.Events():
+		if msg.Type != MsgTypeRejected || msg.Error != "client limit reached" {
+			t.Fatalf("Expected rejected event with reason, got %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("No event for rejected connection")
+	}
+	if client.IsConnected() {
+		t.Error("Client should be disconnected after rejection")
+	}
+	if err := client.connectionError(); !errors.Is(err, ErrConnectionRejected) || !strings.Contains(err.Error(), "client limit reached") {
+		t.Errorf("connectionError() = %v, want ErrConnectionRejected with reason", err)
+	}
+
+	// No generic disconnect follows the rejection
+	select {
+	case msg := <-client.Events():
+		t.Errorf("Unexpected event after rejection: %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // TestQueryBlockedState_Timeout tests timeout behavior
 func TestQueryBlockedState_Timeout(t *testing.T) {
 	// Create a pipe but don't send any response
@@ -153,6 +261,7 @@ func TestQueryBlockedState_Timeout(t *testing.T) {
 		done:           make(chan struct{}),
 		lastPong:       time.Now(),
 		queryResponses: make(map[string]*queryResponse),
+		config:         DaemonClientConfig{QueryTimeout: 200 * time.Millisecond},
 	}
 
 	// Start receive goroutine
@@ -167,7 +276,7 @@ func TestQueryBlockedState_Timeout(t *testing.T) {
 		serverWriter.Close() // Close to prevent receive() from blocking
 	}()
 
-	// Query should timeout after 2 seconds
+	// Query should timeout after the configured 200ms
 	start := time.Now()
 	_, err := client.QueryBlockedState("feature-branch")
 	elapsed := time.Since(start)
@@ -176,11 +285,11 @@ func TestQueryBlockedState_Timeout(t *testing.T) {
 		t.Fatal("Expected timeout error")
 	}
 
-	if elapsed < 2*time.Second {
+	if elapsed < 200*time.Millisecond {
 		t.Errorf("Timeout occurred too quickly: %v", elapsed)
 	}
 
-	if elapsed > 3*time.Second {
+	if elapsed > time.Second {
 		t.Errorf("Timeout took too long: %v", elapsed)
 	}
 }
@@ -726,6 +835,7 @@ func TestQueryBlockedState_TimeoutUsesDefinedError(t *testing.T) {
 		done:           make(chan struct{}),
 		lastPong:       time.Now(),
 		queryResponses: make(map[string]*queryResponse),
+		config:         DaemonClientConfig{QueryTimeout: 100 * time.Millisecond},
 	}
 
 	go client.receive()
@@ -835,7 +945,7 @@ func TestGapDetection_TriggersResync(t *testing.T) {
 			Type:            MsgTypeFullState,
 			SeqNum:          6,
 			Alerts:          map[string]string{"pane1": "alert1", "pane2": "alert2"},
-			BlockedBranches: map[string]string{"branch1": "main"},
+			BlockedBranches: BlockMap{"branch1": {"main"}},
 		}
 		if err := encoder.Encode(fullState); err != nil {
 			t.Logf("Server encode error: %v", err)
@@ -1052,6 +1162,7 @@ func TestHeartbeat_TimeoutDisconnect(t *testing.T) {
 	serverReader, clientWriter := io.Pipe()
 
 	// Create client with stale lastPong (10 seconds ago, well past heartbeat timeout)
+	// and short heartbeat timings so the check runs within milliseconds
 	client := &DaemonClient{
 		clientID: "test-client",
 		conn: &mockConn{
@@ -1067,6 +1178,10 @@ func TestHeartbeat_TimeoutDisconnect(t *testing.T) {
 		lastPong:       time.Now().Add(-10 * time.Second), // Stale pong - 10 seconds ago
 		queryResponses: make(map[string]*queryResponse),
 		connected:      true,
+		config: DaemonClientConfig{
+			HeartbeatInterval: 50 * time.Millisecond,
+			HeartbeatTimeout:  50 * time.Millisecond,
+		},
 	}
 
 	// Start receive goroutine (reads ping but doesn't send pong - timeout scenario)
@@ -1087,10 +1202,8 @@ func TestHeartbeat_TimeoutDisconnect(t *testing.T) {
 		}
 	}()
 
-	// Wait for disconnect event
-	// Heartbeat ticker runs every 5 seconds, so we need to wait at least that long
-	// Plus some margin for the check to complete
-	timeout := time.After(7 * time.Second)
+	// Wait for disconnect event: the first tick (50ms) sees the stale pong
+	timeout := time.After(time.Second)
 	select {
 	case msg := <-client.eventCh:
 		if msg.Type != "disconnect" {
@@ -1109,6 +1222,22 @@ func TestHeartbeat_TimeoutDisconnect(t *testing.T) {
 	close(client.done)
 }
 
+func TestDaemonClientConfig_Defaults(t *testing.T) {
+	client := NewDaemonClientWithConfig(DaemonClientConfig{QueryTimeout: 10 * time.Second})
+	want := DaemonClientConfig{
+		QueryTimeout:      10 * time.Second,
+		HeartbeatInterval: DefaultHeartbeatInterval,
+		HeartbeatTimeout:  DefaultHeartbeatTimeout,
+		ReorderWindow:     DefaultReorderWindow,
+	}
+	if client.config != want {
+		t.Errorf("config = %+v, want %+v", client.config, want)
+	}
+	if got := NewDaemonClient().config; got != DefaultDaemonClientConfig() {
+		t.Errorf("NewDaemonClient config = %+v, want defaults", got)
+	}
+}
+
 // TestBlockBranch_EncoderFailure tests that BlockBranch returns error when encoder fails
 func TestBlockBranch_EncoderFailure(t *testing.T) {
 	// Create a pipe and immediately close the writer to cause send failure
@@ -1365,7 +1494,7 @@ func TestGapDetection_OutOfOrderMessages(t *testing.T) {
 		t.Errorf("Expected resync request, got %s", resyncMsg.Type)
 	}
 
-	// Send message with seqnum 3 (out of order, but no gap since lastSeq is now 5)
+	// Send message with seqnum 3 (late: the window for the 1..5 gap has passed)
 	encoder.Encode(Message{Type: MsgTypeAlertChange, SeqNum: 3, PaneID: "pane3", EventType: "stop"})
 
 	// Wait briefly - should NOT trigger another resync
@@ -1388,9 +1517,23 @@ func TestGapDetection_OutOfOrderMessages(t *testing.T) {
 		// Timeout is expected - no resync request
 	}
 
-	// Verify lastSeq is 3 (updated from the last message)
-	if client.lastSeq.Load() != 3 {
-		t.Errorf("Expected lastSeq=3, got %d", client.lastSeq.Load())
+	// Verify the late message didn't move lastSeq back, which would mask a later gap
+	if client.lastSeq.Load() != 5 {
+		t.Errorf("Expected lastSeq=5, got %d", client.lastSeq.Load())
+	}
+
+	// The late message is still delivered, after the ones already in order
+	var got []uint64
+	for len(got) < 3 {
+		select {
+		case msg := <-client.eventCh:
+			got = append(got, msg.SeqNum)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for messages, got %v", got)
+		}
+	}
+	if got[0] != 1 || got[1] != 5 || got[2] != 3 {
+		t.Errorf("Expected delivery order [1 5 3], got %v", got)
 	}
 
 	// Clean up
@@ -1399,6 +1542,73 @@ func TestGapDetection_OutOfOrderMessages(t *testing.T) {
 	clientWriter.Close()
 }
 
+// TestGapDetection_ReorderWithinWindow tests that messages reordered within the
+// reorder window are delivered in sequence without a resync
+func TestGapDetection_ReorderWithinWindow(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	client := &DaemonClient{
+		clientID: "test-client",
+		conn: &mockConn{
+			reader:     clientReader,
+			writer:     clientWriter,
+			localAddr:  &mockAddr{"unix", "/tmp/test.sock"},
+			remoteAddr: &mockAddr{"unix", "/tmp/test.sock"},
+		},
+		encoder:   json.NewEncoder(clientWriter),
+		decoder:   json.NewDecoder(clientReader),
+		eventCh:   make(chan Message, 100),
+		done:      make(chan struct{}),
+		connected: true,
+		lastPong:  time.Now(),
+		config:    DaemonClientConfig{ReorderWindow: time.Second},
+	}
+
+	go client.receive()
+
+	encoder := json.NewEncoder(serverWriter)
+	for _, seq := range []uint64{1, 3, 4, 2, 5} {
+		encoder.Encode(Message{Type: MsgTypeAlertChange, SeqNum: seq, PaneID: fmt.Sprintf("pane%d", seq)})
+	}
+
+	var got []uint64
+	for len(got) < 5 {
+		select {
+		case msg := <-client.eventCh:
+			got = append(got, msg.SeqNum)
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("Timed out before the window expired, got %v", got)
+		}
+	}
+	for i, seq := range got {
+		if seq != uint64(i+1) {
+			t.Fatalf("Expected messages in sequence 1-5, got %v", got)
+		}
+	}
+	if client.lastSeq.Load() != 5 {
+		t.Errorf("Expected lastSeq=5, got %d", client.lastSeq.Load())
+	}
+
+	// The gap was filled, so no resync is requested
+	resync := make(chan Message, 1)
+	go func() {
+		var msg Message
+		if json.NewDecoder(serverReader).Decode(&msg) == nil {
+			resync <- msg
+		}
+	}()
+	select {
+	case msg := <-resync:
+		t.Errorf("Expected no message to the daemon, got %s", msg.Type)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(client.done)
+	serverWriter.Close()
+	clientWriter.Close()
+}
+
 // TestGapDetection_RapidMultipleGaps tests multiple gaps in quick succession
 func TestGapDetection_RapidMultipleGaps(t *testing.T) {
 	clientReader, serverWriter := io.Pipe()
@@ -1601,7 +1811,7 @@ func TestGapDetection_FullStateWithGap_NoInfiniteLoop(t *testing.T) {
 		Type:            MsgTypeFullState,
 		SeqNum:          30,
 		Alerts:          map[string]string{"pane1": "stop"},
-		BlockedBranches: map[string]string{},
+		BlockedBranches: BlockMap{},
 	})
 	time.Sleep(50 * time.Millisecond)
 
@@ -1620,7 +1830,7 @@ func TestGapDetection_FullStateWithGap_NoInfiniteLoop(t *testing.T) {
 		Type:            MsgTypeFullState,
 		SeqNum:          31,
 		Alerts:          map[string]string{"pane1": "stop", "pane2": "start"},
-		BlockedBranches: map[string]string{},
+		BlockedBranches: BlockMap{},
 	})
 	time.Sleep(100 * time.Millisecond)
 
@@ -1697,7 +1907,7 @@ func TestGapDetection_FullStateResetsSequence(t *testing.T) {
 		Type:            MsgTypeFullState,
 		SeqNum:          4,
 		Alerts:          map[string]string{"pane1": "stop"},
-		BlockedBranches: map[string]string{},
+		BlockedBranches: BlockMap{},
 	})
 	time.Sleep(100 * time.Millisecond)
 
@@ -1811,7 +2021,7 @@ func TestResyncRaceCondition_ConcurrentBroadcast(t *testing.T) {
 			Type:            MsgTypeFullState,
 			SeqNum:          11, // Current sequence at time of resync
 			Alerts:          map[string]string{"pane1": "stop", "pane2": "stop", "pane3": "stop"},
-			BlockedBranches: map[string]string{},
+			BlockedBranches: BlockMap{},
 		}); err != nil {
 			t.Errorf("Failed to send FullState: %v", err)
 			return
@@ -2060,6 +2270,160 @@ func TestQueryBlockedState_NoStarvationUnderBroadcastLoad(t *testing.T) {
 	t.Logf("Received all 150 alerts without loss")
 }
 
+// TestQueryAllBlockedStates_Daemon queries a real daemon for every blocked branch while
+// the full_state from the hello is still being delivered as an event.
+func TestQueryAllBlockedStates_Daemon(t *testing.T) {
+	blocked := BlockMap{"feature-1": {"main"}, "feature-2": {"feature-1", "hotfix"}}
+	d := newBlocksTestDaemon(t, blocked)
+	conn := connectTestClient(t, d)
+
+	client := &DaemonClient{
+		clientID:       "test-client",
+		conn:           conn,
+		encoder:        json.NewEncoder(conn),
+		decoder:        json.NewDecoder(conn),
+		eventCh:        make(chan Message, 100),
+		done:           make(chan struct{}),
+		lastPong:       time.Now(),
+		queryResponses: make(map[string]*queryResponse),
+	}
+	go client.receive()
+	defer client.Close()
+
+	if err := client.sendMessage(Message{Type: MsgTypeHello, ClientID: client.clientID, ProtocolVersion: ProtocolVersion}); err != nil {
+		t.Fatalf("Failed to send hello: %v", err)
+	}
+
+	got, err := client.QueryAllBlockedStates()
+	if err != nil {
+		t.Fatalf("QueryAllBlockedStates failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, blocked) {
+		t.Errorf("QueryAllBlockedStates() = %v, want %v", got, blocked)
+	}
+
+	// The response goes to the caller only; full_state still reaches the event channel
+	sawFullState := false
+	timeout := time.After(200 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case msg := <-client.eventCh:
+			switch msg.Type {
+			case MsgTypeFullState:
+				sawFullState = true
+			case MsgTypeAllBlockedResponse:
+				t.Errorf("Query response leaked into eventCh: %+v", msg)
+			}
+		case <-timeout:
+			done = true
+		}
+	}
+	if !sawFullState {
+		t.Error("full_state event was lost")
+	}
+}
+
+// TestQueryAllBlockedStates_NoStarvationUnderBroadcastLoad tests that QueryAllBlockedStates
+// completes promptly while the daemon floods alerts, and that no alert is lost.
+func TestQueryAllBlockedStates_NoStarvationUnderBroadcastLoad(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	defer clientReader.Close()
+	defer clientWriter.Close()
+	defer serverReader.Close()
+	defer serverWriter.Close()
+
+	client := &DaemonClient{
+		clientID:       "test-client",
+		conn:           &mockConn{reader: clientReader, writer: clientWriter, localAddr: &mockAddr{"unix", "/tmp/test.sock"}, remoteAddr: &mockAddr{"unix", "/tmp/test.sock"}},
+		encoder:        json.NewEncoder(clientWriter),
+		decoder:        json.NewDecoder(clientReader),
+		eventCh:        make(chan Message, 100),
+		done:           make(chan struct{}),
+		lastPong:       time.Now(),
+		queryResponses: make(map[string]*queryResponse),
+	}
+	go client.receive()
+	defer client.Close()
+
+	alertsReceived := atomic.Int32{}
+	go func() {
+		for {
+			select {
+			case msg := <-client.eventCh:
+				if msg.Type == MsgTypeAlertChange {
+					alertsReceived.Add(1)
+				}
+			case <-client.done:
+				return
+			}
+		}
+	}()
+
+	blocked := BlockMap{"feature-branch": {"main"}}
+	go func() {
+		encoder := json.NewEncoder(serverWriter)
+		decoder := json.NewDecoder(serverReader)
+		broadcast := func(from, to int) bool {
+			for i := from; i <= to; i++ {
+				if err := encoder.Encode(Message{
+					Type:      MsgTypeAlertChange,
+					SeqNum:    uint64(i),
+					PaneID:    fmt.Sprintf("pane-%d", i%10),
+					EventType: "stop",
+					Created:   true,
+				}); err != nil {
+					t.Errorf("Failed to send broadcast %d: %v", i, err)
+					return false
+				}
+			}
+			return true
+		}
+
+		if !broadcast(1, 100) {
+			return
+		}
+		var queryMsg Message
+		if err := decoder.Decode(&queryMsg); err != nil {
+			t.Errorf("Failed to read query: %v", err)
+			return
+		}
+		if queryMsg.Type != MsgTypeQueryAllBlocked {
+			t.Errorf("Expected %s, got %s", MsgTypeQueryAllBlocked, queryMsg.Type)
+			return
+		}
+		if err := encoder.Encode(Message{Type: MsgTypeAllBlockedResponse, SeqNum: 101, BlockedBranches: blocked}); err != nil {
+			t.Errorf("Failed to send query response: %v", err)
+			return
+		}
+		broadcast(102, 151)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	queryStart := time.Now()
+	got, err := client.QueryAllBlockedStates()
+	if queryDuration := time.Since(queryStart); queryDuration > 2*time.Second {
+		t.Errorf("STARVATION DETECTED: Query took %v (should be < 2s)", queryDuration)
+	}
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, blocked) {
+		t.Errorf("QueryAllBlockedStates() = %v, want %v", got, blocked)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for alertsReceived.Load() < 150 {
+		select {
+		case <-timeout:
+			t.Fatalf("Only received %d/150 alerts", alertsReceived.Load())
+		default:
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
+
 // TestQueryBlockedState_NoStarvationDuringResyncFlood tests that QueryBlockedState()
 // doesn't starve when daemon floods with FullState messages (resync scenario).
 // This is a critical edge case where large FullState messages could block query responses.
@@ -2110,9 +2474,9 @@ func TestQueryBlockedState_NoStarvationDuringResyncFlood(t *testing.T) {
 			for j := 0; j < 20; j++ {
 				largeAlerts[fmt.Sprintf("pane-%d", j)] = "stop"
 			}
-			largeBlockedBranches := make(map[string]string)
+			largeBlockedBranches := make(BlockMap)
 			for j := 0; j < 10; j++ {
-				largeBlockedBranches[fmt.Sprintf("feature-%d", j)] = "main"
+				largeBlockedBranches[fmt.Sprintf("feature-%d", j)] = []string{"main"}
 			}
 
 			if err := encoder.Encode(Message{
@@ -2918,3 +3282,126 @@ func TestClientReceiveGoroutineCleanup(t *testing.T) {
 
 	t.Log("Goroutine cleanup test completed")
 }
+
+// TestReconnect_AfterDaemonRestart drops the client's connection, brings the "daemon"
+// back only after Reconnect has started retrying, and checks the client says hello
+// again and delivers the new connection's full_state without reporting a gap.
+func TestReconnect_AfterDaemonRestart(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "d.sock")
+
+	// serveOnce accepts one connection and returns the hello read from it
+	serveOnce := func(listener net.Listener, handle func(net.Conn)) <-chan Message {
+		hellos := make(chan Message, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			var hello Message
+			if err := json.NewDecoder(conn).Decode(&hello); err != nil {
+				conn.Close()
+				return
+			}
+			hellos <- hello
+			handle(conn)
+		}()
+		return hellos
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	firstHello := serveOnce(listener, func(conn net.Conn) {
+		// Daemon "restarts": it had sent seq 41, then the connection and socket go away
+		json.NewEncoder(conn).Encode(Message{Type: MsgTypeFullState, SeqNum: 41})
+		conn.Close()
+		listener.Close()
+	})
+
+	client := &DaemonClient{
+		clientID:       "test-reconnect",
+		socketPath:     socketPath,
+		eventCh:        make(chan Message, 100),
+		done:           make(chan struct{}),
+		queryResponses: make(map[string]*queryResponse),
+	}
+	defer client.Close()
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	<-firstHello
+
+	waitForEvent := func(msgType string) Message {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case msg := <-client.Events():
+				if msg.Type == msgType {
+					return msg
+				}
+			case <-timeout:
+				t.Fatalf("Timed out waiting for %s event", msgType)
+			}
+		}
+	}
+	waitForEvent("disconnect")
+
+	reconnectErr := make(chan error, 1)
+	go func() { reconnectErr <- client.Reconnect(context.Background()) }()
+
+	// Let a few attempts fail before the daemon comes back
+	time.Sleep(300 * time.Millisecond)
+	if client.IsConnected() {
+		t.Fatal("Client reports connected while the daemon is down")
+	}
+	os.Remove(socketPath)
+	listener2, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen again: %v", err)
+	}
+	defer listener2.Close()
+	secondHello := serveOnce(listener2, func(conn net.Conn) {
+		// A restarted daemon numbers from 1 again
+		json.NewEncoder(conn).Encode(Message{Type: MsgTypeFullState, SeqNum: 1})
+		t.Cleanup(func() { conn.Close() })
+	})
+
+	if err := <-reconnectErr; err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	if hello := <-secondHello; hello.Type != MsgTypeHello || hello.ClientID != "test-reconnect" {
+		t.Errorf("Reconnect hello = %+v, want hello from test-reconnect", hello)
+	}
+	if fullState := waitForEvent(MsgTypeFullState); fullState.SeqNum != 1 {
+		t.Errorf("full_state seq = %d, want 1 from the new connection", fullState.SeqNum)
+	}
+	if got := client.lastSeq.Load(); got != 1 {
+		t.Errorf("lastSeq = %d, want 1 (reset for the restarted daemon)", got)
+	}
+}
+
+// TestReconnect_StopsOnClose verifies Close ends a Reconnect that is still retrying.
+func TestReconnect_StopsOnClose(t *testing.T) {
+	client := &DaemonClient{
+		clientID:   "test-reconnect-close",
+		socketPath: filepath.Join(t.TempDir(), "missing.sock"),
+		eventCh:    make(chan Message, 100),
+		done:       make(chan struct{}),
+	}
+
+	reconnectErr := make(chan error, 1)
+	go func() { reconnectErr <- client.Reconnect(context.Background()) }()
+	time.Sleep(250 * time.Millisecond)
+	client.Close()
+
+	select {
+	case err := <-reconnectErr:
+		if !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Reconnect error = %v, want ErrClientClosed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Reconnect kept retrying after Close")
+	}
+}
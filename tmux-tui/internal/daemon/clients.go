@@ -0,0 +1,171 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// DefaultMaxClients is how many clients the daemon serves at once before rejecting
+// new connections. TUIs, tmux-tui-block and one-shot commands each count as one.
+const DefaultMaxClients = 64
+
+// maxClientsEnv overrides DefaultMaxClients (positive integer).
+const maxClientsEnv = "TMUX_TUI_MAX_CLIENTS"
+
+// maxClientsFromEnv returns the client limit from TMUX_TUI_MAX_CLIENTS,
+// falling back to DefaultMaxClients when unset or invalid.
+func maxClientsFromEnv() int {
+	raw := os.Getenv(maxClientsEnv)
+	if raw == "" {
+		return DefaultMaxClients
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: Invalid %s=%q (expected positive client count), using default %d\n",
+			maxClientsEnv, raw, DefaultMaxClients)
+		return DefaultMaxClients
+	}
+	return limit
+}
+
+// maxClients returns the configured client limit (0 = DefaultMaxClients).
+func (d *AlertDaemon) maxClients() int {
+	if d.MaxClients > 0 {
+		return d.MaxClients
+	}
+	return DefaultMaxClients
+}
+
+// registerClient adds client under clientID unless the client limit is reached, in
+// which case it returns a rejection reason. A client reconnecting with an ID that is
+// still registered replaces its old entry and never counts against the limit.
+func (d *AlertDaemon) registerClient(clientID string, client *clientConnection) (rejection string) {
+	limit := d.maxClients()
+	d.clientsMu.Lock()
+	defer d.clientsMu.Unlock()
+	if _, exists := d.clients[clientID]; !exists && len(d.clients) >= limit {
+		return fmt.Sprintf("client limit reached (%d connected, max %d) - close some clients or raise %s",
+			len(d.clients), limit, maxClientsEnv)
+	}
+	d.clients[clientID] = client
+	return ""
+}
+
+// rejectClient tells a client why it was refused and closes the connection. The
+// rejection is unsequenced (seq 0): the client never joins the broadcast stream, and
+// consuming a sequence number would look like a gap to every other client.
+func (d *AlertDaemon) rejectClient(conn net.Conn, clientID, reason string) {
+	debug.Log("DAEMON_CLIENT_REJECTED id=%s reason=%s", clientID, reason)
+	fmt.Fprintf(os.Stderr, "WARNING: Rejected client %s: %s\n", clientID, reason)
+
+	rejectMsg, err := NewRejectedMessage(0, reason)
+	if err != nil {
+		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=rejected error=%v", err)
+		conn.Close()
+		return
+	}
+	sendRejection(conn, clientID, rejectMsg)
+}
+
+// rejectProtocolMismatch refuses a client whose hello named an incompatible protocol
+// version (mismatch is the CheckProtocolVersion error), unsequenced like rejectClient.
+func (d *AlertDaemon) rejectProtocolMismatch(conn net.Conn, clientID string, mismatch error) {
+	debug.Log("DAEMON_CLIENT_VERSION_MISMATCH id=%s error=%v", clientID, mismatch)
+	fmt.Fprintf(os.Stderr, "WARNING: Rejected client %s: %v\n", clientID, mismatch)
+
+	mismatchMsg, err := NewVersionMismatchMessage(0, ProtocolVersion, mismatch.Error())
+	if err != nil {
+		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=version_mismatch error=%v", err)
+		conn.Close()
+		return
+	}
+	sendRejection(conn, clientID, mismatchMsg)
+}
+
+// sendRejection writes msg to a refused connection and closes it.
+func sendRejection(conn net.Conn, clientID string, msg MessageV2) {
+	defer conn.Close()
+	conn.SetWriteDeadline(time.Now().Add(oneShotReadTimeout))
+	if err := json.NewEncoder(conn).Encode(msg.ToWireFormat()); err != nil {
+		debug.Log("DAEMON_REJECT_SEND_ERROR id=%s type=%s error=%v", clientID, msg.MessageType(), err)
+	}
+}
+
+// ClientInfo describes one connected client, as reported by QueryClients. Clients
+// identify themselves only by the ID in their hello, so no role or version is known.
+type ClientInfo struct {
+	ID          string    `json:"id"`
+	ConnectedAt time.Time `json:"connected_at"`
+	LastSeen    time.Time `json:"last_seen"`     // Last message received (zero = none since hello)
+	LastPing    time.Time `json:"last_ping"`     // Last ping answered with a pong (zero = never)
+	LastSeqSent uint64    `json:"last_seq_sent"` // Sequence number of the last sequenced message sent
+}
+
+// clientInfos snapshots every registered client, sorted by ID.
+func (d *AlertDaemon) clientInfos() []ClientInfo {
+	d.clientsMu.RLock()
+	infos := make([]ClientInfo, 0, len(d.clients))
+	for id, client := range d.clients {
+		infos = append(infos, ClientInfo{
+			ID:          id,
+			ConnectedAt: client.connectedAt,
+			LastSeen:    unixNanoTime(client.lastSeen.Load()),
+			LastPing:    unixNanoTime(client.lastPing.Load()),
+			LastSeqSent: client.lastSeqSent.Load(),
+		})
+	}
+	d.clientsMu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// unixNanoTime converts a stored Unix nanosecond timestamp, keeping 0 as the zero time.
+func unixNanoTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// QueryClients connects to the daemon over conn as a short-lived client, sends a
+// clients_query and returns the connected clients from the clients_response. The
+// list includes this query's own connection (ID "clients-<pid>").
+func QueryClients(conn net.Conn) ([]ClientInfo, error) {
+	query, err := NewClientsQueryMessage(0)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := helloOneShot(conn, "clients")
+	if err != nil {
+		return nil, err
+	}
+	// Daemon sends full_state before reading further messages
+	if _, err := readUntil(conn, decoder, MsgTypeFullState); err != nil {
+		return nil, err
+	}
+	// Send while reading, as in ImportBlocks: the daemon may be writing to us concurrently
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- json.NewEncoder(conn).Encode(query.ToWireFormat()) }()
+
+	msg, err := readUntil(conn, decoder, MsgTypeClientsResponse)
+	if err != nil {
+		return nil, err
+	}
+	if err := <-sendErr; err != nil {
+		return nil, fmt.Errorf("failed to send clients query: %w", err)
+	}
+	response, err := FromWireFormat(msg)
+	if err != nil {
+		return nil, err
+	}
+	return response.(*ClientsResponseMessageV2).Clients(), nil
+}
@@ -0,0 +1,169 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// connectNamedClient connects a one-shot client registered as "<purpose>-<pid>" and
+// returns its full_state.
+func connectNamedClient(t *testing.T, d *AlertDaemon, purpose string) (net.Conn, *json.Decoder, Message) {
+	t.Helper()
+	conn := connectTestClient(t, d)
+	decoder, err := helloOneShot(conn, purpose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullState, err := readUntil(conn, decoder, MsgTypeFullState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, decoder, fullState
+}
+
+// TestHandleClient_MaxClients fills the daemon to its client limit and checks that the
+// next connection is rejected with a reason and closed without being registered, while
+// a client reconnecting under a registered ID is still accepted.
+func TestHandleClient_MaxClients(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{})
+	d.MaxClients = 2
+
+	connectNamedClient(t, d, "tui-1")
+	connectNamedClient(t, d, "tui-2")
+
+	conn := connectTestClient(t, d)
+	decoder, err := helloOneShot(conn, "tui-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = readUntil(conn, decoder, MsgTypeFullState)
+	if !errors.Is(err, ErrConnectionRejected) {
+		t.Fatalf("Expected ErrConnectionRejected, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "client limit reached") {
+		t.Errorf("Rejection should explain the limit, got %q", err)
+	}
+	var next Message
+	if err := decoder.Decode(&next); err == nil {
+		t.Errorf("Expected the rejected connection to be closed, got %+v", next)
+	}
+	// Only the clients that fit are registered. The sequence counter isn't checked: the
+	// accepted clients' handlers take sequence numbers of their own concurrently.
+	infos := d.clientInfos()
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 registered clients after rejection, got %+v", infos)
+	}
+	for _, info := range infos {
+		if !strings.HasPrefix(info.ID, "tui-1-") && !strings.HasPrefix(info.ID, "tui-2-") {
+			t.Errorf("Rejected client was registered: %+v", infos)
+		}
+	}
+
+	// Same ID as a registered client (e.g. a reconnect before the old connection dropped)
+	connectNamedClient(t, d, "tui-1")
+}
+
+// TestHandleClient_ProtocolMismatch checks that a client of another major protocol
+// version or without one is refused with version_mismatch, while a minor difference is
+// accepted.
+func TestHandleClient_ProtocolMismatch(t *testing.T) {
+	d := newBlocksTestDaemon(t, BlockMap{})
+
+	hello := func(clientID, version string) (*json.Decoder, net.Conn) {
+		t.Helper()
+		conn := connectTestClient(t, d)
+		decoder := json.NewDecoder(conn)
+		go json.NewEncoder(conn).Encode(Message{Type: MsgTypeHello, ClientID: clientID, ProtocolVersion: version})
+		return decoder, conn
+	}
+
+	decoder, conn := hello("future-tui", "4.0")
+	_, err := readUntil(conn, decoder, MsgTypeFullState)
+	if !errors.Is(err, ErrProtocolMismatch) {
+		t.Fatalf("Expected ErrProtocolMismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "daemon speaks protocol "+ProtocolVersion) {
+		t.Errorf("Mismatch should name the daemon's version, got %q", err)
+	}
+	var next Message
+	if err := decoder.Decode(&next); err == nil {
+		t.Errorf("Expected the refused connection to be closed, got %+v", next)
+	}
+	if got := len(d.clientInfos()); got != 0 {
+		t.Errorf("Expected no registered clients after mismatch, got %d", got)
+	}
+
+	// Clients that predate versioning send none
+	decoder, conn = hello("legacy-tui", "")
+	if _, err := readUntil(conn, decoder, MsgTypeFullState); !errors.Is(err, ErrProtocolMismatch) {
+		t.Errorf("Client without a protocol version: expected ErrProtocolMismatch, got %v", err)
+	}
+
+	for _, version := range []string{ProtocolVersion, "3.99"} {
+		decoder, conn := hello("tui-"+version, version)
+		if _, err := readUntil(conn, decoder, MsgTypeFullState); err != nil {
+			t.Errorf("Client with protocol %q should be accepted, got %v", version, err)
+		}
+	}
+}
+
+// TestQueryClients lists two connected clients and checks their connect times,
+// last ping and last sequence number, using a fake clock for exact timestamps.
+func TestQueryClients(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	d := newBlocksTestDaemon(t, BlockMap{})
+	d.clock = clock
+
+	conn1, decoder1, _ := connectNamedClient(t, d, "tui-1")
+	clock.Advance(time.Minute)
+	_, _, fullState2 := connectNamedClient(t, d, "tui-2")
+	clock.Advance(time.Second)
+
+	// tui-1 pings; send while reading since the daemon may be writing to us
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- json.NewEncoder(conn1).Encode(Message{Type: MsgTypePing}) }()
+	pong, err := readUntil(conn1, decoder1, MsgTypePong)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-sendErr; err != nil {
+		t.Fatalf("Failed to send ping: %v", err)
+	}
+
+	clients, err := QueryClients(connectTestClient(t, d))
+	if err != nil {
+		t.Fatalf("QueryClients failed: %v", err)
+	}
+	if len(clients) != 3 {
+		t.Fatalf("Got %d clients, want 3 (two TUIs and the query itself): %+v", len(clients), clients)
+	}
+	pid := fmt.Sprintf("-%d", os.Getpid())
+	if clients[0].ID != "clients"+pid {
+		t.Errorf("clients[0].ID = %q, want the query's own connection", clients[0].ID)
+	}
+
+	tui1, tui2 := clients[1], clients[2]
+	if tui1.ID != "tui-1"+pid || tui2.ID != "tui-2"+pid {
+		t.Fatalf("Client IDs = %q, %q, want tui-1, tui-2", tui1.ID, tui2.ID)
+	}
+	pingTime := start.Add(time.Minute + time.Second)
+	if !tui1.ConnectedAt.Equal(start) || !tui1.LastPing.Equal(pingTime) || !tui1.LastSeen.Equal(pingTime) {
+		t.Errorf("tui-1 = %+v, want connected at %v and last ping/seen at %v", tui1, start, pingTime)
+	}
+	if tui1.LastSeqSent != pong.SeqNum {
+		t.Errorf("tui-1 LastSeqSent = %d, want pong seq %d", tui1.LastSeqSent, pong.SeqNum)
+	}
+	if !tui2.ConnectedAt.Equal(start.Add(time.Minute)) || !tui2.LastPing.IsZero() || !tui2.LastSeen.IsZero() {
+		t.Errorf("tui-2 = %+v, want connected at %v and never pinged", tui2, start.Add(time.Minute))
+	}
+	if tui2.LastSeqSent != fullState2.SeqNum {
+		t.Errorf("tui-2 LastSeqSent = %d, want full_state seq %d", tui2.LastSeqSent, fullState2.SeqNum)
+	}
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/watcher"
+)
+
+func TestFakeClock_AdvanceAndSet(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+	clock.Advance(90 * time.Second)
+	if got, want := clock.Now(), start.Add(90*time.Second); !got.Equal(want) {
+		t.Errorf("Now() after Advance = %v, want %v", got, want)
+	}
+	clock.Set(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("Now() after Set = %v, want %v", got, start)
+	}
+}
+
+// TestPlayAlertSound_RateLimitFollowsClock verifies the audio rate limit is measured
+// on the daemon's clock, so crossing it needs no real delay.
+func TestPlayAlertSound_RateLimitFollowsClock(t *testing.T) {
+	t.Setenv("CLAUDE_E2E_TEST", "")
+	writes := 0
+	mock := &mockWriteCloser{writeFunc: func(p []byte) (int, error) {
+		writes++
+		return len(p), nil
+	}}
+	cleanup := setupPlayAlertSoundTest(func() (io.WriteCloser, error) {
+		return mock, nil
+	})
+	defer cleanup()
+
+	clock := NewFakeClock(time.Now())
+	daemon := &AlertDaemon{
+		clients: make(map[string]*clientConnection),
+		clock:   clock,
+	}
+
+	daemon.playAlertSound(watcher.EventTypeStop)
+	clock.Advance(400 * time.Millisecond)
+	daemon.playAlertSound(watcher.EventTypeStop)
+	if got := writes; got != 1 {
+		t.Fatalf("Expected 1 write inside the rate limit, got %d", got)
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	daemon.playAlertSound(watcher.EventTypeStop)
+	if got := writes; got != 2 {
+		t.Errorf("Expected 2 writes once the rate limit elapsed, got %d", got)
+	}
+}
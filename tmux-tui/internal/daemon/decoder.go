@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// DefaultMaxMessageSize is the largest single JSON message accepted from a peer.
+// Tree updates are the largest legitimate messages (every pane across every repo),
+// so the default is generous: a tree would need tens of thousands of panes to approach it.
+const DefaultMaxMessageSize int64 = 8 << 20 // 8 MiB
+
+// maxMessageSizeEnv overrides DefaultMaxMessageSize for both daemon and client (bytes).
+const maxMessageSizeEnv = "TMUX_TUI_MAX_MESSAGE_SIZE"
+
+// ErrMessageTooLarge is returned by the decode paths when a peer sends a message
+// exceeding the configured size limit. The connection must be closed after this error:
+// the stream is mid-message and cannot be resynchronized.
+var ErrMessageTooLarge = errors.New("message exceeds maximum size")
+
+// messageDecoder is the decode side of a daemon/client connection.
+// Satisfied by *json.Decoder (tests) and *limitedDecoder (production).
+type messageDecoder interface {
+	Decode(v any) error
+}
+
+// maxMessageSizeFromEnv returns the message size limit from TMUX_TUI_MAX_MESSAGE_SIZE,
+// falling back to DefaultMaxMessageSize when unset or invalid.
+func maxMessageSizeFromEnv() int64 {
+	raw := os.Getenv(maxMessageSizeEnv)
+	if raw == "" {
+		return DefaultMaxMessageSize
+	}
+	size, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || size <= 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: Invalid %s=%q (expected positive byte count), using default %d\n",
+			maxMessageSizeEnv, raw, DefaultMaxMessageSize)
+		return DefaultMaxMessageSize
+	}
+	return size
+}
+
+// limitedReader counts bytes handed to the JSON decoder since the start of the
+// current message and refuses to read once the limit is exceeded.
+//
+// json.Decoder buffers the whole value before unmarshaling, so capping what it can
+// read bounds its buffer. The boundary is advanced by limitedDecoder after each
+// successful Decode using json.Decoder.InputOffset().
+type limitedReader struct {
+	r        io.Reader
+	read     int64 // Total bytes read from r
+	boundary int64 // Stream offset where the current message begins
+	max      int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	pending := l.read - l.boundary
+	if pending > l.max {
+		return 0, ErrMessageTooLarge
+	}
+	// Never read more than one byte past the limit, so oversized messages are
+	// detected without buffering the remainder.
+	if remaining := l.max - pending + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	return n, err
+}
+
+// limitedDecoder wraps json.Decoder with a per-message size limit.
+type limitedDecoder struct {
+	reader  *limitedReader
+	decoder *json.Decoder
+}
+
+// newLimitedDecoder creates a decoder that rejects messages larger than maxSize bytes.
+// A non-positive maxSize uses DefaultMaxMessageSize.
+func newLimitedDecoder(r io.Reader, maxSize int64) *limitedDecoder {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	lr := &limitedReader{r: r, max: maxSize}
+	return &limitedDecoder{reader: lr, decoder: json.NewDecoder(lr)}
+}
+
+// Decode reads the next JSON message into v.
+// Returns an error wrapping ErrMessageTooLarge if the message exceeds the limit.
+func (d *limitedDecoder) Decode(v any) error {
+	if err := d.decoder.Decode(v); err != nil {
+		if errors.Is(err, ErrMessageTooLarge) {
+			debug.Log("DECODER_MESSAGE_TOO_LARGE limit=%d", d.reader.max)
+			return fmt.Errorf("%w (limit %d bytes)", ErrMessageTooLarge, d.reader.max)
+		}
+		return err
+	}
+	d.reader.boundary = d.decoder.InputOffset()
+	return nil
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLimitedDecoder_ResetsPerMessage verifies the limit applies per message,
+// not to the cumulative stream.
+func TestLimitedDecoder_ResetsPerMessage(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for i := 0; i < 50; i++ {
+		if err := encoder.Encode(Message{Type: MsgTypePing}); err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+	}
+
+	// Each message is ~16 bytes; the stream is ~800 bytes
+	decoder := newLimitedDecoder(&buf, 64)
+	for i := 0; i < 50; i++ {
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+		if msg.Type != MsgTypePing {
+			t.Errorf("Decode %d: expected ping, got %s", i, msg.Type)
+		}
+	}
+}
+
+// TestLimitedDecoder_RejectsOversizedMessage verifies that an oversized message
+// returns ErrMessageTooLarge without buffering the whole payload.
+func TestLimitedDecoder_RejectsOversizedMessage(t *testing.T) {
+	const limit = 1024
+	payload := `{"type":"ping","error":"` + strings.Repeat("x", 10*limit) + `"}` + "\n"
+	reader := &limitedReader{r: strings.NewReader(payload), max: limit}
+	decoder := &limitedDecoder{reader: reader, decoder: json.NewDecoder(reader)}
+
+	var msg Message
+	err := decoder.Decode(&msg)
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	if reader.read > limit+1 {
+		t.Errorf("Decoder read %d bytes, expected at most %d", reader.read, limit+1)
+	}
+}
+
+// TestLimitedDecoder_DefaultLimit verifies non-positive limits fall back to the default.
+func TestLimitedDecoder_DefaultLimit(t *testing.T) {
+	decoder := newLimitedDecoder(strings.NewReader(""), 0)
+	if decoder.reader.max != DefaultMaxMessageSize {
+		t.Errorf("Expected default limit %d, got %d", DefaultMaxMessageSize, decoder.reader.max)
+	}
+}
+
+// TestMaxMessageSizeFromEnv verifies env override parsing and fallback.
+func TestMaxMessageSizeFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"", DefaultMaxMessageSize},
+		{"4096", 4096},
+		{"not-a-number", DefaultMaxMessageSize},
+		{"-1", DefaultMaxMessageSize},
+		{"0", DefaultMaxMessageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(maxMessageSizeEnv, tt.value)
+			if got := maxMessageSizeFromEnv(); got != tt.want {
+				t.Errorf("maxMessageSizeFromEnv() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandleClient_OversizedMessageDisconnects verifies the daemon drops a client
+// that sends an oversized message instead of buffering it.
+func TestHandleClient_OversizedMessageDisconnects(t *testing.T) {
+	daemon := &AlertDaemon{
+		clients:         make(map[string]*clientConnection),
+		alerts:          make(map[string]string),
+		blockedBranches: make(BlockMap),
+		maxMessageSize:  1024,
+	}
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		daemon.handleClient(serverConn)
+		close(done)
+	}()
+
+	// Drain daemon output in the background so writes never block
+	go io.Copy(io.Discard, clientConn)
+
+	encoder := json.NewEncoder(clientConn)
+	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "big-client", ProtocolVersion: ProtocolVersion}); err != nil {
+		t.Fatalf("Failed to send hello: %v", err)
+	}
+
+	// Wait for registration
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		daemon.clientsMu.RLock()
+		_, registered := daemon.clients["big-client"]
+		daemon.clientsMu.RUnlock()
+		if registered {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Client was never registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Write an oversized message; the write fails once the daemon closes the pipe
+	oversized := `{"type":"ping","error":"` + strings.Repeat("x", 64*1024) + `"}` + "\n"
+	go clientConn.Write([]byte(oversized))
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handleClient did not return after oversized message")
+	}
+
+	daemon.clientsMu.RLock()
+	defer daemon.clientsMu.RUnlock()
+	if _, exists := daemon.clients["big-client"]; exists {
+		t.Error("Expected client to be removed after oversized message")
+	}
+}
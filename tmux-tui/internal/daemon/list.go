@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// ListState is the daemon state reported by QueryList.
+type ListState struct {
+	Alerts          map[string]string `json:"alerts"`           // paneID -> eventType
+	BlockedBranches BlockMap          `json:"blocked_branches"` // branch -> blocking branches
+}
+
+// QueryList connects to the daemon over conn as a short-lived client, sends a
+// list_query and returns the alerts and blocked branches from the list_response.
+func QueryList(conn net.Conn) (ListState, error) {
+	query, err := NewListQueryMessage(0)
+	if err != nil {
+		return ListState{}, err
+	}
+
+	decoder, err := helloOneShot(conn, "list")
+	if err != nil {
+		return ListState{}, err
+	}
+	// Daemon sends full_state before reading further messages
+	if _, err := readUntil(conn, decoder, MsgTypeFullState); err != nil {
+		return ListState{}, err
+	}
+	// Send while reading, as in ImportBlocks: the daemon may be writing to us concurrently
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- json.NewEncoder(conn).Encode(query.ToWireFormat()) }()
+
+	msg, err := readUntil(conn, decoder, MsgTypeListResponse)
+	if err != nil {
+		return ListState{}, err
+	}
+	if err := <-sendErr; err != nil {
+		return ListState{}, fmt.Errorf("failed to send list query: %w", err)
+	}
+	return ListState{
+		Alerts:          copyStringMap(msg.Alerts),
+		BlockedBranches: msg.BlockedBranches.Clone(),
+	}, nil
+}
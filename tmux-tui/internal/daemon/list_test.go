@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestQueryList(t *testing.T) {
+	blocked := BlockMap{"feature-2": {"feature-1"}}
+	d := newBlocksTestDaemon(t, blocked)
+	d.alerts["%1"] = "stop"
+	d.alerts["%4"] = "idle"
+
+	state, err := QueryList(connectTestClient(t, d))
+	if err != nil {
+		t.Fatalf("QueryList failed: %v", err)
+	}
+	want := ListState{
+		Alerts:          map[string]string{"%1": "stop", "%4": "idle"},
+		BlockedBranches: blocked,
+	}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("QueryList() = %+v, want %+v", state, want)
+	}
+}
+
+// TestQueryList_EmptyStateEncodesMaps verifies an idle daemon lists {} rather than
+// null, so scripts can index the maps without special-casing.
+func TestQueryList_EmptyStateEncodesMaps(t *testing.T) {
+	state, err := QueryList(connectTestClient(t, newBlocksTestDaemon(t, BlockMap{})))
+	if err != nil {
+		t.Fatalf("QueryList failed: %v", err)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"alerts":{},"blocked_branches":{}}`; got != want {
+		t.Errorf("Encoded %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// MetricsAddrEnv sets the TCP address (e.g. "127.0.0.1:9469") the daemon serves
+// Prometheus metrics on. Unset or empty disables the metrics listener.
+const MetricsAddrEnv = "TMUX_TUI_METRICS_ADDR"
+
+// metricsContentType is the Prometheus text exposition format
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricsPrefix namespaces every exported metric
+const metricsPrefix = "tmux_tui_daemon_"
+
+// MetricsHandler serves the daemon's health counters in Prometheus text format at
+// /metrics. Each scrape takes a GetHealthStatus snapshot: counters are read from
+// atomics, and the client, alert and blocked-branch counts hold their read locks only
+// for a len().
+func (d *AlertDaemon) MetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		status, err := d.GetHealthStatus()
+		if err != nil {
+			debug.Log("DAEMON_METRICS_ERROR error=%v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", metricsContentType)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if err := WriteMetrics(w, status); err != nil {
+			// The client went away mid-response; nothing useful to send
+			debug.Log("DAEMON_METRICS_WRITE_ERROR error=%v", err)
+		}
+	})
+	return mux
+}
+
+// WriteMetrics writes status in Prometheus text exposition format.
+func WriteMetrics(w io.Writer, status HealthStatus) error {
+	bw := bufio.NewWriter(w)
+	metric := func(name, kind, help string, value float64) {
+		fmt.Fprintf(bw, "# HELP %s%s %s\n", metricsPrefix, name, help)
+		fmt.Fprintf(bw, "# TYPE %s%s %s\n", metricsPrefix, name, kind)
+		fmt.Fprintf(bw, "%s%s %s\n", metricsPrefix, name, strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	counter := func(name, help string, value int64) { metric(name, "counter", help, float64(value)) }
+	gauge := func(name, help string, value float64) { metric(name, "gauge", help, value) }
+
+	counter("broadcast_failures_total", "Broadcasts that failed to reach a client.", status.GetBroadcastFailures())
+	counter("watcher_errors_total", "Errors from the alert and pane focus watchers.", status.GetWatcherErrors())
+	counter("connection_close_errors_total", "Errors closing client connections.", status.GetConnectionCloseErrors())
+	counter("audio_broadcast_failures_total", "Audio alert broadcasts that failed.", status.GetAudioBroadcastFailures())
+	counter("tree_broadcast_errors_total", "tree_update and tree_error broadcasts that failed.", status.GetTreeBroadcastErrors())
+	counter("tree_msg_construct_errors_total", "Tree messages that could not be constructed.", status.GetTreeMsgConstructErrors())
+	counter("resync_failures_total", "Resyncs that failed to send full state to a client.", status.GetResyncFailures())
+
+	gauge("connected_clients", "Clients currently connected.", float64(status.GetConnectedClients()))
+	gauge("active_alerts", "Panes with an active alert.", float64(status.GetActiveAlerts()))
+	gauge("blocked_branches", "Branches currently blocked.", float64(status.GetBlockedBranches()))
+	gauge("last_broadcast_duration_seconds", "Time the most recent broadcast took to reach all clients.", status.GetLastBroadcastDuration().Seconds())
+	gauge("avg_broadcast_duration_seconds", "Rolling average broadcast duration.", status.GetAvgBroadcastDuration().Seconds())
+	if started := status.GetStartTime(); !started.IsZero() {
+		gauge("start_time_seconds", "When the daemon started, in Unix seconds.", float64(started.UnixNano())/1e9)
+	}
+
+	fmt.Fprintf(bw, "# HELP %sbuild_info The running daemon's build.\n", metricsPrefix)
+	fmt.Fprintf(bw, "# TYPE %sbuild_info gauge\n", metricsPrefix)
+	fmt.Fprintf(bw, "%sbuild_info{version=\"%s\"} 1\n", metricsPrefix, escapeLabelValue(status.GetVersion()))
+
+	return bw.Flush()
+}
+
+// escapeLabelValue escapes a Prometheus label value
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// MetricsAddrFromEnv returns the metrics listen address from TMUX_TUI_METRICS_ADDR
+// ("" = disabled).
+func MetricsAddrFromEnv() string {
+	return strings.TrimSpace(os.Getenv(MetricsAddrEnv))
+}
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	status, err := NewHealthStatusBuilder().
+		WithBroadcastMetrics(5, "boom").
+		WithWatcherMetrics(3, "").
+		WithResyncMetrics(2).
+		WithCounters(4, 1, 7).
+		WithBroadcastLatency(1500*time.Microsecond, 0).
+		WithStartTime(time.Unix(1700000000, 0)).
+		WithVersion(`v1.2.3 "dirty"`).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var out strings.Builder
+	if err := WriteMetrics(&out, status); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"# TYPE tmux_tui_daemon_broadcast_failures_total counter\ntmux_tui_daemon_broadcast_failures_total 5\n",
+		"tmux_tui_daemon_watcher_errors_total 3\n",
+		"tmux_tui_daemon_resync_failures_total 2\n",
+		"# TYPE tmux_tui_daemon_connected_clients gauge\ntmux_tui_daemon_connected_clients 4\n",
+		"tmux_tui_daemon_active_alerts 1\n",
+		"tmux_tui_daemon_blocked_branches 7\n",
+		"tmux_tui_daemon_last_broadcast_duration_seconds 0.0015\n",
+		"tmux_tui_daemon_start_time_seconds 1.7e+09\n",
+		`tmux_tui_daemon_build_info{version="v1.2.3 \"dirty\""} 1` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteMetrics() output missing %q, got:\n%s", want, got)
+		}
+	}
+	// Error messages are for the health command, not labels
+	if strings.Contains(got, "boom") {
+		t.Errorf("WriteMetrics() should not export error text, got:\n%s", got)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	d := &AlertDaemon{
+		clients:         make(map[string]*clientConnection),
+		alerts:          map[string]string{"%1": "stop", "%2": "idle"},
+		blockedBranches: BlockMap{"feature": {"main"}},
+	}
+	d.broadcastFailures.Store(1)
+	d.resyncFailures.Store(3)
+	handler := d.MetricsHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want Prometheus text format", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"tmux_tui_daemon_broadcast_failures_total 1\n",
+		"tmux_tui_daemon_resync_failures_total 3\n",
+		"tmux_tui_daemon_active_alerts 2\n",
+		"tmux_tui_daemon_blocked_branches 1\n",
+		"tmux_tui_daemon_connected_clients 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("GET /metrics missing %q, got:\n%s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /metrics status = %d, want 405", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /health status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// ProtectedBranchesEnv names the environment variable holding a comma-separated list of
+// branch names or glob patterns (path.Match syntax, e.g. "release/*") that can never be
+// blocked. When unset, DefaultProtectedBranches applies; set it to an empty string to
+// allow every branch to be blocked.
+const ProtectedBranchesEnv = "TMUX_TUI_PROTECTED_BRANCHES"
+
+// DefaultProtectedBranches are the trunk names protected when ProtectedBranchesEnv is unset.
+var DefaultProtectedBranches = []string{"main", "master"}
+
+// ProtectedBranchesFromEnv returns the protected branch patterns configured through
+// ProtectedBranchesEnv. Malformed patterns are reported on stderr and skipped.
+//
+// The daemon and the TUI both call this so the picker agrees with the daemon's rejections.
+func ProtectedBranchesFromEnv() []string {
+	value, ok := os.LookupEnv(ProtectedBranchesEnv)
+	if !ok {
+		return append([]string(nil), DefaultProtectedBranches...)
+	}
+	patterns, err := ParseProtectedBranches(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: Invalid %s=%q: %v\n", ProtectedBranchesEnv, value, err)
+	}
+	return patterns
+}
+
+// ParseProtectedBranches splits a comma-separated pattern list, dropping empty entries.
+// Malformed patterns are left out of the result and reported in the returned error.
+func ParseProtectedBranches(value string) ([]string, error) {
+	var patterns []string
+	var invalid []string
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			invalid = append(invalid, p)
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+	if len(invalid) > 0 {
+		return patterns, fmt.Errorf("malformed pattern(s) ignored: %s", strings.Join(invalid, ", "))
+	}
+	return patterns, nil
+}
+
+// IsProtectedBranch reports whether branch matches any of the protected patterns.
+func IsProtectedBranch(branch string, patterns []string) bool {
+	for _, p := range patterns {
+		if matched, _ := path.Match(p, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtected reports whether this daemon refuses to block branch.
+func (d *AlertDaemon) isProtected(branch string) bool {
+	if IsProtectedBranch(branch, d.protectedBranches) {
+		debug.Log("DAEMON_PROTECTED_BRANCH branch=%s patterns=%v", branch, d.protectedBranches)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsProtectedBranch(t *testing.T) {
+	patterns := []string{"main", "release/*"}
+
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{"main", true},
+		{"release/1.2", true},
+		{"release/1.2/hotfix", false}, // * does not cross /
+		{"feature", false},
+		{"mainline", false},
+	}
+	for _, tt := range tests {
+		if got := IsProtectedBranch(tt.branch, patterns); got != tt.want {
+			t.Errorf("IsProtectedBranch(%q) = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+
+	if IsProtectedBranch("main", nil) {
+		t.Error("Expected no branch to be protected with empty pattern list")
+	}
+}
+
+func TestParseProtectedBranches(t *testing.T) {
+	got, err := ParseProtectedBranches(" main, ,release/*,[bad ")
+	if err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+	if want := []string{"main", "release/*"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProtectedBranches() = %v, want %v", got, want)
+	}
+}
+
+func TestProtectedBranchesFromEnv(t *testing.T) {
+	t.Setenv(ProtectedBranchesEnv, "") // restores the original value after the test
+	os.Unsetenv(ProtectedBranchesEnv)
+	if got := ProtectedBranchesFromEnv(); !reflect.DeepEqual(got, DefaultProtectedBranches) {
+		t.Errorf("Unset env: got %v, want defaults %v", got, DefaultProtectedBranches)
+	}
+
+	t.Setenv(ProtectedBranchesEnv, "trunk,develop")
+	if got := ProtectedBranchesFromEnv(); !reflect.DeepEqual(got, []string{"trunk", "develop"}) {
+		t.Errorf("Configured env: got %v", got)
+	}
+
+	t.Setenv(ProtectedBranchesEnv, "")
+	if got := ProtectedBranchesFromEnv(); len(got) != 0 {
+		t.Errorf("Empty env should disable protection, got %v", got)
+	}
+}
+
+// TestHandleClient_BlockProtectedBranchRejected verifies that a block request for a
+// protected branch is answered with block_rejected and leaves state and disk untouched.
+func TestHandleClient_BlockProtectedBranchRejected(t *testing.T) {
+	blockedPath := filepath.Join(t.TempDir(), "blocked-branches.json")
+	daemon := &AlertDaemon{
+		clients:           make(map[string]*clientConnection),
+		alerts:            make(map[string]string),
+		blockedBranches:   BlockMap{"feature-1": {"feature-2"}},
+		blockedPath:       blockedPath,
+		protectedBranches: []string{"main"},
+	}
+	daemon.lastBroadcastError.Store("")
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go daemon.handleClient(serverConn)
+
+	encoder := json.NewEncoder(clientConn)
+	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "protected-client", ProtocolVersion: ProtocolVersion}); err != nil {
+		t.Fatalf("Failed to send hello: %v", err)
+	}
+
+	received := make(chan Message, 10)
+	go func() {
+		decoder := json.NewDecoder(clientConn)
+		for {
+			var msg Message
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			received <- msg
+		}
+	}()
+
+	waitFor := func(msgType string) Message {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case msg := <-received:
+				if msg.Type == msgType {
+					return msg
+				}
+				if msg.Type == MsgTypeBlockChange {
+					t.Fatalf("Unexpected block_change for protected branch: %+v", msg)
+				}
+			case <-timeout:
+				t.Fatalf("Timed out waiting for %s", msgType)
+			}
+		}
+	}
+	waitFor(MsgTypeFullState)
+
+	if err := encoder.Encode(Message{Type: MsgTypeBlockBranch, Branch: "main", BlockedBranch: "feature-1"}); err != nil {
+		t.Fatalf("Failed to send block request: %v", err)
+	}
+
+	rejected := waitFor(MsgTypeBlockRejected)
+	if rejected.Branch != "main" || rejected.BlockedBranch != "feature-1" {
+		t.Errorf("Rejection for wrong request: branch=%q blockedBy=%q", rejected.Branch, rejected.BlockedBranch)
+	}
+	if rejected.Error != "protected branch: 'main' cannot be blocked" {
+		t.Errorf("Unexpected rejection reason: %q", rejected.Error)
+	}
+
+	want := BlockMap{"feature-1": {"feature-2"}}
+	if got := daemon.copyBlockedBranches(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocked state changed: got %v, want %v", got, want)
+	}
+	if _, err := os.Stat(blockedPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no persistence write for rejected block, stat err=%v", err)
+	}
+}
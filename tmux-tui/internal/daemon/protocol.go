@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/tmux"
+	"github.com/commons-systems/tmux-tui/internal/watcher"
 )
 
 // TODO(#280): Document error variables with usage context - see PR review for #273
@@ -15,17 +16,21 @@ import (
 var (
 	ErrQueryChannelFull   = errors.New("query response channel full")
 	ErrQueryChannelClosed = errors.New("query response channel closed")
-	ErrQueryTimeout       = errors.New("timeout waiting for blocked state response (2s). " +
+	ErrQueryTimeout       = errors.New("timeout waiting for blocked state response. " +
 		"Troubleshooting: Check daemon health with 'tmux-tui-daemon health' " +
 		"or review debug logs for sequence gaps")
 )
 
 // Connection error types
 var (
-	ErrConnectionTimeout = errors.New("timeout connecting to daemon")
-	ErrConnectionFailed  = errors.New("connection to daemon failed")
-	ErrSocketNotFound    = errors.New("socket not found")
-	ErrPermissionDenied  = errors.New("permission denied")
+	ErrConnectionTimeout  = errors.New("timeout connecting to daemon")
+	ErrConnectionFailed   = errors.New("connection to daemon failed")
+	ErrSocketNotFound     = errors.New("socket not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrClientClosed       = errors.New("daemon client closed")
+	ErrNamespaceMismatch  = errors.New("daemon belongs to a different tmux server")
+	ErrConnectionRejected = errors.New("daemon rejected connection")
+	ErrProtocolMismatch   = errors.New("daemon speaks an incompatible protocol version")
 )
 
 // Health status error types
@@ -107,6 +112,69 @@ const (
 	MsgTypeTreeUpdate = "tree_update"
 	// MsgTypeTreeError is sent by daemon when tree collection fails
 	MsgTypeTreeError = "tree_error"
+	// MsgTypeBlockRejected is sent by daemon to the requesting client when a block_branch request is refused
+	MsgTypeBlockRejected = "block_rejected"
+	// MsgTypeImportBlocks is sent by client to merge or replace the daemon's blocked branches
+	MsgTypeImportBlocks = "import_blocks"
+	// MsgTypeImportBlocksResult is sent by daemon to the importing client with the outcome
+	MsgTypeImportBlocksResult = "import_blocks_result"
+	// MsgTypeListQuery is sent by client to request the current alerts and blocked branches
+	MsgTypeListQuery = "list_query"
+	// MsgTypeListResponse is sent by daemon with the current alerts and blocked branches
+	MsgTypeListResponse = "list_response"
+	// MsgTypeUnblockAll is sent by client to clear every blocked branch
+	MsgTypeUnblockAll = "unblock_all"
+	// MsgTypeClientsQuery is sent by client to request the list of connected clients
+	MsgTypeClientsQuery = "clients_query"
+	// MsgTypeClientsResponse is sent by daemon with every connected client
+	MsgTypeClientsResponse = "clients_response"
+	// MsgTypeQueryAllBlocked is sent by client to query every blocked branch in one round trip
+	MsgTypeQueryAllBlocked = "query_all_blocked"
+	// MsgTypeAllBlockedResponse is sent by daemon in response to query_all_blocked
+	MsgTypeAllBlockedResponse = "all_blocked_response"
+	// MsgTypeRejected is sent by daemon instead of full_state when it refuses a connection
+	// (e.g. the client limit is reached); the daemon closes the connection after it
+	MsgTypeRejected = "rejected"
+	// MsgTypeRenameBranch is sent by client to move a branch's blocks to its new name
+	// after a git branch rename
+	MsgTypeRenameBranch = "rename_branch"
+	// MsgTypeRenameBranchResult is sent by daemon to the renaming client with the outcome
+	MsgTypeRenameBranchResult = "rename_branch_result"
+	// MsgTypeVersionMismatch is sent by daemon instead of full_state when the client's hello
+	// names an incompatible protocol version; the daemon closes the connection after it
+	MsgTypeVersionMismatch = "version_mismatch"
+)
+
+// ProtocolVersion is the wire protocol version this build speaks, as "MAJOR.MINOR".
+// Clients send it in hello. The major version changes when peers can no longer
+// understand each other's messages, and the daemon refuses clients with another one
+// rather than silently ignoring what it can't parse.
+//
+// Version 3 sends blocked_branches as a BlockMap (branch -> list of blockers); version 2
+// sent a single blocker per branch, which version 2 peers can't decode from version 3.
+const ProtocolVersion = "3.0"
+
+// CheckProtocolVersion returns an error wrapping ErrProtocolMismatch unless a peer
+// speaking version can talk to this build. An empty version comes from clients that
+// predate versioning, which speak major version 2, and is refused like any other.
+func CheckProtocolVersion(version string) error {
+	if version == "" {
+		return fmt.Errorf("%w: client sent no protocol version (it predates versioning), daemon speaks %s", ErrProtocolMismatch, ProtocolVersion)
+	}
+	own, _, _ := strings.Cut(ProtocolVersion, ".")
+	major, _, _ := strings.Cut(version, ".")
+	if major != own {
+		return fmt.Errorf("%w: client speaks protocol %s, daemon speaks %s", ErrProtocolMismatch, version, ProtocolVersion)
+	}
+	return nil
+}
+
+// Import modes for import_blocks messages
+const (
+	// ImportModeMerge adds the imported blocks, overwriting entries for the same branch
+	ImportModeMerge = "merge"
+	// ImportModeReplace discards existing blocks before applying the imported ones
+	ImportModeReplace = "replace"
 )
 
 // Message represents a message exchanged between daemon and clients
@@ -119,7 +187,9 @@ type Message struct {
 	PaneID          string            `json:"pane_id,omitempty"`           // For alert_change and block messages
 	EventType       string            `json:"event_type,omitempty"`        // For alert_change messages
 	Created         bool              `json:"created,omitempty"`           // For alert_change messages
-	ActivePaneID    string            `json:"active_pane_id,omitempty"`    // For pane_focus messages
+	AlertedAt       time.Time         `json:"alerted_at,omitzero"`         // For alert_change messages: when the pane entered its alert state (zero for removals)
+	Severity        string            `json:"severity,omitempty"`          // For alert_change messages (optional): low, normal or high
+	ActivePaneID    string            `json:"active_pane_id,omitempty"`    // For pane_focus and full_state messages
 	// BlockedPanes maps paneID to the branch it's blocked on (inverse of BlockedBranches)
 	//
 	// TODO(#280): Update deprecation timeline to specific version - see PR review for #273
@@ -139,14 +209,32 @@ type Message struct {
 	//
 	// Example of what this WOULD have been: {"pane-1": "main"} means pane-1 is blocked on branch main
 	BlockedPanes    map[string]string `json:"blocked_panes,omitempty"`
-	BlockedBranches map[string]string `json:"blocked_branches,omitempty"` // Full blocked state: branch -> blockedByBranch
-	Branch          string            `json:"branch,omitempty"`           // For block_branch messages
-	BlockedBranch   string            `json:"blocked_branch,omitempty"`   // For block_branch messages
+	BlockedBranches BlockMap          `json:"blocked_branches,omitempty"` // Full blocked state: branch -> blocking branches (also accepts the old branch -> blocker form)
+	ImportMode      string            `json:"import_mode,omitempty"`      // For import_blocks messages (merge or replace)
+	Branch          string            `json:"branch,omitempty"`           // For block_branch messages and, as the old name, rename_branch(_result)
+	NewBranch       string            `json:"new_branch,omitempty"`       // For rename_branch and rename_branch_result messages
+	BlockedBranch   string            `json:"blocked_branch,omitempty"`   // For block_branch, unblock_branch (optional: remove only this blocker) and, as the first blocker, block_change/blocked_state_response
+	Blockers        []string          `json:"blockers,omitempty"`         // For block_change and blocked_state_response messages: all blocking branches
 	Blocked         bool              `json:"blocked,omitempty"`          // For block_change messages (true = blocked, false = unblocked)
 	IsBlocked       bool              `json:"is_blocked,omitempty"`       // For blocked_state_response messages
-	Error           string            `json:"error,omitempty"`            // For persistence_error and sync_warning messages
+	CyclePath       []string          `json:"cycle_path,omitempty"`       // For block_rejected messages refusing a block cycle: branch -> blocker -> ... -> branch
+	Error           string            `json:"error,omitempty"`            // For persistence_error, sync_warning, block_rejected rejected (reason), import_blocks_result and rename_branch_result messages; on tree_update, why the tree is partial
 	HealthStatus    *HealthStatus     `json:"health_status,omitempty"`    // For health_response messages
 	Tree            *tmux.RepoTree    `json:"tree,omitempty"`             // Should be non-nil for tree_update only (not enforced by ValidateMessage). See Tree Pointer Safety comment above for usage.
+	Clients         []ClientInfo      `json:"clients,omitempty"`          // For clients_response messages
+	Namespace       string            `json:"namespace,omitempty"`        // For full_state messages: the daemon's session namespace directory
+	Force           bool              `json:"force,omitempty"`            // For resync_request messages: send full_state even if unchanged since the last one
+	ProtocolVersion string            `json:"protocol_version,omitempty"` // For hello (the client's) and version_mismatch (the daemon's) messages
+
+	// AlertTimes holds AlertedAt for each alert in a full_state whose start is known
+	AlertTimes map[string]time.Time `json:"alert_times,omitempty"`
+
+	// TTL is how long a block lasts before the daemon removes it, for show_block_picker and
+	// block_branch messages (0 = never expires)
+	TTL time.Duration `json:"ttl_ns,omitempty"`
+	// BlockExpiries holds when expiring blocks end: every block in a full_state, or the
+	// message's branch in a block_change (absent = no expiring blockers)
+	BlockExpiries BlockExpiries `json:"block_expiries,omitempty"`
 }
 
 // PROTOCOL V2 MIGRATION GUIDE
@@ -171,7 +259,7 @@ type Message struct {
 //
 // EXAMPLE SERVER USAGE:
 //   // Create type-safe v2 message
-//   msg, err := NewAlertChangeMessage(seqNum, paneID, eventType, created)
+//   msg, err := NewAlertChangeMessage(seqNum, paneID, eventType, created, alertedAt, severity)
 //   if err != nil {
 //       // Handle validation error (required fields missing)
 //       return
@@ -221,9 +309,14 @@ type HealthStatus struct {
 	lastTreeBroadcastErr    string // Most recent tree broadcast error
 	treeMsgConstructErrors  int64  // Total tree message construction failures
 	lastTreeMsgConstructErr string // Most recent tree message construction error
+	resyncFailures          int64  // Total full_state resyncs that failed to send
 	connectedClients        int
 	activeAlerts            int
 	blockedBranches         int
+	lastBroadcastDuration   time.Duration // Duration of the most recent broadcast to all clients
+	avgBroadcastDuration    time.Duration // Rolling average broadcast duration
+	startTime               time.Time     // When the daemon was created (zero if unknown)
+	version                 string        // Build of the running daemon (empty if unknown)
 }
 
 // NewHealthStatus creates a validated HealthStatus with current timestamp.
@@ -336,6 +429,9 @@ func (h HealthStatus) GetTreeMsgConstructErrors() int64 { return h.treeMsgConstr
 // GetLastTreeMsgConstructError returns the most recent tree message construction error
 func (h HealthStatus) GetLastTreeMsgConstructError() string { return h.lastTreeMsgConstructErr }
 
+// GetResyncFailures returns the total resyncs that failed to reach a client
+func (h HealthStatus) GetResyncFailures() int64 { return h.resyncFailures }
+
 // GetConnectedClients returns the current number of connected clients
 func (h HealthStatus) GetConnectedClients() int { return h.connectedClients }
 
@@ -345,6 +441,27 @@ func (h HealthStatus) GetActiveAlerts() int { return h.activeAlerts }
 // GetBlockedBranches returns the current number of blocked branches
 func (h HealthStatus) GetBlockedBranches() int { return h.blockedBranches }
 
+// GetLastBroadcastDuration returns how long the most recent broadcast took to send to all clients
+func (h HealthStatus) GetLastBroadcastDuration() time.Duration { return h.lastBroadcastDuration }
+
+// GetAvgBroadcastDuration returns the rolling average broadcast duration
+func (h HealthStatus) GetAvgBroadcastDuration() time.Duration { return h.avgBroadcastDuration }
+
+// GetStartTime returns when the daemon was started (zero if unknown)
+func (h HealthStatus) GetStartTime() time.Time { return h.startTime }
+
+// GetVersion returns the running daemon's build (empty if unknown)
+func (h HealthStatus) GetVersion() string { return h.version }
+
+// GetUptime returns how long the daemon had been running when the status was captured
+// (0 if the start time is unknown)
+func (h HealthStatus) GetUptime() time.Duration {
+	if h.startTime.IsZero() || h.timestamp.Before(h.startTime) {
+		return 0
+	}
+	return h.timestamp.Sub(h.startTime)
+}
+
 // HealthStatusBuilder provides a fluent API for constructing HealthStatus instances.
 // This builder pattern improves readability compared to the 15-parameter NewHealthStatus constructor.
 //
@@ -357,7 +474,10 @@ func (h HealthStatus) GetBlockedBranches() int { return h.blockedBranches }
 //	    WithAudioMetrics(failures, lastErr).
 //	    WithTreeBroadcastMetrics(errors, lastErr).
 //	    WithTreeConstructMetrics(errors, lastErr).
+//	    WithResyncMetrics(failures).
 //	    WithCounters(clients, alerts, blocked).
+//	    WithBroadcastLatency(last, avg).
+//	    WithStartTime(started).
 //	    Build()
 type HealthStatusBuilder struct {
 	broadcastFailures       int64
@@ -372,9 +492,14 @@ type HealthStatusBuilder struct {
 	lastTreeBroadcastErr    string
 	treeMsgConstructErrors  int64
 	lastTreeMsgConstructErr string
+	resyncFailures          int64
 	connectedClients        int
 	activeAlerts            int
 	blockedBranches         int
+	lastBroadcastDuration   time.Duration
+	avgBroadcastDuration    time.Duration
+	startTime               time.Time
+	version                 string
 }
 
 // NewHealthStatusBuilder creates a new HealthStatusBuilder with zero values.
@@ -425,6 +550,12 @@ func (b *HealthStatusBuilder) WithTreeConstructMetrics(errors int64, lastErr str
 	return b
 }
 
+// WithResyncMetrics sets the number of failed full_state resyncs.
+func (b *HealthStatusBuilder) WithResyncMetrics(failures int64) *HealthStatusBuilder {
+	b.resyncFailures = failures
+	return b
+}
+
 // WithCounters sets current state counters (clients, alerts, blocked branches).
 func (b *HealthStatusBuilder) WithCounters(clients, alerts, blocked int) *HealthStatusBuilder {
 	b.connectedClients = clients
@@ -433,11 +564,40 @@ func (b *HealthStatusBuilder) WithCounters(clients, alerts, blocked int) *Health
 	return b
 }
 
+// WithBroadcastLatency sets the last and rolling average broadcast durations.
+func (b *HealthStatusBuilder) WithBroadcastLatency(last, avg time.Duration) *HealthStatusBuilder {
+	b.lastBroadcastDuration = last
+	b.avgBroadcastDuration = avg
+	return b
+}
+
+// WithStartTime sets when the daemon was started, from which the uptime is derived.
+func (b *HealthStatusBuilder) WithStartTime(started time.Time) *HealthStatusBuilder {
+	b.startTime = started
+	return b
+}
+
+// WithVersion sets the running daemon's build, for spotting client/daemon version skew.
+func (b *HealthStatusBuilder) WithVersion(version string) *HealthStatusBuilder {
+	b.version = version
+	return b
+}
+
 // Build creates a validated HealthStatus from the builder's current state.
-// Returns error if any count fields are negative.
+// Returns error if any count fields or durations are negative.
 func (b *HealthStatusBuilder) Build() (HealthStatus, error) {
+	if b.lastBroadcastDuration < 0 {
+		return HealthStatus{}, fmt.Errorf("lastBroadcastDuration must be non-negative, got %v", b.lastBroadcastDuration)
+	}
+	if b.avgBroadcastDuration < 0 {
+		return HealthStatus{}, fmt.Errorf("avgBroadcastDuration must be non-negative, got %v", b.avgBroadcastDuration)
+	}
+	if b.resyncFailures < 0 {
+		return HealthStatus{}, fmt.Errorf("resyncFailures must be non-negative, got %d", b.resyncFailures)
+	}
+
 	// Delegate to NewHealthStatus for validation and construction
-	return NewHealthStatus(
+	status, err := NewHealthStatus(
 		b.broadcastFailures,
 		b.lastBroadcastError,
 		b.watcherErrors,
@@ -454,6 +614,15 @@ func (b *HealthStatusBuilder) Build() (HealthStatus, error) {
 		b.activeAlerts,
 		b.blockedBranches,
 	)
+	if err != nil {
+		return HealthStatus{}, err
+	}
+	status.resyncFailures = b.resyncFailures
+	status.lastBroadcastDuration = b.lastBroadcastDuration
+	status.avgBroadcastDuration = b.avgBroadcastDuration
+	status.startTime = b.startTime
+	status.version = b.version
+	return status, nil
 }
 
 // MarshalJSON implements custom JSON marshaling to maintain wire protocol compatibility
@@ -472,9 +641,15 @@ func (h HealthStatus) MarshalJSON() ([]byte, error) {
 		LastTreeBroadcastErr    string    `json:"last_tree_broadcast_error"`
 		TreeMsgConstructErrors  int64     `json:"tree_msg_construct_errors"`
 		LastTreeMsgConstructErr string    `json:"last_tree_msg_construct_error"`
+		ResyncFailures          int64     `json:"resync_failures"`
 		ConnectedClients        int       `json:"connected_clients"`
 		ActiveAlerts            int       `json:"active_alerts"`
 		BlockedBranches         int       `json:"blocked_branches"`
+		LastBroadcastNanos      int64     `json:"last_broadcast_duration_ns"`
+		AvgBroadcastNanos       int64     `json:"avg_broadcast_duration_ns"`
+		StartTime               time.Time `json:"start_time"`
+		UptimeNanos             int64     `json:"uptime_ns"`
+		Version                 string    `json:"version,omitempty"`
 	}{
 		Timestamp:               h.timestamp,
 		BroadcastFailures:       h.broadcastFailures,
@@ -489,9 +664,15 @@ func (h HealthStatus) MarshalJSON() ([]byte, error) {
 		LastTreeBroadcastErr:    h.lastTreeBroadcastErr,
 		TreeMsgConstructErrors:  h.treeMsgConstructErrors,
 		LastTreeMsgConstructErr: h.lastTreeMsgConstructErr,
+		ResyncFailures:          h.resyncFailures,
 		ConnectedClients:        h.connectedClients,
 		ActiveAlerts:            h.activeAlerts,
 		BlockedBranches:         h.blockedBranches,
+		LastBroadcastNanos:      int64(h.lastBroadcastDuration),
+		AvgBroadcastNanos:       int64(h.avgBroadcastDuration),
+		StartTime:               h.startTime,
+		UptimeNanos:             int64(h.GetUptime()),
+		Version:                 h.version,
 	})
 }
 
@@ -511,9 +692,14 @@ func (h *HealthStatus) UnmarshalJSON(data []byte) error {
 		LastTreeBroadcastErr    string    `json:"last_tree_broadcast_error"`
 		TreeMsgConstructErrors  int64     `json:"tree_msg_construct_errors"`
 		LastTreeMsgConstructErr string    `json:"last_tree_msg_construct_error"`
+		ResyncFailures          int64     `json:"resync_failures"`
 		ConnectedClients        int       `json:"connected_clients"`
 		ActiveAlerts            int       `json:"active_alerts"`
 		BlockedBranches         int       `json:"blocked_branches"`
+		LastBroadcastNanos      int64     `json:"last_broadcast_duration_ns"`
+		AvgBroadcastNanos       int64     `json:"avg_broadcast_duration_ns"`
+		StartTime               time.Time `json:"start_time"` // uptime_ns is derived from this and timestamp
+		Version                 string    `json:"version"`
 	}{}
 
 	if err := json.Unmarshal(data, aux); err != nil {
@@ -539,6 +725,9 @@ func (h *HealthStatus) UnmarshalJSON(data []byte) error {
 	if aux.TreeMsgConstructErrors < 0 {
 		return fmt.Errorf("invalid tree_msg_construct_errors: %d", aux.TreeMsgConstructErrors)
 	}
+	if aux.ResyncFailures < 0 {
+		return fmt.Errorf("invalid resync_failures: %d", aux.ResyncFailures)
+	}
 	if aux.ConnectedClients < 0 {
 		return fmt.Errorf("invalid connected_clients: %d", aux.ConnectedClients)
 	}
@@ -548,6 +737,12 @@ func (h *HealthStatus) UnmarshalJSON(data []byte) error {
 	if aux.BlockedBranches < 0 {
 		return fmt.Errorf("invalid blocked_branches: %d", aux.BlockedBranches)
 	}
+	if aux.LastBroadcastNanos < 0 {
+		return fmt.Errorf("invalid last_broadcast_duration_ns: %d", aux.LastBroadcastNanos)
+	}
+	if aux.AvgBroadcastNanos < 0 {
+		return fmt.Errorf("invalid avg_broadcast_duration_ns: %d", aux.AvgBroadcastNanos)
+	}
 
 	h.timestamp = aux.Timestamp
 	h.broadcastFailures = aux.BroadcastFailures
@@ -562,9 +757,14 @@ func (h *HealthStatus) UnmarshalJSON(data []byte) error {
 	h.lastTreeBroadcastErr = aux.LastTreeBroadcastErr
 	h.treeMsgConstructErrors = aux.TreeMsgConstructErrors
 	h.lastTreeMsgConstructErr = aux.LastTreeMsgConstructErr
+	h.resyncFailures = aux.ResyncFailures
 	h.connectedClients = aux.ConnectedClients
 	h.activeAlerts = aux.ActiveAlerts
 	h.blockedBranches = aux.BlockedBranches
+	h.lastBroadcastDuration = time.Duration(aux.LastBroadcastNanos)
+	h.avgBroadcastDuration = time.Duration(aux.AvgBroadcastNanos)
+	h.startTime = aux.StartTime
+	h.version = aux.Version
 
 	return nil
 }
@@ -572,38 +772,64 @@ func (h *HealthStatus) UnmarshalJSON(data []byte) error {
 // BlockedState represents the result of checking if a branch is blocked
 type BlockedState struct {
 	isBlocked bool
-	blockedBy string // Empty if not blocked
+	blockers  []string // Empty if not blocked
 }
 
 // IsBlocked returns whether the branch is blocked
 func (b BlockedState) IsBlocked() bool { return b.isBlocked }
 
-// BlockedBy returns the branch that is blocking (empty if not blocked)
-func (b BlockedState) BlockedBy() string { return b.blockedBy }
+// BlockedBy returns the first (oldest) blocking branch (empty if not blocked).
+// Use Blockers when the branch may be blocked by more than one branch.
+func (b BlockedState) BlockedBy() string {
+	if len(b.blockers) == 0 {
+		return ""
+	}
+	return b.blockers[0]
+}
 
-// NewBlockedState creates a validated BlockedState.
+// Blockers returns a copy of all blocking branches, oldest first (nil if not blocked)
+func (b BlockedState) Blockers() []string {
+	if len(b.blockers) == 0 {
+		return nil
+	}
+	return append([]string(nil), b.blockers...)
+}
+
+// NewBlockedState creates a validated BlockedState. Blocker names are trimmed and
+// empty names are dropped.
 // Returns error if:
 //   - IsBlocked is false but BlockedBy is provided (non-empty after trimming)
 //   - IsBlocked is true but BlockedBy is empty (empty after trimming whitespace)
-func NewBlockedState(isBlocked bool, blockedBy string) (BlockedState, error) {
-	blockedBy = strings.TrimSpace(blockedBy)
+func NewBlockedState(isBlocked bool, blockedBy ...string) (BlockedState, error) {
+	blockers := trimBlockers(blockedBy)
 
 	// Validation: BlockedBy must be empty when not blocked
-	if !isBlocked && blockedBy != "" {
-		return BlockedState{}, fmt.Errorf("blockedBy must be empty when not blocked, got %q", blockedBy)
+	if !isBlocked && len(blockers) > 0 {
+		return BlockedState{}, fmt.Errorf("blockedBy must be empty when not blocked, got %q", strings.Join(blockers, ", "))
 	}
 
 	// Validation: BlockedBy must be specified when blocked
-	if isBlocked && blockedBy == "" {
+	if isBlocked && len(blockers) == 0 {
 		return BlockedState{}, fmt.Errorf("blockedBy must be specified when blocked")
 	}
 
 	return BlockedState{
 		isBlocked: isBlocked,
-		blockedBy: blockedBy,
+		blockers:  blockers,
 	}, nil
 }
 
+// trimBlockers returns the trimmed, non-empty blocker names (nil if there are none).
+func trimBlockers(blockers []string) []string {
+	var result []string
+	for _, blocker := range blockers {
+		if blocker = strings.TrimSpace(blocker); blocker != "" {
+			result = append(result, blocker)
+		}
+	}
+	return result
+}
+
 // ValidateMessage validates that a Message has required fields for its type.
 // Returns nil if valid, error describing the problem if invalid.
 //
@@ -626,6 +852,11 @@ func ValidateMessage(msg Message) error {
 		if msg.EventType == "" {
 			return errors.New("alert_change message requires event_type")
 		}
+		if msg.Severity != "" {
+			if _, err := watcher.ParseSeverity(msg.Severity); err != nil {
+				return fmt.Errorf("alert_change message has invalid severity: %w", err)
+			}
+		}
 	case MsgTypePaneFocus:
 		if msg.ActivePaneID == "" {
 			return errors.New("pane_focus message requires active_pane_id")
@@ -634,6 +865,9 @@ func ValidateMessage(msg Message) error {
 		if msg.PaneID == "" {
 			return errors.New("show_block_picker message requires pane_id")
 		}
+		if msg.TTL < 0 {
+			return fmt.Errorf("show_block_picker message has negative ttl %v", msg.TTL)
+		}
 	case MsgTypeBlockBranch:
 		if msg.Branch == "" {
 			return errors.New("block_branch message requires branch")
@@ -641,6 +875,9 @@ func ValidateMessage(msg Message) error {
 		if msg.BlockedBranch == "" {
 			return errors.New("block_branch message requires blocked_branch")
 		}
+		if msg.TTL < 0 {
+			return fmt.Errorf("block_branch message has negative ttl %v", msg.TTL)
+		}
 	case MsgTypeUnblockBranch:
 		if msg.Branch == "" {
 			return errors.New("unblock_branch message requires branch")
@@ -653,7 +890,9 @@ func ValidateMessage(msg Message) error {
 		if msg.Branch == "" {
 			return errors.New("blocked_state_response message requires branch")
 		}
-	case MsgTypeFullState, MsgTypePing, MsgTypePong, MsgTypeResyncRequest, MsgTypeHealthQuery, MsgTypeHealthResponse:
+	case MsgTypeFullState, MsgTypePing, MsgTypePong, MsgTypeResyncRequest, MsgTypeHealthQuery, MsgTypeHealthResponse,
+		MsgTypeListQuery, MsgTypeListResponse, MsgTypeUnblockAll, MsgTypeClientsQuery, MsgTypeClientsResponse,
+		MsgTypeQueryAllBlocked, MsgTypeAllBlockedResponse:
 		// No required fields
 	case MsgTypeSyncWarning, MsgTypePersistenceError, MsgTypeAudioError:
 		// Error field is optional but recommended
@@ -662,6 +901,38 @@ func ValidateMessage(msg Message) error {
 			return errors.New("tree_update message requires non-nil tree field - " +
 				"possible daemon bug in ToWireFormat() or message construction")
 		}
+	case MsgTypeBlockRejected:
+		if msg.Branch == "" {
+			return errors.New("block_rejected message requires branch")
+		}
+		if strings.TrimSpace(msg.Error) == "" {
+			return errors.New("block_rejected message requires error (rejection reason)")
+		}
+	case MsgTypeRejected:
+		if strings.TrimSpace(msg.Error) == "" {
+			return errors.New("rejected message requires error (rejection reason)")
+		}
+	case MsgTypeVersionMismatch:
+		if msg.ProtocolVersion == "" {
+			return errors.New("version_mismatch message requires protocol_version")
+		}
+		if strings.TrimSpace(msg.Error) == "" {
+			return errors.New("version_mismatch message requires error (mismatch reason)")
+		}
+	case MsgTypeImportBlocks:
+		if msg.ImportMode != ImportModeMerge && msg.ImportMode != ImportModeReplace {
+			return fmt.Errorf("import_blocks message requires import_mode %q or %q, got %q",
+				ImportModeMerge, ImportModeReplace, msg.ImportMode)
+		}
+	case MsgTypeImportBlocksResult:
+		// Error is set on failure; BlockedBranches (possibly empty) on success
+	case MsgTypeRenameBranch, MsgTypeRenameBranchResult:
+		if msg.Branch == "" {
+			return fmt.Errorf("%s message requires branch", msg.Type)
+		}
+		if msg.NewBranch == "" {
+			return fmt.Errorf("%s message requires new_branch", msg.Type)
+		}
 	case MsgTypeTreeError:
 		errorMsg := strings.TrimSpace(msg.Error)
 		if errorMsg == "" {
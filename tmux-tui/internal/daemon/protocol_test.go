@@ -81,8 +81,8 @@ func TestValidateMessage_Valid(t *testing.T) {
 				Alerts: map[string]string{
 					"pane-1": "idle",
 				},
-				BlockedBranches: map[string]string{
-					"feature": "main",
+				BlockedBranches: BlockMap{
+					"feature": {"main"},
 				},
 			},
 		},
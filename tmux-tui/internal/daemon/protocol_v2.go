@@ -3,10 +3,13 @@ package daemon
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/debug"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
+	"github.com/commons-systems/tmux-tui/internal/watcher"
 )
 
 // TODO(#280): Add tests for FromWireFormat edge cases - see PR review for #273
@@ -22,6 +25,19 @@ func copyStringMap(m map[string]string) map[string]string {
 	return result
 }
 
+// copyTimeMap creates a shallow copy of a string-to-time map.
+// Returns nil for an empty map, so full_state omits alert_times when no start is known.
+func copyTimeMap(m map[string]time.Time) map[string]time.Time {
+	if len(m) == 0 {
+		return nil
+	}
+	result := make(map[string]time.Time, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
 //
 // DESIGN RATIONALE:
 //   - Private fields ensure immutability and encapsulation
@@ -70,11 +86,12 @@ type MessageV2 interface {
 
 // 1. HelloMessageV2 represents a client connection greeting
 type HelloMessageV2 struct {
-	seqNum   uint64
-	clientID string
+	seqNum          uint64
+	clientID        string
+	protocolVersion string
 }
 
-// NewHelloMessage creates a validated HelloMessage.
+// NewHelloMessage creates a validated HelloMessage announcing this build's ProtocolVersion.
 // Returns error if clientID is empty after trimming whitespace.
 func NewHelloMessage(seqNum uint64, clientID string) (*HelloMessageV2, error) {
 	originalClientID := clientID
@@ -83,31 +100,48 @@ func NewHelloMessage(seqNum uint64, clientID string) (*HelloMessageV2, error) {
 		debug.Log("MESSAGE_VALIDATION_FAILED type=hello reason=empty_client_id original=%q", originalClientID)
 		return nil, errors.New("client_id required")
 	}
-	return &HelloMessageV2{seqNum: seqNum, clientID: clientID}, nil
+	return &HelloMessageV2{seqNum: seqNum, clientID: clientID, protocolVersion: ProtocolVersion}, nil
 }
 
 func (m *HelloMessageV2) MessageType() string { return MsgTypeHello }
 func (m *HelloMessageV2) SeqNumber() uint64   { return m.seqNum }
 func (m *HelloMessageV2) ToWireFormat() Message {
 	return Message{
-		Type:     MsgTypeHello,
-		SeqNum:   m.seqNum,
-		ClientID: m.clientID,
+		Type:            MsgTypeHello,
+		SeqNum:          m.seqNum,
+		ClientID:        m.clientID,
+		ProtocolVersion: m.protocolVersion,
 	}
 }
 
 // ClientID returns the client identifier
 func (m *HelloMessageV2) ClientID() string { return m.clientID }
 
+// ProtocolVersion returns the client's protocol version (empty for clients that
+// predate versioning)
+func (m *HelloMessageV2) ProtocolVersion() string { return m.protocolVersion }
+
 // 2. FullStateMessageV2 represents complete alert state snapshot
 type FullStateMessageV2 struct {
 	seqNum          uint64
 	alerts          map[string]string
-	blockedBranches map[string]string
+	alertTimes      map[string]time.Time
+	blockedBranches BlockMap
+	blockExpiries   BlockExpiries
+	activePaneID    string
+	namespace       string
 }
 
 // NewFullStateMessage creates a validated FullStateMessage.
 // Alerts and blockedBranches can be nil or empty maps (represents no active state).
+// activePaneID is the currently focused pane, or empty if the daemon has not seen a
+// focus event yet. It lets newly connected clients highlight the active pane
+// without waiting for the next pane_focus message.
+// alertTimes holds when each alert started, for panes where that is known (see
+// AlertChangeMessageV2.AlertedAt); it can be nil.
+// blockExpiries holds when expiring blocks end; it can be nil (no block expires).
+// namespace is the daemon's session namespace directory, which clients compare with their
+// own to detect a daemon serving a different tmux server; empty skips the check.
 // Map keys and values are not currently validated (see TODO #519).
 // TODO(#519): Add validation for empty/whitespace-only keys in maps (should reject).
 // TODO(#519): Clarify empty value semantics:
@@ -117,11 +151,15 @@ type FullStateMessageV2 struct {
 // Current behavior: Empty values are accepted and preserved in state, which may
 // lead to ambiguous state representation. Define explicit semantics before adding validation.
 // FIXME: This is known-bad behavior that should be addressed before production use.
-func NewFullStateMessage(seqNum uint64, alerts, blockedBranches map[string]string) (*FullStateMessageV2, error) {
+func NewFullStateMessage(seqNum uint64, alerts map[string]string, alertTimes map[string]time.Time, blockedBranches BlockMap, blockExpiries BlockExpiries, activePaneID, namespace string) (*FullStateMessageV2, error) {
 	return &FullStateMessageV2{
 		seqNum:          seqNum,
 		alerts:          copyStringMap(alerts),
-		blockedBranches: copyStringMap(blockedBranches),
+		alertTimes:      copyTimeMap(alertTimes),
+		blockedBranches: blockedBranches.Clone(),
+		blockExpiries:   blockExpiries.Clone(),
+		activePaneID:    strings.TrimSpace(activePaneID),
+		namespace:       namespace,
 	}, nil
 }
 
@@ -132,7 +170,11 @@ func (m *FullStateMessageV2) ToWireFormat() Message {
 		Type:            MsgTypeFullState,
 		SeqNum:          m.seqNum,
 		Alerts:          m.alerts,
+		AlertTimes:      m.alertTimes,
 		BlockedBranches: m.blockedBranches,
+		BlockExpiries:   m.blockExpiries,
+		ActivePaneID:    m.activePaneID,
+		Namespace:       m.namespace,
 	}
 }
 
@@ -141,21 +183,43 @@ func (m *FullStateMessageV2) Alerts() map[string]string {
 	return copyStringMap(m.alerts)
 }
 
+// AlertTimes returns a copy of the known alert start times (paneID -> time)
+func (m *FullStateMessageV2) AlertTimes() map[string]time.Time {
+	return copyTimeMap(m.alertTimes)
+}
+
 // BlockedBranches returns a copy of the blocked branches state to prevent mutation
-func (m *FullStateMessageV2) BlockedBranches() map[string]string {
-	return copyStringMap(m.blockedBranches)
+func (m *FullStateMessageV2) BlockedBranches() BlockMap {
+	return m.blockedBranches.Clone()
+}
+
+// BlockExpiries returns a copy of when expiring blocks end
+func (m *FullStateMessageV2) BlockExpiries() BlockExpiries {
+	return m.blockExpiries.Clone()
 }
 
+// ActivePaneID returns the focused pane at the time of the snapshot (empty if unknown)
+func (m *FullStateMessageV2) ActivePaneID() string { return m.activePaneID }
+
+// Namespace returns the daemon's session namespace directory (empty if not reported)
+func (m *FullStateMessageV2) Namespace() string { return m.namespace }
+
 // 3. AlertChangeMessageV2 represents a single alert state change
 type AlertChangeMessageV2 struct {
 	seqNum    uint64
 	paneID    string
 	eventType string
 	created   bool
+	alertedAt time.Time
+	severity  string
 }
 
 // NewAlertChangeMessage creates a validated AlertChangeMessage.
 // Returns error if paneID or eventType is empty after trimming.
+// alertedAt is when the pane entered its alert state; it stays the same while the
+// alert persists and is zero for removals.
+// severity is the watcher.Severity the daemon assigns the event type, or empty when
+// unknown (older daemons, removals); a non-empty value must be a known severity.
 // TODO(#522): Add event type constants and validation for recognized event types.
 // Currently any non-empty string is accepted as eventType, which allows typos
 // and inconsistent casing to slip through. Define constants like:
@@ -167,7 +231,7 @@ type AlertChangeMessageV2 struct {
 //   - "Alert" vs "alert" (wrong casing)
 //   - "alrt" vs "alert" (typo)
 //   - "custom_event" (unknown type that should be added to constants first)
-func NewAlertChangeMessage(seqNum uint64, paneID, eventType string, created bool) (*AlertChangeMessageV2, error) {
+func NewAlertChangeMessage(seqNum uint64, paneID, eventType string, created bool, alertedAt time.Time, severity string) (*AlertChangeMessageV2, error) {
 	originalPaneID := paneID
 	originalEventType := eventType
 	paneID = strings.TrimSpace(paneID)
@@ -181,12 +245,22 @@ func NewAlertChangeMessage(seqNum uint64, paneID, eventType string, created bool
 		debug.Log("MESSAGE_VALIDATION_FAILED type=alert_change reason=empty_event_type original=%q", originalEventType)
 		return nil, errors.New("event_type required")
 	}
+	if severity != "" {
+		sev, err := watcher.ParseSeverity(severity)
+		if err != nil {
+			debug.Log("MESSAGE_VALIDATION_FAILED type=alert_change reason=unknown_severity original=%q", severity)
+			return nil, err
+		}
+		severity = string(sev)
+	}
 
 	return &AlertChangeMessageV2{
 		seqNum:    seqNum,
 		paneID:    paneID,
 		eventType: eventType,
 		created:   created,
+		alertedAt: alertedAt,
+		severity:  severity,
 	}, nil
 }
 
@@ -199,6 +273,8 @@ func (m *AlertChangeMessageV2) ToWireFormat() Message {
 		PaneID:    m.paneID,
 		EventType: m.eventType,
 		Created:   m.created,
+		AlertedAt: m.alertedAt,
+		Severity:  m.severity,
 	}
 }
 
@@ -211,6 +287,12 @@ func (m *AlertChangeMessageV2) EventType() string { return m.eventType }
 // Created returns whether the alert was created (true) or removed (false)
 func (m *AlertChangeMessageV2) Created() bool { return m.created }
 
+// AlertedAt returns when the alert started (zero if unknown or removed)
+func (m *AlertChangeMessageV2) AlertedAt() time.Time { return m.alertedAt }
+
+// Severity returns the alert's severity (empty if the daemon didn't send one)
+func (m *AlertChangeMessageV2) Severity() string { return m.severity }
+
 // 4. PaneFocusMessageV2 represents active pane change
 type PaneFocusMessageV2 struct {
 	seqNum       uint64
@@ -247,11 +329,13 @@ type BlockBranchMessageV2 struct {
 	seqNum        uint64
 	branch        string
 	blockedBranch string
+	ttl           time.Duration
 }
 
-// NewBlockBranchMessage creates a validated BlockBranchMessage.
-// Returns error if branch or blockedBranch is empty after trimming.
-func NewBlockBranchMessage(seqNum uint64, branch, blockedBranch string) (*BlockBranchMessageV2, error) {
+// NewBlockBranchMessage creates a validated BlockBranchMessage. ttl is how long the
+// block lasts before the daemon removes it (0 = never expires).
+// Returns error if branch or blockedBranch is empty after trimming, or ttl is negative.
+func NewBlockBranchMessage(seqNum uint64, branch, blockedBranch string, ttl time.Duration) (*BlockBranchMessageV2, error) {
 	originalBranch := branch
 	originalBlockedBranch := blockedBranch
 	branch = strings.TrimSpace(branch)
@@ -265,11 +349,15 @@ func NewBlockBranchMessage(seqNum uint64, branch, blockedBranch string) (*BlockB
 		debug.Log("MESSAGE_VALIDATION_FAILED type=block_branch reason=empty_blocked_branch original=%q", originalBlockedBranch)
 		return nil, errors.New("blocked_branch required")
 	}
+	if ttl < 0 {
+		return nil, fmt.Errorf("ttl must be non-negative, got %v", ttl)
+	}
 
 	return &BlockBranchMessageV2{
 		seqNum:        seqNum,
 		branch:        branch,
 		blockedBranch: blockedBranch,
+		ttl:           ttl,
 	}, nil
 }
 
@@ -281,6 +369,7 @@ func (m *BlockBranchMessageV2) ToWireFormat() Message {
 		SeqNum:        m.seqNum,
 		Branch:        m.branch,
 		BlockedBranch: m.blockedBranch,
+		TTL:           m.ttl,
 	}
 }
 
@@ -290,73 +379,90 @@ func (m *BlockBranchMessageV2) Branch() string { return m.branch }
 // BlockedBranch returns the branch being blocked
 func (m *BlockBranchMessageV2) BlockedBranch() string { return m.blockedBranch }
 
+// TTL returns how long the block lasts (0 = never expires)
+func (m *BlockBranchMessageV2) TTL() time.Duration { return m.ttl }
+
 // 6. UnblockBranchMessageV2 represents a request to unblock a branch
 type UnblockBranchMessageV2 struct {
-	seqNum uint64
-	branch string
+	seqNum        uint64
+	branch        string
+	blockedBranch string
 }
 
 // NewUnblockBranchMessage creates a validated UnblockBranchMessage.
+// blockedBranch is optional: if set, only that blocker is removed and the branch stays
+// blocked by any others; if empty, every blocker is removed.
 // Returns error if branch is empty after trimming.
-func NewUnblockBranchMessage(seqNum uint64, branch string) (*UnblockBranchMessageV2, error) {
+func NewUnblockBranchMessage(seqNum uint64, branch, blockedBranch string) (*UnblockBranchMessageV2, error) {
 	originalBranch := branch
 	branch = strings.TrimSpace(branch)
 	if branch == "" {
 		debug.Log("MESSAGE_VALIDATION_FAILED type=unblock_branch reason=empty_branch original=%q", originalBranch)
 		return nil, errors.New("branch required")
 	}
-	return &UnblockBranchMessageV2{seqNum: seqNum, branch: branch}, nil
+	return &UnblockBranchMessageV2{
+		seqNum:        seqNum,
+		branch:        branch,
+		blockedBranch: strings.TrimSpace(blockedBranch),
+	}, nil
 }
 
 func (m *UnblockBranchMessageV2) MessageType() string { return MsgTypeUnblockBranch }
 func (m *UnblockBranchMessageV2) SeqNumber() uint64   { return m.seqNum }
 func (m *UnblockBranchMessageV2) ToWireFormat() Message {
 	return Message{
-		Type:   MsgTypeUnblockBranch,
-		SeqNum: m.seqNum,
-		Branch: m.branch,
+		Type:          MsgTypeUnblockBranch,
+		SeqNum:        m.seqNum,
+		Branch:        m.branch,
+		BlockedBranch: m.blockedBranch,
 	}
 }
 
 // Branch returns the branch to unblock
 func (m *UnblockBranchMessageV2) Branch() string { return m.branch }
 
+// BlockedBranch returns the single blocker to remove (empty = remove all blockers)
+func (m *UnblockBranchMessageV2) BlockedBranch() string { return m.blockedBranch }
+
 // 7. BlockChangeMessageV2 represents a block state change notification
 type BlockChangeMessageV2 struct {
-	seqNum        uint64
-	branch        string
-	blockedBranch string
-	blocked       bool
+	seqNum   uint64
+	branch   string
+	blockers []string
+	expiries map[string]time.Time
 }
 
-// TODO(#328): Extract repeated conditional validation pattern (blocked=true requires blockedBranch)
-// NewBlockChangeMessage creates a validated BlockChangeMessage.
+// NewBlockChangeMessage creates a validated BlockChangeMessage carrying the branch's
+// complete blocker list after the change. An empty list means the branch is now
+// unblocked. Blocker names are trimmed and empty names are dropped.
+// expiries holds when the branch's expiring blockers end (blocker -> expiry); entries
+// for blockers not in the list are dropped.
 // Returns error if branch is empty after trimming.
-// If blocked is false, blockedBranch should be empty (will be cleared).
-func NewBlockChangeMessage(seqNum uint64, branch, blockedBranch string, blocked bool) (*BlockChangeMessageV2, error) {
+func NewBlockChangeMessage(seqNum uint64, branch string, blockers []string, expiries map[string]time.Time) (*BlockChangeMessageV2, error) {
 	originalBranch := branch
-	originalBlockedBranch := blockedBranch
 	branch = strings.TrimSpace(branch)
-	blockedBranch = strings.TrimSpace(blockedBranch)
 
 	if branch == "" {
 		debug.Log("MESSAGE_VALIDATION_FAILED type=block_change reason=empty_branch original=%q", originalBranch)
 		return nil, errors.New("branch required")
 	}
 
-	// If unblocking, blockedBranch should be empty
-	if !blocked {
-		blockedBranch = ""
-	} else if blockedBranch == "" {
-		debug.Log("MESSAGE_VALIDATION_FAILED type=block_change reason=empty_blocked_branch_when_blocked original=%q blocked=%t", originalBlockedBranch, blocked)
-		return nil, errors.New("blocked_branch required when blocked is true")
+	blockers = trimBlockers(blockers)
+	var kept map[string]time.Time
+	for blocker, expiresAt := range expiries {
+		if slices.Contains(blockers, blocker) && !expiresAt.IsZero() {
+			if kept == nil {
+				kept = make(map[string]time.Time)
+			}
+			kept[blocker] = expiresAt
+		}
 	}
 
 	return &BlockChangeMessageV2{
-		seqNum:        seqNum,
-		branch:        branch,
-		blockedBranch: blockedBranch,
-		blocked:       blocked,
+		seqNum:   seqNum,
+		branch:   branch,
+		blockers: blockers,
+		expiries: kept,
 	}, nil
 }
 
@@ -367,19 +473,45 @@ func (m *BlockChangeMessageV2) ToWireFormat() Message {
 		Type:          MsgTypeBlockChange,
 		SeqNum:        m.seqNum,
 		Branch:        m.branch,
-		BlockedBranch: m.blockedBranch,
-		Blocked:       m.blocked,
+		BlockedBranch: m.BlockedBranch(), // for clients that predate Blockers
+		Blockers:      m.Blockers(),
+		Blocked:       m.Blocked(),
+		BlockExpiries: m.blockExpiries(),
 	}
 }
 
+// blockExpiries returns the branch's expiries in the wire form (nil if none)
+func (m *BlockChangeMessageV2) blockExpiries() BlockExpiries {
+	if len(m.expiries) == 0 {
+		return nil
+	}
+	return BlockExpiries{m.branch: m.Expiries()}
+}
+
 // Branch returns the branch that changed block state
 func (m *BlockChangeMessageV2) Branch() string { return m.branch }
 
-// BlockedBranch returns the blocking branch (empty if unblocked)
-func (m *BlockChangeMessageV2) BlockedBranch() string { return m.blockedBranch }
+// BlockedBranch returns the first blocking branch (empty if unblocked)
+func (m *BlockChangeMessageV2) BlockedBranch() string {
+	if len(m.blockers) == 0 {
+		return ""
+	}
+	return m.blockers[0]
+}
+
+// Blockers returns a copy of all blocking branches (nil if unblocked)
+func (m *BlockChangeMessageV2) Blockers() []string {
+	if len(m.blockers) == 0 {
+		return nil
+	}
+	return append([]string(nil), m.blockers...)
+}
 
 // Blocked returns whether the branch is now blocked
-func (m *BlockChangeMessageV2) Blocked() bool { return m.blocked }
+func (m *BlockChangeMessageV2) Blocked() bool { return len(m.blockers) > 0 }
+
+// Expiries returns a copy of when the branch's expiring blockers end (nil if none expire)
+func (m *BlockChangeMessageV2) Expiries() map[string]time.Time { return copyTimeMap(m.expiries) }
 
 // 8. QueryBlockedStateMessageV2 represents a request to check if a branch is blocked
 type QueryBlockedStateMessageV2 struct {
@@ -414,39 +546,28 @@ func (m *QueryBlockedStateMessageV2) Branch() string { return m.branch }
 
 // 9. BlockedStateResponseMessageV2 represents the response to a blocked state query
 type BlockedStateResponseMessageV2 struct {
-	seqNum        uint64
-	branch        string
-	isBlocked     bool
-	blockedBranch string
+	seqNum   uint64
+	branch   string
+	blockers []string
 }
 
-// TODO(#328): Extract repeated conditional validation pattern (blocked=true requires blockedBranch)
-// NewBlockedStateResponseMessage creates a validated BlockedStateResponseMessage.
-// Returns error if branch is empty or if isBlocked is true but blockedBranch is empty.
-func NewBlockedStateResponseMessage(seqNum uint64, branch string, isBlocked bool, blockedBranch string) (*BlockedStateResponseMessageV2, error) {
+// NewBlockedStateResponseMessage creates a validated BlockedStateResponseMessage with
+// all branches blocking branch (empty = not blocked). Blocker names are trimmed and
+// empty names are dropped.
+// Returns error if branch is empty after trimming.
+func NewBlockedStateResponseMessage(seqNum uint64, branch string, blockers []string) (*BlockedStateResponseMessageV2, error) {
 	originalBranch := branch
-	originalBlockedBranch := blockedBranch
 	branch = strings.TrimSpace(branch)
-	blockedBranch = strings.TrimSpace(blockedBranch)
 
 	if branch == "" {
 		debug.Log("MESSAGE_VALIDATION_FAILED type=blocked_state_response reason=empty_branch original=%q", originalBranch)
 		return nil, errors.New("branch required")
 	}
 
-	// If not blocked, blockedBranch should be empty
-	if !isBlocked {
-		blockedBranch = ""
-	} else if blockedBranch == "" {
-		debug.Log("MESSAGE_VALIDATION_FAILED type=blocked_state_response reason=empty_blocked_branch_when_blocked original=%q is_blocked=%t", originalBlockedBranch, isBlocked)
-		return nil, errors.New("blocked_branch required when is_blocked is true")
-	}
-
 	return &BlockedStateResponseMessageV2{
-		seqNum:        seqNum,
-		branch:        branch,
-		isBlocked:     isBlocked,
-		blockedBranch: blockedBranch,
+		seqNum:   seqNum,
+		branch:   branch,
+		blockers: trimBlockers(blockers),
 	}, nil
 }
 
@@ -457,8 +578,9 @@ func (m *BlockedStateResponseMessageV2) ToWireFormat() Message {
 		Type:          MsgTypeBlockedStateResponse,
 		SeqNum:        m.seqNum,
 		Branch:        m.branch,
-		IsBlocked:     m.isBlocked,
-		BlockedBranch: m.blockedBranch,
+		IsBlocked:     m.IsBlocked(),
+		BlockedBranch: m.BlockedBranch(), // for clients that predate Blockers
+		Blockers:      m.Blockers(),
 	}
 }
 
@@ -466,10 +588,39 @@ func (m *BlockedStateResponseMessageV2) ToWireFormat() Message {
 func (m *BlockedStateResponseMessageV2) Branch() string { return m.branch }
 
 // IsBlocked returns whether the branch is blocked
-func (m *BlockedStateResponseMessageV2) IsBlocked() bool { return m.isBlocked }
+func (m *BlockedStateResponseMessageV2) IsBlocked() bool { return len(m.blockers) > 0 }
+
+// BlockedBranch returns the first blocking branch (empty if not blocked)
+func (m *BlockedStateResponseMessageV2) BlockedBranch() string {
+	if len(m.blockers) == 0 {
+		return ""
+	}
+	return m.blockers[0]
+}
+
+// Blockers returns a copy of all blocking branches (nil if not blocked)
+func (m *BlockedStateResponseMessageV2) Blockers() []string {
+	if len(m.blockers) == 0 {
+		return nil
+	}
+	return append([]string(nil), m.blockers...)
+}
 
-// BlockedBranch returns the blocking branch (empty if not blocked)
-func (m *BlockedStateResponseMessageV2) BlockedBranch() string { return m.blockedBranch }
+// wireBlockers returns the blockers of a block_change or blocked_state_response wire
+// message. Messages from daemons that predate Blockers carry only BlockedBranch.
+// Returns error if the message says the branch is blocked but names no blocker.
+func wireBlockers(blocked bool, blockedBranch string, blockers []string) ([]string, error) {
+	if !blocked {
+		return nil, nil
+	}
+	if len(blockers) == 0 {
+		blockers = []string{blockedBranch}
+	}
+	if len(trimBlockers(blockers)) == 0 {
+		return nil, errors.New("blocked_branch required when blocked is true")
+	}
+	return blockers, nil
+}
 
 // 10. PingMessageV2 represents a health check request
 type PingMessageV2 struct {
@@ -614,11 +765,14 @@ func (m *SyncWarningMessageV2) Error() string { return m.errorMsg }
 // 15. ResyncRequestMessageV2 represents a request to resync state after detecting gaps
 type ResyncRequestMessageV2 struct {
 	seqNum uint64
+	force  bool
 }
 
-// NewResyncRequestMessage creates a validated ResyncRequestMessage.
-func NewResyncRequestMessage(seqNum uint64) (*ResyncRequestMessageV2, error) {
-	return &ResyncRequestMessageV2{seqNum: seqNum}, nil
+// NewResyncRequestMessage creates a validated ResyncRequestMessage. The daemon skips
+// the full_state reply when the client already has the current state, unless force
+// is set.
+func NewResyncRequestMessage(seqNum uint64, force bool) (*ResyncRequestMessageV2, error) {
+	return &ResyncRequestMessageV2{seqNum: seqNum, force: force}, nil
 }
 
 func (m *ResyncRequestMessageV2) MessageType() string { return MsgTypeResyncRequest }
@@ -627,9 +781,13 @@ func (m *ResyncRequestMessageV2) ToWireFormat() Message {
 	return Message{
 		Type:   MsgTypeResyncRequest,
 		SeqNum: m.seqNum,
+		Force:  m.force,
 	}
 }
 
+// Force reports whether a full_state is requested even if nothing changed
+func (m *ResyncRequestMessageV2) Force() bool { return m.force }
+
 // 16. PersistenceErrorMessageV2 represents a failure to save blocked state
 type PersistenceErrorMessageV2 struct {
 	seqNum   uint64
@@ -694,18 +852,23 @@ func (m *AudioErrorMessageV2) Error() string { return m.errorMsg }
 type ShowBlockPickerMessageV2 struct {
 	seqNum uint64
 	paneID string
+	ttl    time.Duration
 }
 
-// NewShowBlockPickerMessage creates a validated ShowBlockPickerMessage.
-// Returns error if paneID is empty after trimming.
-func NewShowBlockPickerMessage(seqNum uint64, paneID string) (*ShowBlockPickerMessageV2, error) {
+// NewShowBlockPickerMessage creates a validated ShowBlockPickerMessage. ttl is passed on
+// to the block the picker creates (0 = never expires).
+// Returns error if paneID is empty after trimming, or ttl is negative.
+func NewShowBlockPickerMessage(seqNum uint64, paneID string, ttl time.Duration) (*ShowBlockPickerMessageV2, error) {
 	originalPaneID := paneID
 	paneID = strings.TrimSpace(paneID)
 	if paneID == "" {
 		debug.Log("MESSAGE_VALIDATION_FAILED type=show_block_picker reason=empty_pane_id original=%q", originalPaneID)
 		return nil, errors.New("pane_id required")
 	}
-	return &ShowBlockPickerMessageV2{seqNum: seqNum, paneID: paneID}, nil
+	if ttl < 0 {
+		return nil, fmt.Errorf("ttl must be non-negative, got %v", ttl)
+	}
+	return &ShowBlockPickerMessageV2{seqNum: seqNum, paneID: paneID, ttl: ttl}, nil
 }
 
 func (m *ShowBlockPickerMessageV2) MessageType() string { return MsgTypeShowBlockPicker }
@@ -715,16 +878,21 @@ func (m *ShowBlockPickerMessageV2) ToWireFormat() Message {
 		Type:   MsgTypeShowBlockPicker,
 		SeqNum: m.seqNum,
 		PaneID: m.paneID,
+		TTL:    m.ttl,
 	}
 }
 
 // PaneID returns the pane identifier
 func (m *ShowBlockPickerMessageV2) PaneID() string { return m.paneID }
 
+// TTL returns how long the picked block lasts (0 = never expires)
+func (m *ShowBlockPickerMessageV2) TTL() time.Duration { return m.ttl }
+
 // 19. TreeUpdateMessageV2 represents a tmux tree state broadcast
 type TreeUpdateMessageV2 struct {
-	seqNum uint64
-	tree   tmux.RepoTree
+	seqNum  uint64
+	tree    tmux.RepoTree
+	warning string // Set when some panes couldn't be read and are missing from tree
 }
 
 // NewTreeUpdateMessage creates a validated TreeUpdateMessage.
@@ -749,6 +917,22 @@ func NewTreeUpdateMessage(seqNum uint64, tree tmux.RepoTree) (*TreeUpdateMessage
 	return &TreeUpdateMessageV2{seqNum: seqNum, tree: cloned}, nil
 }
 
+// NewPartialTreeUpdateMessage creates a TreeUpdateMessage for a tree that is missing
+// panes the collector couldn't read. warning says which and why.
+// Returns error if warning is empty after trimming or the tree fails to clone.
+func NewPartialTreeUpdateMessage(seqNum uint64, tree tmux.RepoTree, warning string) (*TreeUpdateMessageV2, error) {
+	warning = strings.TrimSpace(warning)
+	if warning == "" {
+		return nil, errors.New("warning required for a partial tree update")
+	}
+	msg, err := NewTreeUpdateMessage(seqNum, tree)
+	if err != nil {
+		return nil, err
+	}
+	msg.warning = warning
+	return msg, nil
+}
+
 func (m *TreeUpdateMessageV2) MessageType() string { return MsgTypeTreeUpdate }
 func (m *TreeUpdateMessageV2) SeqNumber() uint64   { return m.seqNum }
 func (m *TreeUpdateMessageV2) ToWireFormat() Message {
@@ -760,6 +944,7 @@ func (m *TreeUpdateMessageV2) ToWireFormat() Message {
 		Type:   MsgTypeTreeUpdate,
 		SeqNum: m.seqNum,
 		Tree:   &m.tree,
+		Error:  m.warning,
 	}
 }
 
@@ -770,6 +955,9 @@ func (m *TreeUpdateMessageV2) ToWireFormat() Message {
 // This ensures the message remains truly immutable.
 func (m *TreeUpdateMessageV2) Tree() tmux.RepoTree { return m.tree.Clone() }
 
+// Warning returns why panes are missing from the tree, or "" if it is complete.
+func (m *TreeUpdateMessageV2) Warning() string { return m.warning }
+
 // 20. TreeErrorMessageV2 represents a tree collection failure
 type TreeErrorMessageV2 struct {
 	seqNum   uint64
@@ -801,6 +989,480 @@ func (m *TreeErrorMessageV2) ToWireFormat() Message {
 // Error returns the error message (guaranteed non-empty by constructor)
 func (m *TreeErrorMessageV2) Error() string { return m.errorMsg }
 
+// 21. BlockRejectedMessageV2 represents a refused block_branch request
+type BlockRejectedMessageV2 struct {
+	seqNum        uint64
+	branch        string
+	blockedBranch string
+	reason        string
+	cyclePath     []string // Only set when the block would create a cycle
+}
+
+// NewBlockRejectedMessage creates a validated BlockRejectedMessage.
+// Returns error if branch or reason is empty after trimming. blockedBranch is optional.
+func NewBlockRejectedMessage(seqNum uint64, branch, blockedBranch, reason string) (*BlockRejectedMessageV2, error) {
+	originalBranch := branch
+	branch = strings.TrimSpace(branch)
+	reason = strings.TrimSpace(reason)
+	if branch == "" {
+		debug.Log("MESSAGE_VALIDATION_FAILED type=block_rejected reason=empty_branch original=%q", originalBranch)
+		return nil, errors.New("branch required")
+	}
+	if reason == "" {
+		return nil, errors.New("reason required - rejections must tell the user why")
+	}
+	return &BlockRejectedMessageV2{
+		seqNum:        seqNum,
+		branch:        branch,
+		blockedBranch: strings.TrimSpace(blockedBranch),
+		reason:        reason,
+	}, nil
+}
+
+// NewBlockCycleRejectedMessage creates a BlockRejectedMessage refusing a block that
+// would create cycle, a path branch -> blockedBranch -> ... -> branch. The reason
+// names the cycle. Returns error if branch or blockedBranch is empty after trimming,
+// or if cycle is not such a path.
+func NewBlockCycleRejectedMessage(seqNum uint64, branch, blockedBranch string, cycle []string) (*BlockRejectedMessageV2, error) {
+	branch = strings.TrimSpace(branch)
+	blockedBranch = strings.TrimSpace(blockedBranch)
+	if blockedBranch == "" {
+		return nil, errors.New("blocked branch required - a cycle needs the requested blocker")
+	}
+	if len(cycle) < 2 || cycle[0] != branch || cycle[1] != blockedBranch || cycle[len(cycle)-1] != branch {
+		return nil, fmt.Errorf("cycle %v must lead from %q through %q back to %q", cycle, branch, blockedBranch, branch)
+	}
+	msg, err := NewBlockRejectedMessage(seqNum, branch, blockedBranch, blockCycleReason(branch, blockedBranch, cycle))
+	if err != nil {
+		return nil, err
+	}
+	msg.cyclePath = slices.Clone(cycle)
+	return msg, nil
+}
+
+func (m *BlockRejectedMessageV2) MessageType() string { return MsgTypeBlockRejected }
+func (m *BlockRejectedMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *BlockRejectedMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:          MsgTypeBlockRejected,
+		SeqNum:        m.seqNum,
+		Branch:        m.branch,
+		BlockedBranch: m.blockedBranch,
+		CyclePath:     slices.Clone(m.cyclePath),
+		Error:         m.reason,
+	}
+}
+
+// Branch returns the branch that could not be blocked
+func (m *BlockRejectedMessageV2) Branch() string { return m.branch }
+
+// BlockedBranch returns the requested blocking branch (may be empty)
+func (m *BlockRejectedMessageV2) BlockedBranch() string { return m.blockedBranch }
+
+// Reason returns why the request was refused (guaranteed non-empty by constructor)
+func (m *BlockRejectedMessageV2) Reason() string { return m.reason }
+
+// CyclePath returns a copy of the block cycle the request would have created, or nil
+// if it was refused for another reason
+func (m *BlockRejectedMessageV2) CyclePath() []string { return slices.Clone(m.cyclePath) }
+
+// 22. ImportBlocksMessageV2 represents a request to merge or replace blocked branches
+type ImportBlocksMessageV2 struct {
+	seqNum          uint64
+	blockedBranches BlockMap
+	mode            string
+}
+
+// NewImportBlocksMessage creates a validated ImportBlocksMessage.
+// mode must be ImportModeMerge or ImportModeReplace. blockedBranches may be empty
+// (replacing with an empty map clears every block). The block graph itself is
+// validated by the daemon against its current state, not here.
+func NewImportBlocksMessage(seqNum uint64, blockedBranches BlockMap, mode string) (*ImportBlocksMessageV2, error) {
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		debug.Log("MESSAGE_VALIDATION_FAILED type=import_blocks reason=invalid_mode mode=%q", mode)
+		return nil, fmt.Errorf("import mode must be %q or %q, got %q", ImportModeMerge, ImportModeReplace, mode)
+	}
+	return &ImportBlocksMessageV2{
+		seqNum:          seqNum,
+		blockedBranches: blockedBranches.Clone(),
+		mode:            mode,
+	}, nil
+}
+
+func (m *ImportBlocksMessageV2) MessageType() string { return MsgTypeImportBlocks }
+func (m *ImportBlocksMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *ImportBlocksMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:            MsgTypeImportBlocks,
+		SeqNum:          m.seqNum,
+		BlockedBranches: m.blockedBranches,
+		ImportMode:      m.mode,
+	}
+}
+
+// BlockedBranches returns a copy of the blocks to import
+func (m *ImportBlocksMessageV2) BlockedBranches() BlockMap {
+	return m.blockedBranches.Clone()
+}
+
+// Mode returns the import mode (merge or replace)
+func (m *ImportBlocksMessageV2) Mode() string { return m.mode }
+
+// 23. ImportBlocksResultMessageV2 represents the outcome of an import_blocks request
+type ImportBlocksResultMessageV2 struct {
+	seqNum          uint64
+	blockedBranches BlockMap
+	errorMsg        string
+}
+
+// NewImportBlocksResultMessage creates an ImportBlocksResultMessage. On success
+// errorMsg is empty and blockedBranches is the daemon's state after the import; on
+// failure errorMsg explains why nothing was applied.
+func NewImportBlocksResultMessage(seqNum uint64, blockedBranches BlockMap, errorMsg string) (*ImportBlocksResultMessageV2, error) {
+	return &ImportBlocksResultMessageV2{
+		seqNum:          seqNum,
+		blockedBranches: blockedBranches.Clone(),
+		errorMsg:        strings.TrimSpace(errorMsg),
+	}, nil
+}
+
+func (m *ImportBlocksResultMessageV2) MessageType() string { return MsgTypeImportBlocksResult }
+func (m *ImportBlocksResultMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *ImportBlocksResultMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:            MsgTypeImportBlocksResult,
+		SeqNum:          m.seqNum,
+		BlockedBranches: m.blockedBranches,
+		Error:           m.errorMsg,
+	}
+}
+
+// BlockedBranches returns a copy of the blocked state after the import
+func (m *ImportBlocksResultMessageV2) BlockedBranches() BlockMap {
+	return m.blockedBranches.Clone()
+}
+
+// Error returns why the import failed (empty on success)
+func (m *ImportBlocksResultMessageV2) Error() string { return m.errorMsg }
+
+// 24. ListQueryMessageV2 represents a request for the current alerts and blocked branches
+type ListQueryMessageV2 struct {
+	seqNum uint64
+}
+
+// NewListQueryMessage creates a validated ListQueryMessage.
+func NewListQueryMessage(seqNum uint64) (*ListQueryMessageV2, error) {
+	return &ListQueryMessageV2{seqNum: seqNum}, nil
+}
+
+func (m *ListQueryMessageV2) MessageType() string { return MsgTypeListQuery }
+func (m *ListQueryMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *ListQueryMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:   MsgTypeListQuery,
+		SeqNum: m.seqNum,
+	}
+}
+
+// 25. ListResponseMessageV2 represents the daemon's current alerts and blocked branches
+type ListResponseMessageV2 struct {
+	seqNum          uint64
+	alerts          map[string]string
+	blockedBranches BlockMap
+}
+
+// NewListResponseMessage creates a ListResponseMessage. Both maps may be empty.
+func NewListResponseMessage(seqNum uint64, alerts map[string]string, blockedBranches BlockMap) (*ListResponseMessageV2, error) {
+	return &ListResponseMessageV2{
+		seqNum:          seqNum,
+		alerts:          copyStringMap(alerts),
+		blockedBranches: blockedBranches.Clone(),
+	}, nil
+}
+
+func (m *ListResponseMessageV2) MessageType() string { return MsgTypeListResponse }
+func (m *ListResponseMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *ListResponseMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:            MsgTypeListResponse,
+		SeqNum:          m.seqNum,
+		Alerts:          m.alerts,
+		BlockedBranches: m.blockedBranches,
+	}
+}
+
+// Alerts returns a copy of the alerts map (paneID -> eventType)
+func (m *ListResponseMessageV2) Alerts() map[string]string { return copyStringMap(m.alerts) }
+
+// BlockedBranches returns a copy of the blocked branches map (branch -> blocking branches)
+func (m *ListResponseMessageV2) BlockedBranches() BlockMap {
+	return m.blockedBranches.Clone()
+}
+
+// 26. UnblockAllMessageV2 represents a request to clear every blocked branch
+type UnblockAllMessageV2 struct {
+	seqNum uint64
+}
+
+// NewUnblockAllMessage creates a validated UnblockAllMessage.
+func NewUnblockAllMessage(seqNum uint64) (*UnblockAllMessageV2, error) {
+	return &UnblockAllMessageV2{seqNum: seqNum}, nil
+}
+
+func (m *UnblockAllMessageV2) MessageType() string { return MsgTypeUnblockAll }
+func (m *UnblockAllMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *UnblockAllMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:   MsgTypeUnblockAll,
+		SeqNum: m.seqNum,
+	}
+}
+
+// 27. ClientsQueryMessageV2 represents a request for the connected clients
+type ClientsQueryMessageV2 struct {
+	seqNum uint64
+}
+
+// NewClientsQueryMessage creates a validated ClientsQueryMessage.
+func NewClientsQueryMessage(seqNum uint64) (*ClientsQueryMessageV2, error) {
+	return &ClientsQueryMessageV2{seqNum: seqNum}, nil
+}
+
+func (m *ClientsQueryMessageV2) MessageType() string { return MsgTypeClientsQuery }
+func (m *ClientsQueryMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *ClientsQueryMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:   MsgTypeClientsQuery,
+		SeqNum: m.seqNum,
+	}
+}
+
+// 28. ClientsResponseMessageV2 represents the daemon's connected clients
+type ClientsResponseMessageV2 struct {
+	seqNum  uint64
+	clients []ClientInfo
+}
+
+// NewClientsResponseMessage creates a ClientsResponseMessage. clients may be empty.
+func NewClientsResponseMessage(seqNum uint64, clients []ClientInfo) (*ClientsResponseMessageV2, error) {
+	return &ClientsResponseMessageV2{
+		seqNum:  seqNum,
+		clients: slices.Clone(clients),
+	}, nil
+}
+
+func (m *ClientsResponseMessageV2) MessageType() string { return MsgTypeClientsResponse }
+func (m *ClientsResponseMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *ClientsResponseMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:    MsgTypeClientsResponse,
+		SeqNum:  m.seqNum,
+		Clients: slices.Clone(m.clients),
+	}
+}
+
+// Clients returns a copy of the connected clients
+func (m *ClientsResponseMessageV2) Clients() []ClientInfo { return slices.Clone(m.clients) }
+
+// 29. QueryAllBlockedMessageV2 represents a request for every blocked branch
+type QueryAllBlockedMessageV2 struct {
+	seqNum uint64
+}
+
+// NewQueryAllBlockedMessage creates a validated QueryAllBlockedMessage.
+func NewQueryAllBlockedMessage(seqNum uint64) (*QueryAllBlockedMessageV2, error) {
+	return &QueryAllBlockedMessageV2{seqNum: seqNum}, nil
+}
+
+func (m *QueryAllBlockedMessageV2) MessageType() string { return MsgTypeQueryAllBlocked }
+func (m *QueryAllBlockedMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *QueryAllBlockedMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:   MsgTypeQueryAllBlocked,
+		SeqNum: m.seqNum,
+	}
+}
+
+// 30. AllBlockedResponseMessageV2 represents the response to a query_all_blocked request
+type AllBlockedResponseMessageV2 struct {
+	seqNum          uint64
+	blockedBranches BlockMap
+}
+
+// NewAllBlockedResponseMessage creates an AllBlockedResponseMessage. blockedBranches may be empty.
+func NewAllBlockedResponseMessage(seqNum uint64, blockedBranches BlockMap) (*AllBlockedResponseMessageV2, error) {
+	return &AllBlockedResponseMessageV2{
+		seqNum:          seqNum,
+		blockedBranches: blockedBranches.Clone(),
+	}, nil
+}
+
+func (m *AllBlockedResponseMessageV2) MessageType() string { return MsgTypeAllBlockedResponse }
+func (m *AllBlockedResponseMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *AllBlockedResponseMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:            MsgTypeAllBlockedResponse,
+		SeqNum:          m.seqNum,
+		BlockedBranches: m.blockedBranches,
+	}
+}
+
+// BlockedBranches returns a copy of the blocked branches map (branch -> blocking branches)
+func (m *AllBlockedResponseMessageV2) BlockedBranches() BlockMap {
+	return m.blockedBranches.Clone()
+}
+
+// 31. RejectedMessageV2 represents a connection the daemon refused
+type RejectedMessageV2 struct {
+	seqNum uint64
+	reason string
+}
+
+// NewRejectedMessage creates a validated RejectedMessage.
+// Returns error if reason is empty after trimming.
+func NewRejectedMessage(seqNum uint64, reason string) (*RejectedMessageV2, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("reason required - rejections must tell the user why")
+	}
+	return &RejectedMessageV2{seqNum: seqNum, reason: reason}, nil
+}
+
+func (m *RejectedMessageV2) MessageType() string { return MsgTypeRejected }
+func (m *RejectedMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *RejectedMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:   MsgTypeRejected,
+		SeqNum: m.seqNum,
+		Error:  m.reason,
+	}
+}
+
+// Reason returns why the connection was refused (guaranteed non-empty by constructor)
+func (m *RejectedMessageV2) Reason() string { return m.reason }
+
+// 32. RenameBranchMessageV2 represents a request to move a branch's blocks to a new name
+type RenameBranchMessageV2 struct {
+	seqNum    uint64
+	oldBranch string
+	newBranch string
+}
+
+// NewRenameBranchMessage creates a validated RenameBranchMessage.
+// Returns error if either name is empty after trimming or the names are equal.
+// Whether the rename collides with existing blocks is checked by the daemon.
+func NewRenameBranchMessage(seqNum uint64, oldBranch, newBranch string) (*RenameBranchMessageV2, error) {
+	oldBranch = strings.TrimSpace(oldBranch)
+	newBranch = strings.TrimSpace(newBranch)
+	if oldBranch == "" || newBranch == "" {
+		debug.Log("MESSAGE_VALIDATION_FAILED type=rename_branch reason=empty_branch old=%q new=%q", oldBranch, newBranch)
+		return nil, errors.New("old and new branch names required")
+	}
+	if oldBranch == newBranch {
+		return nil, fmt.Errorf("'%s' is already named '%s'", oldBranch, newBranch)
+	}
+	return &RenameBranchMessageV2{seqNum: seqNum, oldBranch: oldBranch, newBranch: newBranch}, nil
+}
+
+func (m *RenameBranchMessageV2) MessageType() string { return MsgTypeRenameBranch }
+func (m *RenameBranchMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *RenameBranchMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:      MsgTypeRenameBranch,
+		SeqNum:    m.seqNum,
+		Branch:    m.oldBranch,
+		NewBranch: m.newBranch,
+	}
+}
+
+// OldBranch returns the branch's previous name (guaranteed non-empty by constructor)
+func (m *RenameBranchMessageV2) OldBranch() string { return m.oldBranch }
+
+// NewBranch returns the branch's new name (guaranteed non-empty by constructor)
+func (m *RenameBranchMessageV2) NewBranch() string { return m.newBranch }
+
+// 33. RenameBranchResultMessageV2 represents the outcome of a rename_branch request
+type RenameBranchResultMessageV2 struct {
+	seqNum    uint64
+	oldBranch string
+	newBranch string
+	errorMsg  string
+}
+
+// NewRenameBranchResultMessage creates a RenameBranchResultMessage. errorMsg is empty
+// on success; on failure it explains why the blocks were left unchanged.
+func NewRenameBranchResultMessage(seqNum uint64, oldBranch, newBranch, errorMsg string) (*RenameBranchResultMessageV2, error) {
+	if oldBranch == "" || newBranch == "" {
+		return nil, errors.New("old and new branch names required")
+	}
+	return &RenameBranchResultMessageV2{
+		seqNum:    seqNum,
+		oldBranch: oldBranch,
+		newBranch: newBranch,
+		errorMsg:  strings.TrimSpace(errorMsg),
+	}, nil
+}
+
+func (m *RenameBranchResultMessageV2) MessageType() string { return MsgTypeRenameBranchResult }
+func (m *RenameBranchResultMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *RenameBranchResultMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:      MsgTypeRenameBranchResult,
+		SeqNum:    m.seqNum,
+		Branch:    m.oldBranch,
+		NewBranch: m.newBranch,
+		Error:     m.errorMsg,
+	}
+}
+
+// OldBranch returns the branch's previous name
+func (m *RenameBranchResultMessageV2) OldBranch() string { return m.oldBranch }
+
+// NewBranch returns the branch's new name
+func (m *RenameBranchResultMessageV2) NewBranch() string { return m.newBranch }
+
+// Error returns why the rename failed (empty on success)
+func (m *RenameBranchResultMessageV2) Error() string { return m.errorMsg }
+
+// 34. VersionMismatchMessageV2 represents a connection refused for speaking an
+// incompatible protocol version
+type VersionMismatchMessageV2 struct {
+	seqNum          uint64
+	protocolVersion string
+	reason          string
+}
+
+// NewVersionMismatchMessage creates a validated VersionMismatchMessage. protocolVersion
+// is the daemon's. Returns error if either argument is empty after trimming.
+func NewVersionMismatchMessage(seqNum uint64, protocolVersion, reason string) (*VersionMismatchMessageV2, error) {
+	protocolVersion = strings.TrimSpace(protocolVersion)
+	reason = strings.TrimSpace(reason)
+	if protocolVersion == "" {
+		return nil, errors.New("protocol version required")
+	}
+	if reason == "" {
+		return nil, errors.New("reason required - rejections must tell the user why")
+	}
+	return &VersionMismatchMessageV2{seqNum: seqNum, protocolVersion: protocolVersion, reason: reason}, nil
+}
+
+func (m *VersionMismatchMessageV2) MessageType() string { return MsgTypeVersionMismatch }
+func (m *VersionMismatchMessageV2) SeqNumber() uint64   { return m.seqNum }
+func (m *VersionMismatchMessageV2) ToWireFormat() Message {
+	return Message{
+		Type:            MsgTypeVersionMismatch,
+		SeqNum:          m.seqNum,
+		ProtocolVersion: m.protocolVersion,
+		Error:           m.reason,
+	}
+}
+
+// ProtocolVersion returns the daemon's protocol version (guaranteed non-empty by constructor)
+func (m *VersionMismatchMessageV2) ProtocolVersion() string { return m.protocolVersion }
+
+// Reason returns why the connection was refused (guaranteed non-empty by constructor)
+func (m *VersionMismatchMessageV2) Reason() string { return m.reason }
+
 // FromWireFormat converts a v1 Message to a type-safe v2 message.
 // Returns error if the message is invalid or has missing required fields.
 // TODO(#521): Add validation for extraneous fields to catch message construction bugs.
@@ -823,17 +1485,18 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, clientID=%q): %w",
 				MsgTypeHello, msg.SeqNum, msg.ClientID, err)
 		}
+		v2msg.protocolVersion = msg.ProtocolVersion // The sender's, not ours
 		return v2msg, nil
 
 	case MsgTypeFullState:
-		v2msg, err := NewFullStateMessage(msg.SeqNum, msg.Alerts, msg.BlockedBranches)
+		v2msg, err := NewFullStateMessage(msg.SeqNum, msg.Alerts, msg.AlertTimes, msg.BlockedBranches, msg.BlockExpiries, msg.ActivePaneID, msg.Namespace)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeFullState, msg.SeqNum, err)
 		}
 		return v2msg, nil
 
 	case MsgTypeAlertChange:
-		v2msg, err := NewAlertChangeMessage(msg.SeqNum, msg.PaneID, msg.EventType, msg.Created)
+		v2msg, err := NewAlertChangeMessage(msg.SeqNum, msg.PaneID, msg.EventType, msg.Created, msg.AlertedAt, msg.Severity)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, paneID=%q, eventType=%q): %w",
 				MsgTypeAlertChange, msg.SeqNum, msg.PaneID, msg.EventType, err)
@@ -849,7 +1512,7 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		return v2msg, nil
 
 	case MsgTypeBlockBranch:
-		v2msg, err := NewBlockBranchMessage(msg.SeqNum, msg.Branch, msg.BlockedBranch)
+		v2msg, err := NewBlockBranchMessage(msg.SeqNum, msg.Branch, msg.BlockedBranch, msg.TTL)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, branch=%q, blockedBranch=%q): %w",
 				MsgTypeBlockBranch, msg.SeqNum, msg.Branch, msg.BlockedBranch, err)
@@ -857,7 +1520,7 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		return v2msg, nil
 
 	case MsgTypeUnblockBranch:
-		v2msg, err := NewUnblockBranchMessage(msg.SeqNum, msg.Branch)
+		v2msg, err := NewUnblockBranchMessage(msg.SeqNum, msg.Branch, msg.BlockedBranch)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, branch=%q): %w",
 				MsgTypeUnblockBranch, msg.SeqNum, msg.Branch, err)
@@ -865,7 +1528,11 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		return v2msg, nil
 
 	case MsgTypeBlockChange:
-		v2msg, err := NewBlockChangeMessage(msg.SeqNum, msg.Branch, msg.BlockedBranch, msg.Blocked)
+		var v2msg *BlockChangeMessageV2
+		blockers, err := wireBlockers(msg.Blocked, msg.BlockedBranch, msg.Blockers)
+		if err == nil {
+			v2msg, err = NewBlockChangeMessage(msg.SeqNum, msg.Branch, blockers, msg.BlockExpiries[msg.Branch])
+		}
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, branch=%q, blocked=%t): %w",
 				MsgTypeBlockChange, msg.SeqNum, msg.Branch, msg.Blocked, err)
@@ -881,7 +1548,11 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		return v2msg, nil
 
 	case MsgTypeBlockedStateResponse:
-		v2msg, err := NewBlockedStateResponseMessage(msg.SeqNum, msg.Branch, msg.IsBlocked, msg.BlockedBranch)
+		var v2msg *BlockedStateResponseMessageV2
+		blockers, err := wireBlockers(msg.IsBlocked, msg.BlockedBranch, msg.Blockers)
+		if err == nil {
+			v2msg, err = NewBlockedStateResponseMessage(msg.SeqNum, msg.Branch, blockers)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, branch=%q, isBlocked=%t): %w",
 				MsgTypeBlockedStateResponse, msg.SeqNum, msg.Branch, msg.IsBlocked, err)
@@ -929,7 +1600,7 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		return v2msg, nil
 
 	case MsgTypeResyncRequest:
-		v2msg, err := NewResyncRequestMessage(msg.SeqNum)
+		v2msg, err := NewResyncRequestMessage(msg.SeqNum, msg.Force)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeResyncRequest, msg.SeqNum, err)
 		}
@@ -952,7 +1623,7 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		return v2msg, nil
 
 	case MsgTypeShowBlockPicker:
-		v2msg, err := NewShowBlockPickerMessage(msg.SeqNum, msg.PaneID)
+		v2msg, err := NewShowBlockPickerMessage(msg.SeqNum, msg.PaneID, msg.TTL)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d, paneID=%q): %w",
 				MsgTypeShowBlockPicker, msg.SeqNum, msg.PaneID, err)
@@ -970,6 +1641,9 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 				MsgTypeTreeUpdate, msg.SeqNum)
 		}
 		v2msg, err := NewTreeUpdateMessage(msg.SeqNum, *msg.Tree)
+		if err == nil && msg.Error != "" {
+			v2msg, err = NewPartialTreeUpdateMessage(msg.SeqNum, *msg.Tree, msg.Error)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w",
 				MsgTypeTreeUpdate, msg.SeqNum, err)
@@ -984,6 +1658,109 @@ func FromWireFormat(msg Message) (MessageV2, error) {
 		}
 		return v2msg, nil
 
+	case MsgTypeBlockRejected:
+		v2msg, err := NewBlockRejectedMessage(msg.SeqNum, msg.Branch, msg.BlockedBranch, msg.Error)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d, branch=%q): %w",
+				MsgTypeBlockRejected, msg.SeqNum, msg.Branch, err)
+		}
+		// Keep the daemon's reason as sent rather than rebuilding it from the path
+		v2msg.cyclePath = slices.Clone(msg.CyclePath)
+		return v2msg, nil
+
+	case MsgTypeImportBlocks:
+		v2msg, err := NewImportBlocksMessage(msg.SeqNum, msg.BlockedBranches, msg.ImportMode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d, mode=%q): %w",
+				MsgTypeImportBlocks, msg.SeqNum, msg.ImportMode, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeImportBlocksResult:
+		v2msg, err := NewImportBlocksResultMessage(msg.SeqNum, msg.BlockedBranches, msg.Error)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeImportBlocksResult, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeListQuery:
+		v2msg, err := NewListQueryMessage(msg.SeqNum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeListQuery, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeListResponse:
+		v2msg, err := NewListResponseMessage(msg.SeqNum, msg.Alerts, msg.BlockedBranches)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeListResponse, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeUnblockAll:
+		v2msg, err := NewUnblockAllMessage(msg.SeqNum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeUnblockAll, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeClientsQuery:
+		v2msg, err := NewClientsQueryMessage(msg.SeqNum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeClientsQuery, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeClientsResponse:
+		v2msg, err := NewClientsResponseMessage(msg.SeqNum, msg.Clients)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeClientsResponse, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeQueryAllBlocked:
+		v2msg, err := NewQueryAllBlockedMessage(msg.SeqNum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeQueryAllBlocked, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeAllBlockedResponse:
+		v2msg, err := NewAllBlockedResponseMessage(msg.SeqNum, msg.BlockedBranches)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeAllBlockedResponse, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeRejected:
+		v2msg, err := NewRejectedMessage(msg.SeqNum, msg.Error)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeRejected, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeRenameBranch:
+		v2msg, err := NewRenameBranchMessage(msg.SeqNum, msg.Branch, msg.NewBranch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d, branch=%q, new_branch=%q): %w",
+				MsgTypeRenameBranch, msg.SeqNum, msg.Branch, msg.NewBranch, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeRenameBranchResult:
+		v2msg, err := NewRenameBranchResultMessage(msg.SeqNum, msg.Branch, msg.NewBranch, msg.Error)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeRenameBranchResult, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
+	case MsgTypeVersionMismatch:
+		v2msg, err := NewVersionMismatchMessage(msg.SeqNum, msg.ProtocolVersion, msg.Error)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message (seqNum=%d): %w", MsgTypeVersionMismatch, msg.SeqNum, err)
+		}
+		return v2msg, nil
+
 	default:
 		debug.Log("MESSAGE_TYPE_UNKNOWN type=%q seq=%d reason=not_in_switch_statement",
 			msg.Type, msg.SeqNum)
@@ -2,7 +2,9 @@ package daemon
 
 import (
 	"encoding/json"
+	"slices"
 	"testing"
+	"time"
 )
 
 // TestJSONSerializationPreservesAllFields verifies that all message types
@@ -24,14 +26,18 @@ func TestJSONSerializationPreservesAllFields(t *testing.T) {
 				return NewFullStateMessage(
 					42,
 					map[string]string{"pane1": "alert1"},
-					map[string]string{"branch1": "branch2"},
-				)
+					nil,
+					BlockMap{"branch1": {"branch2"}}, nil,
+
+					"%1",
+					"")
+
 			},
 		},
 		{
 			name: "AlertChangeMessage",
 			creator: func() (MessageV2, error) {
-				return NewAlertChangeMessage(42, "pane1", "eventType1", true)
+				return NewAlertChangeMessage(42, "pane1", "eventType1", true, time.Time{}, "")
 			},
 		},
 		{
@@ -55,31 +61,31 @@ func TestJSONSerializationPreservesAllFields(t *testing.T) {
 		{
 			name: "ShowBlockPickerMessage",
 			creator: func() (MessageV2, error) {
-				return NewShowBlockPickerMessage(42, "pane1")
+				return NewShowBlockPickerMessage(42, "pane1", 0)
 			},
 		},
 		{
 			name: "BlockBranchMessage",
 			creator: func() (MessageV2, error) {
-				return NewBlockBranchMessage(42, "branch1", "branch2")
+				return NewBlockBranchMessage(42, "branch1", "branch2", 0)
 			},
 		},
 		{
 			name: "UnblockBranchMessage",
 			creator: func() (MessageV2, error) {
-				return NewUnblockBranchMessage(42, "branch1")
+				return NewUnblockBranchMessage(42, "branch1", "")
 			},
 		},
 		{
 			name: "BlockChangeMessage_blocked",
 			creator: func() (MessageV2, error) {
-				return NewBlockChangeMessage(42, "branch1", "branch2", true)
+				return NewBlockChangeMessage(42, "branch1", []string{"branch2"}, nil)
 			},
 		},
 		{
 			name: "BlockChangeMessage_unblocked",
 			creator: func() (MessageV2, error) {
-				return NewBlockChangeMessage(42, "branch1", "", false)
+				return NewBlockChangeMessage(42, "branch1", nil, nil)
 			},
 		},
 		{
@@ -91,7 +97,7 @@ func TestJSONSerializationPreservesAllFields(t *testing.T) {
 		{
 			name: "BlockedStateResponseMessage",
 			creator: func() (MessageV2, error) {
-				return NewBlockedStateResponseMessage(42, "branch1", true, "branch2")
+				return NewBlockedStateResponseMessage(42, "branch1", []string{"branch2"})
 			},
 		},
 		{
@@ -119,7 +125,7 @@ func TestJSONSerializationPreservesAllFields(t *testing.T) {
 		{
 			name: "ResyncRequestMessage",
 			creator: func() (MessageV2, error) {
-				return NewResyncRequestMessage(42)
+				return NewResyncRequestMessage(42, false)
 			},
 		},
 		{
@@ -196,8 +202,12 @@ func TestV1ClientCanDeserializeV2Messages(t *testing.T) {
 				return NewFullStateMessage(
 					42,
 					map[string]string{"pane1": "alert1"},
-					map[string]string{"branch1": "branch2"},
-				)
+					nil,
+					BlockMap{"branch1": {"branch2"}}, nil,
+
+					"%3",
+					"")
+
 			},
 			verifyFields: func(t *testing.T, msg Message) {
 				if msg.Type != MsgTypeFullState {
@@ -209,15 +219,18 @@ func TestV1ClientCanDeserializeV2Messages(t *testing.T) {
 				if len(msg.Alerts) != 1 || msg.Alerts["pane1"] != "alert1" {
 					t.Errorf("Alerts = %v, want map[pane1:alert1]", msg.Alerts)
 				}
-				if len(msg.BlockedBranches) != 1 || msg.BlockedBranches["branch1"] != "branch2" {
-					t.Errorf("BlockedBranches = %v, want map[branch1:branch2]", msg.BlockedBranches)
+				if len(msg.BlockedBranches) != 1 || !slices.Equal(msg.BlockedBranches["branch1"], []string{"branch2"}) {
+					t.Errorf("BlockedBranches = %v, want map[branch1:[branch2]]", msg.BlockedBranches)
+				}
+				if msg.ActivePaneID != "%3" {
+					t.Errorf("ActivePaneID = %q, want %%3", msg.ActivePaneID)
 				}
 			},
 		},
 		{
 			name: "AlertChangeMessage",
 			creator: func() (MessageV2, error) {
-				return NewAlertChangeMessage(42, "pane1", "event1", true)
+				return NewAlertChangeMessage(42, "pane1", "event1", true, time.Time{}, "")
 			},
 			verifyFields: func(t *testing.T, msg Message) {
 				if msg.Type != MsgTypeAlertChange {
@@ -237,7 +250,7 @@ func TestV1ClientCanDeserializeV2Messages(t *testing.T) {
 		{
 			name: "BlockChangeMessage",
 			creator: func() (MessageV2, error) {
-				return NewBlockChangeMessage(42, "branch1", "branch2", true)
+				return NewBlockChangeMessage(42, "branch1", []string{"branch2"}, nil)
 			},
 			verifyFields: func(t *testing.T, msg Message) {
 				if msg.Type != MsgTypeBlockChange {
@@ -339,7 +352,7 @@ func TestV2ClientCanDeserializeV1Messages(t *testing.T) {
 					Type:            MsgTypeFullState,
 					SeqNum:          42,
 					Alerts:          map[string]string{"pane1": "alert1"},
-					BlockedBranches: map[string]string{"branch1": "branch2"},
+					BlockedBranches: BlockMap{"branch1": {"branch2"}},
 				}
 			},
 			verifyV2Msg: func(t *testing.T, msg MessageV2) {
@@ -352,8 +365,8 @@ func TestV2ClientCanDeserializeV1Messages(t *testing.T) {
 					t.Errorf("Alerts = %v, want map[pane1:alert1]", alerts)
 				}
 				blocked := fs.BlockedBranches()
-				if len(blocked) != 1 || blocked["branch1"] != "branch2" {
-					t.Errorf("BlockedBranches = %v, want map[branch1:branch2]", blocked)
+				if len(blocked) != 1 || !slices.Equal(blocked["branch1"], []string{"branch2"}) {
+					t.Errorf("BlockedBranches = %v, want map[branch1:[branch2]]", blocked)
 				}
 			},
 			expectedType: "full_state",
@@ -3,6 +3,7 @@ package daemon
 import (
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -242,7 +243,7 @@ func TestFromWireFormat_MalformedMessages(t *testing.T) {
 				Type:            MsgTypeFullState,
 				SeqNum:          42,
 				Alerts:          map[string]string{},
-				BlockedBranches: map[string]string{},
+				BlockedBranches: BlockMap{},
 			},
 			expectError: false,
 		},
@@ -324,7 +325,7 @@ func TestConstructor_ExtremeValues(t *testing.T) {
 			"branch-with-{braces}",
 		}
 		for _, branch := range tests {
-			msg, err := NewUnblockBranchMessage(42, branch)
+			msg, err := NewUnblockBranchMessage(42, branch, "")
 			if err != nil {
 				t.Errorf("Branch %q rejected: %v", branch, err)
 			}
@@ -336,10 +337,12 @@ func TestConstructor_ExtremeValues(t *testing.T) {
 
 	t.Run("very_large_map", func(t *testing.T) {
 		largeMap := make(map[string]string, 10000)
+		largeBlocked := make(BlockMap, 10000)
 		for i := 0; i < 10000; i++ {
 			largeMap[strings.Repeat("k", i%100)] = strings.Repeat("v", i%100)
+			largeBlocked[strings.Repeat("k", i%100)] = []string{strings.Repeat("v", i%100)}
 		}
-		msg, err := NewFullStateMessage(42, largeMap, largeMap)
+		msg, err := NewFullStateMessage(42, largeMap, nil, largeBlocked, nil, "", "")
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
@@ -367,22 +370,23 @@ func TestConstructor_ExtremeValues(t *testing.T) {
 func TestMapImmutability(t *testing.T) {
 	t.Run("mutate_original_map", func(t *testing.T) {
 		originalAlerts := map[string]string{"pane1": "alert1"}
-		originalBlocked := map[string]string{"branch1": "branch2"}
+		originalBlocked := BlockMap{"branch1": {"branch2"}}
 
-		msg, err := NewFullStateMessage(42, originalAlerts, originalBlocked)
+		msg, err := NewFullStateMessage(42, originalAlerts, nil, originalBlocked, nil, "", "")
 		if err != nil {
 			t.Fatalf("Failed to create message: %v", err)
 		}
 
 		originalAlerts["pane2"] = "alert2"
-		originalBlocked["branch3"] = "branch4"
+		originalBlocked["branch3"] = []string{"branch4"}
+		originalBlocked["branch1"][0] = "mutated"
 
 		alerts := msg.Alerts()
 		if len(alerts) != 1 || alerts["pane1"] != "alert1" {
 			t.Errorf("Message alerts mutated: %v", alerts)
 		}
 		blocked := msg.BlockedBranches()
-		if len(blocked) != 1 || blocked["branch1"] != "branch2" {
+		if len(blocked) != 1 || !slices.Equal(blocked["branch1"], []string{"branch2"}) {
 			t.Errorf("Message blocked branches mutated: %v", blocked)
 		}
 	})
@@ -391,8 +395,12 @@ func TestMapImmutability(t *testing.T) {
 		msg, err := NewFullStateMessage(
 			42,
 			map[string]string{"pane1": "alert1"},
-			map[string]string{"branch1": "branch2"},
-		)
+			nil,
+			BlockMap{"branch1": {"branch2"}}, nil,
+
+			"",
+			"")
+
 		if err != nil {
 			t.Fatalf("Failed to create message: %v", err)
 		}
@@ -401,7 +409,7 @@ func TestMapImmutability(t *testing.T) {
 		blocked := msg.BlockedBranches()
 
 		alerts["pane2"] = "alert2"
-		blocked["branch3"] = "branch4"
+		blocked["branch3"] = []string{"branch4"}
 
 		alerts2 := msg.Alerts()
 		blocked2 := msg.BlockedBranches()
@@ -409,7 +417,7 @@ func TestMapImmutability(t *testing.T) {
 		if len(alerts2) != 1 || alerts2["pane1"] != "alert1" {
 			t.Errorf("Message alerts mutated via returned map: %v", alerts2)
 		}
-		if len(blocked2) != 1 || blocked2["branch1"] != "branch2" {
+		if len(blocked2) != 1 || !slices.Equal(blocked2["branch1"], []string{"branch2"}) {
 			t.Errorf("Message blocked branches mutated via returned map: %v", blocked2)
 		}
 	})
@@ -444,8 +452,12 @@ func TestConcurrentMessageCreation(t *testing.T) {
 				_, err = NewFullStateMessage(
 					uint64(id*iterations+i),
 					map[string]string{"p": "a"},
-					map[string]string{"b": "c"},
-				)
+					nil,
+					BlockMap{"b": {"c"}}, nil,
+
+					"",
+					"")
+
 				if err != nil {
 					errors <- err
 					continue
@@ -454,9 +466,8 @@ func TestConcurrentMessageCreation(t *testing.T) {
 				_, err = NewBlockChangeMessage(
 					uint64(id*iterations+i),
 					"branch1",
-					"branch2",
-					true,
-				)
+					[]string{"branch2"}, nil)
+
 				if err != nil {
 					errors <- err
 				}
@@ -496,7 +507,7 @@ func TestConcurrentMessageCreation(t *testing.T) {
 // conversion without data loss.
 func TestRoundTripFidelity(t *testing.T) {
 	t.Run("block_change_message", func(t *testing.T) {
-		original, err := NewBlockChangeMessage(42, "branch1", "branch2", true)
+		original, err := NewBlockChangeMessage(42, "branch1", []string{"branch2", "branch3"}, nil)
 		if err != nil {
 			t.Fatalf("Failed to create message: %v", err)
 		}
@@ -521,6 +532,9 @@ func TestRoundTripFidelity(t *testing.T) {
 		if bc.BlockedBranch() != "branch2" {
 			t.Errorf("BlockedBranch = %v, want branch2", bc.BlockedBranch())
 		}
+		if !slices.Equal(bc.Blockers(), []string{"branch2", "branch3"}) {
+			t.Errorf("Blockers = %v, want [branch2 branch3]", bc.Blockers())
+		}
 		if !bc.Blocked() {
 			t.Errorf("Blocked = %v, want true", bc.Blocked())
 		}
@@ -532,12 +546,12 @@ func TestRoundTripFidelity(t *testing.T) {
 			"pane2": "alert2",
 			"pane3": "alert3",
 		}
-		originalBlocked := map[string]string{
-			"branch1": "branch2",
-			"branch3": "branch4",
+		originalBlocked := BlockMap{
+			"branch1": {"branch2"},
+			"branch3": {"branch4", "branch2"},
 		}
 
-		original, err := NewFullStateMessage(42, originalAlerts, originalBlocked)
+		original, err := NewFullStateMessage(42, originalAlerts, nil, originalBlocked, nil, "", "")
 		if err != nil {
 			t.Fatalf("Failed to create message: %v", err)
 		}
@@ -572,7 +586,7 @@ func TestRoundTripFidelity(t *testing.T) {
 			t.Errorf("BlockedBranches length = %d, want %d", len(blocked), len(originalBlocked))
 		}
 		for k, v := range originalBlocked {
-			if blocked[k] != v {
+			if !slices.Equal(blocked[k], v) {
 				t.Errorf("BlockedBranches[%s] = %v, want %v", k, blocked[k], v)
 			}
 		}
@@ -646,7 +660,7 @@ func TestFromWireFormat_UnknownMessageType(t *testing.T) {
 func TestNilPointerHandling(t *testing.T) {
 	t.Run("full_state_nil_maps", func(t *testing.T) {
 		// Constructors should handle nil maps gracefully
-		msg, err := NewFullStateMessage(42, nil, nil)
+		msg, err := NewFullStateMessage(42, nil, nil, nil, nil, "", "")
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
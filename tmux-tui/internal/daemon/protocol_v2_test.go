@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	. "github.com/commons-systems/tmux-tui/internal/tmux"
 )
@@ -89,9 +91,9 @@ func TestHelloMessage(t *testing.T) {
 // TestFullStateMessage tests FullStateMessageV2 construction
 func TestFullStateMessage(t *testing.T) {
 	alerts := map[string]string{"pane-1": "idle", "pane-2": "stop"}
-	blocked := map[string]string{"feature": "main", "bugfix": "develop"}
+	blocked := BlockMap{"feature": {"main"}, "bugfix": {"develop", "release"}}
 
-	msg, err := NewFullStateMessage(1, alerts, blocked)
+	msg, err := NewFullStateMessage(1, alerts, nil, blocked, nil, "%1", "")
 	if err != nil {
 		t.Fatalf("NewFullStateMessage() error = %v", err)
 	}
@@ -112,6 +114,9 @@ func TestFullStateMessage(t *testing.T) {
 	if msg2.MessageType() != MsgTypeFullState {
 		t.Errorf("FromWireFormat().MessageType() = %v, want %v", msg2.MessageType(), MsgTypeFullState)
 	}
+	if got := msg2.(*FullStateMessageV2).ActivePaneID(); got != "%1" {
+		t.Errorf("FromWireFormat().ActivePaneID() = %q, want %%1", got)
+	}
 }
 
 // TestAlertChangeMessage tests AlertChangeMessageV2 validation
@@ -149,7 +154,7 @@ func TestAlertChangeMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			msg, err := NewAlertChangeMessage(1, tt.paneID, tt.eventType, tt.created)
+			msg, err := NewAlertChangeMessage(1, tt.paneID, tt.eventType, tt.created, time.Time{}, "")
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("NewAlertChangeMessage() expected error, got nil")
@@ -176,6 +181,37 @@ func TestAlertChangeMessage(t *testing.T) {
 	}
 }
 
+// TestAlertChangeMessage_Severity tests the optional severity on AlertChangeMessageV2
+func TestAlertChangeMessage_Severity(t *testing.T) {
+	if _, err := NewAlertChangeMessage(1, "%1", "permission", true, time.Time{}, "urgent"); err == nil {
+		t.Error("NewAlertChangeMessage() should reject an unknown severity")
+	}
+	if err := ValidateMessage(Message{Type: MsgTypeAlertChange, PaneID: "%1", EventType: "idle", Severity: "urgent"}); err == nil {
+		t.Error("ValidateMessage() should reject an unknown severity")
+	}
+
+	msg, err := NewAlertChangeMessage(1, "%1", "permission", true, time.Time{}, "HIGH")
+	if err != nil {
+		t.Fatalf("NewAlertChangeMessage() error = %v", err)
+	}
+	msg2, err := FromWireFormat(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("AlertChange round-trip failed: %v", err)
+	}
+	if got := msg2.(*AlertChangeMessageV2).Severity(); got != "high" {
+		t.Errorf("Severity() = %q, want %q", got, "high")
+	}
+
+	// Older daemons send no severity
+	msg2, err = FromWireFormat(Message{Type: MsgTypeAlertChange, SeqNum: 2, PaneID: "%1", EventType: "idle", Created: true})
+	if err != nil {
+		t.Fatalf("FromWireFormat() error = %v", err)
+	}
+	if got := msg2.(*AlertChangeMessageV2).Severity(); got != "" {
+		t.Errorf("Severity() = %q, want empty", got)
+	}
+}
+
 // TestPaneFocusMessage tests PaneFocusMessageV2 validation
 func TestPaneFocusMessage(t *testing.T) {
 	tests := []struct {
@@ -257,7 +293,7 @@ func TestBlockBranchMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			msg, err := NewBlockBranchMessage(1, tt.branch, tt.blockedBranch)
+			msg, err := NewBlockBranchMessage(1, tt.branch, tt.blockedBranch, 0)
 			if tt.wantErr {
 				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
 					t.Errorf("expected error containing %q, got %v", tt.errSubstr, err)
@@ -305,7 +341,7 @@ func TestUnblockBranchMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			msg, err := NewUnblockBranchMessage(1, tt.branch)
+			msg, err := NewUnblockBranchMessage(1, tt.branch, "")
 			if tt.wantErr {
 				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
 					t.Errorf("expected error containing %q, got %v", tt.errSubstr, err)
@@ -333,48 +369,49 @@ func TestUnblockBranchMessage(t *testing.T) {
 // TestBlockChangeMessage tests BlockChangeMessageV2 validation
 func TestBlockChangeMessage(t *testing.T) {
 	tests := []struct {
-		name          string
-		branch        string
-		blockedBranch string
-		blocked       bool
-		wantErr       bool
-		errSubstr     string
+		name        string
+		branch      string
+		blockers    []string
+		wantBlocked bool
+		wantErr     bool
+		errSubstr   string
 	}{
 		{
-			name:          "valid block message",
-			branch:        "feature",
-			blockedBranch: "main",
-			blocked:       true,
-			wantErr:       false,
+			name:        "valid block message",
+			branch:      "feature",
+			blockers:    []string{"main"},
+			wantBlocked: true,
 		},
 		{
-			name:          "valid unblock message",
-			branch:        "feature",
-			blockedBranch: "",
-			blocked:       false,
-			wantErr:       false,
+			name:        "multiple blockers",
+			branch:      "feature",
+			blockers:    []string{"main", "develop"},
+			wantBlocked: true,
 		},
 		{
-			name:          "empty branch",
-			branch:        "",
-			blockedBranch: "main",
-			blocked:       true,
-			wantErr:       true,
-			errSubstr:     "branch required",
+			name:        "valid unblock message",
+			branch:      "feature",
+			blockers:    nil,
+			wantBlocked: false,
 		},
 		{
-			name:          "blocked=true but empty blocked_branch",
-			branch:        "feature",
-			blockedBranch: "",
-			blocked:       true,
-			wantErr:       true,
-			errSubstr:     "blocked_branch required",
+			name:        "blank blockers are dropped",
+			branch:      "feature",
+			blockers:    []string{" "},
+			wantBlocked: false,
+		},
+		{
+			name:      "empty branch",
+			branch:    "",
+			blockers:  []string{"main"},
+			wantErr:   true,
+			errSubstr: "branch required",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			msg, err := NewBlockChangeMessage(1, tt.branch, tt.blockedBranch, tt.blocked)
+			msg, err := NewBlockChangeMessage(1, tt.branch, tt.blockers, nil)
 			if tt.wantErr {
 				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
 					t.Errorf("expected error containing %q, got %v", tt.errSubstr, err)
@@ -385,6 +422,9 @@ func TestBlockChangeMessage(t *testing.T) {
 				t.Errorf("unexpected error = %v", err)
 				return
 			}
+			if msg.Blocked() != tt.wantBlocked {
+				t.Errorf("Blocked() = %v, want %v", msg.Blocked(), tt.wantBlocked)
+			}
 
 			// Test round-trip
 			wire := msg.ToWireFormat()
@@ -395,10 +435,79 @@ func TestBlockChangeMessage(t *testing.T) {
 			if msg2.MessageType() != MsgTypeBlockChange {
 				t.Errorf("MessageType() = %v, want %v", msg2.MessageType(), MsgTypeBlockChange)
 			}
+			if got := msg2.(*BlockChangeMessageV2).Blockers(); !reflect.DeepEqual(got, msg.Blockers()) {
+				t.Errorf("round-trip Blockers() = %v, want %v", got, msg.Blockers())
+			}
 		})
 	}
 }
 
+// TestBlockChangeMessage_WireCompatibility tests decoding block_change messages from
+// daemons that only set blocked_branch, and rejecting blocked messages without a blocker.
+func TestBlockChangeMessage_WireCompatibility(t *testing.T) {
+	msg, err := FromWireFormat(Message{Type: MsgTypeBlockChange, Branch: "feature", BlockedBranch: "main", Blocked: true})
+	if err != nil {
+		t.Fatalf("FromWireFormat() error = %v", err)
+	}
+	if got := msg.(*BlockChangeMessageV2).Blockers(); !reflect.DeepEqual(got, []string{"main"}) {
+		t.Errorf("Blockers() = %v, want [main]", got)
+	}
+
+	_, err = FromWireFormat(Message{Type: MsgTypeBlockChange, Branch: "feature", Blocked: true})
+	if err == nil || !strings.Contains(err.Error(), "blocked_branch required") {
+		t.Errorf("expected 'blocked_branch required' error, got %v", err)
+	}
+}
+
+// TestBlockTTL_RoundTrip tests that block TTLs and expiries survive the wire format,
+// and that a block_change drops expiries of blockers it doesn't list.
+func TestBlockTTL_RoundTrip(t *testing.T) {
+	block, err := NewBlockBranchMessage(1, "feature", "main", 2*time.Hour)
+	if err != nil {
+		t.Fatalf("NewBlockBranchMessage() error = %v", err)
+	}
+	decoded, err := FromWireFormat(block.ToWireFormat())
+	if err != nil {
+		t.Fatalf("FromWireFormat() error = %v", err)
+	}
+	if got := decoded.(*BlockBranchMessageV2).TTL(); got != 2*time.Hour {
+		t.Errorf("round-trip TTL() = %v, want 2h", got)
+	}
+	if _, err := NewBlockBranchMessage(1, "feature", "main", -time.Second); err == nil {
+		t.Error("expected error for negative TTL")
+	}
+
+	expiresAt := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	change, err := NewBlockChangeMessage(2, "feature", []string{"main", "develop"},
+		map[string]time.Time{"main": expiresAt, "stale": expiresAt})
+	if err != nil {
+		t.Fatalf("NewBlockChangeMessage() error = %v", err)
+	}
+	wire := change.ToWireFormat()
+	if want := (BlockExpiries{"feature": {"main": expiresAt}}); !reflect.DeepEqual(wire.BlockExpiries, want) {
+		t.Errorf("wire BlockExpiries = %v, want %v", wire.BlockExpiries, want)
+	}
+	decoded, err = FromWireFormat(wire)
+	if err != nil {
+		t.Fatalf("FromWireFormat() error = %v", err)
+	}
+	if got := decoded.(*BlockChangeMessageV2).Expiries(); !reflect.DeepEqual(got, map[string]time.Time{"main": expiresAt}) {
+		t.Errorf("round-trip Expiries() = %v", got)
+	}
+
+	state, err := NewFullStateMessage(3, nil, nil, BlockMap{"feature": {"main"}}, BlockExpiries{"feature": {"main": expiresAt}}, "", "")
+	if err != nil {
+		t.Fatalf("NewFullStateMessage() error = %v", err)
+	}
+	decoded, err = FromWireFormat(state.ToWireFormat())
+	if err != nil {
+		t.Fatalf("FromWireFormat() error = %v", err)
+	}
+	if got := decoded.(*FullStateMessageV2).BlockExpiries(); !got["feature"]["main"].Equal(expiresAt) {
+		t.Errorf("round-trip BlockExpiries() = %v", got)
+	}
+}
+
 // TestQueryBlockedStateMessage tests QueryBlockedStateMessageV2 validation
 func TestQueryBlockedStateMessage(t *testing.T) {
 	msg, err := NewQueryBlockedStateMessage(1, "feature")
@@ -427,46 +536,41 @@ func TestBlockedStateResponseMessage(t *testing.T) {
 	tests := []struct {
 		name          string
 		branch        string
-		isBlocked     bool
-		blockedBranch string
+		blockers      []string
+		wantIsBlocked bool
 		wantErr       bool
 		errSubstr     string
 	}{
 		{
 			name:          "valid blocked response",
 			branch:        "feature",
-			isBlocked:     true,
-			blockedBranch: "main",
-			wantErr:       false,
+			blockers:      []string{"main"},
+			wantIsBlocked: true,
 		},
 		{
-			name:          "valid unblocked response",
+			name:          "multiple blockers",
 			branch:        "feature",
-			isBlocked:     false,
-			blockedBranch: "",
-			wantErr:       false,
+			blockers:      []string{"main", "develop"},
+			wantIsBlocked: true,
 		},
 		{
-			name:          "empty branch",
-			branch:        "",
-			isBlocked:     true,
-			blockedBranch: "main",
-			wantErr:       true,
-			errSubstr:     "branch required",
+			name:          "valid unblocked response",
+			branch:        "feature",
+			blockers:      nil,
+			wantIsBlocked: false,
 		},
 		{
-			name:          "blocked=true but empty blocked_branch",
-			branch:        "feature",
-			isBlocked:     true,
-			blockedBranch: "",
-			wantErr:       true,
-			errSubstr:     "blocked_branch required",
+			name:      "empty branch",
+			branch:    "",
+			blockers:  []string{"main"},
+			wantErr:   true,
+			errSubstr: "branch required",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			msg, err := NewBlockedStateResponseMessage(1, tt.branch, tt.isBlocked, tt.blockedBranch)
+			msg, err := NewBlockedStateResponseMessage(1, tt.branch, tt.blockers)
 			if tt.wantErr {
 				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
 					t.Errorf("expected error containing %q, got %v", tt.errSubstr, err)
@@ -477,6 +581,9 @@ func TestBlockedStateResponseMessage(t *testing.T) {
 				t.Errorf("unexpected error = %v", err)
 				return
 			}
+			if msg.IsBlocked() != tt.wantIsBlocked {
+				t.Errorf("IsBlocked() = %v, want %v", msg.IsBlocked(), tt.wantIsBlocked)
+			}
 
 			wire := msg.ToWireFormat()
 			msg2, err := FromWireFormat(wire)
@@ -486,16 +593,20 @@ func TestBlockedStateResponseMessage(t *testing.T) {
 			if msg2.MessageType() != MsgTypeBlockedStateResponse {
 				t.Errorf("MessageType() = %v, want %v", msg2.MessageType(), MsgTypeBlockedStateResponse)
 			}
+			if got := msg2.(*BlockedStateResponseMessageV2).Blockers(); !reflect.DeepEqual(got, msg.Blockers()) {
+				t.Errorf("round-trip Blockers() = %v, want %v", got, msg.Blockers())
+			}
 		})
 	}
 }
 
-// TestBlockedStateResponseMessage_ConditionalValidation tests conditional validation rules.
+// TestBlockedStateResponseMessage_ConditionalValidation tests conditional validation rules
+// applied when decoding wire messages.
 func TestBlockedStateResponseMessage_ConditionalValidation(t *testing.T) {
 	t.Run("blocked_true_requires_blockedBranch", func(t *testing.T) {
-		_, err := NewBlockedStateResponseMessage(42, "branch1", true, "")
+		_, err := FromWireFormat(Message{Type: MsgTypeBlockedStateResponse, Branch: "branch1", IsBlocked: true})
 		if err == nil {
-			t.Error("Expected error when blocked=true but blockedBranch empty")
+			t.Fatal("Expected error when blocked=true but blockedBranch empty")
 		}
 		if !strings.Contains(err.Error(), "blocked_branch required") {
 			t.Errorf("Error should mention blocked_branch requirement, got: %v", err)
@@ -503,12 +614,22 @@ func TestBlockedStateResponseMessage_ConditionalValidation(t *testing.T) {
 	})
 
 	t.Run("blocked_false_clears_blockedBranch", func(t *testing.T) {
-		msg, err := NewBlockedStateResponseMessage(42, "branch1", false, "unexpected")
+		msg, err := FromWireFormat(Message{Type: MsgTypeBlockedStateResponse, Branch: "branch1", BlockedBranch: "unexpected"})
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
-		if msg.BlockedBranch() != "" {
-			t.Errorf("BlockedBranch should be cleared when blocked=false, got %q", msg.BlockedBranch())
+		if got := msg.(*BlockedStateResponseMessageV2).BlockedBranch(); got != "" {
+			t.Errorf("BlockedBranch should be cleared when blocked=false, got %q", got)
+		}
+	})
+
+	t.Run("blocked_branch_only_from_older_daemon", func(t *testing.T) {
+		msg, err := FromWireFormat(Message{Type: MsgTypeBlockedStateResponse, Branch: "branch1", IsBlocked: true, BlockedBranch: "main"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := msg.(*BlockedStateResponseMessageV2).Blockers(); !reflect.DeepEqual(got, []string{"main"}) {
+			t.Errorf("Blockers() = %v, want [main]", got)
 		}
 	})
 }
@@ -627,7 +748,7 @@ func TestSyncWarningMessage(t *testing.T) {
 
 // TestResyncRequestMessage tests ResyncRequestMessageV2
 func TestResyncRequestMessage(t *testing.T) {
-	msg, err := NewResyncRequestMessage(1)
+	msg, err := NewResyncRequestMessage(1, false)
 	if err != nil {
 		t.Fatalf("NewResyncRequestMessage() error = %v", err)
 	}
@@ -637,6 +758,12 @@ func TestResyncRequestMessage(t *testing.T) {
 	if err != nil || msg2.MessageType() != MsgTypeResyncRequest {
 		t.Errorf("ResyncRequest round-trip failed: err=%v, type=%v", err, msg2.MessageType())
 	}
+
+	forced, _ := NewResyncRequestMessage(2, true)
+	msg2, err = FromWireFormat(forced.ToWireFormat())
+	if err != nil || !msg2.(*ResyncRequestMessageV2).Force() {
+		t.Errorf("Forced ResyncRequest round-trip lost force: err=%v, msg=%+v", err, msg2)
+	}
 }
 
 // TestPersistenceErrorMessage tests PersistenceErrorMessageV2
@@ -676,6 +803,200 @@ func TestAudioErrorMessage(t *testing.T) {
 	}
 }
 
+// TestBlockRejectedMessage tests BlockRejectedMessageV2 validation and round-trip
+func TestBlockRejectedMessage(t *testing.T) {
+	if _, err := NewBlockRejectedMessage(1, "  ", "feature", "protected branch"); err == nil {
+		t.Error("NewBlockRejectedMessage() with empty branch should fail")
+	}
+	if _, err := NewBlockRejectedMessage(1, "main", "feature", "  "); err == nil {
+		t.Error("NewBlockRejectedMessage() with empty reason should fail")
+	}
+
+	msg, err := NewBlockRejectedMessage(1, "main", "feature", "protected branch")
+	if err != nil {
+		t.Fatalf("NewBlockRejectedMessage() error = %v", err)
+	}
+	msg2, err := FromWireFormat(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("BlockRejected round-trip failed: %v", err)
+	}
+	rejected, ok := msg2.(*BlockRejectedMessageV2)
+	if !ok {
+		t.Fatalf("FromWireFormat() returned %T, want *BlockRejectedMessageV2", msg2)
+	}
+	if rejected.Branch() != "main" || rejected.BlockedBranch() != "feature" || rejected.Reason() != "protected branch" {
+		t.Errorf("Round-trip mismatch: %+v", rejected)
+	}
+	if rejected.CyclePath() != nil {
+		t.Errorf("CyclePath() = %v, want nil for a non-cycle rejection", rejected.CyclePath())
+	}
+}
+
+// TestBlockCycleRejectedMessage tests the cycle variant of BlockRejectedMessageV2
+func TestBlockCycleRejectedMessage(t *testing.T) {
+	for _, cycle := range [][]string{nil, {"a"}, {"a", "c", "a"}, {"a", "b", "c"}} {
+		if _, err := NewBlockCycleRejectedMessage(1, "a", "b", cycle); err == nil {
+			t.Errorf("NewBlockCycleRejectedMessage() with cycle %v should fail", cycle)
+		}
+	}
+
+	msg, err := NewBlockCycleRejectedMessage(1, "a", "b", []string{"a", "b", "c", "a"})
+	if err != nil {
+		t.Fatalf("NewBlockCycleRejectedMessage() error = %v", err)
+	}
+	wantReason := "block cycle: 'a' cannot be blocked by 'b' (a -> b -> c -> a)"
+	if msg.Reason() != wantReason {
+		t.Errorf("Reason() = %q, want %q", msg.Reason(), wantReason)
+	}
+	msg2, err := FromWireFormat(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("BlockRejected round-trip failed: %v", err)
+	}
+	rejected := msg2.(*BlockRejectedMessageV2)
+	if rejected.Reason() != wantReason || !reflect.DeepEqual(rejected.CyclePath(), []string{"a", "b", "c", "a"}) {
+		t.Errorf("Round-trip mismatch: reason=%q cycle=%v", rejected.Reason(), rejected.CyclePath())
+	}
+}
+
+// TestRejectedMessage tests RejectedMessageV2 validation and round-trip
+func TestRejectedMessage(t *testing.T) {
+	if _, err := NewRejectedMessage(0, "  "); err == nil {
+		t.Error("NewRejectedMessage() with empty reason should fail")
+	}
+
+	msg, err := NewRejectedMessage(0, " client limit reached ")
+	if err != nil {
+		t.Fatalf("NewRejectedMessage() error = %v", err)
+	}
+	if err := ValidateMessage(msg.ToWireFormat()); err != nil {
+		t.Errorf("ValidateMessage() error = %v", err)
+	}
+	msg2, err := FromWireFormat(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("Rejected round-trip failed: %v", err)
+	}
+	rejected, ok := msg2.(*RejectedMessageV2)
+	if !ok {
+		t.Fatalf("FromWireFormat() returned %T, want *RejectedMessageV2", msg2)
+	}
+	if rejected.Reason() != "client limit reached" {
+		t.Errorf("Reason() = %q, want %q", rejected.Reason(), "client limit reached")
+	}
+}
+
+// TestVersionMismatchMessage tests VersionMismatchMessageV2 validation and its JSON
+// serialization
+func TestVersionMismatchMessage(t *testing.T) {
+	if _, err := NewVersionMismatchMessage(0, " ", "incompatible"); err == nil {
+		t.Error("NewVersionMismatchMessage() with empty version should fail")
+	}
+	if _, err := NewVersionMismatchMessage(0, "2.0", "  "); err == nil {
+		t.Error("NewVersionMismatchMessage() with empty reason should fail")
+	}
+
+	msg, err := NewVersionMismatchMessage(0, "2.0", "client speaks protocol 3.0, daemon speaks 2.0")
+	if err != nil {
+		t.Fatalf("NewVersionMismatchMessage() error = %v", err)
+	}
+	data, err := json.Marshal(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"type":"version_mismatch","error":"client speaks protocol 3.0, daemon speaks 2.0","protocol_version":"2.0"}`
+	if string(data) != want {
+		t.Errorf("Wire JSON = %s, want %s", data, want)
+	}
+
+	var wire Message
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	msg2, err := FromWireFormat(wire)
+	if err != nil {
+		t.Fatalf("VersionMismatch round-trip failed: %v", err)
+	}
+	mismatch, ok := msg2.(*VersionMismatchMessageV2)
+	if !ok {
+		t.Fatalf("FromWireFormat() returned %T, want *VersionMismatchMessageV2", msg2)
+	}
+	if mismatch.ProtocolVersion() != "2.0" || mismatch.Reason() != msg.Reason() {
+		t.Errorf("Round-trip mismatch: version=%q reason=%q", mismatch.ProtocolVersion(), mismatch.Reason())
+	}
+
+	if err := ValidateMessage(Message{Type: MsgTypeVersionMismatch, Error: "incompatible"}); err == nil {
+		t.Error("ValidateMessage() should require protocol_version")
+	}
+}
+
+// TestHelloMessage_ProtocolVersion tests that hello announces this build's protocol
+// version and that FromWireFormat keeps the sender's
+func TestHelloMessage_ProtocolVersion(t *testing.T) {
+	msg, err := NewHelloMessage(0, "tui")
+	if err != nil {
+		t.Fatalf("NewHelloMessage() error = %v", err)
+	}
+	if got := msg.ToWireFormat().ProtocolVersion; got != ProtocolVersion {
+		t.Errorf("hello protocol_version = %q, want %q", got, ProtocolVersion)
+	}
+
+	for _, version := range []string{"", "3.1"} {
+		msg2, err := FromWireFormat(Message{Type: MsgTypeHello, ClientID: "tui", ProtocolVersion: version})
+		if err != nil {
+			t.Fatalf("FromWireFormat() error = %v", err)
+		}
+		if got := msg2.(*HelloMessageV2).ProtocolVersion(); got != version {
+			t.Errorf("ProtocolVersion() = %q, want %q", got, version)
+		}
+	}
+}
+
+func TestCheckProtocolVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{"", true}, // Clients that predate versioning speak major version 2
+		{ProtocolVersion, false},
+		{"3.7", false},
+		{"2.0", true}, // Single blocker per branch in blocked_branches
+		{"4.0", true},
+		{"1.0", true},
+		{"garbage", true},
+	}
+	for _, tt := range tests {
+		err := CheckProtocolVersion(tt.version)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckProtocolVersion(%q) = %v, wantErr %v", tt.version, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrProtocolMismatch) {
+			t.Errorf("CheckProtocolVersion(%q) = %v, want ErrProtocolMismatch", tt.version, err)
+		}
+	}
+}
+
+func TestListResponseMessage_RoundTrip(t *testing.T) {
+	alerts := map[string]string{"%1": "stop", "%2": "idle"}
+	blocked := BlockMap{"feature": {"main"}}
+	msg, err := NewListResponseMessage(3, alerts, blocked)
+	if err != nil {
+		t.Fatalf("NewListResponseMessage() error = %v", err)
+	}
+	alerts["%3"] = "stop" // constructor must copy
+
+	msg2, err := FromWireFormat(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("ListResponse round-trip failed: %v", err)
+	}
+	resp, ok := msg2.(*ListResponseMessageV2)
+	if !ok {
+		t.Fatalf("FromWireFormat() returned %T, want *ListResponseMessageV2", msg2)
+	}
+	if !reflect.DeepEqual(resp.Alerts(), map[string]string{"%1": "stop", "%2": "idle"}) ||
+		!reflect.DeepEqual(resp.BlockedBranches(), blocked) {
+		t.Errorf("Round-trip mismatch: alerts=%v blocked=%v", resp.Alerts(), resp.BlockedBranches())
+	}
+}
+
 // TestShowBlockPickerMessage tests ShowBlockPickerMessageV2 validation
 func TestShowBlockPickerMessage(t *testing.T) {
 	tests := []struct {
@@ -699,7 +1020,7 @@ func TestShowBlockPickerMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			msg, err := NewShowBlockPickerMessage(1, tt.paneID)
+			msg, err := NewShowBlockPickerMessage(1, tt.paneID, 0)
 			if tt.wantErr {
 				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
 					t.Errorf("expected error containing %q, got %v", tt.errSubstr, err)
@@ -755,14 +1076,14 @@ func TestValidationFailures_LogOriginalValues(t *testing.T) {
 		{
 			name: "alert_change_whitespace_pane_id",
 			attemptConstruction: func() (MessageV2, error) {
-				return NewAlertChangeMessage(1, "  ", "alert", true)
+				return NewAlertChangeMessage(1, "  ", "alert", true, time.Time{}, "")
 			},
 			shouldFail: true,
 		},
 		{
 			name: "alert_change_whitespace_event_type",
 			attemptConstruction: func() (MessageV2, error) {
-				return NewAlertChangeMessage(1, "pane-1", "  \n  ", true)
+				return NewAlertChangeMessage(1, "pane-1", "  \n  ", true, time.Time{}, "")
 			},
 			shouldFail: true,
 		},
@@ -776,7 +1097,7 @@ func TestValidationFailures_LogOriginalValues(t *testing.T) {
 		{
 			name: "block_branch_whitespace_branch",
 			attemptConstruction: func() (MessageV2, error) {
-				return NewBlockBranchMessage(1, " ", "feature")
+				return NewBlockBranchMessage(1, " ", "feature", 0)
 			},
 			shouldFail: true,
 		},
@@ -1336,6 +1657,40 @@ func TestTreeUpdateMessage_FromWireFormat(t *testing.T) {
 			t.Errorf("MessageType() = %v, want %v", msg.MessageType(), MsgTypeTreeUpdate)
 		}
 	})
+
+	t.Run("partial_tree_warning_round_trip", func(t *testing.T) {
+		tree := NewRepoTree()
+		pane, _ := NewPane("%1", "/path", "@0", 0, true, false, "zsh", "title", false)
+		tree.SetPanes("repo", "branch", []Pane{pane})
+
+		if _, err := NewPartialTreeUpdateMessage(1, tree, "  "); err == nil {
+			t.Error("NewPartialTreeUpdateMessage() with blank warning should fail")
+		}
+
+		orig, err := NewPartialTreeUpdateMessage(11, tree, "1 of 2 panes could not be read")
+		if err != nil {
+			t.Fatalf("NewPartialTreeUpdateMessage() error = %v", err)
+		}
+		wire := orig.ToWireFormat()
+		if wire.Error != "1 of 2 panes could not be read" {
+			t.Errorf("wire Error = %q, want the warning", wire.Error)
+		}
+
+		msg, err := FromWireFormat(wire)
+		if err != nil {
+			t.Fatalf("FromWireFormat() error = %v", err)
+		}
+		got := msg.(*TreeUpdateMessageV2)
+		if got.Warning() != orig.Warning() || got.Tree().TotalPanes() != 1 {
+			t.Errorf("Round trip = warning %q, %d panes; want %q, 1 pane", got.Warning(), got.Tree().TotalPanes(), orig.Warning())
+		}
+
+		// Complete trees carry no warning
+		complete, _ := NewTreeUpdateMessage(12, tree)
+		if w := complete.ToWireFormat().Error; w != "" {
+			t.Errorf("Complete tree_update Error = %q, want empty", w)
+		}
+	})
 }
 
 // TestTreeErrorMessage tests TreeErrorMessageV2 construction, validation, and serialization
@@ -1725,3 +2080,43 @@ func TestTreeUpdateMessage_JSONSerialization(t *testing.T) {
 		t.Errorf("Pane is_claude_pane = %v, want true", firstPane["is_claude_pane"])
 	}
 }
+
+// TestRenameBranchMessage tests RenameBranchMessageV2 validation and round-trip
+func TestRenameBranchMessage(t *testing.T) {
+	for _, tc := range []struct{ old, new string }{{"", "feature"}, {"feature", " "}, {"feature", " feature "}} {
+		if _, err := NewRenameBranchMessage(0, tc.old, tc.new); err == nil {
+			t.Errorf("NewRenameBranchMessage(%q, %q) should fail", tc.old, tc.new)
+		}
+	}
+
+	msg, err := NewRenameBranchMessage(3, " feature ", "feature-v2")
+	if err != nil {
+		t.Fatalf("NewRenameBranchMessage() error = %v", err)
+	}
+	msg2, err := FromWireFormat(msg.ToWireFormat())
+	if err != nil {
+		t.Fatalf("RenameBranch round-trip failed: %v", err)
+	}
+	rename, ok := msg2.(*RenameBranchMessageV2)
+	if !ok {
+		t.Fatalf("FromWireFormat() returned %T, want *RenameBranchMessageV2", msg2)
+	}
+	if rename.OldBranch() != "feature" || rename.NewBranch() != "feature-v2" || rename.SeqNumber() != 3 {
+		t.Errorf("Round-trip mismatch: %+v", rename)
+	}
+
+	if err := ValidateMessage(Message{Type: MsgTypeRenameBranchResult, Branch: "feature"}); err == nil {
+		t.Error("ValidateMessage() should require new_branch on rename_branch_result")
+	}
+	result, err := NewRenameBranchResultMessage(4, "feature", "feature-v2", " already has blocks ")
+	if err != nil {
+		t.Fatalf("NewRenameBranchResultMessage() error = %v", err)
+	}
+	msg2, err = FromWireFormat(result.ToWireFormat())
+	if err != nil {
+		t.Fatalf("RenameBranchResult round-trip failed: %v", err)
+	}
+	if got := msg2.(*RenameBranchResultMessageV2); got.Error() != "already has blocks" || got.NewBranch() != "feature-v2" {
+		t.Errorf("Round-trip mismatch: %+v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"sort"
+	"time"
+)
+
+// reorderBufferSize is how many messages a reorderBuffer holds before giving up on a gap
+const reorderBufferSize = 16
+
+// reorderBuffer holds messages that arrive ahead of a sequence gap for a short window,
+// so a message that was merely reordered can fill the gap before a resync is requested.
+// It belongs to a single receive goroutine and is not safe for concurrent use.
+type reorderBuffer struct {
+	window  time.Duration
+	pending []Message // Held messages, sorted by SeqNum (arrival order among equal ones)
+	timer   *time.Timer
+}
+
+func newReorderBuffer(window time.Duration) *reorderBuffer {
+	return &reorderBuffer{window: window}
+}
+
+// push takes a sequenced message given the last sequence number delivered. It returns
+// the messages now ready for delivery, in order, and the new last sequence number.
+// A message at or below lastSeq is late: it is delivered as is and never moves lastSeq
+// back. A message past a gap is held until the gap fills or the window expires;
+// overflow reports that the buffer is full and the caller should flush it.
+func (b *reorderBuffer) push(msg Message, lastSeq uint64) (ready []Message, newLast uint64, overflow bool) {
+	if lastSeq != 0 && msg.SeqNum > lastSeq+1 {
+		i := sort.Search(len(b.pending), func(i int) bool { return b.pending[i].SeqNum > msg.SeqNum })
+		b.pending = append(b.pending, Message{})
+		copy(b.pending[i+1:], b.pending[i:])
+		b.pending[i] = msg
+		if b.timer == nil {
+			b.timer = time.NewTimer(b.window)
+		}
+		return nil, lastSeq, len(b.pending) > reorderBufferSize
+	}
+
+	ready = append(ready, msg)
+	lastSeq = max(lastSeq, msg.SeqNum)
+
+	// The message may have filled a gap: release the held messages that now follow on
+	n := 0
+	for n < len(b.pending) && b.pending[n].SeqNum <= lastSeq+1 {
+		lastSeq = max(lastSeq, b.pending[n].SeqNum)
+		n++
+	}
+	ready = append(ready, b.pending[:n]...)
+	b.pending = b.pending[n:]
+	if len(b.pending) == 0 {
+		b.stop()
+	}
+	return ready, lastSeq, false
+}
+
+// flush gives up on the gap: it returns every held message in order, the new last
+// sequence number and how many sequence numbers never arrived.
+func (b *reorderBuffer) flush(lastSeq uint64) (ready []Message, newLast uint64, missing uint64) {
+	b.stop()
+	ready, b.pending = b.pending, nil
+	for _, msg := range ready {
+		if msg.SeqNum > lastSeq+1 {
+			missing += msg.SeqNum - lastSeq - 1
+		}
+		lastSeq = max(lastSeq, msg.SeqNum)
+	}
+	return ready, lastSeq, missing
+}
+
+// expired fires when the held messages' window runs out (nil while nothing is held)
+func (b *reorderBuffer) expired() <-chan time.Time {
+	if b.timer == nil {
+		return nil
+	}
+	return b.timer.C
+}
+
+// stop cancels the window timer
+func (b *reorderBuffer) stop() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func seqs(msgs []Message) []uint64 {
+	out := make([]uint64, len(msgs))
+	for i, m := range msgs {
+		out[i] = m.SeqNum
+	}
+	return out
+}
+
+func equalSeqs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReorderBuffer_FillsGap(t *testing.T) {
+	b := newReorderBuffer(time.Minute)
+	defer b.stop()
+
+	ready, last, _ := b.push(Message{SeqNum: 1}, 0)
+	if !equalSeqs(seqs(ready), []uint64{1}) || last != 1 {
+		t.Fatalf("push(1) = %v, %d", seqs(ready), last)
+	}
+
+	for _, seq := range []uint64{4, 3} {
+		ready, last, _ = b.push(Message{SeqNum: seq}, last)
+		if len(ready) != 0 || last != 1 {
+			t.Fatalf("push(%d) past the gap = %v, %d; want it held", seq, seqs(ready), last)
+		}
+	}
+	if b.expired() == nil {
+		t.Error("Expected the window timer to run while messages are held")
+	}
+
+	ready, last, _ = b.push(Message{SeqNum: 2}, last)
+	if !equalSeqs(seqs(ready), []uint64{2, 3, 4}) || last != 4 {
+		t.Errorf("push(2) = %v, %d; want [2 3 4], 4", seqs(ready), last)
+	}
+	if b.expired() != nil {
+		t.Error("Expected the window timer to stop once the gap is filled")
+	}
+}
+
+func TestReorderBuffer_LateMessageKeepsLastSeq(t *testing.T) {
+	b := newReorderBuffer(time.Minute)
+	defer b.stop()
+
+	ready, last, _ := b.push(Message{SeqNum: 3}, 5)
+	if !equalSeqs(seqs(ready), []uint64{3}) || last != 5 {
+		t.Errorf("push(3) after 5 = %v, %d; want [3], 5", seqs(ready), last)
+	}
+}
+
+func TestReorderBuffer_FlushReportsMissing(t *testing.T) {
+	b := newReorderBuffer(time.Minute)
+	for _, seq := range []uint64{4, 7, 7} {
+		b.push(Message{SeqNum: seq}, 1)
+	}
+
+	ready, last, missing := b.flush(1)
+	if !equalSeqs(seqs(ready), []uint64{4, 7, 7}) || last != 7 || missing != 4 {
+		t.Errorf("flush() = %v, %d, %d; want [4 7 7], 7, 4", seqs(ready), last, missing)
+	}
+	if b.expired() != nil || len(b.pending) != 0 {
+		t.Error("Expected flush to empty the buffer and stop the timer")
+	}
+}
+
+func TestReorderBuffer_Overflow(t *testing.T) {
+	b := newReorderBuffer(time.Minute)
+	defer b.stop()
+
+	var overflow bool
+	for i := 0; i <= reorderBufferSize; i++ {
+		_, _, overflow = b.push(Message{SeqNum: uint64(10 + i)}, 1)
+		if overflow != (i == reorderBufferSize) {
+			t.Fatalf("push %d: overflow = %v", i+1, overflow)
+		}
+	}
+}
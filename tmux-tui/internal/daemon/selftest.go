@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// SelfTestPaths identifies the filesystem resources the daemon needs at runtime.
+type SelfTestPaths struct {
+	AlertDir    string // Watched for pane focus (and hook alert) files
+	SocketPath  string // Unix socket clients connect to
+	BlockedPath string // Blocked branches persistence file
+}
+
+// SelfTestCheck is the outcome of a single startup check.
+type SelfTestCheck struct {
+	Name   string
+	Target string
+	Err    error // nil when the check passed
+	Fatal  bool  // a failed fatal check means the daemon cannot work
+}
+
+// SelfTestReport collects the startup checks run by RunSelfTest.
+type SelfTestReport struct {
+	Checks []SelfTestCheck
+}
+
+// Failed reports whether any fatal check failed.
+func (r SelfTestReport) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Err != nil && c.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// Write prints one line per check to w.
+func (r SelfTestReport) Write(w io.Writer) {
+	fmt.Fprintln(w, "Daemon self-test:")
+	for _, c := range r.Checks {
+		switch {
+		case c.Err == nil:
+			fmt.Fprintf(w, "  ✓ %-12s %s\n", c.Name, c.Target)
+		case c.Fatal:
+			fmt.Fprintf(w, "  ✗ %-12s %s: %v\n", c.Name, c.Target, c.Err)
+		default:
+			fmt.Fprintf(w, "  ⚠ %-12s %s: %v\n", c.Name, c.Target, c.Err)
+		}
+	}
+}
+
+// RunSelfTest verifies that the daemon can write its persistence file, bind its
+// socket, reach its watch directory and open the terminal used for alert sounds.
+// Checks only probe resources (temp files and sockets are removed again), so it
+// is safe to run before Start.
+//
+// Audio failures are non-fatal: the daemon still works without sound.
+func RunSelfTest(paths SelfTestPaths) SelfTestReport {
+	report := SelfTestReport{Checks: []SelfTestCheck{
+		{Name: "persistence", Target: paths.BlockedPath, Err: checkWritableFile(paths.BlockedPath), Fatal: true},
+		{Name: "socket", Target: paths.SocketPath, Err: checkSocketBind(paths.SocketPath), Fatal: true},
+		{Name: "watch-dir", Target: paths.AlertDir, Err: checkReadableDir(paths.AlertDir), Fatal: true},
+		{Name: "audio", Target: "/dev/tty", Err: checkAudio(), Fatal: false},
+	}}
+
+	for _, c := range report.Checks {
+		debug.Log("DAEMON_SELFTEST check=%s target=%s fatal=%v error=%v", c.Name, c.Target, c.Fatal, c.Err)
+	}
+	return report
+}
+
+// SelfTest runs RunSelfTest against this daemon's paths.
+func (d *AlertDaemon) SelfTest() SelfTestReport {
+	return RunSelfTest(SelfTestPaths{
+		AlertDir:    d.alertDir,
+		SocketPath:  d.socketPath,
+		BlockedPath: d.blockedPath,
+	})
+}
+
+// checkWritableFile verifies path can be written without modifying it: an existing
+// file is opened for writing (no truncation), otherwise a probe file is created
+// next to it.
+func checkWritableFile(path string) error {
+	if path == "" {
+		return fmt.Errorf("path not configured")
+	}
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("is a directory")
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("not writable: %w", err)
+		}
+		return f.Close()
+	}
+
+	probe, err := os.CreateTemp(filepath.Dir(path), ".selftest-*")
+	if err != nil {
+		return fmt.Errorf("directory not writable: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// checkSocketBind verifies a Unix socket can be bound in the socket's directory.
+// A probe path is used so a socket left by a previous daemon is not disturbed.
+func checkSocketBind(socketPath string) error {
+	if socketPath == "" {
+		return fmt.Errorf("path not configured")
+	}
+	probePath := socketPath + ".selftest"
+	os.Remove(probePath)
+	listener, err := net.Listen("unix", probePath)
+	if err != nil {
+		return fmt.Errorf("cannot bind: %w", err)
+	}
+	listener.Close()
+	if err := os.Remove(probePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove probe socket: %w", err)
+	}
+	return nil
+}
+
+// checkReadableDir verifies dir exists and can be listed.
+func checkReadableDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("path not configured")
+	}
+	if _, err := os.ReadDir(dir); err != nil {
+		return fmt.Errorf("not readable: %w", err)
+	}
+	return nil
+}
+
+// checkAudio verifies the alert terminal can be opened, without writing to it.
+func checkAudio() error {
+	if os.Getenv("CLAUDE_E2E_TEST") != "" {
+		return nil // Sound is disabled under E2E tests
+	}
+	f, err := openAlertTTY()
+	if err != nil {
+		return fmt.Errorf("alert sounds unavailable: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("alert sounds unavailable: %w", err)
+	}
+	return nil
+}
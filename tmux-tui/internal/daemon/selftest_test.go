@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newSelfTestDaemon returns a daemon whose paths all live in a fresh temp dir.
+func newSelfTestDaemon(t *testing.T) *AlertDaemon {
+	t.Helper()
+	dir := t.TempDir()
+	return &AlertDaemon{
+		alertDir:    dir,
+		socketPath:  filepath.Join(dir, "daemon.sock"),
+		blockedPath: filepath.Join(dir, "blocked-branches.json"),
+	}
+}
+
+func findCheck(t *testing.T, report SelfTestReport, name string) SelfTestCheck {
+	t.Helper()
+	for _, c := range report.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("self-test report has no %q check: %+v", name, report.Checks)
+	return SelfTestCheck{}
+}
+
+func TestSelfTest_AllPass(t *testing.T) {
+	originalWriter := ttyWriter
+	ttyWriter = func() (io.WriteCloser, error) { return nopWriteCloser{io.Discard}, nil }
+	defer func() { ttyWriter = originalWriter }()
+
+	d := newSelfTestDaemon(t)
+	report := d.SelfTest()
+	for _, c := range report.Checks {
+		if c.Err != nil {
+			t.Errorf("check %s failed: %v", c.Name, c.Err)
+		}
+	}
+	if report.Failed() {
+		t.Error("Expected report to pass")
+	}
+
+	// Probes must not leave files behind or create the persistence file
+	entries, err := os.ReadDir(d.alertDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected self-test to clean up, found %d entries", len(entries))
+	}
+}
+
+// TestSelfTest_NonWritablePersistencePath verifies that a persistence path the
+// daemon cannot write is reported at startup rather than at the first block.
+func TestSelfTest_NonWritablePersistencePath(t *testing.T) {
+	d := newSelfTestDaemon(t)
+
+	// A regular file as the parent directory makes the path unwritable even as root
+	notADir := filepath.Join(d.alertDir, "not-a-dir")
+	if err := os.WriteFile(notADir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	d.blockedPath = filepath.Join(notADir, "blocked-branches.json")
+
+	report := d.SelfTest()
+	check := findCheck(t, report, "persistence")
+	if check.Err == nil {
+		t.Fatal("Expected persistence check to fail")
+	}
+	if !check.Fatal || !report.Failed() {
+		t.Error("Expected persistence failure to be fatal")
+	}
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	if !strings.Contains(buf.String(), "✗ persistence") || !strings.Contains(buf.String(), d.blockedPath) {
+		t.Errorf("Expected report to name the failing persistence path, got:\n%s", buf.String())
+	}
+}
+
+func TestSelfTest_ReadonlyPersistenceDir(t *testing.T) {
+	if os.Getuid() == 0 {
+		t.Skip("Skipping permission test when running as root")
+	}
+
+	d := newSelfTestDaemon(t)
+	readonlyDir := filepath.Join(d.alertDir, "readonly-dir")
+	if err := os.Mkdir(readonlyDir, 0555); err != nil {
+		t.Fatalf("Failed to create readonly dir: %v", err)
+	}
+	defer os.Chmod(readonlyDir, 0755) // Cleanup
+	d.blockedPath = filepath.Join(readonlyDir, "blocked-branches.json")
+
+	if check := findCheck(t, d.SelfTest(), "persistence"); check.Err == nil {
+		t.Error("Expected persistence check to fail for readonly directory")
+	}
+}
+
+func TestSelfTest_MissingWatchDir(t *testing.T) {
+	d := newSelfTestDaemon(t)
+	d.alertDir = filepath.Join(d.alertDir, "missing")
+
+	if check := findCheck(t, d.SelfTest(), "watch-dir"); check.Err == nil || !check.Fatal {
+		t.Errorf("Expected fatal watch-dir failure, got %+v", check)
+	}
+}
+
+func TestSelfTest_AudioFailureIsWarning(t *testing.T) {
+	t.Setenv("CLAUDE_E2E_TEST", "")
+	originalWriter := ttyWriter
+	ttyWriter = func() (io.WriteCloser, error) { return nil, os.ErrPermission }
+	defer func() { ttyWriter = originalWriter }()
+
+	report := newSelfTestDaemon(t).SelfTest()
+	if check := findCheck(t, report, "audio"); check.Err == nil || check.Fatal {
+		t.Errorf("Expected non-fatal audio failure, got %+v", check)
+	}
+	if report.Failed() {
+		t.Error("Audio failure alone should not fail the self-test")
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
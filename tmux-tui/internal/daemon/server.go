@@ -2,10 +2,13 @@ package daemon
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net"
 	"os"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -14,6 +17,7 @@ import (
 	"github.com/commons-systems/tmux-tui/internal/detector"
 	"github.com/commons-systems/tmux-tui/internal/namespace"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
+	"github.com/commons-systems/tmux-tui/internal/version"
 	"github.com/commons-systems/tmux-tui/internal/watcher"
 )
 
@@ -37,6 +41,14 @@ type clientConnection struct {
 	conn      net.Conn
 	encoder   *json.Encoder
 	encoderMu sync.Mutex
+
+	// Reported by the clients query (see ClientInfo)
+	connectedAt time.Time     // When the hello was accepted
+	lastSeen    atomic.Int64  // Unix nanoseconds of the last message received (0 = none since hello)
+	lastPing    atomic.Int64  // Unix nanoseconds of the last ping answered (0 = never)
+	lastSeqSent atomic.Uint64 // Sequence number of the last sequenced message sent
+
+	lastFullStateHash atomic.Uint64 // fullStateHash of the state the client holds (0 = unknown, e.g. changed since the last full_state)
 }
 
 // successfulClient pairs a client ID with its connection for tracking
@@ -57,7 +69,13 @@ type eventKey struct {
 func (c *clientConnection) sendMessage(msg Message) error {
 	c.encoderMu.Lock()
 	defer c.encoderMu.Unlock()
-	return c.encoder.Encode(msg)
+	if err := c.encoder.Encode(msg); err != nil {
+		return err
+	}
+	if msg.SeqNum != 0 {
+		c.lastSeqSent.Store(msg.SeqNum)
+	}
+	return nil
 }
 
 // handlePersistenceError logs and broadcasts a persistence failure to all clients
@@ -88,21 +106,31 @@ func (d *AlertDaemon) handlePersistenceError(err error) {
 
 // revertBlockedBranchChange reverts a failed block/unblock operation and broadcasts the revert.
 // This is called when persistence fails to ensure in-memory state matches disk state.
-func (d *AlertDaemon) revertBlockedBranchChange(branch string, wasBlocked bool, previousBlockedBy string) {
+// previousBlockers is the branch's blocker list before the change (empty if it wasn't blocked)
+// and previousExpiries its block expiries (nil if none expired).
+func (d *AlertDaemon) revertBlockedBranchChange(branch string, previousBlockers []string, previousExpiries map[string]time.Time) {
 	d.blockedMu.Lock()
-	if wasBlocked {
+	if len(previousBlockers) > 0 {
 		// Restore previous blocked state
-		d.blockedBranches[branch] = previousBlockedBy
+		d.blockedBranches[branch] = previousBlockers
 	} else {
 		// Remove the block that failed to persist
 		delete(d.blockedBranches, branch)
 	}
+	if len(previousExpiries) > 0 {
+		if d.blockExpiries == nil {
+			d.blockExpiries = make(BlockExpiries)
+		}
+		d.blockExpiries[branch] = previousExpiries
+	} else {
+		delete(d.blockExpiries, branch)
+	}
 	d.blockedMu.Unlock()
 
 	// Broadcast revert so all clients show correct state
 	// TODO(#356): Add fallback notification when message construction fails
 	// Current: Silent no-op when NewBlockChangeMessage fails (see PR review #273)
-	msg, err := NewBlockChangeMessage(d.seqCounter.Add(1), branch, previousBlockedBy, wasBlocked)
+	msg, err := NewBlockChangeMessage(d.seqCounter.Add(1), branch, previousBlockers, previousExpiries)
 	if err != nil {
 		// TODO(#356): Add fallback notification for message construction failures
 		// See issue for details from PR #273 review
@@ -112,14 +140,17 @@ func (d *AlertDaemon) revertBlockedBranchChange(branch string, wasBlocked bool,
 	}
 	d.broadcast(msg.ToWireFormat())
 
-	debug.Log("DAEMON_REVERTED_BLOCK_CHANGE branch=%s wasBlocked=%v previousBlockedBy=%s",
-		branch, wasBlocked, previousBlockedBy)
+	debug.Log("DAEMON_REVERTED_BLOCK_CHANGE branch=%s wasBlocked=%v previousBlockers=%v",
+		branch, len(previousBlockers) > 0, previousBlockers)
 }
 
-// playAlertSound plays the alert sound via terminal escape sequences.
+// playAlertSound plays the alert sound for eventType via terminal escape sequences, or
+// the configured alert command (see AlertCommandEnv), wrapped in the event type's
+// optional pre/post commands (see AlertPreCommandEnv).
 //
 // Playback Conditions:
 //   - Skipped during E2E tests (CLAUDE_E2E_TEST env var set)
+//   - Skipped when alert audio is disabled (AlertCommandEnv set to empty)
 //   - Rate limited: Maximum 1 sound per 500ms to prevent excessive alerts
 //   - Only plays when transitioning TO an alert state (handleStateChangeEvent determines this)
 //
@@ -132,24 +163,43 @@ func (d *AlertDaemon) revertBlockedBranchChange(branch string, wasBlocked bool,
 //   - Works across SSH when terminal emulator supports OSC passthrough
 //   - Requires tmux to passthrough OSC sequences (allow-passthrough on)
 //   - Synchronous execution with error handling for /dev/tty access failures
-//   - Rate limiting uses global audioMutex and lastAudioPlay timestamp
-func (d *AlertDaemon) playAlertSound() {
+//   - Rate limiting uses global audioMutex and lastAudioPlay timestamp; rate-limited
+//     alerts skip the pre/post commands too
+//   - Pre/post command failures are broadcast as audio errors; the sound still plays
+//   - Alert command failures are broadcast as audio errors like /dev/tty failures
+func (d *AlertDaemon) playAlertSound(eventType string) {
 	// Skip sound during E2E tests
 	if os.Getenv("CLAUDE_E2E_TEST") != "" {
 		return
 	}
+	if d.alertAudio.disabled {
+		return
+	}
 
 	// Rate limit: Only allow one sound every 500ms
 	audioMutex.Lock()
 	defer audioMutex.Unlock()
 
-	now := time.Now()
+	now := d.now()
 	if now.Sub(lastAudioPlay) < 500*time.Millisecond {
 		debug.Log("AUDIO_SKIPPED reason=rate_limit since_last=%v", now.Sub(lastAudioPlay))
 		return
 	}
 	lastAudioPlay = now
 
+	hook := d.alertHooks[eventType]
+	d.runAlertHook("pre", hook.pre, eventType)
+	if d.alertAudio.command != "" {
+		d.runAlertHook("play", d.alertAudio.command, eventType)
+	} else {
+		d.writeAlertNotification()
+	}
+	d.runAlertHook("post", hook.post, eventType)
+}
+
+// writeAlertNotification writes the notification sequence to the alert terminal,
+// broadcasting an audio error on failure.
+func (d *AlertDaemon) writeAlertNotification() {
 	// Play alert sound using multiple notification methods for broad compatibility
 	pid := os.Getpid()
 	debug.Log("AUDIO_PLAYING pid=%d method=osc777+osc9+bel", pid)
@@ -159,14 +209,7 @@ func (d *AlertDaemon) playAlertSound() {
 	// Terminals pick their preferred method and ignore the rest
 	notificationSequence := "\033]777;notify;tmux-tui;Alert\a\033]9;tmux-tui alert\a\a"
 
-	// Use injected writer for testing, otherwise use /dev/tty
-	var f io.WriteCloser
-	var openErr error
-	if ttyWriter != nil {
-		f, openErr = ttyWriter()
-	} else {
-		f, openErr = os.OpenFile("/dev/tty", os.O_WRONLY, 0)
-	}
+	f, openErr := openAlertTTY()
 
 	if openErr == nil {
 		if _, writeErr := f.Write([]byte(notificationSequence)); writeErr != nil {
@@ -187,7 +230,15 @@ func (d *AlertDaemon) playAlertSound() {
 		debug.Log("AUDIO_FAILED pid=%d error=%v", pid, openErr)
 		d.broadcastAudioError(fmt.Errorf("failed to open /dev/tty: %w", openErr))
 	}
+}
 
+// openAlertTTY opens the terminal alert sounds are written to: the injected
+// ttyWriter in tests, otherwise /dev/tty.
+func openAlertTTY() (io.WriteCloser, error) {
+	if ttyWriter != nil {
+		return ttyWriter()
+	}
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
 }
 
 // broadcastAudioError broadcasts an audio playback error to all connected clients.
@@ -283,6 +334,9 @@ func (d *AlertDaemon) broadcastAudioError(audioErr error) {
 //   Use RLock when only reading to allow concurrent access.
 //   These locks are held only during map copying, never during I/O.
 //
+// LEAF: activePaneMu
+//   Guards only activePaneID. Never held while acquiring another lock or doing I/O.
+//
 // EXAMPLES:
 //   ✓ handleAlertChange(): alertsMu.Lock → broadcast() → clientsMu.RLock → encoderMu
 //   ✓ handleBlockBranch(): blockedMu.Lock → broadcast() → clientsMu.RLock → encoderMu
@@ -300,20 +354,39 @@ type AlertDaemon struct {
 	// Now abstracted behind IdleStateDetector interface with two implementations:
 	// - HookDetector (deprecated): Uses AlertWatcher for file-based notifications (TMUX_TUI_DETECTOR=hook)
 	// - TitleDetector (default): Polls pane titles directly, no AlertWatcher involved
-	paneFocusWatcher *watcher.PaneFocusWatcher
-	alerts           map[string]string // Current alert state: paneID -> eventType
-	previousState    map[string]string // Previous state for bell firing logic
-	alertsMu         sync.RWMutex
-	blockedBranches  map[string]string // Blocked branch state: branch -> blockedByBranch
-	blockedMu        sync.RWMutex
-	clients          map[string]*clientConnection
-	clientsMu        sync.RWMutex
-	listener         net.Listener
-	done             chan struct{}
-	socketPath       string
-	blockedPath      string                 // Path to persist blocked state JSON
-	recentEvents     map[eventKey]time.Time // Event deduplication: paneID+eventType+created -> last event time
-	eventsMu         sync.Mutex
+	paneFocusWatcher  *watcher.PaneFocusWatcher
+	alerts            map[string]string // Current alert state: paneID -> eventType
+	previousState     map[string]string // Previous state for bell firing logic
+	alertsMu          sync.RWMutex
+	alertTimes        map[string]time.Time // When each current alert started, under alertsMu (absent for alerts loaded at startup)
+	blockedBranches   BlockMap             // Blocked branch state: branch -> blocking branches
+	blockExpiries     BlockExpiries        // When expiring blocks end, under blockedMu (see watchBlockExpiry)
+	blockedMu         sync.RWMutex
+	saveMu            sync.Mutex
+	activePaneID      string // Last focused pane, sent in full_state so new clients can highlight it
+	activePaneMu      sync.RWMutex
+	clients           map[string]*clientConnection
+	clientsMu         sync.RWMutex
+	conns             sync.Map // Every open client net.Conn (key), reachable by Stop without clientsMu
+	listener          net.Listener
+	done              chan struct{}
+	socketPath        string
+	alertDir          string                 // Watched for pane focus (and hook alert) files
+	blockedPath       string                 // Path to persist blocked state JSON
+	protectedBranches []string               // Branch patterns that can never be blocked (see ProtectedBranchesEnv)
+	severities        watcher.SeverityMap    // Severity sent with each alert, by event type (see watcher.AlertSeverityEnv)
+	alertHooks        map[string]alertHook   // Commands run around the alert sound, by event type (see AlertPreCommandEnv)
+	alertAudio        alertAudio             // Alert command or disabled audio (see AlertCommandEnv)
+	recentEvents      map[eventKey]time.Time // Event deduplication: paneID+eventType+created -> last event time
+	eventsMu          sync.Mutex
+	maxMessageSize    int64 // Per-message decode limit (0 = DefaultMaxMessageSize)
+
+	// MaxClients caps concurrently connected clients; connections past it get a
+	// rejected message and are closed (0 = DefaultMaxClients, see TMUX_TUI_MAX_CLIENTS)
+	MaxClients int
+
+	shutdownDrainTimeout time.Duration // Grace period for in-flight sends in Stop (0 = DefaultShutdownDrainTimeout)
+	clock                Clock         // Time source for deduplication and the audio rate limit (nil = system clock)
 
 	// Health monitoring (accessed atomically)
 	broadcastFailures      atomic.Int64  // Total broadcast failures since startup
@@ -324,7 +397,11 @@ type AlertDaemon struct {
 	lastCloseError         atomic.Value  // Most recent connection close error (string)
 	audioBroadcastFailures atomic.Int64  // Total audio broadcast failures since startup
 	lastAudioBroadcastErr  atomic.Value  // Most recent audio broadcast error (string)
+	resyncFailures         atomic.Int64  // Total resync_request answers that failed to send
+	lastBroadcastNanos     atomic.Int64  // Duration of the most recent broadcast send loop
+	avgBroadcastNanos      atomic.Int64  // Rolling average broadcast duration (see recordBroadcastDuration)
 	seqCounter             atomic.Uint64 // Global sequence number for message ordering
+	startTime              time.Time     // Set by NewAlertDaemon, reported as uptime in health status
 
 	// Tree collection (for centralizing tmux queries)
 	//
@@ -374,31 +451,49 @@ func (d *AlertDaemon) copyAlerts() map[string]string {
 	return copy
 }
 
+// copyAlertTimes returns a copy of the alert start times with read lock protection
+func (d *AlertDaemon) copyAlertTimes() map[string]time.Time {
+	d.alertsMu.RLock()
+	defer d.alertsMu.RUnlock()
+	return copyTimeMap(d.alertTimes)
+}
+
 // TODO(#328): Consider extracting map copy pattern into generic helper function
 // copyBlockedBranches returns a copy of the blockedBranches map with read lock protection
-func (d *AlertDaemon) copyBlockedBranches() map[string]string {
+func (d *AlertDaemon) copyBlockedBranches() BlockMap {
 	d.blockedMu.RLock()
 	defer d.blockedMu.RUnlock()
+	return d.blockedBranches.Clone()
+}
 
-	copy := make(map[string]string, len(d.blockedBranches))
-	for k, v := range d.blockedBranches {
-		copy[k] = v
-	}
-	return copy
+// copyBlockExpiries returns a copy of the block expiries with read lock protection
+func (d *AlertDaemon) copyBlockExpiries() BlockExpiries {
+	d.blockedMu.RLock()
+	defer d.blockedMu.RUnlock()
+	return d.blockExpiries.Clone()
 }
 
-// loadBlockedBranches loads the blocked branches state from JSON file
-func loadBlockedBranches(path string) (map[string]string, error) {
+// getActivePaneID returns the last focused pane with read lock protection
+func (d *AlertDaemon) getActivePaneID() string {
+	d.activePaneMu.RLock()
+	defer d.activePaneMu.RUnlock()
+	return d.activePaneID
+}
+
+// loadBlockedBranches loads the blocked branches state from JSON file.
+// Files written before multiple blockers were supported (branch -> single blocker)
+// are accepted; see BlockMap.
+func loadBlockedBranches(path string) (BlockMap, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// No file yet - return empty map
-			return make(map[string]string), nil
+			return make(BlockMap), nil
 		}
 		return nil, fmt.Errorf("failed to read blocked branches file: %w", err)
 	}
 
-	var blockedBranches map[string]string
+	var blockedBranches BlockMap
 	if err := json.Unmarshal(data, &blockedBranches); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal blocked branches: %w", err)
 	}
@@ -406,9 +501,18 @@ func loadBlockedBranches(path string) (map[string]string, error) {
 	return blockedBranches, nil
 }
 
-// saveBlockedBranches saves the blocked branches state to JSON file
+// saveBlockedBranches saves the blocked branches state to JSON file, and the block
+// expiries next to it (see blockExpiriesPath; removed when no block expires)
 func (d *AlertDaemon) saveBlockedBranches() error {
-	blockedCopy := d.copyBlockedBranches()
+	// Serialize saves and snapshot under saveMu, so concurrent saves can't interleave
+	// writes and the last save to finish always writes the newest state
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+	d.blockedMu.RLock()
+	blockedCopy := d.blockedBranches.Clone()
+	expiriesCopy := d.blockExpiries.Clone()
+	d.blockedMu.RUnlock()
+	expiriesCopy.prune(blockedCopy)
 
 	data, err := json.MarshalIndent(blockedCopy, "", "  ")
 	if err != nil {
@@ -419,7 +523,22 @@ func (d *AlertDaemon) saveBlockedBranches() error {
 		return fmt.Errorf("failed to write blocked branches file: %w", err)
 	}
 
-	debug.Log("DAEMON_BLOCKED_SAVED path=%s count=%d", d.blockedPath, len(blockedCopy))
+	expiriesPath := blockExpiriesPath(d.blockedPath)
+	if len(expiriesCopy) == 0 {
+		if err := os.Remove(expiriesPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove block expiries file: %w", err)
+		}
+	} else {
+		data, err := json.MarshalIndent(expiriesCopy, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal block expiries: %w", err)
+		}
+		if err := os.WriteFile(expiriesPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write block expiries file: %w", err)
+		}
+	}
+
+	debug.Log("DAEMON_BLOCKED_SAVED path=%s count=%d expiring=%d", d.blockedPath, len(blockedCopy), len(expiriesCopy))
 	return nil
 }
 
@@ -454,7 +573,8 @@ func loadExistingAlertsWithRetry(alertDir string, maxRetries int) (map[string]st
 	return nil, fmt.Errorf("failed to load existing alerts after %d attempts: %w", maxRetries, lastErr)
 }
 
-// NewAlertDaemon creates a new AlertDaemon instance.
+// NewAlertDaemon creates a new AlertDaemon instance. The caller holds the namespace's
+// daemon lock (see AcquireLockFile).
 func NewAlertDaemon() (*AlertDaemon, error) {
 	// Use namespace to determine alert directory and socket path
 	alertDir := namespace.AlertDir()
@@ -465,6 +585,14 @@ func NewAlertDaemon() (*AlertDaemon, error) {
 		return nil, fmt.Errorf("failed to create namespace directory: %w", err)
 	}
 
+	// Alerts, focus and the socket left by the daemon of a tmux server that is gone must
+	// not be restored.
+	if cleaned, err := cleanStaleNamespace(alertDir, tmuxServerGone); err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: Failed to clean up stale namespace: %v\n", err)
+	} else if cleaned {
+		debug.Log("DAEMON_INIT cleaned stale namespace dir=%s", alertDir)
+	}
+
 	// Determine which detector to use based on environment variable
 	// Default is "title" (poll pane titles), backward compat is "hook" (alert files)
 	detectorType := os.Getenv("TMUX_TUI_DETECTOR")
@@ -529,20 +657,50 @@ func NewAlertDaemon() (*AlertDaemon, error) {
 		return nil, fmt.Errorf("failed to load blocked branches: %w", err)
 	}
 
-	debug.Log("DAEMON_INIT alert_dir=%s socket=%s existing_alerts=%d blocked_branches=%d",
-		alertDir, socketPath, len(existingAlerts), len(blockedBranches))
+	// A corrupt expiries file only loses the expiries: those blocks become permanent
+	// until unblocked, which is safer than refusing to start
+	blockExpiries, err := loadBlockExpiries(blockExpiriesPath(blockedPath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: %v - blocks will not expire\n", err)
+		blockExpiries = make(BlockExpiries)
+	}
+	blockExpiries.prune(blockedBranches)
+
+	// Restore the last focused pane so clients connecting before the next focus
+	// event (e.g. after a daemon restart) still know which pane is active
+	activePaneID, err := watcher.ReadPaneFocus(alertDir)
+	if err != nil {
+		debug.Log("DAEMON_INIT_NO_PANE_FOCUS error=%v", err)
+		activePaneID = ""
+	}
+
+	debug.Log("DAEMON_INIT alert_dir=%s socket=%s existing_alerts=%d blocked_branches=%d active_pane=%s",
+		alertDir, socketPath, len(existingAlerts), len(blockedBranches), activePaneID)
 
 	daemon := &AlertDaemon{
-		detector:         idleDetector, // May be nil for title detector (requires working collector, initialized at lines 603-612 after collector creation)
-		paneFocusWatcher: paneFocusWatcher,
-		alerts:           existingAlerts,
-		previousState:    make(map[string]string),
-		blockedBranches:  blockedBranches,
-		clients:          make(map[string]*clientConnection),
-		done:             make(chan struct{}),
-		socketPath:       socketPath,
-		blockedPath:      blockedPath,
-		recentEvents:     make(map[eventKey]time.Time),
+		detector:          idleDetector, // May be nil for title detector (requires working collector, initialized at lines 603-612 after collector creation)
+		paneFocusWatcher:  paneFocusWatcher,
+		alerts:            existingAlerts,
+		previousState:     make(map[string]string),
+		blockedBranches:   blockedBranches,
+		blockExpiries:     blockExpiries,
+		activePaneID:      activePaneID,
+		clients:           make(map[string]*clientConnection),
+		done:              make(chan struct{}),
+		socketPath:        socketPath,
+		alertDir:          alertDir,
+		blockedPath:       blockedPath,
+		protectedBranches: ProtectedBranchesFromEnv(),
+		severities:        watcher.SeveritiesFromEnv(),
+		alertHooks:        alertHooksFromEnv(),
+		alertAudio:        alertAudioFromEnv(),
+		recentEvents:      make(map[eventKey]time.Time),
+		maxMessageSize:    maxMessageSizeFromEnv(),
+		MaxClients:        maxClientsFromEnv(),
+
+		shutdownDrainTimeout: shutdownDrainTimeoutFromEnv(),
+		clock:                realClock{},
+		startTime:            time.Now(),
 	}
 
 	// Initialize atomic.Value fields
@@ -649,6 +807,9 @@ func (d *AlertDaemon) Start() error {
 		go d.watchTree()
 	}
 
+	// Remove blocks whose TTL has passed
+	go d.watchBlockExpiry()
+
 	// Accept client connections
 	go d.acceptClients()
 
@@ -796,6 +957,15 @@ func (d *AlertDaemon) collectAndBroadcastTree() {
 
 	// Collect tree (can be slow - up to several seconds with many panes/repos)
 	tree, err := d.collector.GetTree()
+	var partial *tmux.PartialTreeError
+	var warning string
+	if errors.As(err, &partial) {
+		// Some panes couldn't be read - broadcast the rest, with a warning, instead of keeping stale data
+		warning = err.Error()
+		debug.Log("DAEMON_TREE_PARTIAL failed=%d total=%d error=%v", partial.Failed, partial.Total, err)
+		fmt.Fprintf(os.Stderr, "WARNING: Tree is incomplete: %v\n", err)
+		err = nil
+	}
 	if err != nil {
 		// Collection failed - broadcast error to clients
 		d.treeErrors.Add(1)
@@ -821,7 +991,13 @@ func (d *AlertDaemon) collectAndBroadcastTree() {
 	debug.Log("DAEMON_TREE_UPDATE repos=%d panes=%d", len(tree.Repos()), tree.TotalPanes())
 
 	// Broadcast tree_update to all clients
-	msg, msgErr := NewTreeUpdateMessage(d.seqCounter.Add(1), tree)
+	var msg *TreeUpdateMessageV2
+	var msgErr error
+	if warning != "" {
+		msg, msgErr = NewPartialTreeUpdateMessage(d.seqCounter.Add(1), tree, warning)
+	} else {
+		msg, msgErr = NewTreeUpdateMessage(d.seqCounter.Add(1), tree)
+	}
 	if msgErr != nil {
 		failures := d.consecutiveTreeConstructFailures.Add(1)
 		errMsg := fmt.Sprintf("tree_update construction failed: %v (repos=%d, failures=%d)", msgErr, len(tree.Repos()), failures)
@@ -880,7 +1056,7 @@ func (d *AlertDaemon) isDuplicateEvent(paneID, eventType string, created bool) b
 	defer d.eventsMu.Unlock()
 
 	key := eventKey{paneID: paneID, eventType: eventType, created: created}
-	now := time.Now()
+	now := d.now()
 
 	// Check if this event occurred recently (deduplication window)
 	if lastTime, exists := d.recentEvents[key]; exists {
@@ -1048,6 +1224,10 @@ func (d *AlertDaemon) updateTreeForPaneFocus(newActivePaneID string) {
 func (d *AlertDaemon) handlePaneFocusEvent(event watcher.PaneFocusEvent) {
 	debug.Log("DAEMON_PANE_FOCUS_EVENT paneID=%s", event.PaneID)
 
+	d.activePaneMu.Lock()
+	d.activePaneID = event.PaneID
+	d.activePaneMu.Unlock()
+
 	// Update tree immediately
 	d.updateTreeForPaneFocus(event.PaneID)
 
@@ -1095,6 +1275,8 @@ func (d *AlertDaemon) handleStateChangeEvent(event detector.StateEvent) {
 	d.alertsMu.Lock()
 
 	var isNewAlert bool
+	var alertedAt time.Time
+	var severity string
 	previousState, hadPreviousState := d.previousState[event.PaneID()]
 
 	// Update previous state
@@ -1103,25 +1285,35 @@ func (d *AlertDaemon) handleStateChangeEvent(event detector.StateEvent) {
 	if event.State() == detector.StateWorking {
 		// Working state means no alert - remove from alerts map
 		delete(d.alerts, event.PaneID())
+		delete(d.alertTimes, event.PaneID())
 		debug.Log("DAEMON_ALERT_CLEARED paneID=%s remaining=%d", event.PaneID(), len(d.alerts))
 	} else {
 		// Idle state is an alert state
 		// Check if this is a new alert (transition TO alert state)
 		isNewAlert = !hadPreviousState || previousState == watcher.EventTypeWorking
 		d.alerts[event.PaneID()] = eventType
+		// Keep the original start while the pane stays in an alert state
+		if _, known := d.alertTimes[event.PaneID()]; isNewAlert || !known {
+			if d.alertTimes == nil {
+				d.alertTimes = make(map[string]time.Time)
+			}
+			d.alertTimes[event.PaneID()] = d.now()
+		}
+		alertedAt = d.alertTimes[event.PaneID()]
+		severity = string(d.severities.For(eventType))
 		debug.Log("DAEMON_ALERT_STORED paneID=%s eventType=%s total=%d isNew=%v",
 			event.PaneID(), eventType, len(d.alerts), isNewAlert)
 
 		// Play sound only when transitioning to alert state
 		if isNewAlert {
-			d.playAlertSound()
+			d.playAlertSound(eventType)
 		}
 	}
 
 	d.alertsMu.Unlock()
 
 	// Create type-safe v2 message
-	msg, err := NewAlertChangeMessage(d.seqCounter.Add(1), event.PaneID(), eventType, created)
+	msg, err := NewAlertChangeMessage(d.seqCounter.Add(1), event.PaneID(), eventType, created, alertedAt, severity)
 	if err != nil {
 		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=alert_change error=%v", err)
 		fmt.Fprintf(os.Stderr, "ERROR: Failed to construct alert change message: %v\n", err)
@@ -1151,7 +1343,9 @@ func (d *AlertDaemon) acceptClients() {
 
 // handleClient manages a client connection.
 func (d *AlertDaemon) handleClient(conn net.Conn) {
-	decoder := json.NewDecoder(conn)
+	d.conns.Store(conn, struct{}{})
+	defer d.conns.Delete(conn)
+	decoder := newLimitedDecoder(conn, d.maxMessageSize)
 
 	var clientID string
 
@@ -1159,6 +1353,9 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 	var helloMsg Message
 	if err := decoder.Decode(&helloMsg); err != nil {
 		debug.Log("DAEMON_CLIENT_HELLO_ERROR error=%v", err)
+		if errors.Is(err, ErrMessageTooLarge) {
+			fmt.Fprintf(os.Stderr, "WARNING: Rejected oversized hello message: %v - disconnecting\n", err)
+		}
 		conn.Close()
 		return
 	}
@@ -1170,25 +1367,34 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 	}
 
 	clientID = helloMsg.ClientID
-	debug.Log("DAEMON_CLIENT_CONNECTED id=%s", clientID)
+
+	// A client of another major version may send messages this daemon can't parse,
+	// which it would otherwise ignore and leave the client waiting
+	if err := CheckProtocolVersion(helloMsg.ProtocolVersion); err != nil {
+		d.rejectProtocolMismatch(conn, clientID, err)
+		return
+	}
+	debug.Log("DAEMON_CLIENT_CONNECTED id=%s protocol=%s", clientID, helloMsg.ProtocolVersion)
 
 	// Create client connection wrapper
 	client := &clientConnection{
-		conn:    conn,
-		encoder: json.NewEncoder(conn),
+		conn:        conn,
+		encoder:     json.NewEncoder(conn),
+		connectedAt: d.now(),
 	}
 
-	// Register client
-	d.clientsMu.Lock()
-	d.clients[clientID] = client
-	d.clientsMu.Unlock()
+	// Register client, or refuse it with a reason the user can act on
+	if reason := d.registerClient(clientID, client); reason != "" {
+		d.rejectClient(conn, clientID, reason)
+		return
+	}
 
 	// Send full state (alerts + blocked branches)
 	alertsCopy := d.copyAlerts()
 	blockedCopy := d.copyBlockedBranches()
 
 	// Create type-safe v2 message
-	fullStateMsg, err := NewFullStateMessage(d.seqCounter.Add(1), alertsCopy, blockedCopy)
+	fullStateMsg, err := NewFullStateMessage(d.seqCounter.Add(1), alertsCopy, d.copyAlertTimes(), blockedCopy, d.copyBlockExpiries(), d.getActivePaneID(), d.alertDir)
 	if err != nil {
 		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=full_state error=%v", err)
 		d.removeClient(clientID)
@@ -1202,8 +1408,10 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 		conn.Close()
 		return
 	}
+	client.lastFullStateHash.Store(fullStateHash(fullStateMsg.ToWireFormat()))
 
-	debug.Log("DAEMON_SENT_STATE client=%s alerts=%d blocked=%d", clientID, len(alertsCopy), len(blockedCopy))
+	debug.Log("DAEMON_SENT_STATE client=%s alerts=%d blocked=%d active_pane=%s",
+		clientID, len(alertsCopy), len(blockedCopy), fullStateMsg.ActivePaneID())
 
 	// If tree collector failed initialization, notify client why tree updates won't happen
 	if d.collector == nil {
@@ -1219,13 +1427,18 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 		var msg Message
 		if err := decoder.Decode(&msg); err != nil {
 			debug.Log("DAEMON_CLIENT_DISCONNECT client=%s error=%v", clientID, err)
+			if errors.Is(err, ErrMessageTooLarge) {
+				fmt.Fprintf(os.Stderr, "WARNING: Client %s sent oversized message: %v - disconnecting\n", clientID, err)
+			}
 			d.removeClient(clientID)
 			conn.Close()
 			return
 		}
+		client.lastSeen.Store(d.now().UnixNano())
 
 		switch msg.Type {
 		case MsgTypePing:
+			client.lastPing.Store(d.now().UnixNano())
 			// Create type-safe v2 pong message
 			pongMsg, err := NewPongMessage(d.seqCounter.Add(1))
 			if err != nil {
@@ -1270,10 +1483,10 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 			}
 
 			// Broadcast to all clients to show picker for this pane
-			debug.Log("DAEMON_SHOW_PICKER paneID=%s", msg.PaneID)
+			debug.Log("DAEMON_SHOW_PICKER paneID=%s ttl=%v", msg.PaneID, msg.TTL)
 
-			// Create type-safe v2 message
-			pickerMsg, err := NewShowBlockPickerMessage(d.seqCounter.Add(1), msg.PaneID)
+			// Create type-safe v2 message; the TTL rides along to the picker's block_branch
+			pickerMsg, err := NewShowBlockPickerMessage(d.seqCounter.Add(1), msg.PaneID, msg.TTL)
 			if err != nil {
 				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=show_block_picker error=%v", err)
 				continue
@@ -1310,28 +1523,70 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 				continue
 			}
 
-			// Block a branch with another branch
-			debug.Log("DAEMON_BLOCK_BRANCH branch=%s blockedBy=%s", msg.Branch, msg.BlockedBranch)
+			// Protected branches (e.g. main) are never blocked - reject without touching state
+			if d.isProtected(msg.Branch) {
+				reason := fmt.Sprintf("protected branch: '%s' cannot be blocked", msg.Branch)
+				rejectMsg, err := NewBlockRejectedMessage(d.seqCounter.Add(1), msg.Branch, msg.BlockedBranch, reason)
+				if err != nil {
+					debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_rejected error=%v", err)
+					continue
+				}
+				if err := client.sendMessage(rejectMsg.ToWireFormat()); err != nil {
+					debug.Log("DAEMON_BLOCK_REJECTED_SEND_ERROR client=%s error=%v", clientID, err)
+				}
+				continue
+			}
 
-			// Capture previous state before making changes
-			d.blockedMu.RLock()
-			previousBlockedBy, wasBlocked := d.blockedBranches[msg.Branch]
-			d.blockedMu.RUnlock()
+			// Add the blocker to the branch (a branch can be blocked by several branches)
+			debug.Log("DAEMON_BLOCK_BRANCH branch=%s blockedBy=%s ttl=%v", msg.Branch, msg.BlockedBranch, msg.TTL)
 
-			// Update in-memory state
+			// Capture previous state and update in-memory state. Blocker slices are
+			// never modified in place, so previousBlockers stays valid for a revert.
+			// Re-blocking replaces the blocker's expiry: without a TTL it becomes permanent.
+			var expiresAt time.Time
+			if msg.TTL > 0 {
+				expiresAt = d.now().Add(msg.TTL)
+			}
 			d.blockedMu.Lock()
-			d.blockedBranches[msg.Branch] = msg.BlockedBranch
+			// A block that closes a cycle (a blocked by b, b blocked by a) can never be
+			// resolved - reject it without touching state. Checked under the lock so a
+			// concurrent block can't complete the cycle in between.
+			if cycle := d.blockedBranches.cyclePath(msg.Branch, msg.BlockedBranch); cycle != nil {
+				d.blockedMu.Unlock()
+				debug.Log("DAEMON_BLOCK_CYCLE branch=%s blockedBy=%s cycle=%v", msg.Branch, msg.BlockedBranch, cycle)
+				rejectMsg, err := NewBlockCycleRejectedMessage(d.seqCounter.Add(1), msg.Branch, msg.BlockedBranch, cycle)
+				if err != nil {
+					debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_rejected error=%v", err)
+					continue
+				}
+				if err := client.sendMessage(rejectMsg.ToWireFormat()); err != nil {
+					debug.Log("DAEMON_BLOCK_REJECTED_SEND_ERROR client=%s error=%v", clientID, err)
+				}
+				continue
+			}
+			previousBlockers := d.blockedBranches[msg.Branch]
+			previousExpiries := d.blockExpiries.forBranch(msg.Branch)
+			blockers := previousBlockers
+			if !slices.Contains(blockers, msg.BlockedBranch) {
+				blockers = append(slices.Clone(previousBlockers), msg.BlockedBranch)
+			}
+			d.blockedBranches[msg.Branch] = blockers
+			if d.blockExpiries == nil {
+				d.blockExpiries = make(BlockExpiries)
+			}
+			d.blockExpiries.set(msg.Branch, msg.BlockedBranch, expiresAt)
+			expiries := d.blockExpiries.forBranch(msg.Branch)
 			d.blockedMu.Unlock()
 
 			// Save to disk - revert if persistence fails
 			if err := d.saveBlockedBranches(); err != nil {
 				d.handlePersistenceError(err)
-				d.revertBlockedBranchChange(msg.Branch, wasBlocked, previousBlockedBy)
+				d.revertBlockedBranchChange(msg.Branch, previousBlockers, previousExpiries)
 				continue // Skip success broadcast
 			}
 
 			// Only broadcast success if persistence succeeded
-			blockMsg, err := NewBlockChangeMessage(d.seqCounter.Add(1), msg.Branch, msg.BlockedBranch, true)
+			blockMsg, err := NewBlockChangeMessage(d.seqCounter.Add(1), msg.Branch, blockers, expiries)
 			if err != nil {
 				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_change error=%v", err)
 				continue
@@ -1368,43 +1623,61 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 				continue
 			}
 
-			// Unblock a branch
-			debug.Log("DAEMON_UNBLOCK_BRANCH branch=%s", msg.Branch)
-
-			// Capture previous state before making changes
-			d.blockedMu.RLock()
-			previousBlockedBy, wasBlocked := d.blockedBranches[msg.Branch]
-			d.blockedMu.RUnlock()
+			// Unblock a branch: remove one blocker if BlockedBranch is set, otherwise all
+			debug.Log("DAEMON_UNBLOCK_BRANCH branch=%s blockedBy=%s", msg.Branch, msg.BlockedBranch)
 
-			// Update in-memory state
+			// Capture previous state and update in-memory state
 			d.blockedMu.Lock()
-			delete(d.blockedBranches, msg.Branch)
+			previousBlockers := d.blockedBranches[msg.Branch]
+			previousExpiries := d.blockExpiries.forBranch(msg.Branch)
+			var blockers []string
+			if msg.BlockedBranch != "" {
+				for _, blocker := range previousBlockers {
+					if blocker != msg.BlockedBranch {
+						blockers = append(blockers, blocker)
+					}
+				}
+			}
+			if len(blockers) > 0 {
+				d.blockedBranches[msg.Branch] = blockers
+			} else {
+				delete(d.blockedBranches, msg.Branch)
+			}
+			d.blockExpiries.prune(d.blockedBranches)
+			expiries := d.blockExpiries.forBranch(msg.Branch)
 			d.blockedMu.Unlock()
 
 			// Save to disk - revert if persistence fails
 			if err := d.saveBlockedBranches(); err != nil {
 				d.handlePersistenceError(err)
-				d.revertBlockedBranchChange(msg.Branch, wasBlocked, previousBlockedBy)
+				d.revertBlockedBranchChange(msg.Branch, previousBlockers, previousExpiries)
 				continue // Skip success broadcast
 			}
 
 			// Only broadcast success if persistence succeeded
-			unblockMsg, err := NewBlockChangeMessage(d.seqCounter.Add(1), msg.Branch, "", false)
+			unblockMsg, err := NewBlockChangeMessage(d.seqCounter.Add(1), msg.Branch, blockers, expiries)
 			if err != nil {
 				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=block_change error=%v", err)
 				continue
 			}
 			d.broadcast(unblockMsg.ToWireFormat())
 
+		case MsgTypeUnblockAll:
+			// Clear every blocked branch (tmux-tui-block --all)
+			debug.Log("DAEMON_UNBLOCK_ALL_REQUEST client=%s", clientID)
+			if err := d.unblockAll(); err != nil {
+				debug.Log("DAEMON_UNBLOCK_ALL_FAILED client=%s error=%v", clientID, err)
+			}
+
 		case MsgTypeQueryBlockedState:
 			// Query blocked state for a branch
 			debug.Log("DAEMON_QUERY_BLOCKED_STATE branch=%s", msg.Branch)
 			d.blockedMu.RLock()
-			blockedBy, isBlocked := d.blockedBranches[msg.Branch]
+			blockers := d.blockedBranches[msg.Branch]
 			d.blockedMu.RUnlock()
 
 			// Send response back to requesting client
-			response, err := NewBlockedStateResponseMessage(d.seqCounter.Add(1), msg.Branch, isBlocked, blockedBy)
+			response, err := NewBlockedStateResponseMessage(d.seqCounter.Add(1), msg.Branch, blockers)
 			if err != nil {
 				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=blocked_state_response error=%v", err)
 				continue
@@ -1412,8 +1685,98 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 			if err := client.sendMessage(response.ToWireFormat()); err != nil {
 				debug.Log("DAEMON_QUERY_RESPONSE_ERROR client=%s error=%v", clientID, err)
 			} else {
-				debug.Log("DAEMON_QUERY_RESPONSE branch=%s isBlocked=%v blockedBy=%s",
-					msg.Branch, isBlocked, blockedBy)
+				debug.Log("DAEMON_QUERY_RESPONSE branch=%s isBlocked=%v blockedBy=%v",
+					msg.Branch, len(blockers) > 0, blockers)
+			}
+
+		case MsgTypeQueryAllBlocked:
+			// Query every blocked branch at once (TUI startup)
+			blocked := d.copyBlockedBranches()
+			debug.Log("DAEMON_QUERY_ALL_BLOCKED client=%s count=%d", clientID, len(blocked))
+			response, err := NewAllBlockedResponseMessage(d.seqCounter.Add(1), blocked)
+			if err != nil {
+				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=all_blocked_response error=%v", err)
+				continue
+			}
+			if err := client.sendMessage(response.ToWireFormat()); err != nil {
+				debug.Log("DAEMON_QUERY_RESPONSE_ERROR client=%s error=%v", clientID, err)
+			}
+
+		case MsgTypeImportBlocks:
+			// Replace or merge blocked state (tmux-tui-daemon import-blocks)
+			var blocked BlockMap
+			var errText string
+			importMsg, err := FromWireFormat(msg)
+			if err != nil {
+				errText = err.Error()
+			} else {
+				req := importMsg.(*ImportBlocksMessageV2)
+				debug.Log("DAEMON_IMPORT_BLOCKS_REQUEST client=%s mode=%s count=%d", clientID, req.Mode(), len(req.BlockedBranches()))
+				if blocked, err = d.importBlocks(req.BlockedBranches(), req.Mode()); err != nil {
+					errText = err.Error()
+				}
+			}
+			if errText != "" {
+				debug.Log("DAEMON_IMPORT_BLOCKS_REJECTED client=%s error=%v", clientID, errText)
+			}
+
+			resultMsg, err := NewImportBlocksResultMessage(d.seqCounter.Add(1), blocked, errText)
+			if err != nil {
+				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=import_blocks_result error=%v", err)
+				continue
+			}
+			if err := client.sendMessage(resultMsg.ToWireFormat()); err != nil {
+				debug.Log("DAEMON_IMPORT_BLOCKS_SEND_ERROR client=%s error=%v", clientID, err)
+			}
+
+		case MsgTypeRenameBranch:
+			// Move a branch's blocks after a git branch rename (tmux-tui-block --rename)
+			var errText string
+			renameMsg, err := FromWireFormat(msg)
+			if err != nil {
+				errText = err.Error()
+			} else {
+				req := renameMsg.(*RenameBranchMessageV2)
+				debug.Log("DAEMON_RENAME_BRANCH_REQUEST client=%s old=%s new=%s", clientID, req.OldBranch(), req.NewBranch())
+				if err := d.renameBranch(req.OldBranch(), req.NewBranch()); err != nil {
+					errText = err.Error()
+				}
+			}
+			if errText != "" {
+				debug.Log("DAEMON_RENAME_BRANCH_REJECTED client=%s error=%v", clientID, errText)
+			}
+
+			resultMsg, err := NewRenameBranchResultMessage(d.seqCounter.Add(1), msg.Branch, msg.NewBranch, errText)
+			if err != nil {
+				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=rename_branch_result error=%v", err)
+				continue
+			}
+			if err := client.sendMessage(resultMsg.ToWireFormat()); err != nil {
+				debug.Log("DAEMON_RENAME_BRANCH_SEND_ERROR client=%s error=%v", clientID, err)
+			}
+
+		case MsgTypeClientsQuery:
+			// Return every connected client (tmux-tui-daemon clients)
+			debug.Log("DAEMON_CLIENTS_QUERY client=%s", clientID)
+			clientsMsg, err := NewClientsResponseMessage(d.seqCounter.Add(1), d.clientInfos())
+			if err != nil {
+				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=clients_response error=%v", err)
+				continue
+			}
+			if err := client.sendMessage(clientsMsg.ToWireFormat()); err != nil {
+				debug.Log("DAEMON_CLIENTS_SEND_ERROR client=%s error=%v", clientID, err)
+			}
+
+		case MsgTypeListQuery:
+			// Return current alerts and blocked branches (tmux-tui-daemon list)
+			debug.Log("DAEMON_LIST_QUERY client=%s", clientID)
+			listMsg, err := NewListResponseMessage(d.seqCounter.Add(1), d.copyAlerts(), d.copyBlockedBranches())
+			if err != nil {
+				debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=list_response error=%v", err)
+				continue
+			}
+			if err := client.sendMessage(listMsg.ToWireFormat()); err != nil {
+				debug.Log("DAEMON_LIST_SEND_ERROR client=%s error=%v", clientID, err)
 			}
 
 		case MsgTypeHealthQuery:
@@ -1488,8 +1851,9 @@ func (d *AlertDaemon) handleClient(conn net.Conn) {
 
 		case MsgTypeResyncRequest:
 			// Client detected a gap in sequence numbers, send full state
-			debug.Log("DAEMON_RESYNC_REQUEST client=%s", clientID)
-			if err := d.sendFullState(client, clientID); err != nil {
+			debug.Log("DAEMON_RESYNC_REQUEST client=%s force=%v", clientID, msg.Force)
+			if err := d.sendFullState(client, clientID, msg.Force); err != nil {
+				d.resyncFailures.Add(1)
 				debug.Log("DAEMON_RESYNC_FAILED client=%s error=%v", clientID, err)
 			}
 
@@ -1572,7 +1936,24 @@ func (d *AlertDaemon) broadcast(msg Message) {
 	var failedClients []failedClient
 	var successfulClients []successfulClient
 
+	// Clients that receive a full_state have its content (see sendFullState). Any
+	// state message moves them off the state they had, so their hash is cleared before
+	// the send: a reload checking it must never see a hash the client has moved past.
+	var stateHash uint64
+	changesState := false
+	switch msg.Type {
+	case MsgTypeFullState:
+		stateHash = fullStateHash(msg)
+		changesState = true
+	case MsgTypeAlertChange, MsgTypeBlockChange, MsgTypePaneFocus:
+		changesState = true
+	}
+
+	sendStart := time.Now()
 	for clientID, client := range d.clients {
+		if changesState {
+			client.lastFullStateHash.Store(0)
+		}
 		if err := client.sendMessage(msg); err != nil {
 			failedClients = append(failedClients, failedClient{id: clientID, err: err})
 			debug.Log("DAEMON_BROADCAST_ERROR client=%s type=%s seq=%d error=%v",
@@ -1587,9 +1968,13 @@ func (d *AlertDaemon) broadcast(msg Message) {
 				id:     clientID,
 				client: client,
 			})
+			if stateHash != 0 {
+				client.lastFullStateHash.Store(stateHash)
+			}
 		}
 	}
 	d.clientsMu.RUnlock()
+	d.recordBroadcastDuration(time.Since(sendStart))
 
 	// Clean up failed clients - must be done after RUnlock to avoid deadlock
 	// This forces reconnection with full state resync
@@ -1735,6 +2120,13 @@ func (d *AlertDaemon) broadcastTree(msg Message) {
 //   - All blocked branches (branch name -> reason mapping)
 //   - Fresh sequence number from the global counter
 //
+// Deduplication:
+//   - Unless force is set, nothing is sent when the state matches the last full_state
+//     sent to this client. Gaps usually come from sequence numbers spent on other
+//     clients' messages, and every change since that full_state reached this client
+//     (clients that miss a broadcast are disconnected), so it already has this state.
+//   - A skipped send doesn't take a sequence number, so it can't cause a gap itself
+//
 // Error Handling:
 //   - Message construction errors: Logged to stderr and debug, returned to caller
 //   - Send errors: Logged to stderr and debug, returned to caller
@@ -1744,7 +2136,26 @@ func (d *AlertDaemon) broadcastTree(msg Message) {
 //   - Creates copies of alerts and blocked branches maps under locks
 //   - Sends the message without holding any locks (prevents blocking other operations)
 //   - Safe to call concurrently for different clients
-func (d *AlertDaemon) sendFullState(client *clientConnection, clientID string) error {
+func (d *AlertDaemon) sendFullState(client *clientConnection, clientID string, force bool) error {
+	alertsCopy := d.copyAlerts()
+	alertTimesCopy := d.copyAlertTimes()
+	blockedCopy := d.copyBlockedBranches()
+	expiriesCopy := d.copyBlockExpiries()
+	activePaneID := d.getActivePaneID()
+
+	// The sequence number is only taken once the send is known to happen
+	snapshot, err := NewFullStateMessage(0, alertsCopy, alertTimesCopy, blockedCopy, expiriesCopy, activePaneID, d.alertDir)
+	if err != nil {
+		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=full_state error=%v", err)
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to construct full state message for client %s: %v\n", clientID, err)
+		return fmt.Errorf("failed to construct full state message: %w", err)
+	}
+	hash := fullStateHash(snapshot.ToWireFormat())
+	if !force && hash != 0 && client.lastFullStateHash.Load() == hash {
+		debug.Log("DAEMON_RESYNC_SKIPPED client=%s reason=unchanged", clientID)
+		return nil
+	}
+
 	// If collector is nil, send tree_error to explain why tree is empty
 	if d.collector == nil {
 		if err := d.sendCollectorUnavailableError(client, clientID); err != nil {
@@ -1752,11 +2163,8 @@ func (d *AlertDaemon) sendFullState(client *clientConnection, clientID string) e
 		}
 	}
 
-	alertsCopy := d.copyAlerts()
-	blockedCopy := d.copyBlockedBranches()
-
 	// Create type-safe v2 message
-	fullStateMsg, err := NewFullStateMessage(d.seqCounter.Add(1), alertsCopy, blockedCopy)
+	fullStateMsg, err := NewFullStateMessage(d.seqCounter.Add(1), alertsCopy, alertTimesCopy, blockedCopy, expiriesCopy, activePaneID, d.alertDir)
 	if err != nil {
 		debug.Log("DAEMON_MSG_CONSTRUCT_ERROR type=full_state error=%v", err)
 		fmt.Fprintf(os.Stderr, "ERROR: Failed to construct full state message for client %s: %v\n", clientID, err)
@@ -1768,10 +2176,38 @@ func (d *AlertDaemon) sendFullState(client *clientConnection, clientID string) e
 		fmt.Fprintf(os.Stderr, "ERROR: Failed to send full state to client %s: %v\n", clientID, err)
 		return fmt.Errorf("resync failed: %w", err)
 	}
+	client.lastFullStateHash.Store(hash)
 	debug.Log("DAEMON_RESYNC_SENT client=%s alerts=%d blocked=%d", clientID, len(alertsCopy), len(blockedCopy))
 	return nil
 }
 
+// fullStateHash hashes a full_state's content, everything but its sequence number,
+// so resyncs can tell whether a client already has the current state. Returns 0 (never
+// matched) if the state can't be encoded.
+func fullStateHash(msg Message) uint64 {
+	msg.SeqNum = 0
+	data, err := json.Marshal(msg) // Map keys are sorted, so equal states encode equally
+	if err != nil {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// clientsHaveFullState reports whether every connected client was last sent a
+// full_state with this fullStateHash
+func (d *AlertDaemon) clientsHaveFullState(hash uint64) bool {
+	d.clientsMu.RLock()
+	defer d.clientsMu.RUnlock()
+	for _, client := range d.clients {
+		if hash == 0 || client.lastFullStateHash.Load() != hash {
+			return false
+		}
+	}
+	return true
+}
+
 // removeClient removes a client from the clients map.
 func (d *AlertDaemon) removeClient(clientID string) {
 	d.clientsMu.Lock()
@@ -1780,10 +2216,34 @@ func (d *AlertDaemon) removeClient(clientID string) {
 	debug.Log("DAEMON_CLIENT_REMOVED id=%s remaining=%d", clientID, len(d.clients))
 }
 
+// broadcastLatencyWeight is the weight of each new sample in the rolling average
+// broadcast duration (an exponentially weighted moving average over roughly the last
+// 1/broadcastLatencyWeight broadcasts).
+const broadcastLatencyWeight = 0.125
+
+// recordBroadcastDuration updates the last and rolling average broadcast durations.
+// Lock-free: concurrent broadcasts retry the compare-and-swap instead of contending
+// on clientsMu.
+func (d *AlertDaemon) recordBroadcastDuration(elapsed time.Duration) {
+	sample := int64(elapsed)
+	d.lastBroadcastNanos.Store(sample)
+	for {
+		avg := d.avgBroadcastNanos.Load()
+		next := sample // first sample seeds the average
+		if avg != 0 {
+			next = avg + int64(float64(sample-avg)*broadcastLatencyWeight)
+		}
+		if d.avgBroadcastNanos.CompareAndSwap(avg, next) {
+			return
+		}
+	}
+}
+
 // GetHealthStatus returns daemon health metrics for monitoring and diagnostics.
 //
 // Metrics Returned:
 //   - Broadcast failures: Total count of failed client message sends
+//   - Broadcast latency: Last and rolling average duration of the broadcast send loop
 //   - Watcher errors: Total count of alert/pane focus watcher errors
 //   - Connection close errors: Total count of failed connection closures
 //   - Audio failures: Total count of audio playback failures
@@ -1835,7 +2295,11 @@ func (d *AlertDaemon) GetHealthStatus() (HealthStatus, error) {
 		WithAudioMetrics(d.audioBroadcastFailures.Load(), lastAudioBroadcastErr).
 		WithTreeBroadcastMetrics(d.treeBroadcastErrors.Load(), lastTreeBroadcastErr).
 		WithTreeConstructMetrics(d.treeMsgConstructErrors.Load(), lastTreeMsgConstructErr).
+		WithResyncMetrics(d.resyncFailures.Load()).
 		WithCounters(clientCount, alertCount, blockedCount).
+		WithBroadcastLatency(time.Duration(d.lastBroadcastNanos.Load()), time.Duration(d.avgBroadcastNanos.Load())).
+		WithStartTime(d.startTime).
+		WithVersion(version.String()).
 		Build()
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to create health status: %v", err)
@@ -1870,8 +2334,8 @@ func (d *AlertDaemon) Stop() error {
 		d.paneFocusWatcher.Close()
 	}
 
-	// Close all client connections
-	d.clientsMu.Lock()
+	// Close all client connections, force-closing any still sending after the drain timeout
+	d.lockClientsForShutdown()
 	for clientID, client := range d.clients {
 		debug.Log("DAEMON_CLOSING_CLIENT id=%s", clientID)
 		client.conn.Close()
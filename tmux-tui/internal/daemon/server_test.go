@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/commons-systems/tmux-tui/internal/detector"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
 	"github.com/commons-systems/tmux-tui/internal/tmux/testutil"
+	"github.com/commons-systems/tmux-tui/internal/version"
 	"github.com/commons-systems/tmux-tui/internal/watcher"
 )
 
@@ -91,11 +93,11 @@ func TestLoadBlockedBranches_ValidData(t *testing.T) {
 		t.Errorf("Expected 2 branches, got %d", len(branches))
 	}
 
-	if branches["feature-1"] != "main" {
+	if !slices.Equal(branches["feature-1"], []string{"main"}) {
 		t.Errorf("Expected feature-1 blocked by main, got %s", branches["feature-1"])
 	}
 
-	if branches["feature-2"] != "develop" {
+	if !slices.Equal(branches["feature-2"], []string{"develop"}) {
 		t.Errorf("Expected feature-2 blocked by develop, got %s", branches["feature-2"])
 	}
 }
@@ -154,7 +156,7 @@ func TestPersistenceRecovery_TruncatedJSON(t *testing.T) {
 
 	// Verify recovery: Create daemon with clean state and verify it can accept new operations
 	daemon := &AlertDaemon{
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     filePath,
 	}
 
@@ -162,7 +164,7 @@ func TestPersistenceRecovery_TruncatedJSON(t *testing.T) {
 	os.Remove(filePath)
 
 	// Add a new branch - this should work
-	daemon.blockedBranches["test-branch"] = "main"
+	daemon.blockedBranches["test-branch"] = []string{"main"}
 
 	// Save should succeed with clean data
 	if err := daemon.saveBlockedBranches(); err != nil {
@@ -174,10 +176,10 @@ func TestPersistenceRecovery_TruncatedJSON(t *testing.T) {
 	if err != nil {
 		t.Errorf("Failed to read recovered file: %v", err)
 	} else {
-		var loaded map[string]string
+		var loaded map[string][]string
 		if err := json.Unmarshal(data, &loaded); err != nil {
 			t.Errorf("Recovered file contains invalid JSON: %v", err)
-		} else if loaded["test-branch"] != "main" {
+		} else if !slices.Equal(loaded["test-branch"], []string{"main"}) {
 			t.Errorf("Recovered file missing expected data, got: %v", loaded)
 		} else {
 			t.Log("Recovery successful - daemon can save valid data after encountering truncated JSON")
@@ -192,9 +194,9 @@ func TestSaveBlockedBranches_Success(t *testing.T) {
 
 	// Create daemon with test data
 	daemon := &AlertDaemon{
-		blockedBranches: map[string]string{
-			"feature-1": "main",
-			"feature-2": "develop",
+		blockedBranches: BlockMap{
+			"feature-1": {"main"},
+			"feature-2": {"develop"},
 		},
 		blockedPath: filePath,
 	}
@@ -210,7 +212,7 @@ func TestSaveBlockedBranches_Success(t *testing.T) {
 		t.Fatalf("Failed to read saved file: %v", err)
 	}
 
-	var loaded map[string]string
+	var loaded map[string][]string
 	if err := json.Unmarshal(data, &loaded); err != nil {
 		t.Fatalf("Failed to unmarshal saved data: %v", err)
 	}
@@ -219,8 +221,8 @@ func TestSaveBlockedBranches_Success(t *testing.T) {
 		t.Errorf("Expected 2 entries in saved file, got %d", len(loaded))
 	}
 
-	if loaded["feature-1"] != "main" {
-		t.Errorf("Expected feature-1: main, got %s", loaded["feature-1"])
+	if !slices.Equal(loaded["feature-1"], []string{"main"}) {
+		t.Errorf("Expected feature-1: [main], got %v", loaded["feature-1"])
 	}
 }
 
@@ -241,7 +243,7 @@ func TestSaveBlockedBranches_PermissionDenied(t *testing.T) {
 	defer os.Chmod(readonlyDir, 0755) // Cleanup
 
 	daemon := &AlertDaemon{
-		blockedBranches: map[string]string{"feature-1": "main"},
+		blockedBranches: BlockMap{"feature-1": {"main"}},
 		blockedPath:     filePath,
 	}
 
@@ -258,7 +260,7 @@ func TestSaveBlockedBranches_EmptyMap(t *testing.T) {
 	filePath := filepath.Join(tmpDir, "blocked-branches.json")
 
 	daemon := &AlertDaemon{
-		blockedBranches: map[string]string{},
+		blockedBranches: BlockMap{},
 		blockedPath:     filePath,
 	}
 
@@ -308,8 +310,8 @@ func TestRevertBlockedBranchChange_MultiClientConsistency(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients: make(map[string]*clientConnection),
 		alerts:  make(map[string]string),
-		blockedBranches: map[string]string{
-			"feature-1": "main",
+		blockedBranches: BlockMap{
+			"feature-1": {"main"},
 		},
 		blockedPath: blockedPath,
 	}
@@ -334,7 +336,7 @@ func TestRevertBlockedBranchChange_MultiClientConsistency(t *testing.T) {
 
 	// Client1: Send hello
 	enc1 := json.NewEncoder(client1Writer)
-	if err := enc1.Encode(Message{Type: MsgTypeHello, ClientID: "client-1"}); err != nil {
+	if err := enc1.Encode(Message{Type: MsgTypeHello, ClientID: "client-1", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello from client1: %v", err)
 	}
 
@@ -346,7 +348,7 @@ func TestRevertBlockedBranchChange_MultiClientConsistency(t *testing.T) {
 	}
 
 	// Verify initial state: feature-1 blocked by main
-	if fullState1.BlockedBranches["feature-1"] != "main" {
+	if !slices.Equal(fullState1.BlockedBranches["feature-1"], []string{"main"}) {
 		t.Fatalf("Expected initial state: feature-1 blocked by main, got %s",
 			fullState1.BlockedBranches["feature-1"])
 	}
@@ -369,7 +371,7 @@ func TestRevertBlockedBranchChange_MultiClientConsistency(t *testing.T) {
 
 	// Client2: Send hello
 	enc2 := json.NewEncoder(client2Writer)
-	if err := enc2.Encode(Message{Type: MsgTypeHello, ClientID: "client-2"}); err != nil {
+	if err := enc2.Encode(Message{Type: MsgTypeHello, ClientID: "client-2", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello from client2: %v", err)
 	}
 
@@ -435,7 +437,7 @@ func TestRevertBlockedBranchChange_MultiClientConsistency(t *testing.T) {
 
 	// Client3: Send hello
 	enc3 := json.NewEncoder(client3Writer)
-	if err := enc3.Encode(Message{Type: MsgTypeHello, ClientID: "client-3"}); err != nil {
+	if err := enc3.Encode(Message{Type: MsgTypeHello, ClientID: "client-3", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello from client3: %v", err)
 	}
 
@@ -448,7 +450,7 @@ func TestRevertBlockedBranchChange_MultiClientConsistency(t *testing.T) {
 
 	// CRITICAL: Client3 should receive REVERTED state (feature-1 blocked by main)
 	// Not the temporary "successful" state (feature-1 blocked by develop)
-	if fullState3.BlockedBranches["feature-1"] != "main" {
+	if !slices.Equal(fullState3.BlockedBranches["feature-1"], []string{"main"}) {
 		t.Errorf("CONSISTENCY VIOLATION: Client3 received stale state. "+
 			"Expected feature-1 blocked by main (reverted), got %s",
 			fullState3.BlockedBranches["feature-1"])
@@ -490,7 +492,7 @@ drainLoop:
 	finalState := daemon.blockedBranches["feature-1"]
 	daemon.blockedMu.RUnlock()
 
-	if finalState != "main" {
+	if !slices.Equal(finalState, []string{"main"}) {
 		t.Errorf("Final daemon state inconsistent: expected feature-1 blocked by main, got %s", finalState)
 	}
 
@@ -503,10 +505,10 @@ func TestLoadSaveRoundtrip(t *testing.T) {
 	filePath := filepath.Join(tmpDir, "blocked-branches.json")
 
 	// Original data
-	original := map[string]string{
-		"feature-1": "main",
-		"feature-2": "develop",
-		"feature-3": "release",
+	original := BlockMap{
+		"feature-1": {"main"},
+		"feature-2": {"develop", "release"},
+		"feature-3": {"release"},
 	}
 
 	// Save original
@@ -530,7 +532,7 @@ func TestLoadSaveRoundtrip(t *testing.T) {
 	}
 
 	for branch, blockedBy := range original {
-		if loaded[branch] != blockedBy {
+		if !slices.Equal(loaded[branch], blockedBy) {
 			t.Errorf("Mismatch for %s: original=%s, loaded=%s", branch, blockedBy, loaded[branch])
 		}
 	}
@@ -552,7 +554,7 @@ func TestLoadSaveRoundtrip(t *testing.T) {
 
 	// Verify still matches
 	for branch, blockedBy := range original {
-		if final[branch] != blockedBy {
+		if !slices.Equal(final[branch], blockedBy) {
 			t.Errorf("Final mismatch for %s: original=%s, final=%s", branch, blockedBy, final[branch])
 		}
 	}
@@ -566,7 +568,7 @@ func TestBroadcast_EmptyClientList(t *testing.T) {
 	daemon.lastBroadcastError.Store("")
 
 	// Should not panic
-	msg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "test-pane", "idle", true)
+	msg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "test-pane", "idle", true, time.Time{}, "")
 	daemon.broadcast(msg.ToWireFormat())
 }
 
@@ -603,7 +605,7 @@ func TestGetHealthStatus(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
 		alerts:          map[string]string{"pane1": "stop"},
-		blockedBranches: map[string]string{"feature": "main"},
+		blockedBranches: BlockMap{"feature": {"main"}},
 	}
 	daemon.lastBroadcastError.Store("test error")
 	daemon.broadcastFailures.Store(5)
@@ -649,7 +651,7 @@ func TestGetHealthStatus_ValidationFailure(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
 		alerts:          map[string]string{"pane1": "stop"},
-		blockedBranches: map[string]string{"feature": "main"},
+		blockedBranches: BlockMap{"feature": {"main"}},
 	}
 
 	// Corrupt internal state by setting negative error count
@@ -798,7 +800,7 @@ func TestBroadcastSequenceNumbers(t *testing.T) {
 
 	// Broadcast 5 messages
 	for i := 0; i < 5; i++ {
-		msg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane-1", "idle", true)
+		msg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane-1", "idle", true, time.Time{}, "")
 		daemon.broadcast(msg.ToWireFormat())
 	}
 
@@ -1133,7 +1135,7 @@ func TestConcurrentBroadcasts(t *testing.T) {
 			defer wg.Done()
 			for j := 0; j < broadcastsPerGoroutine; j++ {
 				// Use v2 constructor which increments seqCounter
-				msg, err := NewAlertChangeMessage(daemon.seqCounter.Add(1), "test-pane", "test-event", true)
+				msg, err := NewAlertChangeMessage(daemon.seqCounter.Add(1), "test-pane", "test-event", true, time.Time{}, "")
 				if err != nil {
 					t.Errorf("Failed to create message: %v", err)
 					return
@@ -1167,10 +1169,10 @@ func TestBroadcast_FullStateDuringRapidClientChurn(t *testing.T) {
 			"pane-4": "error",
 			"pane-5": "success",
 		},
-		blockedBranches: map[string]string{
-			"feature-1": "main",
-			"feature-2": "develop",
-			"feature-3": "staging",
+		blockedBranches: BlockMap{
+			"feature-1": {"main"},
+			"feature-2": {"develop"},
+			"feature-3": {"staging"},
 		},
 	}
 	daemon.lastBroadcastError.Store("")
@@ -1249,7 +1251,7 @@ func TestBroadcast_FullStateDuringRapidClientChurn(t *testing.T) {
 
 			// Send full state to each client (mimics what daemon does on resync)
 			for clientID, client := range clients {
-				if err := daemon.sendFullState(client, clientID); err != nil {
+				if err := daemon.sendFullState(client, clientID, false); err != nil {
 					// Connection errors expected during churn
 					debug.Log("CHURN_TEST_BROADCAST_ERROR client=%s error=%v", clientID, err)
 				}
@@ -1287,7 +1289,7 @@ func TestSendFullState(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
 		alerts:          map[string]string{"pane-1": "stop", "pane-2": "idle"},
-		blockedBranches: map[string]string{"feature": "main"},
+		blockedBranches: BlockMap{"feature": {"main"}},
 		collector:       collector,
 	}
 	daemon.seqCounter.Store(42) // Set a specific sequence for testing
@@ -1314,7 +1316,7 @@ func TestSendFullState(t *testing.T) {
 	}()
 
 	// Send full state
-	if err := daemon.sendFullState(client, "test-client"); err != nil {
+	if err := daemon.sendFullState(client, "test-client", false); err != nil {
 		t.Fatalf("sendFullState failed: %v", err)
 	}
 
@@ -1337,11 +1339,144 @@ func TestSendFullState(t *testing.T) {
 		t.Errorf("Expected 1 blocked branch, got %d", len(msg.BlockedBranches))
 	}
 
-	if msg.BlockedBranches["feature"] != "main" {
+	if !slices.Equal(msg.BlockedBranches["feature"], []string{"main"}) {
 		t.Errorf("Expected feature blocked by main")
 	}
 }
 
+// TestSendFullState_SkipsUnchanged verifies a resync doesn't resend a full_state the
+// client already has, or spend a sequence number on it, unless forced or the state changed.
+func TestSendFullState_SkipsUnchanged(t *testing.T) {
+	daemon := &AlertDaemon{
+		clients:         make(map[string]*clientConnection),
+		alerts:          map[string]string{"pane-1": "stop"},
+		blockedBranches: make(BlockMap),
+	}
+
+	r, w := io.Pipe()
+	defer r.Close()
+	defer w.Close()
+	client := &clientConnection{
+		conn:    &mockConn{reader: r, writer: w},
+		encoder: json.NewEncoder(w),
+	}
+
+	fullStates := make(chan Message, 10)
+	go func() {
+		decoder := json.NewDecoder(r)
+		for {
+			var msg Message
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			if msg.Type == MsgTypeFullState {
+				fullStates <- msg
+			}
+		}
+	}()
+	expectFullState := func(step string) Message {
+		t.Helper()
+		select {
+		case msg := <-fullStates:
+			return msg
+		case <-time.After(time.Second):
+			t.Fatalf("%s: expected a full_state", step)
+			return Message{}
+		}
+	}
+
+	if err := daemon.sendFullState(client, "c1", false); err != nil {
+		t.Fatalf("sendFullState failed: %v", err)
+	}
+	first := expectFullState("first resync")
+
+	seqBefore := daemon.seqCounter.Load()
+	if err := daemon.sendFullState(client, "c1", false); err != nil {
+		t.Fatalf("sendFullState failed: %v", err)
+	}
+	if got := daemon.seqCounter.Load(); got != seqBefore {
+		t.Errorf("Skipped resync consumed sequence numbers: %d -> %d", seqBefore, got)
+	}
+
+	// Forced resyncs are always answered
+	if err := daemon.sendFullState(client, "c1", true); err != nil {
+		t.Fatalf("sendFullState failed: %v", err)
+	}
+	forced := expectFullState("forced resync")
+	if forced.SeqNum <= first.SeqNum {
+		t.Errorf("Expected increasing sequence numbers, got %d then %d", first.SeqNum, forced.SeqNum)
+	}
+
+	// A changed state is sent even without force
+	daemon.alertsMu.Lock()
+	daemon.alerts["pane-2"] = "idle"
+	daemon.alertsMu.Unlock()
+	if err := daemon.sendFullState(client, "c1", false); err != nil {
+		t.Fatalf("sendFullState failed: %v", err)
+	}
+	if changed := expectFullState("changed state"); len(changed.Alerts) != 2 {
+		t.Errorf("Expected the new alert in the resent state, got %v", changed.Alerts)
+	}
+
+	select {
+	case msg := <-fullStates:
+		t.Errorf("Unexpected extra full_state (seq %d): the unchanged resync should have been skipped", msg.SeqNum)
+	default:
+	}
+}
+
+// TestHandleClient_FullStateIncludesActivePane verifies a newly connected client's
+// full_state carries the focused pane, so it can highlight it before the next pane_focus.
+func TestHandleClient_FullStateIncludesActivePane(t *testing.T) {
+	daemon := &AlertDaemon{
+		clients:         make(map[string]*clientConnection),
+		alerts:          make(map[string]string),
+		blockedBranches: make(BlockMap),
+		activePaneID:    "%7",
+	}
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		daemon.handleClient(serverConn)
+		close(done)
+	}()
+
+	encoder := json.NewEncoder(clientConn)
+	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "focus-client", ProtocolVersion: ProtocolVersion}); err != nil {
+		t.Fatalf("Failed to send hello: %v", err)
+	}
+
+	received := make(chan Message, 1)
+	go func() {
+		var msg Message
+		if err := json.NewDecoder(clientConn).Decode(&msg); err == nil {
+			received <- msg
+		}
+	}()
+
+	select {
+	case msg := <-received:
+		if msg.Type != MsgTypeFullState {
+			t.Fatalf("Expected full_state message, got %s", msg.Type)
+		}
+		if msg.ActivePaneID != "%7" {
+			t.Errorf("Expected active pane %%7 in full_state, got %q", msg.ActivePaneID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for full_state")
+	}
+
+	clientConn.Close()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handleClient did not return after client disconnect")
+	}
+}
+
 // TestHandleClient_ResyncRequest tests that daemon sends full_state on resync request
 func TestHandleClient_ResyncRequest(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -1360,8 +1495,8 @@ func TestHandleClient_ResyncRequest(t *testing.T) {
 			"pane-1": "stop",
 			"pane-2": "idle",
 		},
-		blockedBranches: map[string]string{
-			"feature-1": "main",
+		blockedBranches: BlockMap{
+			"feature-1": {"main"},
 		},
 		blockedPath: blockedPath,
 		collector:   collector,
@@ -1386,7 +1521,7 @@ func TestHandleClient_ResyncRequest(t *testing.T) {
 
 	// Send hello message
 	encoder := json.NewEncoder(clientWriter)
-	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "test-client"}); err != nil {
+	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "test-client", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello: %v", err)
 	}
 
@@ -1404,8 +1539,9 @@ func TestHandleClient_ResyncRequest(t *testing.T) {
 	initialSeq := fullState1.SeqNum
 	t.Logf("Initial full_state seq: %d", initialSeq)
 
-	// Send resync request
-	if err := encoder.Encode(Message{Type: MsgTypeResyncRequest}); err != nil {
+	// Send resync request. The state hasn't changed since the hello, so only a forced
+	// resync is answered (see TestSendFullState_SkipsUnchanged)
+	if err := encoder.Encode(Message{Type: MsgTypeResyncRequest, Force: true}); err != nil {
 		t.Fatalf("Failed to send resync request: %v", err)
 	}
 
@@ -1452,7 +1588,7 @@ func TestHandleClient_ResyncRequest(t *testing.T) {
 			len(fullState2.BlockedBranches))
 	}
 
-	if fullState2.BlockedBranches["feature-1"] != "main" {
+	if !slices.Equal(fullState2.BlockedBranches["feature-1"], []string{"main"}) {
 		t.Error("Expected feature-1 blocked by main in resync")
 	}
 
@@ -1548,7 +1684,7 @@ func TestHandleClient_PongSendFailure(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 	}
 	daemon.lastBroadcastError.Store("")
@@ -1569,8 +1705,9 @@ func TestHandleClient_PongSendFailure(t *testing.T) {
 	// Send hello message
 	encoder := json.NewEncoder(clientWriter)
 	helloMsg := Message{
-		Type:     MsgTypeHello,
-		ClientID: "test-client",
+		Type:            MsgTypeHello,
+		ClientID:        "test-client",
+		ProtocolVersion: ProtocolVersion,
 	}
 	if err := encoder.Encode(helloMsg); err != nil {
 		t.Fatalf("Failed to send hello: %v", err)
@@ -1676,7 +1813,7 @@ func TestBroadcast_FailedClientRemoval(t *testing.T) {
 	}()
 
 	// Broadcast a message
-	testMsg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true)
+	testMsg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true, time.Time{}, "")
 	daemon.broadcast(testMsg.ToWireFormat())
 
 	// Wait for messages to be received
@@ -1773,7 +1910,7 @@ func TestBroadcast_MemoryLeakPrevention(t *testing.T) {
 	}
 
 	// Broadcast a message
-	testMsg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true)
+	testMsg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true, time.Time{}, "")
 	daemon.broadcast(testMsg.ToWireFormat())
 
 	// Give time for messages to be sent
@@ -1790,7 +1927,7 @@ func TestBroadcast_MemoryLeakPrevention(t *testing.T) {
 	}
 
 	// Broadcast again - all remaining clients should succeed
-	testMsg2, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane2", "stop", true)
+	testMsg2, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane2", "stop", true, time.Time{}, "")
 	daemon.broadcast(testMsg2.ToWireFormat())
 
 	// Give time for messages to be sent
@@ -1822,7 +1959,7 @@ func TestConcurrentBlockUnblock_SameBranch(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 	}
 	daemon.lastBroadcastError.Store("")
@@ -1862,10 +1999,10 @@ func TestConcurrentBlockUnblock_SameBranch(t *testing.T) {
 	encoder1 := json.NewEncoder(client1Writer)
 	encoder2 := json.NewEncoder(client2Writer)
 
-	if err := encoder1.Encode(Message{Type: MsgTypeHello, ClientID: "blocker-client"}); err != nil {
+	if err := encoder1.Encode(Message{Type: MsgTypeHello, ClientID: "blocker-client", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello from client1: %v", err)
 	}
-	if err := encoder2.Encode(Message{Type: MsgTypeHello, ClientID: "unblocker-client"}); err != nil {
+	if err := encoder2.Encode(Message{Type: MsgTypeHello, ClientID: "unblocker-client", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello from client2: %v", err)
 	}
 
@@ -1977,7 +2114,7 @@ func TestConcurrentBlockUnblock_SameBranch(t *testing.T) {
 	// Verify final state consistency
 	daemon.blockedMu.RLock()
 	inMemoryBlocked := daemon.blockedBranches["test-branch"]
-	inMemoryIsBlocked := (inMemoryBlocked != "")
+	inMemoryIsBlocked := len(inMemoryBlocked) > 0
 	daemon.blockedMu.RUnlock()
 
 	// Load from persistence file
@@ -1987,7 +2124,7 @@ func TestConcurrentBlockUnblock_SameBranch(t *testing.T) {
 	}
 
 	persistedBlocked := persistedBranches["test-branch"]
-	persistedIsBlocked := (persistedBlocked != "")
+	persistedIsBlocked := len(persistedBlocked) > 0
 
 	// Verify in-memory matches disk
 	if inMemoryIsBlocked != persistedIsBlocked {
@@ -1995,7 +2132,7 @@ func TestConcurrentBlockUnblock_SameBranch(t *testing.T) {
 			inMemoryIsBlocked, persistedIsBlocked)
 	}
 
-	if inMemoryIsBlocked && inMemoryBlocked != persistedBlocked {
+	if inMemoryIsBlocked && !slices.Equal(inMemoryBlocked, persistedBlocked) {
 		t.Errorf("Blocked-by mismatch: in-memory=%s, persisted=%s",
 			inMemoryBlocked, persistedBlocked)
 	}
@@ -2049,8 +2186,8 @@ func TestBlockBranch_PersistenceFailureRollback(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients: make(map[string]*clientConnection),
 		alerts:  make(map[string]string),
-		blockedBranches: map[string]string{
-			"feature-1": "main",
+		blockedBranches: BlockMap{
+			"feature-1": {"main"},
 		},
 		blockedPath: blockedPath,
 	}
@@ -2074,7 +2211,7 @@ func TestBlockBranch_PersistenceFailureRollback(t *testing.T) {
 
 	// Send hello message
 	encoder := json.NewEncoder(clientWriter)
-	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "test-client"}); err != nil {
+	if err := encoder.Encode(Message{Type: MsgTypeHello, ClientID: "test-client", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello: %v", err)
 	}
 
@@ -2086,7 +2223,7 @@ func TestBlockBranch_PersistenceFailureRollback(t *testing.T) {
 	}
 
 	// Verify initial state: feature-1 blocked by main
-	if fullStateMsg.BlockedBranches["feature-1"] != "main" {
+	if !slices.Equal(fullStateMsg.BlockedBranches["feature-1"], []string{"main"}) {
 		t.Fatalf("Expected initial state: feature-1 blocked by main, got %s",
 			fullStateMsg.BlockedBranches["feature-1"])
 	}
@@ -2157,7 +2294,7 @@ func TestBlockBranch_PersistenceFailureRollback(t *testing.T) {
 	inMemoryBlocked := daemon.blockedBranches["feature-1"]
 	daemon.blockedMu.RUnlock()
 
-	if inMemoryBlocked != "main" {
+	if !slices.Equal(inMemoryBlocked, []string{"main"}) {
 		t.Errorf("Expected in-memory state reverted to main, got %s", inMemoryBlocked)
 	}
 
@@ -2258,7 +2395,7 @@ func TestPlayAlertSound_ConcurrentRateLimiting(t *testing.T) {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			daemon.playAlertSound()
+			daemon.playAlertSound(watcher.EventTypeStop)
 			debug.Log("AUDIO_TEST_GOROUTINE_DONE id=%d", id)
 		}(i)
 	}
@@ -2376,7 +2513,7 @@ func TestServerBroadcastRecoveryAfterClientDisconnect(t *testing.T) {
 	// 2. Successfully send to client1
 	// 3. Remove client2 from clients map
 	// 4. Send sync_warning to client1
-	testMsg, err := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true)
+	testMsg, err := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true, time.Time{}, "")
 	if err != nil {
 		t.Fatalf("Failed to create test message: %v", err)
 	}
@@ -2531,7 +2668,7 @@ func TestBroadcast_MetricsTracking(t *testing.T) {
 	t.Logf("Initial broadcast failures: %d", initialFailures)
 
 	// Create and broadcast a test message
-	msg, err := NewAlertChangeMessage(daemon.seqCounter.Add(1), "test-pane-1", "idle", true)
+	msg, err := NewAlertChangeMessage(daemon.seqCounter.Add(1), "test-pane-1", "idle", true, time.Time{}, "")
 	if err != nil {
 		t.Fatalf("Failed to create message: %v", err)
 	}
@@ -2696,7 +2833,7 @@ func TestConcurrentAlertChangeAndStateQuery(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		previousState:   make(map[string]string),
 		done:            make(chan struct{}),
 	}
@@ -2787,7 +2924,7 @@ func TestConnectionCloseErrors_ThresholdMonitoring(t *testing.T) {
 	}
 
 	// Broadcast message - will fail for all clients and trigger close errors
-	testMsg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true)
+	testMsg, _ := NewAlertChangeMessage(daemon.seqCounter.Add(1), "pane1", "stop", true, time.Time{}, "")
 	daemon.broadcast(testMsg.ToWireFormat())
 
 	time.Sleep(200 * time.Millisecond)
@@ -2842,6 +2979,9 @@ func TestIsDuplicateEvent_WindowExpiration(t *testing.T) {
 	daemon, cleanup := setupTestDaemonForDeduplication(t)
 	defer cleanup()
 
+	clock := NewFakeClock(time.Now())
+	daemon.clock = clock
+
 	paneID := "%2"
 	eventType := "stop"
 
@@ -2850,8 +2990,13 @@ func TestIsDuplicateEvent_WindowExpiration(t *testing.T) {
 		t.Error("First event should not be duplicate")
 	}
 
-	// Wait for deduplication window to expire (100ms)
-	time.Sleep(150 * time.Millisecond)
+	// Just inside the deduplication window the event is still a duplicate
+	clock.Advance(eventDeduplicationWindow - time.Millisecond)
+	if !daemon.isDuplicateEvent(paneID, eventType, true) {
+		t.Error("Event inside the window should be duplicate")
+	}
+
+	clock.Advance(eventDeduplicationWindow)
 
 	// After window expires, same event should not be duplicate
 	if daemon.isDuplicateEvent(paneID, eventType, true) {
@@ -2865,8 +3010,11 @@ func TestIsDuplicateEvent_CleanupPreventsMemoryLeak(t *testing.T) {
 	daemon, cleanup := setupTestDaemonForDeduplication(t)
 	defer cleanup()
 
-	// Create 5 old events (>1 second old) by manipulating the map directly
-	oldTime := time.Now().Add(-1100 * time.Millisecond)
+	clock := NewFakeClock(time.Now())
+	daemon.clock = clock
+
+	// Create 5 old events by manipulating the map directly
+	oldTime := clock.Now()
 	oldEvents := []eventKey{
 		{paneID: "%10", eventType: "idle", created: true},
 		{paneID: "%11", eventType: "stop", created: true},
@@ -2885,8 +3033,8 @@ func TestIsDuplicateEvent_CleanupPreventsMemoryLeak(t *testing.T) {
 		t.Fatalf("Expected 5 events in map, got %d", len(daemon.recentEvents))
 	}
 
-	// Wait for cleanup threshold (1100ms) to ensure old events are stale
-	time.Sleep(1100 * time.Millisecond)
+	// Move past the cleanup threshold so the old events are stale
+	clock.Advance(eventCleanupThreshold + 100*time.Millisecond)
 
 	// Trigger cleanup by creating a new event
 	// The isDuplicateEvent function cleans up entries > eventCleanupThreshold (1 second)
@@ -3742,7 +3890,7 @@ func TestHandleClient_TreeUpdateDuringFullState(t *testing.T) {
 
 	// Send hello message from client
 	enc := json.NewEncoder(clientW)
-	if err := enc.Encode(Message{Type: MsgTypeHello, ClientID: "test-client"}); err != nil {
+	if err := enc.Encode(Message{Type: MsgTypeHello, ClientID: "test-client", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello: %v", err)
 	}
 
@@ -4277,7 +4425,7 @@ func TestDaemon_TitleDetectorIntegration(t *testing.T) {
 		clients:         make(map[string]*clientConnection),
 		done:            make(chan struct{}),
 		recentEvents:    make(map[eventKey]time.Time),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     filepath.Join(tmpDir, "blocked.json"),
 	}
 	daemon.lastBroadcastError.Store("")
@@ -4400,7 +4548,7 @@ func TestDaemon_DetectorRepeatedErrors(t *testing.T) {
 		clients:         make(map[string]*clientConnection),
 		done:            make(chan struct{}),
 		recentEvents:    make(map[eventKey]time.Time),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     filepath.Join(tmpDir, "blocked.json"),
 	}
 	daemon.lastBroadcastError.Store("")
@@ -4540,7 +4688,7 @@ func TestDaemon_HookDetectorBackwardCompatibility(t *testing.T) {
 		clients:         make(map[string]*clientConnection),
 		done:            make(chan struct{}),
 		recentEvents:    make(map[eventKey]time.Time),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     filepath.Join(tmpDir, "blocked.json"),
 	}
 	daemon.lastBroadcastError.Store("")
@@ -4607,7 +4755,7 @@ func TestDaemon_HandleStateChangeEvent(t *testing.T) {
 		previousState:   make(map[string]string),
 		clients:         make(map[string]*clientConnection),
 		recentEvents:    make(map[eventKey]time.Time),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     filepath.Join(tmpDir, "blocked.json"),
 	}
 	daemon.lastBroadcastError.Store("")
@@ -4703,6 +4851,77 @@ func TestDaemon_HandleStateChangeEvent(t *testing.T) {
 	}
 }
 
+// TestDaemon_HandleStateChangeEvent_AlertedAt verifies alert_change carries when the alert
+// started, and that the start is kept while the pane stays alerted.
+func TestDaemon_HandleStateChangeEvent_AlertedAt(t *testing.T) {
+	// Skip audio playback in tests
+	t.Setenv("CLAUDE_E2E_TEST", "1")
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	daemon := newBlocksTestDaemon(t, BlockMap{})
+	daemon.clock = clock
+	daemon.alerts = make(map[string]string)
+	daemon.previousState = make(map[string]string)
+	daemon.recentEvents = make(map[eventKey]time.Time)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+	daemon.clients["test-client"] = &clientConnection{conn: serverConn, encoder: json.NewEncoder(serverConn)}
+
+	broadcasts := make(chan Message, 5)
+	go func() {
+		decoder := json.NewDecoder(clientConn)
+		for {
+			var msg Message
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			broadcasts <- msg
+		}
+	}()
+	next := func() Message {
+		t.Helper()
+		select {
+		case msg := <-broadcasts:
+			return msg
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("Timeout waiting for alert_change broadcast")
+			return Message{}
+		}
+	}
+
+	daemon.handleStateChangeEvent(detector.NewStateChangeEvent("%1", detector.StateIdle))
+	if msg := next(); !msg.AlertedAt.Equal(start) {
+		t.Errorf("New alert AlertedAt = %v, want %v", msg.AlertedAt, start)
+	}
+
+	// Repeated idle past the dedup window keeps the original start
+	clock.Advance(time.Minute)
+	daemon.handleStateChangeEvent(detector.NewStateChangeEvent("%1", detector.StateIdle))
+	if msg := next(); !msg.AlertedAt.Equal(start) {
+		t.Errorf("Repeated idle AlertedAt = %v, want original %v", msg.AlertedAt, start)
+	}
+	if got := daemon.copyAlertTimes()["%1"]; !got.Equal(start) {
+		t.Errorf("Full state alert time = %v, want %v", got, start)
+	}
+
+	// Working clears the start; the next alert starts fresh
+	daemon.handleStateChangeEvent(detector.NewStateChangeEvent("%1", detector.StateWorking))
+	if msg := next(); !msg.AlertedAt.IsZero() {
+		t.Errorf("Working AlertedAt = %v, want zero", msg.AlertedAt)
+	}
+	if _, ok := daemon.copyAlertTimes()["%1"]; ok {
+		t.Error("Alert time should be cleared once the pane is working")
+	}
+	clock.Advance(time.Minute)
+	daemon.handleStateChangeEvent(detector.NewStateChangeEvent("%1", detector.StateIdle))
+	if msg, want := next(), start.Add(2*time.Minute); !msg.AlertedAt.Equal(want) {
+		t.Errorf("Next alert AlertedAt = %v, want %v", msg.AlertedAt, want)
+	}
+}
+
 // TestDaemon_DetectorStrategyEnvironmentVariable verifies TMUX_TUI_DETECTOR
 // environment variable controls detector strategy selection.
 //
@@ -5233,7 +5452,7 @@ func TestHandleClient_TreeErrorOnCollectorFailure(t *testing.T) {
 
 	// Send hello message from client
 	enc := json.NewEncoder(clientW)
-	if err := enc.Encode(Message{Type: MsgTypeHello, ClientID: "test-client"}); err != nil {
+	if err := enc.Encode(Message{Type: MsgTypeHello, ClientID: "test-client", ProtocolVersion: ProtocolVersion}); err != nil {
 		t.Fatalf("Failed to send hello: %v", err)
 	}
 
@@ -5433,7 +5652,7 @@ func TestGetHealthStatus_TreeMetrics(t *testing.T) {
 	// Create daemon with tree metrics
 	daemon := &AlertDaemon{
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		clients:         make(map[string]*clientConnection),
 	}
 
@@ -5473,6 +5692,133 @@ func TestGetHealthStatus_TreeMetrics(t *testing.T) {
 	}
 }
 
+func TestRecordBroadcastDuration(t *testing.T) {
+	daemon := &AlertDaemon{
+		alerts:          make(map[string]string),
+		blockedBranches: make(BlockMap),
+		clients:         make(map[string]*clientConnection),
+	}
+	daemon.lastBroadcastError.Store("")
+
+	// First sample seeds the average; later samples move it by broadcastLatencyWeight
+	daemon.recordBroadcastDuration(8 * time.Millisecond)
+	daemon.recordBroadcastDuration(16 * time.Millisecond)
+
+	status, err := daemon.GetHealthStatus()
+	if err != nil {
+		t.Fatalf("GetHealthStatus failed: %v", err)
+	}
+	if got := status.GetLastBroadcastDuration(); got != 16*time.Millisecond {
+		t.Errorf("Expected last broadcast duration 16ms, got %v", got)
+	}
+	if got := status.GetAvgBroadcastDuration(); got != 9*time.Millisecond {
+		t.Errorf("Expected average broadcast duration 9ms, got %v", got)
+	}
+
+	// Durations survive the health_response wire format
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded HealthStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.GetLastBroadcastDuration() != 16*time.Millisecond || decoded.GetAvgBroadcastDuration() != 9*time.Millisecond {
+		t.Errorf("Round trip lost durations: last=%v avg=%v", decoded.GetLastBroadcastDuration(), decoded.GetAvgBroadcastDuration())
+	}
+}
+
+func TestGetHealthStatus_Version(t *testing.T) {
+	daemon := &AlertDaemon{
+		alerts:          make(map[string]string),
+		blockedBranches: make(BlockMap),
+		clients:         make(map[string]*clientConnection),
+	}
+
+	status, err := daemon.GetHealthStatus()
+	if err != nil {
+		t.Fatalf("GetHealthStatus failed: %v", err)
+	}
+	if status.GetVersion() != version.String() {
+		t.Errorf("Expected version %q, got %q", version.String(), status.GetVersion())
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded HealthStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.GetVersion() != status.GetVersion() {
+		t.Errorf("Round trip lost version: got %q, want %q", decoded.GetVersion(), status.GetVersion())
+	}
+}
+
+func TestGetHealthStatus_Uptime(t *testing.T) {
+	started := time.Now().Add(-90 * time.Minute)
+	daemon := &AlertDaemon{
+		alerts:          make(map[string]string),
+		blockedBranches: make(BlockMap),
+		clients:         make(map[string]*clientConnection),
+		startTime:       started,
+	}
+
+	status, err := daemon.GetHealthStatus()
+	if err != nil {
+		t.Fatalf("GetHealthStatus failed: %v", err)
+	}
+	if !status.GetStartTime().Equal(started) {
+		t.Errorf("Expected start time %v, got %v", started, status.GetStartTime())
+	}
+	if got := status.GetUptime(); got < 90*time.Minute || got > 91*time.Minute {
+		t.Errorf("Expected uptime of about 90m, got %v", got)
+	}
+
+	// Start time and uptime survive the health_response wire format
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"uptime_ns":`) {
+		t.Errorf("Expected uptime_ns in JSON, got %s", data)
+	}
+	var decoded HealthStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	// Decoded times lose their monotonic reading, so allow for wall clock drift
+	if !decoded.GetStartTime().Equal(started) || decoded.GetUptime().Round(time.Second) != status.GetUptime().Round(time.Second) {
+		t.Errorf("Round trip lost uptime: start=%v uptime=%v", decoded.GetStartTime(), decoded.GetUptime())
+	}
+
+	// A daemon without a start time reports no uptime
+	daemon.startTime = time.Time{}
+	status, err = daemon.GetHealthStatus()
+	if err != nil {
+		t.Fatalf("GetHealthStatus failed: %v", err)
+	}
+	if status.GetUptime() != 0 {
+		t.Errorf("Expected zero uptime without a start time, got %v", status.GetUptime())
+	}
+}
+
+func TestBroadcast_RecordsDuration(t *testing.T) {
+	daemon, clientConn, serverConn := createTestDaemon(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+	go io.Copy(io.Discard, clientConn)
+
+	daemon.broadcast(Message{Type: MsgTypePong})
+
+	if daemon.lastBroadcastNanos.Load() <= 0 || daemon.avgBroadcastNanos.Load() <= 0 {
+		t.Errorf("Expected broadcast duration recorded, got last=%d avg=%d",
+			daemon.lastBroadcastNanos.Load(), daemon.avgBroadcastNanos.Load())
+	}
+}
+
 // TestCollectAndBroadcastTree_ConcurrentCallsSerialized tests collectorMu lock protection
 func TestCollectAndBroadcastTree_ConcurrentCallsSerialized(t *testing.T) {
 	if testing.Short() {
@@ -5511,7 +5857,7 @@ func TestDaemon_DegradedModeWhenCollectorNil(t *testing.T) {
 	// Create daemon with nil collector (simulating degraded mode)
 	daemon := &AlertDaemon{
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		clients:         make(map[string]*clientConnection),
 		collector:       nil, // Degraded mode - no collector
 		done:            make(chan struct{}),
@@ -5597,7 +5943,7 @@ func TestTreeBroadcast_MessageConstructionFailure(t *testing.T) {
 	// Create daemon with tracking counters
 	daemon := &AlertDaemon{
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		clients:         make(map[string]*clientConnection),
 		done:            make(chan struct{}),
 	}
@@ -5655,7 +6001,7 @@ func TestDaemon_CurrentTreeConcurrentAccess(t *testing.T) {
 	// Create daemon with currentTree
 	daemon := &AlertDaemon{
 		alerts:          make(map[string]string),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		clients:         make(map[string]*clientConnection),
 		currentTree:     tmux.NewRepoTree(),
 		done:            make(chan struct{}),
@@ -5730,7 +6076,7 @@ func TestUpdateTreeForPaneFocus_NilCollector(t *testing.T) {
 	daemon := &AlertDaemon{
 		collector:       nil, // Degraded mode
 		currentTree:     tmux.NewRepoTree(),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 		clients:         make(map[string]*clientConnection),
 	}
@@ -5794,7 +6140,7 @@ func TestUpdateTreeForPaneFocus_PaneNotFound(t *testing.T) {
 	daemon := &AlertDaemon{
 		collector:       nil, // Use nil to avoid actual tmux queries
 		currentTree:     tmux.NewRepoTree(),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 		clients:         make(map[string]*clientConnection),
 	}
@@ -5821,7 +6167,7 @@ func TestUpdateTreeForPaneFocus_WithMultiplePanes(t *testing.T) {
 	daemon := &AlertDaemon{
 		collector:       nil, // Use nil to avoid tmux queries
 		currentTree:     tmux.NewRepoTree(),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 		clients:         make(map[string]*clientConnection),
 	}
@@ -5874,7 +6220,7 @@ func TestUpdateTreeForPaneFocus_ActivationFailure(t *testing.T) {
 	daemon := &AlertDaemon{
 		collector:       nil,
 		currentTree:     tmux.NewRepoTree(),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 		clients:         make(map[string]*clientConnection),
 	}
@@ -5922,7 +6268,7 @@ func TestUpdateTreeForPaneFocus_HappyPath(t *testing.T) {
 	daemon := &AlertDaemon{
 		collector:       nil,
 		currentTree:     tmux.NewRepoTree(),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     blockedPath,
 		clients:         make(map[string]*clientConnection),
 	}
@@ -5996,7 +6342,7 @@ func createTestDaemon(t *testing.T) (*AlertDaemon, net.Conn, net.Conn) {
 	tmpDir := t.TempDir()
 	daemon := &AlertDaemon{
 		clients:         make(map[string]*clientConnection),
-		blockedBranches: make(map[string]string),
+		blockedBranches: make(BlockMap),
 		blockedPath:     filepath.Join(tmpDir, "blocked.json"),
 	}
 
@@ -6028,7 +6374,7 @@ func TestPlayAlertSound_EscapeSequenceFormat(t *testing.T) {
 	daemon := &AlertDaemon{
 		clients: make(map[string]*clientConnection),
 	}
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 
 	// Verify exact escape sequence format
 	expectedSequence := "\033]777;notify;tmux-tui;Alert\a\033]9;tmux-tui alert\a\a"
@@ -6087,7 +6433,7 @@ func TestPlayAlertSound_DevTtyOpenFailure(t *testing.T) {
 	}()
 
 	// Should not panic - broadcast writes to pipe that's being read
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 
 	if !openCalled {
 		t.Error("ttyWriter was not called")
@@ -6143,7 +6489,7 @@ func TestPlayAlertSound_WriteFailure(t *testing.T) {
 	}()
 
 	// Should not panic - broadcast writes to pipe that's being read
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 
 	// Verify close was still called despite write failure
 	if !closeCalled {
@@ -6197,7 +6543,7 @@ func TestPlayAlertSound_CloseFailure(t *testing.T) {
 	}()
 
 	// Should not panic - broadcast writes to pipe that's being read
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 
 	if !closeCalled {
 		t.Error("Close was not called")
@@ -6236,7 +6582,7 @@ func TestPlayAlertSound_CloseFailure(t *testing.T) {
 		return mock2, nil
 	}
 
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 
 	if !closeCalledAgain {
 		t.Error("Daemon not operational after close failure - second call didn't execute")
@@ -6274,7 +6620,7 @@ func TestPlayAlertSound_SkipDuringE2E(t *testing.T) {
 
 	// Create daemon and play sound
 	daemon := &AlertDaemon{clients: make(map[string]*clientConnection)}
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 
 	// Verify skip behavior
 	if writerCalled {
@@ -6308,20 +6654,20 @@ func TestPlayAlertSound_RateLimitSkip(t *testing.T) {
 	daemon := &AlertDaemon{clients: make(map[string]*clientConnection)}
 
 	// First call - should execute
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 	if writeCount != 1 {
 		t.Errorf("First call: expected 1 write, got %d", writeCount)
 	}
 
 	// Immediate second call - should be rate limited
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 	if writeCount != 1 {
 		t.Errorf("Second call within 500ms: expected 1 write (rate limited), got %d", writeCount)
 	}
 
 	// Wait past rate limit
 	time.Sleep(550 * time.Millisecond)
-	daemon.playAlertSound()
+	daemon.playAlertSound(watcher.EventTypeStop)
 	if writeCount != 2 {
 		t.Errorf("Third call after 550ms: expected 2 writes, got %d", writeCount)
 	}
@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+)
+
+// DefaultShutdownDrainTimeout is how long Stop lets in-flight sends finish before it
+// force-closes client connections.
+const DefaultShutdownDrainTimeout = 2 * time.Second
+
+// shutdownDrainTimeoutEnv overrides DefaultShutdownDrainTimeout (Go duration, e.g. "500ms").
+const shutdownDrainTimeoutEnv = "TMUX_TUI_SHUTDOWN_DRAIN_TIMEOUT"
+
+// shutdownDrainTimeoutFromEnv returns the drain timeout from TMUX_TUI_SHUTDOWN_DRAIN_TIMEOUT,
+// falling back to DefaultShutdownDrainTimeout when unset or invalid.
+func shutdownDrainTimeoutFromEnv() time.Duration {
+	raw := os.Getenv(shutdownDrainTimeoutEnv)
+	if raw == "" {
+		return DefaultShutdownDrainTimeout
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: Invalid %s=%q (expected positive duration like 2s), using default %v\n",
+			shutdownDrainTimeoutEnv, raw, DefaultShutdownDrainTimeout)
+		return DefaultShutdownDrainTimeout
+	}
+	return timeout
+}
+
+// drainTimeout returns the configured drain timeout (0 = DefaultShutdownDrainTimeout).
+func (d *AlertDaemon) drainTimeout() time.Duration {
+	if d.shutdownDrainTimeout > 0 {
+		return d.shutdownDrainTimeout
+	}
+	return DefaultShutdownDrainTimeout
+}
+
+// lockClientsForShutdown acquires clientsMu for Stop without hanging on a client that
+// stopped reading.
+//
+// A broadcast blocked in Encode on such a client holds clientsMu.RLock indefinitely.
+// Every connection first gets a write deadline at the end of the grace period, so
+// well-behaved sends finish and stuck ones fail. If the lock is still unavailable
+// after the grace period (e.g. a connection ignores deadlines), all connections are
+// force-closed, which fails every pending write. Connections are reached through
+// d.conns because the clients map itself is behind the lock being waited for.
+func (d *AlertDaemon) lockClientsForShutdown() {
+	timeout := d.drainTimeout()
+	deadline := time.Now().Add(timeout)
+	d.conns.Range(func(key, _ any) bool {
+		key.(net.Conn).SetWriteDeadline(deadline)
+		return true
+	})
+
+	locked := make(chan struct{})
+	go func() {
+		d.clientsMu.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		return
+	case <-time.After(timeout):
+	}
+
+	forced := 0
+	d.conns.Range(func(key, _ any) bool {
+		key.(net.Conn).Close()
+		forced++
+		return true
+	})
+	debug.Log("DAEMON_SHUTDOWN_DRAIN_TIMEOUT timeout=%v force_closed=%d", timeout, forced)
+	fmt.Fprintf(os.Stderr, "WARNING: Clients did not drain within %v, force-closed %d connection(s)\n", timeout, forced)
+	<-locked
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// deadlineIgnoringConn never applies write deadlines, like a connection type that
+// doesn't support them, so only a force-close can unblock its writers.
+type deadlineIgnoringConn struct {
+	net.Conn
+}
+
+func (c deadlineIgnoringConn) SetWriteDeadline(time.Time) error { return nil }
+
+// newStuckClientDaemon returns a daemon with one registered client that never reads.
+func newStuckClientDaemon(t *testing.T, conn net.Conn) *AlertDaemon {
+	t.Helper()
+	d := &AlertDaemon{
+		clients:              map[string]*clientConnection{"stuck": {conn: conn, encoder: json.NewEncoder(conn)}},
+		alerts:               make(map[string]string),
+		blockedBranches:      make(BlockMap),
+		socketPath:           filepath.Join(t.TempDir(), "daemon.sock"),
+		done:                 make(chan struct{}),
+		shutdownDrainTimeout: 100 * time.Millisecond,
+	}
+	d.lastBroadcastError.Store("")
+	d.conns.Store(conn, struct{}{})
+	return d
+}
+
+// stopWithin runs d.Stop while a broadcast is blocked on the stuck client and fails
+// unless both return within limit.
+func stopWithin(t *testing.T, d *AlertDaemon, limit time.Duration) {
+	t.Helper()
+	broadcastDone := make(chan struct{})
+	go func() {
+		d.broadcast(Message{Type: MsgTypePong}) // blocks: the client never reads
+		close(broadcastDone)
+	}()
+	time.Sleep(50 * time.Millisecond) // let the broadcast take clientsMu and block
+
+	stopped := make(chan error, 1)
+	start := time.Now()
+	go func() { stopped <- d.Stop() }()
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("Stop returned error: %v", err)
+		}
+		t.Logf("Stop returned after %v", time.Since(start))
+	case <-time.After(limit):
+		t.Fatalf("Stop did not return within %v with a blocked client", limit)
+	}
+	select {
+	case <-broadcastDone:
+	case <-time.After(limit):
+		t.Fatal("Blocked broadcast was not released by Stop")
+	}
+}
+
+func TestStop_BlockedClientWriteDeadline(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	stopWithin(t, newStuckClientDaemon(t, serverConn), time.Second)
+}
+
+func TestStop_ForceClosesAfterDrainTimeout(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	stopWithin(t, newStuckClientDaemon(t, deadlineIgnoringConn{serverConn}), time.Second)
+}
+
+func TestShutdownDrainTimeoutFromEnv(t *testing.T) {
+	t.Setenv(shutdownDrainTimeoutEnv, "750ms")
+	if got := shutdownDrainTimeoutFromEnv(); got != 750*time.Millisecond {
+		t.Errorf("Expected 750ms, got %v", got)
+	}
+	t.Setenv(shutdownDrainTimeoutEnv, "soon")
+	if got := shutdownDrainTimeoutFromEnv(); got != DefaultShutdownDrainTimeout {
+		t.Errorf("Expected default for invalid value, got %v", got)
+	}
+}
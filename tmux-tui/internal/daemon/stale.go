@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/commons-systems/tmux-tui/internal/debug"
+	"github.com/commons-systems/tmux-tui/internal/tmux"
+	"github.com/commons-systems/tmux-tui/internal/watcher"
+)
+
+// Runtime files a daemon leaves in its namespace directory. They describe a running tmux
+// server and daemon, so they are meaningless once both are gone. Blocked-branch state is
+// not among them: blocks are keyed by branch and outlive a tmux server restart.
+const (
+	daemonSocketFile = "daemon.sock"
+	daemonPIDFile    = "daemon.pid"
+	alertFilePrefix  = "tui-alert-"
+)
+
+// tmuxServerGone reports whether tmux says no server is listening on the named socket
+// (tmux -L name). Any other outcome, including tmux not being installed, counts as alive
+// so cleanup never removes files of a server that might still be running.
+func tmuxServerGone(socketName string) bool {
+	bin, err := tmux.LocateExecutable()
+	if err != nil {
+		return false
+	}
+	output, err := exec.Command(bin, "-L", socketName, "has-session").CombinedOutput()
+	if err == nil {
+		return false
+	}
+	msg := string(output)
+	return strings.Contains(msg, "no server running") || strings.Contains(msg, "error connecting to")
+}
+
+// daemonSocketStale reports whether no daemon answers on socketPath: the socket is
+// missing or refuses connections.
+func daemonSocketStale(socketPath string) bool {
+	conn, err := net.DialTimeout("unix", socketPath, 500*time.Millisecond)
+	if err != nil {
+		return true
+	}
+	conn.Close()
+	return false
+}
+
+// cleanStaleNamespace removes the runtime files left in dir when its tmux server is gone
+// (serverGone is called with the socket name, dir's base name) and no daemon answers on
+// its socket. It reports whether it cleaned anything.
+//
+// Only a daemon's own namespace is cleaned, with its daemon lock held: other namespaces
+// can't be matched to a tmux server reliably, since a server started with -S need not be
+// reachable by the base name of its directory.
+func cleanStaleNamespace(dir string, serverGone func(socketName string) bool) (bool, error) {
+	if !daemonSocketStale(filepath.Join(dir, daemonSocketFile)) || !serverGone(filepath.Base(dir)) {
+		return false, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to read namespace directory %s: %w", dir, err)
+	}
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case name == daemonSocketFile, name == daemonPIDFile, name == watcher.PaneFocusFile,
+			strings.HasPrefix(name, alertFilePrefix):
+		default:
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	debug.Log("DAEMON_STALE_NAMESPACE_CLEANED dir=%s errors=%d", dir, len(errs))
+	return true, errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeNamespaceFiles creates each named file in dir.
+func writeNamespaceFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func namespaceFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func serverGone(string) bool  { return true }
+func serverAlive(string) bool { return false }
+
+func TestCleanStaleNamespace_Own(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dead-server")
+	writeNamespaceFiles(t, dir, "daemon.sock", "daemon.pid", "daemon.lock", "pane-focus",
+		"tui-alert-%1", "tui-blocked-branches.json", "tui-blocked-branches-expiries.json")
+
+	if cleaned, err := cleanStaleNamespace(dir, serverAlive); cleaned || err != nil {
+		t.Fatalf("cleanStaleNamespace() with a live server = %v, %v; want no cleanup", cleaned, err)
+	}
+	if got := namespaceFiles(t, dir); len(got) != 7 {
+		t.Errorf("Files of a live server's namespace were removed: %v", got)
+	}
+
+	cleaned, err := cleanStaleNamespace(dir, serverGone)
+	if !cleaned || err != nil {
+		t.Fatalf("cleanStaleNamespace() = %v, %v; want cleanup", cleaned, err)
+	}
+	want := []string{"daemon.lock", "tui-blocked-branches-expiries.json", "tui-blocked-branches.json"}
+	if got := namespaceFiles(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("Files after cleanup = %v, want %v (blocks and the lock kept)", got, want)
+	}
+}
+
+func TestCleanStaleNamespace_LiveDaemon(t *testing.T) {
+	// Unix socket paths are limited to ~100 bytes, too short for t.TempDir()
+	dir, err := os.MkdirTemp("", "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	listener, err := net.Listen("unix", filepath.Join(dir, "daemon.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	writeNamespaceFiles(t, dir, "pane-focus")
+
+	if cleaned, _ := cleanStaleNamespace(dir, serverGone); cleaned {
+		t.Error("A namespace whose daemon answers on its socket must not be cleaned")
+	}
+	if got := namespaceFiles(t, dir); len(got) != 2 {
+		t.Errorf("Files of a live daemon's namespace were removed: %v", got)
+	}
+}
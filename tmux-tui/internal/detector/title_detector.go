@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -105,6 +106,12 @@ func (d *TitleDetector) pollPaneTitles() {
 func (d *TitleDetector) checkAllPanes() {
 	// Get the current tree of panes
 	tree, err := d.collector.GetTree()
+	var partial *tmux.PartialTreeError
+	if errors.As(err, &partial) {
+		// Check the panes that could be read; the daemon reports the missing ones
+		debug.Log("TITLE_DETECTOR_PARTIAL_TREE failed=%d total=%d error=%v", partial.Failed, partial.Total, err)
+		err = nil
+	}
 	if err != nil {
 		// Emit error event
 		select {
@@ -0,0 +1,49 @@
+// Package jsonout provides the shared JSON output path for tmux-tui commands.
+// Every command that emits JSON uses Write so that --json and --json-pretty
+// behave identically everywhere.
+package jsonout
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// Options holds the JSON output flags registered by RegisterFlags.
+type Options struct {
+	JSON   bool // Emit JSON instead of human-readable text
+	Pretty bool // Indent JSON output; implies JSON
+}
+
+// RegisterFlags adds --json and --json-pretty to fs.
+// Output defaults to compact single-line JSON, which is what pipes and scripts expect.
+func RegisterFlags(fs *flag.FlagSet) *Options {
+	opts := &Options{}
+	fs.BoolVar(&opts.JSON, "json", false, "Output compact JSON (one line)")
+	fs.BoolVar(&opts.Pretty, "json-pretty", false, "Output indented JSON (implies --json)")
+	return opts
+}
+
+// Enabled reports whether JSON output was requested.
+func (o *Options) Enabled() bool {
+	return o.JSON || o.Pretty
+}
+
+// Write encodes v to w as a single JSON document followed by a newline.
+// Compact output is one line; pretty output uses two-space indentation.
+func (o *Options) Write(w io.Writer, v any) error {
+	return Write(w, v, o.Pretty)
+}
+
+// Write encodes v to w as a single JSON document followed by a newline.
+func Write(w io.Writer, v any, pretty bool) error {
+	encoder := json.NewEncoder(w)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
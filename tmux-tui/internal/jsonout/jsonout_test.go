@@ -0,0 +1,80 @@
+package jsonout
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type sample struct {
+	Name   string            `json:"name"`
+	Count  int               `json:"count"`
+	Panes  []string          `json:"panes"`
+	Labels map[string]string `json:"labels"`
+}
+
+func TestWrite_CompactAndPrettyParseIdentically(t *testing.T) {
+	value := sample{
+		Name:   "main",
+		Count:  3,
+		Panes:  []string{"%1", "%2", "%3"},
+		Labels: map[string]string{"branch": "feature/x"},
+	}
+
+	var compact, pretty bytes.Buffer
+	if err := Write(&compact, value, false); err != nil {
+		t.Fatalf("Compact write failed: %v", err)
+	}
+	if err := Write(&pretty, value, true); err != nil {
+		t.Fatalf("Pretty write failed: %v", err)
+	}
+
+	if lines := strings.Count(compact.String(), "\n"); lines != 1 {
+		t.Errorf("Expected compact output on one line, got %d lines: %q", lines, compact.String())
+	}
+	if !strings.Contains(pretty.String(), "\n  \"name\"") {
+		t.Errorf("Expected pretty output to be indented, got: %q", pretty.String())
+	}
+
+	var fromCompact, fromPretty any
+	if err := json.Unmarshal(compact.Bytes(), &fromCompact); err != nil {
+		t.Fatalf("Compact output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(pretty.Bytes(), &fromPretty); err != nil {
+		t.Fatalf("Pretty output is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(fromCompact, fromPretty) {
+		t.Errorf("Compact and pretty outputs differ:\ncompact: %v\npretty:  %v", fromCompact, fromPretty)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	tests := []struct {
+		args        []string
+		wantEnabled bool
+		wantPretty  bool
+	}{
+		{nil, false, false},
+		{[]string{"--json"}, true, false},
+		{[]string{"--json-pretty"}, true, true},
+		{[]string{"--json", "--json-pretty"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			opts := RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if opts.Enabled() != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", opts.Enabled(), tt.wantEnabled)
+			}
+			if opts.Pretty != tt.wantPretty {
+				t.Errorf("Pretty = %v, want %v", opts.Pretty, tt.wantPretty)
+			}
+		})
+	}
+}
@@ -69,6 +69,16 @@ func BlockedBranchesFile() string {
 	return filepath.Join(GetSessionNamespace(), "tui-blocked-branches.json")
 }
 
+// KeyBindingsFile returns the path to the TUI key bindings JSON file for this session.
+func KeyBindingsFile() string {
+	return filepath.Join(GetSessionNamespace(), "tui-keybindings.json")
+}
+
+// ThemeFile returns the path to the TUI color theme JSON file for this session.
+func ThemeFile() string {
+	return filepath.Join(GetSessionNamespace(), "tui-theme.json")
+}
+
 // DaemonLockFile returns the path to the daemon lock file for this session.
 func DaemonLockFile() string {
 	return filepath.Join(GetSessionNamespace(), "daemon.lock")
@@ -1,21 +1,57 @@
 package tmux
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/debug"
 )
 
+// DefaultTreeCacheTTL is how long GetTree reuses its last result. It covers bursts
+// such as a state change immediately followed by a refresh, without letting the
+// 500ms title poll or the 30s tree tick see noticeably stale data.
+const DefaultTreeCacheTTL = 500 * time.Millisecond
+
+// PartialTreeError reports panes left out of an otherwise usable tree, such as a pane
+// that closed while tmux was listing it. GetTree returns it together with the tree of
+// the panes it could read; callers should use that tree and treat this as a warning.
+type PartialTreeError struct {
+	Failed int     // Panes left out of the tree
+	Total  int     // Panes tmux listed, not counting the TUI's own
+	Errs   []error // Why each pane was left out
+}
+
+func (e *PartialTreeError) Error() string {
+	msg := fmt.Sprintf("%d of %d panes could not be read", e.Failed, e.Total)
+	if len(e.Errs) > 0 {
+		msg += ": " + e.Errs[0].Error()
+	}
+	if len(e.Errs) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Errs)-1)
+	}
+	return msg
+}
+
+func (e *PartialTreeError) Unwrap() []error { return e.Errs }
+
 // Collector collects tmux and git information
 type Collector struct {
 	claudeCache *ClaudePaneCache
 	executor    CommandExecutor
+
+	// Tree cache: GetTree returns a clone of the last tree for treeCacheTTL.
+	// treeMu is held for the whole collection, so concurrent callers share one tmux query.
+	treeMu       sync.Mutex
+	treeCacheTTL time.Duration // 0 disables the cache
+	cachedTree   RepoTree
+	cachedAt     time.Time // Zero when nothing is cached
 }
 
 // NewCollector creates a new Collector instance
@@ -25,8 +61,9 @@ func NewCollector() (*Collector, error) {
 		return nil, fmt.Errorf("failed to create cache: %w", err)
 	}
 	return &Collector{
-		claudeCache: cache,
-		executor:    &RealCommandExecutor{},
+		claudeCache:  cache,
+		executor:     &RealCommandExecutor{},
+		treeCacheTTL: DefaultTreeCacheTTL,
 	}, nil
 }
 
@@ -37,13 +74,58 @@ func NewCollectorWithExecutor(executor CommandExecutor) (*Collector, error) {
 		return nil, fmt.Errorf("failed to create cache: %w", err)
 	}
 	return &Collector{
-		claudeCache: cache,
-		executor:    executor,
+		claudeCache:  cache,
+		executor:     executor,
+		treeCacheTTL: DefaultTreeCacheTTL,
 	}, nil
 }
 
-// GetTree collects all panes from the current tmux session and organizes them into a RepoTree
+// GetTree collects all panes from the current tmux session and organizes them into a RepoTree.
+// Calls within DefaultTreeCacheTTL of the last successful collection reuse its result
+// instead of running tmux again; use ForceRefresh when fresh data is required.
+// Errors are never cached. Safe for concurrent use.
+//
+// Panes that can't be read are skipped: the tree of the remaining panes is returned with
+// a *PartialTreeError. Any other error means no tree could be collected.
 func (c *Collector) GetTree() (RepoTree, error) {
+	c.treeMu.Lock()
+	defer c.treeMu.Unlock()
+
+	if !c.cachedAt.IsZero() && time.Since(c.cachedAt) < c.treeCacheTTL {
+		debug.Log("COLLECTOR_TREE_CACHE_HIT age=%v", time.Since(c.cachedAt))
+		return c.cachedTree.Clone(), nil
+	}
+	return c.refreshLocked()
+}
+
+// ForceRefresh collects the tree from tmux, bypassing the cache, and caches the result
+// for subsequent GetTree calls.
+func (c *Collector) ForceRefresh() (RepoTree, error) {
+	c.treeMu.Lock()
+	defer c.treeMu.Unlock()
+	return c.refreshLocked()
+}
+
+// refreshLocked collects the tree and updates the cache. Callers must hold treeMu.
+// The cache keeps its own clone, so callers may modify the returned tree. Partial trees
+// aren't cached, so the next call retries the panes that failed.
+func (c *Collector) refreshLocked() (RepoTree, error) {
+	tree, err := c.collectTree()
+	var partial *PartialTreeError
+	if errors.As(err, &partial) {
+		return tree, err
+	}
+	if err != nil {
+		return RepoTree{}, err
+	}
+	c.cachedTree = tree.Clone()
+	c.cachedAt = time.Now()
+	return tree, nil
+}
+
+// collectTree queries tmux and git for the current panes. Panes that fail are skipped
+// and reported in a *PartialTreeError returned with the tree.
+func (c *Collector) collectTree() (RepoTree, error) {
 	// Get the current session from TMUX environment variable
 	tmuxEnv := os.Getenv("TMUX")
 	if tmuxEnv == "" {
@@ -65,6 +147,7 @@ func (c *Collector) GetTree() (RepoTree, error) {
 	// Track valid pane PIDs for cache cleanup
 	validPIDs := make(map[string]bool)
 
+	partial := &PartialTreeError{}
 	for _, line := range lines {
 		if line == "" {
 			continue
@@ -72,6 +155,11 @@ func (c *Collector) GetTree() (RepoTree, error) {
 
 		parts := strings.Split(line, "|")
 		if len(parts) != 10 {
+			// A pane closing mid-listing can leave a truncated line
+			partial.Total++
+			partial.Failed++
+			partial.Errs = append(partial.Errs, fmt.Errorf("malformed pane line %q: %d fields, want 10", line, len(parts)))
+			debug.Log("COLLECTOR_PANE_MALFORMED line=%q fields=%d", line, len(parts))
 			continue
 		}
 
@@ -91,6 +179,7 @@ func (c *Collector) GetTree() (RepoTree, error) {
 		if command == "tmux-tui" {
 			continue
 		}
+		partial.Total++
 
 		// Track this PID as valid
 		if panePID != "" {
@@ -122,6 +211,8 @@ func (c *Collector) GetTree() (RepoTree, error) {
 		if err != nil {
 			// Log validation error but continue processing other panes
 			debug.Log("COLLECTOR_PANE_VALIDATION_ERROR paneID=%s error=%v", paneID, err)
+			partial.Failed++
+			partial.Errs = append(partial.Errs, fmt.Errorf("pane %s: %w", paneID, err))
 			continue
 		}
 		tempMap[repo][branch] = append(tempMap[repo][branch], pane)
@@ -129,7 +220,7 @@ func (c *Collector) GetTree() (RepoTree, error) {
 
 	// Clean up cache entries for panes that no longer exist.
 	// This is the primary mechanism preventing unbounded cache growth,
-	// called on every GetTree() invocation (typically every 30s).
+	// called on every tree collection (cache hits skip it).
 	// CleanupExcept() removes both invalid PIDs and expired entries.
 	c.claudeCache.CleanupExcept(validPIDs)
 
@@ -156,14 +247,21 @@ func (c *Collector) GetTree() (RepoTree, error) {
 		}
 	}
 
+	if partial.Failed > 0 {
+		debug.Log("COLLECTOR_PARTIAL_TREE failed=%d total=%d", partial.Failed, partial.Total)
+		return tree, partial
+	}
 	return tree, nil
 }
 
-// ClearCache clears the Claude pane detection cache, forcing fresh checks on next GetTree.
-// Call this when external events suggest cached data may be stale (e.g., receiving
-// an alert for a pane that might have just started running Claude).
+// ClearCache clears the Claude pane detection cache and the cached tree, forcing fresh
+// checks on next GetTree. Call this when external events suggest cached data may be stale
+// (e.g., receiving an alert for a pane that might have just started running Claude).
 func (c *Collector) ClearCache() {
 	c.claudeCache.Clear()
+	c.treeMu.Lock()
+	c.cachedAt = time.Time{}
+	c.treeMu.Unlock()
 }
 
 // isClaudePane checks if the pane is running Claude by inspecting child processes
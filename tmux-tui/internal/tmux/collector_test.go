@@ -1,10 +1,14 @@
 package tmux
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/commons-systems/tmux-tui/internal/tmux/testutil"
 )
@@ -90,6 +94,131 @@ func TestCollectorGetTree(t *testing.T) {
 	}
 }
 
+// TestCollectorGetTree_Cache verifies rapid GetTree calls share one tmux query, while
+// ForceRefresh, ClearCache, expiry and errors all lead to a fresh query.
+func TestCollectorGetTree_Cache(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-test,1234,0")
+
+	var listCalls atomic.Int32
+	var fail atomic.Bool
+	mockExec := &testutil.MockCommandExecutor{
+		CustomHandlers: map[string]func([]string) ([]byte, error){
+			"tmux": func(args []string) ([]byte, error) {
+				listCalls.Add(1)
+				if fail.Load() {
+					return nil, fmt.Errorf("no server running")
+				}
+				return []byte("%1|@1|0|zsh|1|0|/home/user/repo1|zsh|host.local|1001\n"), nil
+			},
+		},
+	}
+	collector, err := NewCollectorWithExecutor(mockExec)
+	if err != nil {
+		t.Fatalf("NewCollectorWithExecutor failed: %v", err)
+	}
+	collector.treeCacheTTL = time.Hour // Expiry is simulated below
+
+	expectCalls := func(want int32, step string) {
+		t.Helper()
+		if got := listCalls.Load(); got != want {
+			t.Errorf("%s: tmux ran %d times, want %d", step, got, want)
+		}
+	}
+
+	// Concurrent callers share a single query
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := collector.GetTree(); err != nil {
+				t.Errorf("GetTree failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	expectCalls(1, "concurrent GetTree")
+
+	// Callers get their own copy: changes don't leak into the cache
+	tree, _ := collector.GetTree()
+	if err := tree.SetPanes("unknown", "unknown", nil); err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := collector.GetTree(); cached.TotalPanes() != 1 {
+		t.Errorf("Modifying a returned tree changed the cache: %d panes, want 1", cached.TotalPanes())
+	}
+	expectCalls(1, "cache hits")
+
+	if _, err := collector.ForceRefresh(); err != nil {
+		t.Fatalf("ForceRefresh failed: %v", err)
+	}
+	expectCalls(2, "ForceRefresh")
+
+	collector.ClearCache()
+	collector.GetTree()
+	expectCalls(3, "GetTree after ClearCache")
+
+	collector.treeMu.Lock()
+	collector.cachedAt = time.Now().Add(-2 * time.Hour)
+	collector.treeMu.Unlock()
+	collector.GetTree()
+	expectCalls(4, "GetTree after expiry")
+
+	// Errors are returned by ForceRefresh and never cached
+	fail.Store(true)
+	if _, err := collector.ForceRefresh(); err == nil {
+		t.Error("ForceRefresh should return the tmux error")
+	}
+	fail.Store(false)
+	if tree, err := collector.GetTree(); err != nil || tree.TotalPanes() != 1 {
+		t.Errorf("GetTree after a failed refresh = %d panes, %v; want the cached tree", tree.TotalPanes(), err)
+	}
+	expectCalls(5, "GetTree after a failed refresh")
+}
+
+func TestCollectorGetTree_PartialFailure(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-test,1234,0")
+
+	var listCalls atomic.Int32
+	mockExec := &testutil.MockCommandExecutor{
+		CustomHandlers: map[string]func([]string) ([]byte, error){
+			"tmux": func(args []string) ([]byte, error) {
+				listCalls.Add(1)
+				// The second pane closed while tmux was listing it
+				return []byte("%1|@1|0|zsh|1|0|/home/user/repo1|zsh|host.local|1001\n" +
+					"%2|@1|0|zsh\n" +
+					"%3|@1|1|zsh|0|0|/home/user/repo1|zsh|host.local|1003\n"), nil
+			},
+		},
+	}
+	collector, err := NewCollectorWithExecutor(mockExec)
+	if err != nil {
+		t.Fatalf("NewCollectorWithExecutor failed: %v", err)
+	}
+	collector.treeCacheTTL = time.Hour
+
+	tree, err := collector.GetTree()
+	var partial *PartialTreeError
+	if !errors.As(err, &partial) {
+		t.Fatalf("GetTree error = %v, want a *PartialTreeError", err)
+	}
+	if partial.Failed != 1 || partial.Total != 3 {
+		t.Errorf("PartialTreeError = %d of %d failed, want 1 of 3", partial.Failed, partial.Total)
+	}
+	if !strings.Contains(err.Error(), "1 of 3 panes could not be read") {
+		t.Errorf("Error() = %q, want a failure count", err.Error())
+	}
+	if tree.TotalPanes() != 2 {
+		t.Errorf("Partial tree has %d panes, want the 2 readable ones", tree.TotalPanes())
+	}
+
+	// Partial trees aren't cached, so the next call retries
+	collector.GetTree()
+	if got := listCalls.Load(); got != 2 {
+		t.Errorf("tmux ran %d times, want 2 (partial tree must not be cached)", got)
+	}
+}
+
 func TestCollectorExcludesPane(t *testing.T) {
 	// Set TMUX environment variable for test
 	os.Setenv("TMUX", "/tmp/tmux-test,1234,0")
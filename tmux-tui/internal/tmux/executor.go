@@ -13,15 +13,36 @@ type CommandExecutor interface {
 	ExecCommandOutput(name string, args ...string) ([]byte, error)
 }
 
-// RealCommandExecutor implements CommandExecutor using exec.Command
+// RealCommandExecutor implements CommandExecutor using exec.Command.
+// "tmux" is resolved with LocateExecutable, so $TMUX_BIN applies to every caller.
 type RealCommandExecutor struct{}
 
 func (r *RealCommandExecutor) ExecCommand(name string, args ...string) ([]byte, error) {
-	return exec.Command(name, args...).CombinedOutput()
+	cmd, err := command(name, args...)
+	if err != nil {
+		return nil, err
+	}
+	return cmd.CombinedOutput()
 }
 
 func (r *RealCommandExecutor) ExecCommandOutput(name string, args ...string) ([]byte, error) {
-	return exec.Command(name, args...).Output()
+	cmd, err := command(name, args...)
+	if err != nil {
+		return nil, err
+	}
+	return cmd.Output()
+}
+
+// command builds an exec.Cmd, resolving the tmux executable by name.
+func command(name string, args ...string) (*exec.Cmd, error) {
+	if name == "tmux" {
+		path, err := LocateExecutable()
+		if err != nil {
+			return nil, err
+		}
+		name = path
+	}
+	return exec.Command(name, args...), nil
 }
 
 // GitError represents a git command error for testing
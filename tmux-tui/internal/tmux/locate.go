@@ -0,0 +1,79 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// TmuxBinEnv overrides tmux executable discovery with a path or command name.
+const TmuxBinEnv = "TMUX_BIN"
+
+// ErrTmuxNotFound is returned by LocateExecutable when no tmux executable is found.
+var ErrTmuxNotFound = errors.New("tmux executable not found")
+
+// knownTmuxLocations are checked when tmux is not on PATH, e.g. when launched from a
+// GUI app or a service with a minimal environment.
+var knownTmuxLocations = []string{
+	"/opt/homebrew/bin/tmux",
+	"/usr/local/bin/tmux",
+	"/usr/bin/tmux",
+	"/run/current-system/sw/bin/tmux",
+	"/nix/var/nix/profiles/default/bin/tmux",
+}
+
+var (
+	locateMu    sync.Mutex
+	locatedTmux string
+)
+
+// LocateExecutable returns the path of the tmux executable. It checks $TMUX_BIN, then
+// PATH, then well-known install locations. A successful result is cached for the life
+// of the process; failures are not, so installing tmux later is picked up.
+// Errors wrap ErrTmuxNotFound.
+func LocateExecutable() (string, error) {
+	locateMu.Lock()
+	defer locateMu.Unlock()
+
+	if locatedTmux != "" {
+		return locatedTmux, nil
+	}
+	path, err := locateExecutable(os.Getenv(TmuxBinEnv), exec.LookPath, knownTmuxLocations)
+	if err != nil {
+		return "", err
+	}
+	locatedTmux = path
+	return path, nil
+}
+
+// resetLocateCache forgets the cached tmux path. Used by tests.
+func resetLocateCache() {
+	locateMu.Lock()
+	defer locateMu.Unlock()
+	locatedTmux = ""
+}
+
+// locateExecutable implements LocateExecutable. lookPath is exec.LookPath outside tests.
+func locateExecutable(override string, lookPath func(string) (string, error), known []string) (string, error) {
+	if override = strings.TrimSpace(override); override != "" {
+		// An explicit override that doesn't work is an error, not a reason to guess
+		path, err := lookPath(override)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s=%q is not an executable: %v", ErrTmuxNotFound, TmuxBinEnv, override, err)
+		}
+		return path, nil
+	}
+
+	if path, err := lookPath("tmux"); err == nil {
+		return path, nil
+	}
+	for _, candidate := range known {
+		if path, err := lookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w on PATH or in %s; set %s to its path", ErrTmuxNotFound, strings.Join(known, ", "), TmuxBinEnv)
+}
@@ -0,0 +1,84 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocateExecutable_Order(t *testing.T) {
+	// onPath returns a lookPath that only finds the given names
+	onPath := func(found ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, f := range found {
+				if f == name {
+					if filepath.IsAbs(name) {
+						return name, nil
+					}
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	known := []string{"/opt/homebrew/bin/tmux", "/run/current-system/sw/bin/tmux"}
+
+	tests := []struct {
+		name     string
+		override string
+		lookPath func(string) (string, error)
+		want     string
+		wantErr  bool
+	}{
+		{"override wins over PATH", "/nix/store/abc-tmux/bin/tmux", onPath("tmux", "/nix/store/abc-tmux/bin/tmux"),
+			"/nix/store/abc-tmux/bin/tmux", false},
+		{"override by name", "tmux-next", onPath("tmux", "tmux-next"), "/usr/bin/tmux-next", false},
+		{"broken override does not fall back", "/missing/tmux", onPath("tmux"), "", true},
+		{"PATH", "", onPath("tmux", "/opt/homebrew/bin/tmux"), "/usr/bin/tmux", false},
+		{"known location", "", onPath("/run/current-system/sw/bin/tmux"), "/run/current-system/sw/bin/tmux", false},
+		{"not found", "", onPath(), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := locateExecutable(tt.override, tt.lookPath, known)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrTmuxNotFound) {
+				t.Errorf("error %v does not wrap ErrTmuxNotFound", err)
+			}
+			if got != tt.want {
+				t.Errorf("locateExecutable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealCommandExecutor_UsesTmuxBin(t *testing.T) {
+	fake := filepath.Join(t.TempDir(), "fake-tmux")
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\necho \"fake $@\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(TmuxBinEnv, fake)
+	resetLocateCache()
+	t.Cleanup(resetLocateCache)
+
+	output, err := (&RealCommandExecutor{}).ExecCommandOutput("tmux", "list-panes")
+	if err != nil {
+		t.Fatalf("ExecCommandOutput failed: %v", err)
+	}
+	if got := string(output); got != "fake list-panes\n" {
+		t.Errorf("Output = %q, want the fake tmux's output", got)
+	}
+
+	// The result is cached: changing the environment has no effect until reset
+	t.Setenv(TmuxBinEnv, "/missing/tmux")
+	if path, err := LocateExecutable(); err != nil || path != fake {
+		t.Errorf("LocateExecutable() = %q, %v, want cached %q", path, err, fake)
+	}
+	resetLocateCache()
+	if _, err := LocateExecutable(); !errors.Is(err, ErrTmuxNotFound) {
+		t.Errorf("LocateExecutable() with a broken override = %v, want ErrTmuxNotFound", err)
+	}
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Action is something the user can trigger from the keyboard.
+type Action string
+
+const (
+	ActionPickerUp     Action = "picker_up"     // Move the branch picker selection up
+	ActionPickerDown   Action = "picker_down"   // Move the branch picker selection down
+	ActionPickerSelect Action = "picker_select" // Block with the selected branch
+	ActionPickerCancel Action = "picker_cancel" // Close the branch picker without blocking
+	ActionQuit         Action = "quit"          // Exit tmux-tui
+)
+
+// keyScope is where an action's keys are active. Keys only conflict within a scope.
+type keyScope string
+
+const (
+	scopePicker keyScope = "picker" // While the branch picker is open
+	scopeTree   keyScope = "tree"   // While the tree is shown
+)
+
+var actionScopes = map[Action]keyScope{
+	ActionPickerUp:     scopePicker,
+	ActionPickerDown:   scopePicker,
+	ActionPickerSelect: scopePicker,
+	ActionPickerCancel: scopePicker,
+	ActionQuit:         scopeTree,
+}
+
+// defaultBindings are the keys used when the config file doesn't rebind an action.
+// Keys are named as bubbletea's KeyMsg.String() reports them.
+var defaultBindings = map[Action][]string{
+	ActionPickerUp:     {"up", "ctrl+p"},
+	ActionPickerDown:   {"down", "ctrl+n"},
+	ActionPickerSelect: {"enter"},
+	ActionPickerCancel: {"esc"},
+	ActionQuit:         {"ctrl+c"},
+}
+
+// KeyMap resolves key presses to actions. Create with DefaultKeyMap or LoadKeyMap.
+type KeyMap struct {
+	bindings map[Action][]string
+	actions  map[keyScope]map[string]Action // Reverse index for lookups
+}
+
+// DefaultKeyMap returns the built-in key bindings.
+func DefaultKeyMap() KeyMap {
+	keyMap, err := newKeyMap(defaultBindings)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default key bindings: %v", err))
+	}
+	return keyMap
+}
+
+// LoadKeyMap reads key bindings from a JSON file mapping action names to lists of keys,
+// e.g. {"picker_up": ["up", "ctrl+k"]}. Actions the file doesn't mention keep their
+// default keys. A missing file is not an error and gives the defaults.
+//
+// The file is rejected as a whole if it names an unknown action, leaves an action without
+// keys, binds a picker action to a printable key (typing filters the picker), or binds
+// one key to two actions that are active at the same time.
+func LoadKeyMap(path string) (KeyMap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultKeyMap(), nil
+	}
+	if err != nil {
+		return DefaultKeyMap(), fmt.Errorf("failed to read key bindings: %w", err)
+	}
+	keyMap, err := ParseKeyMap(data)
+	if err != nil {
+		return DefaultKeyMap(), fmt.Errorf("invalid key bindings in %s: %w", path, err)
+	}
+	return keyMap, nil
+}
+
+// ParseKeyMap applies the JSON bindings in data on top of the defaults.
+// See LoadKeyMap for the format and validation rules.
+func ParseKeyMap(data []byte) (KeyMap, error) {
+	var overrides map[Action][]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return KeyMap{}, fmt.Errorf("malformed JSON: %w", err)
+	}
+
+	bindings := make(map[Action][]string, len(defaultBindings))
+	for action, keys := range defaultBindings {
+		bindings[action] = keys
+	}
+	for action, keys := range overrides {
+		if _, ok := actionScopes[action]; !ok {
+			return KeyMap{}, fmt.Errorf("unknown action %q (valid: %s)", action, strings.Join(actionNames(), ", "))
+		}
+		bindings[action] = keys
+	}
+	return newKeyMap(bindings)
+}
+
+// newKeyMap validates bindings and builds the lookup index.
+func newKeyMap(bindings map[Action][]string) (KeyMap, error) {
+	keyMap := KeyMap{
+		bindings: make(map[Action][]string, len(bindings)),
+		actions:  make(map[keyScope]map[string]Action),
+	}
+
+	// Sorted so the reported conflict doesn't depend on map order
+	for _, action := range sortedActions(bindings) {
+		keys := bindings[action]
+		if len(keys) == 0 {
+			return KeyMap{}, fmt.Errorf("action %q has no keys", action)
+		}
+		scope := actionScopes[action]
+		if keyMap.actions[scope] == nil {
+			keyMap.actions[scope] = make(map[string]Action)
+		}
+		for _, key := range keys {
+			if key == "" {
+				return KeyMap{}, fmt.Errorf("action %q has an empty key", action)
+			}
+			if scope == scopePicker && isTypedKey(key) {
+				return KeyMap{}, fmt.Errorf("action %q cannot use %q: typed characters filter the branch picker", action, key)
+			}
+			if other, taken := keyMap.actions[scope][key]; taken && other != action {
+				return KeyMap{}, fmt.Errorf("key %q is bound to both %q and %q", key, other, action)
+			}
+			keyMap.actions[scope][key] = action
+		}
+		keyMap.bindings[action] = append([]string(nil), keys...)
+	}
+	return keyMap, nil
+}
+
+// PickerAction returns the action bound to key while the branch picker is open.
+func (k KeyMap) PickerAction(key string) (Action, bool) {
+	action, ok := k.actions[scopePicker][key]
+	return action, ok
+}
+
+// TreeAction returns the action bound to key while the tree is shown.
+func (k KeyMap) TreeAction(key string) (Action, bool) {
+	action, ok := k.actions[scopeTree][key]
+	return action, ok
+}
+
+// Keys returns the keys bound to action.
+func (k KeyMap) Keys(action Action) []string {
+	return append([]string(nil), k.bindings[action]...)
+}
+
+// isTypedKey reports whether key is a printable character, which the picker treats as
+// filter input (with or without alt). Space is reported as its own key type, so it can
+// still be bound.
+func isTypedKey(key string) bool {
+	key = strings.TrimPrefix(key, "alt+")
+	return utf8.RuneCountInString(key) == 1 && key != " "
+}
+
+func sortedActions(bindings map[Action][]string) []Action {
+	actions := make([]Action, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
+
+func actionNames() []string {
+	names := make([]string, 0, len(actionScopes))
+	for action := range actionScopes {
+		names = append(names, string(action))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeyMap(t *testing.T) {
+	keys := DefaultKeyMap()
+
+	pickerKeys := map[string]Action{
+		"up":     ActionPickerUp,
+		"ctrl+p": ActionPickerUp,
+		"down":   ActionPickerDown,
+		"ctrl+n": ActionPickerDown,
+		"enter":  ActionPickerSelect,
+		"esc":    ActionPickerCancel,
+	}
+	for key, want := range pickerKeys {
+		if got, ok := keys.PickerAction(key); !ok || got != want {
+			t.Errorf("PickerAction(%q) = %q, %v, want %q", key, got, ok, want)
+		}
+	}
+	if got, ok := keys.TreeAction("ctrl+c"); !ok || got != ActionQuit {
+		t.Errorf("TreeAction(ctrl+c) = %q, %v, want quit", got, ok)
+	}
+
+	// Scopes are separate: quit isn't triggered from the picker, nor navigation from the tree
+	if action, ok := keys.PickerAction("ctrl+c"); ok {
+		t.Errorf("PickerAction(ctrl+c) = %q, want no action", action)
+	}
+	if action, ok := keys.TreeAction("up"); ok {
+		t.Errorf("TreeAction(up) = %q, want no action", action)
+	}
+}
+
+func TestParseKeyMap(t *testing.T) {
+	keys, err := ParseKeyMap([]byte(`{"picker_up": ["up", "ctrl+k"], "picker_down": ["ctrl+j"], "quit": ["q", "ctrl+c"]}`))
+	if err != nil {
+		t.Fatalf("ParseKeyMap() error = %v", err)
+	}
+
+	if got := keys.Keys(ActionPickerUp); !reflect.DeepEqual(got, []string{"up", "ctrl+k"}) {
+		t.Errorf("picker_up keys = %v", got)
+	}
+	// Rebinding replaces the defaults
+	if action, ok := keys.PickerAction("down"); ok {
+		t.Errorf("PickerAction(down) = %q, want unbound after rebinding picker_down", action)
+	}
+	if got, _ := keys.PickerAction("ctrl+j"); got != ActionPickerDown {
+		t.Errorf("PickerAction(ctrl+j) = %q, want picker_down", got)
+	}
+	// Unlisted actions keep their defaults
+	if got, _ := keys.PickerAction("esc"); got != ActionPickerCancel {
+		t.Errorf("PickerAction(esc) = %q, want picker_cancel", got)
+	}
+	// Printable keys are fine outside the picker
+	if got, _ := keys.TreeAction("q"); got != ActionQuit {
+		t.Errorf("TreeAction(q) = %q, want quit", got)
+	}
+}
+
+func TestParseKeyMap_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"malformed", `{"picker_up": "k"}`, "malformed JSON"},
+		{"unknown action", `{"scroll": ["j"]}`, `unknown action "scroll"`},
+		{"no keys", `{"picker_select": []}`, `"picker_select" has no keys`},
+		{"empty key", `{"picker_select": [""]}`, "empty key"},
+		{"printable picker key", `{"picker_down": ["j"]}`, `cannot use "j"`},
+		{"alt printable picker key", `{"picker_down": ["alt+j"]}`, `cannot use "alt+j"`},
+		{"conflicting keys", `{"picker_up": ["down"]}`, `key "down" is bound to both`},
+		{"conflict between overrides", `{"picker_select": ["tab"], "picker_cancel": ["tab", "esc"]}`, `key "tab" is bound to both`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKeyMap([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseKeyMap(%s) error = %v, want containing %q", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Space is its own key type, so the picker can use it
+	if _, err := ParseKeyMap([]byte(`{"picker_select": ["enter", " "]}`)); err != nil {
+		t.Errorf("ParseKeyMap() with space error = %v", err)
+	}
+}
+
+func TestLoadKeyMap(t *testing.T) {
+	dir := t.TempDir()
+
+	keys, err := LoadKeyMap(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadKeyMap() with missing file error = %v", err)
+	}
+	if !reflect.DeepEqual(keys, DefaultKeyMap()) {
+		t.Errorf("Missing file should give the default key map")
+	}
+
+	path := filepath.Join(dir, "tui-keybindings.json")
+	if err := os.WriteFile(path, []byte(`{"picker_up": ["ctrl+k"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	keys, err = LoadKeyMap(path)
+	if err != nil {
+		t.Fatalf("LoadKeyMap() error = %v", err)
+	}
+	if got, _ := keys.PickerAction("ctrl+k"); got != ActionPickerUp {
+		t.Errorf("PickerAction(ctrl+k) = %q, want picker_up", got)
+	}
+
+	// Invalid files name the file and still return usable defaults
+	if err := os.WriteFile(path, []byte(`{"picker_up": ["down"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	keys, err = LoadKeyMap(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadKeyMap() error = %v, want one naming %s", err, path)
+	}
+	if !reflect.DeepEqual(keys, DefaultKeyMap()) {
+		t.Errorf("Invalid file should fall back to the default key map")
+	}
+}
@@ -1,62 +1,153 @@
 package ui
 
-import (
-	"strings"
+import "strings"
 
-	"github.com/charmbracelet/lipgloss"
-)
-
-// BranchPicker is a simple interactive picker for selecting branches
+// BranchPicker is a simple interactive picker for selecting branches.
+// Typing narrows the list with Filter; navigation and Selected work on the
+// filtered list. With SetBlockedBranches, branches that are blocked themselves are
+// marked, and branches that would form a block cycle are left out.
 type BranchPicker struct {
-	branches []string
-	selected int
-	width    int
-	height   int
+	branches  []string            // All branches, in display order
+	forBranch string              // Branch being blocked ("" if unknown)
+	blocked   map[string][]string // Blocked branch -> blockers
+	query     string
+	matches   []string // Offered branches matching query
+	selected  int      // Index into matches
+	width     int
+	height    int
+	styles    pickerStyles
 }
 
-var (
-	pickerStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("63")).
-			Padding(1, 2)
-
-	selectedItemStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("0")).
-				Background(lipgloss.Color("63")).
-				Bold(true)
-
-	normalItemStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252"))
-
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("63")).
-			Bold(true).
-			MarginBottom(1)
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			MarginTop(1)
-)
+// blockedMarker follows branches in the list that are blocked themselves
+const blockedMarker = " (blocked)"
 
 // NewBranchPicker creates a new branch picker
 func NewBranchPicker(branches []string, width, height int) *BranchPicker {
 	return &BranchPicker{
 		branches: branches,
+		matches:  branches,
 		selected: 0,
 		width:    width,
 		height:   height,
+		styles:   newPickerStyles(DefaultTheme()),
 	}
 }
 
-// SetBranches updates the list of branches
+// SetTheme updates the colors the picker is drawn with (see LoadTheme)
+func (p *BranchPicker) SetTheme(theme Theme) {
+	p.styles = newPickerStyles(theme)
+}
+
+// SetBranches updates the list of branches, keeping the current filter
 func (p *BranchPicker) SetBranches(branches []string) {
 	p.branches = branches
-	if p.selected >= len(branches) {
-		p.selected = len(branches) - 1
+	p.refilter()
+}
+
+// SetBlockedBranches records the current blocks (blocked branch -> blockers) and the
+// branch being blocked. Branches that are blocked themselves are marked in the list,
+// and branches already blocked by forBranch, directly or through a chain of blocks,
+// are left out: blocking forBranch on one would create a cycle. The order of the
+// remaining branches is unchanged.
+func (p *BranchPicker) SetBlockedBranches(forBranch string, blocked map[string][]string) {
+	p.forBranch = forBranch
+	p.blocked = make(map[string][]string, len(blocked))
+	for branch, blockers := range blocked {
+		p.blocked[branch] = append([]string(nil), blockers...)
+	}
+	p.refilter()
+}
+
+// createsCycle reports whether blocking p.forBranch on candidate would create a block
+// cycle, i.e. whether candidate is already blocked by p.forBranch through a chain of
+// blocks.
+func (p *BranchPicker) createsCycle(candidate string) bool {
+	if p.forBranch == "" {
+		return false
 	}
-	if p.selected < 0 {
-		p.selected = 0
+	seen := map[string]bool{candidate: true}
+	pending := []string{candidate}
+	for len(pending) > 0 {
+		branch := pending[0]
+		pending = pending[1:]
+		for _, blocker := range p.blocked[branch] {
+			if blocker == p.forBranch {
+				return true
+			}
+			if !seen[blocker] {
+				seen[blocker] = true
+				pending = append(pending, blocker)
+			}
+		}
 	}
+	return false
+}
+
+// Filter narrows the list to branches matching query (case-insensitive):
+// substring matches first, then fuzzy matches whose characters appear in order.
+// An empty query shows every branch. The selected branch stays selected if it
+// still matches; otherwise the first match is selected.
+func (p *BranchPicker) Filter(query string) {
+	p.query = query
+	p.refilter()
+}
+
+// Query returns the current filter text
+func (p *BranchPicker) Query() string {
+	return p.query
+}
+
+// refilter recomputes matches for the current query and keeps selected valid
+func (p *BranchPicker) refilter() {
+	previous := p.Selected()
+
+	offered := p.branches
+	if len(p.blocked) > 0 {
+		offered = make([]string, 0, len(p.branches))
+		for _, branch := range p.branches {
+			if !p.createsCycle(branch) {
+				offered = append(offered, branch)
+			}
+		}
+	}
+
+	if p.query == "" {
+		p.matches = offered
+	} else {
+		query := strings.ToLower(p.query)
+		var substring, fuzzy []string
+		for _, branch := range offered {
+			name := strings.ToLower(branch)
+			if strings.Contains(name, query) {
+				substring = append(substring, branch)
+			} else if fuzzyMatch(name, query) {
+				fuzzy = append(fuzzy, branch)
+			}
+		}
+		p.matches = append(substring, fuzzy...)
+	}
+
+	p.selected = 0
+	for i, branch := range p.matches {
+		if branch == previous {
+			p.selected = i
+			break
+		}
+	}
+}
+
+// fuzzyMatch reports whether every rune of query appears in s, in order
+func fuzzyMatch(s, query string) bool {
+	remaining := []rune(query)
+	for _, r := range s {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
 }
 
 // MoveUp moves the selection up
@@ -68,15 +159,15 @@ func (p *BranchPicker) MoveUp() {
 
 // MoveDown moves the selection down
 func (p *BranchPicker) MoveDown() {
-	if p.selected < len(p.branches)-1 {
+	if p.selected < len(p.matches)-1 {
 		p.selected++
 	}
 }
 
-// Selected returns the currently selected branch
+// Selected returns the currently selected branch, or "" if nothing matches
 func (p *BranchPicker) Selected() string {
-	if p.selected >= 0 && p.selected < len(p.branches) {
-		return p.branches[p.selected]
+	if p.selected >= 0 && p.selected < len(p.matches) {
+		return p.matches[p.selected]
 	}
 	return ""
 }
@@ -84,28 +175,41 @@ func (p *BranchPicker) Selected() string {
 // Render renders the picker as a string
 func (p *BranchPicker) Render() string {
 	if len(p.branches) == 0 {
-		return pickerStyle.Render("No branches available")
+		return p.styles.picker.Render("No branches available")
 	}
 
 	var lines []string
 
 	// Title (fit within 40 cols - 4 for border/padding = 36)
 	title := "Block branch:"
-	lines = append(lines, titleStyle.Render(title))
+	lines = append(lines, p.styles.title.Render(title))
+
+	// Max branch name length (40 cols - 4 border/padding - 2 for "> " = 34)
+	maxBranchLen := 34
+	if p.query != "" {
+		query := p.query
+		if len(query) > maxBranchLen-len("filter: ") {
+			query = "…" + query[len(query)-(maxBranchLen-len("filter: ")-1):]
+		}
+		lines = append(lines, p.styles.filter.Render("filter: "+query))
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, p.styles.normalItem.Render("  No matching branches"))
+	}
 
 	// Branch list (limit visible items if too many)
 	maxVisible := 8 // Reduced to fit better
 	startIdx := 0
-	endIdx := len(p.branches)
+	endIdx := len(p.matches)
 
-	if len(p.branches) > maxVisible {
+	if len(p.matches) > maxVisible {
 		// Keep selected item in view
 		if p.selected >= maxVisible/2 {
 			startIdx = p.selected - maxVisible/2
 		}
 		endIdx = startIdx + maxVisible
-		if endIdx > len(p.branches) {
-			endIdx = len(p.branches)
+		if endIdx > len(p.matches) {
+			endIdx = len(p.matches)
 			startIdx = endIdx - maxVisible
 			if startIdx < 0 {
 				startIdx = 0
@@ -113,24 +217,33 @@ func (p *BranchPicker) Render() string {
 		}
 	}
 
-	// Max branch name length (40 cols - 4 border/padding - 2 for "> " = 34)
-	maxBranchLen := 34
 	for i := startIdx; i < endIdx; i++ {
-		branch := p.branches[i]
-		// Truncate if too long
-		if len(branch) > maxBranchLen {
-			branch = branch[:maxBranchLen-1] + "…"
+		branch := p.matches[i]
+		_, isBlocked := p.blocked[branch]
+		// Truncate if too long, leaving room for the blocked marker
+		maxLen := maxBranchLen
+		if isBlocked {
+			maxLen -= len(blockedMarker)
+		}
+		if len(branch) > maxLen {
+			branch = branch[:maxLen-1] + "…"
+		}
+		if isBlocked {
+			branch += blockedMarker
 		}
-		if i == p.selected {
-			lines = append(lines, selectedItemStyle.Render("> "+branch))
-		} else {
-			lines = append(lines, normalItemStyle.Render("  "+branch))
+		switch {
+		case i == p.selected:
+			lines = append(lines, p.styles.selectedItem.Render("> "+branch))
+		case isBlocked:
+			lines = append(lines, p.styles.blockedItem.Render("  "+branch))
+		default:
+			lines = append(lines, p.styles.normalItem.Render("  "+branch))
 		}
 	}
 
 	// Help text (shortened to fit)
-	lines = append(lines, helpStyle.Render("↑/k ↓/j ⏎:ok esc:✗"))
+	lines = append(lines, p.styles.help.Render("type:filter ↑↓ ⏎:ok esc:✗"))
 
 	content := strings.Join(lines, "\n")
-	return pickerStyle.Width(36).Render(content)
+	return p.styles.picker.Width(36).Render(content)
 }
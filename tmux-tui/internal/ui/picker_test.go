@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBranchPicker_Filter(t *testing.T) {
+	branches := []string{"feature-auth", "fix-login", "main", "refactor-ui", "release-1.2"}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", branches},
+		{"fix", []string{"fix-login"}},
+		{"EA", []string{"feature-auth", "release-1.2", "refactor-ui"}}, // Substring matches before fuzzy
+		{"fth", []string{"feature-auth"}},                              // Fuzzy: f...t...h in order
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			p := NewBranchPicker(branches, 80, 24)
+			p.Filter(tt.query)
+
+			var got []string
+			for range branches {
+				if s := p.Selected(); s != "" && (len(got) == 0 || got[len(got)-1] != s) {
+					got = append(got, s)
+				}
+				p.MoveDown()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter(%q) visits %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchPicker_SelectionStaysValid(t *testing.T) {
+	p := NewBranchPicker([]string{"alpha", "beta", "gamma", "delta"}, 80, 24)
+	p.MoveDown()
+	p.MoveDown()
+	if got := p.Selected(); got != "gamma" {
+		t.Fatalf("Selected() = %q, want gamma", got)
+	}
+
+	// Selected branch still matches: it stays selected at its new index
+	p.Filter("a")
+	if got := p.Selected(); got != "gamma" {
+		t.Errorf("After Filter(a): Selected() = %q, want gamma", got)
+	}
+
+	// Selected branch filtered out: first match is selected
+	p.Filter("del")
+	if got := p.Selected(); got != "delta" {
+		t.Errorf("After Filter(del): Selected() = %q, want delta", got)
+	}
+	p.MoveDown()
+	if got := p.Selected(); got != "delta" {
+		t.Errorf("MoveDown past the filtered list: Selected() = %q, want delta", got)
+	}
+
+	// No matches: nothing selected
+	p.Filter("xyz")
+	if got := p.Selected(); got != "" {
+		t.Errorf("With no matches: Selected() = %q, want empty", got)
+	}
+	p.MoveUp()
+	p.MoveDown()
+	if got := p.Selected(); got != "" {
+		t.Errorf("Navigating with no matches: Selected() = %q, want empty", got)
+	}
+	if !strings.Contains(p.Render(), "No matching branches") {
+		t.Error("Render() with no matches should say so")
+	}
+
+	// Widening again shows everything
+	p.Filter("")
+	if got := p.Selected(); got != "alpha" {
+		t.Errorf("After clearing the filter: Selected() = %q, want alpha", got)
+	}
+
+	// SetBranches keeps the filter and the selected branch
+	p.Filter("be")
+	p.SetBranches([]string{"bravo", "beta-2", "beta"})
+	if got := p.Query(); got != "be" {
+		t.Errorf("Query() after SetBranches = %q, want be", got)
+	}
+	if got := p.Selected(); got != "beta" {
+		t.Errorf("After SetBranches: Selected() = %q, want beta", got)
+	}
+}
+
+func TestBranchPicker_BlockedBranches(t *testing.T) {
+	p := NewBranchPicker(nil, 80, 24)
+	// feature blocks api directly and docs through api; ui is blocked by main
+	p.SetBlockedBranches("feature", map[string][]string{
+		"api":  {"feature"},
+		"docs": {"api"},
+		"ui":   {"main"},
+	})
+	p.SetBranches([]string{"api", "docs", "main", "ui", "web"})
+
+	var got []string
+	for i := 0; i < 5; i++ {
+		if s := p.Selected(); len(got) == 0 || got[len(got)-1] != s {
+			got = append(got, s)
+		}
+		p.MoveDown()
+	}
+	if want := []string{"main", "ui", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Picker offers %v, want %v (cycle-forming branches left out, order kept)", got, want)
+	}
+
+	view := p.Render()
+	if !strings.Contains(view, "ui"+blockedMarker) {
+		t.Errorf("Render() should mark blocked branch ui, got:\n%s", view)
+	}
+	if strings.Contains(view, "web"+blockedMarker) || strings.Contains(view, "main"+blockedMarker) {
+		t.Errorf("Render() should only mark blocked branches, got:\n%s", view)
+	}
+
+	// Without blocks every branch is offered again
+	p.SetBlockedBranches("feature", nil)
+	p.Filter("ap")
+	if got := p.Selected(); got != "api" {
+		t.Errorf("After clearing blocks: Selected() = %q, want api", got)
+	}
+}
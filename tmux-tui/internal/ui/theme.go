@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NoColorEnv disables all colors when set to a non-empty value (see https://no-color.org).
+const NoColorEnv = "NO_COLOR"
+
+// Theme holds the colors the TUI draws with. Colors are ANSI 256 color numbers ("0" to
+// "255") or hex RGB ("#5f87af"). Create with DefaultTheme or LoadTheme.
+type Theme struct {
+	Header    string // Date/time header
+	Repo      string // Repository names
+	Alert     string // Background of normal-severity alerts
+	AlertHigh string // Background of high-severity alerts
+	AlertLow  string // Background of low-severity alerts
+	AlertAge  string // How long an alert has been active
+	Active    string // Background of the active pane's row
+	Blocked   string // Blocked branches and their panes
+	Accent    string // Branch picker border, title and selection
+	Text      string // Branch picker entries and filter
+	Help      string // Branch picker key help
+	Error     string // Background of error banners
+	Warning   string // Background of warning banners
+	Info      string // Background of informational banners
+
+	// NoColor drops every color, leaving bold and reverse video to mark alerts and the
+	// active row. Set from NO_COLOR, not the theme file.
+	NoColor bool
+}
+
+// DefaultTheme returns the built-in colors, meant for dark terminals.
+func DefaultTheme() Theme {
+	return Theme{
+		Header:    "244",
+		Repo:      "14",
+		Alert:     "1",
+		AlertHigh: "9",
+		AlertLow:  "3",
+		AlertAge:  "244",
+		Active:    "240",
+		Blocked:   "245",
+		Accent:    "63",
+		Text:      "252",
+		Help:      "241",
+		Error:     "1",
+		Warning:   "3",
+		Info:      "6",
+	}
+}
+
+// themeFields maps the theme file's keys to the colors they set.
+func (t *Theme) themeFields() map[string]*string {
+	return map[string]*string{
+		"header":     &t.Header,
+		"repo":       &t.Repo,
+		"alert":      &t.Alert,
+		"alert_high": &t.AlertHigh,
+		"alert_low":  &t.AlertLow,
+		"alert_age":  &t.AlertAge,
+		"active":     &t.Active,
+		"blocked":    &t.Blocked,
+		"accent":     &t.Accent,
+		"text":       &t.Text,
+		"help":       &t.Help,
+		"error":      &t.Error,
+		"warning":    &t.Warning,
+		"info":       &t.Info,
+	}
+}
+
+// LoadTheme reads colors from a JSON file mapping color names to colors, e.g.
+// {"header": "240", "active": "#d0d0d0"}. Colors the file doesn't mention keep their
+// defaults. A missing file is not an error and gives the defaults.
+//
+// The file is rejected as a whole if it names an unknown color or a color isn't an ANSI
+// 256 color number or hex RGB.
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultTheme(), nil
+	}
+	if err != nil {
+		return DefaultTheme(), fmt.Errorf("failed to read theme: %w", err)
+	}
+	theme, err := ParseTheme(data)
+	if err != nil {
+		return DefaultTheme(), fmt.Errorf("invalid theme in %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// ParseTheme applies the JSON colors in data on top of the defaults.
+// See LoadTheme for the format and validation rules.
+func ParseTheme(data []byte) (Theme, error) {
+	var overrides map[string]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return Theme{}, fmt.Errorf("malformed JSON: %w", err)
+	}
+
+	theme := DefaultTheme()
+	fields := theme.themeFields()
+	// Sorted so the reported error doesn't depend on map order
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown color %q (valid: %s)", name, strings.Join(themeFieldNames(), ", "))
+		}
+		color := strings.TrimSpace(overrides[name])
+		if !isValidColor(color) {
+			return Theme{}, fmt.Errorf("color %q has invalid value %q (want 0-255 or #rrggbb)", name, overrides[name])
+		}
+		*field = color
+	}
+	return theme, nil
+}
+
+// NoColorFromEnv reports whether NO_COLOR asks for output without colors.
+func NoColorFromEnv() bool {
+	return os.Getenv(NoColorEnv) != ""
+}
+
+// Color returns c as a lipgloss color, or no color at all when the theme has NoColor set.
+func (t Theme) Color(c string) lipgloss.TerminalColor {
+	if t.NoColor || c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// isValidColor reports whether c is an ANSI 256 color number or hex RGB
+func isValidColor(c string) bool {
+	if strings.HasPrefix(c, "#") {
+		return hexColorPattern.MatchString(c)
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func themeFieldNames() []string {
+	var theme Theme
+	names := make([]string, 0, 16)
+	for name := range theme.themeFields() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// treeStyles are the TreeRenderer's styles for one theme
+type treeStyles struct {
+	bell          lipgloss.Style
+	attention     lipgloss.Style // High-severity alerts, which need an answer before Claude can go on
+	lowBell       lipgloss.Style // Low-severity alerts, which can wait
+	active        lipgloss.Style
+	blocked       lipgloss.Style
+	blockedActive lipgloss.Style
+	alertAge      lipgloss.Style
+	header        lipgloss.Style
+	repo          lipgloss.Style
+}
+
+func newTreeStyles(t Theme) treeStyles {
+	s := treeStyles{
+		bell:          lipgloss.NewStyle().Foreground(t.Color("0")).Background(t.Color(t.Alert)).Bold(true),
+		attention:     lipgloss.NewStyle().Foreground(t.Color("15")).Background(t.Color(t.AlertHigh)).Bold(true),
+		lowBell:       lipgloss.NewStyle().Foreground(t.Color("0")).Background(t.Color(t.AlertLow)),
+		active:        lipgloss.NewStyle().Background(t.Color(t.Active)),
+		blocked:       lipgloss.NewStyle().Foreground(t.Color(t.Blocked)),
+		blockedActive: lipgloss.NewStyle().Foreground(t.Color(t.Blocked)).Background(t.Color(t.Active)),
+		alertAge:      lipgloss.NewStyle().Foreground(t.Color(t.AlertAge)),
+		header:        lipgloss.NewStyle().Foreground(t.Color(t.Header)).Bold(true),
+		repo:          lipgloss.NewStyle().Foreground(t.Color(t.Repo)).Bold(true),
+	}
+	if t.NoColor {
+		// Without a background the active row needs another way to stand out
+		s.active = s.active.Reverse(true)
+		s.blockedActive = s.blockedActive.Reverse(true)
+	}
+	return s
+}
+
+// pickerStyles are the BranchPicker's styles for one theme
+type pickerStyles struct {
+	picker       lipgloss.Style
+	selectedItem lipgloss.Style
+	normalItem   lipgloss.Style
+	blockedItem  lipgloss.Style // Muted like blocked branches in the tree
+	title        lipgloss.Style
+	filter       lipgloss.Style
+	help         lipgloss.Style
+}
+
+func newPickerStyles(t Theme) pickerStyles {
+	s := pickerStyles{
+		picker:       lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Color(t.Accent)).Padding(1, 2),
+		selectedItem: lipgloss.NewStyle().Foreground(t.Color("0")).Background(t.Color(t.Accent)).Bold(true),
+		normalItem:   lipgloss.NewStyle().Foreground(t.Color(t.Text)),
+		blockedItem:  lipgloss.NewStyle().Foreground(t.Color(t.Blocked)),
+		title:        lipgloss.NewStyle().Foreground(t.Color(t.Accent)).Bold(true).MarginBottom(1),
+		filter:       lipgloss.NewStyle().Foreground(t.Color(t.Text)),
+		help:         lipgloss.NewStyle().Foreground(t.Color(t.Help)).MarginTop(1),
+	}
+	if t.NoColor {
+		s.selectedItem = s.selectedItem.Reverse(true)
+	}
+	return s
+}
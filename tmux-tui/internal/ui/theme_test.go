@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"header": "240", "active": "#d0d0d0", "warning": " 11 "}`))
+	if err != nil {
+		t.Fatalf("ParseTheme() error = %v", err)
+	}
+	want := DefaultTheme()
+	want.Header = "240"
+	want.Active = "#d0d0d0"
+	want.Warning = "11"
+	if theme != want {
+		t.Errorf("ParseTheme() = %+v, want %+v", theme, want)
+	}
+
+	invalid := []struct {
+		name, data, wantErr string
+	}{
+		{"malformed", `{"header": `, "malformed JSON"},
+		{"unknown color", `{"headr": "240"}`, `unknown color "headr"`},
+		{"out of range", `{"alert": "256"}`, `color "alert" has invalid value "256"`},
+		{"color name", `{"alert": "red"}`, `color "alert" has invalid value "red"`},
+		{"bad hex", `{"alert": "#12345"}`, `color "alert" has invalid value`},
+		{"empty", `{"alert": ""}`, `color "alert" has invalid value`},
+	}
+	for _, tt := range invalid {
+		if _, err := ParseTheme([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: ParseTheme() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+
+	theme, err := LoadTheme(filepath.Join(dir, "missing.json"))
+	if err != nil || theme != DefaultTheme() {
+		t.Errorf("LoadTheme(missing) = %+v, %v; want defaults", theme, err)
+	}
+
+	path := filepath.Join(dir, "tui-theme.json")
+	if err := os.WriteFile(path, []byte(`{"blocked": "8"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if theme, err := LoadTheme(path); err != nil || theme.Blocked != "8" {
+		t.Errorf("LoadTheme() = %+v, %v; want blocked 8", theme, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"blocked": "grey"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	theme, err = LoadTheme(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadTheme(invalid) error = %v, want it to name %s", err, path)
+	}
+	if theme != DefaultTheme() {
+		t.Errorf("LoadTheme(invalid) = %+v, want defaults", theme)
+	}
+}
+
+func TestNoColorFromEnv(t *testing.T) {
+	t.Setenv(NoColorEnv, "")
+	if NoColorFromEnv() {
+		t.Error("Empty NO_COLOR should keep colors")
+	}
+	t.Setenv(NoColorEnv, "1")
+	if !NoColorFromEnv() {
+		t.Error("NO_COLOR=1 should disable colors")
+	}
+}
+
+func TestTheme_NoColor(t *testing.T) {
+	theme := DefaultTheme()
+	if got := theme.Color(theme.Header); got != lipgloss.Color("244") {
+		t.Errorf("Color(header) = %v, want 244", got)
+	}
+
+	theme.NoColor = true
+	if got := theme.Color(theme.Header); got != (lipgloss.NoColor{}) {
+		t.Errorf("Color(header) with NoColor = %v, want no color", got)
+	}
+
+	styles := newTreeStyles(theme)
+	for name, style := range map[string]lipgloss.Style{
+		"bell": styles.bell, "attention": styles.attention, "active": styles.active,
+		"blocked": styles.blocked, "header": styles.header, "repo": styles.repo,
+	} {
+		if style.GetForeground() != (lipgloss.NoColor{}) || style.GetBackground() != (lipgloss.NoColor{}) {
+			t.Errorf("%s style has colors with NoColor set", name)
+		}
+	}
+	// The active row and the picker selection still stand out
+	if !styles.active.GetReverse() || !newPickerStyles(theme).selectedItem.GetReverse() {
+		t.Error("Active row and picker selection should use reverse video with NoColor set")
+	}
+}
@@ -25,29 +25,6 @@ const (
 	ElicitationIcon = "❓" // U+2753 BLACK QUESTION MARK ORNAMENT
 )
 
-var bellStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("0")).
-	Background(lipgloss.Color("1")).
-	Bold(true)
-
-var activeStyle = lipgloss.NewStyle().
-	Background(lipgloss.Color("240"))
-
-var blockedStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("245")) // Muted gray text
-
-var blockedActiveStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("245")). // Muted gray text
-	Background(lipgloss.Color("240"))  // Active background highlight
-
-var headerStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("244")).
-	Bold(true)
-
-var repoStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("14")).
-	Bold(true)
-
 // iconForAlertType returns the appropriate icon for a given alert type
 func iconForAlertType(alertType string) string {
 	switch alertType {
@@ -70,11 +47,27 @@ type TreeRenderer struct {
 	width        int
 	height       int
 	headerHeight int
+
+	// When each Claude alert started, keyed by pane ID; panes without an entry show no age
+	alertTimes map[string]time.Time
+	// When expiring blocks end, blocked branch -> blocker -> expiry; blocks without an
+	// entry never expire
+	blockExpiries map[string]map[string]time.Time
+	// Severity of each alert type, which picks its style and sorts high-severity alerts first
+	severities watcher.SeverityMap
+	styles     treeStyles
+	now        func() time.Time
 }
 
 // NewTreeRenderer creates a new TreeRenderer with the given width
 func NewTreeRenderer(width int) *TreeRenderer {
-	return &TreeRenderer{width: width, height: 24}
+	return &TreeRenderer{
+		width:      width,
+		height:     24,
+		severities: watcher.DefaultSeverities,
+		styles:     newTreeStyles(DefaultTheme()),
+		now:        time.Now,
+	}
 }
 
 // SetWidth updates the renderer width
@@ -89,15 +82,35 @@ func (r *TreeRenderer) SetHeight(height int) {
 	r.headerHeight = 2
 }
 
+// SetAlertTimes updates the alert start times used to show how long each alert has been active
+func (r *TreeRenderer) SetAlertTimes(alertTimes map[string]time.Time) {
+	r.alertTimes = alertTimes
+}
+
+// SetBlockExpiries updates the block expiries used to show how long each expiring block has left
+func (r *TreeRenderer) SetBlockExpiries(blockExpiries map[string]map[string]time.Time) {
+	r.blockExpiries = blockExpiries
+}
+
+// SetSeverities updates the severity of each alert type (see watcher.SeveritiesFromEnv)
+func (r *TreeRenderer) SetSeverities(severities watcher.SeverityMap) {
+	r.severities = severities
+}
+
+// SetTheme updates the colors the tree is drawn with (see LoadTheme)
+func (r *TreeRenderer) SetTheme(theme Theme) {
+	r.styles = newTreeStyles(theme)
+}
+
 // RenderHeader returns a formatted date/time header
 func (r *TreeRenderer) RenderHeader() string {
 	now := time.Now()
 	timeStr := now.Format("Mon Jan 2 15:04:05")
-	return headerStyle.Render(timeStr)
+	return r.styles.header.Render(timeStr)
 }
 
 // Render converts a RepoTree into a formatted tree string
-func (r *TreeRenderer) Render(tree tmux.RepoTree, claudeAlerts map[string]string, blockedBranches map[string]string) string {
+func (r *TreeRenderer) Render(tree tmux.RepoTree, claudeAlerts map[string]string, blockedBranches map[string][]string) string {
 	repos := tree.Repos()
 	if len(repos) == 0 {
 		return "No panes found in current tmux session"
@@ -133,9 +146,9 @@ func (r *TreeRenderer) Render(tree tmux.RepoTree, claudeAlerts map[string]string
 	return strings.Join(lines, "\n")
 }
 
-func (r *TreeRenderer) renderRepo(repoName string, tree tmux.RepoTree, isLastRepo bool, claudeAlerts map[string]string, blockedBranches map[string]string) []string {
+func (r *TreeRenderer) renderRepo(repoName string, tree tmux.RepoTree, isLastRepo bool, claudeAlerts map[string]string, blockedBranches map[string][]string) []string {
 	var lines []string
-	lines = append(lines, repoStyle.Render(repoName))
+	lines = append(lines, r.styles.repo.Render(repoName))
 
 	// Get branches for this repo
 	branchNames := tree.Branches(repoName)
@@ -145,11 +158,13 @@ func (r *TreeRenderer) renderRepo(repoName string, tree tmux.RepoTree, isLastRep
 
 	// Calculate blocked counts: how many branches are blocked BY each branch
 	blockedCounts := make(map[string]int)
-	for blockedBranch, blockerBranch := range blockedBranches {
+	for blockedBranch, blockerBranches := range blockedBranches {
 		// Only count if both branches exist in this repo
 		if tree.HasBranch(repoName, blockedBranch) {
-			if tree.HasBranch(repoName, blockerBranch) {
-				blockedCounts[blockerBranch]++
+			for _, blockerBranch := range blockerBranches {
+				if tree.HasBranch(repoName, blockerBranch) {
+					blockedCounts[blockerBranch]++
+				}
 			}
 		}
 	}
@@ -174,15 +189,21 @@ func (r *TreeRenderer) renderRepo(repoName string, tree tmux.RepoTree, isLastRep
 		}
 
 		// Check if this branch is blocked
-		_, isBranchBlocked := blockedBranches[branch]
+		blockers, isBranchBlocked := blockedBranches[branch]
 
 		// Add branch name (muted if blocked)
 		branchLine := branchPrefix + branch
 		if isBranchBlocked {
-			branchLine = blockedStyle.Render(branchLine)
+			branchLine = r.styles.blocked.Render(branchLine)
 		}
 		lines = append(lines, branchLine)
 
+		// List the blocking branches on a separate line, like the blocked count
+		if len(blockers) > 0 {
+			blockersText := "blocked by " + strings.Join(r.blockerLabels(branch, blockers), ", ")
+			lines = append(lines, childPrefix+r.styles.blocked.Render(blockersText))
+		}
+
 		// Add blocked count on separate line if > 0
 		if count := blockedCounts[branch]; count > 0 {
 			countText := fmt.Sprintf("%d branches blocked", count)
@@ -199,21 +220,26 @@ func (r *TreeRenderer) renderRepo(repoName string, tree tmux.RepoTree, isLastRep
 	return lines
 }
 
-func (r *TreeRenderer) renderPanes(panes []tmux.Pane, prefix string, claudeAlerts map[string]string, blockedBranches map[string]string, currentBranch string) []string {
+func (r *TreeRenderer) renderPanes(panes []tmux.Pane, prefix string, claudeAlerts map[string]string, blockedBranches map[string][]string, currentBranch string) []string {
 	var lines []string
 
-	// Sort panes by window index for consistent output
-	sort.Slice(panes, func(i, j int) bool {
-		return panes[i].WindowIndex() < panes[j].WindowIndex()
-	})
-
 	// Check if the entire branch is blocked
 	_, isBranchBlocked := blockedBranches[currentBranch]
 	if isBranchBlocked {
-		debug.Log("TUI_RENDER_BRANCH_BLOCKED branch=%s blockedBy=%s paneCount=%d",
+		debug.Log("TUI_RENDER_BRANCH_BLOCKED branch=%s blockedBy=%v paneCount=%d",
 			currentBranch, blockedBranches[currentBranch], len(panes))
 	}
 
+	// Sort panes by window index for consistent output, with high-severity alerts first
+	// so they are seen before anything else on the branch
+	sort.Slice(panes, func(i, j int) bool {
+		return panes[i].WindowIndex() < panes[j].WindowIndex()
+	})
+	sort.SliceStable(panes, func(i, j int) bool {
+		return r.isHighSeverity(panes[i], claudeAlerts, isBranchBlocked) &&
+			!r.isHighSeverity(panes[j], claudeAlerts, isBranchBlocked)
+	})
+
 	for i, pane := range panes {
 		isLastPane := i == len(panes)-1
 		panePrefix := Branch
@@ -224,49 +250,50 @@ func (r *TreeRenderer) renderPanes(panes []tmux.Pane, prefix string, claudeAlert
 		// Build window number portion separately
 		windowNumber := fmt.Sprintf("%d:", pane.WindowIndex())
 
-		// Determine if bell should be shown and get alert type
-		// Blocked branches should NOT show bell/idle highlighting
-		var showBell bool
-		var alertType string
-		if !isBranchBlocked {
-			if pane.IsClaudePane() {
-				// For Claude panes, use persistent alert state
-				alertType, showBell = claudeAlerts[pane.ID()]
-				// Log render state for Claude panes
-				debug.Log("TUI_RENDER_PANE id=%s isClaudePane=%v alertType=%s showBell=%v", pane.ID(), pane.IsClaudePane(), alertType, showBell)
-			} else {
-				// For non-Claude panes, use default tmux bell behavior
-				showBell = pane.WindowBell()
-				alertType = watcher.EventTypeStop // Default for non-Claude panes
-			}
+		alertType, showBell := paneAlert(pane, claudeAlerts, isBranchBlocked)
+		var alertAge string
+		if alertedAt, ok := r.alertTimes[pane.ID()]; ok && showBell && pane.IsClaudePane() {
+			alertAge = formatAlertAge(r.now().Sub(alertedAt))
+		}
+		if pane.IsClaudePane() && !isBranchBlocked {
+			// Log render state for Claude panes
+			debug.Log("TUI_RENDER_PANE id=%s isClaudePane=%v alertType=%s showBell=%v", pane.ID(), pane.IsClaudePane(), alertType, showBell)
 		}
 
-		// Apply bell style with icon ONLY to window number if bell is active
+		// Apply the severity's bell style with icon ONLY to window number if bell is active
 		if showBell {
 			icon := iconForAlertType(alertType)
-			windowNumber = bellStyle.Render(icon + windowNumber)
+			windowNumber = r.styleForSeverity(r.severities.For(alertType)).Render(icon + windowNumber)
+			if alertAge != "" {
+				windowNumber += r.styles.alertAge.Render(alertAge) + " "
+			}
 		}
 
-		// Build command + title portion
+		// Build command + title portion. The command comes first so it stays visible
+		// as a hint (tests vs. editor) when a long title is truncated.
 		commandTitle := pane.Command()
-		if pane.Title() != "" {
+		if pane.Title() != "" && pane.Title() != pane.Command() {
 			commandTitle += " " + pane.Title()
 		}
 
-		// Assemble the line from parts
-		line := prefix + panePrefix + windowNumber + commandTitle
+		// Assemble the line from parts, truncating the text to the renderer width
+		line := prefix + panePrefix + windowNumber
+		if r.width > 0 {
+			commandTitle = truncateToWidth(commandTitle, r.width-lipgloss.Width(line))
+		}
+		line += commandTitle
 
 		// Apply appropriate styling based on blocked and active state
 		switch {
 		case isBranchBlocked && pane.WindowActive():
 			// Blocked + Active: background highlight with muted text
-			line = blockedActiveStyle.Width(r.width).Render(line)
+			line = r.styles.blockedActive.Width(r.width).Render(line)
 		case isBranchBlocked:
 			// Blocked + Idle: only muted text, no highlight
-			line = blockedStyle.Render(line)
+			line = r.styles.blocked.Render(line)
 		case pane.WindowActive():
 			// Active panes get active style with full width
-			line = activeStyle.Width(r.width).Render(line)
+			line = r.styles.active.Width(r.width).Render(line)
 		}
 
 		lines = append(lines, line)
@@ -274,3 +301,90 @@ func (r *TreeRenderer) renderPanes(panes []tmux.Pane, prefix string, claudeAlert
 
 	return lines
 }
+
+// paneAlert returns the pane's alert type and whether its bell should be shown.
+// Blocked branches should NOT show bell/idle highlighting.
+func paneAlert(pane tmux.Pane, claudeAlerts map[string]string, isBranchBlocked bool) (alertType string, showBell bool) {
+	if isBranchBlocked {
+		return "", false
+	}
+	if pane.IsClaudePane() {
+		// For Claude panes, use persistent alert state
+		alertType, showBell = claudeAlerts[pane.ID()]
+		return alertType, showBell
+	}
+	// For non-Claude panes, use default tmux bell behavior
+	return watcher.EventTypeStop, pane.WindowBell() // Default for non-Claude panes
+}
+
+// isHighSeverity reports whether pane shows an alert of high severity.
+func (r *TreeRenderer) isHighSeverity(pane tmux.Pane, claudeAlerts map[string]string, isBranchBlocked bool) bool {
+	alertType, showBell := paneAlert(pane, claudeAlerts, isBranchBlocked)
+	return showBell && r.severities.For(alertType) == watcher.SeverityHigh
+}
+
+// styleForSeverity returns the bell style for an alert of the given severity
+func (r *TreeRenderer) styleForSeverity(severity watcher.Severity) lipgloss.Style {
+	switch severity {
+	case watcher.SeverityHigh:
+		return r.styles.attention
+	case watcher.SeverityLow:
+		return r.styles.lowBell
+	default:
+		return r.styles.bell
+	}
+}
+
+// blockerLabels returns branch's blockers for display, with the time left on expiring
+// blocks ("main (2h left)").
+func (r *TreeRenderer) blockerLabels(branch string, blockers []string) []string {
+	expiries := r.blockExpiries[branch]
+	if len(expiries) == 0 {
+		return blockers
+	}
+	labels := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		labels[i] = blocker
+		if expiresAt, ok := expiries[blocker]; ok {
+			labels[i] += fmt.Sprintf(" (%s left)", formatAlertAge(expiresAt.Sub(r.now())))
+		}
+	}
+	return labels
+}
+
+// formatAlertAge formats how long an alert has been active in its largest whole unit
+// ("45s", "3m", "2h", "1d").
+func formatAlertAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(int(d/time.Second), 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
+
+// truncateToWidth shortens s to at most width terminal cells, ending with "…" when
+// anything was cut.
+func truncateToWidth(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + "…"
+}
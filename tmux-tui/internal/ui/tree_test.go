@@ -3,7 +3,9 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/commons-systems/tmux-tui/internal/tmux"
 	"github.com/commons-systems/tmux-tui/internal/watcher"
 )
@@ -46,7 +48,7 @@ func TestTreeRenderer(t *testing.T) {
 
 	renderer := NewTreeRenderer(80)
 	claudeAlerts := make(map[string]string)
-	blockedPanes := make(map[string]string)
+	blockedPanes := make(map[string][]string)
 	output := renderer.Render(tree, claudeAlerts, blockedPanes)
 
 	// Verify output contains expected elements
@@ -76,10 +78,45 @@ func TestTreeRenderer(t *testing.T) {
 	}
 }
 
+// TestTreeRenderer_PaneCommandAndTitle verifies each pane shows its command followed by
+// its title, and that long titles are truncated to the renderer width while the
+// command stays visible.
+func TestTreeRenderer_PaneCommandAndTitle(t *testing.T) {
+	const width = 40
+	longTitle := "running the full integration suite against every package"
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"repo": {
+			"main": {
+				testPane("%1", "/path", "@1", 0, false, false, "nvim", "tree.go", false),
+				testPane("%2", "/path", "@2", 1, true, false, "go", longTitle, false),
+			},
+		},
+	})
+
+	renderer := NewTreeRenderer(width)
+	renderer.SetHeight(24)
+	output := renderer.Render(tree, map[string]string{}, map[string][]string{})
+
+	if !strings.Contains(output, "0:nvim tree.go") {
+		t.Errorf("Output missing command and title for pane 0:\n%s", output)
+	}
+	if !strings.Contains(output, "1:go running") || !strings.Contains(output, "…") {
+		t.Errorf("Output missing truncated command and title for pane 1:\n%s", output)
+	}
+	if strings.Contains(output, longTitle) {
+		t.Errorf("Long title was not truncated:\n%s", output)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if w := lipgloss.Width(line); w > width {
+			t.Errorf("Line is %d cells wide, want <= %d: %q", w, width, line)
+		}
+	}
+}
+
 func TestTreeRendererEmpty(t *testing.T) {
 	renderer := NewTreeRenderer(80)
 	claudeAlerts := make(map[string]string)
-	blockedPanes := make(map[string]string)
+	blockedPanes := make(map[string][]string)
 
 	// Test with nil tree
 	output := renderer.Render(tmux.RepoTree{}, claudeAlerts, blockedPanes)
@@ -142,7 +179,7 @@ func TestIconForAlertTypeIntegration(t *testing.T) {
 	}
 
 	renderer := NewTreeRenderer(80)
-	blockedPanes := make(map[string]string)
+	blockedPanes := make(map[string][]string)
 	output := renderer.Render(tree, claudeAlerts, blockedPanes)
 
 	// Verify that output contains the styled window numbers with icons
@@ -170,7 +207,7 @@ func TestTreeRendererFullHeight(t *testing.T) {
 	renderer := NewTreeRenderer(80)
 	renderer.SetHeight(20)
 	claudeAlerts := make(map[string]string)
-	blockedPanes := make(map[string]string)
+	blockedPanes := make(map[string][]string)
 	output := renderer.Render(tree, claudeAlerts, blockedPanes)
 
 	// Count the number of lines (should be height - headerHeight = 20 - 2 = 18)
@@ -231,8 +268,8 @@ func TestTreeRenderer_BlockedBranch_ActivePane(t *testing.T) {
 
 	renderer := NewTreeRenderer(80)
 	claudeAlerts := make(map[string]string)
-	blockedBranches := map[string]string{
-		"feature-branch": "main", // feature-branch is blocked by main
+	blockedBranches := map[string][]string{
+		"feature-branch": {"main"}, // feature-branch is blocked by main
 	}
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
@@ -269,8 +306,8 @@ func TestTreeRenderer_BlockedBranch_IdlePane(t *testing.T) {
 
 	renderer := NewTreeRenderer(80)
 	claudeAlerts := make(map[string]string)
-	blockedBranches := map[string]string{
-		"feature-branch": "main",
+	blockedBranches := map[string][]string{
+		"feature-branch": {"main"},
 	}
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
@@ -307,8 +344,8 @@ func TestTreeRenderer_BlockedBranch_NoBell(t *testing.T) {
 	claudeAlerts := map[string]string{
 		"%1": watcher.EventTypeStop,
 	}
-	blockedBranches := map[string]string{
-		"feature-branch": "main",
+	blockedBranches := map[string][]string{
+		"feature-branch": {"main"},
 	}
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
@@ -335,7 +372,7 @@ func TestTreeRenderer_UnblockedBranch_ShowsBell(t *testing.T) {
 		"%1": watcher.EventTypeStop,
 	}
 	// Empty blocked branches - feature-branch is NOT blocked
-	blockedBranches := make(map[string]string)
+	blockedBranches := make(map[string][]string)
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
 
@@ -363,9 +400,9 @@ func TestTreeRenderer_MultipleBlockedBranches(t *testing.T) {
 
 	renderer := NewTreeRenderer(80)
 	claudeAlerts := make(map[string]string)
-	blockedBranches := map[string]string{
-		"feature-1": "main",
-		"feature-2": "main",
+	blockedBranches := map[string][]string{
+		"feature-1": {"main"},
+		"feature-2": {"main"},
 	}
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
@@ -390,6 +427,66 @@ func TestTreeRenderer_MultipleBlockedBranches(t *testing.T) {
 	}
 }
 
+// TestTreeRenderer_BranchWithMultipleBlockers tests that every blocker is listed and
+// counted when a branch waits on more than one branch
+func TestTreeRenderer_BranchWithMultipleBlockers(t *testing.T) {
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {
+			"feature": {
+				testPane("%1", "", "@1", 0, false, false, "zsh", "", false),
+			},
+			"develop": {
+				testPane("%2", "", "@2", 1, false, false, "nvim", "", false),
+			},
+			"main": {
+				testPane("%3", "", "@3", 2, true, false, "tmux-tui", "", false),
+			},
+		},
+	})
+
+	renderer := NewTreeRenderer(80)
+	blockedBranches := map[string][]string{
+		"feature": {"main", "develop"},
+	}
+
+	output := renderer.Render(tree, make(map[string]string), blockedBranches)
+
+	if !strings.Contains(output, "blocked by main, develop") {
+		t.Errorf("Output should list both blockers, got:\n%s", output)
+	}
+	if got := strings.Count(output, "1 branches blocked"); got != 2 {
+		t.Errorf("Expected a blocked count under each blocker, got %d in:\n%s", got, output)
+	}
+}
+
+// TestTreeRenderer_BlockExpiry tests that expiring blockers show the time left and
+// permanent ones don't
+func TestTreeRenderer_BlockExpiry(t *testing.T) {
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {
+			"feature": {
+				testPane("%1", "", "@1", 0, false, false, "zsh", "", false),
+			},
+		},
+	})
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	renderer := NewTreeRenderer(80)
+	renderer.now = func() time.Time { return now }
+	renderer.SetBlockExpiries(map[string]map[string]time.Time{
+		"feature": {"main": now.Add(2*time.Hour + 30*time.Second)},
+	})
+	blockedBranches := map[string][]string{
+		"feature": {"main", "develop"},
+	}
+
+	output := renderer.Render(tree, make(map[string]string), blockedBranches)
+
+	if !strings.Contains(output, "blocked by main (2h left), develop") {
+		t.Errorf("Output should show the time left on main only, got:\n%s", output)
+	}
+}
+
 // TestTreeRenderer_BlockedBranch_IdleAlert tests idle alerts are hidden on blocked branches
 func TestTreeRenderer_BlockedBranch_IdleAlert(t *testing.T) {
 	tree := testTree(map[string]map[string][]tmux.Pane{
@@ -405,8 +502,8 @@ func TestTreeRenderer_BlockedBranch_IdleAlert(t *testing.T) {
 	claudeAlerts := map[string]string{
 		"%1": watcher.EventTypeIdle,
 	}
-	blockedBranches := map[string]string{
-		"feature-branch": "main",
+	blockedBranches := map[string][]string{
+		"feature-branch": {"main"},
 	}
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
@@ -437,8 +534,8 @@ func TestTreeRenderer_MixedBlockedUnblocked(t *testing.T) {
 		"%2": watcher.EventTypeStop,
 	}
 	// Only blocked-branch is blocked
-	blockedBranches := map[string]string{
-		"blocked-branch": "main",
+	blockedBranches := map[string][]string{
+		"blocked-branch": {"main"},
 	}
 
 	output := renderer.Render(tree, claudeAlerts, blockedBranches)
@@ -458,3 +555,119 @@ func TestTreeRenderer_MixedBlockedUnblocked(t *testing.T) {
 		t.Error("Should show at least one stop icon for unblocked branch")
 	}
 }
+
+// TestTreeRenderer_AlertAge tests that alerted Claude panes show how long the alert has been active
+func TestTreeRenderer_AlertAge(t *testing.T) {
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {
+			"main": {
+				testPane("%1", "", "@1", 0, false, false, "claude", "", true),
+				testPane("%2", "", "@2", 1, false, false, "claude", "", true),
+				testPane("%3", "", "@3", 2, false, false, "claude", "", true),
+			},
+		},
+	})
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	renderer := NewTreeRenderer(80)
+	renderer.now = func() time.Time { return now }
+	renderer.SetAlertTimes(map[string]time.Time{
+		"%1": now.Add(-3*time.Minute - 20*time.Second),
+		"%3": now.Add(-time.Hour), // No longer alerted: no age
+	})
+	claudeAlerts := map[string]string{
+		"%1": watcher.EventTypeStop,
+		"%2": watcher.EventTypeIdle, // Alerted before the TUI knew when: no age
+	}
+
+	lines := strings.Split(renderer.Render(tree, claudeAlerts, nil), "\n")
+	// lines[0] is the repo, lines[1] the branch, then one line per pane
+	if !strings.Contains(lines[2], "3m claude") {
+		t.Errorf("Pane %%1 should show its alert age, got %q", lines[2])
+	}
+	for _, line := range lines[3:5] {
+		if strings.Contains(line, "m claude") || strings.Contains(line, "h claude") {
+			t.Errorf("Pane without a known alert start should show no age, got %q", line)
+		}
+	}
+}
+
+func TestTreeRenderer_HighSeverityFirst(t *testing.T) {
+	tree := testTree(map[string]map[string][]tmux.Pane{
+		"test-repo": {
+			"main": {
+				testPane("%1", "", "@1", 0, false, false, "stopped", "", true),
+				testPane("%2", "", "@2", 1, false, false, "quiet", "", true),
+				testPane("%3", "", "@3", 2, false, false, "asking", "", true),
+				testPane("%4", "", "@4", 3, false, false, "idling", "", true),
+			},
+		},
+	})
+	claudeAlerts := map[string]string{
+		"%1": watcher.EventTypeStop,
+		"%3": watcher.EventTypePermission,
+		"%4": watcher.EventTypeIdle,
+	}
+
+	renderer := NewTreeRenderer(80)
+	lines := strings.Split(renderer.Render(tree, claudeAlerts, nil), "\n")
+	// lines[0] is the repo, lines[1] the branch, then one line per pane
+	want := []string{"asking", "stopped", "quiet", "idling"}
+	for i, command := range want {
+		if !strings.Contains(lines[2+i], command) {
+			t.Errorf("Pane line %d = %q, want %q", i, lines[2+i], command)
+		}
+	}
+
+	// Overriding the severities changes the order
+	renderer.SetSeverities(watcher.SeverityMap{watcher.EventTypeIdle: watcher.SeverityHigh})
+	lines = strings.Split(renderer.Render(tree, claudeAlerts, nil), "\n")
+	if !strings.Contains(lines[2], "idling") || !strings.Contains(lines[3], "stopped") {
+		t.Errorf("Idle alert should sort first when high, got %q", lines[2:6])
+	}
+}
+
+func TestStyleForSeverity(t *testing.T) {
+	renderer := NewTreeRenderer(80)
+	tests := []struct {
+		severity watcher.Severity
+		fg, bg   string
+	}{
+		{watcher.SeverityHigh, "15", "9"},
+		{watcher.SeverityNormal, "0", "1"},
+		{watcher.SeverityLow, "0", "3"},
+	}
+	for _, tt := range tests {
+		got := renderer.styleForSeverity(tt.severity)
+		if got.GetBackground() != lipgloss.Color(tt.bg) || got.GetForeground() != lipgloss.Color(tt.fg) {
+			t.Errorf("styleForSeverity(%q) has different colors than expected", tt.severity)
+		}
+	}
+
+	// The theme picks the backgrounds
+	theme := DefaultTheme()
+	theme.AlertHigh = "#ff0000"
+	renderer.SetTheme(theme)
+	if got := renderer.styleForSeverity(watcher.SeverityHigh).GetBackground(); got != lipgloss.Color("#ff0000") {
+		t.Errorf("High severity background = %v, want the theme's alert_high", got)
+	}
+}
+
+func TestFormatAlertAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{-time.Second, "0s"}, // Clock skew between daemon and TUI
+		{45 * time.Second, "45s"},
+		{time.Minute, "1m"},
+		{59*time.Minute + 59*time.Second, "59m"},
+		{2*time.Hour + 30*time.Minute, "2h"},
+		{49 * time.Hour, "2d"},
+	}
+	for _, tt := range tests {
+		if got := formatAlertAge(tt.age); got != tt.want {
+			t.Errorf("formatAlertAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package version identifies the tmux-tui build, so bug reports and the daemon's
+// health output can say which one is running.
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Version is the release version. Builds set it with
+// -ldflags "-X github.com/commons-systems/tmux-tui/internal/version.Version=v1.2.3".
+var Version = "dev"
+
+// String returns Version followed by the VCS revision Go embedded at build time, if
+// any, e.g. "v1.2.3 (1a2b3c4d5e6f, modified)".
+func String() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version
+	}
+	return format(Version, info.Settings)
+}
+
+// format appends the vcs.revision and vcs.modified build settings to version
+func format(version string, settings []debug.BuildSetting) string {
+	var revision string
+	var modified bool
+	for _, s := range settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision == "" {
+		return version
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		return fmt.Sprintf("%s (%s, modified)", version, revision)
+	}
+	return fmt.Sprintf("%s (%s)", version, revision)
+}
@@ -0,0 +1,29 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []debug.BuildSetting
+		want     string
+	}{
+		{"no VCS info", nil, "v1.0.0"},
+		{"revision", []debug.BuildSetting{{Key: "vcs.revision", Value: "0123456789abcdef0123"}}, "v1.0.0 (0123456789ab)"},
+		{"modified", []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123abc"},
+			{Key: "vcs.modified", Value: "true"},
+		}, "v1.0.0 (0123abc, modified)"},
+		{"modified without revision", []debug.BuildSetting{{Key: "vcs.modified", Value: "true"}}, "v1.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := format("v1.0.0", tt.settings); got != tt.want {
+				t.Errorf("format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package watcher
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -65,6 +66,12 @@ func readPaneID(filePath string) (string, error) {
 	return paneID, nil
 }
 
+// ReadPaneFocus returns the last focused pane ID persisted in alertDir's pane-focus file.
+// Used at daemon startup to restore focus before the first focus event arrives.
+func ReadPaneFocus(alertDir string) (string, error) {
+	return readPaneID(filepath.Join(alertDir, PaneFocusFile))
+}
+
 // PaneFocusEvent represents a pane focus change event
 type PaneFocusEvent struct {
 	PaneID string
@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Severity ranks how urgently an alert needs attention. It only affects presentation:
+// alerts are stored and reconciled by pane ID and event type regardless of severity.
+type Severity string
+
+const (
+	// SeverityLow is for alerts that can wait, like an idle prompt
+	SeverityLow Severity = "low"
+	// SeverityNormal is the severity of event types without a configured one
+	SeverityNormal Severity = "normal"
+	// SeverityHigh is for alerts that block Claude until answered
+	SeverityHigh Severity = "high"
+)
+
+// ParseSeverity returns the severity named by s (case-insensitive).
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case SeverityLow, SeverityNormal, SeverityHigh:
+		return sev, nil
+	default:
+		return "", fmt.Errorf("unknown severity %q (want low, normal or high)", s)
+	}
+}
+
+// AlertSeverityEnv names the environment variable overriding the severity of event types,
+// as comma-separated event=severity pairs (e.g. "idle=normal,stop=high"). Event types
+// it doesn't mention keep their DefaultSeverities entry.
+const AlertSeverityEnv = "TMUX_TUI_ALERT_SEVERITY"
+
+// DefaultSeverities maps each alerting event type to its severity when AlertSeverityEnv
+// is unset. Prompts that wait on the user are high, a plain stop is normal and an idle
+// prompt is low.
+var DefaultSeverities = SeverityMap{
+	EventTypePermission:  SeverityHigh,
+	EventTypeElicitation: SeverityHigh,
+	EventTypeStop:        SeverityNormal,
+	EventTypeIdle:        SeverityLow,
+}
+
+// SeverityMap maps event types to severities.
+type SeverityMap map[string]Severity
+
+// For returns the severity of eventType, or SeverityNormal if it has none.
+func (m SeverityMap) For(eventType string) Severity {
+	if sev, ok := m[eventType]; ok {
+		return sev
+	}
+	return SeverityNormal
+}
+
+// SeveritiesFromEnv returns DefaultSeverities with the overrides configured through
+// AlertSeverityEnv applied. Malformed entries are reported on stderr and skipped.
+//
+// The daemon and the TUI both call this so alert messages and rendering agree.
+func SeveritiesFromEnv() SeverityMap {
+	severities, err := ParseSeverities(os.Getenv(AlertSeverityEnv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: Invalid %s: %v\n", AlertSeverityEnv, err)
+	}
+	return severities
+}
+
+// ParseSeverities applies comma-separated event=severity pairs on top of DefaultSeverities.
+// Malformed pairs and unknown event types are left out of the result and reported in the
+// returned error.
+func ParseSeverities(value string) (SeverityMap, error) {
+	severities := make(SeverityMap, len(DefaultSeverities))
+	for eventType, sev := range DefaultSeverities {
+		severities[eventType] = sev
+	}
+
+	var invalid []string
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		eventType, name, ok := strings.Cut(pair, "=")
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if !ok || !validEventTypes[eventType] || eventType == EventTypeWorking {
+			invalid = append(invalid, pair)
+			continue
+		}
+		sev, err := ParseSeverity(name)
+		if err != nil {
+			invalid = append(invalid, pair)
+			continue
+		}
+		severities[eventType] = sev
+	}
+	if len(invalid) > 0 {
+		return severities, fmt.Errorf("malformed entries ignored: %s", strings.Join(invalid, ", "))
+	}
+	return severities, nil
+}
@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSeverities(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      map[string]Severity
+		errSubstr string
+	}{
+		{
+			name:  "empty keeps defaults",
+			value: "",
+			want: map[string]Severity{
+				EventTypePermission: SeverityHigh,
+				EventTypeStop:       SeverityNormal,
+				EventTypeIdle:       SeverityLow,
+			},
+		},
+		{
+			name:  "overrides merge over defaults",
+			value: " idle = High, stop=low ",
+			want: map[string]Severity{
+				EventTypePermission: SeverityHigh,
+				EventTypeStop:       SeverityLow,
+				EventTypeIdle:       SeverityHigh,
+			},
+		},
+		{
+			name:      "malformed entries are skipped",
+			value:     "idle=urgent,bogus=high,stop,working=high,permission=low",
+			want:      map[string]Severity{EventTypePermission: SeverityLow, EventTypeIdle: SeverityLow},
+			errSubstr: "idle=urgent, bogus=high, stop, working=high",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSeverities(tt.value)
+			if tt.errSubstr == "" && err != nil {
+				t.Errorf("ParseSeverities() error = %v", err)
+			}
+			if tt.errSubstr != "" && (err == nil || !strings.Contains(err.Error(), tt.errSubstr)) {
+				t.Errorf("ParseSeverities() error = %v, want substring %q", err, tt.errSubstr)
+			}
+			for eventType, want := range tt.want {
+				if got.For(eventType) != want {
+					t.Errorf("For(%q) = %q, want %q", eventType, got.For(eventType), want)
+				}
+			}
+		})
+	}
+
+	if DefaultSeverities[EventTypeIdle] != SeverityLow {
+		t.Error("ParseSeverities() must not modify DefaultSeverities")
+	}
+}
+
+func TestSeverityMap_ForUnknown(t *testing.T) {
+	var m SeverityMap
+	if got := m.For("custom"); got != SeverityNormal {
+		t.Errorf("For() on a nil map = %q, want %q", got, SeverityNormal)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -558,7 +559,7 @@ func TestBlockedBranchPersistence_DaemonRestart(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		if fullStateMsg.BlockedBranches[tc.branch] != tc.blockedBy {
+		if !slices.Equal(fullStateMsg.BlockedBranches[tc.branch], []string{tc.blockedBy}) {
 			t.Errorf("Full state: expected %s blocked by %s, got %s",
 				tc.branch, tc.blockedBy, fullStateMsg.BlockedBranches[tc.branch])
 		}
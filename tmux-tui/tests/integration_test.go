@@ -206,7 +206,7 @@ func (m realModel) View() string {
 	m.alertsMu.RUnlock()
 
 	// No blocked branches in integration tests
-	blockedBranches := make(map[string]string)
+	blockedBranches := make(map[string][]string)
 	return m.renderer.Render(m.tree, alertsCopy, blockedBranches)
 }
 
@@ -6,11 +6,16 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/natescherer/commons.systems/wezterm-navigator/internal/model"
+	"github.com/natescherer/commons.systems/wezterm-navigator/internal/wezterm"
 )
 
 // TODO(#1980): Add integration tests for main function
 func main() {
-	m := model.NewModel()
+	selfPaneID, ok := wezterm.CurrentPaneID()
+	if !ok {
+		selfPaneID = -1
+	}
+	m := model.NewModelWithPanes(wezterm.NewClient(), selfPaneID)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -3,10 +3,13 @@ package model
 // TODO(#1977): Add unit tests for wezterm-navigator Bubbletea TUI
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/natescherer/commons.systems/wezterm-navigator/internal/wezterm"
 )
 
 // Tokyo Night color scheme styles
@@ -30,35 +33,212 @@ var (
 			Foreground(lipgloss.Color("#565f89")).
 			Italic(true).
 			MarginTop(1)
+
+	selectedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#bb9af7")).
+			Bold(true)
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#f7768e"))
 )
 
+// PaneClient lists and activates WezTerm panes; *wezterm.Client implements it
+type PaneClient interface {
+	ListPanes() ([]wezterm.Pane, error)
+	ActivatePane(paneID int) error
+}
+
+// panesLoadedMsg carries the result of listing panes
+type panesLoadedMsg struct {
+	panes []wezterm.Pane
+	err   error
+}
+
+// paneActivatedMsg carries the result of switching to a pane
+type paneActivatedMsg struct {
+	paneID int
+	err    error
+}
+
 // TODO(#1984): Model zero value is invalid - width=0, height=0 breaks rendering. Document zero-value danger or add validation.
 // TODO(#1997): Add getter methods for Model width and height fields
 // TODO(#1999): Add dimension validation and invariant enforcement (bounds checking, defensive rendering)
 type Model struct {
 	width  int
 	height int
+
+	// Filter state: filtering is true while the query is being typed (after "/");
+	// a non-empty query keeps narrowing the list after Enter
+	filtering bool
+	query     string
+
+	// Pane switcher state, only used when the model has a client. selfPaneID is the
+	// navigator's own pane, left out of the list; cursor indexes visiblePanes().
+	client     PaneClient
+	selfPaneID int
+	panes      []wezterm.Pane
+	cursor     int
+	err        error
+}
+
+// binding is one entry of the keybindings reference
+type binding struct {
+	keys string
+	desc string
+}
+
+// bindingSection groups related keybindings under a title
+type bindingSection struct {
+	title    string
+	bindings []binding
+}
+
+var keybindingSections = []bindingSection{
+	{"Window Management:", []binding{
+		{"Ctrl+Shift+T", "New tab"},
+		{"Ctrl+Shift+N", "New window"},
+		{"Ctrl+Shift+9", "Switch to navigator window"},
+		{"Ctrl+Shift+0", "Switch to main window"},
+	}},
+	{"Navigation:", []binding{
+		{"Alt+Left/Right", "Switch tabs"},
+		{"Ctrl+Shift+Arrow", "Navigate panes"},
+	}},
+	{"Splitting:", []binding{
+		{"Ctrl+Shift+%", "Horizontal split"},
+		{`Ctrl+Shift+"`, "Vertical split"},
+	}},
+	{"Closing:", []binding{
+		{"Ctrl+Shift+W", "Close tab"},
+	}},
+}
+
+// fuzzyMatch reports whether query's characters appear in label in order, ignoring
+// case. An empty query matches everything.
+func fuzzyMatch(query, label string) bool {
+	label = strings.ToLower(label)
+	for _, r := range strings.ToLower(query) {
+		i := strings.IndexRune(label, r)
+		if i < 0 {
+			return false
+		}
+		label = label[i+len(string(r)):]
+	}
+	return true
+}
+
+// matches reports whether a binding passes the current filter
+func (m Model) matches(b binding) bool {
+	return fuzzyMatch(m.query, b.keys+" "+b.desc)
+}
+
+// paneLabel is the text the filter matches a pane against
+func paneLabel(p wezterm.Pane) string {
+	return p.TabTitle + " " + p.Title + " " + p.Dir()
+}
+
+// visiblePanes returns the panes passing the current filter, without the
+// navigator's own pane
+func (m Model) visiblePanes() []wezterm.Pane {
+	var visible []wezterm.Pane
+	for _, p := range m.panes {
+		if p.PaneID == m.selfPaneID || !fuzzyMatch(m.query, paneLabel(p)) {
+			continue
+		}
+		visible = append(visible, p)
+	}
+	return visible
+}
+
+// clampCursor keeps the cursor on a visible pane after the list or filter changes
+func (m *Model) clampCursor() {
+	n := len(m.visiblePanes())
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
 }
 
 func NewModel() Model {
 	// TODO(#1987): Extract magic numbers to named constants (MinWidth, MinHeight)
 	return Model{
-		width:  40,
-		height: 24,
+		width:      40,
+		height:     24,
+		selfPaneID: -1,
 	}
 }
 
+// NewModelWithPanes creates a model that also lists the panes of every tab and
+// window and switches to the selected one. selfPaneID is the navigator's own pane,
+// or -1 if unknown.
+func NewModelWithPanes(client PaneClient, selfPaneID int) Model {
+	m := NewModel()
+	m.client = client
+	m.selfPaneID = selfPaneID
+	return m
+}
+
 func (m Model) Init() tea.Cmd {
-	return nil
+	if m.client == nil {
+		return nil
+	}
+	return m.loadPanes()
+}
+
+func (m Model) loadPanes() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		panes, err := client.ListPanes()
+		return panesLoadedMsg{panes: panes, err: err}
+	}
+}
+
+func (m Model) activatePane(paneID int) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		return paneActivatedMsg{paneID: paneID, err: client.ActivatePane(paneID)}
+	}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "/":
+			m.filtering = true
+		case "esc":
+			m.query = ""
+			m.clampCursor()
+		}
+		if m.client != nil {
+			return m.updatePanes(msg)
+		}
+	case panesLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.panes = msg.panes
+		m.clampCursor()
+	case paneActivatedMsg:
+		if errors.Is(msg.err, wezterm.ErrPaneNotFound) {
+			// The pane closed after it was listed; refresh so it drops out
+			m.err = fmt.Errorf("pane %d no longer exists", msg.paneID)
+			return m, m.loadPanes()
 		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		// Refresh so the active markers follow the switch
+		return m, m.loadPanes()
 	case tea.WindowSizeMsg:
 		// TODO(#1996): Add bounds validation on WindowSizeMsg values
 		m.width = msg.Width
@@ -67,6 +247,104 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updatePanes handles the pane switcher keys: up/down (or k/j) move the selection,
+// Enter switches to the selected pane and r reloads the list
+func (m Model) updatePanes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.visiblePanes())-1 {
+			m.cursor++
+		}
+	case "enter":
+		visible := m.visiblePanes()
+		if len(visible) == 0 {
+			return m, nil
+		}
+		m.err = nil
+		return m, m.activatePane(visible[m.cursor].PaneID)
+	case "r":
+		m.err = nil
+		return m, m.loadPanes()
+	}
+	return m, nil
+}
+
+// updateFilter handles keys while the filter query is being typed: runes extend the
+// query, Backspace shortens it, Enter keeps it and Esc clears it
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filtering = false
+		m.query = ""
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyBackspace:
+		if r := []rune(m.query); len(r) > 0 {
+			m.query = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	}
+	m.clampCursor()
+	return m, nil
+}
+
+// renderPanes lists the visible panes nested under their tab, marking the
+// selection and each tab's active pane
+func (m Model) renderPanes(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render("Panes:"))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		b.WriteString("\n\n")
+	}
+
+	visible := m.visiblePanes()
+	if len(visible) == 0 {
+		if m.query != "" {
+			b.WriteString(descStyle.Render("No matching panes"))
+		} else {
+			b.WriteString(descStyle.Render("No other panes"))
+		}
+		b.WriteString("\n\n")
+		return
+	}
+
+	for i, p := range visible {
+		if i == 0 || p.WindowID != visible[i-1].WindowID || p.TabID != visible[i-1].TabID {
+			heading := fmt.Sprintf("Window %d, Tab %d", p.WindowID, p.TabID)
+			if p.TabTitle != "" {
+				heading += ": " + p.TabTitle
+			}
+			b.WriteString(keyStyle.Render(heading))
+			b.WriteString("\n")
+		}
+
+		marker := " "
+		if p.IsActive {
+			marker = "*"
+		}
+		line := fmt.Sprintf("%s %d %s", marker, p.PaneID, p.Title)
+		if dir := p.Dir(); dir != "" {
+			line += " (" + dir + ")"
+		}
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			b.WriteString(descStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
 func (m Model) View() string {
 	var b strings.Builder
 
@@ -78,40 +356,53 @@ func (m Model) View() string {
 	b.WriteString(helpStyle.Render("Mode: Singleton Window"))
 	b.WriteString("\n\n")
 
-	b.WriteString(sectionStyle.Render("Keybindings:"))
-	b.WriteString("\n\n")
+	if m.filtering || m.query != "" {
+		b.WriteString(keyStyle.Render("Filter: /" + m.query))
+		b.WriteString("\n\n")
+	}
 
-	b.WriteString(keyStyle.Render("Window Management:"))
-	b.WriteString("\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+T"))
-	b.WriteString(" - New tab\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+N"))
-	b.WriteString(" - New window\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+9"))
-	b.WriteString(" - Switch to navigator window\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+0"))
-	b.WriteString(" - Switch to main window\n\n")
-
-	b.WriteString(keyStyle.Render("Navigation:"))
-	b.WriteString("\n")
-	b.WriteString(descStyle.Render("  Alt+Left/Right"))
-	b.WriteString(" - Switch tabs\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+Arrow"))
-	b.WriteString(" - Navigate panes\n\n")
+	if m.client != nil {
+		m.renderPanes(&b)
+	}
 
-	b.WriteString(keyStyle.Render("Splitting:"))
-	b.WriteString("\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+%"))
-	b.WriteString(" - Horizontal split\n")
-	b.WriteString(descStyle.Render(`  Ctrl+Shift+"`))
-	b.WriteString(" - Vertical split\n\n")
+	b.WriteString(sectionStyle.Render("Keybindings:"))
+	b.WriteString("\n\n")
 
-	b.WriteString(keyStyle.Render("Closing:"))
-	b.WriteString("\n")
-	b.WriteString(descStyle.Render("  Ctrl+Shift+W"))
-	b.WriteString(" - Close tab\n\n")
+	shown := 0
+	for _, section := range keybindingSections {
+		var lines strings.Builder
+		for _, kb := range section.bindings {
+			if !m.matches(kb) {
+				continue
+			}
+			lines.WriteString(descStyle.Render("  " + kb.keys))
+			lines.WriteString(" - " + kb.desc + "\n")
+			shown++
+		}
+		if lines.Len() == 0 {
+			continue
+		}
+		b.WriteString(keyStyle.Render(section.title))
+		b.WriteString("\n")
+		b.WriteString(lines.String())
+		b.WriteString("\n")
+	}
+	if shown == 0 {
+		b.WriteString(descStyle.Render("No matching keybindings"))
+		b.WriteString("\n\n")
+	}
 
-	b.WriteString(helpStyle.Render("Press Ctrl+C or q to quit"))
+	if m.filtering {
+		b.WriteString(helpStyle.Render("Type to filter, Enter to keep, Esc to clear"))
+	} else {
+		if m.client != nil {
+			b.WriteString(helpStyle.Render("Up/Down or k/j to select, Enter to switch, r to refresh"))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render("Press / to filter, Esc to clear"))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("Press Ctrl+C or q to quit"))
+	}
 
 	containerStyle := lipgloss.NewStyle().
 		Width(m.width).
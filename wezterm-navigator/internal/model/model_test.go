@@ -1,10 +1,12 @@
 package model
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/natescherer/commons.systems/wezterm-navigator/internal/wezterm"
 )
 
 func TestNewModel(t *testing.T) {
@@ -170,3 +172,206 @@ func TestModelView_ContainsSingletonMode(t *testing.T) {
 		t.Error("view should contain singleton window mode indicator")
 	}
 }
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		label string
+		want  bool
+	}{
+		{"", "Ctrl+Shift+T New tab", true},
+		{"tab", "Ctrl+Shift+T New tab", true},
+		{"NWT", "Ctrl+Shift+T New tab", true}, // In order, case-insensitive
+		{"bat", "Ctrl+Shift+T New tab", false},
+		{"split", "Ctrl+Shift+% Horizontal split", true},
+		{"xyz", "Ctrl+Shift+W Close tab", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.query, tt.label); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.query, tt.label, got, tt.want)
+		}
+	}
+}
+
+// typeKeys sends each key to the model in turn
+func typeKeys(m Model, keys ...tea.KeyMsg) Model {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModelUpdate_Filter(t *testing.T) {
+	m := typeKeys(NewModel(), runes("/"), runes("s"), runes("p"), runes("l"), runes("q"))
+	if !m.filtering || m.query != "splq" {
+		t.Fatalf("expected filtering with query splq, got filtering=%v query=%q", m.filtering, m.query)
+	}
+	if !strings.Contains(m.View(), "No matching keybindings") {
+		t.Error("view should say nothing matches")
+	}
+
+	// q is part of the query while filtering, not quit; Backspace removes it
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	view := m.View()
+	if !strings.Contains(view, "Horizontal split") || !strings.Contains(view, "Vertical split") {
+		t.Error("filtered view should contain the split bindings")
+	}
+	if strings.Contains(view, "New tab") || strings.Contains(view, "Window Management:") {
+		t.Error("filtered view should hide non-matching bindings and empty sections")
+	}
+
+	// Enter keeps the filter; q quits again
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filtering || m.query != "spl" {
+		t.Fatalf("Enter should keep the query, got filtering=%v query=%q", m.filtering, m.query)
+	}
+	if _, cmd := m.Update(runes("q")); cmd == nil {
+		t.Error("q should quit once the filter is no longer being typed")
+	}
+
+	// Esc clears the filter and restores the full list
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.query != "" {
+		t.Errorf("Esc should clear the query, got %q", m.query)
+	}
+	if !strings.Contains(m.View(), "New tab") {
+		t.Error("cleared filter should show every binding")
+	}
+}
+
+func TestModelUpdate_FilterEscWhileTyping(t *testing.T) {
+	m := typeKeys(NewModel(), runes("/"), runes("t"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filtering || m.query != "" {
+		t.Errorf("Esc while typing should clear and leave filter mode, got filtering=%v query=%q", m.filtering, m.query)
+	}
+}
+
+// fakePanes is a PaneClient over a fixed pane list
+type fakePanes struct {
+	panes     []wezterm.Pane
+	activated []int
+}
+
+func (f *fakePanes) ListPanes() ([]wezterm.Pane, error) {
+	return f.panes, nil
+}
+
+func (f *fakePanes) ActivatePane(paneID int) error {
+	for _, p := range f.panes {
+		if p.PaneID == paneID {
+			f.activated = append(f.activated, paneID)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: pane %d", wezterm.ErrPaneNotFound, paneID)
+}
+
+// run feeds a message to the model and then the messages of the commands it returns
+func run(m Model, msg tea.Msg) Model {
+	for msg != nil {
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		if cmd == nil {
+			break
+		}
+		msg = cmd()
+	}
+	return m
+}
+
+func newPaneModel(t *testing.T) (Model, *fakePanes) {
+	t.Helper()
+	client := &fakePanes{panes: []wezterm.Pane{
+		{WindowID: 0, TabID: 0, PaneID: 0, Title: "navigator"},
+		{WindowID: 1, TabID: 1, PaneID: 1, Title: "vim", TabTitle: "code", IsActive: true},
+		{WindowID: 1, TabID: 1, PaneID: 2, Title: "zsh", TabTitle: "code"},
+		{WindowID: 1, TabID: 2, PaneID: 3, Title: "htop", TabTitle: "monitor", IsActive: true},
+	}}
+	m := NewModelWithPanes(client, 0)
+	cmd := m.Init()
+	if cmd == nil {
+		t.Fatal("expected Init to load panes")
+	}
+	return run(m, cmd()), client
+}
+
+func TestModelPanes_ListedUnderTabs(t *testing.T) {
+	m, _ := newPaneModel(t)
+	view := m.View()
+
+	for _, want := range []string{"Panes:", "Tab 1: code", "Tab 2: monitor", "> * 1 vim", "2 zsh", "3 htop"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+	if strings.Contains(view, "0 navigator") {
+		t.Error("the navigator's own pane should not be listed")
+	}
+	if strings.Index(view, "2 zsh") > strings.Index(view, "Tab 2: monitor") {
+		t.Error("panes should be nested under their tab")
+	}
+}
+
+func TestModelPanes_SelectAndActivate(t *testing.T) {
+	m, client := newPaneModel(t)
+
+	m = typeKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if m.cursor != 2 {
+		t.Fatalf("cursor should stop at the last pane, got %d", m.cursor)
+	}
+	m = typeKeys(m, runes("k"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(client.activated) != 1 || client.activated[0] != 2 {
+		t.Errorf("expected pane 2 to be activated, got %v", client.activated)
+	}
+	if m.err != nil {
+		t.Errorf("unexpected error: %v", m.err)
+	}
+}
+
+func TestModelPanes_VanishedPane(t *testing.T) {
+	m, client := newPaneModel(t)
+
+	// Pane 1 closes after the list was loaded
+	client.panes = client.panes[2:]
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.err == nil || !strings.Contains(m.View(), "pane 1 no longer exists") {
+		t.Errorf("expected a non-fatal error in the view, got err=%v", m.err)
+	}
+	if strings.Contains(m.View(), "1 vim") {
+		t.Error("the vanished pane should drop out after the refresh")
+	}
+
+	// The error clears on the next successful switch
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil {
+		t.Errorf("error should clear, got %v", m.err)
+	}
+}
+
+func TestModelPanes_FilterKeepsCursorValid(t *testing.T) {
+	m, client := newPaneModel(t)
+
+	m = typeKeys(m, runes("j"), runes("j"), runes("/"), runes("z"), runes("s"), runes("h"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.cursor != 0 {
+		t.Fatalf("cursor should move onto the only match, got %d", m.cursor)
+	}
+	if visible := m.visiblePanes(); len(visible) != 1 || visible[m.cursor].PaneID != 2 {
+		t.Fatalf("expected only pane 2 to match, got %+v", visible)
+	}
+	view := m.View()
+	if !strings.Contains(view, "2 zsh") || strings.Contains(view, "htop") {
+		t.Error("filter should narrow the pane list to zsh")
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(client.activated) != 1 || client.activated[0] != 2 {
+		t.Errorf("expected pane 2 to be activated, got %v", client.activated)
+	}
+}
@@ -0,0 +1,123 @@
+// Package wezterm talks to the running WezTerm mux through the wezterm CLI.
+package wezterm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrPaneNotFound is returned by ActivatePane when the pane no longer exists, e.g.
+// because it was closed after the panes were listed
+var ErrPaneNotFound = errors.New("pane not found")
+
+// Pane is one entry of `wezterm cli list --format json`
+type Pane struct {
+	WindowID    int    `json:"window_id"`
+	TabID       int    `json:"tab_id"`
+	PaneID      int    `json:"pane_id"`
+	Workspace   string `json:"workspace"`
+	Title       string `json:"title"`
+	CWD         string `json:"cwd"`
+	IsActive    bool   `json:"is_active"`
+	TabTitle    string `json:"tab_title"`
+	WindowTitle string `json:"window_title"`
+}
+
+// Dir returns the pane's working directory as a path, with the home directory
+// shortened to ~. WezTerm reports it as a file:// URL.
+func (p Pane) Dir() string {
+	dir := p.CWD
+	if u, err := url.Parse(p.CWD); err == nil && u.Scheme == "file" {
+		dir = u.Path
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if dir == home {
+			return "~"
+		}
+		if rest, ok := strings.CutPrefix(dir, home+"/"); ok {
+			return "~/" + rest
+		}
+	}
+	return dir
+}
+
+// Runner runs the wezterm CLI with args and returns its stdout
+type Runner func(args ...string) ([]byte, error)
+
+// Client lists and activates panes
+type Client struct {
+	run Runner
+}
+
+// NewClient creates a client that runs the wezterm binary on PATH
+func NewClient() *Client {
+	return NewClientWithRunner(execRunner)
+}
+
+// NewClientWithRunner creates a client that runs the CLI through run, for tests
+func NewClientWithRunner(run Runner) *Client {
+	return &Client{run: run}
+}
+
+func execRunner(args ...string) ([]byte, error) {
+	out, err := exec.Command("wezterm", append([]string{"cli"}, args...)...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+// ListPanes returns every pane in the mux, in the CLI's window/tab order
+func (c *Client) ListPanes() ([]Pane, error) {
+	out, err := c.run("list", "--format", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list panes: %w", err)
+	}
+	var panes []Pane
+	if err := json.Unmarshal(out, &panes); err != nil {
+		return nil, fmt.Errorf("failed to parse pane list: %w", err)
+	}
+	return panes, nil
+}
+
+// ActivatePane focuses the pane, along with its tab and window. The CLI's error
+// doesn't say why activation failed, so on failure the panes are listed again and
+// ErrPaneNotFound is returned if the pane is gone.
+func (c *Client) ActivatePane(paneID int) error {
+	_, err := c.run("activate-pane", "--pane-id", strconv.Itoa(paneID))
+	if err == nil {
+		return nil
+	}
+	if panes, listErr := c.ListPanes(); listErr == nil && !containsPane(panes, paneID) {
+		return fmt.Errorf("%w: pane %d", ErrPaneNotFound, paneID)
+	}
+	return fmt.Errorf("failed to activate pane %d: %w", paneID, err)
+}
+
+func containsPane(panes []Pane, paneID int) bool {
+	for _, p := range panes {
+		if p.PaneID == paneID {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentPaneID returns the pane this process runs in, from WEZTERM_PANE
+func CurrentPaneID() (int, bool) {
+	id, err := strconv.Atoi(os.Getenv("WEZTERM_PANE"))
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
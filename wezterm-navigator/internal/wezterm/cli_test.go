@@ -0,0 +1,86 @@
+package wezterm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const listJSON = `[
+  {"window_id": 0, "tab_id": 1, "pane_id": 2, "workspace": "default", "title": "vim", "cwd": "file://host/tmp/src", "is_active": true, "tab_title": "code", "window_title": "vim"},
+  {"window_id": 0, "tab_id": 1, "pane_id": 3, "workspace": "default", "title": "zsh", "cwd": "file://host/tmp", "is_active": false, "tab_title": "code", "window_title": "vim"}
+]`
+
+// fakeCLI answers list with the given JSON and fails activate-pane for missing panes
+func fakeCLI(list string, calls *[]string) Runner {
+	return func(args ...string) ([]byte, error) {
+		*calls = append(*calls, strings.Join(args, " "))
+		switch args[0] {
+		case "list":
+			return []byte(list), nil
+		case "activate-pane":
+			if strings.Contains(list, `"pane_id": `+args[2]+",") {
+				return nil, nil
+			}
+			return nil, errors.New("exit status 1")
+		}
+		return nil, errors.New("unexpected command")
+	}
+}
+
+func TestListPanes(t *testing.T) {
+	var calls []string
+	panes, err := NewClientWithRunner(fakeCLI(listJSON, &calls)).ListPanes()
+	if err != nil {
+		t.Fatalf("ListPanes failed: %v", err)
+	}
+	if len(panes) != 2 {
+		t.Fatalf("expected 2 panes, got %d", len(panes))
+	}
+	p := panes[0]
+	if p.PaneID != 2 || p.TabID != 1 || p.Title != "vim" || p.TabTitle != "code" || !p.IsActive {
+		t.Errorf("unexpected pane: %+v", p)
+	}
+	if p.Dir() != "/tmp/src" {
+		t.Errorf("expected dir /tmp/src, got %q", p.Dir())
+	}
+	if calls[0] != "list --format json" {
+		t.Errorf("unexpected command %q", calls[0])
+	}
+}
+
+func TestListPanes_InvalidJSON(t *testing.T) {
+	var calls []string
+	if _, err := NewClientWithRunner(fakeCLI("not json", &calls)).ListPanes(); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestActivatePane(t *testing.T) {
+	var calls []string
+	client := NewClientWithRunner(fakeCLI(listJSON, &calls))
+
+	if err := client.ActivatePane(3); err != nil {
+		t.Fatalf("ActivatePane failed: %v", err)
+	}
+	if calls[0] != "activate-pane --pane-id 3" {
+		t.Errorf("unexpected command %q", calls[0])
+	}
+
+	if err := client.ActivatePane(9); !errors.Is(err, ErrPaneNotFound) {
+		t.Errorf("expected ErrPaneNotFound for a vanished pane, got %v", err)
+	}
+}
+
+func TestActivatePane_OtherFailure(t *testing.T) {
+	client := NewClientWithRunner(func(args ...string) ([]byte, error) {
+		if args[0] == "list" {
+			return []byte(listJSON), nil
+		}
+		return nil, errors.New("mux unavailable")
+	})
+	err := client.ActivatePane(2)
+	if err == nil || errors.Is(err, ErrPaneNotFound) {
+		t.Errorf("expected a plain activation error for an existing pane, got %v", err)
+	}
+}